@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"product-api/internal/config"
+	"strconv"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd socket activation.
+const listenFDsStart = 3
+
+// listen returns the listener of the HTTP server: the socket inherited through systemd socket activation
+// if the process was started with one, so connections queue in the kernel across restarts,
+// otherwise a new socket bound to the configured address.
+func listen(cfg config.HTTPServer) (net.Listener, error) {
+	if l, err := inheritedListener(); l != nil || err != nil {
+		return l, err
+	}
+	lc := net.ListenConfig{}
+	if cfg.ReusePort {
+		lc.Control = reusePortControl
+	}
+	return lc.Listen(context.Background(), "tcp", cfg.Address)
+}
+
+// inheritedListener returns the first socket passed to the process with LISTEN_FDS and LISTEN_PID,
+// or nil if no socket was passed.
+func inheritedListener() (net.Listener, error) {
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid LISTEN_FDS %q", os.Getenv("LISTEN_FDS"))
+	}
+	// Children must not inherit the socket again
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	f := os.NewFile(listenFDsStart, "inherited listener")
+	defer f.Close() // FileListener duplicates the descriptor
+	l, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("inherited listener: %w", err)
+	}
+	return l, nil
+}
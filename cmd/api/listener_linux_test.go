@@ -0,0 +1,22 @@
+package main
+
+import (
+	"product-api/internal/config"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListen_ReusePort(t *testing.T) {
+	first, err := listen(config.HTTPServer{Address: "127.0.0.1:0", ReusePort: true})
+	require.NoError(t, err)
+	defer first.Close()
+
+	// The next process binds the address while the first one drains
+	second, err := listen(config.HTTPServer{Address: first.Addr().String(), ReusePort: true})
+	require.NoError(t, err)
+	second.Close()
+
+	_, err = listen(config.HTTPServer{Address: first.Addr().String()})
+	require.Error(t, err, "without SO_REUSEPORT the address is in use")
+}
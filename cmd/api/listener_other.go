@@ -0,0 +1,13 @@
+//go:build !linux
+
+package main
+
+import (
+	"errors"
+	"syscall"
+)
+
+// reusePortControl fails, SO_REUSEPORT is only supported on Linux.
+func reusePortControl(_, _ string, _ syscall.RawConn) error {
+	return errors.New("HTTP_SERVER_REUSE_PORT is only supported on Linux")
+}
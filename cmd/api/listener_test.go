@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"product-api/internal/config"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListen_IgnoresSocketsOfOtherProcesses(t *testing.T) {
+	// Variables set for the parent, e.g. by a shell started with socket activation
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getppid()))
+	t.Setenv("LISTEN_FDS", "1")
+
+	l, err := listen(config.HTTPServer{Address: "127.0.0.1:0"})
+	require.NoError(t, err)
+	defer l.Close()
+	assert.NotEqual(t, "127.0.0.1:0", l.Addr().String(), "a new socket is bound")
+}
+
+func TestListen_InvalidListenFDs(t *testing.T) {
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	t.Setenv("LISTEN_FDS", "0")
+
+	_, err := listen(config.HTTPServer{Address: "127.0.0.1:0"})
+	assert.ErrorContains(t, err, "invalid LISTEN_FDS")
+}
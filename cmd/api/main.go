@@ -2,16 +2,36 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"product-api/docs"
 	"product-api/internal/config"
+	"product-api/internal/domain"
+	"product-api/internal/event"
+	"product-api/internal/grpcapi"
 	"product-api/internal/handler"
 	"product-api/internal/logger"
+	"product-api/internal/money"
+	"product-api/internal/notification"
+	"product-api/internal/oidc"
+	"product-api/internal/payment"
+	"product-api/internal/ratelimit"
+	"product-api/internal/repository"
 	postgresrepo "product-api/internal/repository/postgres"
+	"product-api/internal/repository/replica"
+	"product-api/internal/repository/shadow"
 	"product-api/internal/service"
+	"product-api/internal/storage"
+	"product-api/internal/telemetry"
+	"product-api/migrations"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -19,27 +39,49 @@ import (
 	sentryhttp "github.com/getsentry/sentry-go/http"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
-
-	_ "product-api/docs"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
 )
 
 // @title Product API
 // @version 1.0
 // @host localhost:8080
+// The served host, base path and scheme are overridden from PUBLIC_URL at startup.
 // @BasePath /
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name Authorization
 
 // main is the entry point of the application.
-// Initializes all service components and starts the HTTP server.
+// Initializes all service components and starts the HTTP server, or runs a subcommand, e.g. "schema check".
 func main() {
+	if len(os.Args) > 1 {
+		if os.Args[1] != "schema" {
+			log.Fatalf("unknown command %q, the only command is schema", os.Args[1])
+		}
+		if err := runSchemaCommand(os.Args[2:]); err != nil {
+			log.Fatalf("schema: %v", err)
+		}
+		return
+	}
 	if err := run(); err != nil {
 		log.Fatalf("server returned an error: %v", err)
 	}
@@ -55,15 +97,38 @@ func run() error {
 	if err := sentry.Init(sentry.ClientOptions{
 		Dsn:              cfg.SentryDSN,
 		EnableTracing:    true,
-		TracesSampleRate: 1.0,
+		TracesSampleRate: cfg.SentryTracesSampleRatio(),
 		Environment:      cfg.Env,
+		Tags:             regionTags(cfg.Region.Name),
 	}); err != nil {
 		return fmt.Errorf("sentry initialization failed: %w", err)
 	}
 	defer sentry.Flush(2 * time.Second)
 
+	// Initialize logger
+	var logAttrs []any
+	if cfg.Region.Name != "" {
+		logAttrs = append(logAttrs, "region", cfg.Region.Name)
+	}
+	logger := logger.NewSlogAdapter(cfg.Env, logAttrs...)
+	logger.Info("logger initialized", "environment", cfg.Env)
+
 	// Create database connection pool
-	dbpool, err := pgxpool.New(context.Background(), cfg.DatabaseURL)
+	poolConfig, err := pgxpool.ParseConfig(cfg.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid database url: %w", err)
+	}
+	var tracers []pgx.QueryTracer
+	if cfg.SlowQueryThreshold > 0 {
+		tracers = append(tracers, postgresrepo.NewSlowQueryTracer(cfg.SlowQueryThreshold, logger))
+	}
+	if cfg.DBAcquireTimeout > 0 {
+		tracers = append(tracers, postgresrepo.NewAcquireTimeoutTracer("primary", cfg.DBAcquireTimeout, logger))
+	}
+	if len(tracers) > 0 {
+		poolConfig.ConnConfig.Tracer = multitracer.New(tracers...)
+	}
+	dbpool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return fmt.Errorf("unable to create connection pool: %w", err)
 	}
@@ -74,12 +139,24 @@ func run() error {
 		return fmt.Errorf("unable to connect to database: %w", err)
 	}
 
-	// Initialize logger
-	logger := logger.NewSlogAdapter(cfg.Env)
-	logger.Info("logger initialized", "environment", cfg.Env)
+	// Verify the schema matches the migrations the binary was built with
+	if err := checkSchemaVersion(context.Background(), dbpool, cfg.SchemaCheckMode(), logger); err != nil {
+		return err
+	}
+
+	// Create the reporting pool, isolating analytical queries from transactional traffic
+	reportingPool, err := newReportingPool(cfg)
+	if err != nil {
+		return err
+	}
+	defer reportingPool.Close()
 
 	// Initialize OpenTelemetry tracer
-	tp, err := initTracer()
+	tp, err := initTracer(cfg.OTLPEndpoint, cfg.Region.Name, telemetry.Sampling{
+		Ratio:       cfg.TraceSampleRatio(),
+		ParentBased: cfg.Tracing.ParentBased,
+		Errors:      cfg.Tracing.SampleErrors,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to initialize tracer: %w", err)
 	}
@@ -90,44 +167,361 @@ func run() error {
 	}()
 	otel.SetTracerProvider(tp)
 
+	// Initialize OpenTelemetry meter for business metrics
+	mp, metricsHandler, err := initMeter(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize meter: %w", err)
+	}
+	defer func() {
+		if err := mp.Shutdown(context.Background()); err != nil {
+			log.Printf("Error shutting down meter provider: %v", err)
+		}
+	}()
+	otel.SetMeterProvider(mp)
+	for name, pool := range map[string]*pgxpool.Pool{"primary": dbpool, "reporting": reportingPool} {
+		if err := observePool(name, pool); err != nil {
+			return fmt.Errorf("failed to observe %s database pool: %w", name, err)
+		}
+	}
+
 	// Initialize repositories
 	userRepo := postgresrepo.NewUserRepository(dbpool)
-	productRepo := postgresrepo.NewProductRepository(dbpool)
+	var productRepo repository.ProductRepository = postgresrepo.NewProductRepository(dbpool)
+	if len(cfg.Replicas.DatabaseURLs) > 0 {
+		replicaRepos := make([]repository.ProductRepository, len(cfg.Replicas.DatabaseURLs))
+		for i, databaseURL := range cfg.Replicas.DatabaseURLs {
+			replicaPool, err := newReplicaPool(cfg, databaseURL)
+			if err != nil {
+				return err
+			}
+			defer replicaPool.Close()
+			replicaRepos[i] = postgresrepo.NewProductRepository(replicaPool)
+		}
+		productRepo = replica.NewProductRepository(productRepo, replicaRepos, replica.Options{
+			Hedge:      cfg.Replicas.Hedge,
+			HedgeDelay: cfg.Replicas.HedgeDelay,
+		}, logger)
+		logger.Info("product lookups are read from replicas", "replicas", len(replicaRepos), "hedge", cfg.Replicas.Hedge)
+	}
+	if cfg.Shadow.DatabaseURL != "" {
+		shadowPool, err := newShadowPool(cfg)
+		if err != nil {
+			return err
+		}
+		defer shadowPool.Close()
+		shadowProductRepo := shadow.NewProductRepository(productRepo, postgresrepo.NewProductRepository(shadowPool), shadow.Options{
+			SampleRate:  cfg.Shadow.SampleRate,
+			Writes:      cfg.Shadow.Writes,
+			Timeout:     cfg.Shadow.Timeout,
+			MaxInFlight: cfg.Shadow.MaxInFlight,
+		}, logger)
+		defer shadowProductRepo.Wait() // Runs before the pool closes
+		productRepo = shadowProductRepo
+		logger.Info("product reads are mirrored to the shadow database", "sample_rate", cfg.Shadow.SampleRate, "writes", cfg.Shadow.Writes)
+	}
 	orderRepo := postgresrepo.NewOrderRepository(dbpool)
+	orderEventRepo := postgresrepo.NewOrderEventRepository(dbpool)
+	orderNumberRepo := postgresrepo.NewOrderNumberRepository(dbpool)
+	paymentRepo := postgresrepo.NewPaymentRepository(dbpool)
+	refundRequestRepo := postgresrepo.NewRefundRequestRepository(dbpool)
+	disputeRepo := postgresrepo.NewDisputeRepository(dbpool)
+	invoiceRepo := postgresrepo.NewInvoiceRepository(dbpool)
+	stockRepo := postgresrepo.NewStockRepository(dbpool)
+	deliverySlotRepo := postgresrepo.NewDeliverySlotRepository(dbpool)
+	consentRepo := postgresrepo.NewConsentRepository(dbpool)
+	preferenceRepo := postgresrepo.NewPreferenceRepository(dbpool)
+	phoneVerificationRepo := postgresrepo.NewPhoneVerificationRepository(dbpool)
+	emailVerificationRepo := postgresrepo.NewEmailVerificationRepository(dbpool)
+	inboxRepo := postgresrepo.NewInboxRepository(dbpool)
+	loginAttemptRepo := postgresrepo.NewLoginAttemptRepository(dbpool)
+	identityRepo := postgresrepo.NewIdentityRepository(dbpool)
+	tokenRepo := postgresrepo.NewTokenRepository(dbpool)
+	quotaRepo := postgresrepo.NewQuotaRepository(dbpool)
+	idempotencyRepo := postgresrepo.NewIdempotencyRepository(dbpool)
+	tagRepo := postgresrepo.NewTagRepository(dbpool)
+	attributeRepo := postgresrepo.NewAttributeRepository(dbpool)
+	productRevisionRepo := postgresrepo.NewProductRevisionRepository(dbpool)
+	notificationTemplateRepo := postgresrepo.NewNotificationTemplateRepository(dbpool)
+
+	// Initialize notification dispatcher (channels without a configured delivery provider log messages)
+	templates := notification.NewTemplates(notificationTemplateRepo, cfg.TemplateReload, logger)
+	smsSender := newSMSSender(cfg, logger)
+	emailSender := notification.NewLogSender(domain.NotificationChannelEmail, logger)
+	notifier := notification.NewDispatcher(userRepo, preferenceRepo, templates, logger,
+		emailSender,
+		smsSender,
+		notification.NewInboxSender(inboxRepo),
+	)
+	adminNotifier := notification.NewAdminMailer(emailSender, cfg.AdminEmails...)
+
+	// Initialize payment providers
+	payments := newPaymentRegistry(cfg, logger)
+
+	// Initialize object storage of product images, avatars, invoices and exports
+	objects, err := newObjectStorage(cfg)
+	if err != nil {
+		return fmt.Errorf("invalid object storage config: %w", err)
+	}
+	logger.Info("object storage initialized", "backend", objects.Name())
+
+	// Initialize the backend of request rate limits
+	limiter, closeLimiter, err := newRateLimiter(cfg)
+	if err != nil {
+		return fmt.Errorf("invalid rate limit config: %w", err)
+	}
+	defer closeLimiter()
+	logger.Info("rate limiter initialized", "backend", limiter.Name())
+
+	// Initialize money formatting for responses and documents
+	moneyFormatter, err := money.NewFormatter(cfg.Currency, cfg.Locale)
+	if err != nil {
+		return fmt.Errorf("invalid money formatting config: %w", err)
+	}
+
+	// Initialize the validators of checkouts
+	checkoutChecks, err := service.NewCheckoutPipelineOf(cfg.Checkout.Validators, service.QuantityLimits{
+		Min: cfg.Checkout.MinLineQuantity,
+		Max: cfg.Checkout.MaxLineQuantity,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid checkout config: %w", err)
+	}
+	logger.Info("checkout validators initialized", "validators", checkoutChecks.Names())
+	orderOptionFees, err := service.NewOrderOptionFees(cfg.OrderOptions.Fees)
+	if err != nil {
+		return fmt.Errorf("invalid order options config: %w", err)
+	}
+
+	// Initialize the rates and accounts payments are journaled with
+	accountingPolicy := newAccountingPolicy(cfg)
+
+	// Initialize the keys access tokens are signed with
+	tokenKeys, err := newTokenKeys(cfg)
+	if err != nil {
+		return fmt.Errorf("invalid token signing config: %w", err)
+	}
+	logger.Info("token signing initialized", "algorithm", tokenKeys.Algorithm())
+
+	// Initialize the bus services publish domain events to and subscribe the listeners
+	events := event.NewBus(logger)
+	event.SubscribeMetrics(events)
 
 	// Initialize services
-	productService := service.NewProductService(productRepo)
-	orderService := service.NewOrderService(dbpool, orderRepo, productRepo, logger)
-	usersService := service.NewUsersService(userRepo, []byte(cfg.JWTSecret), cfg.JWTTTL)
+	productService := service.NewProductService(dbpool, productRepo, productRevisionRepo, attributeRepo)
+	orderService := service.NewOrderService(service.OrderServiceDeps{
+		DB:              dbpool,
+		OrderRepo:       orderRepo,
+		EventRepo:       orderEventRepo,
+		Numbers:         orderNumberRepo,
+		PaymentRepo:     paymentRepo,
+		RefundRepo:      refundRequestRepo,
+		DisputeRepo:     disputeRepo,
+		ProductRepo:     productRepo,
+		StockRepo:       stockRepo,
+		SlotRepo:        deliverySlotRepo,
+		UserRepo:        userRepo,
+		Notifier:        notifier,
+		Providers:       payments,
+		Formatter:       moneyFormatter,
+		TaxRegion:       cfg.TaxRegion,
+		Region:          cfg.Region.Name,
+		Checks:          checkoutChecks,
+		OptionFees:      orderOptionFees,
+		DuplicateWindow: cfg.Checkout.DuplicateWindow,
+		Events:          events,
+		Logger:          logger,
+	})
+	usersService := service.NewUsersService(userRepo, loginAttemptRepo, identityRepo, tokenKeys, cfg.JWTTTL,
+		service.LoginLockout{MaxFailures: cfg.LoginLockout.MaxFailures, Duration: cfg.LoginLockout.Duration}, events, logger)
+	consentService := service.NewConsentService(consentRepo)
+	preferenceService := service.NewPreferenceService(preferenceRepo)
+	phoneService := service.NewPhoneService(userRepo, phoneVerificationRepo, templates, smsSender)
+	inboxService := service.NewInboxService(inboxRepo)
+	stockService := service.NewStockService(stockRepo, events, logger)
+	tokenService := service.NewTokenService(tokenRepo, userRepo, tokenKeys)
+	quotaService := service.NewQuotaService(quotaRepo, cfg.Quotas.Daily, cfg.Quotas.Monthly, cfg.Quotas.SoftLimit)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, cfg.IdempotencyTTL, logger)
+	tagService := service.NewTagService(dbpool, tagRepo, productRevisionRepo, logger)
+	attributeService := service.NewAttributeService(attributeRepo)
+	deliverySlotService := service.NewDeliverySlotService(deliverySlotRepo)
+	disputeService := service.NewDisputeService(dbpool, paymentRepo, disputeRepo, orderService, payments, adminNotifier, logger)
+	templateService := service.NewNotificationTemplateService(notificationTemplateRepo, templates)
+	workers := service.NewWorkers() // Heartbeats of the background runners started below, reported in the system status
+	systemService := service.NewSystemService(map[string]service.DatabasePool{"primary": dbpool, "reporting": reportingPool}, refundRequestRepo, objects, templates, workers)
+	announcementService := service.NewAnnouncementService(postgresrepo.NewAnnouncementRepository(dbpool), userRepo, preferenceRepo, notifier, cfg.Announcements.BatchSize, cfg.Announcements.Lease, logger)
+	// Order exports read the whole order history through the reporting pool
+	orderExportService := service.NewOrderExportService(postgresrepo.NewOrderRepository(reportingPool), objects, cfg.OrderExports.SyncLimit, cfg.OrderExports.Interval, cfg.Storage.PresignTTL, logger)
+	invoiceService := service.NewInvoiceService(dbpool, invoiceRepo, orderRepo, objects, notifier, moneyFormatter, cfg.PublicURL+"/orders", time.Month(cfg.FiscalYearStart), cfg.Storage.PresignTTL)
+	accountingService := service.NewAccountingService(postgresrepo.NewAccountingRepository(dbpool), orderRepo, accountingPolicy, cfg.Accounting.BatchSize, logger)
+	stockSnapshotService := service.NewStockSnapshotService(postgresrepo.NewStockSnapshotRepository(dbpool), logger)
+	bulkOperationService := service.NewBulkOperationService(dbpool, postgresrepo.NewBulkOperationRepository(dbpool), productService, stockRepo, events, cfg.BulkOperations.BatchSize, logger)
+	emailVerificationService := service.NewEmailVerificationService(userRepo, emailVerificationRepo, templates, emailSender, cfg.PublicURL+"/users/email/verify")
+
+	// Services of reporting and export endpoints read through the reporting pool
+	reportingProductRepo := postgresrepo.NewProductRepository(reportingPool)
+	reportingProductService := service.NewProductService(reportingPool, reportingProductRepo, productRevisionRepo, attributeRepo)
+	reportingOrderService := service.NewOrderService(service.OrderServiceDeps{
+		DB:              reportingPool,
+		OrderRepo:       postgresrepo.NewOrderRepository(reportingPool),
+		EventRepo:       orderEventRepo,
+		Numbers:         orderNumberRepo,
+		PaymentRepo:     paymentRepo,
+		RefundRepo:      refundRequestRepo,
+		DisputeRepo:     disputeRepo,
+		ProductRepo:     reportingProductRepo,
+		StockRepo:       stockRepo,
+		SlotRepo:        deliverySlotRepo,
+		UserRepo:        userRepo,
+		Notifier:        notifier,
+		Providers:       payments,
+		Formatter:       moneyFormatter,
+		TaxRegion:       cfg.TaxRegion,
+		Region:          cfg.Region.Name,
+		Checks:          checkoutChecks,
+		OptionFees:      orderOptionFees,
+		DuplicateWindow: cfg.Checkout.DuplicateWindow,
+		Events:          events,
+		Logger:          logger,
+	})
+	reportingStockService := service.NewStockService(postgresrepo.NewStockRepository(reportingPool), events, logger)
+	changeService := service.NewChangeService(postgresrepo.NewChangeRepository(reportingPool), cfg.ChangeLog.Retention, logger)
+	// Old changes are deleted through the primary, the feed is read from the reporting replica
+	changeCleanupService := service.NewChangeService(postgresrepo.NewChangeRepository(dbpool), cfg.ChangeLog.Retention, logger)
+	reportingAccountingService := service.NewAccountingService(postgresrepo.NewAccountingRepository(reportingPool), orderRepo, accountingPolicy, cfg.Accounting.BatchSize, logger)
+	reportingStockSnapshotService := service.NewStockSnapshotService(postgresrepo.NewStockSnapshotRepository(reportingPool), logger)
 
 	// Initialize HTTP handlers
-	userHandler := handler.NewUserHandler(usersService, logger)
-	productHandler := handler.NewProductHandler(productService, logger)
-	orderHandler := handler.NewOrderHandler(orderService, logger)
+	handlers := &handlers{
+		user:       handler.NewUserHandler(usersService, consentService, logger),
+		product:    handler.NewProductHandler(productService, logger),
+		catalog:    handler.NewPublicCatalogHandler(productService, cfg.PublicCatalog.MaxAge, logger),
+		order:      handler.NewOrderHandler(orderService, moneyFormatter, logger),
+		export:     handler.NewOrderExportHandler(orderExportService, logger),
+		announce:   handler.NewAnnouncementHandler(announcementService, logger),
+		bulk:       handler.NewBulkOperationHandler(bulkOperationService, logger),
+		stock:      handler.NewStockHandler(stockService, logger),
+		consent:    handler.NewConsentHandler(consentService, logger),
+		preference: handler.NewPreferenceHandler(preferenceService, logger),
+		phone:      handler.NewPhoneHandler(phoneService, logger),
+		email:      handler.NewEmailVerificationHandler(emailVerificationService, logger),
+		inbox:      handler.NewInboxHandler(inboxService, logger),
+		scim:       handler.NewSCIMHandler(usersService, cfg.PublicURL+"/scim/v2", logger),
+		oidc:       handler.NewOIDCHandler(newOIDCRegistry(cfg), usersService, logger),
+		token:      handler.NewTokenHandler(tokenService, logger),
+		quota:      handler.NewQuotaHandler(quotaService, logger),
+		tag:        handler.NewTagHandler(tagService, logger),
+		attribute:  handler.NewAttributeHandler(attributeService, logger),
+		slot:       handler.NewDeliverySlotHandler(deliverySlotService, logger),
+		invoice:    handler.NewInvoiceHandler(invoiceService, moneyFormatter, logger),
+		webhook:    handler.NewPaymentWebhookHandler(disputeService, logger),
+		template:   handler.NewNotificationTemplateHandler(templateService, logger),
+		system:     handler.NewSystemHandler(systemService, cfg.Redacted(), logger),
+		health:     handler.NewHealthHandler(),
+		metrics:    metricsHandler,
+		reporting: reportingHandlers{
+			product:  handler.NewProductHandler(reportingProductService, logger),
+			order:    handler.NewOrderHandler(reportingOrderService, moneyFormatter, logger),
+			stock:    handler.NewStockHandler(reportingStockService, logger),
+			snapshot: handler.NewStockSnapshotHandler(reportingStockSnapshotService, logger),
+			change:   handler.NewChangeHandler(changeService, logger),
+			journal:  handler.NewAccountingHandler(reportingAccountingService, logger),
+		},
+	}
 	sentryHandler := sentryhttp.New(sentryhttp.Options{})
 
+	// Serve API documentation for the configured public URL
+	if err := configureSwaggerInfo(cfg.PublicURL); err != nil {
+		return err
+	}
+
+	// Outside the primary region writes and order requests are forwarded to it
+	var primaryURL *url.URL
+	if !cfg.Region.IsPrimary() {
+		if primaryURL, err = url.Parse(cfg.Region.PrimaryURL); err != nil {
+			return fmt.Errorf("invalid primary region url: %w", err)
+		}
+		logger.Info("writes are forwarded to the primary region", "primary_region", cfg.Region.Primary, "primary_url", primaryURL.Host)
+	}
+
+	// Initialize router middlewares
+	routerMiddlewares := &middlewares{
+		region:      handler.RegionMiddleware(cfg.Region.Name, primaryURL, []byte(cfg.Region.ForwardSecret), logger),
+		recoverer:   handler.RecovererMiddleware(logger, cfg.PanicCaptureBody),
+		realIP:      handler.RealIPMiddleware(cfg.HTTPServer.TrustedProxyPrefixes()),
+		requestLog:  requestLog(cfg.RequestLog, logger),
+		debug:       handler.DebugCaptureMiddleware(logger, cfg.DebugCapture, cfg.APIKeys["admin"]),
+		jwt:         handler.JWTMiddleware(tokenService),
+		user:        handler.UserMiddleware(usersService, logger),
+		idempotency: handler.IdempotencyMiddleware(idempotencyService, logger),
+		catalog:     catalogBulkhead(cfg.CatalogLimits, dbpool),
+		rateLimit:   rateLimit(limiter, "api", cfg.RateLimits.Requests, cfg.RateLimits.Period, logger),
+		authLimit:   rateLimit(limiter, "auth", cfg.RateLimits.AuthRequests, cfg.RateLimits.AuthPeriod, logger),
+	}
+
 	// Setup router
-	router := setupRouter(sentryHandler, userHandler, productHandler, orderHandler, cfg)
+	router := setupRouter(sentryHandler, handlers, routerMiddlewares, cfg)
 
 	// Create HTTP server with timeout settings
 	server := &http.Server{
-		Addr:         cfg.HTTPServer.Address,
 		Handler:      router,
 		ReadTimeout:  cfg.HTTPServer.Timeout,
 		WriteTimeout: cfg.HTTPServer.Timeout,
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
 	}
+	listener, err := listen(cfg.HTTPServer)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	// Create gRPC server for internal callers, sharing the services of the HTTP API
+	var grpcServer *grpcapi.Server
+	var grpcListener net.Listener
+	if cfg.GRPCServer.Address != "" {
+		grpcServer = grpcapi.NewServer(grpcapi.Services{
+			Products: productService,
+			Orders:   orderService,
+			Tokens:   tokenService,
+			Users:    usersService,
+			Consents: consentService,
+			Money:    moneyFormatter,
+		}, logger)
+		if grpcListener, err = net.Listen("tcp", cfg.GRPCServer.Address); err != nil {
+			return fmt.Errorf("failed to listen for gRPC: %w", err)
+		}
+	}
 
 	// Setup graceful shutdown
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
 
-	// Start server in a separate goroutine
-	serverErrors := make(chan error, 1)
+	// Start servers in separate goroutines
+	serverErrors := make(chan error, 2)
 	go func() {
-		logger.Info("starting server", "address", cfg.HTTPServer.Address)
-		serverErrors <- server.ListenAndServe()
+		logger.Info("starting server", "address", listener.Addr().String())
+		serverErrors <- server.Serve(listener)
 	}()
+	if grpcServer != nil {
+		go func() {
+			logger.Info("starting gRPC server", "address", grpcListener.Addr().String())
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				serverErrors <- fmt.Errorf("gRPC: %w", err)
+			}
+		}()
+	}
+
+	// Send queued announcements, journal payments, snapshot stock, apply bulk operations, expire unpaid orders, delete expired idempotency keys and old changes in the background, only the primary region writes to the database
+	runnerCtx, stopRunners := context.WithCancel(context.Background())
+	defer stopRunners()
+	if cfg.Region.IsPrimary() {
+		go announcementService.Run(runnerCtx, cfg.Announcements.PollInterval, workers.Register("announcements", cfg.Announcements.PollInterval))
+		go accountingService.Run(runnerCtx, cfg.Accounting.PollInterval, workers.Register("accounting", cfg.Accounting.PollInterval))
+		go stockSnapshotService.Run(runnerCtx, cfg.StockSnapshots.PollInterval, workers.Register("stock_snapshots", cfg.StockSnapshots.PollInterval))
+		go bulkOperationService.Run(runnerCtx, cfg.BulkOperations.PollInterval, workers.Register("bulk_operations", cfg.BulkOperations.PollInterval))
+		go orderService.RunPaymentExpiry(runnerCtx, cfg.Payments.PendingTimeout, cfg.Payments.ExpiryPollInterval,
+			workers.Register("payment_expiry", cfg.Payments.ExpiryPollInterval))
+		go idempotencyService.Run(runnerCtx, cfg.IdempotencyCleanup, workers.Register("idempotency_cleanup", cfg.IdempotencyCleanup))
+		go changeCleanupService.Run(runnerCtx, cfg.ChangeLog.CleanupInterval, workers.Register("change_log_cleanup", cfg.ChangeLog.CleanupInterval))
+	}
 
 	// Wait for either server error or shutdown signal
 	select {
@@ -135,63 +529,730 @@ func run() error {
 		return fmt.Errorf("server error: %w", err)
 	case sig := <-shutdown:
 		logger.Info("shutdown signal received", "signal", sig)
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
+		return drain(server, grpcServer, handlers.health, cfg.HTTPServer, shutdown, logger)
+	}
+}
+
+// drain shuts the servers down without dropping requests: readiness probes fail and keep-alive connections
+// are closed after their next response during the pre-stop delay, while load balancers still route requests here.
+// Then the listeners are closed and in-flight requests complete within the shutdown timeout.
+// A second signal skips the delay, or closes remaining connections while draining.
+// The gRPC server is nil when it is disabled.
+func drain(server *http.Server, grpcServer *grpcapi.Server, health *handler.HealthHandler, cfg config.HTTPServer, signals <-chan os.Signal, logger logger.Logger) error {
+	health.Drain()
+	server.SetKeepAlivesEnabled(false)
+	if grpcServer != nil {
+		grpcServer.Drain()
+	}
 
-		if err := server.Shutdown(ctx); err != nil {
-			return fmt.Errorf("graceful shutdown failed: %w", err)
+	if cfg.PreStopDelay > 0 {
+		logger.Info("waiting before closing listener", "delay", cfg.PreStopDelay)
+		select {
+		case <-time.After(cfg.PreStopDelay):
+		case sig := <-signals:
+			logger.Warn("pre-stop delay skipped", "signal", sig)
 		}
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case sig := <-signals:
+			logger.Warn("closing in-flight requests", "signal", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	start := time.Now()
+	grpcStopped := make(chan error, 1)
+	if grpcServer != nil {
+		go func() { grpcStopped <- grpcServer.Shutdown(ctx) }()
+	} else {
+		grpcStopped <- nil
+	}
+	if err := server.Shutdown(ctx); err != nil {
+		server.Close()
+		<-grpcStopped
+		return fmt.Errorf("graceful shutdown failed: %w", err)
+	}
+	if err := <-grpcStopped; err != nil {
+		return fmt.Errorf("graceful gRPC shutdown failed: %w", err)
+	}
+	logger.Info("in-flight requests drained", "duration", time.Since(start))
 	return nil
 }
 
+// handlers groups HTTP handlers used by the router.
+type handlers struct {
+	user       *handler.UserHandler
+	product    *handler.ProductHandler
+	catalog    *handler.PublicCatalogHandler
+	order      *handler.OrderHandler
+	export     *handler.OrderExportHandler
+	announce   *handler.AnnouncementHandler
+	bulk       *handler.BulkOperationHandler
+	stock      *handler.StockHandler
+	consent    *handler.ConsentHandler
+	preference *handler.PreferenceHandler
+	phone      *handler.PhoneHandler
+	email      *handler.EmailVerificationHandler
+	inbox      *handler.InboxHandler
+	scim       *handler.SCIMHandler
+	oidc       *handler.OIDCHandler
+	token      *handler.TokenHandler
+	quota      *handler.QuotaHandler
+	tag        *handler.TagHandler
+	attribute  *handler.AttributeHandler
+	slot       *handler.DeliverySlotHandler
+	invoice    *handler.InvoiceHandler
+	webhook    *handler.PaymentWebhookHandler
+	template   *handler.NotificationTemplateHandler
+	system     *handler.SystemHandler
+	health     *handler.HealthHandler
+	metrics    http.Handler // Prometheus scrape endpoint, nil unless metrics are exported to Prometheus
+	reporting  reportingHandlers
+}
+
+// reportingHandlers serve reporting and export endpoints from the reporting pool.
+type reportingHandlers struct {
+	product  *handler.ProductHandler
+	order    *handler.OrderHandler
+	stock    *handler.StockHandler
+	snapshot *handler.StockSnapshotHandler
+	change   *handler.ChangeHandler
+	journal  *handler.AccountingHandler
+}
+
+// middlewares groups middlewares that depend on application services.
+type middlewares struct {
+	region      func(http.Handler) http.Handler // Forwards writes and order requests outside the primary region
+	recoverer   func(http.Handler) http.Handler // Recovers from panics and reports them
+	realIP      func(http.Handler) http.Handler // Takes the client IP from headers of trusted proxies
+	requestLog  func(http.Handler) http.Handler // Logs every request with its status, duration and user
+	debug       func(http.Handler) http.Handler // Captures request and response bodies for debugging
+	jwt         func(http.Handler) http.Handler // Validates the access token
+	user        func(http.Handler) http.Handler // Loads the authenticated user, must follow jwt
+	idempotency func(http.Handler) http.Handler // Replays responses of retried writes, must follow jwt or an API key check
+	catalog     func(http.Handler) http.Handler // Limits catalog browsing requests served at once, so they cannot starve checkout
+	rateLimit   func(http.Handler) http.Handler // Limits the request rate per user, or per client IP before jwt
+	authLimit   func(http.Handler) http.Handler // Limits login and registration attempts per client IP, password changes per user
+}
+
 // setupRouter configures HTTP router with middleware and routes.
-// Public routes: user registration and authentication.
-// Protected routes (require JWT token): product and order operations.
-func setupRouter(sentryHandler *sentryhttp.Handler, userHandler *handler.UserHandler, productHandler *handler.ProductHandler, orderHandler *handler.OrderHandler, cfg *config.Config) *chi.Mux {
+// Outside the primary region, writes and order requests are forwarded to the primary region.
+// Public routes: health probes, user registration, authentication, email verification links and the public catalog.
+// Public and protected routes are rate limited per client IP and per user; login, registration and password changes have a stricter limit.
+// Protected routes (require JWT token): product and order operations, delivery slot availability and order history exports; catalog browsing is limited by a bulkhead.
+// SCIM routes (require "scim" API key): user provisioning by identity providers.
+// SCIM and OAuth routes count calls against the API client's quotas.
+// Admin routes (require admin API key): legal document publishing, announcements, order total checks, catalog management and bulk operations, delivery slots, notification templates, system status and configuration.
+// Reporting routes under /admin (require admin or "analyst" API key): catalog export and issues, change feed, consistency reports, stock snapshots.
+// Resend routes under /admin (require admin or "support" API key): order confirmations, invoices and verification emails.
+// Refund request routes under /admin: requested with admin or "support" API key, decided with admin or "finance" API key.
+// Accounting routes under /admin (require admin or "finance" API key): payment journal export.
+// OAuth routes (require "gateway" or "introspection" API key): token introspection and revocation.
+// Webhook routes (signed by the payment provider): payment disputes.
+func setupRouter(sentryHandler *sentryhttp.Handler, h *handlers, mw *middlewares, cfg *config.Config) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Middleware for error handling and monitoring
-	r.Use(sentryHandler.Handle)           // Sentry for error tracking
-	r.Use(middleware.Recoverer)           // Panic recovery
-	r.Use(middleware.RequestID)           // Generate unique ID for each request
-	r.Use(middleware.RealIP)              // Get real client IP
+	r.Use(sentryHandler.Handle)          // Sentry for error tracking
+	r.Use(middleware.RequestID)          // Generate unique ID for each request
+	r.Use(handler.HTTPMetricsMiddleware) // Request count, duration and requests in flight
+	r.Use(mw.recoverer)                  // Panic recovery with Sentry report
+	r.Use(mw.realIP)                     // Client IP forwarded by trusted proxies
 	r.Use(func(next http.Handler) http.Handler {
-		return otelhttp.NewHandler(next, "server") // OpenTelemetry tracing
+		// OpenTelemetry tracing; request metrics are recorded by HTTPMetricsMiddleware, so otelhttp records none
+		return otelhttp.NewHandler(next, "server", otelhttp.WithMeterProvider(noop.NewMeterProvider()))
 	})
+	r.Use(handler.RouteTelemetryMiddleware)                   // Name spans by route pattern
+	r.Use(mw.requestLog)                                      // Log requests with their trace ID
+	r.Use(mw.region)                                          // Forward writes and order requests to the primary region
+	r.Use(mw.debug)                                           // Debug body capture, per environment or request
+	r.Use(handler.PoolExhaustionMiddleware(cfg.DBRetryAfter)) // 503 instead of 500 when no database connection is available
 
 	// Swagger documentation
-	r.Get("/swagger/*", httpSwagger.WrapHandler)
+	switch cfg.SwaggerMode() {
+	case config.SwaggerModePublic:
+		r.Get("/swagger/*", httpSwagger.WrapHandler)
+	case config.SwaggerModeBasic:
+		r.With(middleware.BasicAuth("swagger", map[string]string{
+			cfg.Swagger.Username: cfg.Swagger.Password,
+		})).Get("/swagger/*", httpSwagger.WrapHandler)
+	case config.SwaggerModeAdmin:
+		r.With(handler.APIKeyMiddleware(cfg.APIKeys, "admin")).Get("/swagger/*", httpSwagger.WrapHandler)
+	}
+
+	// Health probes, readiness fails while the server drains before shutdown
+	r.Get("/healthz", h.health.Live)
+	r.Get("/readyz", h.health.Ready)
+
+	// Public keys of access tokens for services validating tokens themselves
+	r.Get("/.well-known/jwks.json", h.token.JWKS)
+	if h.metrics != nil {
+		r.Method(http.MethodGet, "/metrics", h.metrics)
+	}
+
+	// Public routes (no authentication required), login and registration strictly rate limited against credential stuffing
+	r.With(mw.authLimit).Post("/users/register", h.user.Register)
+	r.With(mw.authLimit).Post("/users/login", h.user.Login)
+	r.With(mw.rateLimit).Get("/users/check-username", h.user.CheckUsername)
+	r.With(mw.rateLimit).Get("/users/email/verify", h.email.Verify)
+
+	// Public catalog embedded in marketing sites, cached by browsers and CDNs
+	r.Route("/public", func(r chi.Router) {
+		r.Use(handler.PublicCORSMiddleware)
+		r.Use(mw.rateLimit)
+		r.Use(mw.catalog)
+
+		r.Get("/products", h.catalog.List)
+		r.Get("/products/{id}", h.catalog.Get)
+	})
 
-	// Public routes (no authentication required)
-	r.Post("/users/register", userHandler.Register)
-	r.Post("/users/login", userHandler.Login)
+	// OpenID Connect login routes
+	r.Group(func(r chi.Router) {
+		r.Use(mw.rateLimit)
+
+		r.Get("/auth/oidc", h.oidc.Providers)
+		r.Get("/auth/oidc/{provider}/login", h.oidc.Login)
+		r.Get("/auth/oidc/{provider}/callback", h.oidc.Callback)
+	})
 
 	// Protected routes (require JWT token)
 	r.Group(func(r chi.Router) {
-		r.Use(handler.JWTMiddleware([]byte(cfg.JWTSecret)))
+		r.Use(mw.jwt)
+		r.Use(mw.rateLimit)
+		r.Use(mw.user)
+
+		// Account routes (available before the latest documents are accepted)
+		r.Post("/users/logout", h.token.Logout)
+		r.Get("/users/me", h.user.Me)
+		r.Delete("/users/me", h.user.DeleteAccount)
+		r.With(mw.authLimit).Post("/users/me/password", h.user.ChangePassword) // Strictly limited against guessing the current password
+		r.Get("/users/me/login-history", h.user.LoginHistory)
+		r.Get("/users/me/consents", h.consent.History)
+		r.Post("/users/me/consents", h.consent.Accept)
+		r.Get("/users/me/orders/export", h.export.Export)
+
+		// Routes below require acceptance of the latest legal documents
+		r.Group(func(r chi.Router) {
+			r.Use(h.consent.RequireConsent)
+
+			// User profile, preference and phone number routes
+			r.Patch("/users/me", h.user.UpdateProfile)
+			r.Put("/users/me/locale", h.user.SetLocale)
+			r.Get("/users/me/preferences", h.preference.Get)
+			r.Put("/users/me/preferences", h.preference.Update)
+			r.Put("/users/me/phone", h.phone.Set)
+			r.Post("/users/me/phone/verify", h.phone.Verify)
+			r.Delete("/users/me/phone", h.phone.Delete)
+
+			// Notification inbox routes
+			r.Get("/users/me/notifications", h.inbox.List)
+			r.Get("/users/me/notifications/unread-count", h.inbox.UnreadCount)
+			r.Post("/users/me/notifications/read-all", h.inbox.MarkAllRead)
+			r.Post("/users/me/notifications/{id}/read", h.inbox.MarkRead)
+
+			// Product routes
+			r.With(handler.RequireRole(domain.RoleAdmin, domain.RoleManager), mw.idempotency).Post("/products", h.product.Create)
+			r.With(handler.RequireRole(domain.RoleAdmin, domain.RoleManager)).Post("/products/import", h.product.Import)
+			r.With(mw.catalog).Get("/products", h.product.List)
+			r.With(mw.catalog).Get("/products/{id}", h.product.GetByID)
+			r.With(mw.catalog).Get("/products/by-barcode/{code}", h.product.GetByBarcode)
+
+			// Order routes
+			r.With(mw.idempotency).Post("/orders", h.order.Create)
+			r.Get("/orders", h.order.List)
+			r.Get("/orders/{id}", h.order.Get)
+			r.With(mw.idempotency).Post("/orders/{id}/pay", h.order.Pay)
+			r.Get("/orders/{id}/invoice/document", h.invoice.CustomerDocument)
+			r.Get("/delivery-slots", h.slot.Available)
+		})
+	})
+
+	// SCIM provisioning routes (require identity provider API key)
+	r.Route("/scim/v2", func(r chi.Router) {
+		r.Use(handler.APIKeyMiddleware(cfg.APIKeys, "scim"))
+		r.Use(h.quota.Enforce)
+
+		r.Get("/Users", h.scim.ListUsers)
+		r.Post("/Users", h.scim.CreateUser)
+		r.Get("/Users/{id}", h.scim.GetUser)
+		r.Put("/Users/{id}", h.scim.ReplaceUser)
+		r.Patch("/Users/{id}", h.scim.PatchUser)
+		r.Delete("/Users/{id}", h.scim.DeleteUser)
+	})
+
+	// Payment provider webhooks, authenticated by the provider's signature
+	r.Post("/webhooks/payments/{provider}", h.webhook.Handle)
 
-		// Product routes
-		r.Post("/products", productHandler.Create)
-		r.Get("/products/{id}", productHandler.GetByID)
+	// Token introspection and revocation routes (require API key of a sibling service or the gateway)
+	r.Route("/oauth", func(r chi.Router) {
+		r.Use(handler.APIKeyMiddleware(cfg.APIKeys, "gateway", "introspection"))
+		r.Use(h.quota.Enforce)
 
-		// Order routes
-		r.Post("/orders", orderHandler.Create)
+		r.Post("/introspect", h.token.Introspect)
+		r.Post("/revoke", h.token.Revoke)
+	})
+
+	// Admin routes (require admin API key)
+	r.Route("/admin", func(r chi.Router) {
+		// Reporting and export routes are also open to analysts and read through the reporting pool
+		r.Group(func(r chi.Router) {
+			r.Use(handler.APIKeyMiddleware(cfg.APIKeys, "admin", "analyst"))
+
+			r.Get("/products/export", h.reporting.product.Export)
+			r.Get("/products/issues", h.reporting.product.Issues)
+			r.Get("/changes", h.reporting.change.Feed)
+			r.Get("/orders/total-mismatches", h.reporting.order.CheckTotals)
+			r.Get("/stock/drift", h.reporting.stock.CheckDrift)
+			r.Get("/stock/snapshots", h.reporting.snapshot.Export)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(handler.APIKeyMiddleware(cfg.APIKeys, "admin"))
+
+			r.Post("/legal-documents", h.consent.Publish)
+			r.Get("/announcements", h.announce.List)
+			r.Post("/announcements", h.announce.Create)
+			r.Get("/announcements/{id}", h.announce.Get)
+			r.Post("/bundles", h.product.CreateBundle)
+			r.Patch("/products/bulk", h.product.BulkUpdate)
+			r.Post("/products/drafts/cleanup", h.product.CleanupDrafts)
+			r.Post("/products/bulk-operations", h.bulk.Create)
+			r.Get("/products/bulk-operations/{id}", h.bulk.Get)
+			r.Get("/products/bulk-operations/{id}/results", h.bulk.Results)
+			r.Delete("/products/{id}", h.product.Delete)
+			r.Post("/products/{id}/status", h.product.ChangeStatus)
+			r.Get("/products/{id}/history", h.product.History)
+			r.Get("/tags", h.tag.List)
+			r.Post("/tags/merge", h.tag.Merge)
+			r.Post("/tags/{tag}/rename", h.tag.Rename)
+			r.Get("/categories/{category}/attributes", h.attribute.List)
+			r.Put("/categories/{category}/attributes/{name}", h.attribute.Define)
+			r.Delete("/categories/{category}/attributes/{name}", h.attribute.Delete)
+			r.Get("/delivery-slots", h.slot.List)
+			r.Post("/delivery-slots", h.slot.Create)
+			r.Put("/delivery-slots/{id}/capacity", h.slot.UpdateCapacity)
+			r.Post("/orders/total-mismatches/repair", h.order.RepairTotals)
+			r.Post("/orders/{id}/status", h.order.ChangeStatus)
+			r.Get("/orders/{id}/events", h.order.Events)
+			r.Get("/orders/{id}/state", h.order.StateAt)
+			r.With(mw.idempotency).Post("/orders/{id}/payments", h.order.RecordPayment)
+			r.With(mw.idempotency).Post("/orders/{id}/refunds", h.order.Refund)
+			r.Post("/orders/{id}/invoice", h.invoice.Issue)
+			r.Get("/orders/{id}/invoice", h.invoice.Get)
+			r.Get("/orders/{id}/invoice/document", h.invoice.Document)
+			r.Get("/settings/order-numbers", h.order.NumberSettings)
+			r.Put("/settings/order-numbers", h.order.ConfigureNumbers)
+			r.Get("/products/{id}/stock-movements", h.stock.History)
+			r.With(mw.idempotency).Post("/products/{id}/stock-movements", h.stock.RecordMovement)
+			r.Post("/stock/drift/repair", h.stock.RepairDrift)
+			r.Post("/stock/import", h.stock.Import)
+			r.Get("/api-clients/{client}/quotas", h.quota.Get)
+			r.Put("/api-clients/{client}/quotas", h.quota.Set)
+			r.Post("/api-clients/{client}/quotas/reset", h.quota.Reset)
+			r.Get("/notification-templates", h.template.List)
+			r.Post("/notification-templates/{name}", h.template.Save)
+			r.Get("/notification-templates/{name}/versions", h.template.Versions)
+			r.Post("/notification-templates/{name}/preview", h.template.Preview)
+			r.Get("/system/status", h.system.Status)
+			r.Get("/system/config", h.system.Config)
+			r.Put("/users/{id}/role", h.user.SetRole)
+			r.Put("/users/{id}/sandbox", h.user.SetSandbox)
+		})
+
+		// Customer service resends notifications customers lost and looks up prices customers were charged
+		r.Group(func(r chi.Router) {
+			r.Use(handler.APIKeyMiddleware(cfg.APIKeys, "admin", "support"))
+
+			r.Get("/products/{id}/price-at", h.product.PriceAt)
+
+			r.Post("/orders/{id}/confirmation/resend", h.order.ResendConfirmation)
+			r.Post("/orders/{id}/invoice/resend", h.invoice.Resend)
+			r.Post("/users/{id}/verification-email/resend", h.email.Resend)
+		})
+
+		// Refunds are requested by support and approved or rejected by finance
+		r.Group(func(r chi.Router) {
+			r.Use(handler.APIKeyMiddleware(cfg.APIKeys, "admin", "support", "finance"))
+
+			r.Get("/orders/{id}/refund-requests", h.order.RefundRequests)
+			r.Get("/refund-requests", h.order.PendingRefundRequests)
+			r.Get("/refund-requests/{id}", h.order.GetRefundRequest)
+			r.With(handler.APIKeyMiddleware(cfg.APIKeys, "admin", "support")).Post("/orders/{id}/refund-requests", h.order.RequestRefund)
+			r.With(handler.APIKeyMiddleware(cfg.APIKeys, "admin", "finance")).Post("/refund-requests/{id}/approve", h.order.ApproveRefund)
+			r.With(handler.APIKeyMiddleware(cfg.APIKeys, "admin", "finance")).Post("/refund-requests/{id}/reject", h.order.RejectRefund)
+		})
+
+		// Finance imports the payment journal into accounting software, read through the reporting pool
+		r.Group(func(r chi.Router) {
+			r.Use(handler.APIKeyMiddleware(cfg.APIKeys, "admin", "finance"))
+
+			r.Get("/accounting/journal", h.reporting.journal.Journal)
+		})
 	})
 
 	return r
 }
 
+// catalogBulkhead returns middleware limiting catalog browsing requests, by default to three quarters
+// of the database pool, so order creation and payment callbacks always find a connection.
+func catalogBulkhead(limits config.CatalogLimits, db *pgxpool.Pool) func(http.Handler) http.Handler {
+	concurrency := limits.MaxConcurrent
+	switch {
+	case concurrency < 0:
+		return func(next http.Handler) http.Handler { return next }
+	case concurrency == 0:
+		concurrency = max(int(db.Config().MaxConns)*3/4, 1)
+	}
+	return handler.NewBulkhead("catalog", concurrency, limits.Queue, limits.MaxWait).Middleware
+}
+
+// requestLog returns middleware logging every request, or no middleware if the request log is disabled.
+func requestLog(cfg config.RequestLog, logger logger.Logger) func(http.Handler) http.Handler {
+	if !cfg.Enabled {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return handler.RequestLogMiddleware(logger, cfg.BodyLimit)
+}
+
+// rateLimit returns middleware limiting the requests of the class to requests per period, or no middleware if requests is 0.
+func rateLimit(limiter ratelimit.Limiter, class string, requests int, period time.Duration, logger logger.Logger) func(http.Handler) http.Handler {
+	if requests == 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return handler.NewRateLimiter(limiter, class, ratelimit.Limit{Requests: requests, Per: period}, logger).Middleware
+}
+
+// checkSchemaVersion compares the database schema with the embedded migrations.
+// A dirty or outdated schema fails the check in strict mode and is logged in warn mode.
+// A newer schema is only logged: migrations are applied before deploying, so older binaries meet it during rollouts.
+func checkSchemaVersion(ctx context.Context, db *pgxpool.Pool, mode string, logger logger.Logger) error {
+	if mode == config.SchemaCheckOff {
+		return nil
+	}
+	version, dirty, err := postgresrepo.SchemaVersion(ctx, db)
+	if err != nil {
+		return fmt.Errorf("unable to read database schema version: %w", err)
+	}
+
+	err = migrations.Check(version, dirty)
+	switch {
+	case err == nil:
+		logger.Info("database schema version checked", "version", version)
+		return nil
+	case errors.Is(err, migrations.ErrSchemaNewer), mode == config.SchemaCheckWarn:
+		logger.Warn("database schema does not match the binary", "error", err)
+		return nil
+	}
+	return fmt.Errorf("%w (set SCHEMA_CHECK=warn to start anyway)", err)
+}
+
+// newReportingPool creates the database pool of reporting and export endpoints.
+// Sessions are read-only and cancel statements exceeding the configured timeout.
+// Slow queries are expected there, so they are not logged.
+func newReportingPool(cfg *config.Config) (*pgxpool.Pool, error) {
+	databaseURL := cfg.Reporting.DatabaseURL
+	if databaseURL == "" {
+		databaseURL = cfg.DatabaseURL
+	}
+	poolConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reporting database url: %w", err)
+	}
+	poolConfig.MaxConns = cfg.Reporting.MaxConns
+	poolConfig.ConnConfig.RuntimeParams["application_name"] = "product-api-reporting"
+	poolConfig.ConnConfig.RuntimeParams["default_transaction_read_only"] = "on"
+	poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.Reporting.StatementTimeout.Milliseconds(), 10)
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create reporting connection pool: %w", err)
+	}
+	return pool, nil
+}
+
+// newShadowPool creates the database pool of the secondary product repository receiving shadow traffic.
+func newShadowPool(cfg *config.Config) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.Shadow.DatabaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shadow database url: %w", err)
+	}
+	poolConfig.MaxConns = cfg.Shadow.MaxConns
+	poolConfig.ConnConfig.RuntimeParams["application_name"] = "product-api-shadow"
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create shadow connection pool: %w", err)
+	}
+	return pool, nil
+}
+
+// newReplicaPool creates a read-only database pool of a replica serving product lookups.
+func newReplicaPool(cfg *config.Config, databaseURL string) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid replica database url: %w", err)
+	}
+	poolConfig.MaxConns = cfg.Replicas.MaxConns
+	poolConfig.ConnConfig.RuntimeParams["application_name"] = "product-api-replica"
+	poolConfig.ConnConfig.RuntimeParams["default_transaction_read_only"] = "on"
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create replica connection pool: %w", err)
+	}
+	return pool, nil
+}
+
+// configureSwaggerInfo sets the host, base path and scheme of the generated API documentation
+// from the public URL instead of the values in annotations.
+func configureSwaggerInfo(publicURL string) error {
+	u, err := url.Parse(publicURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid public url %q", publicURL)
+	}
+
+	docs.SwaggerInfo.Host = u.Host
+	docs.SwaggerInfo.BasePath = "/" + strings.Trim(u.Path, "/")
+	docs.SwaggerInfo.Schemes = []string{u.Scheme}
+	return nil
+}
+
+// newPaymentRegistry creates payment providers from configuration.
+// The mock provider is not offered in prod, where it only pays orders of sandbox accounts.
+func newPaymentRegistry(cfg *config.Config, logger logger.Logger) *payment.Registry {
+	var providers []payment.Provider
+	if cfg.Payments.PayPalClientID != "" {
+		providers = append(providers, payment.NewPayPalProvider(payment.PayPalConfig{
+			BaseURL:      cfg.Payments.PayPalBaseURL,
+			ClientID:     cfg.Payments.PayPalClientID,
+			ClientSecret: cfg.Payments.PayPalClientSecret,
+			WebhookID:    cfg.Payments.PayPalWebhookID,
+		}))
+	}
+	if cfg.Payments.StripeSecretKey != "" {
+		providers = append(providers, payment.NewStripeProvider(payment.StripeConfig{
+			BaseURL:       cfg.Payments.StripeBaseURL,
+			SecretKey:     cfg.Payments.StripeSecretKey,
+			WebhookSecret: cfg.Payments.StripeWebhookSecret,
+		}))
+	}
+	mock := payment.NewMemoryProvider(cfg.Payments.MockWebhookSecret, logger)
+	if cfg.Env != "prod" {
+		providers = append(providers, mock)
+	}
+
+	var fallback payment.Provider
+	var others []payment.Provider
+	for _, p := range providers {
+		if p.Name() == cfg.Payments.DefaultProvider {
+			fallback = p
+		} else {
+			others = append(others, p)
+		}
+	}
+	// Orders of sandbox accounts are paid with the mock provider, also in prod where checkouts cannot select it
+	return payment.NewRegistry(fallback, others...).WithSandbox(mock)
+}
+
+// newSMSSender creates the sender of text messages, logging them unless Twilio is configured.
+func newSMSSender(cfg *config.Config, logger logger.Logger) notification.Sender {
+	if cfg.SMS.TwilioAccountSID == "" {
+		return notification.NewLogSender(domain.NotificationChannelSMS, logger)
+	}
+	return notification.NewTwilioSender(notification.TwilioConfig{
+		BaseURL:    cfg.SMS.TwilioBaseURL,
+		AccountSID: cfg.SMS.TwilioAccountSID,
+		AuthToken:  cfg.SMS.TwilioAuthToken,
+	}, notification.SMSSenders(cfg.SMS.Senders))
+}
+
+// newObjectStorage creates the configured object storage backend.
+func newObjectStorage(cfg *config.Config) (storage.Storage, error) {
+	switch cfg.Storage.Backend {
+	case "s3":
+		return storage.NewS3Storage(storage.S3Config{
+			Bucket:          cfg.Storage.Bucket,
+			Region:          cfg.Storage.Region,
+			Endpoint:        cfg.Storage.Endpoint,
+			PathStyle:       cfg.Storage.PathStyle,
+			AccessKeyID:     cfg.Storage.AccessKeyID,
+			SecretAccessKey: cfg.Storage.SecretAccessKey,
+		})
+	case "gcs":
+		return storage.NewGCSStorage(storage.GCSConfig{
+			Bucket:    cfg.Storage.Bucket,
+			AccessKey: cfg.Storage.AccessKeyID,
+			Secret:    cfg.Storage.SecretAccessKey,
+		})
+	default:
+		return storage.NewLocalStorage(cfg.Storage.LocalDir)
+	}
+}
+
+// newRateLimiter creates the configured rate limit backend and a function closing it.
+func newRateLimiter(cfg *config.Config) (ratelimit.Limiter, func(), error) {
+	if cfg.RateLimits.Backend != "redis" {
+		return ratelimit.NewMemoryLimiter(), func() {}, nil
+	}
+	options, err := redis.ParseURL(cfg.RateLimits.RedisURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+	client := redis.NewClient(options)
+	return ratelimit.NewRedisLimiter(client), func() { client.Close() }, nil
+}
+
+// newTokenKeys returns the keys access tokens are signed with, reading the private key of RS256 and EdDSA tokens.
+func newTokenKeys(cfg *config.Config) (*service.TokenKeys, error) {
+	var privateKey []byte
+	if cfg.JWTAlgorithm != service.TokenAlgHS256 {
+		var err error
+		if privateKey, err = os.ReadFile(cfg.JWTPrivateKeyFile); err != nil {
+			return nil, fmt.Errorf("read private key: %w", err)
+		}
+	}
+	return service.NewTokenKeys(cfg.JWTAlgorithm, privateKey, []byte(cfg.JWTSecret))
+}
+
+// newAccountingPolicy returns the rates and ledger accounts payments are journaled with.
+func newAccountingPolicy(cfg *config.Config) service.AccountingPolicy {
+	fees := make(map[string]domain.FeeSchedule)
+	for provider, percent := range cfg.Accounting.FeePercent {
+		fees[provider] = domain.FeeSchedule{Percent: percent, Fixed: cfg.Accounting.FeeFixed[provider]}
+	}
+	for provider, fixed := range cfg.Accounting.FeeFixed {
+		fees[provider] = domain.FeeSchedule{Percent: cfg.Accounting.FeePercent[provider], Fixed: fixed}
+	}
+	return service.AccountingPolicy{
+		Currency: cfg.Currency,
+		TaxRates: cfg.Accounting.TaxRates,
+		Fees:     fees,
+		Accounts: cfg.Accounting.Accounts,
+	}
+}
+
+// newOIDCRegistry creates OIDC identity providers from configuration.
+func newOIDCRegistry(cfg *config.Config) *oidc.Registry {
+	configs := make([]oidc.ProviderConfig, 0, len(cfg.OIDC.Issuers))
+	for name, issuer := range cfg.OIDC.Issuers {
+		configs = append(configs, oidc.ProviderConfig{
+			Name:         name,
+			IssuerURL:    issuer,
+			ClientID:     cfg.OIDC.ClientIDs[name],
+			ClientSecret: cfg.OIDC.ClientSecrets[name],
+			RedirectURL:  cfg.PublicURL + "/auth/oidc/" + name + "/callback",
+		})
+	}
+	return oidc.NewRegistry(configs...)
+}
+
 // initTracer initializes OpenTelemetry tracer for request tracing.
-// Uses stdout exporter to output traces to console.
-func initTracer() (*trace.TracerProvider, error) {
-	exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+// Exports traces to the OTLP collector if an endpoint is configured, otherwise to console.
+func initTracer(otlpEndpoint, region string, sampling telemetry.Sampling) (*trace.TracerProvider, error) {
+	var (
+		exporter trace.SpanExporter
+		err      error
+	)
+	if otlpEndpoint != "" {
+		exporter, err = otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(otlpEndpoint+"/v1/traces"))
+	} else {
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	}
+	if err != nil {
+		return nil, err
+	}
+	processor := trace.NewBatchSpanProcessor(exporter)
+	if sampling.Errors {
+		processor = telemetry.NewErrorSpanProcessor(processor)
+	}
+	res, err := regionResource(region)
 	if err != nil {
 		return nil, err
 	}
 	tp := trace.NewTracerProvider(
-		trace.WithBatcher(exporter),
+		trace.WithResource(res),
+		trace.WithSampler(telemetry.NewSampler(sampling)),
+		trace.WithSpanProcessor(processor),
 	)
 	return tp, nil
 }
+
+// initMeter initializes OpenTelemetry meter for business metrics.
+// The returned handler serves metrics to Prometheus, it is nil for other exporters.
+func initMeter(cfg *config.Config) (*sdkmetric.MeterProvider, http.Handler, error) {
+	res, err := regionResource(cfg.Region.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	var exporter sdkmetric.Exporter
+	switch cfg.MetricsExporter() {
+	case config.MetricsExporterOTLP:
+		exporter, err = otlpmetrichttp.New(context.Background(), otlpmetrichttp.WithEndpointURL(cfg.OTLPEndpoint+"/v1/metrics"))
+	case config.MetricsExporterDogStatsD:
+		tags := []string{"env:" + cfg.Env, "service:" + cfg.Metrics.Service}
+		if cfg.Metrics.Version != "" {
+			tags = append(tags, "version:"+cfg.Metrics.Version)
+		}
+		if cfg.Region.Name != "" {
+			tags = append(tags, "region:"+cfg.Region.Name)
+		}
+		exporter, err = telemetry.NewDogStatsDExporter(cfg.Metrics.DogStatsDAddress, append(tags, cfg.Metrics.Tags...))
+	case config.MetricsExporterPrometheus:
+		// Metrics are collected when scraped, along with Go runtime and process metrics
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+		reader, err := otelprometheus.New(
+			otelprometheus.WithRegisterer(registry),
+			// Series of instances in different regions are told apart by a region label
+			otelprometheus.WithResourceAsConstantLabels(func(kv attribute.KeyValue) bool { return kv.Key == semconv.CloudRegionKey }),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		mp := sdkmetric.NewMeterProvider(sdkmetric.WithResource(res), sdkmetric.WithReader(reader))
+		return mp, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+	default:
+		return sdkmetric.NewMeterProvider(sdkmetric.WithResource(res)), nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(cfg.Metrics.Interval))),
+	)
+	return mp, nil, nil
+}
+
+// regionResource describes the service with the region of the instance for traces and metrics, if configured.
+func regionResource(region string) (*resource.Resource, error) {
+	if region == "" {
+		return resource.Default(), nil
+	}
+	return resource.Merge(resource.Default(), resource.NewSchemaless(semconv.CloudRegion(region)))
+}
+
+// regionTags returns the Sentry tags of the region of the instance, nil if not configured.
+func regionTags(region string) map[string]string {
+	if region == "" {
+		return nil
+	}
+	return map[string]string{"region": region}
+}
+
+// observePool reports the connections of the database pool as gauges.
+func observePool(name string, pool *pgxpool.Pool) error {
+	return telemetry.ObservePool(name, func() telemetry.PoolStats {
+		stat := pool.Stat()
+		return telemetry.PoolStats{
+			Acquired: int64(stat.AcquiredConns()),
+			Idle:     int64(stat.IdleConns()),
+			Max:      int64(stat.MaxConns()),
+		}
+	})
+}
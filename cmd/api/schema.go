@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"product-api/internal/config"
+	postgresrepo "product-api/internal/repository/postgres"
+	"product-api/migrations"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// errUnsafeSchema is returned when statements of the binary fail to prepare against a schema version.
+var errUnsafeSchema = errors.New("statements of the binary fail to prepare, the deploy is not safe")
+
+const schemaUsage = `Usage: product-api schema check [-lock-timeout 5s]
+
+Prepares every SQL statement of the binary, without running it, against the current database schema
+and against the schema with the pending migrations of the binary applied. Passing both means the binary
+can serve before and after migrating, so it can roll out while the other color still runs.
+
+Pending migrations are applied in a transaction that is rolled back. Their locks are held while the
+statements are prepared, so prefer a staging copy of the database, or a time of low traffic.
+The connection URL is read from DATABASE_URL.`
+
+// runSchemaCommand runs the schema subcommand with its arguments.
+func runSchemaCommand(args []string) error {
+	if len(args) == 0 || args[0] != "check" {
+		return errors.New(schemaUsage)
+	}
+	flags := flag.NewFlagSet("schema check", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprintln(flags.Output(), schemaUsage) }
+	lockTimeout := flags.Duration("lock-timeout", 5*time.Second, "Give up if migrations wait longer for a lock")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	cfg := config.MustLoad()
+	ctx := context.Background()
+	db, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("unable to create connection pool: %w", err)
+	}
+	defer db.Close()
+	return checkSchemaCompatibility(ctx, db, *lockTimeout, os.Stdout)
+}
+
+// checkSchemaCompatibility prepares the statements of the binary against the current schema version and,
+// within a transaction rolled back afterwards, against the schema with pending migrations applied.
+// Writes a report to w. Returns errUnsafeSchema if statements fail to prepare against either version.
+func checkSchemaCompatibility(ctx context.Context, db *pgxpool.Pool, lockTimeout time.Duration, w io.Writer) error {
+	version, dirty, err := postgresrepo.SchemaVersion(ctx, db)
+	if err != nil {
+		return fmt.Errorf("unable to read database schema version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("%w at version %d", migrations.ErrSchemaDirty, version)
+	}
+	statements, err := postgresrepo.Statements()
+	if err != nil {
+		return fmt.Errorf("unable to list statements: %w", err)
+	}
+	pending, err := migrations.Pending(version)
+	if err != nil {
+		return fmt.Errorf("unable to list migrations: %w", err)
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }() // Nothing is ever committed
+	if _, err := tx.Exec(ctx, `SELECT set_config('lock_timeout', $1, true)`, strconv.FormatInt(lockTimeout.Milliseconds(), 10)); err != nil {
+		return err
+	}
+
+	safe := true
+	report := func(label string) error {
+		failed, err := postgresrepo.PrepareStatements(ctx, tx, statements)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "schema version %s: %d statements prepared, %d failed\n", label, len(statements), len(failed))
+		for _, f := range failed {
+			fmt.Fprintf(w, "  %s %s: %v\n", f.Pos, f.Func, f.Err)
+		}
+		safe = safe && len(failed) == 0
+		return nil
+	}
+
+	if err := report(fmt.Sprintf("%d (current)", version)); err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Fprintf(w, "no pending migrations, version %d is the latest of the binary\n", version)
+	} else {
+		for _, m := range pending {
+			sql, err := m.SQL()
+			if err != nil {
+				return err
+			}
+			// Without arguments, the file is sent with the simple protocol, which runs multiple statements
+			if _, err := tx.Exec(ctx, sql); err != nil {
+				return fmt.Errorf("migration %s failed: %w", m.Name, err)
+			}
+		}
+		next := pending[len(pending)-1]
+		if err := report(fmt.Sprintf("%d (%d pending migrations applied)", next.Version, len(pending))); err != nil {
+			return err
+		}
+	}
+
+	if !safe {
+		return errUnsafeSchema
+	}
+	fmt.Fprintln(w, "the binary is compatible with the current and next schema versions")
+	return nil
+}
@@ -0,0 +1,209 @@
+// Command loadtest exercises the register → login → browse → order flow
+// against a running instance of the API and reports latency percentiles per step.
+// Products are created with the access token of a manager or admin, as customers cannot create them.
+// All virtual users come from one IP, so start the instance with RATE_LIMIT_REQUESTS=0 and AUTH_RATE_LIMIT_REQUESTS=0.
+//
+// Usage:
+//
+//	go run ./cmd/loadtest -url http://localhost:8080 -product-token <token> -concurrency 20 -duration 1m
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Flow steps, in execution order.
+const (
+	stepRegister      = "register"
+	stepLogin         = "login"
+	stepCreateProduct = "create_product"
+	stepGetProduct    = "get_product"
+	stepCreateOrder   = "create_order"
+)
+
+var steps = []string{stepRegister, stepLogin, stepCreateProduct, stepGetProduct, stepCreateOrder}
+
+// options contains load test parameters.
+type options struct {
+	baseURL        string
+	concurrency    int
+	duration       time.Duration
+	iterations     int // Per worker, 0 means until duration elapses
+	ordersPerUser  int
+	timeout        time.Duration
+	termsVersion   string
+	privacyVersion string
+	productToken   string // Access token of a manager or admin
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.baseURL, "url", "http://localhost:8080", "Base URL of the API")
+	flag.IntVar(&opts.concurrency, "concurrency", 10, "Number of concurrent virtual users")
+	flag.DurationVar(&opts.duration, "duration", 30*time.Second, "Test duration")
+	flag.IntVar(&opts.iterations, "iterations", 0, "Flow iterations per virtual user (0 = until duration elapses)")
+	flag.IntVar(&opts.ordersPerUser, "orders", 5, "Browse and order steps per registered user")
+	flag.DurationVar(&opts.timeout, "timeout", 10*time.Second, "HTTP request timeout")
+	flag.StringVar(&opts.termsVersion, "terms-version", "", "Accepted terms of service version, if documents are published")
+	flag.StringVar(&opts.privacyVersion, "privacy-version", "", "Accepted privacy policy version, if documents are published")
+	flag.StringVar(&opts.productToken, "product-token", "", "Access token of a manager or admin creating the products (required)")
+	flag.Parse()
+
+	if opts.concurrency < 1 || opts.ordersPerUser < 1 {
+		log.Fatal("concurrency and orders must be positive")
+	}
+	if opts.productToken == "" {
+		log.Fatal("product-token is required, products can only be created by managers and admins")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, opts.duration)
+	defer cancelTimeout()
+
+	stats := newStats()
+	client := &http.Client{Timeout: opts.timeout}
+
+	log.Printf("running load test against %s: %d virtual users for %s", opts.baseURL, opts.concurrency, opts.duration)
+	started := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.concurrency; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			vu := &virtualUser{id: worker, client: client, opts: &opts, stats: stats}
+			for n := 0; opts.iterations == 0 || n < opts.iterations; n++ {
+				if ctx.Err() != nil {
+					return
+				}
+				vu.runFlow(ctx, n)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	stats.report(os.Stdout, time.Since(started))
+}
+
+// virtualUser runs the flow sequentially, as a single client would.
+type virtualUser struct {
+	id     int
+	client *http.Client
+	opts   *options
+	stats  *stats
+}
+
+// runFlow registers a new user, logs in, creates a product as the manager and repeatedly views and orders it.
+// The flow stops at the first failed step.
+func (vu *virtualUser) runFlow(ctx context.Context, iteration int) {
+	email := fmt.Sprintf("loadtest-%d-%d-%d@example.com", time.Now().UnixNano(), vu.id, iteration)
+	password := "loadtest-password"
+
+	register := map[string]any{
+		"email":                    email,
+		"password":                 password,
+		"firstname":                "Load",
+		"lastname":                 "Test",
+		"birthdate":                "1990-01-01",
+		"accepted_terms_version":   vu.opts.termsVersion,
+		"accepted_privacy_version": vu.opts.privacyVersion,
+	}
+	if !vu.call(ctx, stepRegister, http.MethodPost, "/users/register", "", register, http.StatusCreated, nil) {
+		return
+	}
+
+	var login struct {
+		Token string `json:"token"`
+	}
+	credentials := map[string]any{"email": email, "password": password}
+	if !vu.call(ctx, stepLogin, http.MethodPost, "/users/login", "", credentials, http.StatusOK, &login) {
+		return
+	}
+
+	var product struct {
+		ID string `json:"ID"`
+	}
+	newProduct := map[string]any{
+		"description": "Load test product",
+		"tags":        []string{"loadtest"},
+		"quantity":    vu.opts.ordersPerUser,
+		"price":       9.99,
+	}
+	if !vu.call(ctx, stepCreateProduct, http.MethodPost, "/products", vu.opts.productToken, newProduct, http.StatusCreated, &product) {
+		return
+	}
+
+	for i := 0; i < vu.opts.ordersPerUser; i++ {
+		if !vu.call(ctx, stepGetProduct, http.MethodGet, "/products/"+product.ID, login.Token, nil, http.StatusOK, nil) {
+			return
+		}
+		order := map[string]any{"items": []map[string]any{{"product_id": product.ID, "quantity": 1}}}
+		if !vu.call(ctx, stepCreateOrder, http.MethodPost, "/orders", login.Token, order, http.StatusCreated, nil) {
+			return
+		}
+	}
+}
+
+// call performs a request, records its latency and outcome and decodes the response into out.
+// Returns false if the request failed or returned an unexpected status.
+// Requests interrupted by the end of the test are not recorded.
+func (vu *virtualUser) call(ctx context.Context, step, method, path, token string, body any, wantStatus int, out any) bool {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			log.Fatalf("failed to encode %s request: %v", step, err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, vu.opts.baseURL+path, reqBody)
+	if err != nil {
+		log.Fatalf("failed to create %s request: %v", step, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	start := time.Now()
+	resp, err := vu.client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		if ctx.Err() == nil {
+			vu.stats.record(step, latency, fmt.Sprintf("request error: %v", err))
+		}
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != wantStatus {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		vu.stats.record(step, latency, fmt.Sprintf("status %d", resp.StatusCode))
+		return false
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			vu.stats.record(step, latency, "invalid response body")
+			return false
+		}
+	}
+
+	vu.stats.record(step, latency, "")
+	return true
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// stepStats contains measurements of a single flow step.
+type stepStats struct {
+	latencies []time.Duration // Successful requests only
+	errors    map[string]int  // Failure counts by description
+}
+
+// stats collects measurements from all virtual users.
+type stats struct {
+	mu    sync.Mutex
+	steps map[string]*stepStats
+}
+
+func newStats() *stats {
+	s := &stats{steps: make(map[string]*stepStats, len(steps))}
+	for _, step := range steps {
+		s.steps[step] = &stepStats{errors: make(map[string]int)}
+	}
+	return s
+}
+
+// record stores the outcome of a request; failure is empty for successful requests.
+func (s *stats) record(step string, latency time.Duration, failure string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.steps[step]
+	if failure != "" {
+		st.errors[failure]++
+		return
+	}
+	st.latencies = append(st.latencies, latency)
+}
+
+// report writes request counts, throughput and latency percentiles per step.
+func (s *stats) report(w io.Writer, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "step\tok\terrors\trps\tp50\tp90\tp95\tp99\tmax\t\n")
+	for _, step := range steps {
+		st := s.steps[step]
+		slices.Sort(st.latencies)
+
+		errCount := 0
+		for _, n := range st.errors {
+			errCount += n
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t%s\t\n",
+			step, len(st.latencies), errCount,
+			float64(len(st.latencies))/elapsed.Seconds(),
+			percentile(st.latencies, 50), percentile(st.latencies, 90),
+			percentile(st.latencies, 95), percentile(st.latencies, 99),
+			percentile(st.latencies, 100),
+		)
+	}
+	_ = tw.Flush()
+
+	for _, step := range steps {
+		errs := s.steps[step].errors
+		failures := make([]string, 0, len(errs))
+		for failure := range errs {
+			failures = append(failures, failure)
+		}
+		slices.Sort(failures)
+		for _, failure := range failures {
+			fmt.Fprintf(w, "%s: %d x %s\n", step, errs[failure], failure)
+		}
+	}
+}
+
+// percentile returns the p-th percentile of sorted latencies (nearest-rank method).
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1].Round(time.Microsecond)
+}
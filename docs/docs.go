@@ -15,69 +15,154 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/orders": {
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "consumes": [
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "JSON Web Key Set of the keys access tokens are signed with, so other services can validate tokens\nwithout the shared secret. Tokens name their key in the kid header. Empty if tokens are signed with HS256.",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "Get the public keys of access tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.JWKSResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/accounting/journal": {
+            "get": {
+                "description": "Returns the double-entry journal of charges and refunds made from the first to the last day (UTC)\nas CSV importable by Xero (manual journals) or QuickBooks Online (journal entries).\nEach payment is one balanced journal of payment clearing, revenue, refunds, sales tax and payment fees.\nRows carry the currency of the order; Xero journals in its base currency, so rows of other currencies must be converted.\nPayments are journaled in the background, the latest ones may be missing for up to ACCOUNTING_POLL_INTERVAL.",
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "orders"
+                    "admin"
                 ],
-                "summary": "Create a new order",
+                "summary": "Export the payment journal",
                 "parameters": [
                     {
-                        "description": "Order details",
-                        "name": "order",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.CreateOrderRequest"
-                        }
+                        "type": "string",
+                        "description": "xero or quickbooks",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, e.g. 2024-01-01",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, e.g. 2024-01-31",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin or finance API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Journal CSV",
                         "schema": {
-                            "$ref": "#/definitions/domain.Order"
+                            "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or product not found",
+                        "description": "Invalid format or days",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid API key",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/products": {
-            "post": {
-                "security": [
+        "/admin/announcements": {
+            "get": {
+                "description": "Returns announcements with their delivery stats, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List announcements",
+                "parameters": [
                     {
-                        "ApiKeyAuth": []
+                        "type": "integer",
+                        "description": "Maximum number of announcements (1-100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of announcements to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
                     }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.AnnouncementListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit or offset",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Queues the announcement template with the given Headline and Message for the active users of the segment.\nIt is sent in batches in the background, as a marketing notification: users receive it only on the channels\nthey opted in to for marketing. Delivery stats are updated after every batch.",
                 "consumes": [
                     "application/json"
                 ],
@@ -85,107 +170,166 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "admin"
                 ],
-                "summary": "Create a new product",
+                "summary": "Send an announcement to a segment of users",
                 "parameters": [
                     {
-                        "description": "Product details",
-                        "name": "product",
+                        "description": "Announcement",
+                        "name": "announcement",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.CreateProductRequest"
+                            "$ref": "#/definitions/handler.CreateAnnouncementRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/domain.Product"
+                            "$ref": "#/definitions/domain.Announcement"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid request body, template variables or segment",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid API key",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/products/{id}": {
+        "/admin/announcements/{id}": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
+                "description": "Recipients counts the users of the segment processed so far: Sent, OptedOut and Failed add up to it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "admin"
                 ],
-                "summary": "Get a product by ID",
+                "summary": "Get an announcement with its delivery stats",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product ID",
+                        "description": "Announcement ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Product"
+                            "$ref": "#/definitions/domain.Announcement"
                         }
                     },
                     "400": {
-                        "description": "Invalid product ID",
+                        "description": "Invalid announcement ID",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Invalid API key",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Product not found",
+                        "description": "Announcement not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users/login": {
-            "post": {
+        "/admin/api-clients/{client}/quotas": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get quotas and current usage of an API client",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API client name, e.g. gateway",
+                        "name": "client",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.QuotaUsage"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Overrides the configured default limit of the daily or monthly quota. A limit of 0 disables the quota.",
                 "consumes": [
                     "application/json"
                 ],
@@ -193,50 +337,119 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "admin"
                 ],
-                "summary": "Log in a user",
+                "summary": "Set a quota of an API client",
                 "parameters": [
                     {
-                        "description": "User credentials",
-                        "name": "credentials",
+                        "type": "string",
+                        "description": "API client name, e.g. gateway",
+                        "name": "client",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quota limit",
+                        "name": "quota",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.LoginRequest"
+                            "$ref": "#/definitions/handler.SetQuotaRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.LoginResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.QuotaUsage"
+                            }
                         }
                     },
                     "400": {
                         "description": "Invalid request body",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Invalid email or password",
+                        "description": "Invalid API key",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users/register": {
+        "/admin/api-clients/{client}/quotas/reset": {
+            "post": {
+                "description": "Clears the call counters of all quota windows of the client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reset quota usage of an API client",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API client name, e.g. gateway",
+                        "name": "client",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.QuotaUsage"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/bundles": {
             "post": {
+                "description": "Creates a product composed of existing products. Bundles have no stock of their own:\nordering a bundle allocates the stock of its components and adds a bundle line followed by component lines to the order.",
                 "consumes": [
                     "application/json"
                 ],
@@ -244,267 +457,9744 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "admin"
                 ],
-                "summary": "Register a new user",
+                "summary": "Create a bundle of products",
                 "parameters": [
                     {
-                        "description": "User registration details",
-                        "name": "user",
+                        "description": "Bundle details",
+                        "name": "bundle",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.RegisterRequest"
+                            "$ref": "#/definitions/handler.CreateBundleRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "$ref": "#/definitions/domain.Product"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation error",
+                        "description": "Invalid request body, barcode or attributes, unknown, repeated or nested components",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "409": {
-                        "description": "User with this email already exists",
+                        "description": "Barcode already taken",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.Order": {
-            "type": "object",
-            "properties": {
-                "createdAt": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.OrderItem"
-                    }
-                },
-                "totalAmount": {
-                    "type": "number",
-                    "format": "float64"
-                },
-                "userID": {
+        },
+        "/admin/categories/{category}/attributes": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List attribute definitions of a product category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product category",
+                        "name": "category",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.AttributeDefinition"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/categories/{category}/attributes/{name}": {
+            "put": {
+                "description": "Creates or replaces a typed attribute, e.g. \"screen_size\" of \"monitors\" as a number in inches.\nProduct attributes are validated against the definitions of their category when they are set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Define a product attribute of a category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product category",
+                        "name": "category",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attribute name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Attribute definition",
+                        "name": "attribute",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.DefineAttributeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.AttributeDefinition"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or definition",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Products keep their values of the attribute until their attributes are changed.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a product attribute definition",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product category",
+                        "name": "category",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attribute name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Attribute definition not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/changes": {
+            "get": {
+                "description": "Returns insert, update and delete changes of products, orders, order items and users in a stable order,\nwith the row after each change. Start with since=0 and pass Next of each page as since of the following one.\nSequence numbers identify changes, but the feed is not ordered by them.\nChanges are deleted after the retention period (CHANGE_LOG_RETENTION, 30 days by default); readers further behind\nget change_not_found and must start over from a full export.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Read the change feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sequence number of the last received change, 0 to start from the beginning",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of changes (1-5000, default 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin or analyst API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.ChangePage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid, unknown or deleted sequence number, invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/delivery-slots": {
+            "get": {
+                "description": "Returns all delivery windows of the region starting in the window with their reserved orders, including full ones.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List delivery slots of a region",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Deployment region of the slots",
+                        "name": "region",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the listed window, RFC 3339 (default now)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the listed window, RFC 3339 (default 14 days after from, at most 62 days)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.DeliverySlot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing region or invalid window",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a delivery window to the region that takes up to capacity orders. Each region has at most one slot starting at a time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a delivery slot",
+                "parameters": [
+                    {
+                        "description": "Delivery slot",
+                        "name": "slot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateDeliverySlotRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.DeliverySlot"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or window",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Region already has a slot starting at that time",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/delivery-slots/{id}/capacity": {
+            "put": {
+                "description": "Sets the number of orders the slot takes. It cannot be reduced below the orders already reserved; 0 closes an empty slot.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change the capacity of a delivery slot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery slot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New capacity",
+                        "name": "capacity",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateDeliverySlotCapacityRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.DeliverySlot"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid slot ID or request body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery slot not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Capacity is below the reserved orders",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/legal-documents": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "consents"
+                ],
+                "summary": "Publish a new legal document version",
+                "parameters": [
+                    {
+                        "description": "Document version",
+                        "name": "document",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PublishDocumentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.LegalDocument"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Document version already exists",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/notification-templates": {
+            "get": {
+                "description": "Returns the current version of every notification template with its variables, followed by\nits current versions in other locales. Templates never edited have version 0 and their built-in content.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List notification templates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.NotificationTemplateResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/notification-templates/{name}": {
+            "post": {
+                "description": "The version is sent from the next notification of this instance, and from other instances\nonce their templates reload (TEMPLATE_RELOAD_INTERVAL). Earlier versions are kept.\nVersions in a locale are sent to users of the locale, e.g. \"de-AT\", or of its language, e.g. \"de\";\nother users get the default version.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Save a new version of a notification template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template name, e.g. order_confirmation",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SaveTemplateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.NotificationTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template syntax, unknown variables or unsupported locale",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown template",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Template changed concurrently",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/notification-templates/{name}/preview": {
+            "post": {
+                "description": "Renders the given subject and body, or those sent to users of the locale, with sample values\nof the template variables overridden by the given values. Nothing is stored or sent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Preview a notification template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template name, e.g. order_confirmation",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template and variables",
+                        "name": "preview",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PreviewTemplateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TemplatePreviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template syntax, unknown variables or unsupported locale",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown template",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/notification-templates/{name}/versions": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List versions of a notification template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template name, e.g. order_confirmation",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language or locale of the versions, e.g. de; default versions if omitted",
+                        "name": "locale",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Newest first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.NotificationTemplate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported locale",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown template",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/total-mismatches": {
+            "get": {
+                "description": "Lists orders whose total differs from the sum of item price × quantity.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report orders with inconsistent totals",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of orders (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin or analyst API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.OrderTotalsReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/total-mismatches/repair": {
+            "post": {
+                "description": "Recomputes totals of orders whose total differs from the sum of item price × quantity.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Repair orders with inconsistent totals",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of orders (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.OrderTotalsReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/confirmation/resend": {
+            "post": {
+                "description": "Sends the order confirmation to the customer again through the channels the customer opted in to for order updates.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Resend the confirmation of an order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin or support API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Order was not paid or was cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/events": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the event log of an order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.OrderEvent"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/invoice": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the invoice of an order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.InvoiceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Invoice not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Issues an invoice over the order total with the next number of the current fiscal year, e.g. INV-2024-000001.\nInvoice numbers are strictly sequential without gaps. Invoices cannot be changed once issued.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Issue the invoice of an order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.InvoiceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Order not paid or already invoiced",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/invoice/document": {
+            "get": {
+                "description": "Redirects to a short-lived download URL of the archived invoice document in the object storage.\nStorage backends without presigned URLs serve the document directly.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Download the invoice document of an order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invoice document",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Invoice"
+                        }
+                    },
+                    "302": {
+                        "description": "Redirect to the document",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Invoice not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/invoice/resend": {
+            "post": {
+                "description": "Emails the invoice with a link to its document to the customer again,\nthrough the channels the customer opted in to for order updates.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Resend the invoice of an order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin or support API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Invoice not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/payments": {
+            "post": {
+                "description": "Adds a payment made outside checkout, e.g. a gift card or a deposit, to the order's payment ledger. The payment covering the rest of the total marks the order paid.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Record a payment of an order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RecordPaymentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request; retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or order ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Order does not accept the payment, concurrent update or request with the same idempotency key in progress",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency key was used with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/refund-requests": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List refund requests of an order with their audit trails",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin, support or finance API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.RefundRequest"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Records a refund request to be approved or rejected by finance. Nothing is refunded until approval.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Request a refund of an order payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateRefundRequestRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin or support API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RefundRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or order ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment, amount or returned items do not match the order, or items of an unshipped order",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/refunds": {
+            "post": {
+                "description": "Payments made through a payment provider are refunded through it, refunds of other payments are only recorded.\nRetries with the same Idempotency-Key refund once, even after the stored response expired.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Refund part of a payment of an order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund",
+                        "name": "refund",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RefundRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request; retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or order ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order or payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment disputed, amount exceeds the part of the payment not refunded yet or request with the same idempotency key in progress",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency key was used with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Payment provider rejected the refund",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/state": {
+            "get": {
+                "description": "Rebuilds the order from its event log up to the given time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get an order as it was at a point in time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time (default: now)",
+                        "name": "at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID or time",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order did not exist at that time",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/status": {
+            "post": {
+                "description": "Appends a paid, shipped, delivered or cancelled event to the order's event log.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change the status of an order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ChangeOrderStatusRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or order ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Status change not allowed or concurrent update",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/bulk": {
+            "patch": {
+                "description": "Applies price, description, age restriction, tag, status, barcode, category and attribute changes to many products in a single transaction.\nItems of unknown products or with invalid values fail and are reported; with \"atomic\" no item is applied then.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update many products at once",
+                "parameters": [
+                    {
+                        "description": "Product changes",
+                        "name": "changes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.BulkUpdateProductsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.BulkUpdateReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Changed barcode already taken, nothing applied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/bulk-operations": {
+            "post": {
+                "description": "Queues the operation for the products having all the tags of the filter and of its category: price_percent\nchanges prices by value percent (e.g. 10 or -20), price_set sets them to value, stock_adjust records a stock\nadjustment of value units (negative to remove stock; bundles are skipped). It is applied in batches in the\nbackground, per-item results are kept. With dry_run, only the number of matching products is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Apply a price or stock change to products matching tags or a category",
+                "parameters": [
+                    {
+                        "description": "Operation",
+                        "name": "operation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateBulkOperationRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run",
+                        "schema": {
+                            "$ref": "#/definitions/handler.BulkOperationPreviewResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/domain.BulkOperation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, filter or value",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/bulk-operations/{id}": {
+            "get": {
+                "description": "Processed counts the matching products processed so far: Updated and Failed add up to it.\nMatched is counted when the operation is created, products changed since may be processed or not.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get a bulk operation with its progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bulk operation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.BulkOperation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bulk operation ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Bulk operation not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/bulk-operations/{id}/results": {
+            "get": {
+                "description": "Returns the price or quantity of the processed products before and after the operation, in product ID\norder, with the reason of failed changes, e.g. not enough stock to remove.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List the per-product results of a bulk operation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bulk operation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "updated or failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of results to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.BulkOperationResultListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bulk operation ID, status, limit or offset",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Bulk operation not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/drafts/cleanup": {
+            "post": {
+                "description": "Deletes drafts older than the given age that no bundle references, oldest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete abandoned drafts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Minimum age of deleted drafts, e.g. 720h (default 30 days)",
+                        "name": "older_than",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of drafts (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.DraftCleanupResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid age or limit",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/export": {
+            "get": {
+                "description": "Streams all products, including drafts and archived products, ordered by ID as JSON lines or CSV.\nAll products are read from one database snapshot. To resume an interrupted export,\npass the ID of the last completely received product as cursor. Failed exports are aborted mid-response,\nso they are never received as complete.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Export the whole catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "jsonl (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Export products after this product ID",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin or analyst API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One product per line",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/issues": {
+            "get": {
+                "description": "Runs rule-based checks on draft and active products and returns the issues found, in product ID and rule order,\nwith the number of issues per rule. Rules: zero_price (price not positive), no_tags, negative_stock (the stock\nledger sums to a negative quantity) and inactive_component (a bundle contains a draft or archived product).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report data-quality issues of the catalog",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Rule to run, repeatable (default: every rule)",
+                        "name": "rule",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of issues (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of issues to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin or analyst API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.CatalogIssueReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown rule, invalid limit or offset",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}": {
+            "delete": {
+                "description": "Deletes a product with its stock movements and history, e.g. one created by mistake.\nProducts referenced by orders or bundles cannot be deleted, archive them instead.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Product is referenced by orders or bundles",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/history": {
+            "get": {
+                "description": "Returns edits of the product details with who made them and field-level before and after values, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the change history of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of edits (1-500, default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ProductRevision"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID or limit",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/price-at": {
+            "get": {
+                "description": "Resolves the price the product was sold at at the given time from the history of its price edits,\nwith when and by whom that price was set, to settle customer pricing complaints.\nThe catalog has a single base price per product: there are no promotional or tiered prices to resolve.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the price of a product at a point in time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time",
+                        "name": "timestamp",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin or support API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.EffectivePrice"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID or timestamp",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/status": {
+            "post": {
+                "description": "Publishes a draft, archives a product or restores an archived one.\nArchived products stay visible for order history but cannot be ordered. Products cannot go back to draft.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change the lifecycle status of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ChangeProductStatusRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or product ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Status change not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/stock-movements": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get stock movements of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of movements (1-500, default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.StockMovement"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID or limit",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Appends a receipt, adjustment or return to the stock ledger and updates the product quantity.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Record a stock movement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stock movement",
+                        "name": "movement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RecordStockMovementRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request; retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.StockMovement"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or product ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Quantity would become negative or request with the same idempotency key in progress",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency key was used with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/refund-requests": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List refund requests waiting for a decision",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of requests (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin, support or finance API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.RefundRequest"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/refund-requests/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get a refund request with its audit trail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Refund request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin, support or finance API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RefundRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Refund request not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/refund-requests/{id}/approve": {
+            "post": {
+                "description": "Refunds the payment through its payment provider and returns the returned items to stock.\nIf the refund fails, the failure is audited and the request stays pending.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Approve a refund request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Refund request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Approval comment",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.RefundDecisionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin or finance API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RefundRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or request ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Request cannot be approved by its requester",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Refund request not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Request already decided, payment disputed or amount exceeds the part of the payment not refunded yet",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Payment provider rejected the refund",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/refund-requests/{id}/reject": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reject a refund request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Refund request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rejection reason",
+                        "name": "decision",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RefundDecisionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin or finance API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RefundRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, request ID or missing note",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Request cannot be rejected by its requester",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Refund request not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Request already decided",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/settings/order-numbers": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the format of order numbers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.OrderNumberSettings"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Applies to orders created afterwards. Existing orders keep their numbers and the yearly sequence continues.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change the format of order numbers",
+                "parameters": [
+                    {
+                        "description": "Order number format",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.OrderNumberSettingsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.OrderNumberSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or format",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/stock/drift": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report products whose quantity differs from the stock ledger",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of products (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin or analyst API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.StockDriftReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/stock/drift/repair": {
+            "post": {
+                "description": "Sets the quantity of drifted products to the sum of their stock movements.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Rebuild product quantities from the stock ledger",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of products (1-1000, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.StockDriftReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/stock/import": {
+            "post": {
+                "description": "Applies a CSV file with \"sku\" and \"quantity\" columns, e.g. of the nightly warehouse sync, in batched transactions.\nSKUs are product barcodes, UPC-A codes match their EAN-13 form. In absolute mode quantities replace product quantities, in delta mode they are added.\nEvery changed quantity is recorded in the stock ledger as an adjustment. Invalid rows, unknown SKUs\nand changes to a negative quantity fail without affecting other rows, and are listed in the report.",
+                "consumes": [
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Import product quantities from CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "absolute (default) or delta",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "CSV file, e.g. sku,quantity\n4006381333931,25",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.StockImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid CSV file or mode",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, earlier batches may have been applied",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/stock/snapshots": {
+            "get": {
+                "description": "Streams the closing quantity of products per day (UTC) from the first to the last day, at most 366 days,\nwith the units received, sold (net of cancellations), returned and adjusted and the seconds out of stock\nduring the day, ordered by day and product, as CSV or JSON lines. Bundles are not snapshotted.\nDays are snapshotted shortly after they end (UTC); the current day is never included.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Export daily stock snapshots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, e.g. 2026-01-01",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, e.g. 2026-03-31",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Export only snapshots of this product",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default) or jsonl",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin or analyst API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One snapshot per line",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid days, product ID or format",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/system/config": {
+            "get": {
+                "description": "Returns the configuration this instance loaded, by environment variable name, including defaults.\nSecrets are masked as [REDACTED] and passwords in URLs as xxxxx; unset secrets are empty.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the effective configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/system/status": {
+            "get": {
+                "description": "Checks the database pools and the object storage, and reports pool statistics, queue backlogs, the heartbeats\nof the background runners and the loaded notification templates for on-call diagnosis.\nFailed checks and runners without a run for three intervals are reported with status degraded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the operational status of the application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.SystemStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tags": {
+            "get": {
+                "description": "Returns all tags in use ordered by name, with the number of products that have them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List product tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.TagCount"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tags/merge": {
+            "post": {
+                "description": "Replaces the source tags with the target tag in all products, keeping each tag once per product.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Merge tags",
+                "parameters": [
+                    {
+                        "description": "Source and target tags",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MergeTagsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.TagUpdateResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "None of the source tags found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tags/{tag}/rename": {
+            "post": {
+                "description": "Renames the tag in all products. Products that already have the new name keep it once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Rename a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Current tag name",
+                        "name": "tag",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New tag name",
+                        "name": "name",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RenameTagRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.TagUpdateResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tag not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/role": {
+            "put": {
+                "description": "Admins and managers manage the catalog, customers browse and order products.\nTokens issued with the previous role are rejected, so the user logs in again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Assign a role to a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SetRoleRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or role",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/sandbox": {
+            "put": {
+                "description": "Sandbox accounts let integration partners test checkouts end-to-end against production: their orders\ngo through the same checks, but allocate no stock and are paid with the mock payment provider, whatever\nprovider they select. Sandbox orders and their events carry \"Sandbox\": true and are left out of the\naccounting journal. Orders placed before the change keep their mode.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Mark a user as a sandbox account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sandbox mode",
+                        "name": "sandbox",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SetSandboxRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or request body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/verification-email/resend": {
+            "post": {
+                "description": "Sends a new verification link to the user's email address, the link sent before no longer works.\nVerification emails are sent regardless of the user's notification preferences.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Resend the verification email of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin or support API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email address already verified",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "List configured OIDC identity providers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/callback": {
+            "get": {
+                "description": "Verifies the ID token, links the identity to a local user and returns a JWT token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Complete login with an OIDC identity provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid state or authorization code",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "No linked account or account is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown provider",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/login": {
+            "get": {
+                "description": "Redirects the browser to the identity provider authorization page.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start login with an OIDC identity provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "404": {
+                        "description": "Unknown provider",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Identity provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery-slots": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the delivery windows of the region that have not started and have capacity left, earliest first.\nPass the ID of one as delivery_slot_id when creating an order. Availability can change until the order is placed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "List delivery slots available at checkout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Deployment region of the slots",
+                        "name": "region",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the listed window, RFC 3339 (default now)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the listed window, RFC 3339 (default 14 days after from, at most 62 days)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.DeliverySlot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing region or invalid window",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Succeeds while the process serves requests, including while it drains.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/introspect": {
+            "post": {
+                "description": "RFC 7662 token introspection for sibling services and the API gateway.\nA token is active if it is valid, not expired, not revoked and its user is active with the role of the token.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "Introspect an access token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token to introspect",
+                        "name": "token",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.IntrospectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/revoke": {
+            "post": {
+                "description": "RFC 7009 token revocation. Invalid or expired tokens are ignored.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "Revoke an access token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token to revoke",
+                        "name": "token",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Missing token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the user's orders with their items, newest first, with the number of the user's orders.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "List orders of the current user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of orders (1-100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of orders to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.OrderListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit or offset",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Reserves stock, charges the payment and confirms the order. A failed payment cancels the order.\nPayments the provider confirms later leave the order created with PendingPayment set until the provider's webhook arrives.\nOrder options (gift wrap, gift message, priority handling) are charged with their configured fees as part of the total.\nA delivery slot chosen from GET /delivery-slots has one order of its capacity reserved until the order is cancelled.\nAn order with the same products, quantities and total as an order the user placed within the last minutes is rejected\nwith 409 possible_duplicate_order and the recent order in details, nothing is charged; resubmit with confirm_duplicate to place it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Create a new order",
+                "parameters": [
+                    {
+                        "description": "Order details",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateOrderRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request; retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, product not found, quantity outside the allowed range, order option not offered or unknown payment provider",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment failed",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Buyer does not meet a product age restriction",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Product not available, insufficient stock, delivery slot full, possible duplicate order or request with the same idempotency key in progress",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency key was used with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the order with its payment ledger. Orders of other users are forbidden.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get an order of the current user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Order belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/invoice/document": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Linked from invoice emails. Redirects to a short-lived download URL of the archived invoice document,\nstorage backends without presigned URLs serve the document directly.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Download the invoice document of an order of the current user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invoice document",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Invoice"
+                        }
+                    },
+                    "302": {
+                        "description": "Redirect to the document",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Invoice not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/pay": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Charges the outstanding amount of an order awaiting payment, e.g. after its pending payment was declined.\nA declined payment leaves the order awaiting payment. Payments the provider confirms later are accepted with 202,\nthe order is paid once the provider's webhook arrives.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Pay an order of the current user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment method",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PayOrderRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request; retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.OrderResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Payment awaits confirmation by the provider",
+                        "schema": {
+                            "$ref": "#/definitions/handler.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID, request body or unknown payment provider",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment failed",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Order belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Order does not await payment, a payment awaits confirmation or request with the same idempotency key in progress",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency key was used with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a page of active products, newest first, with the number of products matching the filters.\nTags are combined: products must have all of them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "List products",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag the products must have, repeatable",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum price, inclusive",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum price, inclusive",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only products that can be ordered from stock",
+                        "name": "in_stock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of products (1-100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of products to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.ProductPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid price range, in_stock, limit or offset",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Requires the admin or manager role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Create a new product",
+                "parameters": [
+                    {
+                        "description": "Product details",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateProductRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key of the request; retries with the same key replay the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, barcode or attributes",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient role",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Barcode already taken or request with the same idempotency key in progress",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency key was used with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/by-barcode/{code}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Resolves an EAN-8, UPC-A, EAN-13 or GTIN-14 code, e.g. from a warehouse scanner. Draft products are not found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get a product by barcode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Barcode",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid barcode",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates the products of a CSV file uploaded as the \"file\" part of a multipart form, e.g. to onboard a catalog.\nThe header names the columns: description and price are required; quantity, tags (separated by \"|\"),\nage_restriction, status (draft or active), barcode, category and attributes (a JSON object) are optional.\nRows are validated as products created one by one, except that the quantity may be 0, and are created\nin batched transactions as the file is read. Invalid rows and barcodes repeated in the file or already taken\nfail without affecting other rows, and are listed in the report. If the import stops, e.g. at a malformed\nline, the error details hold the report: earlier batches stay created, and StoppedAt is the failing line.\nRequires the admin or manager role.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Import products from CSV",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file, e.g. description,price,quantity,tags,barcode\nWireless headphones,99.99,100,audio|wireless,4006381333931",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.ProductImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid form or CSV file; once rows were read, details hold the report with the failing line in StoppedAt",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient role",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Barcode taken during the import, details hold the report with the failing line in StoppedAt",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large, details hold the report of the rows read",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, details hold the report with the failing line in StoppedAt",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Draft products are not found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get a product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/products": {
+            "get": {
+                "description": "Returns a page of active products for embedding in marketing sites, without authentication.\nOnly ID, Description, Price, Tags, Category, Attributes and Status are returned, fields narrows them down.\nResponses are cacheable by browsers and CDNs and revalidated with ETag.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "List products of the public catalog",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag the products must have, repeatable",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum price, inclusive",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum price, inclusive",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only products that can be ordered from stock",
+                        "name": "in_stock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of products (1-100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of products to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated product fields to return (default: all public fields)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PublicProductPage"
+                        }
+                    },
+                    "304": {
+                        "description": "Cached response is current",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid price range, in_stock, limit, offset or fields",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service busy",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/products/{id}": {
+            "get": {
+                "description": "Returns a product for embedding in marketing sites, without authentication. Draft products are not found.\nOnly ID, Description, Price, Tags, Category, Attributes and Status are returned, fields narrows them down.\nResponses are cacheable by browsers and CDNs and revalidated with ETag.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "Get a product of the public catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated product fields to return (default: all public fields)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PublicProduct"
+                        }
+                    },
+                    "304": {
+                        "description": "Cached response is current",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID or fields",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service busy",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Fails once the server is draining before shutdown, so load balancers stop routing requests to it.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "draining",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Users": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scim"
+                ],
+                "summary": "List users (SCIM)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter, e.g. userName eq \\",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "1-based index of the first result",
+                        "name": "startIndex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.ListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scim"
+                ],
+                "summary": "Provision a user (SCIM)",
+                "parameters": [
+                    {
+                        "description": "SCIM user",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scim.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/scim.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scim"
+                ],
+                "summary": "Get a user (SCIM)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scim"
+                ],
+                "summary": "Replace a user (SCIM)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "SCIM user",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scim.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deactivates the user; order history is retained.",
+                "tags": [
+                    "scim"
+                ],
+                "summary": "Deprovision a user (SCIM)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Supports add/replace of active, userName, externalId, name and emails. Setting active=false deprovisions the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scim"
+                ],
+                "summary": "Patch a user (SCIM)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "SCIM patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scim.PatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/check-username": {
+            "get": {
+                "description": "Applies normalization rules (trimming, case folding, reserved names) and checks whether the username is free.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Check username availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username to check",
+                        "name": "username",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.UsernameAvailabilityResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing username",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/email/verify": {
+            "get": {
+                "description": "Opened from the link of a verification email. Links expire after 48 hours\nand are replaced by links sent later.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Verify an email address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token of the verification link",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.EmailVerificationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid verification link",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Verification link expired",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Log in a user",
+                "parameters": [
+                    {
+                        "description": "User credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid login or password",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Account is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Account is temporarily locked after too many failed logins",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many login attempts from the client IP",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/logout": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revokes the access token used for this request.",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Log out the current user",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get the current user's profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the account with its consents, preferences, login history and notifications.\nAccounts of users who placed orders are kept, as orders are kept for accounting.",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete the current user's account",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "User has orders",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the fields present in the request, omitted fields are kept. Users must stay at least 18 years old.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update the current user's profile",
+                "parameters": [
+                    {
+                        "description": "Profile fields to change",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, validation error or user would be under 18",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/consents": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "consents"
+                ],
+                "summary": "Get consent history of the current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Consent"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "consents"
+                ],
+                "summary": "Accept a legal document version",
+                "parameters": [
+                    {
+                        "description": "Accepted document version",
+                        "name": "consent",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AcceptDocumentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Consent"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or unknown document version",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/locale": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Emails are rendered from the template versions of the locale or its language, with amounts and dates\nformatted in the locale. Orders keep the locale they were placed with, so do their invoices.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Set the locale of the user's emails and documents",
+                "parameters": [
+                    {
+                        "description": "Locale, e.g. de-AT",
+                        "name": "locale",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SetLocaleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or unsupported locale",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/login-history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get login history of the current user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries (1-100, default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.LoginAttempt"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns notifications delivered to the in-app inbox, newest first, with the number of unread ones.\nUsers receive them for the categories they opted in to on the in_app channel.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List notifications of the current user",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of notifications (1-100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of notifications to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.InboxPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid unread, limit or offset",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notifications/read-all": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Mark all notifications of the current user as read",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notifications/unread-count": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Count unread notifications of the current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.UnreadCountResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notifications/{id}/read": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Mark a notification of the current user as read",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid notification ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Notification not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/orders/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns all of the user's orders with their items, newest first, as CSV (one row per item) or JSON.\nExports are generated at most once per ORDER_EXPORT_INTERVAL, repeated requests get the last export.\nExports of users with many orders are generated in the background: the request is accepted with 202\nand Retry-After until the export is ready. Ready exports redirect to a short-lived download URL,\nstorage backends without presigned URLs serve the export directly.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Export the order history of the current user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (default) or json",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order export",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "202": {
+                        "description": "Export is being generated, retry after the Retry-After seconds",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "302": {
+                        "description": "Redirect to the export",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/password": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Verifies the current password before setting the new one. Tokens issued before the change,\nincluding the one of this request, are rejected afterwards; the response carries a new token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Change the current user's password",
+                "parameters": [
+                    {
+                        "description": "Current and new password",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, validation error or new password equals the current one",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Current password is wrong",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/phone": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sends a verification code by SMS. The number receives notifications once verified,\nthe current number is kept until then. Opt in to SMS in the notification preferences.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Set the phone number of the current user",
+                "parameters": [
+                    {
+                        "description": "Phone number",
+                        "name": "phone",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SetPhoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PhoneVerificationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid phone number or unsupported country",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "A code was sent less than a minute ago",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "The user receives no more SMS notifications. A pending verification is cancelled.",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Remove the phone number of the current user",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/phone/verify": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Checks the code sent by SMS and stores the number. Codes expire after 10 minutes or 5 wrong attempts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Verify the phone number of the current user",
+                "parameters": [
+                    {
+                        "description": "Verification code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.VerifyPhoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PhoneResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or wrong code",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No pending verification",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Code expired",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns per-channel opt-ins for each notification category (category -\u003e channel -\u003e enabled).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get notification preferences of the current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "object",
+                                "additionalProperties": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts a partial map (category -\u003e channel -\u003e enabled); omitted entries keep their current values.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update notification preferences of the current user",
+                "parameters": [
+                    {
+                        "description": "Notification preferences",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "object",
+                                "additionalProperties": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "object",
+                                "additionalProperties": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or unknown category/channel",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/register": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User registration details",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, validation error, invalid username, unsupported locale or user is under 18",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "User with this email or username already exists",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Latest legal documents must be accepted, details list them",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many registration attempts from the client IP",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/payments/{provider}": {
+            "post": {
+                "description": "Authenticated by the provider's signature headers. Disputes and chargebacks flag the order,\nfreeze refunds of the disputed payment and notify admins. Capture confirmations pay orders awaiting a pending\npayment, or let them be paid again if it was declined. Other events are acknowledged and ignored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Receive a payment provider webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment provider, e.g. paypal",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Invalid webhook signature",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown payment provider",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Body too large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.Announcement": {
+            "type": "object",
+            "properties": {
+                "CompletedAt": {
+                    "description": "Set once every user of the segment was processed",
+                    "type": "string"
+                },
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "Cursor": {
+                    "description": "Last processed user ID, nil before the first batch",
+                    "type": "string"
+                },
+                "Data": {
+                    "description": "Template variables, besides those of the recipient",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "Segment": {
+                    "$ref": "#/definitions/domain.AudienceSegment"
+                },
+                "Stats": {
+                    "$ref": "#/definitions/domain.AnnouncementStats"
+                },
+                "Status": {
+                    "type": "string"
+                },
+                "Template": {
+                    "description": "Notification template of the message",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.AnnouncementStats": {
+            "type": "object",
+            "properties": {
+                "Failed": {
+                    "description": "Users whose delivery failed on at least one channel",
+                    "type": "integer"
+                },
+                "OptedOut": {
+                    "description": "Users who opted out of marketing notifications on every channel, they are not sent anything",
+                    "type": "integer"
+                },
+                "Recipients": {
+                    "description": "Users of the segment processed so far",
+                    "type": "integer"
+                },
+                "Sent": {
+                    "description": "Users the announcement was delivered to on every channel they opted in to",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.AttributeDefinition": {
+            "type": "object",
+            "properties": {
+                "AllowedValues": {
+                    "description": "Values of enum attributes",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "Category": {
+                    "type": "string"
+                },
+                "Name": {
+                    "type": "string"
+                },
+                "Required": {
+                    "type": "boolean"
+                },
+                "Type": {
+                    "type": "string"
+                },
+                "Unit": {
+                    "description": "Unit of number and integer values, e.g. \"in\" or \"kg\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.AudienceSegment": {
+            "type": "object",
+            "properties": {
+                "ActiveSince": {
+                    "description": "Users who logged in at or after this time",
+                    "type": "string"
+                },
+                "InactiveSince": {
+                    "description": "Users who have not logged in since this time, including those who never did",
+                    "type": "string"
+                },
+                "RegisteredAfter": {
+                    "description": "Users registered at or after this time",
+                    "type": "string"
+                },
+                "RegisteredBefore": {
+                    "description": "Users registered before this time",
+                    "type": "string"
+                },
+                "Roles": {
+                    "description": "Customer groups: users with any of these roles",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "domain.BulkOperation": {
+            "type": "object",
+            "properties": {
+                "Actor": {
+                    "description": "Caller who created the operation, recorded in the product history",
+                    "type": "string"
+                },
+                "Attempts": {
+                    "description": "Failed attempts of the next batch",
+                    "type": "integer"
+                },
+                "CompletedAt": {
+                    "description": "Set once every matching product was processed",
+                    "type": "string"
+                },
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "Cursor": {
+                    "description": "Last processed product ID, nil before the first batch",
+                    "type": "string"
+                },
+                "Error": {
+                    "description": "Error of the last failed batch",
+                    "type": "string"
+                },
+                "Failed": {
+                    "type": "integer"
+                },
+                "Filter": {
+                    "$ref": "#/definitions/domain.BulkOperationFilter"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "Kind": {
+                    "description": "price_percent, price_set or stock_adjust",
+                    "type": "string"
+                },
+                "Matched": {
+                    "description": "Products matching the filter when the operation was created",
+                    "type": "integer"
+                },
+                "Processed": {
+                    "description": "Products processed so far: Updated and Failed add up to it",
+                    "type": "integer"
+                },
+                "Status": {
+                    "type": "string"
+                },
+                "Updated": {
+                    "type": "integer"
+                },
+                "Value": {
+                    "description": "Percent, price or units, depending on the kind",
+                    "type": "number"
+                }
+            }
+        },
+        "domain.BulkOperationFilter": {
+            "type": "object",
+            "properties": {
+                "Category": {
+                    "description": "Products of this category",
+                    "type": "string"
+                },
+                "Tags": {
+                    "description": "Products having all of these tags",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "domain.BulkOperationResult": {
+            "type": "object",
+            "properties": {
+                "After": {
+                    "description": "Price or quantity after the operation, Before if it failed",
+                    "type": "number"
+                },
+                "Before": {
+                    "description": "Price or quantity before the operation",
+                    "type": "number"
+                },
+                "Error": {
+                    "description": "Reason of the failure",
+                    "type": "string"
+                },
+                "ProductID": {
+                    "type": "string"
+                },
+                "Status": {
+                    "description": "updated or failed",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BundleComponent": {
+            "type": "object",
+            "properties": {
+                "ProductID": {
+                    "type": "string"
+                },
+                "Quantity": {
+                    "description": "Number of component items in one bundle",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.CatalogIssue": {
+            "type": "object",
+            "properties": {
+                "Description": {
+                    "description": "Product description, to recognize the product",
+                    "type": "string"
+                },
+                "Detail": {
+                    "description": "E.g. the ledger quantity or the inactive components",
+                    "type": "string"
+                },
+                "ProductID": {
+                    "type": "string"
+                },
+                "Rule": {
+                    "type": "string"
+                },
+                "Status": {
+                    "description": "draft or active",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Change": {
+            "type": "object",
+            "properties": {
+                "ChangedAt": {
+                    "type": "string"
+                },
+                "Data": {
+                    "description": "Database row after the change without credentials, null for deletes",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "Entity": {
+                    "type": "string"
+                },
+                "EntityID": {
+                    "type": "string"
+                },
+                "Operation": {
+                    "description": "insert, update or delete",
+                    "type": "string"
+                },
+                "Sequence": {
+                    "description": "Identifies the change, not assigned in feed order",
+                    "type": "integer",
+                    "format": "int64"
+                }
+            }
+        },
+        "domain.Consent": {
+            "type": "object",
+            "properties": {
+                "AcceptedAt": {
+                    "type": "string"
+                },
+                "DocumentType": {
+                    "type": "string"
+                },
+                "DocumentVersion": {
+                    "type": "string"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "UserID": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.DeliverySlot": {
+            "type": "object",
+            "properties": {
+                "Capacity": {
+                    "description": "Orders deliverable in the window",
+                    "type": "integer",
+                    "example": 20
+                },
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "EndsAt": {
+                    "type": "string",
+                    "example": "2026-03-14T12:00:00Z"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "Region": {
+                    "description": "Deployment region taking the slot's orders, see OrderSettings.Region",
+                    "type": "string",
+                    "example": "eu-west-1"
+                },
+                "Reserved": {
+                    "description": "Orders of the window that are not cancelled",
+                    "type": "integer",
+                    "example": 7
+                },
+                "StartsAt": {
+                    "type": "string",
+                    "example": "2026-03-14T09:00:00Z"
+                }
+            }
+        },
+        "domain.Dispute": {
+            "type": "object",
+            "properties": {
+                "Amount": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "OrderID": {
+                    "type": "string"
+                },
+                "PaymentID": {
+                    "description": "Disputed charge",
+                    "type": "string"
+                },
+                "Provider": {
+                    "type": "string"
+                },
+                "ProviderDisputeID": {
+                    "type": "string"
+                },
+                "Reason": {
+                    "description": "Provider reason code",
+                    "type": "string"
+                },
+                "Status": {
+                    "type": "string"
+                },
+                "UpdatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.EffectivePrice": {
+            "type": "object",
+            "properties": {
+                "At": {
+                    "type": "string"
+                },
+                "Price": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "ProductID": {
+                    "type": "string"
+                },
+                "SetAt": {
+                    "description": "When the price was set, not set for the price the product was created with",
+                    "type": "string"
+                },
+                "SetBy": {
+                    "description": "Who set the price",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FieldDiff": {
+            "type": "object",
+            "properties": {
+                "After": {},
+                "Before": {},
+                "Field": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.InboxNotification": {
+            "type": "object",
+            "properties": {
+                "Body": {
+                    "type": "string"
+                },
+                "Category": {
+                    "description": "One of NotificationCategory* values",
+                    "type": "string"
+                },
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "ReadAt": {
+                    "description": "Nil until the user reads the notification",
+                    "type": "string"
+                },
+                "Subject": {
+                    "type": "string"
+                },
+                "UserID": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Invoice": {
+            "type": "object",
+            "properties": {
+                "Amount": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "Currency": {
+                    "description": "ISO 4217 code",
+                    "type": "string"
+                },
+                "FiscalYear": {
+                    "type": "integer"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "IssuedAt": {
+                    "type": "string"
+                },
+                "Locale": {
+                    "description": "Locale of the order, empty for invoices of orders without recorded settings",
+                    "type": "string"
+                },
+                "Number": {
+                    "description": "e.g. INV-2024-000001",
+                    "type": "string"
+                },
+                "OrderID": {
+                    "type": "string"
+                },
+                "Sequence": {
+                    "description": "Position in the fiscal year, starting at 1",
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "TaxRegion": {
+                    "description": "Tax region of the order",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.LegalDocument": {
+            "type": "object",
+            "properties": {
+                "ID": {
+                    "type": "string"
+                },
+                "PublishedAt": {
+                    "type": "string"
+                },
+                "Type": {
+                    "type": "string"
+                },
+                "Version": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.LoginAttempt": {
+            "type": "object",
+            "properties": {
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "FailureReason": {
+                    "description": "Empty for successful attempts",
+                    "type": "string"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "IPAddress": {
+                    "type": "string"
+                },
+                "Login": {
+                    "description": "Email or username used for the attempt",
+                    "type": "string"
+                },
+                "Success": {
+                    "type": "boolean"
+                },
+                "UserAgent": {
+                    "type": "string"
+                },
+                "UserID": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.NotificationTemplate": {
+            "type": "object",
+            "properties": {
+                "Body": {
+                    "type": "string"
+                },
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "CreatedBy": {
+                    "description": "e.g. \"client:admin\", empty for built-in templates",
+                    "type": "string"
+                },
+                "Locale": {
+                    "description": "BCP 47 tag of the language or locale, empty for the default sent to other locales",
+                    "type": "string",
+                    "example": "de"
+                },
+                "Name": {
+                    "description": "e.g. order_confirmation",
+                    "type": "string"
+                },
+                "Subject": {
+                    "type": "string"
+                },
+                "Version": {
+                    "description": "Starting at 1 in each locale, 0 for built-in templates",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.OrderDelivery": {
+            "type": "object",
+            "properties": {
+                "EndsAt": {
+                    "type": "string",
+                    "example": "2026-03-14T12:00:00Z"
+                },
+                "Region": {
+                    "type": "string",
+                    "example": "eu-west-1"
+                },
+                "SlotID": {
+                    "type": "string"
+                },
+                "StartsAt": {
+                    "type": "string",
+                    "example": "2026-03-14T09:00:00Z"
+                }
+            }
+        },
+        "domain.OrderEvent": {
+            "type": "object",
+            "properties": {
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "Delivery": {
+                    "description": "Set for created events of orders with a delivery window",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.OrderDelivery"
+                        }
+                    ]
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "Item": {
+                    "description": "Set for item_added events",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.OrderItem"
+                        }
+                    ]
+                },
+                "Number": {
+                    "description": "Order number, set for created events",
+                    "type": "string"
+                },
+                "OrderID": {
+                    "type": "string"
+                },
+                "PaymentID": {
+                    "description": "Set for paid events charged through the payment gateway and for payment_pending and payment_failed events",
+                    "type": "string"
+                },
+                "Region": {
+                    "description": "Deployment region of the instance that recorded the event, empty if not configured",
+                    "type": "string"
+                },
+                "Sequence": {
+                    "description": "Position in the order's event log, starting at 1",
+                    "type": "integer"
+                },
+                "Settings": {
+                    "description": "Set for created events of orders with recorded settings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.OrderSettings"
+                        }
+                    ]
+                },
+                "Type": {
+                    "type": "string"
+                },
+                "UserID": {
+                    "description": "Set for created events",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.OrderItem": {
+            "type": "object",
+            "properties": {
+                "Bundle": {
+                    "description": "Bundle line, stock is allocated by its component lines",
+                    "type": "boolean"
+                },
+                "BundleItemID": {
+                    "description": "Bundle line the component line belongs to",
+                    "type": "string"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "PriceAtPurchase": {
+                    "description": "Price at time of purchase",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "ProductID": {
+                    "type": "string"
+                },
+                "Quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.OrderNumberSettings": {
+            "type": "object",
+            "properties": {
+                "Padding": {
+                    "description": "Minimum number of digits of the sequence number",
+                    "type": "integer"
+                },
+                "Prefix": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.OrderOption": {
+            "type": "object",
+            "properties": {
+                "Fee": {
+                    "type": "number",
+                    "format": "float64",
+                    "example": 4.99
+                },
+                "Message": {
+                    "description": "Gift message, set for gift_message options",
+                    "type": "string",
+                    "example": "Happy birthday!"
+                },
+                "Option": {
+                    "type": "string",
+                    "example": "gift_wrap"
+                }
+            }
+        },
+        "domain.OrderSettings": {
+            "type": "object",
+            "properties": {
+                "Currency": {
+                    "description": "ISO 4217 code of the order amounts",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "Locale": {
+                    "description": "Locale amounts of the order are formatted in",
+                    "type": "string",
+                    "example": "en-US"
+                },
+                "Region": {
+                    "description": "Deployment region the order was placed in, empty if not configured",
+                    "type": "string",
+                    "example": "eu-west-1"
+                },
+                "Sandbox": {
+                    "description": "Placed by a sandbox account: no stock was allocated, payments went to the mock provider",
+                    "type": "boolean"
+                },
+                "TaxRegion": {
+                    "description": "Tax region the order was placed in, empty if not configured",
+                    "type": "string",
+                    "example": "US-CA"
+                }
+            }
+        },
+        "domain.OrderTotalMismatch": {
+            "type": "object",
+            "properties": {
+                "ComputedTotal": {
+                    "description": "Sum of item price × quantity",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "OrderID": {
+                    "type": "string"
+                },
+                "StoredTotal": {
+                    "type": "number",
+                    "format": "float64"
+                }
+            }
+        },
+        "domain.Payment": {
+            "type": "object",
+            "properties": {
+                "Amount": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "Kind": {
+                    "description": "charge or refund",
+                    "type": "string"
+                },
+                "Metadata": {
+                    "description": "Provider-specific details, e.g. the PayPal order ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "Method": {
+                    "description": "Method of the charge, refunds go back the same way",
+                    "type": "string"
+                },
+                "OrderID": {
+                    "type": "string"
+                },
+                "Provider": {
+                    "description": "Payment provider the charge went through, empty for payments made outside checkout",
+                    "type": "string"
+                },
+                "Reference": {
+                    "description": "Provider capture or refund ID, gift card code, empty if none",
+                    "type": "string"
+                },
+                "RefundOf": {
+                    "description": "Refunded charge, set for refunds",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Product": {
+            "type": "object",
+            "properties": {
+                "AgeRestriction": {
+                    "description": "Minimum buyer age, 0 if not restricted",
+                    "type": "integer"
+                },
+                "Attributes": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "Barcode": {
+                    "description": "EAN-8, EAN-13 or GTIN-14, empty if not set",
+                    "type": "string"
+                },
+                "Category": {
+                    "description": "Category whose attribute definitions apply, empty if not set",
+                    "type": "string"
+                },
+                "Components": {
+                    "description": "Products the bundle consists of, empty for regular products",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.BundleComponent"
+                    }
+                },
+                "Description": {
+                    "type": "string"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "Price": {
+                    "description": "Product price",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "Quantity": {
+                    "description": "Product quantity in stock",
+                    "type": "integer"
+                },
+                "Status": {
+                    "description": "draft, active or archived",
+                    "type": "string"
+                },
+                "Tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "domain.ProductRevision": {
+            "type": "object",
+            "properties": {
+                "Actor": {
+                    "description": "Who made the edit, e.g. \"user:\u003cid\u003e\" or \"client:\u003cname\u003e\"",
+                    "type": "string"
+                },
+                "ChangedAt": {
+                    "type": "string"
+                },
+                "Changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldDiff"
+                    }
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "ProductID": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.QuotaUsage": {
+            "type": "object",
+            "properties": {
+                "Limit": {
+                    "type": "integer"
+                },
+                "Period": {
+                    "type": "string"
+                },
+                "ResetAt": {
+                    "description": "End of the current window",
+                    "type": "string"
+                },
+                "SoftLimit": {
+                    "description": "SoftLimit is the number of calls after which the client is warned that the quota is running out,\n0 if the client is not warned",
+                    "type": "integer"
+                },
+                "Used": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.RefundItem": {
+            "type": "object",
+            "properties": {
+                "ItemID": {
+                    "type": "string"
+                },
+                "Quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.RefundRequest": {
+            "type": "object",
+            "properties": {
+                "Amount": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "DecidedAt": {
+                    "description": "Set once approved or rejected",
+                    "type": "string"
+                },
+                "DecidedBy": {
+                    "description": "Set once approved or rejected",
+                    "type": "string"
+                },
+                "Events": {
+                    "description": "Audit trail, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RefundRequestEvent"
+                    }
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "Items": {
+                    "description": "Returned order lines, empty for refunds without a return",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RefundItem"
+                    }
+                },
+                "OrderID": {
+                    "type": "string"
+                },
+                "PaymentID": {
+                    "description": "Charge to refund",
+                    "type": "string"
+                },
+                "Reason": {
+                    "type": "string"
+                },
+                "RefundID": {
+                    "description": "Refund in the payment ledger, set once approved",
+                    "type": "string"
+                },
+                "RequestedBy": {
+                    "description": "e.g. \"user:\u003cid\u003e\" or \"client:\u003cname\u003e\"",
+                    "type": "string"
+                },
+                "Status": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.RefundRequestEvent": {
+            "type": "object",
+            "properties": {
+                "Action": {
+                    "type": "string"
+                },
+                "Actor": {
+                    "type": "string"
+                },
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "Note": {
+                    "description": "Reason of the request, decision comment or refund failure",
+                    "type": "string"
+                },
+                "RequestID": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.StockDrift": {
+            "type": "object",
+            "properties": {
+                "CachedQuantity": {
+                    "description": "products.quantity",
+                    "type": "integer"
+                },
+                "LedgerQuantity": {
+                    "description": "Sum of stock movements",
+                    "type": "integer"
+                },
+                "ProductID": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.StockMovement": {
+            "type": "object",
+            "properties": {
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "Delta": {
+                    "description": "Positive for incoming stock, negative for outgoing",
+                    "type": "integer"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "Note": {
+                    "type": "string"
+                },
+                "OrderID": {
+                    "description": "Set for allocations, releases and returns",
+                    "type": "string"
+                },
+                "ProductID": {
+                    "type": "string"
+                },
+                "Reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TagCount": {
+            "type": "object",
+            "properties": {
+                "Name": {
+                    "type": "string"
+                },
+                "Products": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.User": {
+            "type": "object",
+            "properties": {
+                "Birthdate": {
+                    "description": "Date of birth (time part is ignored)",
+                    "type": "string"
+                },
+                "Email": {
+                    "type": "string"
+                },
+                "EmailVerifiedAt": {
+                    "description": "Time the email address was verified, nil until the user opens a verification link",
+                    "type": "string"
+                },
+                "ExternalID": {
+                    "description": "Identifier assigned by an external identity provider (SCIM), empty if not provisioned",
+                    "type": "string"
+                },
+                "FailedLogins": {
+                    "description": "Consecutive failed password logins since the last successful login or lockout",
+                    "type": "integer"
+                },
+                "Firstname": {
+                    "type": "string"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "IsActive": {
+                    "description": "Inactive (deprovisioned) users cannot log in",
+                    "type": "boolean"
+                },
+                "IsMarried": {
+                    "type": "boolean"
+                },
+                "LastLoginAt": {
+                    "description": "Time of the last successful login, nil if the user never logged in",
+                    "type": "string"
+                },
+                "Lastname": {
+                    "type": "string"
+                },
+                "Locale": {
+                    "description": "Preferred BCP 47 locale of emails and documents, e.g. \"de-AT\", empty for the configured locale",
+                    "type": "string"
+                },
+                "LockedUntil": {
+                    "description": "End of the last lockout after too many failed logins, nil if the account was never locked",
+                    "type": "string"
+                },
+                "PasswordChangedAt": {
+                    "description": "Time the user last changed their password, nil if it was never changed",
+                    "type": "string"
+                },
+                "Phone": {
+                    "description": "Phone number in E.164 format, empty if not set",
+                    "type": "string"
+                },
+                "PhoneVerifiedAt": {
+                    "description": "Time the phone number was verified, nil until the user enters the code sent to it",
+                    "type": "string"
+                },
+                "Role": {
+                    "description": "admin, manager or customer",
+                    "type": "string"
+                },
+                "Sandbox": {
+                    "description": "Test account of an integration partner, its orders allocate no stock and are paid with the mock provider",
+                    "type": "boolean"
+                },
+                "Username": {
+                    "description": "Optional normalized username, empty if not set",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.AcceptDocumentRequest": {
+            "type": "object",
+            "required": [
+                "type",
+                "version"
+            ],
+            "properties": {
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "terms",
+                        "privacy"
+                    ],
+                    "example": "terms"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "2024-06-01"
+                }
+            }
+        },
+        "handler.AnnouncementListResponse": {
+            "type": "object",
+            "properties": {
+                "Announcements": {
+                    "description": "Newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Announcement"
+                    }
+                },
+                "Total": {
+                    "description": "Number of announcements",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.AudienceSegmentRequest": {
+            "type": "object",
+            "properties": {
+                "active_since": {
+                    "description": "Users who logged in at or after this time",
+                    "type": "string"
+                },
+                "inactive_since": {
+                    "description": "Users who have not logged in since this time, or never did",
+                    "type": "string"
+                },
+                "registered_after": {
+                    "description": "Users registered at or after this time",
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "registered_before": {
+                    "description": "Users registered before this time",
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "roles": {
+                    "description": "Customer groups",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "customer"
+                    ]
+                }
+            }
+        },
+        "handler.BulkOperationFilterRequest": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "shoes"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "summer"
+                    ]
+                }
+            }
+        },
+        "handler.BulkOperationPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "Matched": {
+                    "description": "Products matching the filter; stock adjustments skip bundles",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.BulkOperationResultListResponse": {
+            "type": "object",
+            "properties": {
+                "Results": {
+                    "description": "In product ID order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.BulkOperationResult"
+                    }
+                },
+                "Total": {
+                    "description": "Number of results, of the requested status if one was given",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.BulkUpdateProductsRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "atomic": {
+                    "description": "Apply nothing if any item fails",
+                    "type": "boolean",
+                    "example": false
+                },
+                "items": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/handler.ProductChangeRequest"
+                    }
+                }
+            }
+        },
+        "handler.BundleComponentRequest": {
+            "type": "object",
+            "required": [
+                "product_id",
+                "quantity"
+            ],
+            "properties": {
+                "product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "description": "Number of items in one bundle",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "handler.ChangeOrderStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "paid",
+                        "shipped",
+                        "delivered",
+                        "cancelled"
+                    ],
+                    "example": "paid"
+                }
+            }
+        },
+        "handler.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "new_password": {
+                    "type": "string",
+                    "minLength": 8,
+                    "example": "correct-horse-battery"
+                }
+            }
+        },
+        "handler.ChangeProductStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "active",
+                        "archived"
+                    ],
+                    "example": "archived"
+                }
+            }
+        },
+        "handler.CreateAnnouncementRequest": {
+            "type": "object",
+            "required": [
+                "data"
+            ],
+            "properties": {
+                "data": {
+                    "description": "Headline and Message of the announcement template",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "segment": {
+                    "$ref": "#/definitions/handler.AudienceSegmentRequest"
+                }
+            }
+        },
+        "handler.CreateBulkOperationRequest": {
+            "type": "object",
+            "required": [
+                "kind"
+            ],
+            "properties": {
+                "dry_run": {
+                    "description": "Only count the matching products, do not queue the operation",
+                    "type": "boolean"
+                },
+                "filter": {
+                    "$ref": "#/definitions/handler.BulkOperationFilterRequest"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "price_percent",
+                        "price_set",
+                        "stock_adjust"
+                    ],
+                    "example": "price_percent"
+                },
+                "value": {
+                    "description": "Percent for price_percent, price for price_set, units for stock_adjust",
+                    "type": "number",
+                    "example": 10
+                }
+            }
+        },
+        "handler.CreateBundleRequest": {
+            "type": "object",
+            "required": [
+                "components",
+                "description",
+                "price"
+            ],
+            "properties": {
+                "age_restriction": {
+                    "type": "integer",
+                    "maximum": 99,
+                    "minimum": 0,
+                    "example": 0
+                },
+                "attributes": {
+                    "type": "object"
+                },
+                "barcode": {
+                    "type": "string",
+                    "maxLength": 14,
+                    "example": "4006381333931"
+                },
+                "category": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "home-cinema"
+                },
+                "components": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/handler.BundleComponentRequest"
+                    }
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Home cinema set"
+                },
+                "price": {
+                    "description": "Price of the whole bundle",
+                    "type": "number",
+                    "example": 499.99
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "active"
+                    ],
+                    "example": "draft"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "audio",
+                        "bundle"
+                    ]
+                }
+            }
+        },
+        "handler.CreateDeliverySlotRequest": {
+            "type": "object",
+            "required": [
+                "ends_at",
+                "region",
+                "starts_at"
+            ],
+            "properties": {
+                "capacity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 20
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2026-03-14T12:00:00Z"
+                },
+                "region": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "eu-west-1"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2026-03-14T09:00:00Z"
+                }
+            }
+        },
+        "handler.CreateOrderRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "confirm_duplicate": {
+                    "description": "Place the order even if it repeats a recent order of the user",
+                    "type": "boolean",
+                    "example": false
+                },
+                "delivery_slot_id": {
+                    "description": "Delivery window from GET /delivery-slots, none if omitted",
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/handler.OrderItemInput"
+                    }
+                },
+                "options": {
+                    "$ref": "#/definitions/handler.OrderOptionsRequest"
+                },
+                "payment_provider": {
+                    "description": "Payment provider, default if empty",
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "paypal"
+                },
+                "payment_token": {
+                    "description": "Provider token of the buyer's payment method, e.g. a PayPal vault ID",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "handler.CreateProductRequest": {
+            "type": "object",
+            "required": [
+                "description",
+                "price",
+                "quantity",
+                "tags"
+            ],
+            "properties": {
+                "age_restriction": {
+                    "description": "Minimum buyer age, 0 if not restricted",
+                    "type": "integer",
+                    "maximum": 99,
+                    "minimum": 0,
+                    "example": 18
+                },
+                "attributes": {
+                    "description": "Values typed by the attribute definitions of the category",
+                    "type": "object"
+                },
+                "barcode": {
+                    "description": "EAN-8, UPC-A, EAN-13 or GTIN-14",
+                    "type": "string",
+                    "maxLength": 14,
+                    "example": "4006381333931"
+                },
+                "category": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "headphones"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "High-quality wireless headphones"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 99.99
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "status": {
+                    "description": "Drafts are invisible to the storefront, defaults to active",
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "active"
+                    ],
+                    "example": "active"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "audio",
+                        "electronics",
+                        "wireless"
+                    ]
+                }
+            }
+        },
+        "handler.CreateRefundRequestRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "payment_id",
+                "reason"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 5
+                },
+                "items": {
+                    "description": "Returned order lines of shipped or delivered orders, restocked on approval",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/handler.RefundItemRequest"
+                    }
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Damaged in transit"
+                }
+            }
+        },
+        "handler.DefineAttributeRequest": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "allowed_values": {
+                    "description": "Required for enum attributes",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "cotton",
+                        "wool"
+                    ]
+                },
+                "required": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "string",
+                        "number",
+                        "integer",
+                        "boolean",
+                        "enum"
+                    ],
+                    "example": "number"
+                },
+                "unit": {
+                    "description": "Unit of numeric values",
+                    "type": "string",
+                    "maxLength": 20,
+                    "example": "in"
+                }
+            }
+        },
+        "handler.DraftCleanupResponse": {
+            "type": "object",
+            "properties": {
+                "Count": {
+                    "type": "integer"
+                },
+                "Deleted": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.EmailVerificationResponse": {
+            "type": "object",
+            "properties": {
+                "Email": {
+                    "type": "string",
+                    "example": "jane.doe@example.com"
+                },
+                "VerifiedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "insufficient_stock"
+                },
+                "details": {
+                    "description": "Additional information depending on the code, null if there is none"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "insufficient stock"
+                },
+                "request_id": {
+                    "description": "ID of the request in the server logs",
+                    "type": "string",
+                    "example": "api-7d9f/kqXoHnL5Nv-000042"
+                }
+            }
+        },
+        "handler.IntrospectionResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "exp": {
+                    "type": "integer",
+                    "example": 1717315200
+                },
+                "iat": {
+                    "type": "integer",
+                    "example": 1717228800
+                },
+                "jti": {
+                    "type": "string",
+                    "example": "9b2e4f6a-1c3d-4e5f-8a7b-6c5d4e3f2a1b"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "customer"
+                    ]
+                },
+                "sub": {
+                    "description": "User ID",
+                    "type": "string",
+                    "example": "3f1c2a8e-8d4b-4c1e-9a57-2b6d1c7e9f10"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "handler.InvoiceResponse": {
+            "type": "object",
+            "properties": {
+                "Amount": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "Currency": {
+                    "description": "ISO 4217 code",
+                    "type": "string"
+                },
+                "FiscalYear": {
+                    "type": "integer"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "IssuedAt": {
+                    "type": "string"
+                },
+                "Locale": {
+                    "description": "Locale of the order, empty for invoices of orders without recorded settings",
+                    "type": "string"
+                },
+                "Number": {
+                    "description": "e.g. INV-2024-000001",
+                    "type": "string"
+                },
+                "OrderID": {
+                    "type": "string"
+                },
+                "Sequence": {
+                    "description": "Position in the fiscal year, starting at 1",
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "TaxRegion": {
+                    "description": "Tax region of the order",
+                    "type": "string"
+                },
+                "Total": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
+        "handler.JWKSResponse": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "description": "Empty if tokens are signed with the shared secret",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.JWK"
+                    }
+                }
+            }
+        },
+        "handler.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john.doe"
+                }
+            }
+        },
+        "handler.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "handler.MergeTagsRequest": {
+            "type": "object",
+            "required": [
+                "sources",
+                "target"
+            ],
+            "properties": {
+                "sources": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "headphones",
+                        "speakers"
+                    ]
+                },
+                "target": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "audio"
+                }
+            }
+        },
+        "handler.NotificationTemplateResponse": {
+            "type": "object",
+            "properties": {
+                "Body": {
+                    "type": "string"
+                },
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "CreatedBy": {
+                    "description": "e.g. \"client:admin\", empty for built-in templates",
+                    "type": "string"
+                },
+                "Locale": {
+                    "description": "BCP 47 tag of the language or locale, empty for the default sent to other locales",
+                    "type": "string",
+                    "example": "de"
+                },
+                "Name": {
+                    "description": "e.g. order_confirmation",
+                    "type": "string"
+                },
+                "Subject": {
+                    "type": "string"
+                },
+                "Variables": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Email",
+                        "FirstName",
+                        "LastName",
+                        "OrderNumber",
+                        "Total"
+                    ]
+                },
+                "Version": {
+                    "description": "Starting at 1 in each locale, 0 for built-in templates",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.OrderItemInput": {
+            "type": "object",
+            "required": [
+                "product_id",
+                "quantity"
+            ],
+            "properties": {
+                "product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.OrderListResponse": {
+            "type": "object",
+            "properties": {
+                "Orders": {
+                    "description": "Newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.OrderSummaryResponse"
+                    }
+                },
+                "Total": {
+                    "description": "Number of the user's orders",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.OrderNumberSettingsRequest": {
+            "type": "object",
+            "required": [
+                "padding",
+                "prefix"
+            ],
+            "properties": {
+                "padding": {
+                    "description": "Minimum number of digits of the yearly sequence number",
+                    "type": "integer",
+                    "maximum": 12,
+                    "minimum": 1,
+                    "example": 6
+                },
+                "prefix": {
+                    "description": "Uppercase letters and digits, starting with a letter",
+                    "type": "string",
+                    "maxLength": 16,
+                    "example": "ORD"
+                }
+            }
+        },
+        "handler.OrderOptionsRequest": {
+            "type": "object",
+            "properties": {
+                "gift_message": {
+                    "description": "Printed on a gift card in the parcel, empty for none",
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Happy birthday!"
+                },
+                "gift_wrap": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "priority_handling": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "handler.OrderResponse": {
+            "type": "object",
+            "properties": {
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "Currency": {
+                    "description": "ISO 4217 code of the order amounts",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "Delivery": {
+                    "description": "Delivery window chosen at checkout, nil if none was chosen",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.OrderDelivery"
+                        }
+                    ]
+                },
+                "DisputeStatus": {
+                    "description": "open, won or lost if a payment of the order is disputed, empty otherwise",
+                    "type": "string",
+                    "example": "open"
+                },
+                "Disputes": {
+                    "description": "Disputes of the charges, refunds of disputed charges are frozen",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Dispute"
+                    }
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "Items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.OrderItem"
+                    }
+                },
+                "Locale": {
+                    "description": "Locale amounts of the order are formatted in",
+                    "type": "string",
+                    "example": "en-US"
+                },
+                "Number": {
+                    "description": "Human-friendly sequential number, e.g. ORD-2024-000123",
+                    "type": "string"
+                },
+                "Options": {
+                    "description": "Order-level options such as gift wrap, in the order they were chosen",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.OrderOption"
+                    }
+                },
+                "Paid": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "PaymentID": {
+                    "description": "Provider capture ID of the payment completing the order, set once the order is paid",
+                    "type": "string"
+                },
+                "Payments": {
+                    "description": "Payment ledger: charges and refunds in the order they were made",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Payment"
+                    }
+                },
+                "PendingPayment": {
+                    "description": "Provider capture ID of a payment awaiting confirmation by the provider",
+                    "type": "string"
+                },
+                "Refunded": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "Region": {
+                    "description": "Deployment region the order was placed in, empty if not configured",
+                    "type": "string",
+                    "example": "eu-west-1"
+                },
+                "Status": {
+                    "type": "string"
+                },
+                "TaxRegion": {
+                    "description": "Tax region the order was placed in, empty if not configured",
+                    "type": "string",
+                    "example": "US-CA"
+                },
+                "Total": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "TotalAmount": {
+                    "description": "Total order amount, items and option fees",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "UserID": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.OrderSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "CreatedAt": {
+                    "type": "string"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "Items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.OrderItem"
+                    }
+                },
+                "Number": {
+                    "type": "string",
+                    "example": "ORD-2024-000123"
+                },
+                "Sandbox": {
+                    "description": "Placed by a sandbox account",
+                    "type": "boolean"
+                },
+                "Status": {
+                    "type": "string",
+                    "example": "paid"
+                },
+                "Total": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
+        "handler.PayOrderRequest": {
+            "type": "object",
+            "properties": {
+                "payment_provider": {
+                    "description": "Payment provider, default if empty",
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "stripe"
+                },
+                "payment_token": {
+                    "description": "Provider token of the buyer's payment method, e.g. a Stripe payment method ID",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "handler.PhoneResponse": {
+            "type": "object",
+            "properties": {
+                "Phone": {
+                    "type": "string",
+                    "example": "+4915123456789"
+                },
+                "VerifiedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.PhoneVerificationResponse": {
+            "type": "object",
+            "properties": {
+                "ExpiresAt": {
+                    "type": "string"
+                },
+                "Phone": {
+                    "type": "string",
+                    "example": "+4915123456789"
+                }
+            }
+        },
+        "handler.PreviewTemplateRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 20000
+                },
+                "locale": {
+                    "description": "Locale whose current version is rendered, empty for the default version",
+                    "type": "string",
+                    "maxLength": 35,
+                    "example": "de-AT"
+                },
+                "subject": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Your order {{.OrderNumber}}"
+                },
+                "variables": {
+                    "description": "Values of template variables, sample values are used for others",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.ProductChangeRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "add_tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "sale"
+                    ]
+                },
+                "age_restriction": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "attributes": {
+                    "description": "Attributes to set, null values remove them",
+                    "type": "object"
+                },
+                "barcode": {
+                    "description": "Empty string removes the barcode",
+                    "type": "string",
+                    "example": "4006381333931"
+                },
+                "category": {
+                    "description": "Empty string removes the category",
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "headphones"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Wireless headphones, 2024 edition"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 89.99
+                },
+                "remove_tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "new"
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "active",
+                        "archived"
+                    ],
+                    "example": "active"
+                }
+            }
+        },
+        "handler.PublicProduct": {
+            "type": "object",
+            "additionalProperties": {}
+        },
+        "handler.PublicProductPage": {
+            "type": "object",
+            "properties": {
+                "Products": {
+                    "description": "Newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.PublicProduct"
+                    }
+                },
+                "Total": {
+                    "description": "Number of products matching the criteria",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.PublishDocumentRequest": {
+            "type": "object",
+            "required": [
+                "type",
+                "version"
+            ],
+            "properties": {
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "terms",
+                        "privacy"
+                    ],
+                    "example": "terms"
+                },
+                "version": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "2024-06-01"
+                }
+            }
+        },
+        "handler.RecordPaymentRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "method"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 20
+                },
+                "method": {
+                    "type": "string",
+                    "enum": [
+                        "card",
+                        "gift_card",
+                        "bank_transfer",
+                        "cash"
+                    ],
+                    "example": "gift_card"
+                },
+                "reference": {
+                    "description": "Provider charge ID for card payments, gift card code or transfer reference",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "GC-1234"
+                }
+            }
+        },
+        "handler.RecordStockMovementRequest": {
+            "type": "object",
+            "required": [
+                "delta",
+                "reason"
+            ],
+            "properties": {
+                "delta": {
+                    "description": "Positive for incoming stock, negative for outgoing",
+                    "type": "integer",
+                    "example": 50
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Delivery #1042"
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "receipt",
+                        "adjustment",
+                        "return"
+                    ],
+                    "example": "receipt"
+                }
+            }
+        },
+        "handler.RefundDecisionRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "description": "Required for rejections",
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Return received"
+                }
+            }
+        },
+        "handler.RefundItemRequest": {
+            "type": "object",
+            "required": [
+                "item_id",
+                "quantity"
+            ],
+            "properties": {
+                "item_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.RefundRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "payment_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 5
+                },
+                "payment_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "birthdate",
+                "email",
+                "firstname",
+                "lastname",
+                "password"
+            ],
+            "properties": {
+                "accepted_privacy_version": {
+                    "description": "Version of privacy policy accepted by the user",
+                    "type": "string",
+                    "example": "2024-06-01"
+                },
+                "accepted_terms_version": {
+                    "description": "Version of terms of service accepted by the user",
+                    "type": "string",
+                    "example": "2024-06-01"
+                },
+                "birthdate": {
+                    "type": "string",
+                    "example": "1999-04-21"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "firstname": {
+                    "type": "string",
+                    "example": "John"
+                },
+                "is_married": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "lastname": {
+                    "type": "string",
+                    "example": "Doe"
+                },
+                "locale": {
+                    "description": "Optional locale of emails and documents, the configured one if omitted",
+                    "type": "string",
+                    "maxLength": 35,
+                    "example": "de-AT"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8,
+                    "example": "password123"
+                },
+                "username": {
+                    "description": "Optional, case-insensitive",
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "john.doe"
+                }
+            }
+        },
+        "handler.RenameTagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "audio"
+                }
+            }
+        },
+        "handler.SaveTemplateRequest": {
+            "type": "object",
+            "required": [
+                "body",
+                "subject"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 20000,
+                    "example": "Hi {{.FirstName}}, your order {{.OrderNumber}} for {{.Total}} has been placed."
+                },
+                "locale": {
+                    "description": "Language or locale of the version, empty for the default version",
+                    "type": "string",
+                    "maxLength": 35,
+                    "example": "de"
+                },
+                "subject": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Your order {{.OrderNumber}}"
+                }
+            }
+        },
+        "handler.SetLocaleRequest": {
+            "type": "object",
+            "properties": {
+                "locale": {
+                    "description": "BCP 47 tag, empty for the configured locale",
+                    "type": "string",
+                    "maxLength": 35,
+                    "example": "de-AT"
+                }
+            }
+        },
+        "handler.SetPhoneRequest": {
+            "type": "object",
+            "required": [
+                "phone"
+            ],
+            "properties": {
+                "phone": {
+                    "description": "International format, separators are ignored",
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "+4915123456789"
+                }
+            }
+        },
+        "handler.SetQuotaRequest": {
+            "type": "object",
+            "required": [
+                "period"
+            ],
+            "properties": {
+                "limit": {
+                    "description": "0 disables the quota",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10000
+                },
+                "period": {
+                    "type": "string",
+                    "enum": [
+                        "day",
+                        "month"
+                    ],
+                    "example": "day"
+                }
+            }
+        },
+        "handler.SetRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "manager",
+                        "customer"
+                    ],
+                    "example": "manager"
+                }
+            }
+        },
+        "handler.SetSandboxRequest": {
+            "type": "object",
+            "properties": {
+                "sandbox": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.TemplatePreviewResponse": {
+            "type": "object",
+            "properties": {
+                "Body": {
+                    "type": "string",
+                    "example": "Hi Jane, your order ORD-2024-000123 for $25.00 has been placed."
+                },
+                "Subject": {
+                    "type": "string",
+                    "example": "Your order ORD-2024-000123"
+                }
+            }
+        },
+        "handler.UnreadCountResponse": {
+            "type": "object",
+            "properties": {
+                "Unread": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handler.UpdateDeliverySlotCapacityRequest": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "0 closes the slot for new orders",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 25
+                }
+            }
+        },
+        "handler.UpdateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "birthdate": {
+                    "type": "string",
+                    "example": "1999-04-21"
+                },
+                "firstname": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1,
+                    "example": "John"
+                },
+                "is_married": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "lastname": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1,
+                    "example": "Doe"
+                }
+            }
+        },
+        "handler.UsernameAvailabilityResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "username already taken"
+                },
+                "username": {
+                    "description": "Normalized username",
+                    "type": "string",
+                    "example": "john.doe"
+                }
+            }
+        },
+        "handler.VerifyPhoneRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "money.Money": {
+            "type": "object",
+            "properties": {
+                "Amount": {
+                    "description": "Amount in minor units",
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "Currency": {
+                    "description": "ISO 4217 code",
+                    "type": "string"
+                },
+                "Formatted": {
+                    "description": "Amount formatted for display",
+                    "type": "string"
+                },
+                "MinorUnits": {
+                    "description": "Decimal places of the currency",
+                    "type": "integer"
+                }
+            }
+        },
+        "notification.TemplatesStatus": {
+            "type": "object",
+            "properties": {
+                "LoadedAt": {
+                    "description": "Zero until versions are loaded",
+                    "type": "string"
+                },
+                "Stored": {
+                    "description": "Templates with a valid stored version, e.g. \"invoice\" or \"invoice/de\"; others use their built-in content",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "scim.Email": {
+            "type": "object",
+            "properties": {
+                "primary": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "scim.Error": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scimType": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "scim.ListResponse": {
+            "type": "object",
+            "properties": {
+                "Resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scim.User"
+                    }
+                },
+                "itemsPerPage": {
+                    "type": "integer"
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "startIndex": {
+                    "type": "integer"
+                },
+                "totalResults": {
+                    "type": "integer"
+                }
+            }
+        },
+        "scim.Meta": {
+            "type": "object",
+            "properties": {
+                "location": {
+                    "type": "string"
+                },
+                "resourceType": {
+                    "type": "string"
+                }
+            }
+        },
+        "scim.Name": {
+            "type": "object",
+            "properties": {
+                "familyName": {
                     "type": "string"
+                },
+                "givenName": {
+                    "type": "string"
+                }
+            }
+        },
+        "scim.PatchRequest": {
+            "type": "object"
+        },
+        "scim.User": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "emails": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scim.Email"
+                    }
+                },
+                "externalId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/scim.Meta"
+                },
+                "name": {
+                    "$ref": "#/definitions/scim.Name"
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "urn:productapi:params:scim:schemas:extension:2.0:User": {
+                    "$ref": "#/definitions/scim.UserExtension"
+                },
+                "userName": {
+                    "type": "string"
+                }
+            }
+        },
+        "scim.UserExtension": {
+            "type": "object",
+            "properties": {
+                "birthdate": {
+                    "description": "Format: YYYY-MM-DD",
+                    "type": "string"
+                }
+            }
+        },
+        "service.BulkUpdateReport": {
+            "type": "object",
+            "properties": {
+                "Failed": {
+                    "type": "integer"
+                },
+                "Results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.BulkUpdateResult"
+                    }
+                },
+                "Updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service.BulkUpdateResult": {
+            "type": "object",
+            "properties": {
+                "Error": {
+                    "description": "Reason of the failure",
+                    "type": "string"
+                },
+                "ID": {
+                    "type": "string"
+                },
+                "Product": {
+                    "description": "Product after the change, set for updated items",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Product"
+                        }
+                    ]
+                },
+                "Status": {
+                    "description": "updated, failed or rejected",
+                    "type": "string"
+                }
+            }
+        },
+        "service.CatalogIssueReport": {
+            "type": "object",
+            "properties": {
+                "Counts": {
+                    "description": "Issues found by each requested rule",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "Issues": {
+                    "description": "In product ID and rule order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CatalogIssue"
+                    }
+                },
+                "Total": {
+                    "description": "Issues found by the requested rules",
+                    "type": "integer"
                 }
             }
         },
-        "domain.OrderItem": {
+        "service.ChangePage": {
             "type": "object",
             "properties": {
-                "id": {
+                "Changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Change"
+                    }
+                },
+                "Next": {
+                    "description": "Sequence number to read the following page after, equal to since for empty pages",
+                    "type": "integer",
+                    "format": "int64"
+                }
+            }
+        },
+        "service.DependencyStatus": {
+            "type": "object",
+            "properties": {
+                "Error": {
+                    "description": "Reason of a failed check",
                     "type": "string"
                 },
-                "priceAtPurchase": {
-                    "type": "number",
-                    "format": "float64"
+                "LatencyMs": {
+                    "type": "integer",
+                    "example": 3
                 },
-                "productID": {
-                    "type": "string"
+                "Name": {
+                    "type": "string",
+                    "example": "database:primary"
                 },
-                "quantity": {
+                "Status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "service.InboxPage": {
+            "type": "object",
+            "properties": {
+                "Notifications": {
+                    "description": "Newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.InboxNotification"
+                    }
+                },
+                "Total": {
+                    "description": "Number of notifications matching the filter",
+                    "type": "integer"
+                },
+                "Unread": {
+                    "description": "Number of unread notifications",
                     "type": "integer"
                 }
             }
         },
-        "domain.Product": {
+        "service.JWK": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
+                "alg": {
+                    "type": "string",
+                    "example": "RS256"
                 },
-                "id": {
-                    "type": "string"
+                "crv": {
+                    "description": "OKP curve",
+                    "type": "string",
+                    "example": "Ed25519"
                 },
-                "price": {
-                    "type": "number",
-                    "format": "float64"
+                "e": {
+                    "description": "RSA public exponent",
+                    "type": "string",
+                    "example": "AQAB"
                 },
-                "quantity": {
-                    "type": "integer"
+                "kid": {
+                    "type": "string",
+                    "example": "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
                 },
-                "tags": {
+                "kty": {
+                    "description": "RSA or OKP",
+                    "type": "string",
+                    "example": "RSA"
+                },
+                "n": {
+                    "description": "RSA modulus",
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string",
+                    "example": "sig"
+                },
+                "x": {
+                    "description": "OKP public key",
+                    "type": "string"
+                }
+            }
+        },
+        "service.OrderTotalsReport": {
+            "type": "object",
+            "properties": {
+                "Mismatches": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/domain.OrderTotalMismatch"
                     }
+                },
+                "Repaired": {
+                    "description": "Number of repaired orders, 0 unless repair was requested",
+                    "type": "integer"
                 }
             }
         },
-        "domain.User": {
+        "service.PoolStatus": {
+            "type": "object",
+            "properties": {
+                "AcquireCount": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "AcquireDurationMs": {
+                    "description": "Total time spent acquiring connections",
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "AcquiredConns": {
+                    "description": "Connections in use",
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "CanceledAcquireCount": {
+                    "description": "Acquires cancelled while waiting, e.g. by request timeouts",
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "ConstructingConns": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "EmptyAcquireCount": {
+                    "description": "Acquires that waited for a connection, growing quickly when the pool is exhausted",
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "IdleConns": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "MaxConns": {
+                    "type": "integer",
+                    "format": "int32"
+                },
+                "Name": {
+                    "type": "string",
+                    "example": "primary"
+                },
+                "TotalConns": {
+                    "type": "integer",
+                    "format": "int32"
+                }
+            }
+        },
+        "service.ProductImportReport": {
             "type": "object",
             "properties": {
-                "age": {
+                "Created": {
                     "type": "integer"
                 },
-                "email": {
-                    "type": "string"
+                "Failed": {
+                    "type": "integer"
                 },
-                "firstname": {
+                "Results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.ProductImportResult"
+                    }
+                },
+                "StoppedAt": {
+                    "description": "Line the import failed at: the row that could not be read, or the first row of the batch that could not\nbe created. Rows of the batch are reported as failed, later rows were not read. 0 if the import completed.",
+                    "type": "integer"
+                }
+            }
+        },
+        "service.ProductImportResult": {
+            "type": "object",
+            "properties": {
+                "Barcode": {
                     "type": "string"
                 },
-                "id": {
+                "Error": {
+                    "description": "Reason of the failure",
                     "type": "string"
                 },
-                "isMarried": {
-                    "type": "boolean"
+                "Line": {
+                    "description": "Line in the file, starting at 1 with the header",
+                    "type": "integer"
                 },
-                "lastname": {
+                "ProductID": {
+                    "description": "Set for created products",
                     "type": "string"
                 },
-                "passwordHash": {
+                "Status": {
+                    "description": "created or failed",
                     "type": "string"
                 }
             }
         },
-        "handler.CreateOrderRequest": {
+        "service.ProductPage": {
             "type": "object",
-            "required": [
-                "items"
-            ],
             "properties": {
-                "items": {
+                "Products": {
+                    "description": "Newest first",
                     "type": "array",
-                    "minItems": 1,
                     "items": {
-                        "$ref": "#/definitions/handler.OrderItemInput"
+                        "$ref": "#/definitions/domain.Product"
                     }
+                },
+                "Total": {
+                    "description": "Number of products matching the criteria",
+                    "type": "integer"
                 }
             }
         },
-        "handler.CreateProductRequest": {
+        "service.QueueStatus": {
             "type": "object",
-            "required": [
-                "description",
-                "price",
-                "quantity",
-                "tags"
-            ],
             "properties": {
-                "description": {
+                "Depth": {
+                    "type": "integer"
+                },
+                "Name": {
                     "type": "string",
-                    "example": "High-quality wireless headphones"
+                    "example": "refund_requests"
                 },
-                "price": {
-                    "type": "number",
-                    "example": 99.99
+                "OldestAt": {
+                    "description": "Time the oldest waiting item was queued, nil if the queue is empty",
+                    "type": "string"
+                }
+            }
+        },
+        "service.StockDriftReport": {
+            "type": "object",
+            "properties": {
+                "Drifts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.StockDrift"
+                    }
                 },
-                "quantity": {
-                    "type": "integer",
-                    "example": 100
+                "Rebuilt": {
+                    "description": "Number of rebuilt products, 0 unless repair was requested",
+                    "type": "integer"
+                }
+            }
+        },
+        "service.StockImportReport": {
+            "type": "object",
+            "properties": {
+                "Failed": {
+                    "type": "integer"
                 },
-                "tags": {
+                "Mode": {
+                    "type": "string"
+                },
+                "Results": {
                     "type": "array",
                     "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "audio",
-                        "electronics",
-                        "wireless"
-                    ]
+                        "$ref": "#/definitions/service.StockImportResult"
+                    }
+                },
+                "Unchanged": {
+                    "type": "integer"
+                },
+                "Updated": {
+                    "type": "integer"
                 }
             }
         },
-        "handler.LoginRequest": {
+        "service.StockImportResult": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "user@example.com"
+                "Error": {
+                    "description": "Reason of the failure",
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "example": "password123"
+                "Line": {
+                    "type": "integer"
+                },
+                "Previous": {
+                    "description": "Quantity before the import",
+                    "type": "integer"
+                },
+                "ProductID": {
+                    "description": "Not set for unknown SKUs",
+                    "type": "string"
+                },
+                "Quantity": {
+                    "description": "Quantity after the import",
+                    "type": "integer"
+                },
+                "SKU": {
+                    "type": "string"
+                },
+                "Status": {
+                    "description": "updated, unchanged or failed",
+                    "type": "string"
                 }
             }
         },
-        "handler.LoginResponse": {
+        "service.SystemStatus": {
             "type": "object",
             "properties": {
-                "token": {
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                "CheckedAt": {
+                    "type": "string"
+                },
+                "Dependencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.DependencyStatus"
+                    }
+                },
+                "Pools": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PoolStatus"
+                    }
+                },
+                "Queues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.QueueStatus"
+                    }
+                },
+                "StartedAt": {
+                    "type": "string"
+                },
+                "Status": {
+                    "description": "ok, or degraded if a dependency check failed or a background runner stalled",
+                    "type": "string"
+                },
+                "Templates": {
+                    "$ref": "#/definitions/notification.TemplatesStatus"
+                },
+                "Workers": {
+                    "description": "Background runners of the instance, none outside the primary region",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.WorkerStatus"
+                    }
                 }
             }
         },
-        "handler.OrderItemInput": {
+        "service.TagUpdateResult": {
             "type": "object",
-            "required": [
-                "product_id",
-                "quantity"
-            ],
             "properties": {
-                "product_id": {
+                "Tag": {
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "integer"
+                "UpdatedProducts": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "handler.RegisterRequest": {
+        "service.WorkerStatus": {
             "type": "object",
-            "required": [
-                "age",
-                "email",
-                "firstname",
-                "lastname",
-                "password"
-            ],
             "properties": {
-                "age": {
+                "IntervalMs": {
+                    "description": "Interval between runs while idle",
                     "type": "integer",
-                    "minimum": 18,
-                    "example": 25
+                    "example": 60000
                 },
-                "email": {
-                    "type": "string",
-                    "example": "user@example.com"
+                "LastError": {
+                    "description": "Error of the last run, empty if it succeeded",
+                    "type": "string"
                 },
-                "firstname": {
-                    "type": "string",
-                    "example": "John"
+                "LastRunAt": {
+                    "description": "Time the last run finished, nil before the first run",
+                    "type": "string"
                 },
-                "is_married": {
-                    "type": "boolean",
-                    "example": false
+                "LastSuccessAt": {
+                    "description": "Time the last successful run finished, nil if no run succeeded",
+                    "type": "string"
                 },
-                "lastname": {
+                "Name": {
                     "type": "string",
-                    "example": "Doe"
+                    "example": "accounting"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8,
-                    "example": "password123"
+                "Stalled": {
+                    "description": "No run finished for three intervals since the last run or the start",
+                    "type": "boolean"
                 }
             }
         }
@@ -1,7 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
@@ -10,20 +15,410 @@ import (
 
 // Config contains application configuration.
 // All parameters are loaded from environment variables.
+// Secrets are tagged with redact, see Redacted.
 type Config struct {
-	Env         string        `env:"ENV" env-default:"local"`                    // Environment: local, dev, prod
-	DatabaseURL string        `env:"DATABASE_URL" env-required:"true"`           // PostgreSQL connection URL
-	SentryDSN   string        `env:"SENTRY_DSN"`                                 // Sentry DSN (optional)
-	JWTSecret   string        `env:"JWT_SECRET" env-required:"true"`             // Secret key for JWT token signing
-	JWTTTL      time.Duration `env:"JWT_TTL" env-default:"24h"`                   // JWT token lifetime
-	HTTPServer                                                                   // HTTP server settings
+	Env                string            `env:"ENV" env-default:"local"`                        // Environment: local, dev, prod
+	DatabaseURL        string            `env:"DATABASE_URL" env-required:"true" redact:"url"`  // PostgreSQL connection URL
+	SlowQueryThreshold time.Duration     `env:"DB_SLOW_QUERY_THRESHOLD" env-default:"200ms"`    // Queries taking longer are logged, 0 disables
+	DBAcquireTimeout   time.Duration     `env:"DB_ACQUIRE_TIMEOUT" env-default:"2s"`            // Requests waiting longer for a database connection fail with 503 Service Unavailable, 0 waits until the request ends
+	DBRetryAfter       time.Duration     `env:"DB_RETRY_AFTER" env-default:"1s"`                // Retry-After of the 503 responses when no database connection was available, in whole seconds
+	PublicURL          string            `env:"PUBLIC_URL" env-default:"http://localhost:8080"` // Public base URL of the API
+	SentryDSN          string            `env:"SENTRY_DSN" redact:"value"`                      // Sentry DSN (optional)
+	PanicCaptureBody   bool              `env:"PANIC_CAPTURE_BODY" env-default:"false"`         // Attach redacted request body to panic reports
+	DebugCapture       bool              `env:"DEBUG_CAPTURE_BODIES" env-default:"false"`       // Log redacted request and response bodies of all requests, otherwise only of requests with X-Debug-Capture: <admin API key>
+	OTLPEndpoint       string            `env:"OTLP_ENDPOINT" redact:"url"`                     // OTLP/HTTP collector URL for traces and metrics, e.g. "http://otel-collector:4318" (optional)
+	JWTSecret          string            `env:"JWT_SECRET" redact:"value"`                      // Secret key of HS256 tokens, required for HS256; with RS256 or EdDSA, HS256 tokens signed before the switch stay valid while it is set
+	JWTAlgorithm       string            `env:"JWT_ALGORITHM" env-default:"HS256"`              // Token signing algorithm: HS256, RS256 or EdDSA
+	JWTPrivateKeyFile  string            `env:"JWT_PRIVATE_KEY_FILE"`                           // PEM file of the RSA or Ed25519 private key of RS256 and EdDSA tokens, public keys are served at /.well-known/jwks.json
+	JWTTTL             time.Duration     `env:"JWT_TTL" env-default:"24h"`                      // JWT token lifetime
+	IdempotencyTTL     time.Duration     `env:"IDEMPOTENCY_KEY_TTL" env-default:"24h"`          // How long responses are replayed for a reused Idempotency-Key
+	IdempotencyCleanup time.Duration     `env:"IDEMPOTENCY_CLEANUP_INTERVAL" env-default:"1h"`  // How often expired Idempotency-Keys are deleted
+	APIKeys            map[string]string `env:"API_KEYS" redact:"value"`                        // API keys by client name, format: "admin:key1,gateway:key2" (clients: admin, analyst, support, finance, scim, gateway, introspection)
+	Currency           string            `env:"CURRENCY" env-default:"USD"`                     // ISO 4217 code of prices and order totals
+	Locale             string            `env:"LOCALE" env-default:"en-US"`                     // Locale for formatting amounts in responses and documents, e.g. "de-DE"
+	TaxRegion          string            `env:"TAX_REGION"`                                     // Tax region orders are placed in, e.g. "US-CA" or "DE" (optional)
+	FiscalYearStart    int               `env:"FISCAL_YEAR_START_MONTH" env-default:"1"`        // Month (1-12) the fiscal year of invoice numbers starts in
+	AdminEmails        []string          `env:"ADMIN_EMAILS"`                                   // Addresses of operational alerts, e.g. payment disputes, format: "ops@example.com,finance@example.com"
+	TemplateReload     time.Duration     `env:"TEMPLATE_RELOAD_INTERVAL" env-default:"30s"`     // How often edited notification templates are reloaded
+	SchemaCheck        string            `env:"SCHEMA_CHECK"`                                   // Startup check of the database schema version: strict, warn, off (default: strict in prod, warn otherwise)
+	HTTPServer                           // HTTP server settings
+	GRPCServer                           // gRPC server settings
+	OIDC                                 // OpenID Connect identity providers
+	Swagger                              // Swagger UI settings
+	Quotas                               // Default API key call quotas
+	Reporting                            // Database pool of reporting and export endpoints
+	Payments                             // Payment providers
+	Storage                              // Object storage
+	SMS                                  // Text message delivery
+	Tracing                              // Trace sampling
+	RequestLog                           // Log line of every HTTP request
+	Metrics                              // Business metrics export
+	Shadow                               // Shadow traffic to a secondary product database
+	Replicas                             // Read replicas of product lookups
+	CatalogLimits                        // Bulkhead of catalog browsing requests
+	PublicCatalog                        // Anonymous catalog embedded in marketing sites
+	OrderExports                         // Customers' exports of their order history
+	Announcements                        // Batch sending of announcements to segments of users
+	RateLimits                           // Request rate limits per user and client IP
+	LoginLockout                         // Lockout of accounts after failed logins
+	Checkout                             // Validation of checkouts
+	OrderOptions                         // Order-level options such as gift wrap and their fees
+	Accounting                           // Journal of payments exported to accounting software
+	StockSnapshots                       // Daily stock snapshots of analytics
+	BulkOperations                       // Price and stock changes applied to products matching a filter
+	ChangeLog                            // Retention of the change feed of downstream systems
+	Region                               // Deployment region and the primary write region
 }
 
 // HTTPServer contains HTTP server configuration.
 type HTTPServer struct {
-	Address     string        `env:"HTTP_SERVER_ADDRESS" env-default:":8080"`     // Server address and port
+	Address     string        `env:"HTTP_SERVER_ADDRESS" env-default:":8080"`    // Server address and port
 	Timeout     time.Duration `env:"HTTP_SERVER_TIMEOUT" env-default:"5s"`       // Read/write timeout
 	IdleTimeout time.Duration `env:"HTTP_SERVER_IDLE_TIMEOUT" env-default:"60s"` // Idle connection timeout
+	// Time between a termination signal and closing the listener, while readiness probes fail
+	// so load balancers stop routing new requests to the instance
+	PreStopDelay time.Duration `env:"HTTP_SERVER_PRE_STOP_DELAY" env-default:"0s"`
+	// Time in-flight requests have to complete after the listener is closed
+	ShutdownTimeout time.Duration `env:"HTTP_SERVER_SHUTDOWN_TIMEOUT" env-default:"10s"`
+	// Bind the address with SO_REUSEPORT, so a new process can listen while the old one drains (Linux only).
+	// Ignored when the listener is inherited through systemd socket activation (LISTEN_FDS).
+	ReusePort bool `env:"HTTP_SERVER_REUSE_PORT" env-default:"false"`
+	// CIDRs of the proxies in front of the API, whose client IP headers are trusted, format: "10.0.0.0/8,192.0.2.10/32".
+	// Client IP headers of other senders are ignored; requests are rate limited by the address they come from.
+	TrustedProxies []string `env:"TRUSTED_PROXIES"`
+}
+
+// TrustedProxyPrefixes returns the parsed TrustedProxies, validated by MustLoad.
+func (s HTTPServer) TrustedProxyPrefixes() []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(s.TrustedProxies))
+	for _, cidr := range s.TrustedProxies {
+		if prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr)); err == nil {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// GRPCServer contains gRPC server configuration.
+// The server shares the drain settings of the HTTP server (PreStopDelay, ShutdownTimeout).
+type GRPCServer struct {
+	Address string `env:"GRPC_SERVER_ADDRESS" env-default:":9090"` // Server address and port, empty to disable the gRPC API
+}
+
+// OIDC contains OpenID Connect identity provider configuration.
+// Each map is keyed by provider name; a provider is enabled when it has an issuer URL.
+type OIDC struct {
+	Issuers       map[string]string `env:"OIDC_ISSUERS"`                       // Issuer URLs, format: "okta:https://example.okta.com,azure:https://login.microsoftonline.com/<tenant>/v2.0"
+	ClientIDs     map[string]string `env:"OIDC_CLIENT_IDS"`                    // OAuth2 client IDs, format: "okta:id1,azure:id2"
+	ClientSecrets map[string]string `env:"OIDC_CLIENT_SECRETS" redact:"value"` // OAuth2 client secrets, format: "okta:secret1,azure:secret2"
+}
+
+// Quotas contains default call quotas of API key clients.
+// Clients without a quota are not limited; admins can override quotas at runtime.
+type Quotas struct {
+	Daily   map[string]int `env:"API_KEY_DAILY_QUOTAS"`   // Calls per UTC day by client name, format: "gateway:100000,scim:5000"
+	Monthly map[string]int `env:"API_KEY_MONTHLY_QUOTAS"` // Calls per calendar month by client name, format: "gateway:2000000"
+
+	SoftLimit int `env:"API_KEY_QUOTA_SOFT_LIMIT" env-default:"80"` // Percentage of a quota after which responses warn the client, 0 disables warnings
+}
+
+// Reporting contains settings of the database pool used by reporting and export endpoints.
+// The pool is small and read-only, and its statements time out, so analytical queries cannot starve transactional traffic.
+type Reporting struct {
+	DatabaseURL      string        `env:"REPORTING_DATABASE_URL" redact:"url"`          // PostgreSQL connection URL, e.g. of a read replica (default: DATABASE_URL)
+	MaxConns         int32         `env:"REPORTING_DB_MAX_CONNS" env-default:"2"`       // Maximum number of connections
+	StatementTimeout time.Duration `env:"REPORTING_STATEMENT_TIMEOUT" env-default:"2m"` // Statements running longer are cancelled
+}
+
+// Payments contains payment provider settings.
+// The mock provider keeps payments in memory. It is available outside prod, and in prod only as the default provider.
+type Payments struct {
+	DefaultProvider     string        `env:"PAYMENT_PROVIDER" env-default:"mock"`                            // Provider of checkouts that do not select one: mock (not in prod), paypal or stripe
+	MockWebhookSecret   string        `env:"MOCK_PAYMENT_WEBHOOK_SECRET" redact:"value"`                     // Secret of mock provider webhook signatures, webhooks are rejected if empty
+	PayPalBaseURL       string        `env:"PAYPAL_BASE_URL" env-default:"https://api-m.sandbox.paypal.com"` // PayPal REST API URL, https://api-m.paypal.com for live payments
+	PayPalClientID      string        `env:"PAYPAL_CLIENT_ID"`                                               // PayPal is enabled when set
+	PayPalClientSecret  string        `env:"PAYPAL_CLIENT_SECRET" redact:"value"`
+	PayPalWebhookID     string        `env:"PAYPAL_WEBHOOK_ID"`                                    // ID of the registered webhook, used to verify webhook signatures
+	StripeBaseURL       string        `env:"STRIPE_BASE_URL" env-default:"https://api.stripe.com"` // Stripe API URL
+	StripeSecretKey     string        `env:"STRIPE_SECRET_KEY" redact:"value"`                     // Stripe is enabled when set, test mode keys start with sk_test_
+	StripeWebhookSecret string        `env:"STRIPE_WEBHOOK_SECRET" redact:"value"`                 // Signing secret of the webhook endpoint, webhooks are rejected if empty
+	PendingTimeout      time.Duration `env:"PAYMENT_PENDING_TIMEOUT" env-default:"72h"`            // How long an order with a pending or failed payment keeps its stock before it is cancelled
+	ExpiryPollInterval  time.Duration `env:"PAYMENT_EXPIRY_POLL_INTERVAL" env-default:"5m"`        // How often orders awaiting payment are checked for expiry
+}
+
+// Storage contains object storage settings of product images, avatars, invoices and exports.
+// The local backend keeps objects on disk and cannot issue presigned URLs, so files are served through the API.
+type Storage struct {
+	Backend         string        `env:"STORAGE_BACKEND" env-default:"local"`            // Backend: local, s3 or gcs
+	LocalDir        string        `env:"STORAGE_LOCAL_DIR" env-default:"./data/storage"` // Directory of the local backend
+	Bucket          string        `env:"STORAGE_BUCKET"`                                 // Bucket of the s3 and gcs backends
+	Region          string        `env:"STORAGE_REGION" env-default:"us-east-1"`         // S3 region
+	Endpoint        string        `env:"STORAGE_ENDPOINT" redact:"url"`                  // URL of S3-compatible services, e.g. "http://minio:9000" (default: AWS S3)
+	PathStyle       bool          `env:"STORAGE_PATH_STYLE" env-default:"false"`         // Address the bucket in URL paths, needed by most S3-compatible services
+	AccessKeyID     string        `env:"STORAGE_ACCESS_KEY_ID"`                          // S3 access key ID or GCS HMAC key access ID
+	SecretAccessKey string        `env:"STORAGE_SECRET_ACCESS_KEY" redact:"value"`       // S3 secret access key or GCS HMAC key secret
+	PresignTTL      time.Duration `env:"STORAGE_PRESIGN_TTL" env-default:"15m"`          // Validity of presigned upload and download URLs, at most 7 days
+}
+
+// SMS contains settings of text messages: phone verification codes and notifications of users who opted in to SMS.
+// Messages are logged instead of sent unless Twilio is configured.
+type SMS struct {
+	TwilioBaseURL    string            `env:"TWILIO_BASE_URL" env-default:"https://api.twilio.com"` // Twilio REST API URL
+	TwilioAccountSID string            `env:"TWILIO_ACCOUNT_SID"`                                   // Twilio is enabled when set
+	TwilioAuthToken  string            `env:"TWILIO_AUTH_TOKEN" redact:"value"`
+	Senders          map[string]string `env:"SMS_SENDERS"` // Sender number or alphanumeric ID by country calling code, "*" for other countries, format: "1:+15550100,44:MyShop,*:+15550100"
+}
+
+// Tracing contains sampling settings of traces exported to the OTLP collector and Sentry.
+// Sentry error events are never sampled.
+type Tracing struct {
+	SampleRatio       string `env:"TRACE_SAMPLE_RATIO"`                     // Share of traces started by the application, 0 to 1 (default: 0.05 in prod, 1 otherwise)
+	ParentBased       bool   `env:"TRACE_PARENT_BASED" env-default:"true"`  // Follow the sampling decision of callers propagated in trace headers
+	SampleErrors      bool   `env:"TRACE_SAMPLE_ERRORS" env-default:"true"` // Export failed spans of traces that are not sampled
+	SentrySampleRatio string `env:"SENTRY_TRACES_SAMPLE_RATE"`              // Share of traces sent to Sentry, 0 to 1 (default: TRACE_SAMPLE_RATIO)
+}
+
+// RequestLog configures the line logged for every HTTP request with its status, duration and user.
+// Request bodies are logged redacted, and only with responses that are not 2xx.
+type RequestLog struct {
+	Enabled   bool `env:"REQUEST_LOG" env-default:"true"`         // Log every HTTP request
+	BodyLimit int  `env:"REQUEST_LOG_BODY_LIMIT" env-default:"0"` // Bytes of the request body logged with responses that are not 2xx, at most 4096, 0 logs no bodies
+}
+
+// Metrics exporters.
+const (
+	MetricsExporterOTLP       = "otlp"       // Pushed to the OTLP collector
+	MetricsExporterDogStatsD  = "dogstatsd"  // Sent to a Datadog agent with the DogStatsD protocol
+	MetricsExporterPrometheus = "prometheus" // Scraped from GET /metrics
+	MetricsExporterNone       = "none"       // Not collected
+)
+
+// Metrics contains settings of the business metrics exporter.
+// DogStatsD metrics are tagged with env, service and version, following Datadog unified service tagging.
+type Metrics struct {
+	Exporter         string        `env:"METRICS_EXPORTER"`                               // Exporter: otlp, dogstatsd, prometheus, none (default: otlp if OTLP_ENDPOINT is set, none otherwise)
+	Interval         time.Duration `env:"METRICS_EXPORT_INTERVAL" env-default:"60s"`      // How often metrics are pushed, prometheus metrics are collected on scrape
+	DogStatsDAddress string        `env:"DOGSTATSD_ADDRESS" env-default:"localhost:8125"` // UDP address of the Datadog agent
+	Service          string        `env:"DD_SERVICE" env-default:"product-api"`           // Value of the service tag
+	Version          string        `env:"DD_VERSION"`                                     // Value of the version tag, e.g. the deployed release (optional)
+	Tags             []string      `env:"DOGSTATSD_TAGS"`                                 // Additional tags, format: "team:catalog,region:eu"
+}
+
+// Shadow contains settings of shadow traffic, mirroring product reads and optionally writes
+// to a secondary database and comparing results in the background, e.g. while migrating to a new backend.
+// Responses always come from the primary database.
+type Shadow struct {
+	DatabaseURL string        `env:"SHADOW_DATABASE_URL" redact:"url"`      // PostgreSQL connection URL of the secondary, shadowing is disabled if empty
+	SampleRate  float64       `env:"SHADOW_SAMPLE_RATE" env-default:"1"`    // Share of reads mirrored, 0 to 1
+	Writes      bool          `env:"SHADOW_WRITES" env-default:"false"`     // Mirror successful product creations and updates
+	Timeout     time.Duration `env:"SHADOW_TIMEOUT" env-default:"2s"`       // Timeout of calls to the secondary
+	MaxInFlight int           `env:"SHADOW_MAX_IN_FLIGHT" env-default:"16"` // Calls to the secondary in progress at most, further calls are skipped
+	MaxConns    int32         `env:"SHADOW_DB_MAX_CONNS" env-default:"4"`   // Maximum number of connections to the secondary
+}
+
+// Replicas contains settings of read replicas serving product lookups by ID.
+// Lookups failing on replicas, e.g. of products not replicated yet, are retried on the primary database.
+// With hedging, lookups still running after the hedge delay are also sent to a second replica and the first result is used,
+// cutting tail latency while a replica stalls.
+type Replicas struct {
+	DatabaseURLs []string      `env:"REPLICA_DATABASE_URLS" redact:"url"`   // PostgreSQL connection URLs of read replicas, format: "url1,url2", lookups use the primary if empty
+	MaxConns     int32         `env:"REPLICA_DB_MAX_CONNS" env-default:"4"` // Maximum number of connections per replica
+	Hedge        bool          `env:"REPLICA_HEDGE" env-default:"false"`    // Send slow lookups to a second replica, requires two replicas
+	HedgeDelay   time.Duration `env:"REPLICA_HEDGE_DELAY"`                  // Wait before hedging a lookup (default: p95 latency of recent lookups)
+}
+
+// CatalogLimits bound the catalog browsing requests served at once, so their bursts, e.g. during sales events,
+// cannot take the database connections order creation and payment callbacks need. Other requests are not limited.
+type CatalogLimits struct {
+	MaxConcurrent int           `env:"CATALOG_MAX_CONCURRENT_REQUESTS"`           // Catalog requests served at once, -1 for no limit (default: 3/4 of the database pool size)
+	Queue         int           `env:"CATALOG_REQUEST_QUEUE" env-default:"100"`   // Catalog requests waiting for a slot, more are rejected with 503
+	MaxWait       time.Duration `env:"CATALOG_REQUEST_MAX_WAIT" env-default:"2s"` // Longest wait for a slot, longer waiting requests are rejected with 503
+}
+
+// PublicCatalog configures the read-only catalog marketing sites embed without authentication.
+type PublicCatalog struct {
+	MaxAge time.Duration `env:"PUBLIC_CATALOG_MAX_AGE" env-default:"5m"` // How long browsers and CDNs cache responses before revalidating
+}
+
+// OrderExports configures the exports customers download of their own order history.
+// Exports are kept in the object storage and generated at most once per interval, repeated requests get the stored export.
+type OrderExports struct {
+	SyncLimit int           `env:"ORDER_EXPORT_SYNC_LIMIT" env-default:"500"` // Exports of customers with more orders are generated in the background
+	Interval  time.Duration `env:"ORDER_EXPORT_INTERVAL" env-default:"1h"`    // Minimum time between generated exports of a customer
+}
+
+// Announcements configures the batch runner sending announcements to segments of users. Every instance runs it,
+// each batch is sent by one instance.
+type Announcements struct {
+	BatchSize    int           `env:"ANNOUNCEMENT_BATCH_SIZE" env-default:"500"`    // Users sent an announcement at once
+	PollInterval time.Duration `env:"ANNOUNCEMENT_POLL_INTERVAL" env-default:"10s"` // How often idle instances check for queued announcements
+	Lease        time.Duration `env:"ANNOUNCEMENT_LEASE" env-default:"5m"`          // Longest time a batch may take before another instance resumes the announcement
+}
+
+// RateLimits configures token-bucket rate limits of requests, per user of authenticated requests and per client IP
+// otherwise. Login and registration have a stricter limit per client IP against credential stuffing.
+// With the memory backend every instance counts requests on its own, the redis backend shares the limits between instances.
+type RateLimits struct {
+	Backend      string        `env:"RATE_LIMIT_BACKEND" env-default:"memory"`   // Backend: memory or redis
+	RedisURL     string        `env:"REDIS_URL" redact:"url"`                    // Redis connection URL of the redis backend, e.g. "redis://redis:6379/0"
+	Requests     int           `env:"RATE_LIMIT_REQUESTS" env-default:"300"`     // Requests per period of a user or client IP, 0 disables the limit
+	Period       time.Duration `env:"RATE_LIMIT_PERIOD" env-default:"1m"`        // Period the requests refill over, a user or client IP may send all of them at once
+	AuthRequests int           `env:"AUTH_RATE_LIMIT_REQUESTS" env-default:"10"` // Login and registration requests per period of a client IP, password changes of a user, 0 disables the limit
+	AuthPeriod   time.Duration `env:"AUTH_RATE_LIMIT_PERIOD" env-default:"1m"`   // Period the login and registration requests refill over
+}
+
+// LoginLockout configures the lockout of accounts after consecutive failed password logins. Locked accounts are refused
+// with 423 Locked until the lockout ends, even with the right password; a successful login resets the failure count.
+type LoginLockout struct {
+	MaxFailures int           `env:"LOGIN_MAX_FAILURES" env-default:"5"` // Consecutive failed logins locking an account, 0 disables lockouts
+	Duration    time.Duration `env:"LOGIN_LOCKOUT" env-default:"15m"`    // How long accounts stay locked
+}
+
+// Checkout configures the validators checkouts run, in order, before stock is allocated.
+// The availability and stock validators are required. A checkout with the same products, quantities and total
+// as an order the user placed within the duplicate window, e.g. a double submission, must be confirmed.
+type Checkout struct {
+	Validators      []string      `env:"CHECKOUT_VALIDATORS" env-default:"availability,age_restriction,quantity,stock"` // Validators in the order they run: availability, age_restriction, quantity, stock
+	MinLineQuantity int           `env:"CHECKOUT_MIN_LINE_QUANTITY" env-default:"0"`                                    // Fewest items of a product an order may contain, 0 for no limit
+	MaxLineQuantity int           `env:"CHECKOUT_MAX_LINE_QUANTITY" env-default:"0"`                                    // Most items of a product an order may contain, 0 for no limit
+	DuplicateWindow time.Duration `env:"CHECKOUT_DUPLICATE_WINDOW" env-default:"10m"`                                   // How long after an order an identical one must be confirmed, 0 disables the check
+}
+
+// OrderOptions configures the order-level options customers choose at checkout: gift_wrap, gift_message and
+// priority_handling. Fees are in the currency of prices and part of order totals, options not listed are not offered.
+type OrderOptions struct {
+	Fees map[string]float64 `env:"ORDER_OPTION_FEES" env-default:"gift_wrap:4.99,gift_message:0,priority_handling:9.99"` // Fees of offered options, format: "gift_wrap:4.99,gift_message:0"
+}
+
+// Accounting configures the double-entry journal of payments exported to accounting software. Prices include
+// sales tax at the rate of the order's tax region. Payment providers do not report their fees, so they are estimated
+// from the fee schedules. The primary region journals new payments in the background.
+type Accounting struct {
+	TaxRates     map[string]float64 `env:"ACCOUNTING_TAX_RATES"`                                                                                          // Sales tax rates in percent included in prices by tax region, format: "US-CA:7.25,DE:19"
+	FeePercent   map[string]float64 `env:"ACCOUNTING_FEE_PERCENT"`                                                                                        // Fees of payment providers in percent of charges, format: "stripe:2.9,paypal:3.49"
+	FeeFixed     map[string]float64 `env:"ACCOUNTING_FEE_FIXED"`                                                                                          // Fixed fees of payment providers per charge, format: "stripe:0.30,paypal:0.49"
+	Accounts     map[string]string  `env:"ACCOUNTING_ACCOUNTS" env-default:"payment_clearing:610,revenue:200,refunds:200,sales_tax:820,payment_fees:404"` // Ledger accounts of the journal accounts, codes in Xero or names in QuickBooks
+	BatchSize    int                `env:"ACCOUNTING_BATCH_SIZE" env-default:"200"`                                                                       // Payments journaled at once
+	PollInterval time.Duration      `env:"ACCOUNTING_POLL_INTERVAL" env-default:"1m"`                                                                     // How often new payments are journaled
+}
+
+// StockSnapshots configures the runner recording the closing stock of every product per day (UTC) from the stock ledger.
+type StockSnapshots struct {
+	PollInterval time.Duration `env:"STOCK_SNAPSHOT_POLL_INTERVAL" env-default:"1h"` // How often the runner checks for a completed day
+}
+
+// BulkOperations configures the runner applying bulk price and stock operations to the matching products in batches.
+type BulkOperations struct {
+	BatchSize    int           `env:"BULK_OPERATION_BATCH_SIZE" env-default:"100"`    // Products changed per transaction
+	PollInterval time.Duration `env:"BULK_OPERATION_POLL_INTERVAL" env-default:"10s"` // How often the runner checks for queued operations while idle
+}
+
+// ChangeLog configures the retention of the change feed. Readers further behind than the retention
+// get change_not_found and must start over from a full export.
+type ChangeLog struct {
+	Retention       time.Duration `env:"CHANGE_LOG_RETENTION" env-default:"720h"`      // How long changes stay in the feed
+	CleanupInterval time.Duration `env:"CHANGE_LOG_CLEANUP_INTERVAL" env-default:"1h"` // How often older changes are deleted
+}
+
+// Region identifies the deployment region of the instance. In active/passive deployments across regions,
+// instances outside the primary region serve reads locally and forward writes and order requests to the primary region.
+type Region struct {
+	Name       string `env:"REGION"`             // Region of the instance, e.g. "eu-west-1", recorded in telemetry, logs, orders and order events
+	Primary    string `env:"PRIMARY_REGION"`     // Region accepting writes (default: REGION, the instance accepts writes)
+	PrimaryURL string `env:"PRIMARY_REGION_URL"` // Base URL of the API in the primary region, required outside it
+	// Secret shared by the regions, signing the region of forwarded requests, required with PRIMARY_REGION
+	ForwardSecret string `env:"REGION_FORWARD_SECRET" redact:"value"`
+}
+
+// IsPrimary reports whether the instance runs in the primary write region.
+func (r Region) IsPrimary() bool {
+	return r.Primary == "" || r.Primary == r.Name
+}
+
+// Modes of the startup check of the database schema version.
+const (
+	SchemaCheckStrict = "strict" // Refuse to start if the schema is older than the binary or dirty
+	SchemaCheckWarn   = "warn"   // Log mismatches and start
+	SchemaCheckOff    = "off"    // Skip the check
+)
+
+// Swagger UI access modes.
+const (
+	SwaggerModePublic   = "public"   // Available without authentication
+	SwaggerModeBasic    = "basic"    // Protected with HTTP Basic authentication
+	SwaggerModeAdmin    = "admin"    // Protected with the admin API key
+	SwaggerModeDisabled = "disabled" // Not served
+)
+
+// Swagger contains Swagger UI configuration.
+type Swagger struct {
+	Mode     string `env:"SWAGGER_MODE"`                    // Access mode: public, basic, admin, disabled (default: disabled in prod, public otherwise)
+	Username string `env:"SWAGGER_USERNAME"`                // Username for basic mode
+	Password string `env:"SWAGGER_PASSWORD" redact:"value"` // Password for basic mode
+}
+
+// SwaggerMode returns the Swagger UI access mode, applying the environment default if it is not set.
+func (c *Config) SwaggerMode() string {
+	if c.Swagger.Mode != "" {
+		return c.Swagger.Mode
+	}
+	if c.Env == "prod" {
+		return SwaggerModeDisabled
+	}
+	return SwaggerModePublic
+}
+
+// SchemaCheckMode returns the mode of the schema version check, applying the environment default if it is not set.
+func (c *Config) SchemaCheckMode() string {
+	if c.SchemaCheck != "" {
+		return c.SchemaCheck
+	}
+	if c.Env == "prod" {
+		return SchemaCheckStrict
+	}
+	return SchemaCheckWarn
+}
+
+// MetricsExporter returns the metrics exporter, applying the default if it is not set.
+func (c *Config) MetricsExporter() string {
+	if c.Metrics.Exporter != "" {
+		return c.Metrics.Exporter
+	}
+	if c.OTLPEndpoint != "" {
+		return MetricsExporterOTLP
+	}
+	return MetricsExporterNone
+}
+
+// TraceSampleRatio returns the share of traces sampled by the application, applying the environment default if it is not set.
+func (c *Config) TraceSampleRatio() float64 {
+	if ratio, err := parseRatio(c.Tracing.SampleRatio); err == nil {
+		return ratio
+	}
+	if c.Env == "prod" {
+		return 0.05
+	}
+	return 1
+}
+
+// SentryTracesSampleRatio returns the share of traces sent to Sentry, by default the trace sample ratio.
+func (c *Config) SentryTracesSampleRatio() float64 {
+	if ratio, err := parseRatio(c.Tracing.SentrySampleRatio); err == nil {
+		return ratio
+	}
+	return c.TraceSampleRatio()
+}
+
+// parseRatio parses a ratio between 0 and 1.
+func parseRatio(s string) (float64, error) {
+	ratio, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if ratio < 0 || ratio > 1 {
+		return 0, fmt.Errorf("ratio %v is not between 0 and 1", ratio)
+	}
+	return ratio, nil
 }
 
 // MustLoad loads configuration from environment variables.
@@ -42,5 +437,200 @@ func MustLoad() *Config {
 		log.Fatalf("failed to read config from environment variables: %v", err)
 	}
 
+	switch cfg.JWTAlgorithm {
+	case "HS256":
+		if cfg.JWTSecret == "" {
+			log.Fatalf("JWT_SECRET is required for HS256 tokens")
+		}
+	case "RS256", "EdDSA":
+		if cfg.JWTPrivateKeyFile == "" {
+			log.Fatalf("JWT_PRIVATE_KEY_FILE is required for %s tokens", cfg.JWTAlgorithm)
+		}
+	default:
+		log.Fatalf("invalid JWT_ALGORITHM %q, must be HS256, RS256 or EdDSA", cfg.JWTAlgorithm)
+	}
+
+	if cfg.FiscalYearStart < 1 || cfg.FiscalYearStart > 12 {
+		log.Fatalf("FISCAL_YEAR_START_MONTH must be between 1 and 12")
+	}
+
+	if cfg.Quotas.SoftLimit < 0 || cfg.Quotas.SoftLimit > 100 {
+		log.Fatalf("API_KEY_QUOTA_SOFT_LIMIT must be between 0 and 100")
+	}
+
+	switch cfg.Payments.DefaultProvider {
+	case "mock":
+		if cfg.Env == "prod" {
+			log.Fatalf("PAYMENT_PROVIDER must be paypal or stripe in prod, the mock provider keeps payments in memory")
+		}
+	case "paypal":
+		if cfg.Payments.PayPalClientID == "" || cfg.Payments.PayPalClientSecret == "" {
+			log.Fatalf("PAYPAL_CLIENT_ID and PAYPAL_CLIENT_SECRET are required for the paypal payment provider")
+		}
+	case "stripe":
+		if cfg.Payments.StripeSecretKey == "" {
+			log.Fatalf("STRIPE_SECRET_KEY is required for the stripe payment provider")
+		}
+	default:
+		log.Fatalf("invalid PAYMENT_PROVIDER %q", cfg.Payments.DefaultProvider)
+	}
+
+	switch cfg.Storage.Backend {
+	case "local":
+	case "s3", "gcs":
+		if cfg.Storage.Bucket == "" || cfg.Storage.AccessKeyID == "" || cfg.Storage.SecretAccessKey == "" {
+			log.Fatalf("STORAGE_BUCKET, STORAGE_ACCESS_KEY_ID and STORAGE_SECRET_ACCESS_KEY are required for the %s storage backend", cfg.Storage.Backend)
+		}
+	default:
+		log.Fatalf("invalid STORAGE_BACKEND %q", cfg.Storage.Backend)
+	}
+	if cfg.Storage.PresignTTL <= 0 || cfg.Storage.PresignTTL > 7*24*time.Hour {
+		log.Fatalf("STORAGE_PRESIGN_TTL must be positive and at most 7 days")
+	}
+
+	switch cfg.SchemaCheckMode() {
+	case SchemaCheckStrict, SchemaCheckWarn, SchemaCheckOff:
+	default:
+		log.Fatalf("invalid SCHEMA_CHECK %q", cfg.SchemaCheck)
+	}
+
+	switch cfg.MetricsExporter() {
+	case MetricsExporterOTLP:
+		if cfg.OTLPEndpoint == "" {
+			log.Fatalf("OTLP_ENDPOINT is required for the otlp metrics exporter")
+		}
+	case MetricsExporterDogStatsD, MetricsExporterPrometheus, MetricsExporterNone:
+	default:
+		log.Fatalf("invalid METRICS_EXPORTER %q", cfg.Metrics.Exporter)
+	}
+	if cfg.RequestLog.BodyLimit < 0 || cfg.RequestLog.BodyLimit > 4096 {
+		log.Fatalf("REQUEST_LOG_BODY_LIMIT must be between 0 and 4096")
+	}
+	if cfg.DBRetryAfter < time.Second {
+		log.Fatalf("DB_RETRY_AFTER must be at least 1s")
+	}
+	if cfg.Metrics.Interval <= 0 {
+		log.Fatalf("METRICS_EXPORT_INTERVAL must be positive")
+	}
+	if cfg.Shadow.SampleRate < 0 || cfg.Shadow.SampleRate > 1 {
+		log.Fatalf("SHADOW_SAMPLE_RATE must be between 0 and 1")
+	}
+	if cfg.Shadow.DatabaseURL != "" && (cfg.Shadow.Timeout <= 0 || cfg.Shadow.MaxInFlight <= 0) {
+		log.Fatalf("SHADOW_TIMEOUT and SHADOW_MAX_IN_FLIGHT must be positive")
+	}
+	if cfg.Replicas.Hedge && len(cfg.Replicas.DatabaseURLs) < 2 {
+		log.Fatalf("REPLICA_HEDGE requires at least two REPLICA_DATABASE_URLS")
+	}
+	if cfg.Replicas.HedgeDelay < 0 {
+		log.Fatalf("REPLICA_HEDGE_DELAY must not be negative")
+	}
+	if cfg.Region.Primary != "" && (cfg.Region.Name == "" || cfg.Region.ForwardSecret == "") {
+		log.Fatalf("REGION and REGION_FORWARD_SECRET are required with PRIMARY_REGION")
+	}
+	if !cfg.Region.IsPrimary() {
+		if u, err := url.Parse(cfg.Region.PrimaryURL); err != nil || u.Scheme == "" || u.Host == "" {
+			log.Fatalf("PRIMARY_REGION_URL must be an absolute URL outside the primary region")
+		}
+	}
+	if cfg.CatalogLimits.MaxConcurrent < -1 || cfg.CatalogLimits.Queue < 0 || cfg.CatalogLimits.MaxWait <= 0 {
+		log.Fatalf("CATALOG_MAX_CONCURRENT_REQUESTS must be -1 or more, CATALOG_REQUEST_QUEUE must not be negative and CATALOG_REQUEST_MAX_WAIT must be positive")
+	}
+	if cfg.PublicCatalog.MaxAge < 0 {
+		log.Fatalf("PUBLIC_CATALOG_MAX_AGE must not be negative")
+	}
+	if cfg.OrderExports.SyncLimit < 0 || cfg.OrderExports.Interval <= 0 {
+		log.Fatalf("ORDER_EXPORT_SYNC_LIMIT must not be negative and ORDER_EXPORT_INTERVAL must be positive")
+	}
+	if cfg.Announcements.BatchSize <= 0 || cfg.Announcements.PollInterval <= 0 || cfg.Announcements.Lease <= 0 {
+		log.Fatalf("ANNOUNCEMENT_BATCH_SIZE, ANNOUNCEMENT_POLL_INTERVAL and ANNOUNCEMENT_LEASE must be positive")
+	}
+
+	for _, cidr := range cfg.HTTPServer.TrustedProxies {
+		if _, err := netip.ParsePrefix(strings.TrimSpace(cidr)); err != nil {
+			log.Fatalf("invalid TRUSTED_PROXIES CIDR %q: %v", cidr, err)
+		}
+	}
+
+	switch cfg.RateLimits.Backend {
+	case "memory":
+	case "redis":
+		if cfg.RateLimits.RedisURL == "" {
+			log.Fatalf("REDIS_URL is required for the redis rate limit backend")
+		}
+	default:
+		log.Fatalf("invalid RATE_LIMIT_BACKEND %q", cfg.RateLimits.Backend)
+	}
+	if cfg.RateLimits.Requests < 0 || cfg.RateLimits.Period <= 0 || cfg.RateLimits.AuthRequests < 0 || cfg.RateLimits.AuthPeriod <= 0 {
+		log.Fatalf("RATE_LIMIT_REQUESTS and AUTH_RATE_LIMIT_REQUESTS must not be negative, RATE_LIMIT_PERIOD and AUTH_RATE_LIMIT_PERIOD must be positive")
+	}
+
+	if cfg.Checkout.MinLineQuantity < 0 || cfg.Checkout.MaxLineQuantity < 0 ||
+		(cfg.Checkout.MaxLineQuantity > 0 && cfg.Checkout.MinLineQuantity > cfg.Checkout.MaxLineQuantity) {
+		log.Fatalf("CHECKOUT_MIN_LINE_QUANTITY and CHECKOUT_MAX_LINE_QUANTITY must not be negative, and the minimum must not exceed the maximum")
+	}
+	if cfg.Checkout.DuplicateWindow < 0 {
+		log.Fatalf("CHECKOUT_DUPLICATE_WINDOW must not be negative")
+	}
+	for option, fee := range cfg.OrderOptions.Fees {
+		if fee < 0 {
+			log.Fatalf("ORDER_OPTION_FEES of %q must not be negative", option)
+		}
+	}
+	if cfg.Accounting.BatchSize <= 0 || cfg.Accounting.PollInterval <= 0 {
+		log.Fatalf("ACCOUNTING_BATCH_SIZE and ACCOUNTING_POLL_INTERVAL must be positive")
+	}
+	if cfg.Payments.PendingTimeout <= 0 || cfg.Payments.ExpiryPollInterval <= 0 {
+		log.Fatalf("PAYMENT_PENDING_TIMEOUT and PAYMENT_EXPIRY_POLL_INTERVAL must be positive")
+	}
+	if cfg.StockSnapshots.PollInterval <= 0 {
+		log.Fatalf("STOCK_SNAPSHOT_POLL_INTERVAL must be positive")
+	}
+	if cfg.IdempotencyCleanup <= 0 {
+		log.Fatalf("IDEMPOTENCY_CLEANUP_INTERVAL must be positive")
+	}
+	if cfg.ChangeLog.Retention <= 0 || cfg.ChangeLog.CleanupInterval <= 0 {
+		log.Fatalf("CHANGE_LOG_RETENTION and CHANGE_LOG_CLEANUP_INTERVAL must be positive")
+	}
+	if cfg.BulkOperations.BatchSize <= 0 || cfg.BulkOperations.PollInterval <= 0 {
+		log.Fatalf("BULK_OPERATION_BATCH_SIZE and BULK_OPERATION_POLL_INTERVAL must be positive")
+	}
+	for name, rates := range map[string]map[string]float64{
+		"ACCOUNTING_TAX_RATES":   cfg.Accounting.TaxRates,
+		"ACCOUNTING_FEE_PERCENT": cfg.Accounting.FeePercent,
+		"ACCOUNTING_FEE_FIXED":   cfg.Accounting.FeeFixed,
+	} {
+		for key, rate := range rates {
+			if rate < 0 {
+				log.Fatalf("%s of %q must not be negative", name, key)
+			}
+		}
+	}
+	if cfg.LoginLockout.MaxFailures < 0 || cfg.LoginLockout.Duration <= 0 {
+		log.Fatalf("LOGIN_MAX_FAILURES must not be negative and LOGIN_LOCKOUT must be positive")
+	}
+
+	for name, ratio := range map[string]string{
+		"TRACE_SAMPLE_RATIO":        cfg.Tracing.SampleRatio,
+		"SENTRY_TRACES_SAMPLE_RATE": cfg.Tracing.SentrySampleRatio,
+	} {
+		if _, err := parseRatio(ratio); ratio != "" && err != nil {
+			log.Fatalf("invalid %s: %v", name, err)
+		}
+	}
+
+	if cfg.SMS.TwilioAccountSID != "" && (cfg.SMS.TwilioAuthToken == "" || len(cfg.SMS.Senders) == 0) {
+		log.Fatalf("TWILIO_AUTH_TOKEN and SMS_SENDERS are required for Twilio")
+	}
+
+	switch cfg.SwaggerMode() {
+	case SwaggerModePublic, SwaggerModeAdmin, SwaggerModeDisabled:
+	case SwaggerModeBasic:
+		if cfg.Swagger.Username == "" || cfg.Swagger.Password == "" {
+			log.Fatalf("SWAGGER_USERNAME and SWAGGER_PASSWORD are required for basic swagger mode")
+		}
+	default:
+		log.Fatalf("invalid SWAGGER_MODE %q", cfg.Swagger.Mode)
+	}
+
 	return &cfg
 }
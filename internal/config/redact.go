@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Redaction modes of the redact struct tag.
+const (
+	redactValue = "value" // The whole value is masked; maps keep their keys
+	redactURL   = "url"   // The password of URLs is masked, values that are not URLs are masked entirely
+)
+
+// redactedValue replaces masked secrets.
+const redactedValue = "[REDACTED]"
+
+// Redacted returns the effective configuration by environment variable name, with secrets masked.
+// Values are formatted as they are written in the environment, e.g. "30s" or "admin:[REDACTED],scim:[REDACTED]".
+// Unset secrets stay empty, so operators can tell whether a secret is set.
+func (c *Config) Redacted() map[string]string {
+	values := make(map[string]string)
+	redactStruct(reflect.ValueOf(c).Elem(), values)
+	return values
+}
+
+// redactStruct adds the fields of the struct to values, descending into nested settings.
+func redactStruct(v reflect.Value, values map[string]string) {
+	for i := range v.NumField() {
+		field, value := v.Type().Field(i), v.Field(i)
+		name := field.Tag.Get("env")
+		if name == "" {
+			if value.Kind() == reflect.Struct {
+				redactStruct(value, values)
+			}
+			continue
+		}
+		values[name] = formatValue(value, field.Tag.Get("redact"))
+	}
+}
+
+// formatValue formats the value in the environment variable format, masking it as the redaction mode requires.
+func formatValue(v reflect.Value, redact string) string {
+	switch v.Kind() {
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		slices.Sort(keys)
+		items := make([]string, len(keys))
+		for i, key := range keys {
+			items[i] = key + ":" + formatValue(v.MapIndex(reflect.ValueOf(key)), redact)
+		}
+		return strings.Join(items, ",")
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatValue(v.Index(i), redact)
+		}
+		return strings.Join(items, ",")
+	}
+
+	var s string
+	if d, ok := v.Interface().(time.Duration); ok {
+		s = d.String()
+	} else {
+		s = fmt.Sprint(v.Interface())
+	}
+	if s == "" {
+		return s
+	}
+	switch redact {
+	case redactValue:
+		return redactedValue
+	case redactURL:
+		u, err := url.Parse(s)
+		if err != nil || u.Scheme == "" {
+			// e.g. keyword/value connection strings: "host=db password=secret"
+			return redactedValue
+		}
+		return u.Redacted()
+	}
+	return s
+}
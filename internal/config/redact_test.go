@@ -0,0 +1,57 @@
+package config_test
+
+import (
+	"product-api/internal/config"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Redacted(t *testing.T) {
+	cfg := config.Config{
+		DatabaseURL:     "postgres://app:s3cret@db:5432/shop?sslmode=disable",
+		JWTSecret:       "jwt-secret",
+		JWTTTL:          24 * time.Hour,
+		APIKeys:         map[string]string{"scim": "key2", "admin": "key1"},
+		FiscalYearStart: 4,
+		AdminEmails:     []string{"ops@example.com", "finance@example.com"},
+		Reporting:       config.Reporting{DatabaseURL: "host=replica user=app password=s3cret"},
+		Storage:         config.Storage{Backend: "s3", PathStyle: true},
+	}
+
+	values := cfg.Redacted()
+	assert.Equal(t, "postgres://app:xxxxx@db:5432/shop?sslmode=disable", values["DATABASE_URL"])
+	assert.Equal(t, "[REDACTED]", values["REPORTING_DATABASE_URL"], "connection strings that are not URLs are masked entirely")
+	assert.Equal(t, "[REDACTED]", values["JWT_SECRET"])
+	assert.Equal(t, "admin:[REDACTED],scim:[REDACTED]", values["API_KEYS"])
+	assert.Equal(t, "", values["PAYPAL_CLIENT_SECRET"], "unset secrets stay empty")
+	assert.Equal(t, "24h0m0s", values["JWT_TTL"])
+	assert.Equal(t, "4", values["FISCAL_YEAR_START_MONTH"])
+	assert.Equal(t, "ops@example.com,finance@example.com", values["ADMIN_EMAILS"])
+	assert.Equal(t, "s3", values["STORAGE_BACKEND"], "nested settings")
+	assert.Equal(t, "true", values["STORAGE_PATH_STYLE"])
+}
+
+// Settings named like secrets must be redacted, so new secrets cannot be exposed by accident.
+func TestConfig_SecretsAreRedacted(t *testing.T) {
+	secret := regexp.MustCompile(`SECRET|PASSWORD|TOKEN|API_KEYS|DATABASE_URL|DSN`)
+
+	var check func(reflect.Type)
+	check = func(typ reflect.Type) {
+		for i := range typ.NumField() {
+			field := typ.Field(i)
+			name := field.Tag.Get("env")
+			if name == "" && field.Type.Kind() == reflect.Struct {
+				check(field.Type)
+				continue
+			}
+			if secret.MatchString(name) {
+				assert.NotEmpty(t, field.Tag.Get("redact"), "%s must have a redact tag", name)
+			}
+		}
+	}
+	check(reflect.TypeOf(config.Config{}))
+}
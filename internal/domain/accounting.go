@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Accounts of the payment journal. Exports map them to the accounts of the ledger they are imported into.
+const (
+	AccountPaymentClearing = "payment_clearing" // Money received for orders, until providers pay it out
+	AccountRevenue         = "revenue"          // Sales net of the included tax
+	AccountRefunds         = "refunds"          // Refunded sales net of the included tax
+	AccountSalesTax        = "sales_tax"        // Tax included in prices, owed to the tax authority
+	AccountPaymentFees     = "payment_fees"     // Fees payment providers deduct from charges
+)
+
+// Accounts lists the accounts of the payment journal.
+var Accounts = []string{AccountPaymentClearing, AccountRevenue, AccountRefunds, AccountSalesTax, AccountPaymentFees}
+
+// AccountingEntry is a line of the double-entry journal of a payment. The lines of a payment balance:
+// their debits add up to their credits.
+type AccountingEntry struct {
+	ID          uuid.UUID
+	PaymentID   uuid.UUID
+	OrderID     uuid.UUID
+	OrderNumber string
+	Line        int // Position in the journal of the payment, starting at 1
+	Account     string
+	Debit       float64
+	Credit      float64
+	Currency    string // ISO 4217 code of the order amounts
+	Description string
+	PostedAt    time.Time // Time of the payment
+}
+
+// FeeSchedule is the fee a payment provider deducts from each charge.
+type FeeSchedule struct {
+	Percent float64 // Share of the charged amount, in percent
+	Fixed   float64 // Fixed amount per charge
+}
+
+// Fee returns the fee of a charge of the amount, at most the amount.
+func (f FeeSchedule) Fee(amount float64) float64 {
+	return min(RoundCents(amount*f.Percent/100+f.Fixed), amount)
+}
+
+// IncludedTax returns the tax included in a gross amount at the rate, in percent.
+func IncludedTax(amount, rate float64) float64 {
+	return RoundCents(amount * rate / (100 + rate))
+}
+
+// JournalPayment returns the balanced journal of a charge or refund of the order, in the order's currency.
+// Prices include tax at taxRate, in percent. The fee of a charge is deducted from the money received;
+// refunds return no fees.
+//
+// A charge debits the money received net of the fee to payment clearing and the fee to payment fees,
+// and credits the amount net of tax to revenue and the tax to sales tax. A refund debits refunds and sales tax,
+// and credits payment clearing.
+func JournalPayment(order *Order, p *Payment, taxRate, fee float64) []AccountingEntry {
+	tax := IncludedTax(p.Amount, taxRate)
+	net := RoundCents(p.Amount - tax)
+	reference := order.Number
+	if reference == "" {
+		reference = order.ID.String() // Orders placed before orders were numbered
+	}
+
+	var entries []AccountingEntry
+	add := func(account string, debit, credit float64) {
+		if debit == 0 && credit == 0 {
+			return
+		}
+		entries = append(entries, AccountingEntry{
+			ID:          uuid.New(),
+			PaymentID:   p.ID,
+			OrderID:     order.ID,
+			OrderNumber: order.Number,
+			Line:        len(entries) + 1,
+			Account:     account,
+			Debit:       debit,
+			Credit:      credit,
+			Currency:    order.Currency,
+			Description: "Order " + reference + " " + p.Kind + " (" + p.Method + ")",
+			PostedAt:    p.CreatedAt,
+		})
+	}
+
+	if p.Kind == PaymentKindRefund {
+		add(AccountRefunds, net, 0)
+		add(AccountSalesTax, tax, 0)
+		add(AccountPaymentClearing, 0, p.Amount)
+		return entries
+	}
+	add(AccountPaymentClearing, RoundCents(p.Amount-fee), 0)
+	add(AccountPaymentFees, fee, 0)
+	add(AccountRevenue, 0, net)
+	add(AccountSalesTax, 0, tax)
+	return entries
+}
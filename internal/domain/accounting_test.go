@@ -0,0 +1,78 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// journalLine is the account, debit and credit of a journal entry.
+type journalLine struct {
+	account       string
+	debit, credit float64
+}
+
+func journalLines(entries []domain.AccountingEntry) []journalLine {
+	lines := make([]journalLine, len(entries))
+	for i, e := range entries {
+		lines[i] = journalLine{e.Account, e.Debit, e.Credit}
+	}
+	return lines
+}
+
+func TestJournalPayment(t *testing.T) {
+	order := &domain.Order{Number: "ORD-2024-000123", OrderSettings: domain.OrderSettings{Currency: "EUR"}}
+	card := charge(domain.PaymentMethodCard, 119)
+	fee := domain.FeeSchedule{Percent: 1.5, Fixed: 0.25}.Fee(card.Amount)
+	assert.Equal(t, 2.04, fee)
+
+	entries := domain.JournalPayment(order, &card, 19, fee)
+	assert.Equal(t, []journalLine{
+		{domain.AccountPaymentClearing, 116.96, 0},
+		{domain.AccountPaymentFees, 2.04, 0},
+		{domain.AccountRevenue, 0, 100},
+		{domain.AccountSalesTax, 0, 19},
+	}, journalLines(entries))
+	for i, e := range entries {
+		assert.Equal(t, i+1, e.Line)
+		assert.Equal(t, card.ID, e.PaymentID)
+		assert.Equal(t, "EUR", e.Currency)
+		assert.Equal(t, "Order ORD-2024-000123 charge (card)", e.Description)
+	}
+
+	// Refunds return the tax, not the fee
+	partial := refund(card, 59.5)
+	entries = domain.JournalPayment(order, &partial, 19, 0)
+	assert.Equal(t, []journalLine{
+		{domain.AccountRefunds, 50, 0},
+		{domain.AccountSalesTax, 9.5, 0},
+		{domain.AccountPaymentClearing, 0, 59.5},
+	}, journalLines(entries))
+
+	// Zero amounts are left out
+	cash := charge(domain.PaymentMethodCash, 10)
+	entries = domain.JournalPayment(order, &cash, 0, 0)
+	assert.Equal(t, []journalLine{
+		{domain.AccountPaymentClearing, 10, 0},
+		{domain.AccountRevenue, 0, 10},
+	}, journalLines(entries))
+}
+
+func TestJournalPayment_Balances(t *testing.T) {
+	order := &domain.Order{Number: "ORD-2024-000124"}
+	for _, amount := range []float64{0.01, 0.99, 3.33, 17.45, 1234.56} {
+		for _, rate := range []float64{0, 7.25, 19, 20} {
+			card := charge(domain.PaymentMethodCard, amount)
+			fee := domain.FeeSchedule{Percent: 2.9, Fixed: 0.30}.Fee(amount)
+			for _, p := range []domain.Payment{card, refund(card, amount)} {
+				var debits, credits float64
+				for _, e := range domain.JournalPayment(order, &p, rate, fee) {
+					debits += e.Debit
+					credits += e.Credit
+				}
+				assert.Equal(t, domain.RoundCents(debits), domain.RoundCents(credits), "%s of %.2f at %.2f%%", p.Kind, amount, rate)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Announcement statuses.
+const (
+	AnnouncementQueued    = "queued"    // Waiting for the first batch
+	AnnouncementSending   = "sending"   // Batches are being sent
+	AnnouncementCompleted = "completed" // Every user of the segment was processed
+)
+
+// ErrInvalidSegment is returned when the criteria of an audience segment contradict each other or are unknown.
+var ErrInvalidSegment = errors.New("invalid audience segment")
+
+// AudienceSegment selects the active users an announcement is sent to. Empty criteria match every user.
+type AudienceSegment struct {
+	RegisteredAfter  *time.Time `json:",omitempty"` // Users registered at or after this time
+	RegisteredBefore *time.Time `json:",omitempty"` // Users registered before this time
+	ActiveSince      *time.Time `json:",omitempty"` // Users who logged in at or after this time
+	InactiveSince    *time.Time `json:",omitempty"` // Users who have not logged in since this time, including those who never did
+	Roles            []string   `json:",omitempty"` // Customer groups: users with any of these roles
+}
+
+// Validate checks that the segment's time ranges are not empty and its roles exist.
+func (s *AudienceSegment) Validate() error {
+	if s.RegisteredAfter != nil && s.RegisteredBefore != nil && !s.RegisteredAfter.Before(*s.RegisteredBefore) {
+		return fmt.Errorf("%w: registered after must be before registered before", ErrInvalidSegment)
+	}
+	if s.ActiveSince != nil && s.InactiveSince != nil && !s.ActiveSince.Before(*s.InactiveSince) {
+		return fmt.Errorf("%w: active since must be before inactive since", ErrInvalidSegment)
+	}
+	for _, role := range s.Roles {
+		if !IsValidRole(role) {
+			return fmt.Errorf("%w: unknown role %q", ErrInvalidSegment, role)
+		}
+	}
+	return nil
+}
+
+// AnnouncementStats counts the users an announcement was processed for.
+type AnnouncementStats struct {
+	Recipients int // Users of the segment processed so far
+	Sent       int // Users the announcement was delivered to on every channel they opted in to
+	OptedOut   int // Users who opted out of marketing notifications on every channel, they are not sent anything
+	Failed     int // Users whose delivery failed on at least one channel
+}
+
+// Announcement is a templated message sent to a segment of users in batches.
+// Users are processed in ID order, Cursor is the last processed user.
+type Announcement struct {
+	ID          uuid.UUID
+	Template    string            // Notification template of the message
+	Data        map[string]string // Template variables, besides those of the recipient
+	Segment     AudienceSegment
+	Status      string
+	Cursor      uuid.UUID // Last processed user ID, nil before the first batch
+	Stats       AnnouncementStats
+	CreatedAt   time.Time
+	CompletedAt *time.Time // Set once every user of the segment was processed
+	LockedUntil *time.Time `json:"-"` // End of the lease of the instance sending the next batch, nil if not leased
+}
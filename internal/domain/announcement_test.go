@@ -0,0 +1,37 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudienceSegment_Validate(t *testing.T) {
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name    string
+		segment domain.AudienceSegment
+		wantErr bool
+	}{
+		{name: "everyone", segment: domain.AudienceSegment{}},
+		{name: "registered in january", segment: domain.AudienceSegment{RegisteredAfter: &jan, RegisteredBefore: &feb}},
+		{name: "active customers", segment: domain.AudienceSegment{ActiveSince: &jan, Roles: []string{domain.RoleCustomer}}},
+		{name: "empty registration range", segment: domain.AudienceSegment{RegisteredAfter: &feb, RegisteredBefore: &jan}, wantErr: true},
+		{name: "active and inactive", segment: domain.AudienceSegment{ActiveSince: &jan, InactiveSince: &jan}, wantErr: true},
+		{name: "unknown role", segment: domain.AudienceSegment{Roles: []string{"vip"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.segment.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidSegment)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
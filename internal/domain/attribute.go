@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+var (
+	// ErrInvalidAttributes is returned when product attributes do not match the definitions of its category.
+	ErrInvalidAttributes = errors.New("invalid product attributes")
+	// ErrInvalidAttributeDefinition is returned when an attribute definition is incomplete or inconsistent.
+	ErrInvalidAttributeDefinition = errors.New("invalid attribute definition")
+)
+
+// Attribute value types.
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeInteger = "integer"
+	AttributeTypeBoolean = "boolean"
+	AttributeTypeEnum    = "enum" // String from AllowedValues
+)
+
+// AttributeDefinition describes a typed attribute of products in a category, e.g. "screen_size" of "monitors".
+type AttributeDefinition struct {
+	Category      string
+	Name          string
+	Type          string
+	AllowedValues []string // Values of enum attributes
+	Unit          string   // Unit of number and integer values, e.g. "in" or "kg"
+	Required      bool
+}
+
+// Validate checks that the definition has a known type and enum attributes have allowed values.
+func (d *AttributeDefinition) Validate() error {
+	switch {
+	case d.Category == "" || d.Name == "":
+		return fmt.Errorf("%w: category and name are required", ErrInvalidAttributeDefinition)
+	case d.Type == AttributeTypeEnum && len(d.AllowedValues) == 0:
+		return fmt.Errorf("%w: enum attribute %q needs allowed values", ErrInvalidAttributeDefinition, d.Name)
+	case d.Type != AttributeTypeEnum && len(d.AllowedValues) > 0:
+		return fmt.Errorf("%w: only enum attributes have allowed values", ErrInvalidAttributeDefinition)
+	case d.Unit != "" && d.Type != AttributeTypeNumber && d.Type != AttributeTypeInteger:
+		return fmt.Errorf("%w: only numeric attributes have units", ErrInvalidAttributeDefinition)
+	}
+	switch d.Type {
+	case AttributeTypeString, AttributeTypeNumber, AttributeTypeInteger, AttributeTypeBoolean, AttributeTypeEnum:
+		return nil
+	}
+	return fmt.Errorf("%w: unknown type %q", ErrInvalidAttributeDefinition, d.Type)
+}
+
+// Accepts reports whether the value, as decoded from JSON, matches the attribute type.
+func (d *AttributeDefinition) Accepts(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return d.Type == AttributeTypeString || d.Type == AttributeTypeEnum && slices.Contains(d.AllowedValues, v)
+	case float64:
+		return d.Type == AttributeTypeNumber || d.Type == AttributeTypeInteger && v == math.Trunc(v)
+	case bool:
+		return d.Type == AttributeTypeBoolean
+	}
+	return false
+}
+
+// ValidateAttributes checks product attributes against the definitions of its category:
+// every attribute must be defined and have a value of its type, and required attributes must be set.
+func ValidateAttributes(attributes map[string]any, definitions []AttributeDefinition) error {
+	defined := make(map[string]*AttributeDefinition, len(definitions))
+	for i := range definitions {
+		defined[definitions[i].Name] = &definitions[i]
+	}
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names) // Report the same error for the same input
+	for _, name := range names {
+		d, ok := defined[name]
+		switch {
+		case !ok:
+			return fmt.Errorf("%w: attribute %q is not defined for the category", ErrInvalidAttributes, name)
+		case !d.Accepts(attributes[name]):
+			return fmt.Errorf("%w: attribute %q must be %s", ErrInvalidAttributes, name, d.describe())
+		}
+	}
+	for _, d := range definitions {
+		if _, ok := attributes[d.Name]; d.Required && !ok {
+			return fmt.Errorf("%w: attribute %q is required", ErrInvalidAttributes, d.Name)
+		}
+	}
+	return nil
+}
+
+// describe returns the expected values of the attribute for error messages.
+func (d *AttributeDefinition) describe() string {
+	switch {
+	case d.Type == AttributeTypeEnum:
+		return fmt.Sprintf("one of %v", d.AllowedValues)
+	case d.Type == AttributeTypeInteger && d.Unit != "":
+		return "an integer in " + d.Unit
+	case d.Type == AttributeTypeInteger:
+		return "an integer"
+	case d.Unit != "":
+		return fmt.Sprintf("a %s in %s", d.Type, d.Unit)
+	}
+	return "a " + d.Type
+}
@@ -0,0 +1,43 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAttributes(t *testing.T) {
+	definitions := []domain.AttributeDefinition{
+		{Category: "monitors", Name: "screen_size", Type: domain.AttributeTypeNumber, Unit: "in", Required: true},
+		{Category: "monitors", Name: "ports", Type: domain.AttributeTypeInteger},
+		{Category: "monitors", Name: "panel", Type: domain.AttributeTypeEnum, AllowedValues: []string{"ips", "va"}},
+		{Category: "monitors", Name: "curved", Type: domain.AttributeTypeBoolean},
+	}
+
+	valid := map[string]any{"screen_size": 27.5, "ports": 3.0, "panel": "ips", "curved": false}
+	assert.NoError(t, domain.ValidateAttributes(valid, definitions))
+
+	for name, attributes := range map[string]map[string]any{
+		"missing required": {"ports": 3.0},
+		"undefined":        {"screen_size": 27.0, "weight": 5.0},
+		"wrong type":       {"screen_size": "27in"},
+		"fractional":       {"screen_size": 27.0, "ports": 2.5},
+		"not allowed":      {"screen_size": 27.0, "panel": "oled"},
+	} {
+		assert.ErrorIs(t, domain.ValidateAttributes(attributes, definitions), domain.ErrInvalidAttributes, name)
+	}
+}
+
+func TestAttributeDefinition_Validate(t *testing.T) {
+	assert.NoError(t, (&domain.AttributeDefinition{Category: "shirts", Name: "size", Type: domain.AttributeTypeEnum, AllowedValues: []string{"s", "m"}}).Validate())
+
+	for name, d := range map[string]domain.AttributeDefinition{
+		"unknown type":        {Category: "shirts", Name: "size", Type: "date"},
+		"enum without values": {Category: "shirts", Name: "size", Type: domain.AttributeTypeEnum},
+		"values of string":    {Category: "shirts", Name: "size", Type: domain.AttributeTypeString, AllowedValues: []string{"s"}},
+		"unit of boolean":     {Category: "shirts", Name: "organic", Type: domain.AttributeTypeBoolean, Unit: "kg"},
+	} {
+		assert.ErrorIs(t, d.Validate(), domain.ErrInvalidAttributeDefinition, name)
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidBarcode is returned when a barcode is not a valid EAN-8, UPC-A, EAN-13 or GTIN-14 code.
+var ErrInvalidBarcode = errors.New("invalid barcode")
+
+// NormalizeBarcode validates an EAN-8, UPC-A, EAN-13 or GTIN-14 barcode, including its check digit.
+// UPC-A codes are returned as EAN-13 with a leading zero, so a product is found whichever way it is scanned.
+func NormalizeBarcode(code string) (string, error) {
+	switch len(code) {
+	case 8, 13, 14:
+	case 12:
+		code = "0" + code
+	default:
+		return "", fmt.Errorf("%w: %q must have 8, 12, 13 or 14 digits", ErrInvalidBarcode, code)
+	}
+
+	// GS1 check digit: digits are weighted 3 and 1 alternately from the right, excluding the check digit
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		d := code[i]
+		if d < '0' || d > '9' {
+			return "", fmt.Errorf("%w: %q must only contain digits", ErrInvalidBarcode, code)
+		}
+		weight := 1
+		if (len(code)-2-i)%2 == 0 {
+			weight = 3
+		}
+		sum += int(d-'0') * weight
+	}
+	if check := byte('0' + (10-sum%10)%10); code[len(code)-1] != check {
+		return "", fmt.Errorf("%w: %q has a wrong check digit", ErrInvalidBarcode, code)
+	}
+	return code, nil
+}
@@ -0,0 +1,30 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeBarcode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"96385074", "96385074"},             // EAN-8
+		{"4006381333931", "4006381333931"},   // EAN-13
+		{"036000291452", "0036000291452"},    // UPC-A
+		{"10012345000017", "10012345000017"}, // GTIN-14
+	}
+	for _, tt := range tests {
+		got, err := domain.NormalizeBarcode(tt.code)
+		assert.NoError(t, err, tt.code)
+		assert.Equal(t, tt.want, got)
+	}
+
+	for _, code := range []string{"", "1234", "4006381333932", "40063813339x1", "400638133393100"} {
+		_, err := domain.NormalizeBarcode(code)
+		assert.ErrorIs(t, err, domain.ErrInvalidBarcode, code)
+	}
+}
@@ -0,0 +1,56 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"product-api/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BenchmarkEncodeProducts measures JSON encoding of product lists as done by handlers.
+func BenchmarkEncodeProducts(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		products := make([]domain.Product, n)
+		for i := range products {
+			products[i] = domain.Product{
+				ID:          uuid.New(),
+				Description: fmt.Sprintf("Product %d with a reasonably long description", i),
+				Tags:        []string{"audio", "electronics", "wireless"},
+				Quantity:    i,
+				Price:       99.99,
+			}
+		}
+
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if err := json.NewEncoder(io.Discard).Encode(products); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkEncodeOrders measures JSON encoding of order lists with items.
+func BenchmarkEncodeOrders(b *testing.B) {
+	orders := make([]domain.Order, 1000)
+	for i := range orders {
+		items := make([]domain.OrderItem, 5)
+		for j := range items {
+			items[j] = domain.OrderItem{ID: uuid.New(), ProductID: uuid.New(), Quantity: j + 1, PriceAtPurchase: 9.99}
+		}
+		orders[i] = domain.Order{ID: uuid.New(), UserID: uuid.New(), Items: items, CreatedAt: time.Now(), TotalAmount: 149.85}
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := json.NewEncoder(io.Discard).Encode(orders); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
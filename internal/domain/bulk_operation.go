@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Bulk operation kinds.
+const (
+	BulkOpPricePercent = "price_percent" // Change prices by Value percent, e.g. 10 raises them by 10%, -20 lowers them by 20%
+	BulkOpPriceSet     = "price_set"     // Set prices to Value
+	BulkOpStockAdjust  = "stock_adjust"  // Record a stock adjustment of Value units, negative to remove stock
+)
+
+// Bulk operation statuses.
+const (
+	BulkOperationQueued    = "queued"    // Waiting for the first batch
+	BulkOperationRunning   = "running"   // Batches are being applied
+	BulkOperationCompleted = "completed" // Every matching product was processed
+	BulkOperationFailed    = "failed"    // Abandoned after MaxBulkBatchAttempts failed batches in a row
+)
+
+// Bulk operation result statuses.
+const (
+	BulkResultUpdated = "updated"
+	BulkResultFailed  = "failed"
+)
+
+// Bulk operation limits.
+const (
+	MaxBulkPrice           = 99_999_999.99 // Highest price the catalog stores
+	MaxBulkStockAdjustment = 1_000_000     // Most units added or removed per product
+	MaxBulkBatchAttempts   = 5             // Failed attempts of a batch before the operation fails
+)
+
+// ErrInvalidBulkOperation is returned when a bulk operation has an unknown kind, an invalid value or an empty filter.
+var ErrInvalidBulkOperation = errors.New("invalid bulk operation")
+
+// BulkOperationFilter selects the products a bulk operation applies to.
+type BulkOperationFilter struct {
+	Tags     []string `json:",omitempty"` // Products having all of these tags
+	Category string   `json:",omitempty"` // Products of this category
+}
+
+// BulkOperation is a price or stock change applied in the background to all products matching a filter.
+// Products are processed in ID order, Cursor is the last processed product. Bundles have no stock of their own,
+// stock adjustments skip them.
+type BulkOperation struct {
+	ID          uuid.UUID
+	Filter      BulkOperationFilter
+	Kind        string  // price_percent, price_set or stock_adjust
+	Value       float64 // Percent, price or units, depending on the kind
+	Actor       string  // Caller who created the operation, recorded in the product history
+	Status      string
+	Cursor      uuid.UUID // Last processed product ID, nil before the first batch
+	Matched     int       // Products matching the filter when the operation was created
+	Processed   int       // Products processed so far: Updated and Failed add up to it
+	Updated     int
+	Failed      int
+	Attempts    int    // Failed attempts of the next batch
+	Error       string `json:",omitempty"` // Error of the last failed batch
+	CreatedAt   time.Time
+	CompletedAt *time.Time // Set once every matching product was processed
+}
+
+// BulkOperationResult is the outcome of a bulk operation for a product.
+type BulkOperationResult struct {
+	ProductID uuid.UUID
+	Status    string  // updated or failed
+	Error     string  `json:",omitempty"` // Reason of the failure
+	Before    float64 // Price or quantity before the operation
+	After     float64 // Price or quantity after the operation, Before if it failed
+}
+
+// Validate checks that the kind is known, the value is valid for it and the filter selects by tags or category,
+// so a mistake cannot change the whole catalog.
+func (o *BulkOperation) Validate() error {
+	if len(o.Filter.Tags) == 0 && o.Filter.Category == "" {
+		return fmt.Errorf("%w: filter must have tags or a category", ErrInvalidBulkOperation)
+	}
+	switch o.Kind {
+	case BulkOpPricePercent:
+		if o.Value == 0 || o.Value <= -100 || o.Value > 1000 {
+			return fmt.Errorf("%w: percent must be non-zero, above -100 and at most 1000", ErrInvalidBulkOperation)
+		}
+	case BulkOpPriceSet:
+		if o.Value <= 0 || o.Value > MaxBulkPrice {
+			return fmt.Errorf("%w: price must be positive and at most %.2f", ErrInvalidBulkOperation, MaxBulkPrice)
+		}
+	case BulkOpStockAdjust:
+		if o.Value == 0 || o.Value != math.Trunc(o.Value) || math.Abs(o.Value) > MaxBulkStockAdjustment {
+			return fmt.Errorf("%w: stock adjustment must be a non-zero number of units, at most %d", ErrInvalidBulkOperation, MaxBulkStockAdjustment)
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidBulkOperation, o.Kind)
+	}
+	return nil
+}
+
+// IsStockOperation reports whether the operation changes stock rather than prices.
+func (o *BulkOperation) IsStockOperation() bool {
+	return o.Kind == BulkOpStockAdjust
+}
+
+// NewPrice returns the price of a product after a price operation, rounded to cents.
+func (o *BulkOperation) NewPrice(price float64) float64 {
+	if o.Kind == BulkOpPriceSet {
+		return RoundCents(o.Value)
+	}
+	return RoundCents(price * (1 + o.Value/100))
+}
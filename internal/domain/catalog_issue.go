@@ -0,0 +1,38 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Catalog issue rules, the data-quality checks run on draft and active products.
+// Products have no images or variants; bundles are the only products composed of others.
+const (
+	CatalogIssueZeroPrice         = "zero_price"         // Price is not positive, e.g. of products created before prices were validated
+	CatalogIssueNoTags            = "no_tags"            // No tags, tag filters and bulk operations miss the product
+	CatalogIssueNegativeStock     = "negative_stock"     // Stock ledger sums to a negative quantity, e.g. after a missed receipt
+	CatalogIssueInactiveComponent = "inactive_component" // Bundle contains a draft or archived product, still sold as part of it
+)
+
+// CatalogIssueRules lists the catalog issue rules in the order issues of a product are reported.
+var CatalogIssueRules = []string{
+	CatalogIssueInactiveComponent,
+	CatalogIssueNegativeStock,
+	CatalogIssueNoTags,
+	CatalogIssueZeroPrice,
+}
+
+// IsValidCatalogIssueRule reports whether rule is a known catalog issue rule.
+func IsValidCatalogIssueRule(rule string) bool {
+	switch rule {
+	case CatalogIssueZeroPrice, CatalogIssueNoTags, CatalogIssueNegativeStock, CatalogIssueInactiveComponent:
+		return true
+	}
+	return false
+}
+
+// CatalogIssue is a data-quality problem of a product found by a catalog issue rule.
+type CatalogIssue struct {
+	ProductID   uuid.UUID
+	Description string // Product description, to recognize the product
+	Status      string // draft or active
+	Rule        string
+	Detail      string `json:",omitempty"` // E.g. the ledger quantity or the inactive components
+}
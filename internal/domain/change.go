@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Change feed entities.
+const (
+	ChangeEntityProduct   = "product"
+	ChangeEntityOrder     = "order"
+	ChangeEntityOrderItem = "order_item"
+	ChangeEntityUser      = "user"
+)
+
+// Change feed operations.
+const (
+	ChangeOperationInsert = "insert"
+	ChangeOperationUpdate = "update"
+	ChangeOperationDelete = "delete"
+)
+
+// Change is a mutation of a product, order, order item or user in the change feed.
+type Change struct {
+	Sequence  int64 // Identifies the change, not assigned in feed order
+	Entity    string
+	EntityID  uuid.UUID
+	Operation string         // insert, update or delete
+	Data      map[string]any // Database row after the change without credentials, null for deletes
+	ChangedAt time.Time
+}
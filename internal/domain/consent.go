@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Legal document types that require user consent.
+const (
+	DocumentTypeTerms   = "terms"
+	DocumentTypePrivacy = "privacy"
+)
+
+// LegalDocument represents a published version of a legal document (terms of service, privacy policy).
+type LegalDocument struct {
+	ID          uuid.UUID
+	Type        string
+	Version     string
+	PublishedAt time.Time
+}
+
+// Consent represents a user's acceptance of a specific legal document version.
+type Consent struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	DocumentType    string
+	DocumentVersion string
+	AcceptedAt      time.Time
+}
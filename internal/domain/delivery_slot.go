@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidDeliverySlot is returned when a delivery slot has no region, an empty window or a negative capacity.
+var ErrInvalidDeliverySlot = errors.New("invalid delivery slot")
+
+// DeliverySlot is a delivery window in a region with the number of orders that can be delivered in it.
+// Checkouts choosing the slot reserve one unit of its capacity, cancelling the order releases it.
+// Only orders placed in the slot's region can choose it.
+type DeliverySlot struct {
+	ID        uuid.UUID
+	Region    string    `example:"eu-west-1"` // Deployment region taking the slot's orders, see OrderSettings.Region
+	StartsAt  time.Time `example:"2026-03-14T09:00:00Z"`
+	EndsAt    time.Time `example:"2026-03-14T12:00:00Z"`
+	Capacity  int       `example:"20"` // Orders deliverable in the window
+	Reserved  int       `example:"7"`  // Orders of the window that are not cancelled
+	CreatedAt time.Time
+}
+
+// Available returns the number of orders the slot can still take.
+func (s *DeliverySlot) Available() int {
+	return max(s.Capacity-s.Reserved, 0)
+}
+
+// Validate checks that the slot has a region, ends after it starts and has no negative capacity.
+func (s *DeliverySlot) Validate() error {
+	if s.Region == "" {
+		return fmt.Errorf("%w: region is required", ErrInvalidDeliverySlot)
+	}
+	if !s.StartsAt.Before(s.EndsAt) {
+		return fmt.Errorf("%w: window must end after it starts", ErrInvalidDeliverySlot)
+	}
+	if s.Capacity < 0 {
+		return fmt.Errorf("%w: capacity must not be negative", ErrInvalidDeliverySlot)
+	}
+	return nil
+}
+
+// OrderDelivery is the delivery window chosen for an order at checkout.
+type OrderDelivery struct {
+	SlotID   uuid.UUID
+	Region   string    `example:"eu-west-1"`
+	StartsAt time.Time `example:"2026-03-14T09:00:00Z"`
+	EndsAt   time.Time `example:"2026-03-14T12:00:00Z"`
+}
+
+// Delivery returns the slot as the delivery window of an order.
+func (s *DeliverySlot) Delivery() *OrderDelivery {
+	return &OrderDelivery{SlotID: s.ID, Region: s.Region, StartsAt: s.StartsAt, EndsAt: s.EndsAt}
+}
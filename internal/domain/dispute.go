@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Dispute statuses.
+const (
+	DisputeStatusOpen = "open" // Under review by the payment provider
+	DisputeStatusWon  = "won"  // Resolved in favour of the merchant
+	DisputeStatusLost = "lost" // Resolved in favour of the buyer, the amount was charged back
+)
+
+// ErrPaymentDisputed is returned when refunding a charge with an open or lost dispute.
+var ErrPaymentDisputed = errors.New("payment is disputed")
+
+// Dispute is a chargeback or dispute of an order's charge opened by the buyer with the payment provider.
+// While it is open, refunds of the charge are frozen. A lost dispute already returned the money to the buyer.
+type Dispute struct {
+	ID                uuid.UUID
+	OrderID           uuid.UUID
+	PaymentID         uuid.UUID // Disputed charge
+	Provider          string
+	ProviderDisputeID string
+	Status            string
+	Reason            string // Provider reason code
+	Amount            float64
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// DisputeStatus returns the dispute status of the order: open if any dispute is open,
+// otherwise lost if any dispute was lost, won if all were won, and empty if there are none.
+func (o *Order) DisputeStatus() string {
+	var status string
+	for _, d := range o.Disputes {
+		switch {
+		case d.Status == DisputeStatusOpen:
+			return DisputeStatusOpen
+		case d.Status == DisputeStatusLost, status == "":
+			status = d.Status
+		}
+	}
+	return status
+}
+
+// IsRefundFrozen reports whether refunds of the charge are frozen by an open or lost dispute.
+func (o *Order) IsRefundFrozen(chargeID uuid.UUID) bool {
+	for _, d := range o.Disputes {
+		if d.PaymentID == chargeID && d.Status != DisputeStatusWon {
+			return true
+		}
+	}
+	return false
+}
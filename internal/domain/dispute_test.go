@@ -0,0 +1,45 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrder_DisputeStatus(t *testing.T) {
+	order := &domain.Order{}
+	assert.Empty(t, order.DisputeStatus())
+
+	order.Disputes = []domain.Dispute{{Status: domain.DisputeStatusWon}}
+	assert.Equal(t, domain.DisputeStatusWon, order.DisputeStatus())
+
+	order.Disputes = append(order.Disputes, domain.Dispute{Status: domain.DisputeStatusLost})
+	assert.Equal(t, domain.DisputeStatusLost, order.DisputeStatus())
+
+	order.Disputes = append(order.Disputes, domain.Dispute{Status: domain.DisputeStatusOpen})
+	assert.Equal(t, domain.DisputeStatusOpen, order.DisputeStatus())
+}
+
+func TestOrder_DisputedRefundIsFrozen(t *testing.T) {
+	card := charge(domain.PaymentMethodCard, 30)
+	cash := charge(domain.PaymentMethodCash, 20)
+	order := &domain.Order{
+		Status:      domain.OrderStatusPaid,
+		TotalAmount: 50,
+		Payments:    []domain.Payment{card, cash},
+		Disputes:    []domain.Dispute{{PaymentID: card.ID, Status: domain.DisputeStatusOpen}},
+	}
+
+	assert.True(t, order.IsRefundFrozen(card.ID))
+	assert.ErrorIs(t, order.AddRefund(refund(card, 10)), domain.ErrPaymentDisputed)
+	require.NoError(t, order.AddRefund(refund(cash, 5)), "other charges are not frozen")
+
+	order.Disputes[0].Status = domain.DisputeStatusLost
+	assert.ErrorIs(t, order.AddRefund(refund(card, 10)), domain.ErrPaymentDisputed)
+
+	order.Disputes[0].Status = domain.DisputeStatusWon
+	assert.False(t, order.IsRefundFrozen(card.ID))
+	require.NoError(t, order.AddRefund(refund(card, 10)))
+}
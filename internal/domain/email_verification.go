@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailVerification is a link sent to the email address of a user to verify it.
+// The address is marked verified on the user once the link is opened, unless the email changed meanwhile.
+type EmailVerification struct {
+	UserID    uuid.UUID
+	Email     string // Address the link was sent to
+	TokenHash string // SHA-256 hash of the token in the link, hex encoded
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord stores the response of a write request by its Idempotency-Key,
+// so a retried request gets the same response instead of being executed again.
+type IdempotencyRecord struct {
+	Scope       string // Caller, method and path the key was used with
+	Key         string
+	RequestHash string // SHA-256 of the request body, a reused key must come with the same body
+	StatusCode  int    // 0 while the original request is in progress
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// IsCompleted reports whether the response of the original request was stored.
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.StatusCode != 0
+}
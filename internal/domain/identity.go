@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Identity links a user to an account at an external identity provider (OIDC).
+type Identity struct {
+	Provider  string // Provider name from configuration
+	Subject   string // Subject (sub claim) at the provider
+	UserID    uuid.UUID
+	Email     string // Email reported by the provider at link time
+	CreatedAt time.Time
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InboxNotification is a notification shown in the user's inbox in the web app.
+type InboxNotification struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Category  string // One of NotificationCategory* values
+	Subject   string
+	Body      string
+	CreatedAt time.Time
+	ReadAt    *time.Time // Nil until the user reads the notification
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invoice is an issued invoice of an order. Invoice numbers are gapless within a fiscal year.
+type Invoice struct {
+	ID         uuid.UUID
+	Number     string // e.g. INV-2024-000001
+	FiscalYear int
+	Sequence   int64 // Position in the fiscal year, starting at 1
+	OrderID    uuid.UUID
+	Amount     float64
+	Currency   string // ISO 4217 code
+	Locale     string // Locale of the order, empty for invoices of orders without recorded settings
+	TaxRegion  string // Tax region of the order
+	IssuedAt   time.Time
+}
+
+// FiscalYear returns the fiscal year of the time for fiscal years starting on the first day of startMonth.
+// Fiscal years are named by the calendar year they start in.
+func FiscalYear(t time.Time, startMonth time.Month) int {
+	if t.Month() < startMonth {
+		return t.Year() - 1
+	}
+	return t.Year()
+}
+
+// InvoiceNumber formats the invoice number with its sequence number in the fiscal year.
+func InvoiceNumber(fiscalYear int, sequence int64) string {
+	return fmt.Sprintf("INV-%d-%06d", fiscalYear, sequence)
+}
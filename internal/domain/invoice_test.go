@@ -0,0 +1,22 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFiscalYear(t *testing.T) {
+	march := time.Date(2025, time.March, 31, 12, 0, 0, 0, time.UTC)
+	april := time.Date(2025, time.April, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 2025, domain.FiscalYear(march, time.January))
+	assert.Equal(t, 2024, domain.FiscalYear(march, time.April))
+	assert.Equal(t, 2025, domain.FiscalYear(april, time.April))
+}
+
+func TestInvoiceNumber(t *testing.T) {
+	assert.Equal(t, "INV-2024-000042", domain.InvoiceNumber(2024, 42))
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Login failure reasons.
+const (
+	LoginFailureUnknownUser     = "unknown_user"
+	LoginFailureWrongPassword   = "wrong_password"
+	LoginFailureAccountDisabled = "account_disabled"
+	LoginFailureAccountLocked   = "account_locked"
+)
+
+// LoginAttempt represents a single successful or failed login attempt.
+// UserID is nil when the email does not belong to any user.
+type LoginAttempt struct {
+	ID            uuid.UUID
+	UserID        *uuid.UUID
+	Login         string // Email or username used for the attempt
+	IPAddress     string
+	UserAgent     string
+	Success       bool
+	FailureReason string // Empty for successful attempts
+	CreatedAt     time.Time
+}
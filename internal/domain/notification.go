@@ -0,0 +1,82 @@
+package domain
+
+import "slices"
+
+// Notification categories users can opt in to or out of.
+const (
+	NotificationCategoryOrderUpdates   = "order_updates"    // Order confirmations and status changes
+	NotificationCategoryMarketing      = "marketing"        // Promotions and announcements
+	NotificationCategoryLowStockAlerts = "low_stock_alerts" // Wishlist products running low on stock
+)
+
+// Notification delivery channels.
+const (
+	NotificationChannelEmail = "email"
+	NotificationChannelSMS   = "sms"
+	NotificationChannelPush  = "push"
+	NotificationChannelInApp = "in_app" // Inbox of the web app
+)
+
+// NotificationCategories lists all supported notification categories.
+var NotificationCategories = []string{
+	NotificationCategoryOrderUpdates,
+	NotificationCategoryMarketing,
+	NotificationCategoryLowStockAlerts,
+}
+
+// NotificationChannels lists all supported notification channels.
+var NotificationChannels = []string{
+	NotificationChannelEmail,
+	NotificationChannelSMS,
+	NotificationChannelPush,
+	NotificationChannelInApp,
+}
+
+// NotificationPreferences holds per-channel opt-ins for each notification category
+// (category -> channel -> opted in).
+type NotificationPreferences map[string]map[string]bool
+
+// DefaultNotificationPreferences returns preferences for users who have not changed them:
+// order updates by email and in the app inbox, everything else opted out.
+func DefaultNotificationPreferences() NotificationPreferences {
+	prefs := make(NotificationPreferences, len(NotificationCategories))
+	for _, category := range NotificationCategories {
+		prefs[category] = make(map[string]bool, len(NotificationChannels))
+		for _, channel := range NotificationChannels {
+			prefs[category][channel] = false
+		}
+	}
+	prefs[NotificationCategoryOrderUpdates][NotificationChannelEmail] = true
+	prefs[NotificationCategoryOrderUpdates][NotificationChannelInApp] = true
+	return prefs
+}
+
+// Merge returns a copy of the preferences with the given overrides applied.
+func (p NotificationPreferences) Merge(overrides NotificationPreferences) NotificationPreferences {
+	merged := make(NotificationPreferences, len(p))
+	for category, channels := range p {
+		merged[category] = make(map[string]bool, len(channels))
+		for channel, enabled := range channels {
+			merged[category][channel] = enabled
+		}
+	}
+	for category, channels := range overrides {
+		if merged[category] == nil {
+			merged[category] = make(map[string]bool, len(channels))
+		}
+		for channel, enabled := range channels {
+			merged[category][channel] = enabled
+		}
+	}
+	return merged
+}
+
+// Allows reports whether the user opted in to notifications of the category on the channel.
+func (p NotificationPreferences) Allows(category, channel string) bool {
+	return p[category][channel]
+}
+
+// IsValidNotificationPreference reports whether category and channel are supported.
+func IsValidNotificationPreference(category, channel string) bool {
+	return slices.Contains(NotificationCategories, category) && slices.Contains(NotificationChannels, channel)
+}
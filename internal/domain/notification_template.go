@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrUnknownTemplate is returned for notification template names the application does not send.
+	ErrUnknownTemplate = errors.New("unknown notification template")
+	// ErrInvalidTemplate is returned when a template does not parse or uses unknown variables.
+	ErrInvalidTemplate = errors.New("invalid notification template")
+)
+
+// NotificationTemplate is a version of the subject and body of a notification, with variables
+// in Go text/template syntax, e.g. "Your order {{.OrderNumber}} has been placed."
+// Versions are never changed. The latest version of a template in the recipient's locale is sent.
+type NotificationTemplate struct {
+	Name      string // e.g. order_confirmation
+	Locale    string `example:"de"` // BCP 47 tag of the language or locale, empty for the default sent to other locales
+	Version   int    // Starting at 1 in each locale, 0 for built-in templates
+	Subject   string
+	Body      string
+	CreatedBy string // e.g. "client:admin", empty for built-in templates
+	CreatedAt time.Time
+}
@@ -0,0 +1,32 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationPreferences_Merge(t *testing.T) {
+	defaults := domain.DefaultNotificationPreferences()
+	merged := defaults.Merge(domain.NotificationPreferences{
+		domain.NotificationCategoryOrderUpdates: {domain.NotificationChannelEmail: false, domain.NotificationChannelSMS: true},
+		domain.NotificationCategoryMarketing:    {domain.NotificationChannelPush: true},
+	})
+
+	assert.False(t, merged.Allows(domain.NotificationCategoryOrderUpdates, domain.NotificationChannelEmail))
+	assert.True(t, merged.Allows(domain.NotificationCategoryOrderUpdates, domain.NotificationChannelSMS))
+	assert.True(t, merged.Allows(domain.NotificationCategoryMarketing, domain.NotificationChannelPush))
+	assert.False(t, merged.Allows(domain.NotificationCategoryLowStockAlerts, domain.NotificationChannelEmail))
+
+	// Defaults must stay untouched
+	assert.True(t, defaults.Allows(domain.NotificationCategoryOrderUpdates, domain.NotificationChannelEmail))
+	assert.False(t, defaults.Allows(domain.NotificationCategoryOrderUpdates, domain.NotificationChannelSMS))
+	assert.True(t, defaults.Allows(domain.NotificationCategoryOrderUpdates, domain.NotificationChannelInApp))
+}
+
+func TestIsValidNotificationPreference(t *testing.T) {
+	assert.True(t, domain.IsValidNotificationPreference(domain.NotificationCategoryMarketing, domain.NotificationChannelSMS))
+	assert.False(t, domain.IsValidNotificationPreference("newsletter", domain.NotificationChannelEmail))
+	assert.False(t, domain.IsValidNotificationPreference(domain.NotificationCategoryMarketing, "pigeon"))
+}
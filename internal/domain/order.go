@@ -1,25 +1,89 @@
 package domain
 
 import (
+	"math"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Order statuses.
+const (
+	OrderStatusCreated   = "created"
+	OrderStatusPaid      = "paid"
+	OrderStatusShipped   = "shipped"
+	OrderStatusDelivered = "delivered"
+	OrderStatusCancelled = "cancelled"
+)
+
 // Order represents a user's order.
 type Order struct {
-	ID          uuid.UUID
-	UserID      uuid.UUID
-	Items       []OrderItem
-	CreatedAt   time.Time
-	TotalAmount float64 // Total order amount
+	ID             uuid.UUID
+	Number         string // Human-friendly sequential number, e.g. ORD-2024-000123
+	UserID         uuid.UUID
+	Items          []OrderItem
+	Status         string
+	PaymentID      string // Provider capture ID of the payment completing the order, set once the order is paid
+	PendingPayment string `json:",omitempty"` // Provider capture ID of a payment awaiting confirmation by the provider
+	CreatedAt      time.Time
+	TotalAmount    float64 // Total order amount, items and option fees
+	OrderSettings
+
+	Options  []OrderOption  `json:",omitempty"` // Order-level options such as gift wrap, in the order they were chosen
+	Delivery *OrderDelivery `json:",omitempty"` // Delivery window chosen at checkout, nil if none was chosen
+
+	Payments []Payment // Payment ledger: charges and refunds in the order they were made
+	Disputes []Dispute // Disputes of the charges, refunds of disputed charges are frozen
+}
+
+// OrderSettings are the settings an order was placed with. They are recorded at purchase,
+// so invoices, refunds and reports of the order do not change with the configuration.
+// Orders placed before settings were recorded have empty ones.
+type OrderSettings struct {
+	Currency  string `example:"USD"`       // ISO 4217 code of the order amounts
+	Locale    string `example:"en-US"`     // Locale amounts of the order are formatted in
+	TaxRegion string `example:"US-CA"`     // Tax region the order was placed in, empty if not configured
+	Region    string `example:"eu-west-1"` // Deployment region the order was placed in, empty if not configured
+	Sandbox   bool   `json:",omitempty"`   // Placed by a sandbox account: no stock was allocated, payments went to the mock provider
 }
 
 // OrderItem represents a single item in an order.
 // PriceAtPurchase stores the product price at the time of purchase.
+// An ordered bundle is a bundle line with the bundle price, followed by component lines
+// that carry the allocated stock and have no price of their own.
 type OrderItem struct {
 	ID              uuid.UUID
 	ProductID       uuid.UUID
 	Quantity        int
 	PriceAtPurchase float64 // Price at time of purchase
+
+	Bundle       bool       // Bundle line, stock is allocated by its component lines
+	BundleItemID *uuid.UUID // Bundle line the component line belongs to
+}
+
+// AllocatesStock reports whether the item holds stock of its product.
+func (i *OrderItem) AllocatesStock() bool {
+	return !i.Bundle
+}
+
+// ComputeTotal returns the sum of item price × quantity and the option fees rounded to cents,
+// the same way the database computes it.
+func (o *Order) ComputeTotal() float64 {
+	var total float64
+	for _, item := range o.Items {
+		total += RoundCents(item.PriceAtPurchase * float64(item.Quantity))
+	}
+	return RoundCents(total + o.ComputeOptionFees())
+}
+
+// RoundCents rounds a monetary amount to cents.
+func RoundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// OrderTotalMismatch describes an order whose stored total differs from the sum of its items and option fees.
+type OrderTotalMismatch struct {
+	OrderID       uuid.UUID
+	StoredTotal   float64
+	ComputedTotal float64 // Sum of item price × quantity and option fees
 }
@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Order event types.
+const (
+	OrderEventCreated     = "created"
+	OrderEventItemAdded   = "item_added"
+	OrderEventOptionAdded = "option_added"
+	OrderEventPaid        = "paid"
+	OrderEventShipped     = "shipped"
+	OrderEventDelivered   = "delivered"
+	OrderEventCancelled   = "cancelled"
+
+	OrderEventPaymentPending = "payment_pending" // A capture awaits confirmation by the payment provider
+	OrderEventPaymentFailed  = "payment_failed"  // The pending capture was declined, the order awaits payment again
+)
+
+// ErrInvalidOrderTransition is returned when an event cannot be applied to the current order state.
+var ErrInvalidOrderTransition = errors.New("invalid order transition")
+
+// OrderEvent is an entry in the append-only event log of an order.
+// The order state is the result of applying all its events in sequence.
+type OrderEvent struct {
+	ID        uuid.UUID
+	OrderID   uuid.UUID
+	Sequence  int // Position in the order's event log, starting at 1
+	Type      string
+	UserID    uuid.UUID      // Set for created events
+	Number    string         // Order number, set for created events
+	Settings  *OrderSettings // Set for created events of orders with recorded settings
+	Delivery  *OrderDelivery // Set for created events of orders with a delivery window
+	Item      *OrderItem     // Set for item_added events
+	Option    *OrderOption   // Set for option_added events
+	PaymentID string         // Set for paid events charged through the payment gateway and for payment_pending and payment_failed events
+	Region    string         // Deployment region of the instance that recorded the event, empty if not configured
+	CreatedAt time.Time
+}
+
+// orderTransitions lists the statuses each status-changing event can be applied in.
+var orderTransitions = map[string][]string{
+	OrderEventPaid:      {OrderStatusCreated},
+	OrderEventShipped:   {OrderStatusPaid},
+	OrderEventDelivered: {OrderStatusShipped},
+	OrderEventCancelled: {OrderStatusCreated, OrderStatusPaid},
+}
+
+// orderEventStatus maps status-changing events to the resulting status.
+var orderEventStatus = map[string]string{
+	OrderEventPaid:      OrderStatusPaid,
+	OrderEventShipped:   OrderStatusShipped,
+	OrderEventDelivered: OrderStatusDelivered,
+	OrderEventCancelled: OrderStatusCancelled,
+}
+
+// IsOrderStatusEvent reports whether the event type changes the order status after creation.
+func IsOrderStatusEvent(eventType string) bool {
+	_, ok := orderEventStatus[eventType]
+	return ok
+}
+
+// Apply applies the event to the order.
+// Returns ErrInvalidOrderTransition if the event is not allowed in the current state.
+func (o *Order) Apply(e OrderEvent) error {
+	switch e.Type {
+	case OrderEventCreated:
+		if o.Status != "" {
+			return fmt.Errorf("%w: order %s already created", ErrInvalidOrderTransition, o.ID)
+		}
+		o.ID, o.Number, o.UserID, o.CreatedAt, o.Status = e.OrderID, e.Number, e.UserID, e.CreatedAt, OrderStatusCreated
+		if e.Settings != nil {
+			o.OrderSettings = *e.Settings
+		}
+		o.Delivery = e.Delivery
+	case OrderEventItemAdded:
+		if o.Status != OrderStatusCreated || e.Item == nil {
+			return fmt.Errorf("%w: cannot add item to %s order", ErrInvalidOrderTransition, o.Status)
+		}
+		o.Items = append(o.Items, *e.Item)
+		o.TotalAmount = o.ComputeTotal()
+	case OrderEventOptionAdded:
+		if o.Status != OrderStatusCreated || e.Option == nil {
+			return fmt.Errorf("%w: cannot add option to %s order", ErrInvalidOrderTransition, o.Status)
+		}
+		o.Options = append(o.Options, *e.Option)
+		o.TotalAmount = o.ComputeTotal()
+	case OrderEventPaymentPending:
+		if o.Status != OrderStatusCreated || o.PendingPayment != "" || e.PaymentID == "" {
+			return fmt.Errorf("%w: cannot await payment of %s order", ErrInvalidOrderTransition, o.Status)
+		}
+		o.PendingPayment = e.PaymentID
+	case OrderEventPaymentFailed:
+		if o.PendingPayment == "" || e.PaymentID != o.PendingPayment {
+			return fmt.Errorf("%w: payment %s of order is not pending", ErrInvalidOrderTransition, e.PaymentID)
+		}
+		o.PendingPayment = ""
+	default:
+		status, ok := orderEventStatus[e.Type]
+		if !ok {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidOrderTransition, e.Type)
+		}
+		if !slices.Contains(orderTransitions[e.Type], o.Status) {
+			return fmt.Errorf("%w: cannot apply %s to %s order", ErrInvalidOrderTransition, e.Type, o.Status)
+		}
+		o.Status = status
+		o.PendingPayment = "" // Paid with the pending capture, or its outcome no longer matters
+		if e.Type == OrderEventPaid {
+			o.PaymentID = e.PaymentID
+		}
+	}
+	return nil
+}
+
+// ReplayOrder rebuilds an order by applying its events in sequence.
+func ReplayOrder(events []OrderEvent) (*Order, error) {
+	order := &Order{}
+	for _, e := range events {
+		if err := order.Apply(e); err != nil {
+			return nil, fmt.Errorf("event %d: %w", e.Sequence, err)
+		}
+	}
+	return order, nil
+}
+
+// CreationEvents returns the events recording creation of the order with its items and options.
+func (o *Order) CreationEvents() []OrderEvent {
+	events := make([]OrderEvent, 0, len(o.Items)+len(o.Options)+1)
+	events = append(events, OrderEvent{
+		ID:        uuid.New(),
+		OrderID:   o.ID,
+		Sequence:  1,
+		Type:      OrderEventCreated,
+		UserID:    o.UserID,
+		Number:    o.Number,
+		Settings:  &o.OrderSettings,
+		Delivery:  o.Delivery,
+		CreatedAt: o.CreatedAt,
+	})
+	for i := range o.Items {
+		events = append(events, OrderEvent{
+			ID:        uuid.New(),
+			OrderID:   o.ID,
+			Sequence:  i + 2,
+			Type:      OrderEventItemAdded,
+			Item:      &o.Items[i],
+			CreatedAt: o.CreatedAt,
+		})
+	}
+	for i := range o.Options {
+		events = append(events, OrderEvent{
+			ID:        uuid.New(),
+			OrderID:   o.ID,
+			Sequence:  len(events) + 1,
+			Type:      OrderEventOptionAdded,
+			Option:    &o.Options[i],
+			CreatedAt: o.CreatedAt,
+		})
+	}
+	return events
+}
@@ -0,0 +1,147 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayOrder(t *testing.T) {
+	order := &domain.Order{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Status:    domain.OrderStatusCreated,
+		CreatedAt: time.Now(),
+		OrderSettings: domain.OrderSettings{
+			Currency: "EUR", Locale: "de-DE", TaxRegion: "DE",
+		},
+		Items: []domain.OrderItem{
+			{ID: uuid.New(), ProductID: uuid.New(), Quantity: 2, PriceAtPurchase: 10},
+			{ID: uuid.New(), ProductID: uuid.New(), Quantity: 1, PriceAtPurchase: 0.99},
+		},
+	}
+	order.TotalAmount = order.ComputeTotal()
+
+	events := order.CreationEvents()
+	require.Len(t, events, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{events[0].Sequence, events[1].Sequence, events[2].Sequence})
+
+	replayed, err := domain.ReplayOrder(events)
+	require.NoError(t, err)
+	assert.Equal(t, order, replayed)
+
+	events = append(events, domain.OrderEvent{OrderID: order.ID, Sequence: 4, Type: domain.OrderEventPaid})
+	replayed, err = domain.ReplayOrder(events)
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusPaid, replayed.Status)
+}
+
+func TestReplayOrder_Options(t *testing.T) {
+	order := &domain.Order{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Status:    domain.OrderStatusCreated,
+		CreatedAt: time.Now(),
+		Items:     []domain.OrderItem{{ID: uuid.New(), ProductID: uuid.New(), Quantity: 3, PriceAtPurchase: 3.33}},
+		Options: []domain.OrderOption{
+			{Option: domain.OrderOptionGiftWrap, Fee: 4.99},
+			{Option: domain.OrderOptionGiftMessage, Message: "Happy birthday!"},
+		},
+	}
+	order.TotalAmount = order.ComputeTotal()
+	assert.Equal(t, 14.98, order.TotalAmount, "option fees are part of the total")
+	assert.Equal(t, "Happy birthday!", order.GiftMessage())
+	assert.Nil(t, order.Option(domain.OrderOptionPriorityHandling))
+
+	events := order.CreationEvents()
+	require.Len(t, events, 4)
+	assert.Equal(t, domain.OrderEventOptionAdded, events[3].Type)
+	assert.Equal(t, 4, events[3].Sequence)
+
+	replayed, err := domain.ReplayOrder(events)
+	require.NoError(t, err)
+	assert.Equal(t, order, replayed)
+
+	// Options are chosen at checkout
+	replayed.Status = domain.OrderStatusPaid
+	assert.ErrorIs(t, replayed.Apply(domain.OrderEvent{Type: domain.OrderEventOptionAdded, Option: &order.Options[0]}), domain.ErrInvalidOrderTransition)
+}
+
+func TestReplayOrder_Delivery(t *testing.T) {
+	slot := &domain.DeliverySlot{ID: uuid.New(), Region: "eu-west-1", StartsAt: time.Now().Add(24 * time.Hour), EndsAt: time.Now().Add(27 * time.Hour), Capacity: 10}
+	order := &domain.Order{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Status:    domain.OrderStatusCreated,
+		CreatedAt: time.Now(),
+		Items:     []domain.OrderItem{{ID: uuid.New(), ProductID: uuid.New(), Quantity: 1, PriceAtPurchase: 5}},
+		Delivery:  slot.Delivery(),
+	}
+	order.TotalAmount = order.ComputeTotal()
+
+	replayed, err := domain.ReplayOrder(order.CreationEvents())
+	require.NoError(t, err)
+	assert.Equal(t, order, replayed)
+	assert.Equal(t, 10, slot.Available())
+}
+
+func TestOrderApply_PendingPayment(t *testing.T) {
+	order := &domain.Order{ID: uuid.New(), Status: domain.OrderStatusCreated}
+
+	require.NoError(t, order.Apply(domain.OrderEvent{Type: domain.OrderEventPaymentPending, PaymentID: "cap_1"}))
+	assert.Equal(t, "cap_1", order.PendingPayment)
+	assert.ErrorIs(t, order.Apply(domain.OrderEvent{Type: domain.OrderEventPaymentPending, PaymentID: "cap_2"}), domain.ErrInvalidOrderTransition, "one pending payment at a time")
+	assert.ErrorIs(t, order.Apply(domain.OrderEvent{Type: domain.OrderEventPaymentFailed, PaymentID: "cap_2"}), domain.ErrInvalidOrderTransition, "other payment is not pending")
+
+	require.NoError(t, order.Apply(domain.OrderEvent{Type: domain.OrderEventPaymentFailed, PaymentID: "cap_1"}))
+	assert.Empty(t, order.PendingPayment)
+	assert.Equal(t, domain.OrderStatusCreated, order.Status, "order awaits payment again")
+
+	require.NoError(t, order.Apply(domain.OrderEvent{Type: domain.OrderEventPaymentPending, PaymentID: "cap_2"}))
+	require.NoError(t, order.Apply(domain.OrderEvent{Type: domain.OrderEventPaid, PaymentID: "cap_2"}))
+	assert.Equal(t, domain.OrderStatusPaid, order.Status)
+	assert.Equal(t, "cap_2", order.PaymentID)
+	assert.Empty(t, order.PendingPayment)
+	assert.ErrorIs(t, order.Apply(domain.OrderEvent{Type: domain.OrderEventPaymentPending, PaymentID: "cap_3"}), domain.ErrInvalidOrderTransition)
+}
+
+func TestOrderApply_Transitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		event   string
+		want    string
+		wantErr bool
+	}{
+		{name: "pay created", status: domain.OrderStatusCreated, event: domain.OrderEventPaid, want: domain.OrderStatusPaid},
+		{name: "ship paid", status: domain.OrderStatusPaid, event: domain.OrderEventShipped, want: domain.OrderStatusShipped},
+		{name: "deliver shipped", status: domain.OrderStatusShipped, event: domain.OrderEventDelivered, want: domain.OrderStatusDelivered},
+		{name: "cancel created", status: domain.OrderStatusCreated, event: domain.OrderEventCancelled, want: domain.OrderStatusCancelled},
+		{name: "cancel paid", status: domain.OrderStatusPaid, event: domain.OrderEventCancelled, want: domain.OrderStatusCancelled},
+		{name: "ship unpaid", status: domain.OrderStatusCreated, event: domain.OrderEventShipped, wantErr: true},
+		{name: "cancel shipped", status: domain.OrderStatusShipped, event: domain.OrderEventCancelled, wantErr: true},
+		{name: "deliver unshipped", status: domain.OrderStatusPaid, event: domain.OrderEventDelivered, wantErr: true},
+		{name: "pay twice", status: domain.OrderStatusPaid, event: domain.OrderEventPaid, wantErr: true},
+		{name: "add item to paid", status: domain.OrderStatusPaid, event: domain.OrderEventItemAdded, wantErr: true},
+		{name: "create twice", status: domain.OrderStatusCreated, event: domain.OrderEventCreated, wantErr: true},
+		{name: "unknown event", status: domain.OrderStatusCreated, event: "refunded", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &domain.Order{Status: tt.status}
+			err := order.Apply(domain.OrderEvent{Type: tt.event, Item: &domain.OrderItem{}})
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidOrderTransition)
+				assert.Equal(t, tt.status, order.Status)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, order.Status)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ErrInvalidOrderNumberSettings is returned when the order number format is invalid.
+var ErrInvalidOrderNumberSettings = errors.New("invalid order number settings")
+
+// orderNumberPrefix matches prefixes of order numbers: uppercase letters and digits, starting with a letter.
+var orderNumberPrefix = regexp.MustCompile(`^[A-Z][A-Z0-9]{0,15}$`)
+
+// OrderNumberSettings is the format of human-friendly order numbers: prefix, year and a zero-padded
+// sequence number restarting every year, e.g. ORD-2024-000123.
+type OrderNumberSettings struct {
+	Prefix  string
+	Padding int // Minimum number of digits of the sequence number
+}
+
+// Validate checks that the prefix consists of uppercase letters and digits and the padding is between 1 and 12.
+func (s *OrderNumberSettings) Validate() error {
+	if !orderNumberPrefix.MatchString(s.Prefix) {
+		return fmt.Errorf("%w: prefix must be up to 16 uppercase letters and digits, starting with a letter", ErrInvalidOrderNumberSettings)
+	}
+	if s.Padding < 1 || s.Padding > 12 {
+		return fmt.Errorf("%w: padding must be between 1 and 12", ErrInvalidOrderNumberSettings)
+	}
+	return nil
+}
+
+// Number formats the order number with the sequence number of the year.
+func (s *OrderNumberSettings) Number(year int, sequence int64) string {
+	return fmt.Sprintf("%s-%d-%0*d", s.Prefix, year, s.Padding, sequence)
+}
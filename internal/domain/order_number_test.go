@@ -0,0 +1,26 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderNumberSettings(t *testing.T) {
+	settings := domain.OrderNumberSettings{Prefix: "ORD", Padding: 6}
+	assert.NoError(t, settings.Validate())
+	assert.Equal(t, "ORD-2024-000123", settings.Number(2024, 123))
+	assert.Equal(t, "ORD-2024-1234567", settings.Number(2024, 1234567)) // Padding is a minimum
+
+	for _, invalid := range []domain.OrderNumberSettings{
+		{Prefix: "", Padding: 6},
+		{Prefix: "ord", Padding: 6},
+		{Prefix: "1ORD", Padding: 6},
+		{Prefix: "ORD-EU", Padding: 6},
+		{Prefix: "ORD", Padding: 0},
+		{Prefix: "ORD", Padding: 13},
+	} {
+		assert.ErrorIs(t, invalid.Validate(), domain.ErrInvalidOrderNumberSettings, invalid.Prefix)
+	}
+}
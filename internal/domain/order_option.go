@@ -0,0 +1,56 @@
+package domain
+
+import "strings"
+
+// Order options, chosen for the whole order at checkout.
+const (
+	OrderOptionGiftWrap         = "gift_wrap"         // Items are gift wrapped
+	OrderOptionGiftMessage      = "gift_message"      // A message is printed on a gift card in the parcel
+	OrderOptionPriorityHandling = "priority_handling" // The order is picked and packed before others
+)
+
+// MaxGiftMessageLength is the maximum number of characters of a gift message.
+const MaxGiftMessageLength = 500
+
+// OrderOption is an order-level option with the fee charged for it at purchase. Fees are part of the order total.
+type OrderOption struct {
+	Option  string  `example:"gift_wrap"`
+	Fee     float64 `example:"4.99"`
+	Message string  `json:",omitempty" example:"Happy birthday!"` // Gift message, set for gift_message options
+}
+
+// ComputeOptionFees returns the sum of the fees of the order's options rounded to cents.
+func (o *Order) ComputeOptionFees() float64 {
+	var fees float64
+	for _, option := range o.Options {
+		fees += option.Fee
+	}
+	return RoundCents(fees)
+}
+
+// Option returns the option of the order with the name, or nil if it was not chosen.
+func (o *Order) Option(name string) *OrderOption {
+	for i := range o.Options {
+		if o.Options[i].Option == name {
+			return &o.Options[i]
+		}
+	}
+	return nil
+}
+
+// GiftMessage returns the gift message of the order, empty if none was chosen.
+func (o *Order) GiftMessage() string {
+	if option := o.Option(OrderOptionGiftMessage); option != nil {
+		return option.Message
+	}
+	return ""
+}
+
+// OptionNames returns the names of the order's options joined by sep, e.g. "gift_wrap;priority_handling".
+func (o *Order) OptionNames(sep string) string {
+	names := make([]string, len(o.Options))
+	for i, option := range o.Options {
+		names[i] = option.Option
+	}
+	return strings.Join(names, sep)
+}
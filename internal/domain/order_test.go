@@ -0,0 +1,42 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderComputeTotal(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []domain.OrderItem
+		want  float64
+	}{
+		{name: "no items", want: 0},
+		{name: "single item", items: []domain.OrderItem{{Quantity: 3, PriceAtPurchase: 99.99}}, want: 299.97},
+		{
+			name: "float drift is rounded away",
+			items: []domain.OrderItem{
+				{Quantity: 1, PriceAtPurchase: 0.1},
+				{Quantity: 1, PriceAtPurchase: 0.2},
+			},
+			want: 0.3,
+		},
+		{
+			name: "many cheap items",
+			items: []domain.OrderItem{
+				{Quantity: 1000, PriceAtPurchase: 0.01},
+				{Quantity: 7, PriceAtPurchase: 19.99},
+			},
+			want: 149.93,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := domain.Order{Items: tt.items}
+			assert.Equal(t, tt.want, order.ComputeTotal())
+		})
+	}
+}
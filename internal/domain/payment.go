@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payment kinds.
+const (
+	PaymentKindCharge = "charge"
+	PaymentKindRefund = "refund"
+)
+
+// Payment methods.
+const (
+	PaymentMethodCard         = "card"
+	PaymentMethodPayPal       = "paypal"
+	PaymentMethodGiftCard     = "gift_card"
+	PaymentMethodBankTransfer = "bank_transfer"
+	PaymentMethodCash         = "cash"
+)
+
+// PaymentProviderLegacy is the provider of card payments taken before payment providers were recorded.
+// Their gateway is gone, so refunds of them are only recorded and paid out out-of-band by finance.
+const PaymentProviderLegacy = "legacy"
+
+var (
+	// ErrInvalidPayment is returned when a payment cannot be added to the order.
+	ErrInvalidPayment = errors.New("invalid payment")
+	// ErrInvalidRefund is returned when a refund exceeds the refundable amount of the charge.
+	ErrInvalidRefund = errors.New("invalid refund")
+)
+
+// IsValidPaymentMethod reports whether the method is a known payment method.
+func IsValidPaymentMethod(method string) bool {
+	switch method {
+	case PaymentMethodCard, PaymentMethodPayPal, PaymentMethodGiftCard, PaymentMethodBankTransfer, PaymentMethodCash:
+		return true
+	}
+	return false
+}
+
+// Payment is an entry in the payment ledger of an order: a charge paying part of the order
+// or a refund of part of a charge. Entries are never changed.
+type Payment struct {
+	ID        uuid.UUID
+	OrderID   uuid.UUID
+	Kind      string // charge or refund
+	Method    string // Method of the charge, refunds go back the same way
+	Reference string // Provider capture or refund ID, gift card code, empty if none
+	Amount    float64
+	RefundOf  *uuid.UUID        // Refunded charge, set for refunds
+	Provider  string            // Payment provider the charge went through, empty for payments made outside checkout
+	Metadata  map[string]string // Provider-specific details, e.g. the PayPal order ID
+	CreatedAt time.Time
+}
+
+// ThroughProvider reports whether the payment was made through a payment provider its refunds go through.
+func (p *Payment) ThroughProvider() bool {
+	return p.Provider != "" && p.Provider != PaymentProviderLegacy
+}
+
+// PaidAmount returns the sum of the order's charges.
+func (o *Order) PaidAmount() float64 {
+	return o.sumPayments(PaymentKindCharge)
+}
+
+// RefundedAmount returns the sum of the order's refunds.
+func (o *Order) RefundedAmount() float64 {
+	return o.sumPayments(PaymentKindRefund)
+}
+
+func (o *Order) sumPayments(kind string) float64 {
+	var sum float64
+	for _, p := range o.Payments {
+		if p.Kind == kind {
+			sum += p.Amount
+		}
+	}
+	return RoundCents(sum)
+}
+
+// OutstandingAmount returns the part of the total not covered by payments net of refunds.
+func (o *Order) OutstandingAmount() float64 {
+	return RoundCents(o.TotalAmount - o.PaidAmount() + o.RefundedAmount())
+}
+
+// AddPayment adds a charge to the order's payments.
+// Only orders awaiting payment accept charges, up to the outstanding amount.
+func (o *Order) AddPayment(p Payment) error {
+	switch outstanding := o.OutstandingAmount(); {
+	case p.Kind != PaymentKindCharge || !IsValidPaymentMethod(p.Method):
+		return fmt.Errorf("%w: unknown payment kind %q or method %q", ErrInvalidPayment, p.Kind, p.Method)
+	case o.Status != OrderStatusCreated:
+		return fmt.Errorf("%w: %s order does not accept payments", ErrInvalidPayment, o.Status)
+	case p.Amount <= 0:
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidPayment)
+	case p.Amount > outstanding:
+		return fmt.Errorf("%w: amount %.2f exceeds outstanding %.2f", ErrInvalidPayment, p.Amount, outstanding)
+	}
+	o.Payments = append(o.Payments, p)
+	return nil
+}
+
+// RefundableAmount returns the part of the charge that has not been refunded yet.
+func (o *Order) RefundableAmount(chargeID uuid.UUID) float64 {
+	var refundable float64
+	for _, p := range o.Payments {
+		switch {
+		case p.ID == chargeID && p.Kind == PaymentKindCharge:
+			refundable += p.Amount
+		case p.RefundOf != nil && *p.RefundOf == chargeID:
+			refundable -= p.Amount
+		}
+	}
+	return RoundCents(refundable)
+}
+
+// AddRefund adds a refund of one of the order's charges, up to its refundable amount.
+// Returns ErrPaymentDisputed if refunds of the charge are frozen by a dispute.
+func (o *Order) AddRefund(r Payment) error {
+	if r.Kind != PaymentKindRefund || r.RefundOf == nil {
+		return fmt.Errorf("%w: refund must reference a charge", ErrInvalidRefund)
+	}
+	if o.IsRefundFrozen(*r.RefundOf) {
+		return fmt.Errorf("%w: refunds of payment %s are frozen", ErrPaymentDisputed, *r.RefundOf)
+	}
+	switch refundable := o.RefundableAmount(*r.RefundOf); {
+	case r.Amount <= 0:
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidRefund)
+	case r.Amount > refundable:
+		return fmt.Errorf("%w: amount %.2f exceeds refundable %.2f", ErrInvalidRefund, r.Amount, refundable)
+	}
+	o.Payments = append(o.Payments, r)
+	return nil
+}
+
+// FindCharge returns the order's charge with the ID, nil if there is none.
+func (o *Order) FindCharge(id uuid.UUID) *Payment {
+	for i := range o.Payments {
+		if o.Payments[i].ID == id && o.Payments[i].Kind == PaymentKindCharge {
+			return &o.Payments[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func charge(method string, amount float64) domain.Payment {
+	return domain.Payment{ID: uuid.New(), Kind: domain.PaymentKindCharge, Method: method, Amount: amount}
+}
+
+func refund(of domain.Payment, amount float64) domain.Payment {
+	return domain.Payment{ID: uuid.New(), Kind: domain.PaymentKindRefund, Method: of.Method, Amount: amount, RefundOf: &of.ID}
+}
+
+func TestOrder_SplitPayment(t *testing.T) {
+	order := &domain.Order{Status: domain.OrderStatusCreated, TotalAmount: 50}
+	giftCard := charge(domain.PaymentMethodGiftCard, 20)
+
+	require.NoError(t, order.AddPayment(giftCard))
+	assert.Equal(t, 30.0, order.OutstandingAmount())
+	assert.ErrorIs(t, order.AddPayment(charge(domain.PaymentMethodCard, 30.01)), domain.ErrInvalidPayment)
+	assert.ErrorIs(t, order.AddPayment(charge("cheque", 30)), domain.ErrInvalidPayment)
+	require.NoError(t, order.AddPayment(charge(domain.PaymentMethodCard, 30)))
+
+	assert.Equal(t, 50.0, order.PaidAmount())
+	assert.Zero(t, order.OutstandingAmount())
+
+	order.Status = domain.OrderStatusPaid
+	assert.ErrorIs(t, order.AddPayment(charge(domain.PaymentMethodCash, 1)), domain.ErrInvalidPayment)
+}
+
+func TestOrder_PartialRefunds(t *testing.T) {
+	card := charge(domain.PaymentMethodCard, 30)
+	order := &domain.Order{Status: domain.OrderStatusPaid, TotalAmount: 30, Payments: []domain.Payment{card}}
+
+	require.NoError(t, order.AddRefund(refund(card, 10)))
+	require.NoError(t, order.AddRefund(refund(card, 15.5)))
+	assert.Equal(t, 4.5, order.RefundableAmount(card.ID))
+	assert.ErrorIs(t, order.AddRefund(refund(card, 5)), domain.ErrInvalidRefund)
+	assert.ErrorIs(t, order.AddRefund(domain.Payment{Kind: domain.PaymentKindRefund, Amount: 1}), domain.ErrInvalidRefund)
+
+	assert.Equal(t, 30.0, order.PaidAmount())
+	assert.Equal(t, 25.5, order.RefundedAmount())
+	assert.Zero(t, order.RefundableAmount(uuid.New()))
+}
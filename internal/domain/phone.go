@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrPhoneInvalid is returned when a phone number is not an international number.
+var ErrPhoneInvalid = errors.New("phone number must be in international format, e.g. +4915123456789")
+
+// phonePattern matches E.164 phone numbers: a country calling code and up to 15 digits in total.
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// phoneSeparators are removed from phone numbers before validation.
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+// NormalizePhone removes separators from a phone number, replaces the international prefix 00 with +
+// and validates the result is in E.164 format.
+// Returns ErrPhoneInvalid if the number has no country calling code or too few or many digits.
+func NormalizePhone(phone string) (string, error) {
+	normalized := phoneSeparators.Replace(strings.TrimSpace(phone))
+	if rest, ok := strings.CutPrefix(normalized, "00"); ok {
+		normalized = "+" + rest
+	}
+
+	if !phonePattern.MatchString(normalized) {
+		return "", ErrPhoneInvalid
+	}
+	return normalized, nil
+}
+
+// PhoneVerification is a code sent by SMS to a phone number the user wants to receive notifications on.
+// The number is stored on the user once the code is entered.
+type PhoneVerification struct {
+	UserID    uuid.UUID
+	Phone     string // Number being verified, in E.164 format
+	CodeHash  string // SHA-256 hash of the code, hex encoded
+	Attempts  int    // Wrong codes entered
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
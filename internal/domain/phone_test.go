@@ -0,0 +1,37 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name    string
+		phone   string
+		want    string
+		wantErr error
+	}{
+		{name: "keeps E.164", phone: "+4915123456789", want: "+4915123456789"},
+		{name: "removes separators", phone: " +1 (555) 010-0199 ", want: "+15550100199"},
+		{name: "replaces international prefix", phone: "0044 20 7946 0958", want: "+442079460958"},
+		{name: "requires country calling code", phone: "015123456789", wantErr: domain.ErrPhoneInvalid},
+		{name: "country code cannot start with zero", phone: "+015123456789", wantErr: domain.ErrPhoneInvalid},
+		{name: "too long", phone: "+4915123456789012", wantErr: domain.ErrPhoneInvalid},
+		{name: "letters", phone: "+49 151 CALL ME", wantErr: domain.ErrPhoneInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domain.NormalizePhone(tt.phone)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
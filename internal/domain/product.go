@@ -1,12 +1,192 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidProductChange is returned when a product change would leave the product invalid.
+	ErrInvalidProductChange = errors.New("invalid product change")
+	// ErrInvalidProductTransition is returned when the product cannot move to the requested status.
+	ErrInvalidProductTransition = errors.New("invalid product status transition")
+	// ErrInvalidBundle is returned when a bundle has no components, repeats or nests them.
+	ErrInvalidBundle = errors.New("invalid bundle")
+)
+
+// Product statuses.
+const (
+	ProductStatusDraft    = "draft"    // Being prepared, invisible to the storefront
+	ProductStatusActive   = "active"   // Visible and can be ordered
+	ProductStatusArchived = "archived" // Visible for order history, cannot be ordered
+)
+
+// productTransitions lists the statuses a product can move to each status from.
+// Published products cannot go back to draft.
+var productTransitions = map[string][]string{
+	ProductStatusActive:   {ProductStatusDraft, ProductStatusArchived},
+	ProductStatusArchived: {ProductStatusDraft, ProductStatusActive},
+}
+
+// IsValidProductStatus reports whether the status is a known product status.
+func IsValidProductStatus(status string) bool {
+	switch status {
+	case ProductStatusDraft, ProductStatusActive, ProductStatusArchived:
+		return true
+	}
+	return false
+}
 
 // Product represents a product in the system.
 type Product struct {
-	ID          uuid.UUID
-	Description string
-	Tags        []string
-	Quantity    int     // Product quantity in stock
-	Price       float64 // Product price
+	ID             uuid.UUID
+	Description    string
+	Tags           []string
+	Quantity       int     // Product quantity in stock
+	Price          float64 // Product price
+	AgeRestriction int     // Minimum buyer age, 0 if not restricted
+	Status         string  // draft, active or archived
+	Barcode        string  // EAN-8, EAN-13 or GTIN-14, empty if not set
+	Category       string  // Category whose attribute definitions apply, empty if not set
+	Attributes     map[string]any
+
+	Components []BundleComponent // Products the bundle consists of, empty for regular products
+}
+
+// BundleComponent is a product contained in a bundle.
+type BundleComponent struct {
+	ProductID uuid.UUID
+	Quantity  int // Number of component items in one bundle
+}
+
+// IsBundle reports whether the product is a bundle of other products.
+// Bundles have no stock of their own, ordering one allocates the stock of its components.
+func (p *Product) IsBundle() bool {
+	return len(p.Components) > 0
+}
+
+// ValidateComponents checks that the bundle components are not empty, repeated or bundles themselves.
+// products must contain every component product.
+func ValidateComponents(components []BundleComponent, products map[uuid.UUID]*Product) error {
+	if len(components) == 0 {
+		return fmt.Errorf("%w: bundle must have components", ErrInvalidBundle)
+	}
+	seen := make(map[uuid.UUID]bool, len(components))
+	for _, c := range components {
+		switch product := products[c.ProductID]; {
+		case c.Quantity <= 0:
+			return fmt.Errorf("%w: component quantity must be positive", ErrInvalidBundle)
+		case seen[c.ProductID]:
+			return fmt.Errorf("%w: component %s is repeated", ErrInvalidBundle, c.ProductID)
+		case product == nil:
+			return fmt.Errorf("%w: component %s not found", ErrInvalidBundle, c.ProductID)
+		case product.IsBundle():
+			return fmt.Errorf("%w: component %s is a bundle", ErrInvalidBundle, c.ProductID)
+		}
+		seen[c.ProductID] = true
+	}
+	return nil
+}
+
+// IsAgeRestricted reports whether the product requires a minimum buyer age.
+func (p *Product) IsAgeRestricted() bool {
+	return p.AgeRestriction > 0
+}
+
+// IsVisible reports whether the product is shown in the storefront.
+func (p *Product) IsVisible() bool {
+	return p.Status != ProductStatusDraft
+}
+
+// IsOrderable reports whether the product can be added to new orders.
+func (p *Product) IsOrderable() bool {
+	return p.Status == ProductStatusActive
+}
+
+// CanTransitionTo reports whether the product can move to the status. Keeping the current status is allowed.
+func (p *Product) CanTransitionTo(status string) bool {
+	return status == p.Status || slices.Contains(productTransitions[status], p.Status)
+}
+
+// ProductChange is a partial update of product details. Nil and empty fields are left unchanged.
+// Quantity cannot be changed, it only changes through the stock ledger.
+type ProductChange struct {
+	Description    *string
+	Price          *float64
+	AgeRestriction *int
+	AddTags        []string
+	RemoveTags     []string
+	Status         *string
+	Barcode        *string        // Empty removes the barcode
+	Category       *string        // Empty removes the category
+	Attributes     map[string]any // Set attributes, nil values remove them
+}
+
+// Apply applies the change to the product.
+// Returns ErrInvalidProductChange, ErrInvalidProductTransition or ErrInvalidBarcode and leaves the product unchanged if the result would be invalid.
+func (p *Product) Apply(c ProductChange) error {
+	switch {
+	case c.Description != nil && *c.Description == "":
+		return fmt.Errorf("%w: description must not be empty", ErrInvalidProductChange)
+	case c.Price != nil && *c.Price <= 0:
+		return fmt.Errorf("%w: price must be positive", ErrInvalidProductChange)
+	case c.AgeRestriction != nil && (*c.AgeRestriction < 0 || *c.AgeRestriction > 99):
+		return fmt.Errorf("%w: age restriction must be between 0 and 99", ErrInvalidProductChange)
+	case c.Status != nil && !p.CanTransitionTo(*c.Status):
+		return fmt.Errorf("%w: cannot move %s product to %q", ErrInvalidProductTransition, p.Status, *c.Status)
+	}
+
+	var barcode string
+	if c.Barcode != nil && *c.Barcode != "" {
+		var err error
+		if barcode, err = NormalizeBarcode(*c.Barcode); err != nil {
+			return err
+		}
+	}
+
+	if c.Description != nil {
+		p.Description = *c.Description
+	}
+	if c.Price != nil {
+		p.Price = RoundCents(*c.Price)
+	}
+	if c.AgeRestriction != nil {
+		p.AgeRestriction = *c.AgeRestriction
+	}
+	for _, tag := range c.AddTags {
+		if tag != "" && !slices.Contains(p.Tags, tag) {
+			p.Tags = append(p.Tags, tag)
+		}
+	}
+	if len(c.RemoveTags) > 0 {
+		p.Tags = slices.DeleteFunc(p.Tags, func(tag string) bool { return slices.Contains(c.RemoveTags, tag) })
+	}
+	if c.Status != nil {
+		p.Status = *c.Status
+	}
+	if c.Barcode != nil {
+		p.Barcode = barcode
+	}
+	if c.Category != nil {
+		p.Category = *c.Category
+	}
+	if len(c.Attributes) > 0 {
+		attributes := maps.Clone(p.Attributes)
+		if attributes == nil {
+			attributes = make(map[string]any, len(c.Attributes))
+		}
+		for name, value := range c.Attributes {
+			if value == nil {
+				delete(attributes, name)
+			} else {
+				attributes[name] = value
+			}
+		}
+		p.Attributes = attributes
+	}
+	return nil
 }
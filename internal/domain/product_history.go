@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Product fields tracked in the change history.
+const (
+	ProductFieldDescription    = "description"
+	ProductFieldTags           = "tags"
+	ProductFieldPrice          = "price"
+	ProductFieldAgeRestriction = "age_restriction"
+	ProductFieldStatus         = "status"
+	ProductFieldBarcode        = "barcode"
+	ProductFieldCategory       = "category"
+	ProductFieldAttributes     = "attributes"
+)
+
+// FieldDiff is the value of a product field before and after an edit.
+type FieldDiff struct {
+	Field  string
+	Before any
+	After  any
+}
+
+// ProductRevision records a single edit of a product for catalog governance.
+type ProductRevision struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+	Actor     string // Who made the edit, e.g. "user:<id>" or "client:<name>"
+	ChangedAt time.Time
+	Changes   []FieldDiff
+}
+
+// DiffProducts returns the details that differ between two versions of a product.
+// Quantity is not compared, its history is kept by the stock ledger.
+func DiffProducts(before, after *Product) []FieldDiff {
+	var diffs []FieldDiff
+	if before.Description != after.Description {
+		diffs = append(diffs, FieldDiff{Field: ProductFieldDescription, Before: before.Description, After: after.Description})
+	}
+	if !slices.Equal(before.Tags, after.Tags) {
+		diffs = append(diffs, FieldDiff{Field: ProductFieldTags, Before: before.Tags, After: after.Tags})
+	}
+	if before.Price != after.Price {
+		diffs = append(diffs, FieldDiff{Field: ProductFieldPrice, Before: before.Price, After: after.Price})
+	}
+	if before.AgeRestriction != after.AgeRestriction {
+		diffs = append(diffs, FieldDiff{Field: ProductFieldAgeRestriction, Before: before.AgeRestriction, After: after.AgeRestriction})
+	}
+	if before.Status != after.Status {
+		diffs = append(diffs, FieldDiff{Field: ProductFieldStatus, Before: before.Status, After: after.Status})
+	}
+	if before.Barcode != after.Barcode {
+		diffs = append(diffs, FieldDiff{Field: ProductFieldBarcode, Before: before.Barcode, After: after.Barcode})
+	}
+	if before.Category != after.Category {
+		diffs = append(diffs, FieldDiff{Field: ProductFieldCategory, Before: before.Category, After: after.Category})
+	}
+	if !reflect.DeepEqual(before.Attributes, after.Attributes) && len(before.Attributes)+len(after.Attributes) > 0 {
+		diffs = append(diffs, FieldDiff{Field: ProductFieldAttributes, Before: before.Attributes, After: after.Attributes})
+	}
+	return diffs
+}
+
+// EffectivePrice is the price a product was sold at at a point in time, resolved from its change history.
+type EffectivePrice struct {
+	ProductID uuid.UUID
+	At        time.Time
+	Price     float64
+	SetAt     *time.Time `json:",omitempty"` // When the price was set, not set for the price the product was created with
+	SetBy     string     `json:",omitempty"` // Who set the price
+}
+
+// PriceAt resolves the price of a product at a point in time from its current price and the revisions changing
+// its price, oldest first: the price set by the last change up to that time, or, before the first change,
+// the price it replaced. Returns false if a revision does not hold a price.
+func PriceAt(productID uuid.UUID, current float64, revisions []ProductRevision, at time.Time) (EffectivePrice, bool) {
+	price := EffectivePrice{ProductID: productID, At: at, Price: current}
+	for i := range revisions {
+		rev := &revisions[i]
+		idx := slices.IndexFunc(rev.Changes, func(d FieldDiff) bool { return d.Field == ProductFieldPrice })
+		if idx < 0 {
+			continue
+		}
+		diff := rev.Changes[idx]
+		if rev.ChangedAt.After(at) {
+			before, ok := diff.Before.(float64)
+			if !ok {
+				return EffectivePrice{}, false
+			}
+			price.Price = before // Set by the previous change, if any
+			return price, true
+		}
+		after, ok := diff.After.(float64)
+		if !ok {
+			return EffectivePrice{}, false
+		}
+		price.Price, price.SetAt, price.SetBy = after, &rev.ChangedAt, rev.Actor
+	}
+	return price, true
+}
@@ -0,0 +1,69 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffProducts(t *testing.T) {
+	before := domain.Product{Description: "Headphones", Tags: []string{"audio"}, Quantity: 5, Price: 10, Status: domain.ProductStatusDraft}
+	after := before
+	after.Tags = []string{"audio", "sale"}
+	after.Quantity = 3
+	after.Status = domain.ProductStatusActive
+
+	assert.Equal(t, []domain.FieldDiff{
+		{Field: domain.ProductFieldTags, Before: []string{"audio"}, After: []string{"audio", "sale"}},
+		{Field: domain.ProductFieldStatus, Before: domain.ProductStatusDraft, After: domain.ProductStatusActive},
+	}, domain.DiffProducts(&before, &after))
+	assert.Empty(t, domain.DiffProducts(&before, &before))
+}
+
+func TestPriceAt(t *testing.T) {
+	id := uuid.New()
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	may := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	revisions := []domain.ProductRevision{
+		{ProductID: id, Actor: "user:1", ChangedAt: march, Changes: []domain.FieldDiff{
+			{Field: domain.ProductFieldDescription, Before: "Headphones", After: "Wireless headphones"},
+			{Field: domain.ProductFieldPrice, Before: 99.0, After: 79.0},
+		}},
+		{ProductID: id, Actor: "client:admin", ChangedAt: may, Changes: []domain.FieldDiff{
+			{Field: domain.ProductFieldPrice, Before: 79.0, After: 89.0},
+		}},
+	}
+
+	for name, tc := range map[string]struct {
+		at    time.Time
+		price float64
+		setAt *time.Time
+		setBy string
+	}{
+		"before any change":   {at: march.Add(-time.Hour), price: 99},
+		"at a change":         {at: march, price: 79, setAt: &march, setBy: "user:1"},
+		"between changes":     {at: march.AddDate(0, 1, 0), price: 79, setAt: &march, setBy: "user:1"},
+		"after the last edit": {at: may.AddDate(0, 1, 0), price: 89, setAt: &may, setBy: "client:admin"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			price, ok := domain.PriceAt(id, 89, revisions, tc.at)
+
+			require.True(t, ok)
+			assert.Equal(t, domain.EffectivePrice{ProductID: id, At: tc.at, Price: tc.price, SetAt: tc.setAt, SetBy: tc.setBy}, price)
+		})
+	}
+}
+
+func TestPriceAt_NeverChanged(t *testing.T) {
+	id := uuid.New()
+	at := time.Now()
+
+	price, ok := domain.PriceAt(id, 25, nil, at)
+
+	require.True(t, ok)
+	assert.Equal(t, domain.EffectivePrice{ProductID: id, At: at, Price: 25}, price)
+}
@@ -0,0 +1,94 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductApply(t *testing.T) {
+	product := domain.Product{Description: "Headphones", Price: 10, Tags: []string{"audio", "new"}}
+	price := 12.345
+
+	err := product.Apply(domain.ProductChange{Price: &price, AddTags: []string{"sale", "audio"}, RemoveTags: []string{"new"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 12.35, product.Price)
+	assert.Equal(t, "Headphones", product.Description)
+	assert.Equal(t, []string{"audio", "sale"}, product.Tags)
+}
+
+func TestProductApply_InvalidChangeKeepsProduct(t *testing.T) {
+	product := domain.Product{Description: "Headphones", Price: 10}
+	price, description := -1.0, "Speakers"
+
+	err := product.Apply(domain.ProductChange{Description: &description, Price: &price})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidProductChange)
+	assert.Equal(t, "Headphones", product.Description)
+	assert.Equal(t, 10.0, product.Price)
+}
+
+func TestProductCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{domain.ProductStatusDraft, domain.ProductStatusActive, true},
+		{domain.ProductStatusDraft, domain.ProductStatusArchived, true},
+		{domain.ProductStatusActive, domain.ProductStatusArchived, true},
+		{domain.ProductStatusArchived, domain.ProductStatusActive, true},
+		{domain.ProductStatusActive, domain.ProductStatusActive, true},
+		{domain.ProductStatusActive, domain.ProductStatusDraft, false},
+		{domain.ProductStatusArchived, domain.ProductStatusDraft, false},
+		{domain.ProductStatusActive, "deleted", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			product := domain.Product{Status: tt.from}
+			assert.Equal(t, tt.allowed, product.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestProductApply_InvalidStatus(t *testing.T) {
+	product := domain.Product{Status: domain.ProductStatusActive}
+	status := domain.ProductStatusDraft
+
+	err := product.Apply(domain.ProductChange{Status: &status})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidProductTransition)
+	assert.Equal(t, domain.ProductStatusActive, product.Status)
+}
+
+func TestValidateComponents(t *testing.T) {
+	component := &domain.Product{ID: uuid.New()}
+	bundle := &domain.Product{ID: uuid.New(), Components: []domain.BundleComponent{{ProductID: component.ID, Quantity: 1}}}
+	products := map[uuid.UUID]*domain.Product{component.ID: component, bundle.ID: bundle}
+
+	tests := []struct {
+		name       string
+		components []domain.BundleComponent
+		valid      bool
+	}{
+		{"valid", []domain.BundleComponent{{ProductID: component.ID, Quantity: 2}}, true},
+		{"empty", nil, false},
+		{"zero quantity", []domain.BundleComponent{{ProductID: component.ID}}, false},
+		{"repeated", []domain.BundleComponent{{ProductID: component.ID, Quantity: 1}, {ProductID: component.ID, Quantity: 1}}, false},
+		{"unknown", []domain.BundleComponent{{ProductID: uuid.New(), Quantity: 1}}, false},
+		{"nested bundle", []domain.BundleComponent{{ProductID: bundle.ID, Quantity: 1}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := domain.ValidateComponents(tt.components, products)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrInvalidBundle)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// API quota periods.
+const (
+	QuotaPeriodDay   = "day"   // Resets at midnight UTC
+	QuotaPeriodMonth = "month" // Resets on the first day of the month, midnight UTC
+)
+
+// QuotaPeriods lists quota periods from the shortest to the longest.
+var QuotaPeriods = []string{QuotaPeriodDay, QuotaPeriodMonth}
+
+// IsValidQuotaPeriod reports whether period is a known quota period.
+func IsValidQuotaPeriod(period string) bool {
+	return period == QuotaPeriodDay || period == QuotaPeriodMonth
+}
+
+// APIQuota limits the number of calls an API client may make per period.
+type APIQuota struct {
+	Client string // API key client name, e.g. "gateway"
+	Period string
+	Limit  int // 0 disables the quota
+}
+
+// QuotaUsage is the number of calls an API client made in the current window of a quota.
+type QuotaUsage struct {
+	Period  string
+	Limit   int
+	Used    int
+	ResetAt time.Time // End of the current window
+
+	// SoftLimit is the number of calls after which the client is warned that the quota is running out,
+	// 0 if the client is not warned
+	SoftLimit int
+}
+
+// Remaining returns the number of calls left in the current window.
+func (u QuotaUsage) Remaining() int {
+	return max(u.Limit-u.Used, 0)
+}
+
+// Exceeded reports whether more calls were made than the quota allows.
+func (u QuotaUsage) Exceeded() bool {
+	return u.Used > u.Limit
+}
+
+// Warned reports whether more calls were made than the soft limit, while the quota is not exceeded yet.
+func (u QuotaUsage) Warned() bool {
+	return u.SoftLimit > 0 && u.Used > u.SoftLimit && !u.Exceeded()
+}
+
+// QuotaWindow returns the start and end of the window of period that contains t, in UTC.
+func QuotaWindow(period string, t time.Time) (start, end time.Time) {
+	t = t.UTC()
+	switch period {
+	case QuotaPeriodMonth:
+		start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	default:
+		start = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 0, 1)
+	}
+}
@@ -0,0 +1,38 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaWindow(t *testing.T) {
+	at := time.Date(2024, time.December, 31, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*60*60))
+
+	start, end := domain.QuotaWindow(domain.QuotaPeriodDay, at)
+	assert.Equal(t, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC), end)
+
+	start, end = domain.QuotaWindow(domain.QuotaPeriodMonth, at)
+	assert.Equal(t, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestQuotaUsage(t *testing.T) {
+	within := domain.QuotaUsage{Limit: 10, Used: 10}
+	assert.False(t, within.Exceeded())
+	assert.Equal(t, 0, within.Remaining())
+
+	over := domain.QuotaUsage{Limit: 10, Used: 11}
+	assert.True(t, over.Exceeded())
+	assert.Equal(t, 0, over.Remaining())
+}
+
+func TestQuotaUsage_Warned(t *testing.T) {
+	assert.False(t, domain.QuotaUsage{Limit: 10, Used: 8, SoftLimit: 8}.Warned())
+	assert.True(t, domain.QuotaUsage{Limit: 10, Used: 9, SoftLimit: 8}.Warned())
+	assert.False(t, domain.QuotaUsage{Limit: 10, Used: 11, SoftLimit: 8}.Warned(), "exceeded quotas are not only warned")
+	assert.False(t, domain.QuotaUsage{Limit: 10, Used: 9}.Warned(), "warnings disabled")
+}
@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Refund request statuses.
+const (
+	RefundRequestRequested = "requested" // Waiting for a finance decision
+	RefundRequestApproved  = "approved"  // Refunded and returned items restocked
+	RefundRequestRejected  = "rejected"
+)
+
+// Refund request audit actions.
+const (
+	RefundActionRequested    = "requested"
+	RefundActionApproved     = "approved"
+	RefundActionRejected     = "rejected"
+	RefundActionRefundFailed = "refund_failed" // Approval failed, the request stays pending
+)
+
+var (
+	// ErrInvalidRefundRequest is returned when a refund request does not match the order.
+	ErrInvalidRefundRequest = errors.New("invalid refund request")
+	// ErrRefundRequestDecided is returned when a decision is made on a request that is not pending.
+	ErrRefundRequestDecided = errors.New("refund request already decided")
+	// ErrSelfApproval is returned when the requester of a refund tries to decide it.
+	ErrSelfApproval = errors.New("refund request cannot be decided by its requester")
+)
+
+// RefundItem is an order line returned with a refund request.
+type RefundItem struct {
+	ItemID   uuid.UUID
+	Quantity int
+}
+
+// RefundRequest is a refund of part of an order's charge, requested by support and
+// carried out only after approval by finance. Approval refunds the charge and restocks the returned items.
+type RefundRequest struct {
+	ID          uuid.UUID
+	OrderID     uuid.UUID
+	PaymentID   uuid.UUID // Charge to refund
+	Amount      float64
+	Items       []RefundItem // Returned order lines, empty for refunds without a return
+	Reason      string
+	Status      string
+	RequestedBy string     // e.g. "user:<id>" or "client:<name>"
+	DecidedBy   string     // Set once approved or rejected
+	DecidedAt   *time.Time // Set once approved or rejected
+	RefundID    *uuid.UUID // Refund in the payment ledger, set once approved
+	CreatedAt   time.Time
+	Events      []RefundRequestEvent // Audit trail, oldest first
+}
+
+// RefundRequestEvent is an entry in the append-only audit trail of a refund request.
+type RefundRequestEvent struct {
+	ID        uuid.UUID
+	RequestID uuid.UUID
+	Action    string
+	Actor     string
+	Note      string // Reason of the request, decision comment or refund failure
+	CreatedAt time.Time
+}
+
+// Validate checks the request against the order and its other refund requests.
+// The amount must fit the part of the charge neither refunded nor held by other pending requests,
+// and returned items must be lines of the order not returned by other pending or approved requests.
+// Items are only returned from shipped or delivered orders, cancelling an order before releases all its stock.
+func (r *RefundRequest) Validate(order *Order, others []RefundRequest) error {
+	if order.FindCharge(r.PaymentID) == nil {
+		return fmt.Errorf("%w: order has no charge %s", ErrInvalidRefundRequest, r.PaymentID)
+	}
+	if len(r.Items) > 0 && !order.AcceptsReturns() {
+		return fmt.Errorf("%w: items of %s orders cannot be returned", ErrInvalidRefundRequest, order.Status)
+	}
+
+	available := order.RefundableAmount(r.PaymentID)
+	returned := make(map[uuid.UUID]int)
+	for _, other := range others {
+		if other.ID == r.ID || other.Status == RefundRequestRejected {
+			continue
+		}
+		if other.Status == RefundRequestRequested && other.PaymentID == r.PaymentID {
+			available -= other.Amount
+		}
+		for _, item := range other.Items {
+			returned[item.ItemID] += item.Quantity
+		}
+	}
+	switch available = RoundCents(available); {
+	case r.Amount <= 0:
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidRefundRequest)
+	case r.Amount > available:
+		return fmt.Errorf("%w: amount %.2f exceeds refundable %.2f", ErrInvalidRefundRequest, r.Amount, available)
+	}
+
+	for _, item := range r.Items {
+		line := order.findItem(item.ItemID)
+		switch {
+		case line == nil || line.BundleItemID != nil:
+			return fmt.Errorf("%w: order has no line %s", ErrInvalidRefundRequest, item.ItemID)
+		case item.Quantity <= 0:
+			return fmt.Errorf("%w: returned quantity must be positive", ErrInvalidRefundRequest)
+		case item.Quantity > line.Quantity-returned[item.ItemID]:
+			return fmt.Errorf("%w: %d of line %s returned, %d not returned yet", ErrInvalidRefundRequest,
+				item.Quantity, item.ItemID, line.Quantity-returned[item.ItemID])
+		}
+		returned[item.ItemID] += item.Quantity
+	}
+	return nil
+}
+
+// Decide approves or rejects the pending request on behalf of actor.
+// Returns ErrRefundRequestDecided if the request is not pending and ErrSelfApproval if actor requested it.
+func (r *RefundRequest) Decide(status, actor string, at time.Time) error {
+	switch {
+	case status != RefundRequestApproved && status != RefundRequestRejected:
+		return fmt.Errorf("%w: unknown decision %q", ErrInvalidRefundRequest, status)
+	case r.Status != RefundRequestRequested:
+		return fmt.Errorf("%w: request is %s", ErrRefundRequestDecided, r.Status)
+	case actor == r.RequestedBy:
+		return ErrSelfApproval
+	}
+	r.Status, r.DecidedBy, r.DecidedAt = status, actor, &at
+	return nil
+}
+
+// ReturnMovements returns the stock movements restocking the request's returned items, ordered by product.
+// Returned bundles restock their components. Orders not accepting returns restock nothing, their stock
+// is released when they are cancelled.
+func (r *RefundRequest) ReturnMovements(order *Order, at time.Time) []StockMovement {
+	if !order.AcceptsReturns() {
+		return nil
+	}
+	quantities := make(map[uuid.UUID]int)
+	for _, item := range r.Items {
+		line := order.findItem(item.ItemID)
+		if line == nil {
+			continue
+		}
+		if line.AllocatesStock() {
+			quantities[line.ProductID] += item.Quantity
+			continue
+		}
+		for _, component := range order.Items {
+			if component.BundleItemID != nil && *component.BundleItemID == line.ID {
+				quantities[component.ProductID] += component.Quantity / line.Quantity * item.Quantity
+			}
+		}
+	}
+
+	movements := make([]StockMovement, 0, len(quantities))
+	for productID, quantity := range quantities {
+		movements = append(movements, StockMovement{
+			ID:        uuid.New(),
+			ProductID: productID,
+			Delta:     quantity,
+			Reason:    StockReasonReturn,
+			OrderID:   &r.OrderID,
+			Note:      "refund request " + r.ID.String(),
+			CreatedAt: at,
+		})
+	}
+	slices.SortFunc(movements, func(a, b StockMovement) int { return bytes.Compare(a.ProductID[:], b.ProductID[:]) })
+	return movements
+}
+
+// findItem returns the order line with the ID, nil if there is none.
+// AcceptsReturns reports whether items of the order can be returned to stock, which requires the order
+// to have left the warehouse.
+func (o *Order) AcceptsReturns() bool {
+	return o.Status == OrderStatusShipped || o.Status == OrderStatusDelivered
+}
+
+func (o *Order) findItem(id uuid.UUID) *OrderItem {
+	for i := range o.Items {
+		if o.Items[i].ID == id {
+			return &o.Items[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefundRequest_Validate(t *testing.T) {
+	card := charge(domain.PaymentMethodCard, 30)
+	line := domain.OrderItem{ID: uuid.New(), ProductID: uuid.New(), Quantity: 3, PriceAtPurchase: 10}
+	order := &domain.Order{Status: domain.OrderStatusShipped, TotalAmount: 30, Items: []domain.OrderItem{line},
+		Payments: []domain.Payment{card, refund(card, 5)}}
+	pending := domain.RefundRequest{ID: uuid.New(), PaymentID: card.ID, Amount: 10, Status: domain.RefundRequestRequested,
+		Items: []domain.RefundItem{{ItemID: line.ID, Quantity: 1}}}
+	rejected := domain.RefundRequest{ID: uuid.New(), PaymentID: card.ID, Amount: 25, Status: domain.RefundRequestRejected,
+		Items: []domain.RefundItem{{ItemID: line.ID, Quantity: 3}}}
+	others := []domain.RefundRequest{pending, rejected}
+
+	valid := domain.RefundRequest{PaymentID: card.ID, Amount: 15, Items: []domain.RefundItem{{ItemID: line.ID, Quantity: 2}}}
+	require.NoError(t, valid.Validate(order, others))
+
+	for name, r := range map[string]domain.RefundRequest{
+		"unknown charge":        {PaymentID: uuid.New(), Amount: 1},
+		"amount held by others": {PaymentID: card.ID, Amount: 15.01},
+		"zero amount":           {PaymentID: card.ID},
+		"unknown line":          {PaymentID: card.ID, Amount: 1, Items: []domain.RefundItem{{ItemID: uuid.New(), Quantity: 1}}},
+		"line returned":         {PaymentID: card.ID, Amount: 1, Items: []domain.RefundItem{{ItemID: line.ID, Quantity: 3}}},
+		"zero quantity":         {PaymentID: card.ID, Amount: 1, Items: []domain.RefundItem{{ItemID: line.ID}}},
+	} {
+		assert.ErrorIs(t, r.Validate(order, others), domain.ErrInvalidRefundRequest, name)
+	}
+
+	// Paid orders can be refunded, but their items are released by cancelling them
+	order.Status = domain.OrderStatusPaid
+	assert.ErrorIs(t, valid.Validate(order, others), domain.ErrInvalidRefundRequest, "items of paid order")
+	require.NoError(t, (&domain.RefundRequest{PaymentID: card.ID, Amount: 15}).Validate(order, others))
+}
+
+func TestRefundRequest_Decide(t *testing.T) {
+	r := domain.RefundRequest{Status: domain.RefundRequestRequested, RequestedBy: "client:support"}
+
+	assert.ErrorIs(t, r.Decide(domain.RefundRequestApproved, "client:support", time.Now()), domain.ErrSelfApproval)
+	assert.ErrorIs(t, r.Decide(domain.RefundRequestRequested, "client:finance", time.Now()), domain.ErrInvalidRefundRequest)
+	require.NoError(t, r.Decide(domain.RefundRequestRejected, "client:finance", time.Now()))
+	assert.Equal(t, domain.RefundRequestRejected, r.Status)
+	assert.Equal(t, "client:finance", r.DecidedBy)
+	assert.NotNil(t, r.DecidedAt)
+	assert.ErrorIs(t, r.Decide(domain.RefundRequestApproved, "client:finance", time.Now()), domain.ErrRefundRequestDecided)
+}
+
+func TestRefundRequest_ReturnMovements(t *testing.T) {
+	mug := domain.OrderItem{ID: uuid.New(), ProductID: uuid.New(), Quantity: 2, PriceAtPurchase: 5}
+	bundle := domain.OrderItem{ID: uuid.New(), ProductID: uuid.New(), Quantity: 2, PriceAtPurchase: 30, Bundle: true}
+	speaker := domain.OrderItem{ID: uuid.New(), ProductID: uuid.New(), Quantity: 2, BundleItemID: &bundle.ID}
+	cable := domain.OrderItem{ID: uuid.New(), ProductID: uuid.New(), Quantity: 4, BundleItemID: &bundle.ID}
+	order := &domain.Order{ID: uuid.New(), Status: domain.OrderStatusDelivered, Items: []domain.OrderItem{mug, bundle, speaker, cable}}
+	r := domain.RefundRequest{ID: uuid.New(), OrderID: order.ID, Items: []domain.RefundItem{
+		{ItemID: mug.ID, Quantity: 2},
+		{ItemID: bundle.ID, Quantity: 1},
+	}}
+
+	returned := make(map[uuid.UUID]int)
+	for _, m := range r.ReturnMovements(order, time.Now()) {
+		assert.Equal(t, domain.StockReasonReturn, m.Reason)
+		assert.Equal(t, order.ID, *m.OrderID)
+		returned[m.ProductID] = m.Delta
+	}
+	// One bundle of two holds one speaker and two cables
+	assert.Equal(t, map[uuid.UUID]int{mug.ProductID: 2, speaker.ProductID: 1, cable.ProductID: 2}, returned)
+
+	order.Status = domain.OrderStatusPaid
+	assert.Empty(t, r.ReturnMovements(order, time.Now()), "stock of unshipped orders is released on cancel")
+}
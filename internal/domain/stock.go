@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Stock movement reasons.
+const (
+	StockReasonReceipt    = "receipt"    // Goods received into stock
+	StockReasonAllocation = "allocation" // Stock allocated to an order
+	StockReasonAdjustment = "adjustment" // Manual correction, e.g. after a stock count
+	StockReasonReturn     = "return"     // Goods returned by a customer
+	StockReasonRelease    = "release"    // Allocation released by a cancelled order
+)
+
+// StockMovement is an append-only ledger entry changing the quantity of a product.
+// The product quantity is the sum of all its movements.
+type StockMovement struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+	Delta     int // Positive for incoming stock, negative for outgoing
+	Reason    string
+	OrderID   *uuid.UUID // Set for allocations, releases and returns
+	Note      string
+	CreatedAt time.Time
+}
+
+// IsValidStockReason reports whether reason is a known stock movement reason.
+func IsValidStockReason(reason string) bool {
+	switch reason {
+	case StockReasonReceipt, StockReasonAllocation, StockReasonAdjustment, StockReasonReturn, StockReasonRelease:
+		return true
+	}
+	return false
+}
+
+// StockDrift describes a product whose cached quantity differs from the sum of its ledger movements.
+type StockDrift struct {
+	ProductID      uuid.UUID
+	CachedQuantity int // products.quantity
+	LedgerQuantity int // Sum of stock movements
+}
+
+// StockSnapshot is the closing stock of a product on a day (UTC) with the movements of the day, derived from the ledger.
+// The opening quantity of a day is the closing quantity of the previous day, so sell-through of a period is
+// the units sold over the opening quantity plus the units received.
+type StockSnapshot struct {
+	Day             time.Time // Midnight UTC
+	ProductID       uuid.UUID
+	Quantity        int // Quantity at the end of the day
+	Received        int // Sum of receipts
+	Sold            int // Allocations to orders, net of releases by cancelled orders
+	Returned        int // Sum of customer returns
+	Adjusted        int // Sum of manual adjustments
+	StockoutSeconds int // Time the product was out of stock during the day
+}
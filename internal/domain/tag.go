@@ -0,0 +1,16 @@
+package domain
+
+import "github.com/google/uuid"
+
+// TagCount is a product tag with the number of products that have it.
+type TagCount struct {
+	Name     string
+	Products int
+}
+
+// TagChange contains the tags of a product before and after a rename or merge.
+type TagChange struct {
+	ProductID uuid.UUID
+	Before    []string
+	After     []string
+}
@@ -1,6 +1,29 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdultAge is the minimum age required to register and buy age-restricted products.
+const AdultAge = 18
+
+// User roles. Users are customers unless an admin assigns them another role.
+const (
+	RoleAdmin    = "admin"    // Manages the catalog and users
+	RoleManager  = "manager"  // Manages the catalog
+	RoleCustomer = "customer" // Browses and orders products
+)
+
+// IsValidRole reports whether the role is a known user role.
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleManager, RoleCustomer:
+		return true
+	}
+	return false
+}
 
 // User represents a user in the system.
 type User struct {
@@ -8,12 +31,60 @@ type User struct {
 	Firstname    string
 	Lastname     string
 	Email        string
-	Age          int
+	Username     string    // Optional normalized username, empty if not set
+	Birthdate    time.Time // Date of birth (time part is ignored)
 	IsMarried    bool
-	PasswordHash string // Password hash (bcrypt)
+	PasswordHash string     `json:"-"` // Password hash (bcrypt), never written to responses
+	IsActive     bool       // Inactive (deprovisioned) users cannot log in
+	Role         string     // admin, manager or customer
+	ExternalID   string     // Identifier assigned by an external identity provider (SCIM), empty if not provisioned
+	LastLoginAt  *time.Time // Time of the last successful login, nil if the user never logged in
+
+	EmailVerifiedAt *time.Time // Time the email address was verified, nil until the user opens a verification link
+
+	Phone           string     // Phone number in E.164 format, empty if not set
+	PhoneVerifiedAt *time.Time // Time the phone number was verified, nil until the user enters the code sent to it
+
+	Locale string // Preferred BCP 47 locale of emails and documents, e.g. "de-AT", empty for the configured locale
+
+	FailedLogins int        // Consecutive failed password logins since the last successful login or lockout
+	LockedUntil  *time.Time // End of the last lockout after too many failed logins, nil if the account was never locked
+
+	PasswordChangedAt *time.Time // Time the user last changed their password, nil if it was never changed
+
+	Sandbox bool `json:",omitempty"` // Test account of an integration partner, its orders allocate no stock and are paid with the mock provider
 }
 
 // FullName returns the user's full name.
 func (u *User) FullName() string {
 	return u.Firstname + " " + u.Lastname
 }
+
+// IsLocked reports whether password logins of the user are locked out at the time.
+func (u *User) IsLocked(at time.Time) bool {
+	return u.LockedUntil != nil && at.Before(*u.LockedUntil)
+}
+
+// HasVerifiedEmail reports whether the user verified the current email address.
+func (u *User) HasVerifiedEmail() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// HasVerifiedPhone reports whether the user has a phone number that was verified, so it can receive SMS.
+func (u *User) HasVerifiedPhone() bool {
+	return u.Phone != "" && u.PhoneVerifiedAt != nil
+}
+
+// Age returns the user's age in full years as of today.
+func (u *User) Age() int {
+	return AgeAt(u.Birthdate, time.Now())
+}
+
+// AgeAt returns the age in full years of a person born on birthdate as of the given moment.
+func AgeAt(birthdate, at time.Time) int {
+	age := at.Year() - birthdate.Year()
+	if at.Month() < birthdate.Month() || (at.Month() == birthdate.Month() && at.Day() < birthdate.Day()) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,49 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"product-api/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgeAt(t *testing.T) {
+	birthdate := time.Date(2000, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want int
+	}{
+		{name: "day before birthday", at: time.Date(2018, 6, 14, 0, 0, 0, 0, time.UTC), want: 17},
+		{name: "on birthday", at: time.Date(2018, 6, 15, 0, 0, 0, 0, time.UTC), want: 18},
+		{name: "month before birthday", at: time.Date(2018, 5, 20, 0, 0, 0, 0, time.UTC), want: 17},
+		{name: "after birthday", at: time.Date(2018, 12, 1, 0, 0, 0, 0, time.UTC), want: 18},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, domain.AgeAt(birthdate, tt.at))
+		})
+	}
+}
+
+func TestUser_IsLocked(t *testing.T) {
+	lockedUntil := time.Date(2024, 3, 1, 12, 15, 0, 0, time.UTC)
+	locked := domain.User{LockedUntil: &lockedUntil}
+
+	assert.False(t, (&domain.User{}).IsLocked(lockedUntil), "never locked")
+	assert.True(t, locked.IsLocked(lockedUntil.Add(-time.Second)))
+	assert.False(t, locked.IsLocked(lockedUntil), "lockout ended")
+}
+
+func TestUser_JSONOmitsPasswordHash(t *testing.T) {
+	data, err := json.Marshal(domain.User{Email: "ada@example.com", PasswordHash: "$2a$10$secret"})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "ada@example.com")
+	assert.NotContains(t, string(data), "PasswordHash")
+	assert.NotContains(t, string(data), "secret")
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrUsernameInvalid is returned when a username does not match the allowed format.
+	ErrUsernameInvalid = errors.New("username must be 3-32 characters long and contain only letters, digits, '.', '_' or '-'")
+	// ErrUsernameReserved is returned when a username is reserved by the system.
+	ErrUsernameReserved = errors.New("username is reserved")
+)
+
+// usernamePattern matches normalized usernames: starts with a letter or digit.
+var usernamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{2,31}$`)
+
+// reservedUsernames cannot be registered because they clash with routes or impersonate staff.
+var reservedUsernames = []string{
+	"admin", "administrator", "api", "me", "root", "security", "staff", "support", "system",
+}
+
+// NormalizeUsername applies normalization rules (trimming, case folding) and validates the result.
+// Returns ErrUsernameInvalid or ErrUsernameReserved if the username cannot be used.
+func NormalizeUsername(username string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(username))
+
+	if !usernamePattern.MatchString(normalized) {
+		return "", ErrUsernameInvalid
+	}
+	if slices.Contains(reservedUsernames, normalized) {
+		return "", ErrUsernameReserved
+	}
+	return normalized, nil
+}
@@ -0,0 +1,36 @@
+package domain_test
+
+import (
+	"product-api/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		want     string
+		wantErr  error
+	}{
+		{name: "lowercases and trims", username: "  John.Doe ", want: "john.doe"},
+		{name: "allows digits and dashes", username: "user_42-x", want: "user_42-x"},
+		{name: "too short", username: "jo", wantErr: domain.ErrUsernameInvalid},
+		{name: "invalid characters", username: "john doe", wantErr: domain.ErrUsernameInvalid},
+		{name: "must start with letter or digit", username: "_john", wantErr: domain.ErrUsernameInvalid},
+		{name: "reserved regardless of case", username: "Admin", wantErr: domain.ErrUsernameReserved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := domain.NormalizeUsername(tt.username)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Package event dispatches domain events within the process. Services publish events once the change they describe
+// is committed, and side effects such as notifications, cache invalidation or projections subscribe to them
+// instead of being hard-coded into the services.
+package event
+
+import (
+	"context"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Event is a domain event. Events are values, their name identifies the type subscribers receive.
+type Event interface {
+	// EventName returns the name of the event type, e.g. "order.created".
+	EventName() string
+}
+
+// OrderCreated is published when a checkout reserved the stock of a new order, before it is paid.
+// Orders of sandbox accounts are marked with Sandbox and reserve no stock.
+type OrderCreated struct {
+	Order *domain.Order
+}
+
+// EventName returns "order.created".
+func (OrderCreated) EventName() string { return "order.created" }
+
+// ProductStockChanged is published for every committed stock movement of a product,
+// e.g. an allocation to an order, a release of a cancelled order or a manual receipt.
+type ProductStockChanged struct {
+	ProductID uuid.UUID
+	Delta     int        // Change of the quantity, negative for allocations
+	Reason    string     // Reason of the stock movement, e.g. "allocation", or StockReasonRebuild
+	OrderID   *uuid.UUID // Order the stock was allocated to or released from, nil for other movements
+}
+
+// EventName returns "product.stock_changed".
+func (ProductStockChanged) EventName() string { return "product.stock_changed" }
+
+// StockReasonRebuild is the reason of a ProductStockChanged event of a quantity rebuilt from the stock ledger
+// after it drifted. No movement is recorded for it, the ledger already sums up to the new quantity.
+const StockReasonRebuild = "rebuild"
+
+// StockChanged returns the event of a committed stock movement.
+func StockChanged(m domain.StockMovement) ProductStockChanged {
+	return ProductStockChanged{ProductID: m.ProductID, Delta: m.Delta, Reason: m.Reason, OrderID: m.OrderID}
+}
+
+// UserRegistered is published when a user registered with a password.
+// Users provisioned by SCIM or identity providers are not registered.
+type UserRegistered struct {
+	User *domain.User
+}
+
+// EventName returns "user.registered".
+func (UserRegistered) EventName() string { return "user.registered" }
+
+// Publisher publishes domain events to their subscribers.
+type Publisher interface {
+	Publish(ctx context.Context, events ...Event)
+}
+
+// Handler handles a published event.
+type Handler func(ctx context.Context, e Event) error
+
+// subscription is a handler subscribed by a named listener, e.g. "search_index".
+type subscription struct {
+	listener string
+	handler  Handler
+}
+
+// Bus is a Publisher calling the handlers subscribed to an event synchronously, in the order they subscribed.
+// Listeners cannot fail the publisher: errors and panics of handlers are logged and the next handler is called.
+// Handlers needing the event to be processed reliably, e.g. outbox writers, must not rely on the bus alone,
+// as events published before a crash are lost.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions map[string][]subscription
+	logger        logger.Logger
+}
+
+// NewBus creates a bus without subscriptions.
+func NewBus(logger logger.Logger) *Bus {
+	return &Bus{subscriptions: make(map[string][]subscription), logger: logger}
+}
+
+// Subscribe calls the handler of the listener for every published event with the name.
+func (b *Bus) Subscribe(eventName, listener string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions[eventName] = append(b.subscriptions[eventName], subscription{listener: listener, handler: handler})
+}
+
+// Subscribe calls the handler of the listener for every published event of type E.
+func Subscribe[E Event](b *Bus, listener string, handler func(ctx context.Context, e E) error) {
+	var zero E
+	b.Subscribe(zero.EventName(), listener, func(ctx context.Context, e Event) error {
+		return handler(ctx, e.(E))
+	})
+}
+
+// Publish calls the handlers subscribed to the events, event by event.
+func (b *Bus) Publish(ctx context.Context, events ...Event) {
+	for _, e := range events {
+		b.mu.RLock()
+		subscriptions := b.subscriptions[e.EventName()]
+		b.mu.RUnlock()
+
+		for _, s := range subscriptions {
+			if err := b.handle(ctx, s, e); err != nil {
+				b.logger.WithTrace(ctx).Error("event listener failed", "event", e.EventName(), "listener", s.listener, "error", err)
+			}
+		}
+	}
+}
+
+// handle calls the handler of the subscription, turning a panic into an error.
+func (b *Bus) handle(ctx context.Context, s subscription, e Event) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v", rec)
+		}
+	}()
+	return s.handler(ctx, e)
+}
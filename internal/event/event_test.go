@@ -0,0 +1,43 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus_Publish(t *testing.T) {
+	bus := NewBus(logger.NewSlogAdapter("local"))
+	ctx := context.Background()
+
+	var calls []string
+	Subscribe(bus, "failing", func(context.Context, UserRegistered) error {
+		calls = append(calls, "failing")
+		return errors.New("listener failed")
+	})
+	Subscribe(bus, "panicking", func(context.Context, UserRegistered) error {
+		calls = append(calls, "panicking")
+		panic("listener panicked")
+	})
+	Subscribe(bus, "welcome", func(_ context.Context, e UserRegistered) error {
+		calls = append(calls, "welcome:"+e.User.Email)
+		return nil
+	})
+	bus.Subscribe("product.stock_changed", "projection", func(_ context.Context, e Event) error {
+		calls = append(calls, "projection:"+e.(ProductStockChanged).Reason)
+		return nil
+	})
+
+	// Handlers run in subscription order, failing handlers do not stop the others
+	bus.Publish(ctx,
+		UserRegistered{User: &domain.User{Email: "ada@example.com"}},
+		StockChanged(domain.StockMovement{ProductID: uuid.New(), Delta: -2, Reason: domain.StockReasonAllocation}),
+		OrderCreated{Order: &domain.Order{}}, // No subscribers
+	)
+	assert.Equal(t, []string{"failing", "panicking", "welcome:ada@example.com", "projection:allocation"}, calls)
+}
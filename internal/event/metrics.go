@@ -0,0 +1,19 @@
+package event
+
+import (
+	"context"
+	"product-api/internal/telemetry"
+)
+
+// SubscribeMetrics subscribes the "metrics" listener, which counts committed stock movements by reason
+// and user registrations as business metrics.
+func SubscribeMetrics(b *Bus) {
+	Subscribe(b, "metrics", func(ctx context.Context, e ProductStockChanged) error {
+		telemetry.RecordStockMovement(ctx, e.Reason)
+		return nil
+	})
+	Subscribe(b, "metrics", func(ctx context.Context, _ UserRegistered) error {
+		telemetry.RecordUserRegistration(ctx)
+		return nil
+	})
+}
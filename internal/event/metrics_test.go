@@ -0,0 +1,50 @@
+package event
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestSubscribeMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	bus := NewBus(logger.NewSlogAdapter("local"))
+	SubscribeMetrics(bus)
+	ctx := context.Background()
+
+	productID := uuid.New()
+	bus.Publish(ctx,
+		StockChanged(domain.StockMovement{ProductID: productID, Delta: -2, Reason: domain.StockReasonAllocation}),
+		StockChanged(domain.StockMovement{ProductID: productID, Delta: -1, Reason: domain.StockReasonAllocation}),
+		ProductStockChanged{ProductID: productID, Delta: 3, Reason: StockReasonRebuild},
+		UserRegistered{User: &domain.User{Email: "ada@example.com"}},
+		OrderCreated{Order: &domain.Order{}},
+	)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	sums := map[string]metricdata.Sum[int64]{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		sums[m.Name] = m.Data.(metricdata.Sum[int64])
+	}
+
+	movements := map[string]int64{}
+	for _, dp := range sums["stock.movements"].DataPoints {
+		reason, _ := dp.Attributes.Value(attribute.Key("reason"))
+		movements[reason.AsString()] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{domain.StockReasonAllocation: 2, StockReasonRebuild: 1}, movements)
+	require.Len(t, sums["users.registrations"].DataPoints, 1)
+	assert.Equal(t, int64(1), sums["users.registrations"].DataPoints[0].Value)
+}
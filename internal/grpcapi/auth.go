@@ -0,0 +1,106 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	productapiv1 "product-api/pkg/productapi/v1"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// contextKey is used for safe value storage in context.
+type contextKey string
+
+// userKey is the key for storing the authenticated user in context.
+const userKey contextKey = "user"
+
+// UserFromContext returns the user authenticated by AuthInterceptor.
+func UserFromContext(ctx context.Context) (*domain.User, bool) {
+	user, ok := ctx.Value(userKey).(*domain.User)
+	return user, ok
+}
+
+// methodRoles lists the roles allowed to call methods restricted to some roles.
+var methodRoles = map[string][]string{
+	productapiv1.ProductService_CreateProduct_FullMethodName: {domain.RoleAdmin, domain.RoleManager},
+}
+
+// isPublic reports whether the method is served without authentication, e.g. health checks of load balancers.
+func isPublic(method string) bool {
+	return strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
+
+// AuthInterceptor authenticates unary calls with the access token in the "authorization" metadata,
+// format "Bearer <token>", with the checks of the HTTP API: revoked tokens and deleted users are UNAUTHENTICATED,
+// as are tokens issued before a role or password change; disabled users and missing roles are PERMISSION_DENIED,
+// and users who have not accepted the latest legal documents are FAILED_PRECONDITION.
+// The authenticated user is added to the context (see UserFromContext).
+func AuthInterceptor(tokens *service.TokenService, users *service.UsersService, consents *service.ConsentService, l logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isPublic(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		log := l.WithTrace(ctx)
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "missing auth token")
+		}
+		tokenString, ok := strings.CutPrefix(values[0], "Bearer ")
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "invalid auth token format")
+		}
+
+		claims, err := tokens.Parse(tokenString)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		revoked, err := tokens.IsRevoked(ctx, claims)
+		if err != nil {
+			log.Error("failed to check token revocation", "method", info.FullMethod, "error", err)
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+		if revoked {
+			return nil, status.Error(codes.Unauthenticated, "token revoked")
+		}
+
+		user, err := users.GetUser(ctx, claims.UserID)
+		if err != nil {
+			if errors.Is(err, service.ErrUserNotFound) {
+				return nil, status.Error(codes.Unauthenticated, "invalid token")
+			}
+			log.Error("failed to load current user", "method", info.FullMethod, "error", err)
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+		if !user.IsActive {
+			return nil, status.Error(codes.PermissionDenied, "account is disabled")
+		}
+		// Tokens carry the role at login, a changed role or password requires logging in again
+		if !claims.IsCurrentFor(user) {
+			return nil, status.Error(codes.Unauthenticated, "role or password changed, log in again")
+		}
+		if roles, ok := methodRoles[info.FullMethod]; ok && !claims.HasRole(roles...) {
+			return nil, status.Error(codes.PermissionDenied, "insufficient role")
+		}
+
+		pending, err := consents.PendingDocuments(ctx, user.ID)
+		if err != nil {
+			log.Error("failed to check pending legal documents", "method", info.FullMethod, "error", err)
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+		if len(pending) > 0 {
+			return nil, status.Error(codes.FailedPrecondition, "consent required: the latest legal documents must be accepted")
+		}
+
+		return handler(context.WithValue(ctx, userKey, user), req)
+	}
+}
@@ -0,0 +1,50 @@
+package grpcapi_test
+
+import (
+	"context"
+	"product-api/internal/grpcapi"
+	"product-api/internal/logger"
+	productapiv1 "product-api/pkg/productapi/v1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	// Calls rejected before the token is parsed do not reach the services
+	interceptor := grpcapi.AuthInterceptor(nil, nil, nil, logger.NewSlogAdapter("local"))
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+
+	tests := []struct {
+		name   string
+		method string
+		md     metadata.MD
+		want   codes.Code
+	}{
+		{name: "missing token", method: productapiv1.ProductService_GetProduct_FullMethodName, want: codes.Unauthenticated},
+		{name: "not a bearer token", method: productapiv1.OrderService_ListOrders_FullMethodName, md: metadata.Pairs("authorization", "Basic dXNlcjpwYXNz"), want: codes.Unauthenticated},
+		{name: "public health check", method: healthpb.Health_Check_FullMethodName, want: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			assert.Equal(t, tt.want, status.Code(err))
+		})
+	}
+}
+
+func TestRecoveryInterceptor(t *testing.T) {
+	interceptor := grpcapi.RecoveryInterceptor(logger.NewSlogAdapter("local"))
+	handler := func(ctx context.Context, req any) (any, error) { panic("boom") }
+
+	resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: productapiv1.ProductService_GetProduct_FullMethodName}, handler)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
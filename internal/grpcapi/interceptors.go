@@ -0,0 +1,53 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"runtime/debug"
+
+	"github.com/getsentry/sentry-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryInterceptor recovers from panics in handlers of unary calls, which would otherwise crash the server.
+// Logs the panic with its stack, reports it to Sentry tagged with the method and fails the call with INTERNAL.
+func RecoveryInterceptor(l logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			args := []any{"panic", fmt.Sprint(rec), "method", info.FullMethod, "stack", string(debug.Stack())}
+			if user, ok := UserFromContext(ctx); ok {
+				args = append(args, "user_id", user.ID)
+			}
+			l.WithTrace(ctx).Error("panic recovered", args...)
+
+			hub := sentry.CurrentHub().Clone()
+			hub.WithScope(func(scope *sentry.Scope) {
+				scope.SetTag("grpc.method", info.FullMethod)
+				hub.RecoverWithContext(ctx, rec)
+			})
+			resp, err = nil, status.Error(codes.Internal, "internal server error")
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// PoolExhaustionInterceptor fails unary calls with UNAVAILABLE instead of INTERNAL when no database connection
+// was available in time (see repository.ErrPoolExhausted), so clients back off and retry.
+func PoolExhaustionInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = repository.WithPoolExhaustion(ctx)
+		resp, err := handler(ctx, req)
+		if status.Code(err) == codes.Internal && repository.PoolExhausted(ctx) {
+			return nil, status.Error(codes.Unavailable, "service temporarily unavailable, retry later")
+		}
+		return resp, err
+	}
+}
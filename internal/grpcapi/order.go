@@ -0,0 +1,191 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/money"
+	"product-api/internal/service"
+	productapiv1 "product-api/pkg/productapi/v1"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Order listing page size limits, as in the HTTP API.
+const (
+	defaultOrderListLimit = 20
+	maxOrderListLimit     = 100
+)
+
+// OrderServer implements the gRPC OrderService with the order service.
+type OrderServer struct {
+	productapiv1.UnimplementedOrderServiceServer
+	service *service.OrderService
+	money   *money.Formatter
+	logger  logger.Logger
+}
+
+// NewOrderServer creates a new order server. Amounts are formatted in the currency and locale of each order.
+func NewOrderServer(s *service.OrderService, f *money.Formatter, l logger.Logger) *OrderServer {
+	return &OrderServer{service: s, money: f, logger: l}
+}
+
+func (s *OrderServer) CreateOrder(ctx context.Context, req *productapiv1.CreateOrderRequest) (*productapiv1.Order, error) {
+	const op = "OrderServer.CreateOrder"
+
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth token")
+	}
+	switch {
+	case len(req.GetItems()) == 0:
+		return nil, status.Error(codes.InvalidArgument, "items are required")
+	case len(req.GetPaymentProvider()) > 32:
+		return nil, status.Error(codes.InvalidArgument, "payment_provider must be at most 32 characters")
+	case len(req.GetPaymentToken()) > 255:
+		return nil, status.Error(codes.InvalidArgument, "payment_token must be at most 255 characters")
+	}
+	items := make([]service.OrderItemInput, len(req.GetItems()))
+	for i, item := range req.GetItems() {
+		productID, err := uuid.Parse(item.GetProductId())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid product ID")
+		}
+		if item.GetQuantity() <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "quantity must be positive")
+		}
+		items[i] = service.OrderItemInput{ProductID: productID, Quantity: int(item.GetQuantity())}
+	}
+
+	// Order options and delivery slots are not offered through gRPC yet, and as clients cannot confirm
+	// possible duplicates, checkouts are placed without the duplicate check
+	source := service.PaymentSource{Provider: req.GetPaymentProvider(), Token: req.GetPaymentToken()}
+	order, err := s.service.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, uuid.Nil, true, source)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownPaymentProvider):
+			return nil, status.Error(codes.InvalidArgument, "unknown payment provider")
+		case errors.Is(err, service.ErrProductNotFound):
+			return nil, status.Error(codes.InvalidArgument, "one or more products not found")
+		case errors.Is(err, service.ErrQuantityLimit):
+			return nil, status.Error(codes.InvalidArgument, "ordered quantity of one or more products is outside the allowed range")
+		case errors.Is(err, service.ErrProductUnavailable):
+			return nil, status.Error(codes.FailedPrecondition, "one or more products are not available for ordering")
+		case errors.Is(err, service.ErrInsufficientStock):
+			return nil, status.Error(codes.FailedPrecondition, "insufficient stock for one or more products")
+		case errors.Is(err, service.ErrAgeRestricted):
+			return nil, status.Error(codes.PermissionDenied, "buyer does not meet the age restriction of one or more products")
+		case errors.Is(err, service.ErrPaymentFailed):
+			return nil, status.Error(codes.Aborted, "payment failed, the order was cancelled")
+		}
+		s.logger.WithTrace(ctx).Error("failed to create order", "op", op, "error", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	return s.order(ctx, op, order, true)
+}
+
+func (s *OrderServer) GetOrder(ctx context.Context, req *productapiv1.GetOrderRequest) (*productapiv1.Order, error) {
+	const op = "OrderServer.GetOrder"
+
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth token")
+	}
+	orderID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order ID")
+	}
+	order, err := s.service.GetOrder(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			return nil, status.Error(codes.NotFound, "order not found")
+		}
+		s.logger.WithTrace(ctx).Error("failed to get order", "op", op, "error", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	if order.UserID != user.ID {
+		return nil, status.Error(codes.PermissionDenied, "order belongs to another user")
+	}
+	return s.order(ctx, op, order, true)
+}
+
+func (s *OrderServer) ListOrders(ctx context.Context, req *productapiv1.ListOrdersRequest) (*productapiv1.ListOrdersResponse, error) {
+	const op = "OrderServer.ListOrders"
+
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth token")
+	}
+	limit, offset := int(req.GetLimit()), int(req.GetOffset())
+	if limit == 0 {
+		limit = defaultOrderListLimit
+	}
+	if limit < 0 || limit > maxOrderListLimit {
+		return nil, status.Error(codes.InvalidArgument, "invalid limit")
+	}
+	if offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid offset")
+	}
+
+	page, err := s.service.ListUserOrders(ctx, user.ID, offset, limit)
+	if err != nil {
+		s.logger.WithTrace(ctx).Error("failed to list orders", "op", op, "error", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	resp := &productapiv1.ListOrdersResponse{Orders: make([]*productapiv1.Order, len(page.Orders)), Total: int32(page.Total)}
+	for i := range page.Orders {
+		if resp.Orders[i], err = s.order(ctx, op, &page.Orders[i], false); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// order converts the order to its message with its amounts in the currency and locale it was placed with.
+// Paid and refunded amounts are only set with payments, order lists are loaded without them.
+func (s *OrderServer) order(ctx context.Context, op string, o *domain.Order, payments bool) (*productapiv1.Order, error) {
+	f, err := s.money.In(o.Currency, o.Locale)
+	if err != nil {
+		s.logger.WithTrace(ctx).Error("failed to format order amounts", "op", op, "order_id", o.ID, "error", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	msg := &productapiv1.Order{
+		Id:        o.ID.String(),
+		Number:    o.Number,
+		UserId:    o.UserID.String(),
+		Status:    o.Status,
+		Items:     make([]*productapiv1.OrderItem, len(o.Items)),
+		CreatedAt: timestamppb.New(o.CreatedAt),
+		Total:     moneyMessage(f.Money(o.TotalAmount)),
+		Locale:    o.Locale,
+		TaxRegion: o.TaxRegion,
+		Sandbox:   o.Sandbox,
+	}
+	if payments {
+		msg.Paid = moneyMessage(f.Money(o.PaidAmount()))
+		msg.Refunded = moneyMessage(f.Money(o.RefundedAmount()))
+	}
+	for i, item := range o.Items {
+		msg.Items[i] = &productapiv1.OrderItem{
+			Id:              item.ID.String(),
+			ProductId:       item.ProductID.String(),
+			Quantity:        int32(item.Quantity),
+			PriceAtPurchase: item.PriceAtPurchase,
+			Bundle:          item.Bundle,
+		}
+		if item.BundleItemID != nil {
+			msg.Items[i].BundleItemId = item.BundleItemID.String()
+		}
+	}
+	return msg, nil
+}
+
+// moneyMessage converts an amount to its message.
+func moneyMessage(m money.Money) *productapiv1.Money {
+	return &productapiv1.Money{Amount: m.Amount, Currency: m.Currency, MinorUnits: int32(m.MinorUnits), Formatted: m.Formatted}
+}
@@ -0,0 +1,194 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	productapiv1 "product-api/pkg/productapi/v1"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Product listing page size limits, as in the HTTP API.
+const (
+	defaultProductListLimit = 20
+	maxProductListLimit     = 100
+)
+
+// ProductServer implements the gRPC ProductService with the product service.
+type ProductServer struct {
+	productapiv1.UnimplementedProductServiceServer
+	service *service.ProductService
+	logger  logger.Logger
+}
+
+// NewProductServer creates a new product server.
+func NewProductServer(s *service.ProductService, l logger.Logger) *ProductServer {
+	return &ProductServer{service: s, logger: l}
+}
+
+func (s *ProductServer) GetProduct(ctx context.Context, req *productapiv1.GetProductRequest) (*productapiv1.Product, error) {
+	const op = "ProductServer.GetProduct"
+
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid product ID")
+	}
+	product, err := s.service.GetProductByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			return nil, status.Error(codes.NotFound, "product not found")
+		}
+		s.logger.WithTrace(ctx).Error("failed to get product by id", "op", op, "error", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	return s.product(ctx, op, product)
+}
+
+func (s *ProductServer) GetProductByBarcode(ctx context.Context, req *productapiv1.GetProductByBarcodeRequest) (*productapiv1.Product, error) {
+	const op = "ProductServer.GetProductByBarcode"
+
+	product, err := s.service.GetProductByBarcode(ctx, req.GetBarcode())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidBarcode):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, service.ErrProductNotFound):
+			return nil, status.Error(codes.NotFound, "product not found")
+		}
+		s.logger.WithTrace(ctx).Error("failed to get product by barcode", "op", op, "error", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	return s.product(ctx, op, product)
+}
+
+func (s *ProductServer) ListProducts(ctx context.Context, req *productapiv1.ListProductsRequest) (*productapiv1.ListProductsResponse, error) {
+	const op = "ProductServer.ListProducts"
+
+	in := service.ProductListInput{
+		Tags:     req.GetTags(),
+		MinPrice: req.MinPrice,
+		MaxPrice: req.MaxPrice,
+		InStock:  req.GetInStock(),
+		Offset:   int(req.GetOffset()),
+		Limit:    int(req.GetLimit()),
+	}
+	if in.Limit == 0 {
+		in.Limit = defaultProductListLimit
+	}
+	if in.Limit < 0 || in.Limit > maxProductListLimit {
+		return nil, status.Error(codes.InvalidArgument, "invalid limit")
+	}
+	if in.Offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid offset")
+	}
+
+	page, err := s.service.ListProducts(ctx, in)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPriceRange) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		s.logger.WithTrace(ctx).Error("failed to list products", "op", op, "error", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	resp := &productapiv1.ListProductsResponse{Products: make([]*productapiv1.Product, len(page.Products)), Total: int32(page.Total)}
+	for i := range page.Products {
+		if resp.Products[i], err = s.product(ctx, op, &page.Products[i]); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (s *ProductServer) CreateProduct(ctx context.Context, req *productapiv1.CreateProductRequest) (*productapiv1.Product, error) {
+	const op = "ProductServer.CreateProduct"
+
+	if err := validateCreateProduct(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	product, err := s.service.CreateProduct(ctx, service.CreateProductInput{
+		Description:    req.GetDescription(),
+		Tags:           req.GetTags(),
+		Quantity:       int(req.GetQuantity()),
+		Price:          req.GetPrice(),
+		AgeRestriction: int(req.GetAgeRestriction()),
+		Status:         req.GetStatus(),
+		Barcode:        req.GetBarcode(),
+		Category:       req.GetCategory(),
+		Attributes:     req.GetAttributes().AsMap(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidProductTransition), errors.Is(err, domain.ErrInvalidBarcode),
+			errors.Is(err, domain.ErrInvalidAttributes):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, service.ErrBarcodeTaken):
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+		s.logger.WithTrace(ctx).Error("failed to create product", "op", op, "error", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	return s.product(ctx, op, product)
+}
+
+// validateCreateProduct checks the request with the rules of the HTTP API.
+func validateCreateProduct(req *productapiv1.CreateProductRequest) error {
+	switch {
+	case req.GetDescription() == "":
+		return errors.New("description is required")
+	case req.GetQuantity() <= 0:
+		return errors.New("quantity must be positive")
+	case req.GetPrice() <= 0:
+		return errors.New("price must be positive")
+	case req.GetAgeRestriction() < 0 || req.GetAgeRestriction() > 99:
+		return errors.New("age_restriction must be between 0 and 99")
+	case req.GetStatus() != "" && req.GetStatus() != domain.ProductStatusDraft && req.GetStatus() != domain.ProductStatusActive:
+		return errors.New("status must be draft or active")
+	case len(req.GetCategory()) > 100:
+		return errors.New("category must be at most 100 characters")
+	}
+	return nil
+}
+
+// product converts the product to its message, failing the call if its attributes cannot be represented.
+func (s *ProductServer) product(ctx context.Context, op string, p *domain.Product) (*productapiv1.Product, error) {
+	msg, err := productMessage(p)
+	if err != nil {
+		s.logger.WithTrace(ctx).Error("failed to convert product", "op", op, "product_id", p.ID, "error", err)
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	return msg, nil
+}
+
+// productMessage converts a product to its message.
+func productMessage(p *domain.Product) (*productapiv1.Product, error) {
+	msg := &productapiv1.Product{
+		Id:             p.ID.String(),
+		Description:    p.Description,
+		Tags:           p.Tags,
+		Quantity:       int32(p.Quantity),
+		Price:          p.Price,
+		AgeRestriction: int32(p.AgeRestriction),
+		Status:         p.Status,
+		Barcode:        p.Barcode,
+		Category:       p.Category,
+	}
+	if len(p.Attributes) > 0 {
+		attributes, err := structpb.NewStruct(p.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("attributes: %w", err)
+		}
+		msg.Attributes = attributes
+	}
+	for _, c := range p.Components {
+		msg.Components = append(msg.Components, &productapiv1.BundleComponent{ProductId: c.ProductID.String(), Quantity: int32(c.Quantity)})
+	}
+	return msg, nil
+}
@@ -0,0 +1,80 @@
+// Package grpcapi serves the product and order services over gRPC for internal callers,
+// alongside the HTTP API and with the same service layer and access tokens.
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"product-api/internal/logger"
+	"product-api/internal/money"
+	"product-api/internal/service"
+	productapiv1 "product-api/pkg/productapi/v1"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Services are the application services exposed over gRPC.
+type Services struct {
+	Products *service.ProductService
+	Orders   *service.OrderService
+	Tokens   *service.TokenService
+	Users    *service.UsersService
+	Consents *service.ConsentService
+	Money    *money.Formatter // Formats order amounts in the currency and locale of each order
+}
+
+// Server is the gRPC server with the product, order and health services.
+type Server struct {
+	server *grpc.Server
+	health *health.Server
+}
+
+// NewServer creates a gRPC server. Calls are traced and measured with OpenTelemetry,
+// recovered from panics and authenticated with access tokens (see AuthInterceptor).
+func NewServer(s Services, l logger.Logger) *Server {
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			RecoveryInterceptor(l),
+			PoolExhaustionInterceptor(),
+			AuthInterceptor(s.Tokens, s.Users, s.Consents, l),
+		),
+	)
+	productapiv1.RegisterProductServiceServer(server, NewProductServer(s.Products, l))
+	productapiv1.RegisterOrderServiceServer(server, NewOrderServer(s.Orders, s.Money, l))
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	return &Server{server: server, health: healthServer}
+}
+
+// Serve accepts connections on the listener until the server is stopped.
+func (s *Server) Serve(l net.Listener) error {
+	return s.server.Serve(l)
+}
+
+// Drain reports the server as not serving to health checks, so load balancers stop routing new calls to it.
+func (s *Server) Drain() {
+	s.health.Shutdown()
+}
+
+// Shutdown stops accepting connections and waits for in-flight calls to complete.
+// When the context is done first, remaining connections are closed and the context error is returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
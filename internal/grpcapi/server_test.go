@@ -0,0 +1,224 @@
+package grpcapi_test
+
+import (
+	"context"
+	"net"
+	"product-api/internal/domain"
+	"product-api/internal/event"
+	"product-api/internal/grpcapi"
+	"product-api/internal/logger"
+	"product-api/internal/money"
+	"product-api/internal/notification"
+	"product-api/internal/payment"
+	"product-api/internal/repository"
+	"product-api/internal/repository/postgres"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"product-api/internal/testutil/testdb"
+	productapiv1 "product-api/pkg/productapi/v1"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// ServerTestSuite calls the gRPC server over an in-memory connection, with the services on a test database.
+type ServerTestSuite struct {
+	suite.Suite
+	dbpool      *pgxpool.Pool
+	userRepo    repository.UserRepository
+	productRepo repository.ProductRepository
+	users       *service.UsersService
+	products    productapiv1.ProductServiceClient
+	orders      productapiv1.OrderServiceClient
+}
+
+func (s *ServerTestSuite) SetupTest() {
+	s.dbpool = testdb.New(s.T())
+	s.userRepo = postgres.NewUserRepository(s.dbpool)
+	s.productRepo = postgres.NewProductRepository(s.dbpool)
+
+	log := logger.NewSlogAdapter("local")
+	keys := service.NewHMACTokenKeys([]byte("test-secret"))
+	s.users = service.NewUsersService(s.userRepo, postgres.NewLoginAttemptRepository(s.dbpool), postgres.NewIdentityRepository(s.dbpool), keys, time.Hour,
+		service.LoginLockout{MaxFailures: 3, Duration: 15 * time.Minute}, event.NewBus(log), log)
+	formatter, err := money.NewFormatter("USD", "en-US")
+	s.Require().NoError(err)
+	checks, err := service.NewCheckoutPipelineOf(service.DefaultCheckoutValidators, service.QuantityLimits{})
+	s.Require().NoError(err)
+	provider := payment.NewMemoryProvider("", log)
+	notifier := notification.NewDispatcher(s.userRepo, postgres.NewPreferenceRepository(s.dbpool), notification.NewTemplates(postgres.NewNotificationTemplateRepository(s.dbpool), time.Minute, log), log,
+		notification.NewLogSender(domain.NotificationChannelEmail, log))
+	orders := service.NewOrderService(service.OrderServiceDeps{
+		DB:          s.dbpool,
+		OrderRepo:   postgres.NewOrderRepository(s.dbpool),
+		EventRepo:   postgres.NewOrderEventRepository(s.dbpool),
+		Numbers:     postgres.NewOrderNumberRepository(s.dbpool),
+		PaymentRepo: postgres.NewPaymentRepository(s.dbpool),
+		RefundRepo:  postgres.NewRefundRequestRepository(s.dbpool),
+		DisputeRepo: postgres.NewDisputeRepository(s.dbpool),
+		ProductRepo: s.productRepo,
+		StockRepo:   postgres.NewStockRepository(s.dbpool),
+		SlotRepo:    postgres.NewDeliverySlotRepository(s.dbpool),
+		UserRepo:    s.userRepo,
+		Notifier:    notifier,
+		Providers:   payment.NewRegistry(provider).WithSandbox(provider),
+		Formatter:   formatter,
+		TaxRegion:   "US-CA",
+		Region:      "eu-west-1",
+		Checks:      checks,
+		OptionFees:  service.OrderOptionFees{},
+		Events:      event.NewBus(log),
+		Logger:      log,
+	})
+
+	server := grpcapi.NewServer(grpcapi.Services{
+		Products: service.NewProductService(s.dbpool, s.productRepo, postgres.NewProductRevisionRepository(s.dbpool), postgres.NewAttributeRepository(s.dbpool)),
+		Orders:   orders,
+		Tokens:   service.NewTokenService(postgres.NewTokenRepository(s.dbpool), s.userRepo, keys),
+		Users:    s.users,
+		Consents: service.NewConsentService(postgres.NewConsentRepository(s.dbpool)),
+		Money:    formatter,
+	}, log)
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	s.T().Cleanup(func() { _ = server.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = conn.Close() })
+	s.products = productapiv1.NewProductServiceClient(conn)
+	s.orders = productapiv1.NewOrderServiceClient(conn)
+}
+
+// login creates a user and returns a context calling the server with the user's access token.
+func (s *ServerTestSuite) login(opts ...factory.UserOption) (context.Context, *domain.User) {
+	user := factory.CreateUser(s.T(), s.userRepo, opts...)
+	token, err := s.users.Login(context.Background(), user.Email, factory.DefaultPassword, service.LoginMetadata{IPAddress: "127.0.0.1", UserAgent: "test-agent"})
+	s.Require().NoError(err)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token), user
+}
+
+func (s *ServerTestSuite) TestCreateAndGetOrder() {
+	ctx, user := s.login()
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(10), factory.WithQuantity(5))
+
+	created, err := s.orders.CreateOrder(ctx, &productapiv1.CreateOrderRequest{
+		Items: []*productapiv1.OrderItemInput{{ProductId: product.ID.String(), Quantity: 2}},
+	})
+	s.Require().NoError(err)
+	s.Equal(user.ID.String(), created.GetUserId())
+	s.Require().Len(created.GetItems(), 1)
+	s.Equal(product.ID.String(), created.GetItems()[0].GetProductId())
+	s.Equal(int32(2), created.GetItems()[0].GetQuantity())
+	s.Equal("USD", created.GetTotal().GetCurrency())
+	s.Equal("US-CA", created.GetTaxRegion())
+	s.False(created.GetSandbox())
+
+	got, err := s.orders.GetOrder(ctx, &productapiv1.GetOrderRequest{Id: created.GetId()})
+	s.Require().NoError(err)
+	s.Equal(created.GetNumber(), got.GetNumber())
+	s.Equal(created.GetTotal().GetAmount(), got.GetTotal().GetAmount())
+	s.Equal(created.GetTotal().GetAmount(), got.GetPaid().GetAmount(), "the order is paid at checkout")
+
+	stored, err := s.productRepo.FindByID(context.Background(), product.ID)
+	s.Require().NoError(err)
+	s.Equal(3, stored.Quantity)
+}
+
+func (s *ServerTestSuite) TestCreateOrderOfSandboxAccount() {
+	ctx, user := s.login()
+	_, err := s.dbpool.Exec(context.Background(), `UPDATE users SET sandbox = TRUE WHERE id = $1`, user.ID)
+	s.Require().NoError(err)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(5))
+
+	created, err := s.orders.CreateOrder(ctx, &productapiv1.CreateOrderRequest{
+		Items: []*productapiv1.OrderItemInput{{ProductId: product.ID.String(), Quantity: 2}},
+	})
+	s.Require().NoError(err)
+	s.True(created.GetSandbox())
+
+	got, err := s.orders.GetOrder(ctx, &productapiv1.GetOrderRequest{Id: created.GetId()})
+	s.Require().NoError(err)
+	s.True(got.GetSandbox())
+	stored, err := s.productRepo.FindByID(context.Background(), product.ID)
+	s.Require().NoError(err)
+	s.Equal(5, stored.Quantity, "sandbox orders allocate no stock")
+}
+
+func (s *ServerTestSuite) TestCreateOrder_Errors() {
+	ctx, _ := s.login()
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(1))
+
+	tests := []struct {
+		name  string
+		items []*productapiv1.OrderItemInput
+		want  codes.Code
+	}{
+		{name: "no items", want: codes.InvalidArgument},
+		{name: "invalid product ID", items: []*productapiv1.OrderItemInput{{ProductId: "42", Quantity: 1}}, want: codes.InvalidArgument},
+		{name: "unknown product", items: []*productapiv1.OrderItemInput{{ProductId: uuid.NewString(), Quantity: 1}}, want: codes.InvalidArgument},
+		{name: "insufficient stock", items: []*productapiv1.OrderItemInput{{ProductId: product.ID.String(), Quantity: 2}}, want: codes.FailedPrecondition},
+	}
+	for _, tt := range tests {
+		_, err := s.orders.CreateOrder(ctx, &productapiv1.CreateOrderRequest{Items: tt.items})
+		s.Equal(tt.want, status.Code(err), tt.name)
+	}
+}
+
+func (s *ServerTestSuite) TestGetOrder_Errors() {
+	ctx, _ := s.login()
+	otherCtx, _ := s.login()
+	product := factory.CreateProduct(s.T(), s.productRepo)
+	created, err := s.orders.CreateOrder(ctx, &productapiv1.CreateOrderRequest{
+		Items: []*productapiv1.OrderItemInput{{ProductId: product.ID.String(), Quantity: 1}},
+	})
+	s.Require().NoError(err)
+
+	_, err = s.orders.GetOrder(otherCtx, &productapiv1.GetOrderRequest{Id: created.GetId()})
+	s.Equal(codes.PermissionDenied, status.Code(err), "orders of other users")
+	_, err = s.orders.GetOrder(ctx, &productapiv1.GetOrderRequest{Id: uuid.NewString()})
+	s.Equal(codes.NotFound, status.Code(err))
+	_, err = s.orders.GetOrder(context.Background(), &productapiv1.GetOrderRequest{Id: created.GetId()})
+	s.Equal(codes.Unauthenticated, status.Code(err), "calls without a token")
+}
+
+func (s *ServerTestSuite) TestListProducts() {
+	ctx, _ := s.login()
+	cheap := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(5), factory.WithTags("sale"))
+	factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(50), factory.WithTags("sale"))
+	factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(5), factory.WithTags("new"))
+	factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(5), factory.WithTags("sale"), factory.WithStatus(domain.ProductStatusDraft))
+
+	page, err := s.products.ListProducts(ctx, &productapiv1.ListProductsRequest{Limit: 2})
+	s.Require().NoError(err)
+	s.Len(page.GetProducts(), 2)
+	s.Equal(int32(3), page.GetTotal(), "only active products are listed")
+
+	maxPrice := 10.0
+	page, err = s.products.ListProducts(ctx, &productapiv1.ListProductsRequest{Tags: []string{"sale"}, MaxPrice: &maxPrice})
+	s.Require().NoError(err)
+	s.Require().Len(page.GetProducts(), 1)
+	s.Equal(cheap.ID.String(), page.GetProducts()[0].GetId())
+	s.Equal([]string{"sale"}, page.GetProducts()[0].GetTags())
+
+	_, err = s.products.ListProducts(ctx, &productapiv1.ListProductsRequest{Limit: 101})
+	s.Equal(codes.InvalidArgument, status.Code(err))
+	minPrice := 20.0
+	_, err = s.products.ListProducts(ctx, &productapiv1.ListProductsRequest{MinPrice: &minPrice, MaxPrice: &maxPrice})
+	s.Equal(codes.InvalidArgument, status.Code(err), "invalid price range")
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
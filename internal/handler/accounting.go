@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"mime"
+	"net/http"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	"time"
+)
+
+// AccountingHandler serves the payment journal to finance.
+type AccountingHandler struct {
+	service *service.AccountingService
+	logger  logger.Logger
+}
+
+// NewAccountingHandler creates a new accounting handler.
+func NewAccountingHandler(s *service.AccountingService, l logger.Logger) *AccountingHandler {
+	return &AccountingHandler{service: s, logger: l}
+}
+
+// Journal godoc
+// @Summary Export the payment journal
+// @Description Returns the double-entry journal of charges and refunds made from the first to the last day (UTC)
+// @Description as CSV importable by Xero (manual journals) or QuickBooks Online (journal entries).
+// @Description Each payment is one balanced journal of payment clearing, revenue, refunds, sales tax and payment fees.
+// @Description Rows carry the currency of the order; Xero journals in its base currency, so rows of other currencies must be converted.
+// @Description Payments are journaled in the background, the latest ones may be missing for up to ACCOUNTING_POLL_INTERVAL.
+// @Tags admin
+// @Produce  plain
+// @Param   format  query  string  true  "xero or quickbooks"
+// @Param   from  query  string  true  "First day, e.g. 2024-01-01"
+// @Param   to  query  string  true  "Last day, e.g. 2024-01-31"
+// @Param   X-API-Key  header  string  true  "Admin or finance API key"
+// @Success 200  {string}  string "Journal CSV"
+// @Failure 400  {object}  ErrorResponse "Invalid format or days"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/accounting/journal [get]
+func (h *AccountingHandler) Journal(w http.ResponseWriter, r *http.Request) {
+	const op = "AccountingHandler.Journal"
+	log := h.logger.WithTrace(r.Context())
+
+	format := r.URL.Query().Get("format")
+	from, fromErr := time.Parse(time.DateOnly, r.URL.Query().Get("from"))
+	to, toErr := time.Parse(time.DateOnly, r.URL.Query().Get("to"))
+	if fromErr != nil || toErr != nil || to.Before(from) {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "from and to must be days (YYYY-MM-DD), to not before from")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := h.service.ExportJournal(r.Context(), &buf, format, from, to.AddDate(0, 0, 1)); err != nil {
+		if errors.Is(err, service.ErrInvalidJournalFormat) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidJournalFormat, err.Error())
+			return
+		}
+		log.Error("failed to export journal", "op", op, "format", format, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	filename := "journal-" + format + "-" + from.Format(time.DateOnly) + "-" + to.Format(time.DateOnly) + ".csv"
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	if _, err := buf.WriteTo(w); err != nil {
+		log.Error("failed to write journal", "op", op, "error", err)
+	}
+}
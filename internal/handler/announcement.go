@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	customvalidator "product-api/pkg/validator"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Announcement list page size limits.
+const (
+	defaultAnnouncementListLimit = 20
+	maxAnnouncementListLimit     = 100
+)
+
+// AudienceSegmentRequest selects the active users an announcement is sent to. Omitted criteria match every user.
+type AudienceSegmentRequest struct {
+	RegisteredAfter  *time.Time `json:"registered_after" example:"2024-01-01T00:00:00Z"`                       // Users registered at or after this time
+	RegisteredBefore *time.Time `json:"registered_before" example:"2025-01-01T00:00:00Z"`                      // Users registered before this time
+	ActiveSince      *time.Time `json:"active_since"`                                                          // Users who logged in at or after this time
+	InactiveSince    *time.Time `json:"inactive_since"`                                                        // Users who have not logged in since this time, or never did
+	Roles            []string   `json:"roles" example:"customer" validate:"dive,oneof=admin manager customer"` // Customer groups
+}
+
+// CreateAnnouncementRequest contains the variables of the announcement template and the users it is sent to.
+type CreateAnnouncementRequest struct {
+	Data    map[string]string      `json:"data" validate:"required"` // Headline and Message of the announcement template
+	Segment AudienceSegmentRequest `json:"segment"`
+}
+
+// AnnouncementListResponse is a page of announcements.
+type AnnouncementListResponse struct {
+	Announcements []domain.Announcement // Newest first
+	Total         int                   // Number of announcements
+}
+
+// AnnouncementHandler handles admin requests for announcements sent to segments of users.
+type AnnouncementHandler struct {
+	service *service.AnnouncementService
+	logger  logger.Logger
+}
+
+// NewAnnouncementHandler creates a new announcement handler.
+func NewAnnouncementHandler(s *service.AnnouncementService, l logger.Logger) *AnnouncementHandler {
+	return &AnnouncementHandler{service: s, logger: l}
+}
+
+// Create godoc
+// @Summary Send an announcement to a segment of users
+// @Description Queues the announcement template with the given Headline and Message for the active users of the segment.
+// @Description It is sent in batches in the background, as a marketing notification: users receive it only on the channels
+// @Description they opted in to for marketing. Delivery stats are updated after every batch.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   announcement  body  CreateAnnouncementRequest  true  "Announcement"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 202  {object}  domain.Announcement
+// @Failure 400  {object}  ErrorResponse "Invalid request body, template variables or segment"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/announcements [post]
+func (h *AnnouncementHandler) Create(w http.ResponseWriter, r *http.Request) {
+	const op = "AnnouncementHandler.Create"
+	log := h.logger.WithTrace(r.Context())
+
+	var req CreateAnnouncementRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	announcement, err := h.service.Create(r.Context(), service.AnnouncementInput{
+		Data: req.Data,
+		Segment: domain.AudienceSegment{
+			RegisteredAfter:  req.Segment.RegisteredAfter,
+			RegisteredBefore: req.Segment.RegisteredBefore,
+			ActiveSince:      req.Segment.ActiveSince,
+			InactiveSince:    req.Segment.InactiveSince,
+			Roles:            req.Segment.Roles,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSegment) || errors.Is(err, domain.ErrInvalidTemplate) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidAnnouncement, err.Error())
+			return
+		}
+		log.Error("failed to create announcement", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	log.Info("announcement queued", "op", op, "announcement_id", announcement.ID)
+	h.writeJSON(w, r, op, http.StatusAccepted, announcement)
+}
+
+// Get godoc
+// @Summary Get an announcement with its delivery stats
+// @Description Recipients counts the users of the segment processed so far: Sent, OptedOut and Failed add up to it.
+// @Tags admin
+// @Produce  json
+// @Param   id  path  string  true  "Announcement ID"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  domain.Announcement
+// @Failure 400  {object}  ErrorResponse "Invalid announcement ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Announcement not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/announcements/{id} [get]
+func (h *AnnouncementHandler) Get(w http.ResponseWriter, r *http.Request) {
+	const op = "AnnouncementHandler.Get"
+	log := h.logger.WithTrace(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid announcement ID")
+		return
+	}
+
+	announcement, err := h.service.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrAnnouncementNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeAnnouncementNotFound, "announcement not found")
+			return
+		}
+		log.Error("failed to get announcement", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	h.writeJSON(w, r, op, http.StatusOK, announcement)
+}
+
+// List godoc
+// @Summary List announcements
+// @Description Returns announcements with their delivery stats, newest first.
+// @Tags admin
+// @Produce  json
+// @Param   limit   query  int  false  "Maximum number of announcements (1-100, default 20)"
+// @Param   offset  query  int  false  "Number of announcements to skip"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  AnnouncementListResponse
+// @Failure 400  {object}  ErrorResponse "Invalid limit or offset"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/announcements [get]
+func (h *AnnouncementHandler) List(w http.ResponseWriter, r *http.Request) {
+	const op = "AnnouncementHandler.List"
+	log := h.logger.WithTrace(r.Context())
+
+	query := r.URL.Query()
+	limit, err := parsePositiveInt(query.Get("limit"), defaultAnnouncementListLimit)
+	if err != nil || limit > maxAnnouncementListLimit {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid limit")
+		return
+	}
+	var offset int
+	if v := query.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid offset")
+			return
+		}
+	}
+
+	page, err := h.service.List(r.Context(), offset, limit)
+	if err != nil {
+		log.Error("failed to list announcements", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	h.writeJSON(w, r, op, http.StatusOK, AnnouncementListResponse{Announcements: page.Announcements, Total: page.Total})
+}
+
+// writeJSON writes the response as JSON with the status.
+func (h *AnnouncementHandler) writeJSON(w http.ResponseWriter, r *http.Request, op string, status int, resp any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to encode announcement response", "op", op, "error", err)
+	}
+}
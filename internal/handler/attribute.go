@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	customvalidator "product-api/pkg/validator"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// DefineAttributeRequest contains a typed attribute definition of a product category.
+type DefineAttributeRequest struct {
+	Type          string   `json:"type" example:"number" validate:"required,oneof=string number integer boolean enum"`
+	AllowedValues []string `json:"allowed_values" example:"cotton,wool"` // Required for enum attributes
+	Unit          string   `json:"unit" example:"in" validate:"max=20"`  // Unit of numeric values
+	Required      bool     `json:"required" example:"false"`
+}
+
+// AttributeHandler handles HTTP requests related to product attribute definitions.
+type AttributeHandler struct {
+	service *service.AttributeService
+	logger  logger.Logger
+}
+
+// NewAttributeHandler creates a new attribute handler.
+func NewAttributeHandler(s *service.AttributeService, l logger.Logger) *AttributeHandler {
+	return &AttributeHandler{service: s, logger: l}
+}
+
+// List godoc
+// @Summary List attribute definitions of a product category
+// @Tags admin
+// @Produce  json
+// @Param   category  path  string  true  "Product category"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {array}   domain.AttributeDefinition
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/categories/{category}/attributes [get]
+func (h *AttributeHandler) List(w http.ResponseWriter, r *http.Request) {
+	const op = "AttributeHandler.List"
+	log := h.logger.WithTrace(r.Context())
+
+	definitions, err := h.service.List(r.Context(), chi.URLParam(r, "category"))
+	if err != nil {
+		log.Error("failed to list attribute definitions", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(definitions); err != nil {
+		log.Error("failed to encode attribute definitions", "op", op, "error", err)
+	}
+}
+
+// Define godoc
+// @Summary Define a product attribute of a category
+// @Description Creates or replaces a typed attribute, e.g. "screen_size" of "monitors" as a number in inches.
+// @Description Product attributes are validated against the definitions of their category when they are set.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   category  path  string  true  "Product category"
+// @Param   name  path  string  true  "Attribute name"
+// @Param   attribute  body  DefineAttributeRequest  true  "Attribute definition"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  domain.AttributeDefinition
+// @Failure 400  {object}  ErrorResponse "Invalid request body or definition"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/categories/{category}/attributes/{name} [put]
+func (h *AttributeHandler) Define(w http.ResponseWriter, r *http.Request) {
+	const op = "AttributeHandler.Define"
+	log := h.logger.WithTrace(r.Context())
+
+	var req DefineAttributeRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	definition := &domain.AttributeDefinition{
+		Category:      chi.URLParam(r, "category"),
+		Name:          chi.URLParam(r, "name"),
+		Type:          req.Type,
+		AllowedValues: req.AllowedValues,
+		Unit:          req.Unit,
+		Required:      req.Required,
+	}
+	if err := h.service.Define(r.Context(), definition); err != nil {
+		if errors.Is(err, domain.ErrInvalidAttributeDefinition) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidAttributeDefinition, err.Error())
+			return
+		}
+		log.Error("failed to define attribute", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	log.Info("attribute defined", "op", op, "category", definition.Category, "name", definition.Name)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(definition); err != nil {
+		log.Error("failed to encode attribute definition", "op", op, "error", err)
+	}
+}
+
+// Delete godoc
+// @Summary Delete a product attribute definition
+// @Description Products keep their values of the attribute until their attributes are changed.
+// @Tags admin
+// @Param   category  path  string  true  "Product category"
+// @Param   name  path  string  true  "Attribute name"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 204
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Attribute definition not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/categories/{category}/attributes/{name} [delete]
+func (h *AttributeHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	const op = "AttributeHandler.Delete"
+	log := h.logger.WithTrace(r.Context())
+
+	category, name := chi.URLParam(r, "category"), chi.URLParam(r, "name")
+	if err := h.service.Delete(r.Context(), category, name); err != nil {
+		if errors.Is(err, service.ErrAttributeNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeAttributeNotFound, err.Error())
+			return
+		}
+		log.Error("failed to delete attribute definition", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	log.Info("attribute deleted", "op", op, "category", category, "name", name)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	customvalidator "product-api/pkg/validator"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Bulk operation result page size limits.
+const (
+	defaultBulkOperationResultLimit = 100
+	maxBulkOperationResultLimit     = 1000
+)
+
+// BulkOperationFilterRequest selects the products an operation applies to: those having all the tags
+// and of the category. At least one of them is required.
+type BulkOperationFilterRequest struct {
+	Tags     []string `json:"tags" example:"summer"`
+	Category string   `json:"category" example:"shoes"`
+}
+
+// CreateBulkOperationRequest contains the change applied to the products matching the filter.
+type CreateBulkOperationRequest struct {
+	Filter BulkOperationFilterRequest `json:"filter"`
+	Kind   string                     `json:"kind" example:"price_percent" validate:"required,oneof=price_percent price_set stock_adjust"`
+	Value  float64                    `json:"value" example:"10"` // Percent for price_percent, price for price_set, units for stock_adjust
+	DryRun bool                       `json:"dry_run"`            // Only count the matching products, do not queue the operation
+}
+
+// BulkOperationPreviewResponse is the number of products a dry run would change.
+type BulkOperationPreviewResponse struct {
+	Matched int // Products matching the filter; stock adjustments skip bundles
+}
+
+// BulkOperationResultListResponse is a page of the per-product results of a bulk operation.
+type BulkOperationResultListResponse struct {
+	Results []domain.BulkOperationResult // In product ID order
+	Total   int                          // Number of results, of the requested status if one was given
+}
+
+// BulkOperationHandler handles admin requests for price and stock changes applied to products matching a filter.
+type BulkOperationHandler struct {
+	service *service.BulkOperationService
+	logger  logger.Logger
+}
+
+// NewBulkOperationHandler creates a new bulk operation handler.
+func NewBulkOperationHandler(s *service.BulkOperationService, l logger.Logger) *BulkOperationHandler {
+	return &BulkOperationHandler{service: s, logger: l}
+}
+
+// Create godoc
+// @Summary Apply a price or stock change to products matching tags or a category
+// @Description Queues the operation for the products having all the tags of the filter and of its category: price_percent
+// @Description changes prices by value percent (e.g. 10 or -20), price_set sets them to value, stock_adjust records a stock
+// @Description adjustment of value units (negative to remove stock; bundles are skipped). It is applied in batches in the
+// @Description background, per-item results are kept. With dry_run, only the number of matching products is returned.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   operation  body  CreateBulkOperationRequest  true  "Operation"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  BulkOperationPreviewResponse "Dry run"
+// @Success 202  {object}  domain.BulkOperation
+// @Failure 400  {object}  ErrorResponse "Invalid request body, filter or value"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/products/bulk-operations [post]
+func (h *BulkOperationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	const op = "BulkOperationHandler.Create"
+	log := h.logger.WithTrace(r.Context())
+
+	var req CreateBulkOperationRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+	in := service.BulkOperationInput{
+		Filter: domain.BulkOperationFilter{Tags: req.Filter.Tags, Category: req.Filter.Category},
+		Kind:   req.Kind,
+		Value:  req.Value,
+	}
+
+	if req.DryRun {
+		preview, err := h.service.Preview(r.Context(), in)
+		if err != nil {
+			h.respondCreateError(w, r, op, err)
+			return
+		}
+		h.writeJSON(w, r, op, http.StatusOK, BulkOperationPreviewResponse{Matched: preview.Matched})
+		return
+	}
+
+	operation, err := h.service.Create(r.Context(), callerID(r.Context()), in)
+	if err != nil {
+		h.respondCreateError(w, r, op, err)
+		return
+	}
+	log.Info("bulk operation queued", "op", op, "operation_id", operation.ID, "kind", operation.Kind, "matched", operation.Matched)
+	h.writeJSON(w, r, op, http.StatusAccepted, operation)
+}
+
+// respondCreateError writes the error of a created or previewed operation.
+func (h *BulkOperationHandler) respondCreateError(w http.ResponseWriter, r *http.Request, op string, err error) {
+	if errors.Is(err, domain.ErrInvalidBulkOperation) {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidBulkOperation, err.Error())
+		return
+	}
+	h.logger.WithTrace(r.Context()).Error("failed to create bulk operation", "op", op, "error", err)
+	respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+}
+
+// Get godoc
+// @Summary Get a bulk operation with its progress
+// @Description Processed counts the matching products processed so far: Updated and Failed add up to it.
+// @Description Matched is counted when the operation is created, products changed since may be processed or not.
+// @Tags admin
+// @Produce  json
+// @Param   id  path  string  true  "Bulk operation ID"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  domain.BulkOperation
+// @Failure 400  {object}  ErrorResponse "Invalid bulk operation ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Bulk operation not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/products/bulk-operations/{id} [get]
+func (h *BulkOperationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	const op = "BulkOperationHandler.Get"
+	log := h.logger.WithTrace(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid bulk operation ID")
+		return
+	}
+
+	operation, err := h.service.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrBulkOperationNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeBulkOperationNotFound, "bulk operation not found")
+			return
+		}
+		log.Error("failed to get bulk operation", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	h.writeJSON(w, r, op, http.StatusOK, operation)
+}
+
+// Results godoc
+// @Summary List the per-product results of a bulk operation
+// @Description Returns the price or quantity of the processed products before and after the operation, in product ID
+// @Description order, with the reason of failed changes, e.g. not enough stock to remove.
+// @Tags admin
+// @Produce  json
+// @Param   id  path  string  true  "Bulk operation ID"
+// @Param   status  query  string  false  "updated or failed"
+// @Param   limit   query  int  false  "Maximum number of results (1-1000, default 100)"
+// @Param   offset  query  int  false  "Number of results to skip"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  BulkOperationResultListResponse
+// @Failure 400  {object}  ErrorResponse "Invalid bulk operation ID, status, limit or offset"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Bulk operation not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/products/bulk-operations/{id}/results [get]
+func (h *BulkOperationHandler) Results(w http.ResponseWriter, r *http.Request) {
+	const op = "BulkOperationHandler.Results"
+	log := h.logger.WithTrace(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid bulk operation ID")
+		return
+	}
+	query := r.URL.Query()
+	status := query.Get("status")
+	if status != "" && status != domain.BulkResultUpdated && status != domain.BulkResultFailed {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "status must be updated or failed")
+		return
+	}
+	limit, err := parsePositiveInt(query.Get("limit"), defaultBulkOperationResultLimit)
+	if err != nil || limit > maxBulkOperationResultLimit {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid limit")
+		return
+	}
+	var offset int
+	if v := query.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid offset")
+			return
+		}
+	}
+
+	page, err := h.service.Results(r.Context(), id, status, offset, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrBulkOperationNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeBulkOperationNotFound, "bulk operation not found")
+			return
+		}
+		log.Error("failed to list bulk operation results", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	h.writeJSON(w, r, op, http.StatusOK, BulkOperationResultListResponse{Results: page.Results, Total: page.Total})
+}
+
+// writeJSON writes the response as JSON with the status.
+func (h *BulkOperationHandler) writeJSON(w http.ResponseWriter, r *http.Request, op string, status int, resp any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to encode bulk operation response", "op", op, "error", err)
+	}
+}
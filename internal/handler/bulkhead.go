@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+	"product-api/internal/telemetry"
+	"strconv"
+	"time"
+)
+
+// Bulkhead limits the requests of a traffic class served at once, e.g. catalog browsing, so its bursts
+// cannot take the database connections other classes need, e.g. order creation and payment callbacks.
+// Requests over the limit wait in a bounded queue in arrival order; requests finding the queue full or
+// waiting longer than the maximum wait are rejected with 503 Service Unavailable and Retry-After.
+type Bulkhead struct {
+	class   string
+	slots   chan struct{} // Requests being served
+	queue   chan struct{} // Requests being served or waiting for a slot
+	maxWait time.Duration
+}
+
+// NewBulkhead creates a bulkhead serving up to concurrency requests of the class at once,
+// with up to queue more requests waiting at most maxWait for a slot.
+func NewBulkhead(class string, concurrency, queue int, maxWait time.Duration) *Bulkhead {
+	return &Bulkhead{
+		class:   class,
+		slots:   make(chan struct{}, concurrency),
+		queue:   make(chan struct{}, concurrency+queue),
+		maxWait: maxWait,
+	}
+}
+
+// Middleware serves requests within the limits of the bulkhead.
+func (b *Bulkhead) Middleware(next http.Handler) http.Handler {
+	retryAfter := strconv.Itoa(max(int(b.maxWait.Round(time.Second).Seconds()), 1))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case b.queue <- struct{}{}:
+			defer func() { <-b.queue }()
+		default:
+			b.reject(w, r, retryAfter, "queue_full")
+			return
+		}
+
+		timer := time.NewTimer(b.maxWait)
+		defer timer.Stop()
+		select {
+		case b.slots <- struct{}{}:
+			defer func() { <-b.slots }()
+		case <-timer.C:
+			b.reject(w, r, retryAfter, "timeout")
+			return
+		case <-r.Context().Done():
+			return // The client is gone
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// reject responds 503 to a request the bulkhead does not serve and counts it by reason.
+func (b *Bulkhead) reject(w http.ResponseWriter, r *http.Request, retryAfter, reason string) {
+	telemetry.RecordShedRequest(r.Context(), b.class, reason)
+	w.Header().Set("Retry-After", retryAfter)
+	respondError(w, r, http.StatusServiceUnavailable, ErrCodeUnavailable, "service busy, retry later")
+}
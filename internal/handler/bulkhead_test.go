@@ -0,0 +1,101 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/handler"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingHandler holds the slot of each request until release is closed, and signals served requests.
+func blockingHandler(release <-chan struct{}, served chan<- struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// serveAsync serves a request in the background and returns the channel of its response.
+func serveAsync(h http.Handler) <-chan *httptest.ResponseRecorder {
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/products", nil))
+		done <- rec
+	}()
+	return done
+}
+
+func TestBulkhead_RejectsWhenQueueIsFull(t *testing.T) {
+	release, served := make(chan struct{}), make(chan struct{}, 1)
+	h := handler.NewBulkhead("catalog", 1, 0, time.Minute).Middleware(blockingHandler(release, served))
+	first := serveAsync(h)
+	<-served
+
+	rec, resp := serveError[any](t, h, httptest.NewRequest(http.MethodGet, "/products", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "service_unavailable", resp.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"), "clients retry after the maximum wait")
+
+	close(release)
+	assert.Equal(t, http.StatusOK, (<-first).Code)
+}
+
+func TestBulkhead_ServesQueuedRequests(t *testing.T) {
+	release, served := make(chan struct{}), make(chan struct{}, 2)
+	h := handler.NewBulkhead("catalog", 1, 1, time.Minute).Middleware(blockingHandler(release, served))
+	first := serveAsync(h)
+	<-served
+	queued := serveAsync(h)
+
+	select {
+	case <-served:
+		t.Fatal("the queued request was served while the slot was taken")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	assert.Equal(t, http.StatusOK, (<-first).Code)
+	assert.Equal(t, http.StatusOK, (<-queued).Code, "the request gets the slot once it is freed")
+}
+
+func TestBulkhead_RejectsAfterMaxWait(t *testing.T) {
+	release, served := make(chan struct{}), make(chan struct{}, 1)
+	h := handler.NewBulkhead("catalog", 1, 1, 50*time.Millisecond).Middleware(blockingHandler(release, served))
+	first := serveAsync(h)
+	<-served
+
+	start := time.Now()
+	rec, resp := serveError[any](t, h, httptest.NewRequest(http.MethodGet, "/products", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "service_unavailable", resp.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"), "at least a second")
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	close(release)
+	assert.Equal(t, http.StatusOK, (<-first).Code)
+}
+
+func TestBulkhead_ReleasesSlotOnPanic(t *testing.T) {
+	panics := true
+	h := handler.NewBulkhead("catalog", 1, 0, time.Second).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if panics {
+			panic("handler failed")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// The recoverer of the router answers the panicking request further up
+	require.Panics(t, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/products", nil))
+	})
+
+	panics = false
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/products", nil))
+	assert.Equal(t, http.StatusOK, rec.Code, "the slot and queue place of the panicking request are released")
+}
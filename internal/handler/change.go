@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	"strconv"
+)
+
+// Change feed page size limits.
+const (
+	defaultChangeFeedLimit = 500
+	maxChangeFeedLimit     = 5000
+)
+
+// ChangeHandler handles HTTP requests related to the change feed.
+type ChangeHandler struct {
+	service *service.ChangeService
+	logger  logger.Logger
+}
+
+// NewChangeHandler creates a new change feed handler.
+func NewChangeHandler(s *service.ChangeService, l logger.Logger) *ChangeHandler {
+	return &ChangeHandler{service: s, logger: l}
+}
+
+// Feed godoc
+// @Summary Read the change feed
+// @Description Returns insert, update and delete changes of products, orders, order items and users in a stable order,
+// @Description with the row after each change. Start with since=0 and pass Next of each page as since of the following one.
+// @Description Sequence numbers identify changes, but the feed is not ordered by them.
+// @Description Changes are deleted after the retention period (CHANGE_LOG_RETENTION, 30 days by default); readers further behind
+// @Description get change_not_found and must start over from a full export.
+// @Tags admin
+// @Produce  json
+// @Param   since  query  int  false  "Sequence number of the last received change, 0 to start from the beginning"
+// @Param   limit  query  int  false  "Maximum number of changes (1-5000, default 500)"
+// @Param   X-API-Key  header  string  true  "Admin or analyst API key"
+// @Success 200  {object}  service.ChangePage
+// @Failure 400  {object}  ErrorResponse "Invalid, unknown or deleted sequence number, invalid limit"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/changes [get]
+func (h *ChangeHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	const op = "ChangeHandler.Feed"
+	log := h.logger.WithTrace(r.Context())
+
+	var since int64
+	if v := r.URL.Query().Get("since"); v != "" {
+		var err error
+		if since, err = strconv.ParseInt(v, 10, 64); err != nil || since < 0 {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid since")
+			return
+		}
+	}
+	limit, err := parsePositiveInt(r.URL.Query().Get("limit"), defaultChangeFeedLimit)
+	if err != nil || limit > maxChangeFeedLimit {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid limit")
+		return
+	}
+
+	page, err := h.service.Feed(r.Context(), since, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrChangeNotFound) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeChangeNotFound, "unknown sequence number")
+			return
+		}
+		log.Error("failed to read change feed", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		log.Error("failed to encode change feed", "op", op, "error", err)
+	}
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	customvalidator "product-api/pkg/validator"
+)
+
+// PublishDocumentRequest contains data for publishing a new legal document version.
+type PublishDocumentRequest struct {
+	Type    string `json:"type" example:"terms" validate:"required,oneof=terms privacy"`
+	Version string `json:"version" example:"2024-06-01" validate:"required,max=64"`
+}
+
+// AcceptDocumentRequest contains data for accepting a legal document version.
+type AcceptDocumentRequest struct {
+	Type    string `json:"type" example:"terms" validate:"required,oneof=terms privacy"`
+	Version string `json:"version" example:"2024-06-01" validate:"required"`
+}
+
+// ConsentHandler handles HTTP requests related to legal documents and user consents.
+type ConsentHandler struct {
+	service *service.ConsentService
+	logger  logger.Logger
+}
+
+// NewConsentHandler creates a new consent handler.
+func NewConsentHandler(s *service.ConsentService, l logger.Logger) *ConsentHandler {
+	return &ConsentHandler{service: s, logger: l}
+}
+
+// Publish godoc
+// @Summary Publish a new legal document version
+// @Tags consents
+// @Accept  json
+// @Produce  json
+// @Param   document  body      PublishDocumentRequest  true  "Document version"
+// @Security ApiKeyAuth
+// @Success 201  {object}  domain.LegalDocument
+// @Failure 400  {object}  ErrorResponse "Invalid request body"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 409  {object}  ErrorResponse "Document version already exists"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/legal-documents [post]
+func (h *ConsentHandler) Publish(w http.ResponseWriter, r *http.Request) {
+	const op = "ConsentHandler.Publish"
+	log := h.logger.WithTrace(r.Context())
+
+	var req PublishDocumentRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	doc, err := h.service.PublishDocument(r.Context(), req.Type, req.Version)
+	if err != nil {
+		if errors.Is(err, service.ErrDocumentAlreadyExists) {
+			respondError(w, r, http.StatusConflict, ErrCodeDocumentAlreadyExists, err.Error())
+			return
+		}
+		log.Error("failed to publish legal document", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	log.Info("legal document published", "op", op, "type", doc.Type, "version", doc.Version)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		log.Error("failed to encode document response", "op", op, "error", err)
+	}
+}
+
+// Accept godoc
+// @Summary Accept a legal document version
+// @Tags consents
+// @Accept  json
+// @Produce  json
+// @Param   consent  body      AcceptDocumentRequest  true  "Accepted document version"
+// @Security ApiKeyAuth
+// @Success 201  {object}  domain.Consent
+// @Failure 400  {object}  ErrorResponse "Invalid request body or unknown document version"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/consents [post]
+func (h *ConsentHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	const op = "ConsentHandler.Accept"
+	log := h.logger.WithTrace(r.Context())
+
+	var req AcceptDocumentRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	consent, err := h.service.Accept(r.Context(), userID, req.Type, req.Version)
+	if err != nil {
+		if errors.Is(err, service.ErrDocumentNotFound) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeDocumentNotFound, err.Error())
+			return
+		}
+		log.Error("failed to accept legal document", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(consent); err != nil {
+		log.Error("failed to encode consent response", "op", op, "error", err)
+	}
+}
+
+// History godoc
+// @Summary Get consent history of the current user
+// @Tags consents
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200  {array}   domain.Consent
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/consents [get]
+func (h *ConsentHandler) History(w http.ResponseWriter, r *http.Request) {
+	const op = "ConsentHandler.History"
+	log := h.logger.WithTrace(r.Context())
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	consents, err := h.service.History(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to get consent history", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	if consents == nil {
+		consents = []domain.Consent{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(consents); err != nil {
+		log.Error("failed to encode consent history", "op", op, "error", err)
+	}
+}
+
+// RequireConsent creates middleware that rejects requests with 428 Precondition Required and consent_required,
+// listing the documents in details, while the authenticated user has not accepted the latest legal document versions.
+// Must be placed after JWTMiddleware.
+func (h *ConsentHandler) RequireConsent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const op = "ConsentHandler.RequireConsent"
+		log := h.logger.WithTrace(r.Context())
+
+		userID, err := userIDFromContext(r)
+		if err != nil {
+			log.Error("failed to get user id from context", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+			return
+		}
+
+		pending, err := h.service.PendingDocuments(r.Context(), userID)
+		if err != nil {
+			log.Error("failed to check pending legal documents", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+			return
+		}
+
+		if len(pending) > 0 {
+			respondErrorDetails(w, r, http.StatusPreconditionRequired, ErrCodeConsentRequired, "consent required", pending)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
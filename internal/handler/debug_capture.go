@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"product-api/internal/logger"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// DebugCaptureHeader enables body capture of a single request when it carries the admin API key.
+const DebugCaptureHeader = "X-Debug-Capture"
+
+// DebugCaptureMiddleware creates middleware that captures request and response bodies for debugging
+// hard-to-reproduce client issues. Bodies (up to 4 KiB, with sensitive fields redacted, non-JSON bodies
+// described by their size) are logged with the route, status and request ID, and the request body is added
+// as a Sentry breadcrumb, so errors reported while handling the request include it.
+// Capture is enabled for all requests if enabled is true, otherwise for requests whose DebugCaptureHeader
+// carries adminKey.
+// Must be placed after Sentry, RequestID and OpenTelemetry middlewares.
+func DebugCaptureMiddleware(l logger.Logger, enabled bool, adminKey string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !enabled && !debugCaptureRequested(r, adminKey) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			r.Header.Del(DebugCaptureHeader)
+
+			// Read the start of the body ahead, so the breadcrumb is recorded before the handler runs
+			var requestBody []byte
+			if r.Body != nil {
+				var err error
+				requestBody, err = io.ReadAll(io.LimitReader(r.Body, maxCapturedBodySize))
+				if err != nil {
+					respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "failed to read request body")
+					return
+				}
+				r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(requestBody), r.Body), Closer: r.Body}
+			}
+			redactedRequest := redactBody(requestBody)
+			if hub := sentry.GetHubFromContext(r.Context()); hub != nil {
+				hub.AddBreadcrumb(&sentry.Breadcrumb{
+					Type:     "http",
+					Category: "request.body",
+					Message:  redactedRequest,
+					Data:     map[string]any{"method": r.Method, "url": r.URL.Path},
+				}, nil)
+			}
+
+			response := &limitedBuffer{}
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(response)
+			next.ServeHTTP(ww, r)
+
+			l.WithTrace(r.Context()).Info("request captured",
+				"method", r.Method,
+				"status", ww.Status(),
+				"request_id", middleware.GetReqID(r.Context()),
+				"request_body", redactedRequest,
+				"response_body", redactBody(response.Bytes()),
+			)
+		})
+	}
+}
+
+// debugCaptureRequested reports whether the request asks for body capture with the admin API key.
+func debugCaptureRequested(r *http.Request, adminKey string) bool {
+	key := r.Header.Get(DebugCaptureHeader)
+	return key != "" && adminKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(adminKey)) == 1
+}
+
+// readCloser reads from Reader and closes Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// limitedBuffer keeps the first maxCapturedBodySize bytes written to it and discards the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := maxCapturedBodySize - b.Len(); remaining > 0 {
+		b.Buffer.Write(p[:min(len(p), remaining)])
+	}
+	return len(p), nil
+}
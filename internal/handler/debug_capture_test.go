@@ -0,0 +1,83 @@
+package handler_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/handler"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const debugAdminKey = "admin-key"
+
+// captureRequest serves a login request with the headers by an echoing handler under DebugCaptureMiddleware,
+// and returns the logged records and the request body the handler read.
+func captureRequest(t *testing.T, enabled bool, headers map[string]string, body string) ([]logRecord, string) {
+	t.Helper()
+	log := newRecordingLogger()
+	var read string
+	h := handler.DebugCaptureMiddleware(log, enabled, debugAdminKey)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEqual(t, debugAdminKey, r.Header.Get(handler.DebugCaptureHeader), "the admin key is not passed on")
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		read = string(b)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"code": "invalid_credentials", "token": "secret-token"}`))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/users/login", strings.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	return *log.records, read
+}
+
+func TestDebugCaptureMiddleware_CapturesRequestsWithAdminKey(t *testing.T) {
+	const body = `{"email": "user@example.com", "password": "hunter22"}`
+
+	records, read := captureRequest(t, false, nil, body)
+	assert.Empty(t, records, "capture is off by default")
+	assert.Equal(t, body, read)
+
+	records, _ = captureRequest(t, false, map[string]string{handler.DebugCaptureHeader: "wrong-key"}, body)
+	assert.Empty(t, records)
+
+	records, read = captureRequest(t, false, map[string]string{handler.DebugCaptureHeader: debugAdminKey}, body)
+	assert.Equal(t, body, read, "the handler reads the whole body")
+	require.Len(t, records, 1)
+	assert.Equal(t, "request captured", records[0].msg)
+	assert.Equal(t, http.StatusUnauthorized, records[0].args["status"])
+	assert.JSONEq(t, `{"email": "user@example.com", "password": "[REDACTED]"}`, records[0].args["request_body"].(string))
+	assert.JSONEq(t, `{"code": "invalid_credentials", "token": "[REDACTED]"}`, records[0].args["response_body"].(string))
+}
+
+func TestDebugCaptureMiddleware_CapturesAllRequestsWhenEnabled(t *testing.T) {
+	body := `{"note": "` + strings.Repeat("x", 5000) + `"}`
+
+	records, read := captureRequest(t, true, nil, body)
+	assert.Equal(t, body, read, "bodies over the capture limit reach the handler in full")
+	require.Len(t, records, 1)
+	assert.Equal(t, "[4096 bytes, not JSON or truncated]", records[0].args["request_body"])
+}
+
+func TestDebugCaptureMiddleware_AddsSentryBreadcrumb(t *testing.T) {
+	hub := sentry.NewHub(nil, sentry.NewScope())
+	h := handler.DebugCaptureMiddleware(newRecordingLogger(), true, "")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Errors reported by the handler carry the breadcrumb
+		event := hub.Scope().ApplyToEvent(sentry.NewEvent(), nil, nil)
+		require.Len(t, event.Breadcrumbs, 1)
+		assert.Equal(t, "request.body", event.Breadcrumbs[0].Category)
+		assert.JSONEq(t, `{"password": "[REDACTED]"}`, event.Breadcrumbs[0].Message)
+		assert.Equal(t, "/users/login", event.Breadcrumbs[0].Data["url"])
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/users/login", strings.NewReader(`{"password": "hunter22"}`))
+	h.ServeHTTP(httptest.NewRecorder(), req.WithContext(sentry.SetHubOnContext(req.Context(), hub)))
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	customvalidator "product-api/pkg/validator"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// CreateDeliverySlotRequest contains a delivery window of a region and the number of orders it takes.
+type CreateDeliverySlotRequest struct {
+	Region   string    `json:"region" example:"eu-west-1" validate:"required,max=64"`
+	StartsAt time.Time `json:"starts_at" example:"2026-03-14T09:00:00Z" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" example:"2026-03-14T12:00:00Z" validate:"required"`
+	Capacity int       `json:"capacity" example:"20" validate:"gte=0"`
+}
+
+// UpdateDeliverySlotCapacityRequest contains the new capacity of a delivery slot.
+type UpdateDeliverySlotCapacityRequest struct {
+	Capacity int `json:"capacity" example:"25" validate:"gte=0"` // 0 closes the slot for new orders
+}
+
+// DeliverySlotHandler handles HTTP requests related to delivery slots.
+type DeliverySlotHandler struct {
+	service *service.DeliverySlotService
+	logger  logger.Logger
+}
+
+// NewDeliverySlotHandler creates a new delivery slot handler.
+func NewDeliverySlotHandler(s *service.DeliverySlotService, l logger.Logger) *DeliverySlotHandler {
+	return &DeliverySlotHandler{service: s, logger: l}
+}
+
+// Available godoc
+// @Summary List delivery slots available at checkout
+// @Description Returns the delivery windows of the region that have not started and have capacity left, earliest first.
+// @Description Pass the ID of one as delivery_slot_id when creating an order. Availability can change until the order is placed.
+// @Tags orders
+// @Produce  json
+// @Param   region  query  string  true   "Deployment region of the slots"
+// @Param   from    query  string  false  "Start of the listed window, RFC 3339 (default now)"
+// @Param   to      query  string  false  "End of the listed window, RFC 3339 (default 14 days after from, at most 62 days)"
+// @Security ApiKeyAuth
+// @Success 200  {array}   domain.DeliverySlot
+// @Failure 400  {object}  ErrorResponse "Missing region or invalid window"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /delivery-slots [get]
+func (h *DeliverySlotHandler) Available(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, "DeliverySlotHandler.Available", h.service.Available)
+}
+
+// List godoc
+// @Summary List delivery slots of a region
+// @Description Returns all delivery windows of the region starting in the window with their reserved orders, including full ones.
+// @Tags admin
+// @Produce  json
+// @Param   region  query  string  true   "Deployment region of the slots"
+// @Param   from    query  string  false  "Start of the listed window, RFC 3339 (default now)"
+// @Param   to      query  string  false  "End of the listed window, RFC 3339 (default 14 days after from, at most 62 days)"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {array}   domain.DeliverySlot
+// @Failure 400  {object}  ErrorResponse "Missing region or invalid window"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/delivery-slots [get]
+func (h *DeliverySlotHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, "DeliverySlotHandler.List", h.service.List)
+}
+
+// list writes the slots of the region and window of the request query returned by find.
+func (h *DeliverySlotHandler) list(w http.ResponseWriter, r *http.Request, op string, find func(ctx context.Context, region string, from, to time.Time) ([]domain.DeliverySlot, error)) {
+	log := h.logger.WithTrace(r.Context())
+
+	query := r.URL.Query()
+	region := query.Get("region")
+	if region == "" {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "region is required")
+		return
+	}
+	from := time.Now()
+	if v := query.Get("from"); v != "" {
+		var err error
+		if from, err = time.Parse(time.RFC3339, v); err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid from, must be RFC 3339")
+			return
+		}
+	}
+	to := from.Add(service.DefaultDeliverySlotWindow)
+	if v := query.Get("to"); v != "" {
+		var err error
+		if to, err = time.Parse(time.RFC3339, v); err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid to, must be RFC 3339")
+			return
+		}
+	}
+
+	slots, err := find(r.Context(), region, from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSlotWindow) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidSlotWindow, "to must be after from and at most 62 days later")
+			return
+		}
+		log.Error("failed to list delivery slots", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	h.writeJSON(w, r, op, http.StatusOK, slots)
+}
+
+// Create godoc
+// @Summary Create a delivery slot
+// @Description Adds a delivery window to the region that takes up to capacity orders. Each region has at most one slot starting at a time.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   slot  body  CreateDeliverySlotRequest  true  "Delivery slot"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 201  {object}  domain.DeliverySlot
+// @Failure 400  {object}  ErrorResponse "Invalid request body or window"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 409  {object}  ErrorResponse "Region already has a slot starting at that time"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/delivery-slots [post]
+func (h *DeliverySlotHandler) Create(w http.ResponseWriter, r *http.Request) {
+	const op = "DeliverySlotHandler.Create"
+	log := h.logger.WithTrace(r.Context())
+
+	var req CreateDeliverySlotRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	slot, err := h.service.Create(r.Context(), req.Region, req.StartsAt, req.EndsAt, req.Capacity)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidDeliverySlot):
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidDeliverySlot, err.Error())
+		case errors.Is(err, service.ErrDeliverySlotExists):
+			respondError(w, r, http.StatusConflict, ErrCodeDeliverySlotExists, "region already has a delivery slot starting at that time")
+		default:
+			log.Error("failed to create delivery slot", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	log.Info("delivery slot created", "op", op, "slot_id", slot.ID, "region", slot.Region, "capacity", slot.Capacity)
+	h.writeJSON(w, r, op, http.StatusCreated, slot)
+}
+
+// UpdateCapacity godoc
+// @Summary Change the capacity of a delivery slot
+// @Description Sets the number of orders the slot takes. It cannot be reduced below the orders already reserved; 0 closes an empty slot.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   id  path  string  true  "Delivery slot ID"
+// @Param   capacity  body  UpdateDeliverySlotCapacityRequest  true  "New capacity"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  domain.DeliverySlot
+// @Failure 400  {object}  ErrorResponse "Invalid slot ID or request body"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Delivery slot not found"
+// @Failure 409  {object}  ErrorResponse "Capacity is below the reserved orders"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/delivery-slots/{id}/capacity [put]
+func (h *DeliverySlotHandler) UpdateCapacity(w http.ResponseWriter, r *http.Request) {
+	const op = "DeliverySlotHandler.UpdateCapacity"
+	log := h.logger.WithTrace(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid delivery slot ID")
+		return
+	}
+	var req UpdateDeliverySlotCapacityRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	slot, err := h.service.UpdateCapacity(r.Context(), id, req.Capacity)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidDeliverySlot):
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidDeliverySlot, err.Error())
+		case errors.Is(err, service.ErrDeliverySlotNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeDeliverySlotNotFound, "delivery slot not found")
+		case errors.Is(err, service.ErrDeliverySlotCapacity):
+			respondError(w, r, http.StatusConflict, ErrCodeDeliverySlotCapacity, "capacity is below the reserved orders of the slot")
+		default:
+			log.Error("failed to update delivery slot capacity", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	log.Info("delivery slot capacity updated", "op", op, "slot_id", slot.ID, "capacity", slot.Capacity, "reserved", slot.Reserved)
+	h.writeJSON(w, r, op, http.StatusOK, slot)
+}
+
+// writeJSON writes the response as JSON with the status.
+func (h *DeliverySlotHandler) writeJSON(w http.ResponseWriter, r *http.Request, op string, status int, resp any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to encode delivery slot response", "op", op, "error", err)
+	}
+}
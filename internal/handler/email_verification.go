@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// EmailVerificationResponse contains the verified email address of a user.
+type EmailVerificationResponse struct {
+	Email      string `example:"jane.doe@example.com"`
+	VerifiedAt *time.Time
+}
+
+// EmailVerificationHandler handles HTTP requests related to email address verification.
+type EmailVerificationHandler struct {
+	service *service.EmailVerificationService
+	logger  logger.Logger
+}
+
+// NewEmailVerificationHandler creates a new email verification handler.
+func NewEmailVerificationHandler(s *service.EmailVerificationService, l logger.Logger) *EmailVerificationHandler {
+	return &EmailVerificationHandler{service: s, logger: l}
+}
+
+// Verify godoc
+// @Summary Verify an email address
+// @Description Opened from the link of a verification email. Links expire after 48 hours
+// @Description and are replaced by links sent later.
+// @Tags users
+// @Produce  json
+// @Param   token  query  string  true  "Token of the verification link"
+// @Success 200  {object}  EmailVerificationResponse
+// @Failure 400  {object}  ErrorResponse "Invalid verification link"
+// @Failure 410  {object}  ErrorResponse "Verification link expired"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/email/verify [get]
+func (h *EmailVerificationHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	const op = "EmailVerificationHandler.Verify"
+	log := h.logger.WithTrace(r.Context())
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, service.ErrEmailVerificationInvalid.Error())
+		return
+	}
+
+	user, err := h.service.Verify(r.Context(), token)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmailVerificationInvalid):
+			respondError(w, r, http.StatusBadRequest, ErrCodeEmailVerificationInvalid, err.Error())
+		case errors.Is(err, service.ErrEmailVerificationExpired):
+			respondError(w, r, http.StatusGone, ErrCodeEmailVerificationExpired, err.Error())
+		default:
+			log.Error("failed to verify email address", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(EmailVerificationResponse{Email: user.Email, VerifiedAt: user.EmailVerifiedAt}); err != nil {
+		log.Error("failed to encode email verification response", "op", op, "error", err)
+	}
+}
+
+// Resend godoc
+// @Summary Resend the verification email of a user
+// @Description Sends a new verification link to the user's email address, the link sent before no longer works.
+// @Description Verification emails are sent regardless of the user's notification preferences.
+// @Tags admin
+// @Param   id  path  string  true  "User ID"
+// @Param   X-API-Key  header  string  true  "Admin or support API key"
+// @Success 204
+// @Failure 400  {object}  ErrorResponse "Invalid user ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "User not found"
+// @Failure 409  {object}  ErrorResponse "Email address already verified"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/users/{id}/verification-email/resend [post]
+func (h *EmailVerificationHandler) Resend(w http.ResponseWriter, r *http.Request) {
+	const op = "EmailVerificationHandler.Resend"
+	log := h.logger.WithTrace(r.Context())
+
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid user ID")
+		return
+	}
+
+	if _, err := h.service.Send(r.Context(), userID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeUserNotFound, "user not found")
+		case errors.Is(err, service.ErrEmailAlreadyVerified):
+			respondError(w, r, http.StatusConflict, ErrCodeEmailAlreadyVerified, err.Error())
+		default:
+			log.Error("failed to resend verification email", "op", op, "user_id", userID, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+	log.Info("verification email resent", "op", op, "user_id", userID, "actor", callerID(r.Context()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
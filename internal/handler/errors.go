@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	customvalidator "product-api/pkg/validator"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ErrorCode is the machine-readable code of an error response. Clients branch on the code,
+// the message is meant for people and may change.
+type ErrorCode string
+
+// Codes of errors without a more specific code, by status.
+const (
+	ErrCodeInvalidRequest   ErrorCode = "invalid_request"     // 400, malformed request or parameters
+	ErrCodeValidationFailed ErrorCode = "validation_failed"   // 400, request body fields failed validation, details map the fields to the failed rules
+	ErrCodeUnauthorized     ErrorCode = "unauthorized"        // 401, missing or invalid credentials
+	ErrCodeForbidden        ErrorCode = "forbidden"           // 403
+	ErrCodeNotFound         ErrorCode = "not_found"           // 404
+	ErrCodeConflict         ErrorCode = "conflict"            // 409
+	ErrCodePayloadTooLarge  ErrorCode = "payload_too_large"   // 413
+	ErrCodeRateLimited      ErrorCode = "rate_limited"        // 429, retry after the Retry-After header
+	ErrCodeInternal         ErrorCode = "internal_error"      // 500
+	ErrCodeUpstreamFailed   ErrorCode = "upstream_failed"     // 502, an identity or payment provider or the primary region failed
+	ErrCodeUnavailable      ErrorCode = "service_unavailable" // 503, retry after the Retry-After header
+)
+
+// Codes of specific errors, named after the service and domain errors they report.
+const (
+	ErrCodeAccountDisabled            ErrorCode = "account_disabled"
+	ErrCodeAccountLocked              ErrorCode = "account_locked"
+	ErrCodeAgeRestricted              ErrorCode = "age_restricted"
+	ErrCodeAnnouncementNotFound       ErrorCode = "announcement_not_found"
+	ErrCodeAttributeNotFound          ErrorCode = "attribute_not_found"
+	ErrCodeBarcodeTaken               ErrorCode = "barcode_taken"
+	ErrCodeBulkOperationNotFound      ErrorCode = "bulk_operation_not_found"
+	ErrCodeChangeNotFound             ErrorCode = "change_not_found"
+	ErrCodeConsentRequired            ErrorCode = "consent_required" // 428, details list the legal documents to accept
+	ErrCodeDeliverySlotCapacity       ErrorCode = "delivery_slot_capacity"
+	ErrCodeDeliverySlotExists         ErrorCode = "delivery_slot_exists"
+	ErrCodeDeliverySlotNotFound       ErrorCode = "delivery_slot_not_found"
+	ErrCodeDeliverySlotUnavailable    ErrorCode = "delivery_slot_unavailable"
+	ErrCodeDocumentAlreadyExists      ErrorCode = "document_already_exists"
+	ErrCodeDocumentNotFound           ErrorCode = "document_not_found"
+	ErrCodeEmailAlreadyVerified       ErrorCode = "email_already_verified"
+	ErrCodeEmailVerificationExpired   ErrorCode = "email_verification_expired"
+	ErrCodeEmailVerificationInvalid   ErrorCode = "email_verification_invalid"
+	ErrCodeIdempotencyKeyInProgress   ErrorCode = "idempotency_key_in_progress"
+	ErrCodeIdempotencyKeyReused       ErrorCode = "idempotency_key_reused"
+	ErrCodeInboxNotificationNotFound  ErrorCode = "inbox_notification_not_found"
+	ErrCodeInsufficientStock          ErrorCode = "insufficient_stock"
+	ErrCodeInvalidAnnouncement        ErrorCode = "invalid_announcement"
+	ErrCodeInvalidAttributeDefinition ErrorCode = "invalid_attribute_definition"
+	ErrCodeInvalidAttributes          ErrorCode = "invalid_attributes"
+	ErrCodeInvalidBarcode             ErrorCode = "invalid_barcode"
+	ErrCodeInvalidBulkOperation       ErrorCode = "invalid_bulk_operation"
+	ErrCodeInvalidCredentials         ErrorCode = "invalid_credentials"
+	ErrCodeInvalidDeliverySlot        ErrorCode = "invalid_delivery_slot"
+	ErrCodeInvalidExportFormat        ErrorCode = "invalid_export_format"
+	ErrCodeInvalidIssueRule           ErrorCode = "invalid_issue_rule"
+	ErrCodeInvalidJournalFormat       ErrorCode = "invalid_journal_format"
+	ErrCodeInvalidOrderNumberSettings ErrorCode = "invalid_order_number_settings"
+	ErrCodeInvalidOrderOption         ErrorCode = "invalid_order_option"
+	ErrCodeInvalidOrderTransition     ErrorCode = "invalid_order_transition"
+	ErrCodeInvalidPayment             ErrorCode = "invalid_payment"
+	ErrCodeInvalidPhone               ErrorCode = "invalid_phone"
+	ErrCodeInvalidPreference          ErrorCode = "invalid_preference"
+	ErrCodeInvalidPriceRange          ErrorCode = "invalid_price_range"
+	ErrCodeInvalidProduct             ErrorCode = "invalid_product"
+	ErrCodeInvalidProductImport       ErrorCode = "invalid_product_import"
+	ErrCodeInvalidProductTransition   ErrorCode = "invalid_product_transition"
+	ErrCodeInvalidQuota               ErrorCode = "invalid_quota"
+	ErrCodeInvalidRefundRequest       ErrorCode = "invalid_refund_request"
+	ErrCodeInvalidRole                ErrorCode = "invalid_role"
+	ErrCodeInvalidSlotWindow          ErrorCode = "invalid_slot_window"
+	ErrCodeInvalidSnapshotWindow      ErrorCode = "invalid_snapshot_window"
+	ErrCodeInvalidStockImport         ErrorCode = "invalid_stock_import"
+	ErrCodeInvalidStockMovement       ErrorCode = "invalid_stock_movement"
+	ErrCodeInvalidTag                 ErrorCode = "invalid_tag"
+	ErrCodeInvalidTemplate            ErrorCode = "invalid_template"
+	ErrCodeInvalidWebhook             ErrorCode = "invalid_webhook"
+	ErrCodeInvoiceNotFound            ErrorCode = "invoice_not_found"
+	ErrCodeNotOrderOwner              ErrorCode = "not_order_owner"
+	ErrCodeOrderConflict              ErrorCode = "order_conflict"
+	ErrCodeOrderExportPending         ErrorCode = "order_export_pending"
+	ErrCodeOrderNotConfirmed          ErrorCode = "order_not_confirmed"
+	ErrCodeOrderNotFound              ErrorCode = "order_not_found"
+	ErrCodeOrderNotInvoiceable        ErrorCode = "order_not_invoiceable"
+	ErrCodePaymentDisputed            ErrorCode = "payment_disputed"
+	ErrCodePaymentFailed              ErrorCode = "payment_failed"
+	ErrCodePaymentNotFound            ErrorCode = "payment_not_found"
+	ErrCodePaymentPending             ErrorCode = "payment_pending"
+	ErrCodePhoneCodeExpired           ErrorCode = "phone_code_expired"
+	ErrCodePhoneCodeInvalid           ErrorCode = "phone_code_invalid"
+	ErrCodePhoneCodeTooSoon           ErrorCode = "phone_code_too_soon"
+	ErrCodePhoneVerificationNotFound  ErrorCode = "phone_verification_not_found"
+	ErrCodePossibleDuplicateOrder     ErrorCode = "possible_duplicate_order"
+	ErrCodeProductInUse               ErrorCode = "product_in_use"
+	ErrCodeProductNotFound            ErrorCode = "product_not_found"
+	ErrCodeProductUnavailable         ErrorCode = "product_unavailable"
+	ErrCodeQuantityLimit              ErrorCode = "quantity_limit"
+	ErrCodeQuotaExceeded              ErrorCode = "quota_exceeded"
+	ErrCodeRefundFailed               ErrorCode = "refund_failed"
+	ErrCodeRefundNotAllowed           ErrorCode = "refund_not_allowed"
+	ErrCodeRefundRequestNotFound      ErrorCode = "refund_request_not_found"
+	ErrCodeRegionLoop                 ErrorCode = "region_loop"
+	ErrCodeSamePassword               ErrorCode = "same_password"
+	ErrCodeSelfApproval               ErrorCode = "self_approval"
+	ErrCodeTagNotFound                ErrorCode = "tag_not_found"
+	ErrCodeTemplateVersionConflict    ErrorCode = "template_version_conflict"
+	ErrCodeUnderage                   ErrorCode = "underage"
+	ErrCodeUnknownLocale              ErrorCode = "unknown_locale"
+	ErrCodeUnknownPaymentProvider     ErrorCode = "unknown_payment_provider"
+	ErrCodeUnknownTemplate            ErrorCode = "unknown_template"
+	ErrCodeUserExists                 ErrorCode = "user_exists"
+	ErrCodeUserHasOrders              ErrorCode = "user_has_orders"
+	ErrCodeUserNotFound               ErrorCode = "user_not_found"
+	ErrCodeWrongPassword              ErrorCode = "wrong_password"
+)
+
+// ErrorResponse is the body of every error response, except SCIM routes which respond with SCIM errors.
+type ErrorResponse struct {
+	Code      ErrorCode `json:"code" swaggertype:"string" example:"insufficient_stock"`
+	Message   string    `json:"message" example:"insufficient stock"`
+	Details   any       `json:"details"`                                         // Additional information depending on the code, null if there is none
+	RequestID string    `json:"request_id" example:"api-7d9f/kqXoHnL5Nv-000042"` // ID of the request in the server logs
+}
+
+// respondError writes an error response with the status, code and message.
+func respondError(w http.ResponseWriter, r *http.Request, status int, code ErrorCode, message string) {
+	writeError(w, r, status, ErrorResponse{Code: code, Message: message})
+}
+
+// respondErrorDetails writes an error response with the status, code, message and details of the code.
+func respondErrorDetails(w http.ResponseWriter, r *http.Request, status int, code ErrorCode, message string, details any) {
+	writeError(w, r, status, ErrorResponse{Code: code, Message: message, Details: details})
+}
+
+// respondValidationError writes the error of customvalidator.DecodeAndValidate: validation_failed with
+// the failed rule of each field as details, or invalid_request if the body could not be decoded.
+func respondValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	fields, ok := customvalidator.FieldErrors(err)
+	if !ok {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+	respondErrorDetails(w, r, http.StatusBadRequest, ErrCodeValidationFailed, "request validation failed", fields)
+}
+
+// writeError writes resp as JSON with the status and the ID of the request.
+// Like http.Error, it removes a Content-Length set by the handler.
+func writeError(w http.ResponseWriter, r *http.Request, status int, resp ErrorResponse) {
+	resp.RequestID = middleware.GetReqID(r.Context())
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", "application/json")
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp) // The status is sent, the client cannot be told about a failed write
+}
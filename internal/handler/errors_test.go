@@ -0,0 +1,130 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/domain"
+	"product-api/internal/handler"
+	"product-api/internal/logger"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// errorEnvelope is handler.ErrorResponse with details of the type D.
+type errorEnvelope[D any] struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Details   D      `json:"details"`
+	RequestID string `json:"request_id"`
+}
+
+// serveError serves the request with a request ID and decodes the error response.
+func serveError[D any](t *testing.T, h http.Handler, req *http.Request) (*httptest.ResponseRecorder, errorEnvelope[D]) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	var requestID string
+	middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = middleware.GetReqID(r.Context())
+		h.ServeHTTP(w, r)
+	})).ServeHTTP(rec, req)
+
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+	var resp errorEnvelope[D]
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp.RequestID)
+	assert.Equal(t, requestID, resp.RequestID, "the ID of the request in the server logs")
+	return rec, resp
+}
+
+func TestRequireConsent_RespondsWithPendingDocuments(t *testing.T) {
+	repo := mocks.NewMockConsentRepository(t)
+	consents := handler.NewConsentHandler(service.NewConsentService(repo), logger.NewSlogAdapter("local"))
+	userID := uuid.New()
+	terms := domain.LegalDocument{ID: uuid.New(), Type: domain.DocumentTypeTerms, Version: "2024-06-01", PublishedAt: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+	repo.EXPECT().FindPendingDocuments(mock.Anything, userID).Return([]domain.LegalDocument{terms}, nil)
+
+	h := consents.RequireConsent(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("requests of users with pending documents must not be served")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/users/me", nil)
+	req = req.WithContext(context.WithValue(req.Context(), handler.UserIDKey, userID.String()))
+	rec, resp := serveError[[]domain.LegalDocument](t, h, req)
+
+	assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+	assert.Equal(t, "consent_required", resp.Code)
+	assert.Equal(t, []domain.LegalDocument{terms}, resp.Details)
+}
+
+func TestRegister_RespondsWithLatestDocuments(t *testing.T) {
+	repo := mocks.NewMockConsentRepository(t)
+	users := handler.NewUserHandler(nil, service.NewConsentService(repo), logger.NewSlogAdapter("local"))
+	privacy := domain.LegalDocument{ID: uuid.New(), Type: domain.DocumentTypePrivacy, Version: "2024-06-01", PublishedAt: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+	repo.EXPECT().FindLatestDocuments(mock.Anything).Return([]domain.LegalDocument{privacy}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/register", strings.NewReader(`{"email": "user@example.com",
+		"password": "password123", "firstname": "John", "lastname": "Doe", "birthdate": "1999-04-21"}`))
+	rec, resp := serveError[[]domain.LegalDocument](t, http.HandlerFunc(users.Register), req)
+
+	assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+	assert.Equal(t, "consent_required", resp.Code)
+	assert.Equal(t, "consent required", resp.Message)
+	assert.Equal(t, []domain.LegalDocument{privacy}, resp.Details)
+}
+
+func TestRespondValidationError(t *testing.T) {
+	consents := handler.NewConsentHandler(service.NewConsentService(mocks.NewMockConsentRepository(t)), logger.NewSlogAdapter("local"))
+
+	t.Run("failed fields", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/users/me/consents", strings.NewReader(`{"type": "cookies"}`))
+		rec, resp := serveError[map[string]string](t, http.HandlerFunc(consents.Accept), req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "validation_failed", resp.Code)
+		assert.Equal(t, map[string]string{
+			"Type":    "failed on the 'oneof' tag",
+			"Version": "failed on the 'required' tag",
+		}, resp.Details)
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/users/me/consents", strings.NewReader(`{"type":`))
+		rec, resp := serveError[any](t, http.HandlerFunc(consents.Accept), req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "invalid_request", resp.Code)
+		assert.Nil(t, resp.Details, "details are null for codes without details")
+	})
+}
+
+func TestRespondError_ReplacesPartialResponseHeaders(t *testing.T) {
+	repo := mocks.NewMockConsentRepository(t)
+	consents := handler.NewConsentHandler(service.NewConsentService(repo), logger.NewSlogAdapter("local"))
+	repo.EXPECT().FindPendingDocuments(mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Headers of a response the handler started before it failed
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Length", "1024")
+		consents.RequireConsent(http.NotFoundHandler()).ServeHTTP(w, r)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/users/me", nil)
+	req = req.WithContext(context.WithValue(req.Context(), handler.UserIDKey, uuid.NewString()))
+	rec, resp := serveError[any](t, h, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "internal_error", resp.Code)
+	assert.Equal(t, "internal server error", resp.Message)
+	assert.Empty(t, rec.Header().Get("Content-Length"))
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// HealthHandler serves liveness and readiness probes of load balancers and orchestrators.
+// Readiness flips to unavailable when the server starts draining, so no new traffic is routed to it
+// while in-flight requests complete.
+type HealthHandler struct {
+	draining atomic.Bool
+}
+
+// NewHealthHandler creates a new health handler reporting ready.
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{}
+}
+
+// Drain makes readiness probes fail from now on.
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// Live godoc
+// @Summary Liveness probe
+// @Description Succeeds while the process serves requests, including while it drains.
+// @Tags health
+// @Produce  plain
+// @Success 200  {string}  string "ok"
+// @Router /healthz [get]
+func (h *HealthHandler) Live(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte("ok"))
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Fails once the server is draining before shutdown, so load balancers stop routing requests to it.
+// @Tags health
+// @Produce  plain
+// @Success 200  {string}  string "ok"
+// @Failure 503  {object}  ErrorResponse "draining"
+// @Router /readyz [get]
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		respondError(w, r, http.StatusServiceUnavailable, ErrCodeUnavailable, "draining")
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte("ok"))
+}
@@ -0,0 +1,29 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/handler"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthHandler_ReadinessFailsWhileDraining(t *testing.T) {
+	h := handler.NewHealthHandler()
+	probe := func(fn http.HandlerFunc) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		fn(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, probe(h.Live).Code)
+	assert.Equal(t, http.StatusOK, probe(h.Ready).Code)
+
+	h.Drain()
+	assert.Equal(t, http.StatusOK, probe(h.Live).Code, "in-flight requests are still served")
+	rec, resp := serveError[any](t, http.HandlerFunc(h.Ready), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "service_unavailable", resp.Code)
+	assert.Equal(t, "draining", resp.Message)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Idempotency-Key limits.
+const (
+	maxIdempotencyKeyLength = 255
+	maxIdempotentBodySize   = 1 << 20
+)
+
+// IdempotencyMiddleware creates middleware that deduplicates retried write requests by their Idempotency-Key header.
+// The first request with a key is executed and its response stored; retries by the same caller
+// to the same path get the stored response with Idempotent-Replayed: true.
+// A retry while the first request is running gets 409 Conflict, a key reused with a different body
+// gets 422 Unprocessable Entity. Server errors are not stored, so such requests can be retried.
+// Requests without the header are passed through.
+// Must be placed after JWTMiddleware or APIKeyMiddleware, as keys are scoped by caller.
+func IdempotencyMiddleware(s *service.IdempotencyService, l logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "IdempotencyMiddleware"
+			log := l.WithTrace(r.Context())
+
+			key := r.Header.Get("Idempotency-Key")
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "idempotency key is too long")
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize+1))
+			if err != nil {
+				respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid request body")
+				return
+			}
+			if len(body) > maxIdempotentBodySize {
+				respondError(w, r, http.StatusRequestEntityTooLarge, ErrCodePayloadTooLarge, "request body too large")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			hash := sha256.Sum256(body)
+
+			scope := callerID(r.Context()) + " " + r.Method + " " + r.URL.Path
+			stored, err := s.Begin(r.Context(), scope, key, hex.EncodeToString(hash[:]))
+			switch {
+			case errors.Is(err, service.ErrIdempotencyKeyInProgress):
+				respondError(w, r, http.StatusConflict, ErrCodeIdempotencyKeyInProgress, err.Error())
+				return
+			case errors.Is(err, service.ErrIdempotencyKeyReused):
+				respondError(w, r, http.StatusUnprocessableEntity, ErrCodeIdempotencyKeyReused, err.Error())
+				return
+			case err != nil:
+				log.Error("failed to reserve idempotency key", "op", op, "error", err)
+				respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+				return
+			case stored != nil:
+				if stored.ContentType != "" {
+					w.Header().Set("Content-Type", stored.ContentType)
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(stored.StatusCode)
+				if _, err := w.Write(stored.Body); err != nil {
+					log.Error("failed to write replayed response", "op", op, "error", err)
+				}
+				return
+			}
+
+			// The outcome must be stored even if the client went away
+			storeCtx := context.WithoutCancel(r.Context())
+			completed := false
+			defer func() {
+				if !completed {
+					// Handler panicked, release the key so the request can be retried
+					if err := s.Release(storeCtx, scope, key); err != nil {
+						log.Error("failed to release idempotency key", "op", op, "error", err)
+					}
+				}
+			}()
+
+			var response bytes.Buffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&response)
+			next.ServeHTTP(ww, r)
+			completed = true
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if err := s.Complete(storeCtx, scope, key, status, ww.Header().Get("Content-Type"), response.Bytes()); err != nil {
+				log.Error("failed to store idempotent response", "op", op, "error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Inbox page size limits.
+const (
+	defaultInboxLimit = 20
+	maxInboxLimit     = 100
+)
+
+// UnreadCountResponse is the number of unread notifications shown on the bell icon.
+type UnreadCountResponse struct {
+	Unread int `example:"3"`
+}
+
+// InboxHandler handles HTTP requests related to the notification inbox of the current user.
+type InboxHandler struct {
+	service *service.InboxService
+	logger  logger.Logger
+}
+
+// NewInboxHandler creates a new inbox handler.
+func NewInboxHandler(s *service.InboxService, l logger.Logger) *InboxHandler {
+	return &InboxHandler{service: s, logger: l}
+}
+
+// List godoc
+// @Summary List notifications of the current user
+// @Description Returns notifications delivered to the in-app inbox, newest first, with the number of unread ones.
+// @Description Users receive them for the categories they opted in to on the in_app channel.
+// @Tags users
+// @Produce  json
+// @Param   unread  query  bool  false  "Only unread notifications"
+// @Param   limit   query  int   false  "Maximum number of notifications (1-100, default 20)"
+// @Param   offset  query  int   false  "Number of notifications to skip"
+// @Security ApiKeyAuth
+// @Success 200  {object}  service.InboxPage
+// @Failure 400  {object}  ErrorResponse "Invalid unread, limit or offset"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/notifications [get]
+func (h *InboxHandler) List(w http.ResponseWriter, r *http.Request) {
+	const op = "InboxHandler.List"
+	log := h.logger.WithTrace(r.Context())
+
+	query := r.URL.Query()
+	var unreadOnly bool
+	if v := query.Get("unread"); v != "" {
+		var err error
+		if unreadOnly, err = strconv.ParseBool(v); err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid unread")
+			return
+		}
+	}
+	limit, err := parsePositiveInt(query.Get("limit"), defaultInboxLimit)
+	if err != nil || limit > maxInboxLimit {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid limit")
+		return
+	}
+	var offset int
+	if v := query.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid offset")
+			return
+		}
+	}
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	page, err := h.service.List(r.Context(), userID, unreadOnly, offset, limit)
+	if err != nil {
+		log.Error("failed to list inbox notifications", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		log.Error("failed to encode inbox page", "op", op, "error", err)
+	}
+}
+
+// UnreadCount godoc
+// @Summary Count unread notifications of the current user
+// @Tags users
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200  {object}  UnreadCountResponse
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/notifications/unread-count [get]
+func (h *InboxHandler) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	const op = "InboxHandler.UnreadCount"
+	log := h.logger.WithTrace(r.Context())
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	count, err := h.service.UnreadCount(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to count unread notifications", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(UnreadCountResponse{Unread: count}); err != nil {
+		log.Error("failed to encode unread count", "op", op, "error", err)
+	}
+}
+
+// MarkRead godoc
+// @Summary Mark a notification of the current user as read
+// @Tags users
+// @Param   id  path  string  true  "Notification ID"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 400  {object}  ErrorResponse "Invalid notification ID"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 404  {object}  ErrorResponse "Notification not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/notifications/{id}/read [post]
+func (h *InboxHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	const op = "InboxHandler.MarkRead"
+	log := h.logger.WithTrace(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid notification ID")
+		return
+	}
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	if err := h.service.MarkRead(r.Context(), userID, id); err != nil {
+		if errors.Is(err, service.ErrInboxNotificationNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeInboxNotificationNotFound, err.Error())
+			return
+		}
+		log.Error("failed to mark notification read", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications of the current user as read
+// @Tags users
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/notifications/read-all [post]
+func (h *InboxHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	const op = "InboxHandler.MarkAllRead"
+	log := h.logger.WithTrace(r.Context())
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	if err := h.service.MarkAllRead(r.Context(), userID); err != nil {
+		log.Error("failed to mark notifications read", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/money"
+	"product-api/internal/service"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// InvoiceResponse is an invoice with its amount in minor units of the currency.
+type InvoiceResponse struct {
+	domain.Invoice
+	Total money.Money
+}
+
+// InvoiceHandler handles HTTP requests related to invoices.
+type InvoiceHandler struct {
+	service *service.InvoiceService
+	money   *money.Formatter
+	logger  logger.Logger
+}
+
+// NewInvoiceHandler creates a new invoice handler.
+func NewInvoiceHandler(s *service.InvoiceService, f *money.Formatter, l logger.Logger) *InvoiceHandler {
+	return &InvoiceHandler{service: s, money: f, logger: l}
+}
+
+// Issue godoc
+// @Summary Issue the invoice of an order
+// @Description Issues an invoice over the order total with the next number of the current fiscal year, e.g. INV-2024-000001.
+// @Description Invoice numbers are strictly sequential without gaps. Invoices cannot be changed once issued.
+// @Tags admin
+// @Produce  json
+// @Param   id  path  string  true  "Order ID"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 201  {object}  InvoiceResponse
+// @Failure 400  {object}  ErrorResponse "Invalid order ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Order not found"
+// @Failure 409  {object}  ErrorResponse "Order not paid or already invoiced"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/orders/{id}/invoice [post]
+func (h *InvoiceHandler) Issue(w http.ResponseWriter, r *http.Request) {
+	const op = "InvoiceHandler.Issue"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+
+	invoice, err := h.service.Issue(r.Context(), orderID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeOrderNotFound, "order not found")
+		case errors.Is(err, service.ErrOrderNotInvoiceable), errors.Is(err, service.ErrInvoiceExists):
+			respondError(w, r, http.StatusConflict, ErrCodeOrderNotInvoiceable, err.Error())
+		default:
+			log.Error("failed to issue invoice", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+	log.Info("invoice issued", "op", op, "order_id", orderID, "number", invoice.Number)
+
+	h.writeInvoice(w, r, invoice, http.StatusCreated)
+}
+
+// Get godoc
+// @Summary Get the invoice of an order
+// @Tags admin
+// @Produce  json
+// @Param   id  path  string  true  "Order ID"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  InvoiceResponse
+// @Failure 400  {object}  ErrorResponse "Invalid order ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Invoice not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/orders/{id}/invoice [get]
+func (h *InvoiceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	const op = "InvoiceHandler.Get"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+
+	invoice, err := h.service.Get(r.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvoiceNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeInvoiceNotFound, "invoice not found")
+			return
+		}
+		log.Error("failed to get invoice", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	h.writeInvoice(w, r, invoice, http.StatusOK)
+}
+
+// Document godoc
+// @Summary Download the invoice document of an order
+// @Description Redirects to a short-lived download URL of the archived invoice document in the object storage.
+// @Description Storage backends without presigned URLs serve the document directly.
+// @Tags admin
+// @Produce  json
+// @Param   id  path  string  true  "Order ID"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  domain.Invoice "Invoice document"
+// @Success 302  {string}  string "Redirect to the document"
+// @Failure 400  {object}  ErrorResponse "Invalid order ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Invoice not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/orders/{id}/invoice/document [get]
+func (h *InvoiceHandler) Document(w http.ResponseWriter, r *http.Request) {
+	const op = "InvoiceHandler.Document"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+
+	doc, err := h.service.Document(r.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvoiceNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeInvoiceNotFound, "invoice not found")
+			return
+		}
+		log.Error("failed to get invoice document", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	h.writeDocument(w, r, doc)
+}
+
+// CustomerDocument godoc
+// @Summary Download the invoice document of an order of the current user
+// @Description Linked from invoice emails. Redirects to a short-lived download URL of the archived invoice document,
+// @Description storage backends without presigned URLs serve the document directly.
+// @Tags orders
+// @Produce  json
+// @Param   id  path  string  true  "Order ID"
+// @Security ApiKeyAuth
+// @Success 200  {object}  domain.Invoice "Invoice document"
+// @Success 302  {string}  string "Redirect to the document"
+// @Failure 400  {object}  ErrorResponse "Invalid order ID"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 404  {object}  ErrorResponse "Invoice not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /orders/{id}/invoice/document [get]
+func (h *InvoiceHandler) CustomerDocument(w http.ResponseWriter, r *http.Request) {
+	const op = "InvoiceHandler.CustomerDocument"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	doc, err := h.service.CustomerDocument(r.Context(), userID, orderID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvoiceNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeInvoiceNotFound, "invoice not found")
+			return
+		}
+		log.Error("failed to get invoice document", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	h.writeDocument(w, r, doc)
+}
+
+// Resend godoc
+// @Summary Resend the invoice of an order
+// @Description Emails the invoice with a link to its document to the customer again,
+// @Description through the channels the customer opted in to for order updates.
+// @Tags admin
+// @Param   id  path  string  true  "Order ID"
+// @Param   X-API-Key  header  string  true  "Admin or support API key"
+// @Success 204
+// @Failure 400  {object}  ErrorResponse "Invalid order ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Invoice not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/orders/{id}/invoice/resend [post]
+func (h *InvoiceHandler) Resend(w http.ResponseWriter, r *http.Request) {
+	const op = "InvoiceHandler.Resend"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+
+	invoice, err := h.service.Send(r.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvoiceNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeInvoiceNotFound, "invoice not found")
+			return
+		}
+		log.Error("failed to resend invoice", "op", op, "order_id", orderID, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	log.Info("invoice resent", "op", op, "order_id", orderID, "invoice", invoice.Number, "actor", callerID(r.Context()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeDocument redirects to the download URL of the invoice document, or writes its contents.
+func (h *InvoiceHandler) writeDocument(w http.ResponseWriter, r *http.Request, doc *service.InvoiceDocument) {
+	if doc.URL != "" {
+		http.Redirect(w, r, doc.URL, http.StatusFound)
+		return
+	}
+
+	defer doc.Object.Body.Close()
+	w.Header().Set("Content-Type", doc.Object.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(doc.Object.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(doc.Object.Key)}))
+	if _, err := io.Copy(w, doc.Object.Body); err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to write invoice document", "error", err)
+	}
+}
+
+// writeInvoice writes the invoice with its amount in minor units of its currency, formatted in its locale.
+func (h *InvoiceHandler) writeInvoice(w http.ResponseWriter, r *http.Request, invoice *domain.Invoice, status int) {
+	f, err := h.money.In(invoice.Currency, invoice.Locale)
+	if err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to format invoice amount", "invoice", invoice.Number, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	resp := InvoiceResponse{Invoice: *invoice, Total: f.Money(invoice.Amount)}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to encode invoice response", "error", err)
+	}
+}
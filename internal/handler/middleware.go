@@ -2,10 +2,15 @@ package handler
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/service"
 	"strings"
 
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // contextKey is used for safe value storage in context.
@@ -14,52 +19,174 @@ type contextKey string
 // UserIDKey is the key for storing user ID in request context.
 const UserIDKey contextKey = "userID"
 
+// errNoUserID is returned when request context does not contain a user ID.
+var errNoUserID = errors.New("user id not found in context")
+
+// userIDFromContext extracts the authenticated user ID set by JWTMiddleware.
+func userIDFromContext(r *http.Request) (uuid.UUID, error) {
+	userIDStr, ok := r.Context().Value(UserIDKey).(string)
+	if !ok {
+		return uuid.Nil, errNoUserID
+	}
+	return uuid.Parse(userIDStr)
+}
+
+// TokenClaimsKey is the key for storing parsed access token claims in request context.
+const TokenClaimsKey contextKey = "tokenClaims"
+
 // JWTMiddleware creates middleware for JWT token validation in Authorization header.
-// Extracts user ID from token and adds it to request context.
+// Rejects revoked tokens. Extracts user ID from token and adds it to request context.
 // Requires header format: "Bearer <token>".
-func JWTMiddleware(jwtSecret []byte) func(http.Handler) http.Handler {
+func JWTMiddleware(tokens *service.TokenService) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Check for Authorization header
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				http.Error(w, "missing auth token", http.StatusUnauthorized)
+				respondError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized, "missing auth token")
 				return
 			}
 
 			// Extract token from header (format: "Bearer <token>")
 			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 			if tokenString == authHeader {
-				http.Error(w, "invalid auth token format", http.StatusUnauthorized)
+				respondError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized, "invalid auth token format")
 				return
 			}
 
 			// Parse and validate token
-			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-				// Check signing method (must be HMAC)
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, http.ErrAbortHandler
-				}
-				return jwtSecret, nil
-			})
+			claims, err := tokens.Parse(tokenString)
+			if err != nil {
+				respondError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized, "invalid token")
+				return
+			}
+
+			// Check that the token was not revoked (e.g. on logout)
+			revoked, err := tokens.IsRevoked(r.Context(), claims)
+			if err != nil {
+				respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+				return
+			}
+			if revoked {
+				respondError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized, "token revoked")
+				return
+			}
 
+			// Add user ID and claims to context
+			setSnapshotUserID(r.Context(), claims.UserID.String())
+			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID.String())
+			ctx = context.WithValue(ctx, TokenClaimsKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// CurrentUserKey is the key for storing the authenticated user in request context.
+const CurrentUserKey contextKey = "currentUser"
+
+// UserFromContext returns the authenticated user loaded by UserMiddleware.
+func UserFromContext(ctx context.Context) (*domain.User, bool) {
+	user, ok := ctx.Value(CurrentUserKey).(*domain.User)
+	return user, ok
+}
+
+// UserMiddleware creates middleware that loads the authenticated user once per request
+// and adds it to request context (see UserFromContext).
+// Rejects requests of deleted (401) and disabled (403) users, and tokens issued before a role or password change (401).
+// Must be placed after JWTMiddleware.
+func UserMiddleware(users *service.UsersService, l logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			log := l.WithTrace(r.Context())
+
+			userID, err := userIDFromContext(r)
 			if err != nil {
-				http.Error(w, "invalid token", http.StatusUnauthorized)
+				log.Error("failed to get user id from context", "error", err)
+				respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+				return
+			}
+
+			user, err := users.GetUser(r.Context(), userID)
+			if err != nil {
+				if errors.Is(err, service.ErrUserNotFound) {
+					respondError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized, "invalid token")
+					return
+				}
+				log.Error("failed to load current user", "error", err)
+				respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+				return
+			}
+
+			if !user.IsActive {
+				respondError(w, r, http.StatusForbidden, ErrCodeAccountDisabled, "account is disabled")
+				return
+			}
+
+			// Tokens carry the role at login, a changed role or password requires logging in again
+			if claims, ok := r.Context().Value(TokenClaimsKey).(*service.TokenClaims); ok && !claims.IsCurrentFor(user) {
+				respondError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized, "role or password changed, log in again")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), CurrentUserKey, user)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireRole creates middleware rejecting (403) users whose token grants none of the roles.
+// Must be placed after JWTMiddleware and UserMiddleware, which rejects tokens of changed roles.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := r.Context().Value(TokenClaimsKey).(*service.TokenClaims)
+			if !ok || !claims.HasRole(roles...) {
+				respondError(w, r, http.StatusForbidden, ErrCodeForbidden, "insufficient role")
 				return
 			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
 
-			// Extract user ID from claims and add to context
-			if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-				userID, ok := claims["sub"].(string)
-				if !ok {
-					http.Error(w, "invalid token claims", http.StatusUnauthorized)
+// APIKeyNameKey is the key for storing the authenticated API client name in request context.
+const APIKeyNameKey contextKey = "apiKeyName"
+
+// APIKeyMiddleware creates middleware for API key validation in X-API-Key header.
+// keys maps client names to their secret keys; only clients listed in allowed are accepted.
+// The name of the authenticated client is added to request context.
+func APIKeyMiddleware(keys map[string]string, allowed ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey := r.Header.Get("X-API-Key")
+			if apiKey == "" {
+				respondError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized, "missing api key")
+				return
+			}
+
+			// Find a client from the allowed list with a matching key
+			for _, name := range allowed {
+				key, ok := keys[name]
+				if ok && key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+					ctx := context.WithValue(r.Context(), APIKeyNameKey, name)
+					next.ServeHTTP(w, r.WithContext(ctx))
 					return
 				}
-				ctx := context.WithValue(r.Context(), UserIDKey, userID)
-				next.ServeHTTP(w, r.WithContext(ctx))
-			} else {
-				http.Error(w, "invalid token", http.StatusUnauthorized)
 			}
+
+			respondError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized, "invalid api key")
 		})
 	}
 }
+
+// callerID identifies the authenticated user or API client of the request,
+// as "user:<id>" or "client:<name>", for keying and attributing their actions.
+func callerID(ctx context.Context) string {
+	if userID, ok := ctx.Value(UserIDKey).(string); ok {
+		return "user:" + userID
+	}
+	if client, ok := ctx.Value(APIKeyNameKey).(string); ok {
+		return "client:" + client
+	}
+	return "anonymous"
+}
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/money"
+	"product-api/internal/notification"
+	"product-api/internal/service"
+	customvalidator "product-api/pkg/validator"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SaveTemplateRequest contains a new version of a notification template.
+// Variables are written as {{.Name}}, e.g. {{.OrderNumber}}.
+type SaveTemplateRequest struct {
+	Locale  string `json:"locale" example:"de" validate:"max=35"` // Language or locale of the version, empty for the default version
+	Subject string `json:"subject" example:"Your order {{.OrderNumber}}" validate:"required,max=255"`
+	Body    string `json:"body" example:"Hi {{.FirstName}}, your order {{.OrderNumber}} for {{.Total}} has been placed." validate:"required,max=20000"`
+}
+
+// PreviewTemplateRequest contains a template to render. An empty subject or body renders the current one.
+type PreviewTemplateRequest struct {
+	Locale    string            `json:"locale" example:"de-AT" validate:"max=35"` // Locale whose current version is rendered, empty for the default version
+	Subject   string            `json:"subject" example:"Your order {{.OrderNumber}}" validate:"max=255"`
+	Body      string            `json:"body" validate:"max=20000"`
+	Variables map[string]string `json:"variables"` // Values of template variables, sample values are used for others
+}
+
+// NotificationTemplateResponse is the current version of a notification template with the variables it can use.
+type NotificationTemplateResponse struct {
+	domain.NotificationTemplate
+	Variables []string `example:"Email,FirstName,LastName,OrderNumber,Total"`
+}
+
+// TemplatePreviewResponse is a rendered notification.
+type TemplatePreviewResponse struct {
+	Subject string `example:"Your order ORD-2024-000123"`
+	Body    string `example:"Hi Jane, your order ORD-2024-000123 for $25.00 has been placed."`
+}
+
+// NotificationTemplateHandler handles HTTP requests related to notification templates.
+type NotificationTemplateHandler struct {
+	service *service.NotificationTemplateService
+	logger  logger.Logger
+}
+
+// NewNotificationTemplateHandler creates a new notification template handler.
+func NewNotificationTemplateHandler(s *service.NotificationTemplateService, l logger.Logger) *NotificationTemplateHandler {
+	return &NotificationTemplateHandler{service: s, logger: l}
+}
+
+// List godoc
+// @Summary List notification templates
+// @Description Returns the current version of every notification template with its variables, followed by
+// @Description its current versions in other locales. Templates never edited have version 0 and their built-in content.
+// @Tags admin
+// @Produce  json
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {array}   NotificationTemplateResponse
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/notification-templates [get]
+func (h *NotificationTemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	const op = "NotificationTemplateHandler.List"
+	log := h.logger.WithTrace(r.Context())
+
+	templates, err := h.service.List(r.Context())
+	if err != nil {
+		log.Error("failed to list notification templates", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	resp := make([]NotificationTemplateResponse, len(templates))
+	for i, t := range templates {
+		spec, _ := notification.LookupTemplateSpec(t.Name)
+		resp[i] = NotificationTemplateResponse{NotificationTemplate: t, Variables: slices.Sorted(maps.Keys(spec.Variables))}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("failed to encode notification templates", "op", op, "error", err)
+	}
+}
+
+// Versions godoc
+// @Summary List versions of a notification template
+// @Tags admin
+// @Produce  json
+// @Param   name  path  string  true  "Template name, e.g. order_confirmation"
+// @Param   locale  query  string  false  "Language or locale of the versions, e.g. de; default versions if omitted"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {array}   domain.NotificationTemplate "Newest first"
+// @Failure 400  {object}  ErrorResponse "Unsupported locale"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Unknown template"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/notification-templates/{name}/versions [get]
+func (h *NotificationTemplateHandler) Versions(w http.ResponseWriter, r *http.Request) {
+	const op = "NotificationTemplateHandler.Versions"
+	log := h.logger.WithTrace(r.Context())
+
+	versions, err := h.service.Versions(r.Context(), chi.URLParam(r, "name"), r.URL.Query().Get("locale"))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnknownTemplate):
+			respondError(w, r, http.StatusNotFound, ErrCodeUnknownTemplate, err.Error())
+		case errors.Is(err, money.ErrUnknownLocale):
+			respondError(w, r, http.StatusBadRequest, ErrCodeUnknownLocale, err.Error())
+		default:
+			log.Error("failed to list notification template versions", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(versions); err != nil {
+		log.Error("failed to encode notification template versions", "op", op, "error", err)
+	}
+}
+
+// Save godoc
+// @Summary Save a new version of a notification template
+// @Description The version is sent from the next notification of this instance, and from other instances
+// @Description once their templates reload (TEMPLATE_RELOAD_INTERVAL). Earlier versions are kept.
+// @Description Versions in a locale are sent to users of the locale, e.g. "de-AT", or of its language, e.g. "de";
+// @Description other users get the default version.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   name  path  string  true  "Template name, e.g. order_confirmation"
+// @Param   template  body  SaveTemplateRequest  true  "Template"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 201  {object}  domain.NotificationTemplate
+// @Failure 400  {object}  ErrorResponse "Invalid template syntax, unknown variables or unsupported locale"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Unknown template"
+// @Failure 409  {object}  ErrorResponse "Template changed concurrently"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/notification-templates/{name} [post]
+func (h *NotificationTemplateHandler) Save(w http.ResponseWriter, r *http.Request) {
+	const op = "NotificationTemplateHandler.Save"
+	log := h.logger.WithTrace(r.Context())
+
+	var req SaveTemplateRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	template, err := h.service.Save(r.Context(), callerID(r.Context()), chi.URLParam(r, "name"), req.Locale, req.Subject, req.Body)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnknownTemplate):
+			respondError(w, r, http.StatusNotFound, ErrCodeUnknownTemplate, err.Error())
+		case errors.Is(err, domain.ErrInvalidTemplate), errors.Is(err, money.ErrUnknownLocale):
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidTemplate, err.Error())
+		case errors.Is(err, service.ErrTemplateVersionConflict):
+			respondError(w, r, http.StatusConflict, ErrCodeTemplateVersionConflict, err.Error())
+		default:
+			log.Error("failed to save notification template", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+	log.Info("notification template saved", "op", op, "name", template.Name, "locale", template.Locale, "version", template.Version, "actor", template.CreatedBy)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(template); err != nil {
+		log.Error("failed to encode notification template", "op", op, "error", err)
+	}
+}
+
+// Preview godoc
+// @Summary Preview a notification template
+// @Description Renders the given subject and body, or those sent to users of the locale, with sample values
+// @Description of the template variables overridden by the given values. Nothing is stored or sent.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   name  path  string  true  "Template name, e.g. order_confirmation"
+// @Param   preview  body  PreviewTemplateRequest  true  "Template and variables"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  TemplatePreviewResponse
+// @Failure 400  {object}  ErrorResponse "Invalid template syntax, unknown variables or unsupported locale"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Unknown template"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/notification-templates/{name}/preview [post]
+func (h *NotificationTemplateHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	const op = "NotificationTemplateHandler.Preview"
+	log := h.logger.WithTrace(r.Context())
+
+	var req PreviewTemplateRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	msg, err := h.service.Preview(r.Context(), chi.URLParam(r, "name"), service.TemplatePreviewInput{
+		Locale:    req.Locale,
+		Subject:   req.Subject,
+		Body:      req.Body,
+		Variables: req.Variables,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnknownTemplate):
+			respondError(w, r, http.StatusNotFound, ErrCodeUnknownTemplate, err.Error())
+		case errors.Is(err, domain.ErrInvalidTemplate), errors.Is(err, money.ErrUnknownLocale):
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidTemplate, err.Error())
+		default:
+			log.Error("failed to preview notification template", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(TemplatePreviewResponse{Subject: msg.Subject, Body: msg.Body}); err != nil {
+		log.Error("failed to encode template preview", "op", op, "error", err)
+	}
+}
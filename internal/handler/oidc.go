@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/logger"
+	"product-api/internal/oidc"
+	"product-api/internal/service"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// oidcStateCookie stores the state and nonce of an in-progress OIDC login.
+const oidcStateCookie = "oidc_state"
+
+// oidcStateTTL is how long a user may take to authenticate at the identity provider.
+const oidcStateTTL = 10 * time.Minute
+
+// OIDCHandler handles OpenID Connect login through external identity providers.
+type OIDCHandler struct {
+	providers *oidc.Registry
+	service   *service.UsersService
+	logger    logger.Logger
+}
+
+// NewOIDCHandler creates a new OIDC handler.
+func NewOIDCHandler(providers *oidc.Registry, s *service.UsersService, l logger.Logger) *OIDCHandler {
+	return &OIDCHandler{providers: providers, service: s, logger: l}
+}
+
+// Providers godoc
+// @Summary List configured OIDC identity providers
+// @Tags auth
+// @Produce  json
+// @Success 200  {array}  string
+// @Router /auth/oidc [get]
+func (h *OIDCHandler) Providers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.providers.Names()); err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to encode providers", "error", err)
+	}
+}
+
+// Login godoc
+// @Summary Start login with an OIDC identity provider
+// @Description Redirects the browser to the identity provider authorization page.
+// @Tags auth
+// @Param   provider  path  string  true  "Provider name"
+// @Success 302
+// @Failure 404  {object}  ErrorResponse "Unknown provider"
+// @Failure 502  {object}  ErrorResponse "Identity provider unavailable"
+// @Router /auth/oidc/{provider}/login [get]
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	const op = "OIDCHandler.Login"
+	log := h.logger.WithTrace(r.Context())
+
+	provider, ok := h.providers.Get(chi.URLParam(r, "provider"))
+	if !ok {
+		respondError(w, r, http.StatusNotFound, ErrCodeNotFound, "unknown identity provider")
+		return
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		log.Error("failed to generate state", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		log.Error("failed to generate nonce", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	authURL, err := provider.AuthCodeURL(r.Context(), state, nonce)
+	if err != nil {
+		log.Error("failed to build authorization url", "op", op, "provider", provider.Name(), "error", err)
+		respondError(w, r, http.StatusBadGateway, ErrCodeUpstreamFailed, "identity provider unavailable")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state + "." + nonce,
+		Path:     "/auth/oidc",
+		MaxAge:   int(oidcStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback godoc
+// @Summary Complete login with an OIDC identity provider
+// @Description Verifies the ID token, links the identity to a local user and returns a JWT token.
+// @Tags auth
+// @Produce  json
+// @Param   provider  path   string  true  "Provider name"
+// @Param   code      query  string  true  "Authorization code"
+// @Param   state     query  string  true  "State"
+// @Success 200  {object}  LoginResponse
+// @Failure 400  {object}  ErrorResponse "Invalid state or authorization code"
+// @Failure 403  {object}  ErrorResponse "No linked account or account is disabled"
+// @Failure 404  {object}  ErrorResponse "Unknown provider"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /auth/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	const op = "OIDCHandler.Callback"
+	log := h.logger.WithTrace(r.Context())
+
+	provider, ok := h.providers.Get(chi.URLParam(r, "provider"))
+	if !ok {
+		respondError(w, r, http.StatusNotFound, ErrCodeNotFound, "unknown identity provider")
+		return
+	}
+
+	// Verify state against the cookie set at login start (CSRF protection)
+	cookie, err := r.Cookie(oidcStateCookie)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "missing login state")
+		return
+	}
+	state, nonce, found := strings.Cut(cookie.Value, ".")
+	if !found || state == "" || r.URL.Query().Get("state") != state {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid login state")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: oidcStateCookie, Path: "/auth/oidc", MaxAge: -1})
+
+	if errParam := r.URL.Query().Get("error"); errParam != "" {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "authentication failed: "+errParam)
+		return
+	}
+
+	claims, err := provider.Exchange(r.Context(), r.URL.Query().Get("code"), nonce)
+	if err != nil {
+		log.Warn("oidc code exchange failed", "op", op, "provider", provider.Name(), "error", err)
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid authorization code")
+		return
+	}
+
+	identity := service.FederatedIdentity{
+		Provider:      provider.Name(),
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Firstname:     claims.GivenName,
+		Lastname:      claims.FamilyName,
+	}
+	if birthdate, err := time.Parse(time.DateOnly, claims.Birthdate); err == nil {
+		identity.Birthdate = &birthdate
+	}
+
+	meta := service.LoginMetadata{IPAddress: r.RemoteAddr, UserAgent: r.UserAgent()}
+	token, err := h.service.FederatedLogin(r.Context(), identity, meta)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrFederatedAccountNotFound),
+			errors.Is(err, service.ErrAccountDisabled),
+			errors.Is(err, service.ErrUnderage):
+			respondError(w, r, http.StatusForbidden, ErrCodeUnderage, err.Error())
+		default:
+			log.Error("failed to complete federated login", "op", op, "provider", provider.Name(), "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(LoginResponse{Token: token}); err != nil {
+		log.Error("failed to write login response", "op", op, "error", err)
+	}
+}
+
+// randomToken generates a random URL-safe token.
+func randomToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
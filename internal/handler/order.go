@@ -4,10 +4,15 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"product-api/internal/domain"
 	"product-api/internal/logger"
+	"product-api/internal/money"
 	"product-api/internal/service"
 	customvalidator "product-api/pkg/validator"
+	"strconv"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
@@ -17,33 +22,136 @@ type OrderItemInput struct {
 	Quantity  int       `json:"quantity" validate:"required,gt=0"`
 }
 
+// OrderOptionsRequest selects order-level options, each charged with its configured fee.
+type OrderOptionsRequest struct {
+	GiftWrap         bool   `json:"gift_wrap" example:"true"`
+	GiftMessage      string `json:"gift_message" example:"Happy birthday!" validate:"max=500"` // Printed on a gift card in the parcel, empty for none
+	PriorityHandling bool   `json:"priority_handling" example:"false"`
+}
+
 // CreateOrderRequest contains data for creating a new order.
 type CreateOrderRequest struct {
-	Items []OrderItemInput `json:"items" validate:"required,min=1,dive"`
+	Items            []OrderItemInput    `json:"items" validate:"required,min=1,dive"`
+	Options          OrderOptionsRequest `json:"options"`
+	DeliverySlotID   uuid.UUID           `json:"delivery_slot_id"`                                    // Delivery window from GET /delivery-slots, none if omitted
+	ConfirmDuplicate bool                `json:"confirm_duplicate" example:"false"`                   // Place the order even if it repeats a recent order of the user
+	PaymentProvider  string              `json:"payment_provider" example:"paypal" validate:"max=32"` // Payment provider, default if empty
+	PaymentToken     string              `json:"payment_token" validate:"max=255"`                    // Provider token of the buyer's payment method, e.g. a PayPal vault ID
+}
+
+// DuplicateOrderDetails are the details of possible_duplicate_order errors: the recent order the checkout repeats.
+type DuplicateOrderDetails struct {
+	OrderID   uuid.UUID `json:"order_id"`
+	Number    string    `json:"number" example:"ORD-2026-000123"`
+	Status    string    `json:"status" example:"paid"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PayOrderRequest contains the payment method paying an order awaiting payment.
+type PayOrderRequest struct {
+	PaymentProvider string `json:"payment_provider" example:"stripe" validate:"max=32"` // Payment provider, default if empty
+	PaymentToken    string `json:"payment_token" validate:"max=255"`                    // Provider token of the buyer's payment method, e.g. a Stripe payment method ID
+}
+
+// ChangeOrderStatusRequest contains the new status of an order.
+type ChangeOrderStatusRequest struct {
+	Status string `json:"status" example:"paid" validate:"required,oneof=paid shipped delivered cancelled"`
+}
+
+// OrderNumberSettingsRequest contains the format of new order numbers, e.g. prefix "ORD" and padding 6 for ORD-2024-000123.
+type OrderNumberSettingsRequest struct {
+	Prefix  string `json:"prefix" example:"ORD" validate:"required,max=16"`      // Uppercase letters and digits, starting with a letter
+	Padding int    `json:"padding" example:"6" validate:"required,gte=1,lte=12"` // Minimum number of digits of the yearly sequence number
+}
+
+// RecordPaymentRequest contains a payment of an order made outside checkout, e.g. a gift card or a deposit.
+type RecordPaymentRequest struct {
+	Method    string  `json:"method" example:"gift_card" validate:"required,oneof=card gift_card bank_transfer cash"`
+	Reference string  `json:"reference" example:"GC-1234" validate:"max=255"` // Provider charge ID for card payments, gift card code or transfer reference
+	Amount    float64 `json:"amount" example:"20.00" validate:"required,gt=0"`
+}
+
+// RefundRequest contains the charge to refund and the refunded amount.
+type RefundRequest struct {
+	PaymentID uuid.UUID `json:"payment_id" validate:"required"`
+	Amount    float64   `json:"amount" example:"5.00" validate:"required,gt=0"`
+}
+
+// orderStatusEvents maps requested statuses to the events recording them.
+var orderStatusEvents = map[string]string{
+	domain.OrderStatusPaid:      domain.OrderEventPaid,
+	domain.OrderStatusShipped:   domain.OrderEventShipped,
+	domain.OrderStatusDelivered: domain.OrderEventDelivered,
+	domain.OrderStatusCancelled: domain.OrderEventCancelled,
+}
+
+// OrderResponse is an order with its total, paid and refunded amounts in minor units of the currency,
+// so clients do not have to guess decimal places. Paid and refunded amounts are sums of the payment ledger.
+type OrderResponse struct {
+	domain.Order
+	Total         money.Money
+	Paid          money.Money
+	Refunded      money.Money
+	DisputeStatus string `example:"open"` // open, won or lost if a payment of the order is disputed, empty otherwise
+}
+
+// OrderSummaryResponse is an order in the list of the user's orders, with its total in minor units of the currency.
+type OrderSummaryResponse struct {
+	ID        uuid.UUID
+	Number    string `example:"ORD-2024-000123"`
+	Status    string `example:"paid"`
+	CreatedAt time.Time
+	Items     []domain.OrderItem
+	Total     money.Money
+	Sandbox   bool `json:",omitempty"` // Placed by a sandbox account
+}
+
+// OrderListResponse is a page of the user's orders.
+type OrderListResponse struct {
+	Orders []OrderSummaryResponse // Newest first
+	Total  int                    // Number of the user's orders
 }
 
+// Order list page size limits.
+const (
+	defaultOrderListLimit = 20
+	maxOrderListLimit     = 100
+)
+
 // OrderHandler handles HTTP requests related to orders.
 type OrderHandler struct {
 	service *service.OrderService
+	money   *money.Formatter
 	logger  logger.Logger
 }
 
 // NewOrderHandler creates a new order handler.
-func NewOrderHandler(s *service.OrderService, l logger.Logger) *OrderHandler {
-	return &OrderHandler{service: s, logger: l}
+func NewOrderHandler(s *service.OrderService, f *money.Formatter, l logger.Logger) *OrderHandler {
+	return &OrderHandler{service: s, money: f, logger: l}
 }
 
 // Create godoc
 // @Summary Create a new order
+// @Description Reserves stock, charges the payment and confirms the order. A failed payment cancels the order.
+// @Description Payments the provider confirms later leave the order created with PendingPayment set until the provider's webhook arrives.
+// @Description Order options (gift wrap, gift message, priority handling) are charged with their configured fees as part of the total.
+// @Description A delivery slot chosen from GET /delivery-slots has one order of its capacity reserved until the order is cancelled.
+// @Description An order with the same products, quantities and total as an order the user placed within the last minutes is rejected
+// @Description with 409 possible_duplicate_order and the recent order in details, nothing is charged; resubmit with confirm_duplicate to place it.
 // @Tags orders
 // @Accept  json
 // @Produce  json
 // @Param   order  body      CreateOrderRequest  true  "Order details"
+// @Param   Idempotency-Key  header  string  false  "Unique key of the request; retries with the same key replay the first response"
 // @Security ApiKeyAuth
-// @Success 201  {object}  domain.Order
-// @Failure 400  {string}  string "Invalid request body or product not found"
-// @Failure 401  {string}  string "Unauthorized"
-// @Failure 500  {string}  string "Internal server error"
+// @Success 201  {object}  OrderResponse
+// @Failure 400  {object}  ErrorResponse "Invalid request body, product not found, quantity outside the allowed range, order option not offered or unknown payment provider"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 402  {object}  ErrorResponse "Payment failed"
+// @Failure 403  {object}  ErrorResponse "Buyer does not meet a product age restriction"
+// @Failure 409  {object}  ErrorResponse "Product not available, insufficient stock, delivery slot full, possible duplicate order or request with the same idempotency key in progress"
+// @Failure 422  {object}  ErrorResponse "Idempotency key was used with a different request"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
 // @Router /orders [post]
 func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 	const op = "OrderHandler.Create"
@@ -51,21 +159,14 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	var req CreateOrderRequest
 	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
-		customvalidator.HandleValidationError(w, err)
+		respondValidationError(w, r, err)
 		return
 	}
 
-	userIDStr, ok := r.Context().Value(UserIDKey).(string)
-	if !ok {
-		log.Error("failed to get user id from context", "op", op)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
-		return
-	}
-
-	userID, err := uuid.Parse(userIDStr)
+	userID, err := userIDFromContext(r)
 	if err != nil {
-		log.Error("failed to parse user id", "op", op, "error", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
 		return
 	}
 
@@ -77,23 +178,692 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	order, err := h.service.CreateOrder(r.Context(), userID, serviceItems)
+	options := service.OrderOptionsInput{
+		GiftWrap:         req.Options.GiftWrap,
+		GiftMessage:      req.Options.GiftMessage,
+		PriorityHandling: req.Options.PriorityHandling,
+	}
+	source := service.PaymentSource{Provider: req.PaymentProvider, Token: req.PaymentToken}
+	order, err := h.service.CreateOrder(r.Context(), userID, serviceItems, options, req.DeliverySlotID, req.ConfirmDuplicate, source)
 	if err != nil {
+		var duplicate *service.DuplicateOrderError
 		switch {
+		case errors.As(err, &duplicate):
+			log.Info("possible duplicate order rejected", "op", op, "user_id", userID, "duplicate_of", duplicate.Order.ID)
+			respondErrorDetails(w, r, http.StatusConflict, ErrCodePossibleDuplicateOrder,
+				"order repeats order "+duplicate.Order.Number+" placed shortly before, resubmit with confirm_duplicate to place it",
+				DuplicateOrderDetails{
+					OrderID:   duplicate.Order.ID,
+					Number:    duplicate.Order.Number,
+					Status:    duplicate.Order.Status,
+					CreatedAt: duplicate.Order.CreatedAt,
+				})
+		case errors.Is(err, service.ErrUnknownPaymentProvider):
+			respondError(w, r, http.StatusBadRequest, ErrCodeUnknownPaymentProvider, "unknown payment provider")
+		case errors.Is(err, service.ErrOrderOptionUnavailable), errors.Is(err, service.ErrGiftMessageTooLong):
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidOrderOption, err.Error())
 		case errors.Is(err, service.ErrProductNotFound):
-			http.Error(w, "one or more products not found", http.StatusBadRequest)
+			respondError(w, r, http.StatusBadRequest, ErrCodeProductNotFound, "one or more products not found")
+		case errors.Is(err, service.ErrQuantityLimit):
+			respondError(w, r, http.StatusBadRequest, ErrCodeQuantityLimit, "ordered quantity of one or more products is outside the allowed range")
+		case errors.Is(err, service.ErrProductUnavailable):
+			respondError(w, r, http.StatusConflict, ErrCodeProductUnavailable, "one or more products are not available for ordering")
 		case errors.Is(err, service.ErrInsufficientStock):
-			http.Error(w, "insufficient stock for one or more products", http.StatusConflict)
+			respondError(w, r, http.StatusConflict, ErrCodeInsufficientStock, "insufficient stock for one or more products")
+		case errors.Is(err, service.ErrDeliverySlotUnavailable):
+			respondError(w, r, http.StatusConflict, ErrCodeDeliverySlotUnavailable, "delivery slot is full or no longer available")
+		case errors.Is(err, service.ErrAgeRestricted):
+			respondError(w, r, http.StatusForbidden, ErrCodeAgeRestricted, "age_restricted: buyer does not meet the age restriction of one or more products")
+		case errors.Is(err, service.ErrPaymentFailed):
+			respondError(w, r, http.StatusPaymentRequired, ErrCodePaymentFailed, "payment failed, the order was cancelled")
 		default:
 			log.Error("failed to create order", "op", op, "error", err)
-			http.Error(w, "internal server error", http.StatusInternalServerError)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	h.writeOrder(w, r, order, http.StatusCreated)
+}
+
+// Get godoc
+// @Summary Get an order of the current user
+// @Description Returns the order with its payment ledger. Orders of other users are forbidden.
+// @Tags orders
+// @Produce  json
+// @Param   id  path  string  true  "Order ID"
+// @Security ApiKeyAuth
+// @Success 200  {object}  OrderResponse
+// @Failure 400  {object}  ErrorResponse "Invalid order ID"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 403  {object}  ErrorResponse "Order belongs to another user"
+// @Failure 404  {object}  ErrorResponse "Order not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /orders/{id} [get]
+func (h *OrderHandler) Get(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.Get"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	order, err := h.service.GetOrder(r.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeOrderNotFound, "order not found")
+			return
 		}
+		log.Error("failed to get order", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	if order.UserID != userID {
+		log.Warn("order of another user requested", "op", op, "order_id", orderID, "user_id", userID)
+		respondError(w, r, http.StatusForbidden, ErrCodeNotOrderOwner, "order belongs to another user")
+		return
+	}
+
+	h.writeOrder(w, r, order, http.StatusOK)
+}
+
+// Pay godoc
+// @Summary Pay an order of the current user
+// @Description Charges the outstanding amount of an order awaiting payment, e.g. after its pending payment was declined.
+// @Description A declined payment leaves the order awaiting payment. Payments the provider confirms later are accepted with 202,
+// @Description the order is paid once the provider's webhook arrives.
+// @Tags orders
+// @Accept  json
+// @Produce  json
+// @Param   id     path  string           true  "Order ID"
+// @Param   payment  body  PayOrderRequest  true  "Payment method"
+// @Param   Idempotency-Key  header  string  false  "Unique key of the request; retries with the same key replay the first response"
+// @Security ApiKeyAuth
+// @Success 200  {object}  OrderResponse
+// @Success 202  {object}  OrderResponse "Payment awaits confirmation by the provider"
+// @Failure 400  {object}  ErrorResponse "Invalid order ID, request body or unknown payment provider"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 402  {object}  ErrorResponse "Payment failed"
+// @Failure 403  {object}  ErrorResponse "Order belongs to another user"
+// @Failure 404  {object}  ErrorResponse "Order not found"
+// @Failure 409  {object}  ErrorResponse "Order does not await payment, a payment awaits confirmation or request with the same idempotency key in progress"
+// @Failure 422  {object}  ErrorResponse "Idempotency key was used with a different request"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /orders/{id}/pay [post]
+func (h *OrderHandler) Pay(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.Pay"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+
+	var req PayOrderRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
 		return
 	}
 
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	source := service.PaymentSource{Provider: req.PaymentProvider, Token: req.PaymentToken}
+	order, err := h.service.PayOrder(r.Context(), userID, orderID, source)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownPaymentProvider):
+			respondError(w, r, http.StatusBadRequest, ErrCodeUnknownPaymentProvider, "unknown payment provider")
+		case errors.Is(err, service.ErrOrderNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeOrderNotFound, "order not found")
+		case errors.Is(err, service.ErrNotOrderOwner):
+			log.Warn("payment of another user's order", "op", op, "order_id", orderID, "user_id", userID)
+			respondError(w, r, http.StatusForbidden, ErrCodeNotOrderOwner, "order belongs to another user")
+		case errors.Is(err, service.ErrPaymentPending):
+			respondError(w, r, http.StatusConflict, ErrCodePaymentPending, "a payment of the order awaits confirmation")
+		case errors.Is(err, domain.ErrInvalidPayment):
+			respondError(w, r, http.StatusConflict, ErrCodeInvalidPayment, "order does not await payment")
+		case errors.Is(err, service.ErrPaymentFailed):
+			respondError(w, r, http.StatusPaymentRequired, ErrCodePaymentFailed, "payment failed, the order still awaits payment")
+		default:
+			log.Error("failed to pay order", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if order.PendingPayment != "" {
+		status = http.StatusAccepted
+	}
+	h.writeOrder(w, r, order, status)
+}
+
+// List godoc
+// @Summary List orders of the current user
+// @Description Returns the user's orders with their items, newest first, with the number of the user's orders.
+// @Tags orders
+// @Produce  json
+// @Param   limit   query  int  false  "Maximum number of orders (1-100, default 20)"
+// @Param   offset  query  int  false  "Number of orders to skip"
+// @Security ApiKeyAuth
+// @Success 200  {object}  OrderListResponse
+// @Failure 400  {object}  ErrorResponse "Invalid limit or offset"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /orders [get]
+func (h *OrderHandler) List(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.List"
+	log := h.logger.WithTrace(r.Context())
+
+	query := r.URL.Query()
+	limit, err := parsePositiveInt(query.Get("limit"), defaultOrderListLimit)
+	if err != nil || limit > maxOrderListLimit {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid limit")
+		return
+	}
+	var offset int
+	if v := query.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid offset")
+			return
+		}
+	}
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	page, err := h.service.ListUserOrders(r.Context(), userID, offset, limit)
+	if err != nil {
+		log.Error("failed to list orders", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	resp := OrderListResponse{Orders: make([]OrderSummaryResponse, len(page.Orders)), Total: page.Total}
+	for i, order := range page.Orders {
+		f, err := h.money.In(order.Currency, order.Locale)
+		if err != nil {
+			log.Error("failed to format order total", "op", op, "order_id", order.ID, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+			return
+		}
+		resp.Orders[i] = OrderSummaryResponse{
+			ID:        order.ID,
+			Number:    order.Number,
+			Status:    order.Status,
+			CreatedAt: order.CreatedAt,
+			Items:     order.Items,
+			Total:     f.Money(order.TotalAmount),
+			Sandbox:   order.Sandbox,
+		}
+	}
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(order); err != nil {
-		log.Error("failed to encode order response", "op", op, "error", err)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("failed to encode orders", "op", op, "error", err)
+	}
+}
+
+// Order total check page size limits.
+const (
+	defaultTotalCheckLimit = 100
+	maxTotalCheckLimit     = 1000
+)
+
+// CheckTotals godoc
+// @Summary Report orders with inconsistent totals
+// @Description Lists orders whose total differs from the sum of item price × quantity.
+// @Tags admin
+// @Produce  json
+// @Param   limit  query  int  false  "Maximum number of orders (1-1000, default 100)"
+// @Param   X-API-Key  header  string  true  "Admin or analyst API key"
+// @Success 200  {object}  service.OrderTotalsReport
+// @Failure 400  {object}  ErrorResponse "Invalid limit"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/orders/total-mismatches [get]
+func (h *OrderHandler) CheckTotals(w http.ResponseWriter, r *http.Request) {
+	h.checkTotals(w, r, false)
+}
+
+// RepairTotals godoc
+// @Summary Repair orders with inconsistent totals
+// @Description Recomputes totals of orders whose total differs from the sum of item price × quantity.
+// @Tags admin
+// @Produce  json
+// @Param   limit  query  int  false  "Maximum number of orders (1-1000, default 100)"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  service.OrderTotalsReport
+// @Failure 400  {object}  ErrorResponse "Invalid limit"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/orders/total-mismatches/repair [post]
+func (h *OrderHandler) RepairTotals(w http.ResponseWriter, r *http.Request) {
+	h.checkTotals(w, r, true)
+}
+
+// checkTotals reports (and optionally repairs) orders with inconsistent totals.
+func (h *OrderHandler) checkTotals(w http.ResponseWriter, r *http.Request, repair bool) {
+	const op = "OrderHandler.checkTotals"
+	log := h.logger.WithTrace(r.Context())
+
+	limit, err := parsePositiveInt(r.URL.Query().Get("limit"), defaultTotalCheckLimit)
+	if err != nil || limit > maxTotalCheckLimit {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid limit")
+		return
+	}
+
+	report, err := h.service.CheckTotals(r.Context(), limit, repair)
+	if err != nil {
+		log.Error("failed to check order totals", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Error("failed to encode order totals report", "op", op, "error", err)
+	}
+}
+
+// ChangeStatus godoc
+// @Summary Change the status of an order
+// @Description Appends a paid, shipped, delivered or cancelled event to the order's event log.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   id  path  string  true  "Order ID"
+// @Param   status  body  ChangeOrderStatusRequest  true  "New status"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  OrderResponse
+// @Failure 400  {object}  ErrorResponse "Invalid request body or order ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Order not found"
+// @Failure 409  {object}  ErrorResponse "Status change not allowed or concurrent update"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/orders/{id}/status [post]
+func (h *OrderHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.ChangeStatus"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+
+	var req ChangeOrderStatusRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	order, err := h.service.ChangeStatus(r.Context(), orderID, orderStatusEvents[req.Status])
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeOrderNotFound, "order not found")
+		case errors.Is(err, domain.ErrInvalidOrderTransition):
+			respondError(w, r, http.StatusConflict, ErrCodeInvalidOrderTransition, err.Error())
+		case errors.Is(err, service.ErrOrderConflict):
+			respondError(w, r, http.StatusConflict, ErrCodeOrderConflict, "order was changed concurrently, retry")
+		default:
+			log.Error("failed to change order status", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	h.writeOrder(w, r, order, http.StatusOK)
+}
+
+// ResendConfirmation godoc
+// @Summary Resend the confirmation of an order
+// @Description Sends the order confirmation to the customer again through the channels the customer opted in to for order updates.
+// @Tags admin
+// @Param   id  path  string  true  "Order ID"
+// @Param   X-API-Key  header  string  true  "Admin or support API key"
+// @Success 204
+// @Failure 400  {object}  ErrorResponse "Invalid order ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Order not found"
+// @Failure 409  {object}  ErrorResponse "Order was not paid or was cancelled"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/orders/{id}/confirmation/resend [post]
+func (h *OrderHandler) ResendConfirmation(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.ResendConfirmation"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+
+	order, err := h.service.ResendConfirmation(r.Context(), orderID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeOrderNotFound, "order not found")
+		case errors.Is(err, service.ErrOrderNotConfirmed):
+			respondError(w, r, http.StatusConflict, ErrCodeOrderNotConfirmed, err.Error())
+		default:
+			log.Error("failed to resend order confirmation", "op", op, "order_id", orderID, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+	log.Info("order confirmation resent", "op", op, "order_id", orderID, "user_id", order.UserID, "actor", callerID(r.Context()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RecordPayment godoc
+// @Summary Record a payment of an order
+// @Description Adds a payment made outside checkout, e.g. a gift card or a deposit, to the order's payment ledger. The payment covering the rest of the total marks the order paid.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   id  path  string  true  "Order ID"
+// @Param   payment  body  RecordPaymentRequest  true  "Payment"
+// @Param   Idempotency-Key  header  string  false  "Unique key of the request; retries with the same key replay the first response"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 201  {object}  OrderResponse
+// @Failure 400  {object}  ErrorResponse "Invalid request body or order ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Order not found"
+// @Failure 409  {object}  ErrorResponse "Order does not accept the payment, concurrent update or request with the same idempotency key in progress"
+// @Failure 422  {object}  ErrorResponse "Idempotency key was used with a different request"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/orders/{id}/payments [post]
+func (h *OrderHandler) RecordPayment(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.RecordPayment"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+
+	var req RecordPaymentRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	order, err := h.service.RecordPayment(r.Context(), orderID, service.PaymentInput{
+		Method:    req.Method,
+		Reference: req.Reference,
+		Amount:    req.Amount,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeOrderNotFound, "order not found")
+		case errors.Is(err, domain.ErrInvalidPayment):
+			respondError(w, r, http.StatusConflict, ErrCodeInvalidPayment, err.Error())
+		case errors.Is(err, service.ErrOrderConflict):
+			respondError(w, r, http.StatusConflict, ErrCodeOrderConflict, "order was changed concurrently, retry")
+		default:
+			log.Error("failed to record payment", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+	log.Info("order payment recorded", "op", op, "order_id", orderID, "method", req.Method, "amount", req.Amount)
+
+	h.writeOrder(w, r, order, http.StatusCreated)
+}
+
+// Refund godoc
+// @Summary Refund part of a payment of an order
+// @Description Payments made through a payment provider are refunded through it, refunds of other payments are only recorded.
+// @Description Retries with the same Idempotency-Key refund once, even after the stored response expired.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   id  path  string  true  "Order ID"
+// @Param   refund  body  RefundRequest  true  "Refund"
+// @Param   Idempotency-Key  header  string  false  "Unique key of the request; retries with the same key replay the first response"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 201  {object}  OrderResponse
+// @Failure 400  {object}  ErrorResponse "Invalid request body or order ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Order or payment not found"
+// @Failure 409  {object}  ErrorResponse "Payment disputed, amount exceeds the part of the payment not refunded yet or request with the same idempotency key in progress"
+// @Failure 422  {object}  ErrorResponse "Idempotency key was used with a different request"
+// @Failure 502  {object}  ErrorResponse "Payment provider rejected the refund"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/orders/{id}/refunds [post]
+func (h *OrderHandler) Refund(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.Refund"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+
+	var req RefundRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	order, err := h.service.Refund(r.Context(), orderID, req.PaymentID, req.Amount, r.Header.Get("Idempotency-Key"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeOrderNotFound, "order not found")
+		case errors.Is(err, service.ErrPaymentNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodePaymentNotFound, "payment not found")
+		case errors.Is(err, domain.ErrInvalidRefund), errors.Is(err, domain.ErrPaymentDisputed):
+			respondError(w, r, http.StatusConflict, ErrCodeRefundNotAllowed, err.Error())
+		case errors.Is(err, service.ErrRefundFailed):
+			log.Warn("refund rejected by payment provider", "op", op, "order_id", orderID, "error", err)
+			respondError(w, r, http.StatusBadGateway, ErrCodeRefundFailed, "payment provider rejected the refund")
+		default:
+			log.Error("failed to refund payment", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+	log.Info("order payment refunded", "op", op, "order_id", orderID, "payment_id", req.PaymentID, "amount", req.Amount)
+
+	h.writeOrder(w, r, order, http.StatusCreated)
+}
+
+// NumberSettings godoc
+// @Summary Get the format of order numbers
+// @Tags admin
+// @Produce  json
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  domain.OrderNumberSettings
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/settings/order-numbers [get]
+func (h *OrderHandler) NumberSettings(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.NumberSettings"
+	log := h.logger.WithTrace(r.Context())
+
+	settings, err := h.service.NumberSettings(r.Context())
+	if err != nil {
+		log.Error("failed to get order number settings", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(settings); err != nil {
+		log.Error("failed to encode order number settings", "op", op, "error", err)
+	}
+}
+
+// ConfigureNumbers godoc
+// @Summary Change the format of order numbers
+// @Description Applies to orders created afterwards. Existing orders keep their numbers and the yearly sequence continues.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   settings  body  OrderNumberSettingsRequest  true  "Order number format"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  domain.OrderNumberSettings
+// @Failure 400  {object}  ErrorResponse "Invalid request body or format"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/settings/order-numbers [put]
+func (h *OrderHandler) ConfigureNumbers(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.ConfigureNumbers"
+	log := h.logger.WithTrace(r.Context())
+
+	var req OrderNumberSettingsRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	settings := &domain.OrderNumberSettings{Prefix: req.Prefix, Padding: req.Padding}
+	if err := h.service.ConfigureNumbers(r.Context(), settings); err != nil {
+		if errors.Is(err, domain.ErrInvalidOrderNumberSettings) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidOrderNumberSettings, err.Error())
+			return
+		}
+		log.Error("failed to configure order numbers", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	log.Info("order number format changed", "op", op, "prefix", settings.Prefix, "padding", settings.Padding)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(settings); err != nil {
+		log.Error("failed to encode order number settings", "op", op, "error", err)
+	}
+}
+
+// Events godoc
+// @Summary Get the event log of an order
+// @Tags admin
+// @Produce  json
+// @Param   id  path  string  true  "Order ID"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {array}   domain.OrderEvent
+// @Failure 400  {object}  ErrorResponse "Invalid order ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Order not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/orders/{id}/events [get]
+func (h *OrderHandler) Events(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.Events"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+
+	events, err := h.service.Events(r.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeOrderNotFound, "order not found")
+			return
+		}
+		log.Error("failed to get order events", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(events); err != nil {
+		log.Error("failed to encode order events", "op", op, "error", err)
+	}
+}
+
+// StateAt godoc
+// @Summary Get an order as it was at a point in time
+// @Description Rebuilds the order from its event log up to the given time.
+// @Tags admin
+// @Produce  json
+// @Param   id  path  string  true  "Order ID"
+// @Param   at  query  string  false  "RFC 3339 time (default: now)"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  OrderResponse
+// @Failure 400  {object}  ErrorResponse "Invalid order ID or time"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Order did not exist at that time"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/orders/{id}/state [get]
+func (h *OrderHandler) StateAt(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.StateAt"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+
+	at := time.Now()
+	if v := r.URL.Query().Get("at"); v != "" {
+		if at, err = time.Parse(time.RFC3339, v); err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid time, expected RFC 3339")
+			return
+		}
+	}
+
+	order, err := h.service.StateAt(r.Context(), orderID, at)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeOrderNotFound, "order not found")
+			return
+		}
+		log.Error("failed to rebuild order", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	h.writeOrder(w, r, order, http.StatusOK)
+}
+
+// writeOrder writes the order with its amounts formatted in the currency and locale it was placed with as JSON.
+func (h *OrderHandler) writeOrder(w http.ResponseWriter, r *http.Request, order *domain.Order, status int) {
+	f, err := h.money.In(order.Currency, order.Locale)
+	if err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to format order amounts", "order_id", order.ID, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	resp := OrderResponse{
+		Order:         *order,
+		Total:         f.Money(order.TotalAmount),
+		Paid:          f.Money(order.PaidAmount()),
+		Refunded:      f.Money(order.RefundedAmount()),
+		DisputeStatus: order.DisputeStatus(),
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to encode order response", "error", err)
 	}
 }
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	"strconv"
+)
+
+// orderExportRetryAfter is the number of seconds clients should wait before asking for an export again
+// while it is generated in the background.
+const orderExportRetryAfter = 30
+
+// OrderExportHandler serves customers' exports of their own order history.
+type OrderExportHandler struct {
+	service *service.OrderExportService
+	logger  logger.Logger
+}
+
+// NewOrderExportHandler creates a new order export handler.
+func NewOrderExportHandler(s *service.OrderExportService, l logger.Logger) *OrderExportHandler {
+	return &OrderExportHandler{service: s, logger: l}
+}
+
+// Export godoc
+// @Summary Export the order history of the current user
+// @Description Returns all of the user's orders with their items, newest first, as CSV (one row per item) or JSON.
+// @Description Exports are generated at most once per ORDER_EXPORT_INTERVAL, repeated requests get the last export.
+// @Description Exports of users with many orders are generated in the background: the request is accepted with 202
+// @Description and Retry-After until the export is ready. Ready exports redirect to a short-lived download URL,
+// @Description storage backends without presigned URLs serve the export directly.
+// @Tags users
+// @Produce  plain
+// @Param   format  query  string  false  "csv (default) or json"
+// @Security ApiKeyAuth
+// @Success 200  {string}  string "Order export"
+// @Success 202  {object}  ErrorResponse "Export is being generated, retry after the Retry-After seconds"
+// @Success 302  {string}  string "Redirect to the export"
+// @Failure 400  {object}  ErrorResponse "Invalid format"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/orders/export [get]
+func (h *OrderExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderExportHandler.Export"
+	log := h.logger.WithTrace(r.Context())
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = service.OrderExportCSV
+	}
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	export, err := h.service.Export(r.Context(), userID, format)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidExportFormat):
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidExportFormat, err.Error())
+		case errors.Is(err, service.ErrOrderExportPending):
+			w.Header().Set("Retry-After", strconv.Itoa(orderExportRetryAfter))
+			respondError(w, r, http.StatusAccepted, ErrCodeOrderExportPending, "order export is being generated, retry later")
+		default:
+			log.Error("failed to export orders", "op", op, "user_id", userID, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, no-store")
+	if export.URL != "" {
+		http.Redirect(w, r, export.URL, http.StatusFound)
+		return
+	}
+
+	defer export.Object.Body.Close()
+	w.Header().Set("Content-Type", export.Object.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(export.Object.Size, 10))
+	w.Header().Set("Last-Modified", export.GeneratedAt.UTC().Format(http.TimeFormat))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "orders." + format}))
+	if _, err := io.Copy(w, export.Object.Body); err != nil {
+		log.Error("failed to write order export", "op", op, "error", err)
+	}
+}
@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/service"
+	customvalidator "product-api/pkg/validator"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Pending refund requests page size limits.
+const (
+	defaultRefundRequestLimit = 100
+	maxRefundRequestLimit     = 1000
+)
+
+// RefundItemRequest contains an order line returned with a refund.
+type RefundItemRequest struct {
+	ItemID   uuid.UUID `json:"item_id" validate:"required"`
+	Quantity int       `json:"quantity" validate:"required,gt=0"`
+}
+
+// CreateRefundRequestRequest contains a refund requested by support.
+type CreateRefundRequestRequest struct {
+	PaymentID uuid.UUID           `json:"payment_id" validate:"required"`
+	Amount    float64             `json:"amount" example:"5.00" validate:"required,gt=0"`
+	Items     []RefundItemRequest `json:"items" validate:"max=100,dive"` // Returned order lines of shipped or delivered orders, restocked on approval
+	Reason    string              `json:"reason" example:"Damaged in transit" validate:"required,max=1000"`
+}
+
+// RefundDecisionRequest contains the comment of a refund approval or rejection.
+type RefundDecisionRequest struct {
+	Note string `json:"note" example:"Return received" validate:"max=1000"` // Required for rejections
+}
+
+// RequestRefund godoc
+// @Summary Request a refund of an order payment
+// @Description Records a refund request to be approved or rejected by finance. Nothing is refunded until approval.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   id  path  string  true  "Order ID"
+// @Param   request  body  CreateRefundRequestRequest  true  "Refund request"
+// @Param   X-API-Key  header  string  true  "Admin or support API key"
+// @Success 201  {object}  domain.RefundRequest
+// @Failure 400  {object}  ErrorResponse "Invalid request body or order ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Order not found"
+// @Failure 409  {object}  ErrorResponse "Payment, amount or returned items do not match the order, or items of an unshipped order"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/orders/{id}/refund-requests [post]
+func (h *OrderHandler) RequestRefund(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.RequestRefund"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+
+	var req CreateRefundRequestRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	input := service.RefundRequestInput{PaymentID: req.PaymentID, Amount: req.Amount, Reason: req.Reason}
+	for _, item := range req.Items {
+		input.Items = append(input.Items, domain.RefundItem{ItemID: item.ItemID, Quantity: item.Quantity})
+	}
+	request, err := h.service.RequestRefund(r.Context(), callerID(r.Context()), orderID, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeOrderNotFound, "order not found")
+		case errors.Is(err, domain.ErrInvalidRefundRequest):
+			respondError(w, r, http.StatusConflict, ErrCodeInvalidRefundRequest, err.Error())
+		default:
+			log.Error("failed to request refund", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+	log.Info("refund requested", "op", op, "order_id", orderID, "request_id", request.ID, "amount", request.Amount)
+
+	h.writeRefundRequest(w, r, request, http.StatusCreated)
+}
+
+// ApproveRefund godoc
+// @Summary Approve a refund request
+// @Description Refunds the payment through its payment provider and returns the returned items to stock.
+// @Description If the refund fails, the failure is audited and the request stays pending.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   id  path  string  true  "Refund request ID"
+// @Param   decision  body  RefundDecisionRequest  false  "Approval comment"
+// @Param   X-API-Key  header  string  true  "Admin or finance API key"
+// @Success 200  {object}  domain.RefundRequest
+// @Failure 400  {object}  ErrorResponse "Invalid request body or request ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 403  {object}  ErrorResponse "Request cannot be approved by its requester"
+// @Failure 404  {object}  ErrorResponse "Refund request not found"
+// @Failure 409  {object}  ErrorResponse "Request already decided, payment disputed or amount exceeds the part of the payment not refunded yet"
+// @Failure 502  {object}  ErrorResponse "Payment provider rejected the refund"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/refund-requests/{id}/approve [post]
+func (h *OrderHandler) ApproveRefund(w http.ResponseWriter, r *http.Request) {
+	h.decideRefund(w, r, domain.RefundRequestApproved)
+}
+
+// RejectRefund godoc
+// @Summary Reject a refund request
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   id  path  string  true  "Refund request ID"
+// @Param   decision  body  RefundDecisionRequest  true  "Rejection reason"
+// @Param   X-API-Key  header  string  true  "Admin or finance API key"
+// @Success 200  {object}  domain.RefundRequest
+// @Failure 400  {object}  ErrorResponse "Invalid request body, request ID or missing note"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 403  {object}  ErrorResponse "Request cannot be rejected by its requester"
+// @Failure 404  {object}  ErrorResponse "Refund request not found"
+// @Failure 409  {object}  ErrorResponse "Request already decided"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/refund-requests/{id}/reject [post]
+func (h *OrderHandler) RejectRefund(w http.ResponseWriter, r *http.Request) {
+	h.decideRefund(w, r, domain.RefundRequestRejected)
+}
+
+// decideRefund approves or rejects the refund request on behalf of the caller.
+func (h *OrderHandler) decideRefund(w http.ResponseWriter, r *http.Request, decision string) {
+	const op = "OrderHandler.decideRefund"
+	log := h.logger.WithTrace(r.Context())
+
+	requestID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid refund request ID")
+		return
+	}
+
+	var req RefundDecisionRequest
+	if r.ContentLength != 0 {
+		if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+			respondValidationError(w, r, err)
+			return
+		}
+	}
+
+	actor := callerID(r.Context())
+	var request *domain.RefundRequest
+	if decision == domain.RefundRequestApproved {
+		request, err = h.service.ApproveRefund(r.Context(), actor, requestID, req.Note)
+	} else {
+		if req.Note == "" {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "note is required to reject a refund request")
+			return
+		}
+		request, err = h.service.RejectRefund(r.Context(), actor, requestID, req.Note)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRefundRequestNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeRefundRequestNotFound, "refund request not found")
+		case errors.Is(err, domain.ErrSelfApproval):
+			respondError(w, r, http.StatusForbidden, ErrCodeSelfApproval, err.Error())
+		case errors.Is(err, domain.ErrRefundRequestDecided), errors.Is(err, domain.ErrInvalidRefund),
+			errors.Is(err, domain.ErrPaymentDisputed):
+			respondError(w, r, http.StatusConflict, ErrCodePaymentDisputed, err.Error())
+		case errors.Is(err, service.ErrRefundFailed):
+			log.Warn("refund rejected by payment provider", "op", op, "request_id", requestID, "error", err)
+			respondError(w, r, http.StatusBadGateway, ErrCodeRefundFailed, "payment provider rejected the refund")
+		default:
+			log.Error("failed to decide refund request", "op", op, "decision", decision, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+	log.Info("refund request decided", "op", op, "request_id", requestID, "order_id", request.OrderID,
+		"status", request.Status, "actor", actor)
+
+	h.writeRefundRequest(w, r, request, http.StatusOK)
+}
+
+// GetRefundRequest godoc
+// @Summary Get a refund request with its audit trail
+// @Tags admin
+// @Produce  json
+// @Param   id  path  string  true  "Refund request ID"
+// @Param   X-API-Key  header  string  true  "Admin, support or finance API key"
+// @Success 200  {object}  domain.RefundRequest
+// @Failure 400  {object}  ErrorResponse "Invalid request ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Refund request not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/refund-requests/{id} [get]
+func (h *OrderHandler) GetRefundRequest(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.GetRefundRequest"
+	log := h.logger.WithTrace(r.Context())
+
+	requestID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid refund request ID")
+		return
+	}
+
+	request, err := h.service.RefundRequest(r.Context(), requestID)
+	if err != nil {
+		if errors.Is(err, service.ErrRefundRequestNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeRefundRequestNotFound, "refund request not found")
+			return
+		}
+		log.Error("failed to get refund request", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	h.writeRefundRequest(w, r, request, http.StatusOK)
+}
+
+// RefundRequests godoc
+// @Summary List refund requests of an order with their audit trails
+// @Tags admin
+// @Produce  json
+// @Param   id  path  string  true  "Order ID"
+// @Param   X-API-Key  header  string  true  "Admin, support or finance API key"
+// @Success 200  {array}   domain.RefundRequest
+// @Failure 400  {object}  ErrorResponse "Invalid order ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/orders/{id}/refund-requests [get]
+func (h *OrderHandler) RefundRequests(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.RefundRequests"
+	log := h.logger.WithTrace(r.Context())
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid order ID")
+		return
+	}
+
+	requests, err := h.service.RefundRequests(r.Context(), orderID)
+	if err != nil {
+		log.Error("failed to list refund requests", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	h.writeRefundRequests(w, r, requests)
+}
+
+// PendingRefundRequests godoc
+// @Summary List refund requests waiting for a decision
+// @Tags admin
+// @Produce  json
+// @Param   limit  query  int  false  "Maximum number of requests (1-1000, default 100)"
+// @Param   X-API-Key  header  string  true  "Admin, support or finance API key"
+// @Success 200  {array}   domain.RefundRequest
+// @Failure 400  {object}  ErrorResponse "Invalid limit"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/refund-requests [get]
+func (h *OrderHandler) PendingRefundRequests(w http.ResponseWriter, r *http.Request) {
+	const op = "OrderHandler.PendingRefundRequests"
+	log := h.logger.WithTrace(r.Context())
+
+	limit, err := parsePositiveInt(r.URL.Query().Get("limit"), defaultRefundRequestLimit)
+	if err != nil || limit > maxRefundRequestLimit {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid limit")
+		return
+	}
+
+	requests, err := h.service.PendingRefundRequests(r.Context(), limit)
+	if err != nil {
+		log.Error("failed to list pending refund requests", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	h.writeRefundRequests(w, r, requests)
+}
+
+func (h *OrderHandler) writeRefundRequest(w http.ResponseWriter, r *http.Request, request *domain.RefundRequest, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(request); err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to encode refund request", "error", err)
+	}
+}
+
+func (h *OrderHandler) writeRefundRequests(w http.ResponseWriter, r *http.Request, requests []domain.RefundRequest) {
+	if requests == nil {
+		requests = []domain.RefundRequest{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(requests); err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to encode refund requests", "error", err)
+	}
+}
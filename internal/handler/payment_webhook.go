@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxWebhookBodySize limits payment provider webhook bodies.
+const maxWebhookBodySize = 1 << 20
+
+// PaymentWebhookHandler handles notifications sent by payment providers.
+type PaymentWebhookHandler struct {
+	service *service.DisputeService
+	logger  logger.Logger
+}
+
+// NewPaymentWebhookHandler creates a new payment webhook handler.
+func NewPaymentWebhookHandler(s *service.DisputeService, l logger.Logger) *PaymentWebhookHandler {
+	return &PaymentWebhookHandler{service: s, logger: l}
+}
+
+// Handle godoc
+// @Summary Receive a payment provider webhook
+// @Description Authenticated by the provider's signature headers. Disputes and chargebacks flag the order,
+// @Description freeze refunds of the disputed payment and notify admins. Capture confirmations pay orders awaiting a pending
+// @Description payment, or let them be paid again if it was declined. Other events are acknowledged and ignored.
+// @Tags webhooks
+// @Accept  json
+// @Param   provider  path  string  true  "Payment provider, e.g. paypal"
+// @Success 204
+// @Failure 401  {object}  ErrorResponse "Invalid webhook signature"
+// @Failure 404  {object}  ErrorResponse "Unknown payment provider"
+// @Failure 413  {object}  ErrorResponse "Body too large"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /webhooks/payments/{provider} [post]
+func (h *PaymentWebhookHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	const op = "PaymentWebhookHandler.Handle"
+	log := h.logger.WithTrace(r.Context())
+	provider := chi.URLParam(r, "provider")
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize+1))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "could not read body")
+		return
+	}
+	if len(body) > maxWebhookBodySize {
+		respondError(w, r, http.StatusRequestEntityTooLarge, ErrCodePayloadTooLarge, "body too large")
+		return
+	}
+
+	if err := h.service.HandleWebhook(r.Context(), provider, r.Header, body); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownPaymentProvider):
+			respondError(w, r, http.StatusNotFound, ErrCodeUnknownPaymentProvider, "unknown payment provider")
+		case errors.Is(err, service.ErrInvalidWebhook):
+			log.Warn("payment webhook with invalid signature", "op", op, "provider", provider)
+			respondError(w, r, http.StatusUnauthorized, ErrCodeInvalidWebhook, "invalid webhook signature")
+		default:
+			log.Error("failed to handle payment webhook", "op", op, "provider", provider, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	customvalidator "product-api/pkg/validator"
+	"time"
+)
+
+// SetPhoneRequest contains the phone number to receive SMS notifications on.
+type SetPhoneRequest struct {
+	Phone string `json:"phone" example:"+4915123456789" validate:"required,max=32"` // International format, separators are ignored
+}
+
+// VerifyPhoneRequest contains the code sent to the phone number.
+type VerifyPhoneRequest struct {
+	Code string `json:"code" example:"123456" validate:"required,len=6,numeric"`
+}
+
+// PhoneVerificationResponse describes a sent verification code.
+type PhoneVerificationResponse struct {
+	Phone     string `example:"+4915123456789"`
+	ExpiresAt time.Time
+}
+
+// PhoneResponse is the verified phone number of the user.
+type PhoneResponse struct {
+	Phone      string `example:"+4915123456789"`
+	VerifiedAt *time.Time
+}
+
+// PhoneHandler handles HTTP requests related to phone numbers of users.
+type PhoneHandler struct {
+	service *service.PhoneService
+	logger  logger.Logger
+}
+
+// NewPhoneHandler creates a new phone handler.
+func NewPhoneHandler(s *service.PhoneService, l logger.Logger) *PhoneHandler {
+	return &PhoneHandler{service: s, logger: l}
+}
+
+// Set godoc
+// @Summary Set the phone number of the current user
+// @Description Sends a verification code by SMS. The number receives notifications once verified,
+// @Description the current number is kept until then. Opt in to SMS in the notification preferences.
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param   phone  body  SetPhoneRequest  true  "Phone number"
+// @Security ApiKeyAuth
+// @Success 202  {object}  PhoneVerificationResponse
+// @Failure 400  {object}  ErrorResponse "Invalid phone number or unsupported country"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 429  {object}  ErrorResponse "A code was sent less than a minute ago"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/phone [put]
+func (h *PhoneHandler) Set(w http.ResponseWriter, r *http.Request) {
+	const op = "PhoneHandler.Set"
+	log := h.logger.WithTrace(r.Context())
+
+	var req SetPhoneRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	verification, err := h.service.RequestVerification(r.Context(), userID, req.Phone)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPhoneInvalid), errors.Is(err, service.ErrPhoneCountryUnsupported):
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidPhone, err.Error())
+		case errors.Is(err, service.ErrPhoneCodeTooSoon):
+			respondError(w, r, http.StatusTooManyRequests, ErrCodePhoneCodeTooSoon, err.Error())
+		default:
+			log.Error("failed to send phone verification code", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	resp := PhoneVerificationResponse{Phone: verification.Phone, ExpiresAt: verification.ExpiresAt}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("failed to encode phone verification response", "op", op, "error", err)
+	}
+}
+
+// Verify godoc
+// @Summary Verify the phone number of the current user
+// @Description Checks the code sent by SMS and stores the number. Codes expire after 10 minutes or 5 wrong attempts.
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param   code  body  VerifyPhoneRequest  true  "Verification code"
+// @Security ApiKeyAuth
+// @Success 200  {object}  PhoneResponse
+// @Failure 400  {object}  ErrorResponse "Invalid request body or wrong code"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 404  {object}  ErrorResponse "No pending verification"
+// @Failure 410  {object}  ErrorResponse "Code expired"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/phone/verify [post]
+func (h *PhoneHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	const op = "PhoneHandler.Verify"
+	log := h.logger.WithTrace(r.Context())
+
+	var req VerifyPhoneRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	user, err := h.service.Verify(r.Context(), userID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPhoneCodeInvalid):
+			respondError(w, r, http.StatusBadRequest, ErrCodePhoneCodeInvalid, err.Error())
+		case errors.Is(err, service.ErrPhoneVerificationNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodePhoneVerificationNotFound, err.Error())
+		case errors.Is(err, service.ErrPhoneCodeExpired):
+			respondError(w, r, http.StatusGone, ErrCodePhoneCodeExpired, err.Error())
+		default:
+			log.Error("failed to verify phone number", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(PhoneResponse{Phone: user.Phone, VerifiedAt: user.PhoneVerifiedAt}); err != nil {
+		log.Error("failed to encode phone response", "op", op, "error", err)
+	}
+}
+
+// Delete godoc
+// @Summary Remove the phone number of the current user
+// @Description The user receives no more SMS notifications. A pending verification is cancelled.
+// @Tags users
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/phone [delete]
+func (h *PhoneHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	const op = "PhoneHandler.Delete"
+	log := h.logger.WithTrace(r.Context())
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	if err := h.service.Remove(r.Context(), userID); err != nil {
+		log.Error("failed to remove phone number", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
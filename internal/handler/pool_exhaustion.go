@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"product-api/internal/repository"
+	"strconv"
+	"time"
+)
+
+// PoolExhaustionMiddleware creates middleware responding 503 Service Unavailable with Retry-After, instead of
+// 500 Internal Server Error, to requests that failed because no database connection was available in time
+// (see repository.ErrPoolExhausted), so clients back off and retry instead of reporting a server error.
+// Must be placed after middlewares capturing responses, so they see the 503.
+func PoolExhaustionMiddleware(retryAfter time.Duration) func(http.Handler) http.Handler {
+	seconds := strconv.Itoa(max(int(retryAfter.Round(time.Second).Seconds()), 1))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(repository.WithPoolExhaustion(r.Context()))
+			next.ServeHTTP(&poolExhaustionWriter{ResponseWriter: w, r: r, retryAfter: seconds}, r)
+		})
+	}
+}
+
+// poolExhaustionWriter replaces 500 responses of requests that failed to acquire a database connection.
+type poolExhaustionWriter struct {
+	http.ResponseWriter
+	r          *http.Request
+	retryAfter string
+	replaced   bool // The response was replaced, the handler's body is discarded
+}
+
+func (w *poolExhaustionWriter) WriteHeader(status int) {
+	if status != http.StatusInternalServerError || !repository.PoolExhausted(w.r.Context()) {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.replaced = true
+	w.Header().Set("Retry-After", w.retryAfter)
+	respondError(w.ResponseWriter, w.r, http.StatusServiceUnavailable, ErrCodeUnavailable, "service temporarily unavailable, retry later")
+}
+
+func (w *poolExhaustionWriter) Write(b []byte) (int, error) {
+	if w.replaced {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *poolExhaustionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,60 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/handler"
+	"product-api/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingHandler responds with the status and a body, after failing to acquire a connection if exhausted.
+func failingHandler(status int, exhausted bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if exhausted {
+			repository.RecordPoolExhaustion(r.Context())
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("handler body"))
+	})
+}
+
+func TestPoolExhaustionMiddleware_RespondsUnavailable(t *testing.T) {
+	h := handler.PoolExhaustionMiddleware(3 * time.Second)(failingHandler(http.StatusInternalServerError, true))
+	rec, resp := serveError[any](t, h, httptest.NewRequest(http.MethodGet, "/products", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "3", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "service_unavailable", resp.Code)
+	assert.NotContains(t, rec.Body.String(), "handler body", "the body of the 500 is discarded")
+}
+
+func TestPoolExhaustionMiddleware_RoundsRetryAfter(t *testing.T) {
+	for retryAfter, want := range map[time.Duration]string{
+		1500 * time.Millisecond: "2",
+		100 * time.Millisecond:  "1", // Clients would retry immediately on 0
+	} {
+		h := handler.PoolExhaustionMiddleware(retryAfter)(failingHandler(http.StatusInternalServerError, true))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/products", nil))
+		assert.Equal(t, want, rec.Header().Get("Retry-After"), retryAfter)
+	}
+}
+
+func TestPoolExhaustionMiddleware_KeepsOtherResponses(t *testing.T) {
+	for name, h := range map[string]http.Handler{
+		"other server error":      failingHandler(http.StatusInternalServerError, false),
+		"served after exhaustion": failingHandler(http.StatusOK, true), // e.g. from a replica after the primary failed
+	} {
+		rec := httptest.NewRecorder()
+		handler.PoolExhaustionMiddleware(time.Second)(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/products", nil))
+
+		assert.NotEqual(t, http.StatusServiceUnavailable, rec.Code, name)
+		assert.Empty(t, rec.Header().Get("Retry-After"), name)
+		assert.Equal(t, "handler body", rec.Body.String(), name)
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+)
+
+// PreferenceHandler handles HTTP requests related to user notification preferences.
+type PreferenceHandler struct {
+	service *service.PreferenceService
+	logger  logger.Logger
+}
+
+// NewPreferenceHandler creates a new notification preference handler.
+func NewPreferenceHandler(s *service.PreferenceService, l logger.Logger) *PreferenceHandler {
+	return &PreferenceHandler{service: s, logger: l}
+}
+
+// Get godoc
+// @Summary Get notification preferences of the current user
+// @Description Returns per-channel opt-ins for each notification category (category -> channel -> enabled).
+// @Tags users
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200  {object}  map[string]map[string]bool
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/preferences [get]
+func (h *PreferenceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	const op = "PreferenceHandler.Get"
+	log := h.logger.WithTrace(r.Context())
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	prefs, err := h.service.Get(r.Context(), userID)
+	if err != nil {
+		log.Error("failed to get notification preferences", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(prefs); err != nil {
+		log.Error("failed to encode preferences response", "op", op, "error", err)
+	}
+}
+
+// Update godoc
+// @Summary Update notification preferences of the current user
+// @Description Accepts a partial map (category -> channel -> enabled); omitted entries keep their current values.
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param   preferences  body      map[string]map[string]bool  true  "Notification preferences"
+// @Security ApiKeyAuth
+// @Success 200  {object}  map[string]map[string]bool
+// @Failure 400  {object}  ErrorResponse "Invalid request body or unknown category/channel"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/preferences [put]
+func (h *PreferenceHandler) Update(w http.ResponseWriter, r *http.Request) {
+	const op = "PreferenceHandler.Update"
+	log := h.logger.WithTrace(r.Context())
+
+	var req domain.NotificationPreferences
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	prefs, err := h.service.Update(r.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPreference) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidPreference, err.Error())
+			return
+		}
+		log.Error("failed to update notification preferences", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(prefs); err != nil {
+		log.Error("failed to encode preferences response", "op", op, "error", err)
+	}
+}
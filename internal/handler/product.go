@@ -3,10 +3,16 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
+	"product-api/internal/domain"
 	"product-api/internal/logger"
 	"product-api/internal/service"
 	customvalidator "product-api/pkg/validator"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -18,6 +24,93 @@ type CreateProductRequest struct {
 	Tags        []string `json:"tags" example:"audio,electronics,wireless" validate:"required"`
 	Quantity    int      `json:"quantity" example:"100" validate:"required,gt=0"`
 	Price       float64  `json:"price" example:"99.99" validate:"required,gt=0"`
+
+	AgeRestriction int    `json:"age_restriction" example:"18" validate:"gte=0,lte=99"`                // Minimum buyer age, 0 if not restricted
+	Status         string `json:"status" example:"active" validate:"omitempty,oneof=draft active"`     // Drafts are invisible to the storefront, defaults to active
+	Barcode        string `json:"barcode" example:"4006381333931" validate:"omitempty,numeric,max=14"` // EAN-8, UPC-A, EAN-13 or GTIN-14
+
+	Category   string         `json:"category" example:"headphones" validate:"max=100"`
+	Attributes map[string]any `json:"attributes" swaggertype:"object"` // Values typed by the attribute definitions of the category
+}
+
+// CreateBundleRequest contains data for creating a bundle of existing products.
+type CreateBundleRequest struct {
+	Description    string                   `json:"description" example:"Home cinema set" validate:"required"`
+	Tags           []string                 `json:"tags" example:"audio,bundle"`
+	Price          float64                  `json:"price" example:"499.99" validate:"required,gt=0"` // Price of the whole bundle
+	AgeRestriction int                      `json:"age_restriction" example:"0" validate:"gte=0,lte=99"`
+	Status         string                   `json:"status" example:"draft" validate:"omitempty,oneof=draft active"`
+	Barcode        string                   `json:"barcode" example:"4006381333931" validate:"omitempty,numeric,max=14"`
+	Category       string                   `json:"category" example:"home-cinema" validate:"max=100"`
+	Attributes     map[string]any           `json:"attributes" swaggertype:"object"`
+	Components     []BundleComponentRequest `json:"components" validate:"required,min=1,max=50,dive"`
+}
+
+// BundleComponentRequest is a product contained in a bundle.
+type BundleComponentRequest struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+	Quantity  int       `json:"quantity" example:"2" validate:"required,gt=0"` // Number of items in one bundle
+}
+
+// ChangeProductStatusRequest contains the new lifecycle status of a product.
+type ChangeProductStatusRequest struct {
+	Status string `json:"status" example:"archived" validate:"required,oneof=draft active archived"`
+}
+
+// BulkUpdateProductsRequest contains changes of many products applied in one transaction.
+type BulkUpdateProductsRequest struct {
+	Items  []ProductChangeRequest `json:"items" validate:"required,min=1,max=1000,dive"`
+	Atomic bool                   `json:"atomic" example:"false"` // Apply nothing if any item fails
+}
+
+// ProductChangeRequest is a partial update of a single product. Omitted fields are left unchanged.
+type ProductChangeRequest struct {
+	ID             uuid.UUID `json:"id" validate:"required"`
+	Description    *string   `json:"description,omitempty" example:"Wireless headphones, 2024 edition"`
+	Price          *float64  `json:"price,omitempty" example:"89.99"`
+	AgeRestriction *int      `json:"age_restriction,omitempty" example:"0"`
+	AddTags        []string  `json:"add_tags,omitempty" example:"sale"`
+	RemoveTags     []string  `json:"remove_tags,omitempty" example:"new"`
+	Status         *string   `json:"status,omitempty" example:"active" validate:"omitempty,oneof=draft active archived"`
+	Barcode        *string   `json:"barcode,omitempty" example:"4006381333931"`                            // Empty string removes the barcode
+	Category       *string   `json:"category,omitempty" example:"headphones" validate:"omitempty,max=100"` // Empty string removes the category
+
+	Attributes map[string]any `json:"attributes,omitempty" swaggertype:"object"` // Attributes to set, null values remove them
+}
+
+// Product listing page size limits.
+const (
+	defaultProductListLimit = 20
+	maxProductListLimit     = 100
+)
+
+// Product history page size limits.
+const (
+	defaultProductHistoryLimit = 50
+	maxProductHistoryLimit     = 500
+)
+
+// Catalog issue page size limits.
+const (
+	defaultCatalogIssueLimit = 100
+	maxCatalogIssueLimit     = 1000
+)
+
+// Draft cleanup defaults and limits.
+const (
+	defaultDraftCleanupAge   = 30 * 24 * time.Hour
+	defaultDraftCleanupLimit = 100
+	maxDraftCleanupLimit     = 1000
+)
+
+// maxProductImportSize is the maximum size of a product import request, files of 100 000 rows with
+// descriptions and attributes fit in it.
+const maxProductImportSize = 64 << 20
+
+// DraftCleanupResponse lists the deleted drafts.
+type DraftCleanupResponse struct {
+	Deleted []uuid.UUID
+	Count   int
 }
 
 // ProductHandler handles HTTP requests related to products.
@@ -33,15 +126,20 @@ func NewProductHandler(s *service.ProductService, l logger.Logger) *ProductHandl
 
 // Create godoc
 // @Summary Create a new product
+// @Description Requires the admin or manager role.
 // @Tags products
 // @Accept  json
 // @Produce  json
 // @Param   product  body      CreateProductRequest  true  "Product details"
+// @Param   Idempotency-Key  header  string  false  "Unique key of the request; retries with the same key replay the first response"
 // @Security ApiKeyAuth
 // @Success 201  {object}  domain.Product
-// @Failure 400  {string}  string "Invalid request body"
-// @Failure 401  {string}  string "Unauthorized"
-// @Failure 500  {string}  string "Internal server error"
+// @Failure 400  {object}  ErrorResponse "Invalid request body, barcode or attributes"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 403  {object}  ErrorResponse "Insufficient role"
+// @Failure 409  {object}  ErrorResponse "Barcode already taken or request with the same idempotency key in progress"
+// @Failure 422  {object}  ErrorResponse "Idempotency key was used with a different request"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
 // @Router /products [post]
 func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	const op = "ProductHandler.Create"
@@ -49,14 +147,33 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	var req CreateProductRequest
 	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
-		customvalidator.HandleValidationError(w, err)
+		respondValidationError(w, r, err)
 		return
 	}
 
-	product, err := h.service.CreateProduct(r.Context(), req.Description, req.Tags, req.Quantity, req.Price)
+	product, err := h.service.CreateProduct(r.Context(), service.CreateProductInput{
+		Description:    req.Description,
+		Tags:           req.Tags,
+		Quantity:       req.Quantity,
+		Price:          req.Price,
+		AgeRestriction: req.AgeRestriction,
+		Status:         req.Status,
+		Barcode:        req.Barcode,
+		Category:       req.Category,
+		Attributes:     req.Attributes,
+	})
 	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidProductTransition), errors.Is(err, domain.ErrInvalidBarcode),
+			errors.Is(err, domain.ErrInvalidAttributes):
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidAttributes, err.Error())
+			return
+		case errors.Is(err, service.ErrBarcodeTaken):
+			respondError(w, r, http.StatusConflict, ErrCodeBarcodeTaken, err.Error())
+			return
+		}
 		log.Error("failed to create product", "op", op, "err", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
 		return
 	}
 
@@ -67,17 +184,105 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Import godoc
+// @Summary Import products from CSV
+// @Description Creates the products of a CSV file uploaded as the "file" part of a multipart form, e.g. to onboard a catalog.
+// @Description The header names the columns: description and price are required; quantity, tags (separated by "|"),
+// @Description age_restriction, status (draft or active), barcode, category and attributes (a JSON object) are optional.
+// @Description Rows are validated as products created one by one, except that the quantity may be 0, and are created
+// @Description in batched transactions as the file is read. Invalid rows and barcodes repeated in the file or already taken
+// @Description fail without affecting other rows, and are listed in the report. If the import stops, e.g. at a malformed
+// @Description line, the error details hold the report: earlier batches stay created, and StoppedAt is the failing line.
+// @Description Requires the admin or manager role.
+// @Tags products
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param   file  formData  file  true  "CSV file, e.g. description,price,quantity,tags,barcode\nWireless headphones,99.99,100,audio|wireless,4006381333931"
+// @Security ApiKeyAuth
+// @Success 200  {object}  service.ProductImportReport
+// @Failure 400  {object}  ErrorResponse "Invalid form or CSV file; once rows were read, details hold the report with the failing line in StoppedAt"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 403  {object}  ErrorResponse "Insufficient role"
+// @Failure 409  {object}  ErrorResponse "Barcode taken during the import, details hold the report with the failing line in StoppedAt"
+// @Failure 413  {object}  ErrorResponse "File too large, details hold the report of the rows read"
+// @Failure 500  {object}  ErrorResponse "Internal server error, details hold the report with the failing line in StoppedAt"
+// @Router /products/import [post]
+func (h *ProductHandler) Import(w http.ResponseWriter, r *http.Request) {
+	const op = "ProductHandler.Import"
+	log := h.logger.WithTrace(r.Context())
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxProductImportSize)
+	form, err := r.MultipartReader()
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid multipart form")
+		return
+	}
+	// The file is read as it is uploaded, without buffering the form
+	var file *multipart.Part
+	for file == nil {
+		part, err := form.NextPart()
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			switch {
+			case errors.As(err, &tooLarge):
+				respondError(w, r, http.StatusRequestEntityTooLarge, ErrCodePayloadTooLarge, "file too large")
+			case errors.Is(err, io.EOF):
+				respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "missing file part")
+			default:
+				respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid multipart form")
+			}
+			return
+		}
+		if part.FormName() == "file" {
+			file = part
+		}
+	}
+
+	report, err := h.service.ImportProducts(r.Context(), file)
+	if err != nil {
+		// Products of earlier batches stay created, the report of the rows read names the failing line
+		var details any
+		created, stoppedAt := 0, 0
+		if report != nil {
+			details, created, stoppedAt = report, report.Created, report.StoppedAt
+		}
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			log.Info("product import too large", "op", op, "created", created, "stopped_at", stoppedAt)
+			respondErrorDetails(w, r, http.StatusRequestEntityTooLarge, ErrCodePayloadTooLarge, "file too large", details)
+		case errors.Is(err, service.ErrInvalidProductImport):
+			log.Info("invalid product import", "op", op, "error", err, "created", created, "stopped_at", stoppedAt)
+			respondErrorDetails(w, r, http.StatusBadRequest, ErrCodeInvalidProductImport, err.Error(), details)
+		case errors.Is(err, service.ErrBarcodeTaken):
+			log.Info("product import conflicted", "op", op, "error", err, "created", created, "stopped_at", stoppedAt)
+			respondErrorDetails(w, r, http.StatusConflict, ErrCodeBarcodeTaken, err.Error(), details)
+		default:
+			log.Error("failed to import products", "op", op, "error", err, "created", created, "stopped_at", stoppedAt)
+			respondErrorDetails(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error", details)
+		}
+		return
+	}
+	log.Info("products imported", "op", op, "rows", len(report.Results), "created", report.Created, "failed", report.Failed)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Error("failed to encode product import report", "op", op, "error", err)
+	}
+}
+
 // GetByID godoc
 // @Summary Get a product by ID
+// @Description Draft products are not found.
 // @Tags products
 // @Produce  json
 // @Param   id   path      string  true  "Product ID"
 // @Security ApiKeyAuth
 // @Success 200  {object}  domain.Product
-// @Failure 400  {string}  string "Invalid product ID"
-// @Failure 401  {string}  string "Unauthorized"
-// @Failure 404  {string}  string "Product not found"
-// @Failure 500  {string}  string "Internal server error"
+// @Failure 400  {object}  ErrorResponse "Invalid product ID"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 404  {object}  ErrorResponse "Product not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
 // @Router /products/{id} [get]
 func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	const op = "ProductHandler.GetByID"
@@ -87,18 +292,18 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		http.Error(w, "invalid product ID", http.StatusBadRequest)
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid product ID")
 		return
 	}
 
 	product, err := h.service.GetProductByID(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, service.ErrProductNotFound) {
-			http.Error(w, "product not found", http.StatusNotFound)
+			respondError(w, r, http.StatusNotFound, ErrCodeProductNotFound, "product not found")
 			return
 		}
 		log.Error("failed to get product by id", "op", op, "error", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
 		return
 	}
 
@@ -107,3 +312,533 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		log.Error("failed to encode product response", "op", op, "err", err)
 	}
 }
+
+// List godoc
+// @Summary List products
+// @Description Returns a page of active products, newest first, with the number of products matching the filters.
+// @Description Tags are combined: products must have all of them.
+// @Tags products
+// @Produce  json
+// @Param   tag        query  []string  false  "Tag the products must have, repeatable"  collectionFormat(multi)
+// @Param   min_price  query  number    false  "Minimum price, inclusive"
+// @Param   max_price  query  number    false  "Maximum price, inclusive"
+// @Param   in_stock   query  bool      false  "Only products that can be ordered from stock"
+// @Param   limit      query  int       false  "Maximum number of products (1-100, default 20)"
+// @Param   offset     query  int       false  "Number of products to skip"
+// @Security ApiKeyAuth
+// @Success 200  {object}  service.ProductPage
+// @Failure 400  {object}  ErrorResponse "Invalid price range, in_stock, limit or offset"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /products [get]
+func (h *ProductHandler) List(w http.ResponseWriter, r *http.Request) {
+	const op = "ProductHandler.List"
+	log := h.logger.WithTrace(r.Context())
+
+	in, err := parseProductListInput(r.URL.Query())
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.ListProducts(r.Context(), in)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPriceRange) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidPriceRange, err.Error())
+			return
+		}
+		log.Error("failed to list products", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		log.Error("failed to encode product page", "op", op, "error", err)
+	}
+}
+
+// parseProductListInput parses the filters and page of a catalog listing from query parameters.
+func parseProductListInput(query url.Values) (service.ProductListInput, error) {
+	in := service.ProductListInput{Tags: query["tag"]}
+	var err error
+	if in.MinPrice, err = parseOptionalPrice(query.Get("min_price")); err != nil {
+		return in, errors.New("invalid min_price")
+	}
+	if in.MaxPrice, err = parseOptionalPrice(query.Get("max_price")); err != nil {
+		return in, errors.New("invalid max_price")
+	}
+	if v := query.Get("in_stock"); v != "" {
+		if in.InStock, err = strconv.ParseBool(v); err != nil {
+			return in, errors.New("invalid in_stock")
+		}
+	}
+	in.Limit, err = parsePositiveInt(query.Get("limit"), defaultProductListLimit)
+	if err != nil || in.Limit > maxProductListLimit {
+		return in, errors.New("invalid limit")
+	}
+	if v := query.Get("offset"); v != "" {
+		if in.Offset, err = strconv.Atoi(v); err != nil || in.Offset < 0 {
+			return in, errors.New("invalid offset")
+		}
+	}
+	return in, nil
+}
+
+// parseOptionalPrice parses a price query parameter, nil if it is empty.
+func parseOptionalPrice(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+// GetByBarcode godoc
+// @Summary Get a product by barcode
+// @Description Resolves an EAN-8, UPC-A, EAN-13 or GTIN-14 code, e.g. from a warehouse scanner. Draft products are not found.
+// @Tags products
+// @Produce  json
+// @Param   code  path  string  true  "Barcode"
+// @Security ApiKeyAuth
+// @Success 200  {object}  domain.Product
+// @Failure 400  {object}  ErrorResponse "Invalid barcode"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 404  {object}  ErrorResponse "Product not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /products/by-barcode/{code} [get]
+func (h *ProductHandler) GetByBarcode(w http.ResponseWriter, r *http.Request) {
+	const op = "ProductHandler.GetByBarcode"
+	log := h.logger.WithTrace(r.Context())
+
+	product, err := h.service.GetProductByBarcode(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidBarcode):
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidBarcode, err.Error())
+		case errors.Is(err, service.ErrProductNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeProductNotFound, "product not found")
+		default:
+			log.Error("failed to get product by barcode", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(product); err != nil {
+		log.Error("failed to encode product response", "op", op, "err", err)
+	}
+}
+
+// BulkUpdate godoc
+// @Summary Update many products at once
+// @Description Applies price, description, age restriction, tag, status, barcode, category and attribute changes to many products in a single transaction.
+// @Description Items of unknown products or with invalid values fail and are reported; with "atomic" no item is applied then.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   changes  body  BulkUpdateProductsRequest  true  "Product changes"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  service.BulkUpdateReport
+// @Failure 400  {object}  ErrorResponse "Invalid request body"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 409  {object}  ErrorResponse "Changed barcode already taken, nothing applied"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/products/bulk [patch]
+func (h *ProductHandler) BulkUpdate(w http.ResponseWriter, r *http.Request) {
+	const op = "ProductHandler.BulkUpdate"
+	log := h.logger.WithTrace(r.Context())
+
+	var req BulkUpdateProductsRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	changes := make([]service.ProductChangeInput, len(req.Items))
+	for i, item := range req.Items {
+		changes[i] = service.ProductChangeInput{
+			ID: item.ID,
+			Change: domain.ProductChange{
+				Description:    item.Description,
+				Price:          item.Price,
+				AgeRestriction: item.AgeRestriction,
+				AddTags:        item.AddTags,
+				RemoveTags:     item.RemoveTags,
+				Status:         item.Status,
+				Barcode:        item.Barcode,
+				Category:       item.Category,
+				Attributes:     item.Attributes,
+			},
+		}
+	}
+
+	report, err := h.service.BulkUpdate(r.Context(), callerID(r.Context()), changes, req.Atomic)
+	if err != nil {
+		if errors.Is(err, service.ErrBarcodeTaken) {
+			respondError(w, r, http.StatusConflict, ErrCodeBarcodeTaken, err.Error())
+			return
+		}
+		log.Error("failed to bulk update products", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	log.Info("products bulk updated", "op", op, "updated", report.Updated, "failed", report.Failed)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Error("failed to encode bulk update report", "op", op, "error", err)
+	}
+}
+
+// ChangeStatus godoc
+// @Summary Change the lifecycle status of a product
+// @Description Publishes a draft, archives a product or restores an archived one.
+// @Description Archived products stay visible for order history but cannot be ordered. Products cannot go back to draft.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   id  path  string  true  "Product ID"
+// @Param   status  body  ChangeProductStatusRequest  true  "New status"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  domain.Product
+// @Failure 400  {object}  ErrorResponse "Invalid request body or product ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Product not found"
+// @Failure 409  {object}  ErrorResponse "Status change not allowed"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/products/{id}/status [post]
+func (h *ProductHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	const op = "ProductHandler.ChangeStatus"
+	log := h.logger.WithTrace(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid product ID")
+		return
+	}
+
+	var req ChangeProductStatusRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	product, err := h.service.ChangeStatus(r.Context(), callerID(r.Context()), id, req.Status)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrProductNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeProductNotFound, "product not found")
+		case errors.Is(err, domain.ErrInvalidProductTransition):
+			respondError(w, r, http.StatusConflict, ErrCodeInvalidProductTransition, err.Error())
+		default:
+			log.Error("failed to change product status", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+	log.Info("product status changed", "op", op, "product_id", id, "status", product.Status)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(product); err != nil {
+		log.Error("failed to encode product response", "op", op, "err", err)
+	}
+}
+
+// Delete godoc
+// @Summary Delete a product
+// @Description Deletes a product with its stock movements and history, e.g. one created by mistake.
+// @Description Products referenced by orders or bundles cannot be deleted, archive them instead.
+// @Tags admin
+// @Param   id  path  string  true  "Product ID"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 204
+// @Failure 400  {object}  ErrorResponse "Invalid product ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Product not found"
+// @Failure 409  {object}  ErrorResponse "Product is referenced by orders or bundles"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/products/{id} [delete]
+func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	const op = "ProductHandler.Delete"
+	log := h.logger.WithTrace(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid product ID")
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, service.ErrProductNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeProductNotFound, "product not found")
+		case errors.Is(err, service.ErrProductInUse):
+			respondError(w, r, http.StatusConflict, ErrCodeProductInUse, err.Error())
+		default:
+			log.Error("failed to delete product", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+	log.Info("product deleted", "op", op, "product_id", id, "actor", callerID(r.Context()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CleanupDrafts godoc
+// @Summary Delete abandoned drafts
+// @Description Deletes drafts older than the given age that no bundle references, oldest first.
+// @Tags admin
+// @Produce  json
+// @Param   older_than  query  string  false  "Minimum age of deleted drafts, e.g. 720h (default 30 days)"
+// @Param   limit  query  int  false  "Maximum number of drafts (1-1000, default 100)"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  DraftCleanupResponse
+// @Failure 400  {object}  ErrorResponse "Invalid age or limit"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/products/drafts/cleanup [post]
+func (h *ProductHandler) CleanupDrafts(w http.ResponseWriter, r *http.Request) {
+	const op = "ProductHandler.CleanupDrafts"
+	log := h.logger.WithTrace(r.Context())
+	query := r.URL.Query()
+
+	olderThan := defaultDraftCleanupAge
+	if v := query.Get("older_than"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid older_than")
+			return
+		}
+		olderThan = d
+	}
+	limit, err := parsePositiveInt(query.Get("limit"), defaultDraftCleanupLimit)
+	if err != nil || limit > maxDraftCleanupLimit {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid limit")
+		return
+	}
+
+	deleted, err := h.service.CleanupDrafts(r.Context(), olderThan, limit)
+	if err != nil {
+		log.Error("failed to clean up drafts", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	log.Info("drafts cleaned up", "op", op, "deleted", len(deleted), "older_than", olderThan.String(), "actor", callerID(r.Context()))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(DraftCleanupResponse{Deleted: deleted, Count: len(deleted)}); err != nil {
+		log.Error("failed to encode draft cleanup response", "op", op, "error", err)
+	}
+}
+
+// Issues godoc
+// @Summary Report data-quality issues of the catalog
+// @Description Runs rule-based checks on draft and active products and returns the issues found, in product ID and rule order,
+// @Description with the number of issues per rule. Rules: zero_price (price not positive), no_tags, negative_stock (the stock
+// @Description ledger sums to a negative quantity) and inactive_component (a bundle contains a draft or archived product).
+// @Tags admin
+// @Produce  json
+// @Param   rule  query  []string  false  "Rule to run, repeatable (default: every rule)"  collectionFormat(multi)
+// @Param   limit  query  int  false  "Maximum number of issues (1-1000, default 100)"
+// @Param   offset  query  int  false  "Number of issues to skip"
+// @Param   X-API-Key  header  string  true  "Admin or analyst API key"
+// @Success 200  {object}  service.CatalogIssueReport
+// @Failure 400  {object}  ErrorResponse "Unknown rule, invalid limit or offset"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/products/issues [get]
+func (h *ProductHandler) Issues(w http.ResponseWriter, r *http.Request) {
+	const op = "ProductHandler.Issues"
+	log := h.logger.WithTrace(r.Context())
+	query := r.URL.Query()
+
+	limit, err := parsePositiveInt(query.Get("limit"), defaultCatalogIssueLimit)
+	if err != nil || limit > maxCatalogIssueLimit {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid limit")
+		return
+	}
+	var offset int
+	if v := query.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid offset")
+			return
+		}
+	}
+
+	report, err := h.service.Issues(r.Context(), query["rule"], offset, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidIssueRule) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidIssueRule, err.Error())
+			return
+		}
+		log.Error("failed to find catalog issues", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Error("failed to encode catalog issue report", "op", op, "error", err)
+	}
+}
+
+// History godoc
+// @Summary Get the change history of a product
+// @Description Returns edits of the product details with who made them and field-level before and after values, newest first.
+// @Tags admin
+// @Produce  json
+// @Param   id  path  string  true  "Product ID"
+// @Param   limit  query  int  false  "Maximum number of edits (1-500, default 50)"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {array}   domain.ProductRevision
+// @Failure 400  {object}  ErrorResponse "Invalid product ID or limit"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/products/{id}/history [get]
+func (h *ProductHandler) History(w http.ResponseWriter, r *http.Request) {
+	const op = "ProductHandler.History"
+	log := h.logger.WithTrace(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid product ID")
+		return
+	}
+
+	limit, err := parsePositiveInt(r.URL.Query().Get("limit"), defaultProductHistoryLimit)
+	if err != nil || limit > maxProductHistoryLimit {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid limit")
+		return
+	}
+
+	revisions, err := h.service.History(r.Context(), id, limit)
+	if err != nil {
+		log.Error("failed to get product history", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(revisions); err != nil {
+		log.Error("failed to encode product history", "op", op, "error", err)
+	}
+}
+
+// PriceAt godoc
+// @Summary Get the price of a product at a point in time
+// @Description Resolves the price the product was sold at at the given time from the history of its price edits,
+// @Description with when and by whom that price was set, to settle customer pricing complaints.
+// @Description The catalog has a single base price per product: there are no promotional or tiered prices to resolve.
+// @Tags admin
+// @Produce  json
+// @Param   id  path  string  true  "Product ID"
+// @Param   timestamp  query  string  true  "RFC 3339 time"
+// @Param   X-API-Key  header  string  true  "Admin or support API key"
+// @Success 200  {object}  domain.EffectivePrice
+// @Failure 400  {object}  ErrorResponse "Invalid product ID or timestamp"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Product not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/products/{id}/price-at [get]
+func (h *ProductHandler) PriceAt(w http.ResponseWriter, r *http.Request) {
+	const op = "ProductHandler.PriceAt"
+	log := h.logger.WithTrace(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid product ID")
+		return
+	}
+
+	at, err := time.Parse(time.RFC3339, r.URL.Query().Get("timestamp"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid timestamp, expected RFC 3339")
+		return
+	}
+
+	price, err := h.service.PriceAt(r.Context(), id, at)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeProductNotFound, "product not found")
+			return
+		}
+		log.Error("failed to resolve product price", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(price); err != nil {
+		log.Error("failed to encode product price", "op", op, "error", err)
+	}
+}
+
+// CreateBundle godoc
+// @Summary Create a bundle of products
+// @Description Creates a product composed of existing products. Bundles have no stock of their own:
+// @Description ordering a bundle allocates the stock of its components and adds a bundle line followed by component lines to the order.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   bundle  body  CreateBundleRequest  true  "Bundle details"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 201  {object}  domain.Product
+// @Failure 400  {object}  ErrorResponse "Invalid request body, barcode or attributes, unknown, repeated or nested components"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 409  {object}  ErrorResponse "Barcode already taken"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/bundles [post]
+func (h *ProductHandler) CreateBundle(w http.ResponseWriter, r *http.Request) {
+	const op = "ProductHandler.CreateBundle"
+	log := h.logger.WithTrace(r.Context())
+
+	var req CreateBundleRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	components := make([]domain.BundleComponent, len(req.Components))
+	for i, c := range req.Components {
+		components[i] = domain.BundleComponent{ProductID: c.ProductID, Quantity: c.Quantity}
+	}
+
+	bundle, err := h.service.CreateBundle(r.Context(), service.CreateBundleInput{
+		Description:    req.Description,
+		Tags:           req.Tags,
+		Price:          req.Price,
+		AgeRestriction: req.AgeRestriction,
+		Status:         req.Status,
+		Barcode:        req.Barcode,
+		Category:       req.Category,
+		Attributes:     req.Attributes,
+		Components:     components,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidBundle), errors.Is(err, domain.ErrInvalidProductTransition),
+			errors.Is(err, domain.ErrInvalidBarcode), errors.Is(err, domain.ErrInvalidAttributes):
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidProduct, err.Error())
+			return
+		case errors.Is(err, service.ErrBarcodeTaken):
+			respondError(w, r, http.StatusConflict, ErrCodeBarcodeTaken, err.Error())
+			return
+		}
+		log.Error("failed to create bundle", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	log.Info("bundle created", "op", op, "product_id", bundle.ID, "components", len(bundle.Components))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(bundle); err != nil {
+		log.Error("failed to encode product response", "op", op, "err", err)
+	}
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"product-api/internal/domain"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Catalog export formats.
+const (
+	exportFormatJSONLines = "jsonl"
+	exportFormatCSV       = "csv"
+)
+
+// exportFlushInterval is the number of exported products after which the response is flushed to the client.
+const exportFlushInterval = 1000
+
+// productCSVHeader lists columns of the CSV catalog export.
+// Tags, attributes and bundle components are JSON encoded.
+var productCSVHeader = []string{
+	"id", "description", "tags", "quantity", "price", "age_restriction", "status", "barcode", "category", "attributes", "components",
+}
+
+// Export godoc
+// @Summary Export the whole catalog
+// @Description Streams all products, including drafts and archived products, ordered by ID as JSON lines or CSV.
+// @Description All products are read from one database snapshot. To resume an interrupted export,
+// @Description pass the ID of the last completely received product as cursor. Failed exports are aborted mid-response,
+// @Description so they are never received as complete.
+// @Tags admin
+// @Produce  plain
+// @Param   format  query  string  false  "jsonl (default) or csv"
+// @Param   cursor  query  string  false  "Export products after this product ID"
+// @Param   X-API-Key  header  string  true  "Admin or analyst API key"
+// @Success 200  {string}  string "One product per line"
+// @Failure 400  {object}  ErrorResponse "Invalid format or cursor"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/products/export [get]
+func (h *ProductHandler) Export(w http.ResponseWriter, r *http.Request) {
+	const op = "ProductHandler.Export"
+	log := h.logger.WithTrace(r.Context())
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exportFormatJSONLines
+	}
+	if format != exportFormatJSONLines && format != exportFormatCSV {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "format must be jsonl or csv")
+		return
+	}
+	var cursor uuid.UUID
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		var err error
+		if cursor, err = uuid.Parse(v); err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid cursor")
+			return
+		}
+	}
+
+	// The export outlives the server write timeout of regular requests
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn("failed to clear write deadline of export", "op", op, "error", err)
+	}
+
+	buf := bufio.NewWriter(w)
+	var encode func(*domain.Product) error
+	if format == exportFormatCSV {
+		w.Header().Set("Content-Type", "text/csv")
+		cw := csv.NewWriter(buf)
+		encode = func(p *domain.Product) error {
+			record, err := productCSVRecord(p)
+			if err != nil {
+				return err
+			}
+			cw.Write(record)
+			cw.Flush()
+			return cw.Error()
+		}
+		if cursor == uuid.Nil {
+			cw.Write(productCSVHeader)
+		}
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(buf)
+		encode = func(p *domain.Product) error { return enc.Encode(p) }
+	}
+
+	count := 0
+	err := h.service.Export(r.Context(), cursor, func(p *domain.Product) error {
+		if err := encode(p); err != nil {
+			return err
+		}
+		count++
+		if count%exportFlushInterval == 0 {
+			if err := buf.Flush(); err != nil {
+				return err
+			}
+			return rc.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err != nil {
+		if count == 0 && r.Context().Err() == nil {
+			log.Error("failed to export catalog", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+			return
+		}
+		// Part of the export was sent, abort the response so the client does not take it as complete
+		log.Error("catalog export interrupted", "op", op, "exported", count, "error", err)
+		panic(http.ErrAbortHandler)
+	}
+	log.Info("catalog exported", "op", op, "format", format, "products", count)
+}
+
+// productCSVRecord returns the product as a row of productCSVHeader columns.
+func productCSVRecord(p *domain.Product) ([]string, error) {
+	tags, err := json.Marshal(p.Tags)
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := json.Marshal(p.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	components, err := json.Marshal(p.Components)
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		p.ID.String(),
+		p.Description,
+		string(tags),
+		strconv.Itoa(p.Quantity),
+		strconv.FormatFloat(p.Price, 'f', 2, 64),
+		strconv.Itoa(p.AgeRestriction),
+		p.Status,
+		p.Barcode,
+		p.Category,
+		string(attributes),
+		string(components),
+	}, nil
+}
@@ -0,0 +1,122 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/domain"
+	"product-api/internal/handler"
+	"product-api/internal/logger"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// exportProducts makes the repository export the products after the cursor, then fail with err if it is set.
+func exportProducts(repo *mocks.MockProductRepository, cursor uuid.UUID, products []domain.Product, err error) {
+	repo.EXPECT().Export(mock.Anything, cursor, mock.Anything).RunAndReturn(func(_ context.Context, _ uuid.UUID, fn func(*domain.Product) error) error {
+		for i := range products {
+			if err := fn(&products[i]); err != nil {
+				return err
+			}
+		}
+		return err
+	})
+}
+
+// newExportHandler returns the export handler on the repository.
+func newExportHandler(t *testing.T, repo *mocks.MockProductRepository) http.Handler {
+	products := service.NewProductService(mocks.NewMockTxBeginner(t), repo, mocks.NewMockProductRevisionRepository(t), mocks.NewMockAttributeRepository(t))
+	return http.HandlerFunc(handler.NewProductHandler(products, logger.NewSlogAdapter("local")).Export)
+}
+
+func TestProductHandler_Export_JSONLines(t *testing.T) {
+	repo := mocks.NewMockProductRepository(t)
+	products := []domain.Product{
+		{ID: uuid.New(), Description: "Headphones", Price: 99.99, Status: domain.ProductStatusActive},
+		{ID: uuid.New(), Description: "Speaker", Price: 59.99, Status: domain.ProductStatusArchived},
+	}
+	exportProducts(repo, uuid.Nil, products, nil)
+
+	rec := httptest.NewRecorder()
+	newExportHandler(t, repo).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/products/export", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	for i, line := range lines {
+		var got domain.Product
+		require.NoError(t, json.Unmarshal([]byte(line), &got))
+		assert.Equal(t, products[i], got)
+	}
+}
+
+func TestProductHandler_Export_CSV(t *testing.T) {
+	repo := mocks.NewMockProductRepository(t)
+	product := domain.Product{
+		ID: uuid.New(), Description: "Bundle, large", Tags: []string{"gift"}, Price: 10, Status: domain.ProductStatusActive,
+		Attributes: map[string]any{"color": "red"}, Components: []domain.BundleComponent{{ProductID: uuid.New(), Quantity: 2}},
+	}
+	cursor := uuid.New()
+	exportProducts(repo, uuid.Nil, []domain.Product{product}, nil)
+	exportProducts(repo, cursor, []domain.Product{product}, nil)
+	h := newExportHandler(t, repo)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/products/export?format=csv", nil))
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"id", "description", "tags", "quantity", "price", "age_restriction", "status", "barcode", "category", "attributes", "components"}, rows[0])
+	assert.Equal(t, []string{product.ID.String(), "Bundle, large", `["gift"]`, "0", "10.00", "0", "active", "", "", `{"color":"red"}`,
+		`[{"ProductID":"` + product.Components[0].ProductID.String() + `","Quantity":2}]`}, rows[1])
+
+	// A resumed export continues the rows of the interrupted one, without a header
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/products/export?format=csv&cursor="+cursor.String(), nil))
+	rows, err = csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, product.ID.String(), rows[0][0])
+}
+
+func TestProductHandler_Export_InvalidRequest(t *testing.T) {
+	h := newExportHandler(t, mocks.NewMockProductRepository(t))
+
+	for _, query := range []string{"format=xml", "cursor=42"} {
+		rec, resp := serveError[any](t, h, httptest.NewRequest(http.MethodGet, "/admin/products/export?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		assert.Equal(t, "invalid_request", resp.Code, query)
+	}
+}
+
+func TestProductHandler_Export_Fails(t *testing.T) {
+	t.Run("before the first product", func(t *testing.T) {
+		repo := mocks.NewMockProductRepository(t)
+		exportProducts(repo, uuid.Nil, nil, assert.AnError)
+
+		rec, resp := serveError[any](t, newExportHandler(t, repo), httptest.NewRequest(http.MethodGet, "/admin/products/export", nil))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, "internal_error", resp.Code)
+	})
+
+	t.Run("after products were sent", func(t *testing.T) {
+		repo := mocks.NewMockProductRepository(t)
+		exportProducts(repo, uuid.Nil, []domain.Product{{ID: uuid.New(), Description: "Headphones"}}, assert.AnError)
+
+		// The response is aborted, so the client does not take the partial export as complete
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			newExportHandler(t, repo).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/admin/products/export", nil))
+		})
+	})
+}
@@ -0,0 +1,54 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/handler"
+	"product-api/internal/logger"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductHandler_Import_ReportsRowsOfFailedImport(t *testing.T) {
+	repo := mocks.NewMockProductRepository(t)
+	products := service.NewProductService(mocks.NewMockTxBeginner(t), repo, mocks.NewMockProductRevisionRepository(t), mocks.NewMockAttributeRepository(t))
+	h := middleware.RequestID(http.HandlerFunc(handler.NewProductHandler(products, logger.NewSlogAdapter("local")).Import))
+
+	repo.EXPECT().CreateMany(mock.Anything, mock.Anything).Return(assert.AnError)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "catalog.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("description,price\nHeadphones,99.99\nSpeaker,59.99\n"))
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+	req := httptest.NewRequest(http.MethodPost, "/products/import", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	var resp struct {
+		Code      string
+		RequestID string `json:"request_id"`
+		Details   service.ProductImportReport
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "internal_error", resp.Code)
+	assert.NotEmpty(t, resp.RequestID)
+	assert.Equal(t, 2, resp.Details.StoppedAt)
+	assert.Equal(t, 2, resp.Details.Failed)
+	require.Len(t, resp.Details.Results, 2)
+	assert.Equal(t, service.ProductImportFailed, resp.Details.Results[0].Status)
+}
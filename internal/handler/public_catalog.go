@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// publicProductFields lists the product fields anonymous clients may read, in response order.
+// Stock levels, barcodes, age restrictions and bundle components stay private.
+var publicProductFields = []string{"ID", "Description", "Price", "Tags", "Category", "Attributes", "Status"}
+
+// PublicProduct is the view of a product embedded in marketing sites.
+type PublicProduct map[string]any
+
+// PublicProductPage is a page of the public catalog.
+type PublicProductPage struct {
+	Products []PublicProduct // Newest first
+	Total    int             // Number of products matching the criteria
+}
+
+// PublicCatalogHandler serves the read-only catalog marketing sites embed without authentication.
+// Responses only carry whitelisted product fields and are cached by browsers and CDNs for maxAge.
+type PublicCatalogHandler struct {
+	service *service.ProductService
+	maxAge  time.Duration
+	logger  logger.Logger
+}
+
+// NewPublicCatalogHandler creates a new public catalog handler caching responses for maxAge.
+func NewPublicCatalogHandler(s *service.ProductService, maxAge time.Duration, l logger.Logger) *PublicCatalogHandler {
+	return &PublicCatalogHandler{service: s, maxAge: maxAge, logger: l}
+}
+
+// PublicCORSMiddleware lets pages of any origin read the public catalog. Requests carry no credentials,
+// so any origin is allowed; preflight requests are answered here and cached by browsers for a day.
+func PublicCORSMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "If-None-Match")
+			w.Header().Set("Access-Control-Max-Age", "86400")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// List godoc
+// @Summary List products of the public catalog
+// @Description Returns a page of active products for embedding in marketing sites, without authentication.
+// @Description Only ID, Description, Price, Tags, Category, Attributes and Status are returned, fields narrows them down.
+// @Description Responses are cacheable by browsers and CDNs and revalidated with ETag.
+// @Tags public
+// @Produce  json
+// @Param   tag        query  []string  false  "Tag the products must have, repeatable"  collectionFormat(multi)
+// @Param   min_price  query  number    false  "Minimum price, inclusive"
+// @Param   max_price  query  number    false  "Maximum price, inclusive"
+// @Param   in_stock   query  bool      false  "Only products that can be ordered from stock"
+// @Param   limit      query  int       false  "Maximum number of products (1-100, default 20)"
+// @Param   offset     query  int       false  "Number of products to skip"
+// @Param   fields     query  string    false  "Comma-separated product fields to return (default: all public fields)"
+// @Param   If-None-Match  header  string  false  "ETag of a cached response"
+// @Success 200  {object}  PublicProductPage
+// @Success 304  {string}  string "Cached response is current"
+// @Failure 400  {object}  ErrorResponse "Invalid price range, in_stock, limit, offset or fields"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Failure 503  {object}  ErrorResponse "Service busy"
+// @Router /public/products [get]
+func (h *PublicCatalogHandler) List(w http.ResponseWriter, r *http.Request) {
+	const op = "PublicCatalogHandler.List"
+	log := h.logger.WithTrace(r.Context())
+
+	fields, err := parsePublicFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
+		return
+	}
+	in, err := parseProductListInput(r.URL.Query())
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.ListProducts(r.Context(), in)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPriceRange) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidPriceRange, err.Error())
+			return
+		}
+		log.Error("failed to list products", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	resp := PublicProductPage{Products: make([]PublicProduct, 0, len(page.Products)), Total: page.Total}
+	for i := range page.Products {
+		resp.Products = append(resp.Products, newPublicProduct(&page.Products[i], fields))
+	}
+	h.writeCached(w, r, op, resp)
+}
+
+// Get godoc
+// @Summary Get a product of the public catalog
+// @Description Returns a product for embedding in marketing sites, without authentication. Draft products are not found.
+// @Description Only ID, Description, Price, Tags, Category, Attributes and Status are returned, fields narrows them down.
+// @Description Responses are cacheable by browsers and CDNs and revalidated with ETag.
+// @Tags public
+// @Produce  json
+// @Param   id      path   string  true   "Product ID"
+// @Param   fields  query  string  false  "Comma-separated product fields to return (default: all public fields)"
+// @Param   If-None-Match  header  string  false  "ETag of a cached response"
+// @Success 200  {object}  PublicProduct
+// @Success 304  {string}  string "Cached response is current"
+// @Failure 400  {object}  ErrorResponse "Invalid product ID or fields"
+// @Failure 404  {object}  ErrorResponse "Product not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Failure 503  {object}  ErrorResponse "Service busy"
+// @Router /public/products/{id} [get]
+func (h *PublicCatalogHandler) Get(w http.ResponseWriter, r *http.Request) {
+	const op = "PublicCatalogHandler.Get"
+	log := h.logger.WithTrace(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid product ID")
+		return
+	}
+	fields, err := parsePublicFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
+		return
+	}
+
+	product, err := h.service.GetProductByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeProductNotFound, "product not found")
+			return
+		}
+		log.Error("failed to get product by id", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	h.writeCached(w, r, op, newPublicProduct(product, fields))
+}
+
+// writeCached writes the response as JSON with cache headers, or 304 Not Modified
+// if the client already holds it, so revalidations after maxAge cost no transfer.
+func (h *PublicCatalogHandler) writeCached(w http.ResponseWriter, r *http.Request, op string, resp any) {
+	body, err := json.Marshal(resp)
+	if err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to encode public catalog response", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	maxAge := strconv.Itoa(int(h.maxAge.Seconds()))
+	w.Header().Set("Cache-Control", "public, max-age="+maxAge+", stale-while-revalidate="+maxAge+", stale-if-error=86400")
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(append(body, '\n')); err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to write public catalog response", "op", op, "error", err)
+	}
+}
+
+// etagMatches reports whether an If-None-Match header lists the ETag, weakly compared.
+func etagMatches(header, etag string) bool {
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePublicFields parses the comma-separated fields query parameter, all public fields if it is empty.
+func parsePublicFields(value string) ([]string, error) {
+	if value == "" {
+		return publicProductFields, nil
+	}
+	var fields []string
+	for field := range strings.SplitSeq(value, ",") {
+		field = strings.TrimSpace(field)
+		idx := slices.IndexFunc(publicProductFields, func(f string) bool { return strings.EqualFold(f, field) })
+		if idx < 0 {
+			return nil, fmt.Errorf("unknown field %q, expected any of %s", field, strings.Join(publicProductFields, ", "))
+		}
+		if !slices.Contains(fields, publicProductFields[idx]) {
+			fields = append(fields, publicProductFields[idx])
+		}
+	}
+	return fields, nil
+}
+
+// newPublicProduct copies the fields of the product, which must be public, into its public view.
+func newPublicProduct(p *domain.Product, fields []string) PublicProduct {
+	view := make(PublicProduct, len(fields))
+	for _, field := range fields {
+		switch field {
+		case "ID":
+			view[field] = p.ID
+		case "Description":
+			view[field] = p.Description
+		case "Price":
+			view[field] = p.Price
+		case "Tags":
+			view[field] = p.Tags
+		case "Category":
+			view[field] = p.Category
+		case "Attributes":
+			view[field] = p.Attributes
+		case "Status":
+			view[field] = p.Status
+		}
+	}
+	return view
+}
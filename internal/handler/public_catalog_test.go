@@ -0,0 +1,112 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/domain"
+	"product-api/internal/handler"
+	"product-api/internal/logger"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// privateProduct returns an active product with all the fields the public catalog must not expose set.
+func privateProduct() domain.Product {
+	return domain.Product{
+		ID:             uuid.New(),
+		Description:    "Trail shoes",
+		Tags:           []string{"summer"},
+		Quantity:       12,
+		Price:          89.9,
+		AgeRestriction: 16,
+		Status:         domain.ProductStatusActive,
+		Barcode:        "4006381333931",
+		Category:       "shoes",
+		Attributes:     map[string]any{"color": "red"},
+		Components:     []domain.BundleComponent{{ProductID: uuid.New(), Quantity: 2}},
+	}
+}
+
+// newPublicCatalogRouter routes the public catalog endpoints to a handler on the repository.
+func newPublicCatalogRouter(repo *mocks.MockProductRepository) http.Handler {
+	h := handler.NewPublicCatalogHandler(service.NewProductService(nil, repo, nil, nil), time.Minute, logger.NewSlogAdapter("local"))
+	r := chi.NewRouter()
+	r.Get("/public/products", h.List)
+	r.Get("/public/products/{id}", h.Get)
+	return r
+}
+
+// servePublic serves the request and decodes the JSON response.
+func servePublic(t *testing.T, h http.Handler, target string, resp any) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), resp))
+	return rec
+}
+
+// fieldNames returns the sorted field names of a decoded product.
+func fieldNames(product map[string]any) []string {
+	names := make([]string, 0, len(product))
+	for name := range product {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestPublicCatalog_StripsPrivateFields(t *testing.T) {
+	repo := mocks.NewMockProductRepository(t)
+	product := privateProduct()
+	repo.EXPECT().FindByID(mock.Anything, product.ID).Return(&product, nil)
+	repo.EXPECT().List(mock.Anything, mock.Anything, 0, 20).Return([]domain.Product{product}, 1, nil)
+	h := newPublicCatalogRouter(repo)
+	public := []string{"Attributes", "Category", "Description", "ID", "Price", "Status", "Tags"}
+
+	var got map[string]any
+	servePublic(t, h, "/public/products/"+product.ID.String(), &got)
+	assert.Equal(t, public, fieldNames(got), "no quantity, barcode, age restriction or components")
+	assert.Equal(t, product.ID.String(), got["ID"])
+
+	var page struct {
+		Products []map[string]any
+		Total    int
+	}
+	servePublic(t, h, "/public/products", &page)
+	require.Len(t, page.Products, 1)
+	assert.Equal(t, public, fieldNames(page.Products[0]))
+	assert.Equal(t, 1, page.Total)
+}
+
+func TestPublicCatalog_NarrowsFields(t *testing.T) {
+	repo := mocks.NewMockProductRepository(t)
+	product := privateProduct()
+	repo.EXPECT().FindByID(mock.Anything, product.ID).Return(&product, nil)
+	h := newPublicCatalogRouter(repo)
+
+	var got map[string]any
+	servePublic(t, h, "/public/products/"+product.ID.String()+"?fields=price,%20Tags,price", &got)
+	assert.Equal(t, map[string]any{"Price": 89.9, "Tags": []any{"summer"}}, got)
+}
+
+func TestPublicCatalog_RejectsPrivateFields(t *testing.T) {
+	h := newPublicCatalogRouter(mocks.NewMockProductRepository(t))
+
+	for _, field := range []string{"Quantity", "Barcode", "AgeRestriction", "Components"} {
+		rec, resp := serveError[any](t, h, httptest.NewRequest(http.MethodGet, "/public/products/"+uuid.NewString()+"?fields=ID,"+field, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, field)
+		assert.Equal(t, "invalid_request", resp.Code, field)
+		assert.Contains(t, resp.Message, field)
+	}
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	customvalidator "product-api/pkg/validator"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetQuotaRequest contains the new limit of an API client quota.
+type SetQuotaRequest struct {
+	Period string `json:"period" example:"day" validate:"required,oneof=day month"`
+	Limit  int    `json:"limit" example:"10000" validate:"gte=0"` // 0 disables the quota
+}
+
+// QuotaHandler handles API client quota enforcement and administration.
+type QuotaHandler struct {
+	service *service.QuotaService
+	logger  logger.Logger
+}
+
+// NewQuotaHandler creates a new quota handler.
+func NewQuotaHandler(s *service.QuotaService, l logger.Logger) *QuotaHandler {
+	return &QuotaHandler{service: s, logger: l}
+}
+
+// Enforce creates middleware that counts the call against the API client's quotas.
+// Sets X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset (Unix time) headers of the quota
+// closest to exhaustion, also as X-Quota-* for older clients. Past the soft limit of a quota, calls are
+// served with an X-RateLimit-Warning header, so clients can back off before calls over quota are rejected
+// with 429 Too Many Requests and Retry-After. Must be placed after APIKeyMiddleware.
+func (h *QuotaHandler) Enforce(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const op = "QuotaHandler.Enforce"
+		log := h.logger.WithTrace(r.Context())
+
+		client, ok := r.Context().Value(APIKeyNameKey).(string)
+		if !ok {
+			log.Error("api client not found in context", "op", op)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+			return
+		}
+
+		usage, err := h.service.Consume(r.Context(), client)
+		if err != nil && !errors.Is(err, service.ErrQuotaExceeded) {
+			log.Error("failed to consume api quota", "op", op, "client", client, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+			return
+		}
+
+		if len(usage) > 0 {
+			tightest := tightestQuota(usage)
+			limit, remaining := strconv.Itoa(tightest.Limit), strconv.Itoa(tightest.Remaining())
+			reset := strconv.FormatInt(tightest.ResetAt.Unix(), 10)
+			w.Header().Set("X-RateLimit-Limit", limit)
+			w.Header().Set("X-RateLimit-Remaining", remaining)
+			w.Header().Set("X-RateLimit-Reset", reset)
+			w.Header().Set("X-Quota-Limit", limit)
+			w.Header().Set("X-Quota-Remaining", remaining)
+			w.Header().Set("X-Quota-Reset", reset)
+		}
+		for _, u := range usage {
+			if !u.Warned() {
+				continue
+			}
+			w.Header().Add("X-RateLimit-Warning", fmt.Sprintf("%d of %d %s calls used, resets at %s",
+				u.Used, u.Limit, u.Period, u.ResetAt.Format(time.RFC3339)))
+			if u.Used == u.SoftLimit+1 {
+				log.Warn("api quota soft limit reached", "op", op, "client", client, "period", u.Period, "limit", u.Limit)
+			}
+		}
+
+		if err != nil {
+			retryAfter := exceededQuotaReset(usage).Sub(time.Now())
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			log.Warn("api quota exceeded", "op", op, "client", client)
+			respondError(w, r, http.StatusTooManyRequests, ErrCodeQuotaExceeded, "api quota exceeded")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tightestQuota returns the quota with the fewest remaining calls.
+func tightestQuota(usage []domain.QuotaUsage) domain.QuotaUsage {
+	tightest := usage[0]
+	for _, u := range usage[1:] {
+		if u.Remaining() < tightest.Remaining() {
+			tightest = u
+		}
+	}
+	return tightest
+}
+
+// exceededQuotaReset returns the time when all exceeded quotas are reset.
+func exceededQuotaReset(usage []domain.QuotaUsage) time.Time {
+	var reset time.Time
+	for _, u := range usage {
+		if u.Exceeded() && u.ResetAt.After(reset) {
+			reset = u.ResetAt
+		}
+	}
+	return reset
+}
+
+// Get godoc
+// @Summary Get quotas and current usage of an API client
+// @Tags admin
+// @Produce  json
+// @Param   client  path  string  true  "API client name, e.g. gateway"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {array}   domain.QuotaUsage
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/api-clients/{client}/quotas [get]
+func (h *QuotaHandler) Get(w http.ResponseWriter, r *http.Request) {
+	const op = "QuotaHandler.Get"
+	log := h.logger.WithTrace(r.Context())
+
+	usage, err := h.service.Usage(r.Context(), chi.URLParam(r, "client"))
+	if err != nil {
+		log.Error("failed to get api quota usage", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(usage); err != nil {
+		log.Error("failed to encode api quota usage", "op", op, "error", err)
+	}
+}
+
+// Set godoc
+// @Summary Set a quota of an API client
+// @Description Overrides the configured default limit of the daily or monthly quota. A limit of 0 disables the quota.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   client  path  string  true  "API client name, e.g. gateway"
+// @Param   quota  body  SetQuotaRequest  true  "Quota limit"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {array}   domain.QuotaUsage
+// @Failure 400  {object}  ErrorResponse "Invalid request body"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/api-clients/{client}/quotas [put]
+func (h *QuotaHandler) Set(w http.ResponseWriter, r *http.Request) {
+	const op = "QuotaHandler.Set"
+	log := h.logger.WithTrace(r.Context())
+
+	var req SetQuotaRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	client := chi.URLParam(r, "client")
+	if err := h.service.SetQuota(r.Context(), client, req.Period, req.Limit); err != nil {
+		if errors.Is(err, service.ErrInvalidQuota) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidQuota, err.Error())
+			return
+		}
+		log.Error("failed to set api quota", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	log.Info("api quota set", "op", op, "client", client, "period", req.Period, "limit", req.Limit)
+
+	h.Get(w, r)
+}
+
+// Reset godoc
+// @Summary Reset quota usage of an API client
+// @Description Clears the call counters of all quota windows of the client.
+// @Tags admin
+// @Produce  json
+// @Param   client  path  string  true  "API client name, e.g. gateway"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {array}   domain.QuotaUsage
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/api-clients/{client}/quotas/reset [post]
+func (h *QuotaHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	const op = "QuotaHandler.Reset"
+	log := h.logger.WithTrace(r.Context())
+
+	client := chi.URLParam(r, "client")
+	if err := h.service.ResetUsage(r.Context(), client); err != nil {
+		log.Error("failed to reset api quota usage", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	log.Info("api quota usage reset", "op", op, "client", client)
+
+	h.Get(w, r)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net"
+	"net/http"
+	"product-api/internal/logger"
+	"product-api/internal/ratelimit"
+	"product-api/internal/telemetry"
+	"strconv"
+	"time"
+)
+
+// RateLimiter limits the request rate of a traffic class, e.g. "auth", per user of authenticated requests
+// and per client IP otherwise. Token buckets allow bursts up to the limit and refill evenly over its period.
+// Requests over the limit are rejected with 429 Too Many Requests and Retry-After. If the limiter fails,
+// e.g. while Redis is unavailable, requests are served, so its outage does not take the API down.
+type RateLimiter struct {
+	limiter ratelimit.Limiter
+	class   string
+	limit   ratelimit.Limit
+	logger  logger.Logger
+}
+
+// NewRateLimiter creates a rate limiter of the class taking tokens from buckets of the limiter.
+func NewRateLimiter(limiter ratelimit.Limiter, class string, limit ratelimit.Limit, l logger.Logger) *RateLimiter {
+	return &RateLimiter{limiter: limiter, class: class, limit: limit, logger: l}
+}
+
+// Middleware serves requests within the rate limit and sets X-RateLimit-Limit, X-RateLimit-Remaining
+// and X-RateLimit-Reset (Unix time) headers. Must be placed after RealIPMiddleware, and after JWTMiddleware
+// to limit authenticated requests per user.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const op = "RateLimiter.Middleware"
+
+		key := callerID(r.Context())
+		if key == "anonymous" {
+			key = "ip:" + clientIP(r)
+		}
+		result, err := l.limiter.Allow(r.Context(), l.class+":"+key, l.limit)
+		if err != nil {
+			l.logger.WithTrace(r.Context()).Error("failed to check rate limit, serving request",
+				"op", op, "class", l.class, "backend", l.limiter.Name(), "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(result.ResetAfter).Unix(), 10))
+		if !result.Allowed {
+			telemetry.RecordRateLimitedRequest(r.Context(), l.class)
+			w.Header().Set("Retry-After", strconv.Itoa(max(int((result.RetryAfter+time.Second-1)/time.Second), 1)))
+			respondError(w, r, http.StatusTooManyRequests, ErrCodeRateLimited, "too many requests, retry later")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the IP address of the client, as set by RealIPMiddleware.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
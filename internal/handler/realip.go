@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// RealIPMiddleware creates middleware setting RemoteAddr to the client IP reported by a trusted proxy in the
+// True-Client-IP, X-Real-IP or X-Forwarded-For header, in that order. The headers are only read from requests
+// sent by an address in the trusted prefixes, so clients connecting directly cannot pick the IP their requests
+// are rate limited by. Of X-Forwarded-For, the rightmost address that is not a trusted proxy is taken, addresses
+// left of it are sent by the client. Must be placed before the rate limiters and the request log.
+func RealIPMiddleware(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip, ok := forwardedIP(r, trusted); ok {
+				r.RemoteAddr = ip.String()
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedIP returns the client IP forwarded by a trusted proxy, false if the request was not sent by one
+// or carries no valid client IP.
+func forwardedIP(r *http.Request, trusted []netip.Prefix) (netip.Addr, bool) {
+	peer, err := netip.ParseAddr(clientIP(r))
+	if err != nil || !isTrustedProxy(peer, trusted) {
+		return netip.Addr{}, false
+	}
+	for _, header := range []string{"True-Client-IP", "X-Real-IP"} {
+		if ip, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get(header))); err == nil {
+			return ip.Unmap(), true
+		}
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		if !isTrustedProxy(ip, trusted) {
+			return ip.Unmap(), true
+		}
+	}
+	return netip.Addr{}, false
+}
+
+// isTrustedProxy reports whether the address is in one of the trusted prefixes.
+func isTrustedProxy(ip netip.Addr, trusted []netip.Prefix) bool {
+	ip = ip.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"product-api/internal/handler"
+	"product-api/internal/logger"
+	"product-api/internal/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var trustedProxies = []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("2001:db8::/32")}
+
+func TestRealIPMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{name: "direct client", remoteAddr: "203.0.113.7:51234", want: "203.0.113.7:51234"},
+		{name: "headers of an untrusted sender are ignored", remoteAddr: "203.0.113.7:51234",
+			headers: map[string]string{"True-Client-IP": "198.51.100.1", "X-Real-IP": "198.51.100.2", "X-Forwarded-For": "198.51.100.3"},
+			want:    "203.0.113.7:51234"},
+		{name: "True-Client-IP of a trusted proxy", remoteAddr: "10.1.2.3:443",
+			headers: map[string]string{"True-Client-IP": "198.51.100.1", "X-Real-IP": "198.51.100.2"}, want: "198.51.100.1"},
+		{name: "X-Real-IP of a trusted proxy", remoteAddr: "10.1.2.3:443",
+			headers: map[string]string{"X-Real-IP": "198.51.100.2"}, want: "198.51.100.2"},
+		{name: "X-Forwarded-For skips trusted proxies and forged addresses", remoteAddr: "10.1.2.3:443",
+			headers: map[string]string{"X-Forwarded-For": "192.0.2.99, 198.51.100.3, 10.9.9.9"}, want: "198.51.100.3"},
+		{name: "IPv6 proxies only", remoteAddr: "[2001:db8::1]:443",
+			headers: map[string]string{"X-Forwarded-For": "2001:db8:ffff::5"}, want: "[2001:db8::1]:443"},
+		{name: "IPv6 client", remoteAddr: "[2001:db8::1]:443",
+			headers: map[string]string{"X-Forwarded-For": "2a00:1450::1"}, want: "2a00:1450::1"},
+		{name: "invalid forwarded address", remoteAddr: "10.1.2.3:443",
+			headers: map[string]string{"X-Forwarded-For": "unknown"}, want: "10.1.2.3:443"},
+		{name: "only trusted proxies forwarded", remoteAddr: "10.1.2.3:443",
+			headers: map[string]string{"X-Forwarded-For": "10.4.5.6"}, want: "10.1.2.3:443"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := handler.RealIPMiddleware(trustedProxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/products", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRealIPMiddleware_RateLimitCannotBeBypassed(t *testing.T) {
+	limiter := handler.NewRateLimiter(ratelimit.NewMemoryLimiter(), "auth", ratelimit.Limit{Requests: 2, Per: time.Minute},
+		logger.NewSlogAdapter("local"))
+	h := handler.RealIPMiddleware(trustedProxies)(limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+	login := func(remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodPost, "/users/login", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// A direct client rotating X-Forwarded-For is limited by its own address
+	assert.Equal(t, http.StatusNoContent, login("203.0.113.7:1000", "198.51.100.1"))
+	assert.Equal(t, http.StatusNoContent, login("203.0.113.7:1001", "198.51.100.2"))
+	assert.Equal(t, http.StatusTooManyRequests, login("203.0.113.7:1002", "198.51.100.3"))
+
+	// Clients behind the proxy are limited separately
+	assert.Equal(t, http.StatusNoContent, login("10.0.0.1:443", "198.51.100.1"))
+	assert.Equal(t, http.StatusNoContent, login("10.0.0.1:443", "198.51.100.1"))
+	assert.Equal(t, http.StatusTooManyRequests, login("10.0.0.1:443", "198.51.100.1"))
+	assert.Equal(t, http.StatusNoContent, login("10.0.0.1:443", "198.51.100.2"))
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"product-api/internal/logger"
+	"runtime/debug"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// maxCapturedBodySize limits the request body kept for panic reports.
+const maxCapturedBodySize = 4 << 10
+
+// redactedValue replaces sensitive values in captured request bodies.
+const redactedValue = "[REDACTED]"
+
+// sensitiveFields are substrings of JSON field names whose values are redacted.
+var sensitiveFields = []string{"password", "token", "secret", "authorization", "api_key", "apikey"}
+
+// requestSnapshotKey is the key for storing the request snapshot in request context.
+const requestSnapshotKey contextKey = "requestSnapshot"
+
+// requestSnapshot collects request data for panic reports.
+// Inner middlewares fill it in, as their context changes are not visible to the recoverer.
+type requestSnapshot struct {
+	userID string
+	body   *bytes.Buffer // nil if body capture is disabled
+}
+
+// setSnapshotUserID records the authenticated user ID for panic reports.
+func setSnapshotUserID(ctx context.Context, userID string) {
+	if snapshot, ok := ctx.Value(requestSnapshotKey).(*requestSnapshot); ok {
+		snapshot.userID = userID
+	}
+}
+
+// RecovererMiddleware creates middleware that recovers from panics in handlers.
+// Logs the panic with its stack, reports it to Sentry tagged with route, user and request ID,
+// and responds with 500 Internal Server Error.
+// If captureBody is true, the request body (up to 4 KiB, with sensitive fields redacted)
+// is attached to the report.
+// Must be placed after Sentry and RequestID middlewares.
+func RecovererMiddleware(l logger.Logger, captureBody bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			snapshot := &requestSnapshot{}
+			if captureBody && r.Body != nil {
+				snapshot.body = &bytes.Buffer{}
+				r.Body = &capturingReader{ReadCloser: r.Body, buf: snapshot.body}
+			}
+			r = r.WithContext(context.WithValue(r.Context(), requestSnapshotKey, snapshot))
+
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					// Abort the response without reporting, as net/http does
+					panic(rec)
+				}
+
+				reportPanic(l, r, snapshot, rec)
+
+				if r.Header.Get("Connection") != "Upgrade" {
+					respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// reportPanic logs the recovered panic and sends it to Sentry.
+func reportPanic(l logger.Logger, r *http.Request, snapshot *requestSnapshot, rec any) {
+	ctx := r.Context()
+	route := r.URL.Path
+	if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+		route = rctx.RoutePattern()
+	}
+	requestID := middleware.GetReqID(ctx)
+
+	args := []any{
+		"panic", fmt.Sprint(rec),
+		"method", r.Method,
+		"route", route,
+		"request_id", requestID,
+		"user_id", snapshot.userID,
+		"stack", string(debug.Stack()),
+	}
+	var body string
+	if snapshot.body != nil {
+		body = redactBody(snapshot.body.Bytes())
+		args = append(args, "request_body", body)
+	}
+	l.WithTrace(ctx).Error("panic recovered", args...)
+
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		return
+	}
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTag("route", route)
+		scope.SetTag("request_id", requestID)
+		if snapshot.userID != "" {
+			scope.SetUser(sentry.User{ID: snapshot.userID})
+		}
+		if snapshot.body != nil {
+			scope.SetContext("request_body", sentry.Context{"body": body})
+		}
+		hub.RecoverWithContext(ctx, rec)
+	})
+}
+
+// redactBody returns the captured JSON body with sensitive field values redacted.
+// Bodies that are not valid JSON (including truncated ones) are described only by their size.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Sprintf("[%d bytes, not JSON or truncated]", len(body))
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+	return string(redacted)
+}
+
+// redactValue recursively replaces values of sensitive fields in decoded JSON.
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if isSensitiveField(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// isSensitiveField reports whether a JSON field may contain credentials.
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, field := range sensitiveFields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// capturingReader copies up to maxCapturedBodySize bytes read from the body into buf.
+type capturingReader struct {
+	io.ReadCloser
+	buf *bytes.Buffer
+}
+
+func (c *capturingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	if remaining := maxCapturedBodySize - c.buf.Len(); remaining > 0 && n > 0 {
+		c.buf.Write(p[:min(n, remaining)])
+	}
+	return n, err
+}
@@ -0,0 +1,85 @@
+package handler_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/handler"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// panickingRouter routes POST /products/{id} to a handler reading the body and panicking under RecovererMiddleware.
+func panickingRouter(log recordingLogger, captureBody bool) http.Handler {
+	r := chi.NewRouter()
+	r.Use(handler.RecovererMiddleware(log, captureBody))
+	r.Post("/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		panic("nil map")
+	})
+	return r
+}
+
+func TestRecovererMiddleware_ReportsPanics(t *testing.T) {
+	log := newRecordingLogger()
+	req := httptest.NewRequest(http.MethodPost, "/products/42", strings.NewReader(`{"price": 10, "api_key": "k"}`))
+	rec, resp := serveError[any](t, panickingRouter(log, true), req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "internal_error", resp.Code)
+	require.Len(t, *log.records, 1)
+	record := (*log.records)[0]
+	assert.Equal(t, "error", record.level)
+	assert.Equal(t, "panic recovered", record.msg)
+	assert.Equal(t, "nil map", record.args["panic"])
+	assert.Equal(t, "/products/{id}", record.args["route"])
+	assert.Equal(t, resp.RequestID, record.args["request_id"], "the report is found by the ID the client got")
+	assert.Contains(t, record.args["stack"], "recoverer_test.go")
+	assert.JSONEq(t, `{"price": 10, "api_key": "[REDACTED]"}`, record.args["request_body"].(string))
+}
+
+func TestRecovererMiddleware_DoesNotCaptureBodiesByDefault(t *testing.T) {
+	log := newRecordingLogger()
+	rec := httptest.NewRecorder()
+	panickingRouter(log, false).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/products/42", strings.NewReader(`{"price": 10}`)))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Len(t, *log.records, 1)
+	assert.NotContains(t, (*log.records)[0].args, "request_body")
+}
+
+func TestRecovererMiddleware_PassesOnAbortedResponses(t *testing.T) {
+	log := newRecordingLogger()
+	h := handler.RecovererMiddleware(log, false)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/admin/products/export", nil))
+	})
+	assert.Empty(t, *log.records, "aborted responses are not reported")
+}
+
+func TestRecovererMiddleware_ReportsToSentry(t *testing.T) {
+	var events []*sentry.Event
+	client, err := sentry.NewClient(sentry.ClientOptions{BeforeSend: func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+		events = append(events, event)
+		return nil
+	}})
+	require.NoError(t, err)
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	req := httptest.NewRequest(http.MethodPost, "/products/42", strings.NewReader(`{"password": "hunter22"}`))
+	req = req.WithContext(sentry.SetHubOnContext(req.Context(), hub))
+	_, resp := serveError[any](t, panickingRouter(newRecordingLogger(), true), req)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "/products/{id}", events[0].Tags["route"])
+	assert.Equal(t, resp.RequestID, events[0].Tags["request_id"])
+	assert.Equal(t, sentry.Context{"body": `{"password":"[REDACTED]"}`}, events[0].Contexts["request_body"])
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"product-api/internal/logger"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const (
+	// forwardedRegionHeader names the region that forwarded a request to the primary region.
+	forwardedRegionHeader = "X-Forwarded-Region"
+	// forwardedRegionSignatureHeader carries the HMAC-SHA256 of the forwarded region with the shared secret.
+	forwardedRegionSignatureHeader = "X-Forwarded-Region-Signature"
+)
+
+// RegionMiddleware creates middleware adding the region of the instance to responses as X-Region.
+// Outside the primary write region of an active/passive deployment (primary is not nil), writes and order
+// requests are forwarded to the API in the primary region, so orders are always read where they were just
+// written; other reads, e.g. catalog browsing, are served locally. Forwarded responses carry the primary region.
+// Forwarded requests name the region they come from in X-Forwarded-Region, signed with the secret shared by
+// the regions. Unsigned or wrongly signed X-Forwarded-Region headers, e.g. sent by clients, are removed.
+func RegionMiddleware(region string, primary *url.URL, secret []byte, l logger.Logger) func(http.Handler) http.Handler {
+	var proxy *httputil.ReverseProxy
+	if primary != nil {
+		proxy = &httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				pr.SetURL(primary)
+				pr.SetXForwarded()
+				pr.Out.Header.Set(forwardedRegionHeader, region)
+				pr.Out.Header.Set(forwardedRegionSignatureHeader, signRegion(secret, region))
+			},
+			Transport: otelhttp.NewTransport(http.DefaultTransport), // Continues the trace in the primary region
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				l.WithTrace(r.Context()).Error("failed to forward request to the primary region",
+					"op", "RegionMiddleware", "region", region, "primary", primary.Host, "error", err)
+				respondError(w, r, http.StatusBadGateway, ErrCodeUpstreamFailed, "primary region unavailable")
+			},
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			from := r.Header.Get(forwardedRegionHeader)
+			if from != "" && !validRegionSignature(secret, from, r.Header.Get(forwardedRegionSignatureHeader)) {
+				l.WithTrace(r.Context()).Warn("removed unsigned forwarded region header",
+					"op", "RegionMiddleware", "region", region, "from", from)
+				from = ""
+			}
+			if from == "" {
+				r.Header.Del(forwardedRegionHeader)
+				r.Header.Del(forwardedRegionSignatureHeader)
+			}
+
+			if proxy == nil || !routedToPrimary(r) {
+				if region != "" {
+					w.Header().Set("X-Region", region)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			if from != "" {
+				// The primary region forwarded it back, the regions disagree about which is primary
+				l.WithTrace(r.Context()).Error("request forwarded between passive regions",
+					"op", "RegionMiddleware", "region", region, "from", from)
+				respondError(w, r, http.StatusLoopDetected, ErrCodeRegionLoop, "request forwarded between passive regions")
+				return
+			}
+			proxy.ServeHTTP(w, r)
+		})
+	}
+}
+
+// signRegion returns the hex HMAC-SHA256 of the region with the secret.
+func signRegion(secret []byte, region string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(region))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validRegionSignature reports whether the signature is the one of the region. Without a secret,
+// no forwarded region is accepted.
+func validRegionSignature(secret []byte, region, signature string) bool {
+	return len(secret) > 0 && hmac.Equal([]byte(signature), []byte(signRegion(secret, region)))
+}
+
+// routedToPrimary reports whether the request must be served by the primary region:
+// writes, and order reads, which must see orders placed moments ago.
+func routedToPrimary(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return true
+	}
+	for _, prefix := range []string{"/orders", "/admin/orders", "/admin/refund-requests"} {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package handler_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"product-api/internal/handler"
+	"product-api/internal/logger"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var regionSecret = []byte("region-secret")
+
+func regionSignature(region string) string {
+	mac := hmac.New(sha256.New, regionSecret)
+	mac.Write([]byte(region))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newPrimaryRegion starts the API of the primary region, recording the forwarded region headers it receives.
+func newPrimaryRegion(t *testing.T) (*url.URL, *http.Header) {
+	t.Helper()
+	received := &http.Header{}
+	local := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*received = r.Header.Clone()
+		w.WriteHeader(http.StatusCreated)
+	})
+	primary := httptest.NewServer(handler.RegionMiddleware("us-east-1", nil, regionSecret, logger.NewSlogAdapter("local"))(local))
+	t.Cleanup(primary.Close)
+	u, err := url.Parse(primary.URL)
+	require.NoError(t, err)
+	return u, received
+}
+
+// servedLocally answers 200 and marks the response as served by the instance itself.
+var servedLocally = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Served-Locally", "true")
+	w.WriteHeader(http.StatusOK)
+})
+
+func TestRegionMiddleware_ForwardsWrites(t *testing.T) {
+	primary, received := newPrimaryRegion(t)
+	h := handler.RegionMiddleware("eu-west-1", primary, regionSecret, logger.NewSlogAdapter("local"))(servedLocally)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "us-east-1", rec.Header().Get("X-Region"))
+	assert.Empty(t, rec.Header().Get("X-Served-Locally"))
+	assert.Equal(t, "eu-west-1", received.Get("X-Forwarded-Region"))
+	assert.Equal(t, regionSignature("eu-west-1"), received.Get("X-Forwarded-Region-Signature"))
+}
+
+func TestRegionMiddleware_ServesReadsLocally(t *testing.T) {
+	primary, received := newPrimaryRegion(t)
+	h := handler.RegionMiddleware("eu-west-1", primary, regionSecret, logger.NewSlogAdapter("local"))(servedLocally)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/products", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "eu-west-1", rec.Header().Get("X-Region"))
+	assert.Equal(t, "true", rec.Header().Get("X-Served-Locally"))
+	assert.Empty(t, *received)
+}
+
+func TestRegionMiddleware_DetectsLoops(t *testing.T) {
+	primary, received := newPrimaryRegion(t)
+	h := handler.RegionMiddleware("eu-west-1", primary, regionSecret, logger.NewSlogAdapter("local"))(servedLocally)
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set("X-Forwarded-Region", "ap-south-1")
+	req.Header.Set("X-Forwarded-Region-Signature", regionSignature("ap-south-1"))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusLoopDetected, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"region_loop"`)
+	assert.Empty(t, *received)
+}
+
+func TestRegionMiddleware_RemovesUnsignedRegion(t *testing.T) {
+	primary, received := newPrimaryRegion(t)
+	h := handler.RegionMiddleware("eu-west-1", primary, regionSecret, logger.NewSlogAdapter("local"))(servedLocally)
+
+	// A client cannot make the passive region refuse its writes, nor pass a region on to the primary region
+	for _, signature := range []string{"", "forged", regionSignature("eu-west-1")} {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set("X-Forwarded-Region", "ap-south-1")
+		req.Header.Set("X-Forwarded-Region-Signature", signature)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusCreated, rec.Code, signature)
+		assert.Equal(t, "eu-west-1", received.Get("X-Forwarded-Region"), signature)
+	}
+}
+
+func TestRegionMiddleware_PrimaryRegionServesRequests(t *testing.T) {
+	var forwarded []string
+	h := handler.RegionMiddleware("us-east-1", nil, regionSecret, logger.NewSlogAdapter("local"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			forwarded = append(forwarded, r.Header.Get("X-Forwarded-Region"))
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+	signed := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	signed.Header.Set("X-Forwarded-Region", "eu-west-1")
+	signed.Header.Set("X-Forwarded-Region-Signature", regionSignature("eu-west-1"))
+	forged := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	forged.Header.Set("X-Forwarded-Region", "eu-west-1")
+
+	for _, req := range []*http.Request{httptest.NewRequest(http.MethodPost, "/orders", nil), signed, forged} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "us-east-1", rec.Header().Get("X-Region"))
+	}
+	assert.Equal(t, []string{"", "eu-west-1", ""}, forwarded)
+}
+
+func TestRegionMiddleware_NoSecretAcceptsNoRegion(t *testing.T) {
+	var forwarded string
+	h := handler.RegionMiddleware("us-east-1", nil, nil, logger.NewSlogAdapter("local"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			forwarded = r.Header.Get("X-Forwarded-Region")
+		}))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set("X-Forwarded-Region", "eu-west-1")
+	req.Header.Set("X-Forwarded-Region-Signature", "")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Empty(t, forwarded)
+}
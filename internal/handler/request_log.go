@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"product-api/internal/logger"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestLogMiddleware creates middleware that logs every request with its method, path, status, latency,
+// request ID and authenticated user, with the trace ID and route of the request. Server errors are logged as warnings.
+// If bodyLimit is positive, the request body read by the handler is logged with responses that are not 2xx,
+// with sensitive fields such as passwords redacted and cut to bodyLimit bytes; bodies over 4 KiB and bodies
+// that are not JSON are described by their size only.
+// Must be placed after Recoverer (which records the user) and OpenTelemetry middlewares.
+func RequestLogMiddleware(l logger.Logger, bodyLimit int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			var body *bytes.Buffer
+			if bodyLimit > 0 && r.Body != nil {
+				body = &bytes.Buffer{}
+				r.Body = &capturingReader{ReadCloser: r.Body, buf: body}
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK // Nothing was written, net/http responds 200
+			}
+			args := []any{
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"duration_ms", time.Since(start).Milliseconds(),
+				"bytes", ww.BytesWritten(),
+				"request_id", middleware.GetReqID(r.Context()),
+			}
+			if userID := snapshotUserID(r.Context()); userID != "" {
+				args = append(args, "user_id", userID)
+			}
+			if body != nil && (status < 200 || status > 299) {
+				args = append(args, "request_body", truncateBody(redactBody(body.Bytes()), bodyLimit))
+			}
+
+			log := l.WithTrace(r.Context())
+			if status >= http.StatusInternalServerError {
+				log.Warn("request handled", args...)
+				return
+			}
+			log.Info("request handled", args...)
+		})
+	}
+}
+
+// snapshotUserID returns the authenticated user ID recorded for the request, empty if there is none.
+func snapshotUserID(ctx context.Context) string {
+	if snapshot, ok := ctx.Value(requestSnapshotKey).(*requestSnapshot); ok {
+		return snapshot.userID
+	}
+	return ""
+}
+
+// truncateBody cuts the redacted body to limit bytes, without splitting a UTF-8 character.
+func truncateBody(body string, limit int) string {
+	if len(body) <= limit {
+		return body
+	}
+	return strings.ToValidUTF8(body[:limit], "") + "...[truncated]"
+}
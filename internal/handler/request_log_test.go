@@ -0,0 +1,114 @@
+package handler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/handler"
+	"product-api/internal/logger"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logRecord is a message logged by recordingLogger with its arguments by key.
+type logRecord struct {
+	level string
+	msg   string
+	args  map[string]any
+}
+
+// recordingLogger keeps logged messages in memory.
+type recordingLogger struct {
+	records *[]logRecord
+}
+
+func newRecordingLogger() recordingLogger {
+	return recordingLogger{records: &[]logRecord{}}
+}
+
+func (l recordingLogger) log(level, msg string, args []any) {
+	record := logRecord{level: level, msg: msg, args: make(map[string]any)}
+	for i := 0; i+1 < len(args); i += 2 {
+		record.args[args[i].(string)] = args[i+1]
+	}
+	*l.records = append(*l.records, record)
+}
+
+func (l recordingLogger) Info(msg string, args ...any)            { l.log("info", msg, args) }
+func (l recordingLogger) Warn(msg string, args ...any)            { l.log("warn", msg, args) }
+func (l recordingLogger) Error(msg string, args ...any)           { l.log("error", msg, args) }
+func (l recordingLogger) Debug(msg string, args ...any)           { l.log("debug", msg, args) }
+func (l recordingLogger) WithTrace(context.Context) logger.Logger { return l }
+
+// logRequest serves a request with the body by a handler reading it and responding with the status,
+// and returns the logged record of the request.
+func logRequest(t *testing.T, bodyLimit, status int, body string) logRecord {
+	t.Helper()
+	log := newRecordingLogger()
+	h := handler.RequestLogMiddleware(log, bodyLimit)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users/login", strings.NewReader(body)))
+
+	require.Len(t, *log.records, 1)
+	record := (*log.records)[0]
+	assert.Equal(t, "request handled", record.msg)
+	assert.Equal(t, status, record.args["status"])
+	return record
+}
+
+func TestRequestLogMiddleware_LogsBodiesOfFailedRequests(t *testing.T) {
+	const body = `{"email": "user@example.com", "password": "hunter22"}`
+
+	for _, status := range []int{http.StatusOK, http.StatusCreated, http.StatusNoContent} {
+		record := logRequest(t, 256, status, body)
+		assert.NotContains(t, record.args, "request_body", "status %d", status)
+		assert.Equal(t, "info", record.level)
+	}
+
+	record := logRequest(t, 256, http.StatusUnauthorized, body)
+	assert.Contains(t, record.args, "request_body")
+	assert.Equal(t, "info", record.level)
+
+	record = logRequest(t, 256, http.StatusInternalServerError, body)
+	assert.Contains(t, record.args, "request_body")
+	assert.Equal(t, "warn", record.level, "server errors are warnings")
+
+	record = logRequest(t, 0, http.StatusBadRequest, body)
+	assert.NotContains(t, record.args, "request_body", "body logging is disabled")
+}
+
+func TestRequestLogMiddleware_RedactsSensitiveFields(t *testing.T) {
+	record := logRequest(t, 1024, http.StatusBadRequest,
+		`{"email": "user@example.com", "password": "hunter22", "devices": [{"push_token": "abc123", "name": "phone"}]}`)
+
+	logged := record.args["request_body"].(string)
+	assert.NotContains(t, logged, "hunter22")
+	assert.NotContains(t, logged, "abc123")
+	assert.JSONEq(t, `{"email": "user@example.com", "password": "[REDACTED]", "devices": [{"push_token": "[REDACTED]", "name": "phone"}]}`, logged)
+}
+
+func TestRequestLogMiddleware_TruncatesWithoutSplittingCharacters(t *testing.T) {
+	// The limit ends after the first byte of the second "é"
+	record := logRequest(t, 11, http.StatusBadRequest, `{"name":"ééééé"}`)
+
+	logged := record.args["request_body"].(string)
+	assert.True(t, utf8.ValidString(logged), "%q", logged)
+	assert.Equal(t, `{"name":"é...[truncated]`, logged)
+}
+
+func TestRequestLogMiddleware_LogsSizeOfOtherBodies(t *testing.T) {
+	record := logRequest(t, 256, http.StatusBadRequest, "email=user%40example.com&password=hunter22")
+	assert.Equal(t, "[42 bytes, not JSON or truncated]", record.args["request_body"])
+
+	// Bodies over 4 KiB are captured in part, so they cannot be redacted
+	record = logRequest(t, 256, http.StatusBadRequest, `{"password": "`+strings.Repeat("x", 5000)+`"}`)
+	assert.Equal(t, "[4096 bytes, not JSON or truncated]", record.args["request_body"])
+}
@@ -0,0 +1,356 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"product-api/internal/scim"
+	"product-api/internal/service"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// SCIM list page size limits.
+const (
+	defaultSCIMCount = 100
+	maxSCIMCount     = 200
+)
+
+// SCIMHandler handles SCIM 2.0 user provisioning requests from enterprise identity providers.
+type SCIMHandler struct {
+	service *service.UsersService
+	mapper  scim.Mapper
+	logger  logger.Logger
+}
+
+// NewSCIMHandler creates a new SCIM handler.
+// baseURL is the public URL of the SCIM root used in resource locations.
+func NewSCIMHandler(s *service.UsersService, baseURL string, l logger.Logger) *SCIMHandler {
+	return &SCIMHandler{service: s, mapper: scim.Mapper{BaseURL: baseURL}, logger: l}
+}
+
+// ListUsers godoc
+// @Summary List users (SCIM)
+// @Tags scim
+// @Produce  json
+// @Param   filter      query  string  false  "Filter, e.g. userName eq \"john\""
+// @Param   startIndex  query  int     false  "1-based index of the first result"
+// @Param   count       query  int     false  "Maximum number of results"
+// @Security ApiKeyAuth
+// @Success 200  {object}  scim.ListResponse
+// @Failure 400  {object}  scim.Error
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Router /scim/v2/Users [get]
+func (h *SCIMHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	const op = "SCIMHandler.ListUsers"
+	log := h.logger.WithTrace(r.Context())
+
+	q := r.URL.Query()
+	startIndex, err := parsePositiveInt(q.Get("startIndex"), 1)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalidValue", "startIndex must be a positive integer")
+		return
+	}
+	count, err := parsePositiveInt(q.Get("count"), defaultSCIMCount)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalidValue", "count must be a positive integer")
+		return
+	}
+	count = min(count, maxSCIMCount)
+
+	filter, err := scim.ParseFilter(q.Get("filter"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalidFilter", err.Error())
+		return
+	}
+
+	repoFilter := repository.UserFilter{ExternalID: filter.ExternalID, Email: filter.Email}
+	if filter.UserName != "" {
+		if strings.Contains(filter.UserName, "@") {
+			repoFilter.Email = filter.UserName
+		} else {
+			repoFilter.Username = strings.ToLower(filter.UserName)
+		}
+	}
+
+	users, total, err := h.service.ListUsers(r.Context(), repoFilter, startIndex-1, count)
+	if err != nil {
+		log.Error("failed to list users", "op", op, "error", err)
+		h.respondError(w, http.StatusInternalServerError, "", "internal server error")
+		return
+	}
+
+	resp := scim.ListResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(users),
+		Resources:    make([]scim.User, 0, len(users)),
+	}
+	for i := range users {
+		resp.Resources = append(resp.Resources, h.mapper.ToResource(&users[i]))
+	}
+
+	h.respond(w, r, http.StatusOK, resp)
+}
+
+// GetUser godoc
+// @Summary Get a user (SCIM)
+// @Tags scim
+// @Produce  json
+// @Param   id   path      string  true  "User ID"
+// @Security ApiKeyAuth
+// @Success 200  {object}  scim.User
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 404  {object}  scim.Error
+// @Router /scim/v2/Users/{id} [get]
+func (h *SCIMHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.loadUser(w, r)
+	if !ok {
+		return
+	}
+	h.respond(w, r, http.StatusOK, h.mapper.ToResource(user))
+}
+
+// CreateUser godoc
+// @Summary Provision a user (SCIM)
+// @Tags scim
+// @Accept  json
+// @Produce  json
+// @Param   user  body      scim.User  true  "SCIM user"
+// @Security ApiKeyAuth
+// @Success 201  {object}  scim.User
+// @Failure 400  {object}  scim.Error
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 409  {object}  scim.Error
+// @Router /scim/v2/Users [post]
+func (h *SCIMHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	const op = "SCIMHandler.CreateUser"
+	log := h.logger.WithTrace(r.Context())
+
+	var res scim.User
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalidSyntax", "invalid request body")
+		return
+	}
+
+	var mapped domain.User
+	if err := h.mapper.Apply(&res, &mapped); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalidValue", err.Error())
+		return
+	}
+
+	user, err := h.service.ProvisionUser(r.Context(), service.ProvisionInput{
+		ExternalID: mapped.ExternalID,
+		Email:      mapped.Email,
+		Username:   mapped.Username,
+		Firstname:  mapped.Firstname,
+		Lastname:   mapped.Lastname,
+		Birthdate:  mapped.Birthdate,
+		Active:     mapped.IsActive,
+	})
+	if err != nil {
+		if !h.respondServiceError(w, err) {
+			log.Error("failed to provision user", "op", op, "error", err)
+			h.respondError(w, http.StatusInternalServerError, "", "internal server error")
+		}
+		return
+	}
+
+	log.Info("user provisioned", "op", op, "user_id", user.ID, "client", r.Context().Value(APIKeyNameKey))
+	h.respond(w, r, http.StatusCreated, h.mapper.ToResource(user))
+}
+
+// ReplaceUser godoc
+// @Summary Replace a user (SCIM)
+// @Tags scim
+// @Accept  json
+// @Produce  json
+// @Param   id    path      string     true  "User ID"
+// @Param   user  body      scim.User  true  "SCIM user"
+// @Security ApiKeyAuth
+// @Success 200  {object}  scim.User
+// @Failure 400  {object}  scim.Error
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 404  {object}  scim.Error
+// @Failure 409  {object}  scim.Error
+// @Router /scim/v2/Users/{id} [put]
+func (h *SCIMHandler) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	var res scim.User
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalidSyntax", "invalid request body")
+		return
+	}
+
+	user, ok := h.loadUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.mapper.Apply(&res, user); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalidValue", err.Error())
+		return
+	}
+
+	h.saveUser(w, r, user)
+}
+
+// PatchUser godoc
+// @Summary Patch a user (SCIM)
+// @Description Supports add/replace of active, userName, externalId, name and emails. Setting active=false deprovisions the user.
+// @Tags scim
+// @Accept  json
+// @Produce  json
+// @Param   id     path      string             true  "User ID"
+// @Param   patch  body      scim.PatchRequest  true  "SCIM patch operations"
+// @Security ApiKeyAuth
+// @Success 200  {object}  scim.User
+// @Failure 400  {object}  scim.Error
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 404  {object}  scim.Error
+// @Failure 409  {object}  scim.Error
+// @Router /scim/v2/Users/{id} [patch]
+func (h *SCIMHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	var req scim.PatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalidSyntax", "invalid request body")
+		return
+	}
+
+	user, ok := h.loadUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.mapper.ApplyPatch(req.Operations, user); err != nil {
+		scimType := "invalidValue"
+		if errors.Is(err, scim.ErrInvalidPath) {
+			scimType = "invalidPath"
+		}
+		h.respondError(w, http.StatusBadRequest, scimType, err.Error())
+		return
+	}
+
+	h.saveUser(w, r, user)
+}
+
+// DeleteUser godoc
+// @Summary Deprovision a user (SCIM)
+// @Description Deactivates the user; order history is retained.
+// @Tags scim
+// @Param   id   path      string  true  "User ID"
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 404  {object}  scim.Error
+// @Router /scim/v2/Users/{id} [delete]
+func (h *SCIMHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	const op = "SCIMHandler.DeleteUser"
+	log := h.logger.WithTrace(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusNotFound, "", "user not found")
+		return
+	}
+
+	if err := h.service.SetActive(r.Context(), id, false); err != nil {
+		if !h.respondServiceError(w, err) {
+			log.Error("failed to deprovision user", "op", op, "error", err)
+			h.respondError(w, http.StatusInternalServerError, "", "internal server error")
+		}
+		return
+	}
+
+	log.Info("user deprovisioned", "op", op, "user_id", id, "client", r.Context().Value(APIKeyNameKey))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadUser loads the user identified by the {id} URL parameter.
+// Writes an error response and returns false if the user cannot be loaded.
+func (h *SCIMHandler) loadUser(w http.ResponseWriter, r *http.Request) (*domain.User, bool) {
+	const op = "SCIMHandler.loadUser"
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusNotFound, "", "user not found")
+		return nil, false
+	}
+
+	user, err := h.service.GetUser(r.Context(), id)
+	if err != nil {
+		if !h.respondServiceError(w, err) {
+			h.logger.WithTrace(r.Context()).Error("failed to get user", "op", op, "error", err)
+			h.respondError(w, http.StatusInternalServerError, "", "internal server error")
+		}
+		return nil, false
+	}
+	return user, true
+}
+
+// saveUser saves changes made by PUT/PATCH and writes the updated resource.
+func (h *SCIMHandler) saveUser(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	const op = "SCIMHandler.saveUser"
+
+	if err := h.service.UpdateUser(r.Context(), user); err != nil {
+		if !h.respondServiceError(w, err) {
+			h.logger.WithTrace(r.Context()).Error("failed to update user", "op", op, "error", err)
+			h.respondError(w, http.StatusInternalServerError, "", "internal server error")
+		}
+		return
+	}
+
+	h.respond(w, r, http.StatusOK, h.mapper.ToResource(user))
+}
+
+// respondServiceError writes a SCIM error for known service errors.
+// Returns false if the error is unknown and must be handled as internal.
+func (h *SCIMHandler) respondServiceError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		h.respondError(w, http.StatusNotFound, "", "user not found")
+	case errors.Is(err, service.ErrUserAlreadyExists),
+		errors.Is(err, service.ErrUsernameTaken),
+		errors.Is(err, service.ErrExternalIDTaken):
+		h.respondError(w, http.StatusConflict, "uniqueness", err.Error())
+	case errors.Is(err, service.ErrUnderage),
+		errors.Is(err, domain.ErrUsernameInvalid),
+		errors.Is(err, domain.ErrUsernameReserved):
+		h.respondError(w, http.StatusBadRequest, "invalidValue", err.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *SCIMHandler) respond(w http.ResponseWriter, r *http.Request, status int, body any) {
+	w.Header().Set("Content-Type", scim.ContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to encode scim response", "error", err)
+	}
+}
+
+func (h *SCIMHandler) respondError(w http.ResponseWriter, status int, scimType, detail string) {
+	w.Header().Set("Content-Type", scim.ContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(scim.NewError(status, scimType, detail))
+}
+
+// parsePositiveInt parses an optional positive integer query parameter.
+func parsePositiveInt(value string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, errors.New("must be a positive integer")
+	}
+	return n, nil
+}
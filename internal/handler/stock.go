@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	customvalidator "product-api/pkg/validator"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// RecordStockMovementRequest contains data for a manual stock movement.
+type RecordStockMovementRequest struct {
+	Delta  int    `json:"delta" example:"50" validate:"required"` // Positive for incoming stock, negative for outgoing
+	Reason string `json:"reason" example:"receipt" validate:"required,oneof=receipt adjustment return"`
+	Note   string `json:"note" example:"Delivery #1042" validate:"max=500"`
+}
+
+// Stock movement history and drift check page size limits.
+const (
+	defaultStockHistoryLimit = 50
+	maxStockHistoryLimit     = 500
+	defaultStockDriftLimit   = 100
+	maxStockDriftLimit       = 1000
+	maxStockImportSize       = 16 << 20
+)
+
+// StockHandler handles HTTP requests related to the stock ledger.
+type StockHandler struct {
+	service *service.StockService
+	logger  logger.Logger
+}
+
+// NewStockHandler creates a new stock handler.
+func NewStockHandler(s *service.StockService, l logger.Logger) *StockHandler {
+	return &StockHandler{service: s, logger: l}
+}
+
+// RecordMovement godoc
+// @Summary Record a stock movement
+// @Description Appends a receipt, adjustment or return to the stock ledger and updates the product quantity.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   id  path  string  true  "Product ID"
+// @Param   movement  body  RecordStockMovementRequest  true  "Stock movement"
+// @Param   Idempotency-Key  header  string  false  "Unique key of the request; retries with the same key replay the first response"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 201  {object}  domain.StockMovement
+// @Failure 400  {object}  ErrorResponse "Invalid request body or product ID"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Product not found"
+// @Failure 409  {object}  ErrorResponse "Quantity would become negative or request with the same idempotency key in progress"
+// @Failure 422  {object}  ErrorResponse "Idempotency key was used with a different request"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/products/{id}/stock-movements [post]
+func (h *StockHandler) RecordMovement(w http.ResponseWriter, r *http.Request) {
+	const op = "StockHandler.RecordMovement"
+	log := h.logger.WithTrace(r.Context())
+
+	productID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid product ID")
+		return
+	}
+
+	var req RecordStockMovementRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	movement, err := h.service.RecordMovement(r.Context(), service.RecordMovementInput{
+		ProductID: productID,
+		Delta:     req.Delta,
+		Reason:    req.Reason,
+		Note:      req.Note,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidStockMovement):
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidStockMovement, "invalid stock movement")
+		case errors.Is(err, service.ErrProductNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeProductNotFound, "product not found")
+		case errors.Is(err, service.ErrInsufficientStock):
+			respondError(w, r, http.StatusConflict, ErrCodeInsufficientStock, "quantity would become negative")
+		default:
+			log.Error("failed to record stock movement", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(movement); err != nil {
+		log.Error("failed to encode stock movement response", "op", op, "error", err)
+	}
+}
+
+// History godoc
+// @Summary Get stock movements of a product
+// @Tags admin
+// @Produce  json
+// @Param   id  path  string  true  "Product ID"
+// @Param   limit  query  int  false  "Maximum number of movements (1-500, default 50)"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {array}   domain.StockMovement
+// @Failure 400  {object}  ErrorResponse "Invalid product ID or limit"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/products/{id}/stock-movements [get]
+func (h *StockHandler) History(w http.ResponseWriter, r *http.Request) {
+	const op = "StockHandler.History"
+	log := h.logger.WithTrace(r.Context())
+
+	productID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid product ID")
+		return
+	}
+
+	limit, err := parsePositiveInt(r.URL.Query().Get("limit"), defaultStockHistoryLimit)
+	if err != nil || limit > maxStockHistoryLimit {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid limit")
+		return
+	}
+
+	movements, err := h.service.History(r.Context(), productID, limit)
+	if err != nil {
+		log.Error("failed to get stock movements", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(movements); err != nil {
+		log.Error("failed to encode stock movements", "op", op, "error", err)
+	}
+}
+
+// CheckDrift godoc
+// @Summary Report products whose quantity differs from the stock ledger
+// @Tags admin
+// @Produce  json
+// @Param   limit  query  int  false  "Maximum number of products (1-1000, default 100)"
+// @Param   X-API-Key  header  string  true  "Admin or analyst API key"
+// @Success 200  {object}  service.StockDriftReport
+// @Failure 400  {object}  ErrorResponse "Invalid limit"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/stock/drift [get]
+func (h *StockHandler) CheckDrift(w http.ResponseWriter, r *http.Request) {
+	h.checkDrift(w, r, false)
+}
+
+// RepairDrift godoc
+// @Summary Rebuild product quantities from the stock ledger
+// @Description Sets the quantity of drifted products to the sum of their stock movements.
+// @Tags admin
+// @Produce  json
+// @Param   limit  query  int  false  "Maximum number of products (1-1000, default 100)"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  service.StockDriftReport
+// @Failure 400  {object}  ErrorResponse "Invalid limit"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/stock/drift/repair [post]
+func (h *StockHandler) RepairDrift(w http.ResponseWriter, r *http.Request) {
+	h.checkDrift(w, r, true)
+}
+
+// Import godoc
+// @Summary Import product quantities from CSV
+// @Description Applies a CSV file with "sku" and "quantity" columns, e.g. of the nightly warehouse sync, in batched transactions.
+// @Description SKUs are product barcodes, UPC-A codes match their EAN-13 form. In absolute mode quantities replace product quantities, in delta mode they are added.
+// @Description Every changed quantity is recorded in the stock ledger as an adjustment. Invalid rows, unknown SKUs
+// @Description and changes to a negative quantity fail without affecting other rows, and are listed in the report.
+// @Tags admin
+// @Accept  text/csv
+// @Produce  json
+// @Param   mode  query  string  false  "absolute (default) or delta"
+// @Param   file  body  string  true  "CSV file, e.g. sku,quantity\n4006381333931,25"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  service.StockImportReport
+// @Failure 400  {object}  ErrorResponse "Invalid CSV file or mode"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 413  {object}  ErrorResponse "File too large"
+// @Failure 500  {object}  ErrorResponse "Internal server error, earlier batches may have been applied"
+// @Router /admin/stock/import [post]
+func (h *StockHandler) Import(w http.ResponseWriter, r *http.Request) {
+	const op = "StockHandler.Import"
+	log := h.logger.WithTrace(r.Context())
+
+	rows, err := service.ParseStockImport(http.MaxBytesReader(w, r.Body, maxStockImportSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			respondError(w, r, http.StatusRequestEntityTooLarge, ErrCodePayloadTooLarge, "file too large")
+		case errors.Is(err, service.ErrInvalidStockImport):
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidStockImport, err.Error())
+		default:
+			log.Error("failed to read stock import", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	mode := cmp.Or(r.URL.Query().Get("mode"), service.StockImportAbsolute)
+	report, err := h.service.Import(r.Context(), rows, mode)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidStockImport) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidStockImport, err.Error())
+			return
+		}
+		// Rows of earlier batches stay applied
+		log.Error("failed to import stock", "op", op, "error", err, "updated", report.Updated)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	log.Info("stock imported", "op", op, "mode", mode, "rows", len(rows),
+		"updated", report.Updated, "unchanged", report.Unchanged, "failed", report.Failed)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Error("failed to encode stock import report", "op", op, "error", err)
+	}
+}
+
+// checkDrift reports (and optionally rebuilds) products whose quantity differs from the ledger.
+func (h *StockHandler) checkDrift(w http.ResponseWriter, r *http.Request, repair bool) {
+	const op = "StockHandler.checkDrift"
+	log := h.logger.WithTrace(r.Context())
+
+	limit, err := parsePositiveInt(r.URL.Query().Get("limit"), defaultStockDriftLimit)
+	if err != nil || limit > maxStockDriftLimit {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid limit")
+		return
+	}
+
+	report, err := h.service.CheckDrift(r.Context(), limit, repair)
+	if err != nil {
+		log.Error("failed to check stock drift", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Error("failed to encode stock drift report", "op", op, "error", err)
+	}
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// stockSnapshotCSVHeader lists columns of the CSV stock snapshot export.
+var stockSnapshotCSVHeader = []string{
+	"day", "product_id", "quantity", "received", "sold", "returned", "adjusted", "stockout_seconds",
+}
+
+// StockSnapshotHandler serves the daily stock snapshots to analysts.
+type StockSnapshotHandler struct {
+	service *service.StockSnapshotService
+	logger  logger.Logger
+}
+
+// NewStockSnapshotHandler creates a new stock snapshot handler.
+func NewStockSnapshotHandler(s *service.StockSnapshotService, l logger.Logger) *StockSnapshotHandler {
+	return &StockSnapshotHandler{service: s, logger: l}
+}
+
+// Export godoc
+// @Summary Export daily stock snapshots
+// @Description Streams the closing quantity of products per day (UTC) from the first to the last day, at most 366 days,
+// @Description with the units received, sold (net of cancellations), returned and adjusted and the seconds out of stock
+// @Description during the day, ordered by day and product, as CSV or JSON lines. Bundles are not snapshotted.
+// @Description Days are snapshotted shortly after they end (UTC); the current day is never included.
+// @Tags admin
+// @Produce  plain
+// @Param   from  query  string  true  "First day, e.g. 2026-01-01"
+// @Param   to  query  string  true  "Last day, e.g. 2026-03-31"
+// @Param   product_id  query  string  false  "Export only snapshots of this product"
+// @Param   format  query  string  false  "csv (default) or jsonl"
+// @Param   X-API-Key  header  string  true  "Admin or analyst API key"
+// @Success 200  {string}  string "One snapshot per line"
+// @Failure 400  {object}  ErrorResponse "Invalid days, product ID or format"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/stock/snapshots [get]
+func (h *StockSnapshotHandler) Export(w http.ResponseWriter, r *http.Request) {
+	const op = "StockSnapshotHandler.Export"
+	log := h.logger.WithTrace(r.Context())
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = exportFormatCSV
+	}
+	if format != exportFormatJSONLines && format != exportFormatCSV {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "format must be csv or jsonl")
+		return
+	}
+	from, fromErr := time.Parse(time.DateOnly, query.Get("from"))
+	to, toErr := time.Parse(time.DateOnly, query.Get("to"))
+	if fromErr != nil || toErr != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "from and to must be days (YYYY-MM-DD)")
+		return
+	}
+	var productID *uuid.UUID
+	if v := query.Get("product_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid product ID")
+			return
+		}
+		productID = &id
+	}
+
+	// The export outlives the server write timeout of regular requests
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn("failed to clear write deadline of export", "op", op, "error", err)
+	}
+
+	buf := bufio.NewWriter(w)
+	var encode func(*domain.StockSnapshot) error
+	if format == exportFormatCSV {
+		w.Header().Set("Content-Type", "text/csv")
+		cw := csv.NewWriter(buf)
+		encode = func(s *domain.StockSnapshot) error {
+			cw.Write(stockSnapshotCSVRecord(s))
+			cw.Flush()
+			return cw.Error()
+		}
+		cw.Write(stockSnapshotCSVHeader)
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(buf)
+		encode = func(s *domain.StockSnapshot) error { return enc.Encode(s) }
+	}
+
+	count := 0
+	err := h.service.Export(r.Context(), from, to, productID, func(s *domain.StockSnapshot) error {
+		if err := encode(s); err != nil {
+			return err
+		}
+		count++
+		if count%exportFlushInterval == 0 {
+			if err := buf.Flush(); err != nil {
+				return err
+			}
+			return rc.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSnapshotWindow) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidSnapshotWindow, "to must not be before from and at most 366 days later")
+			return
+		}
+		if count == 0 && r.Context().Err() == nil {
+			log.Error("failed to export stock snapshots", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+			return
+		}
+		// Part of the export was sent, abort the response so the client does not take it as complete
+		log.Error("stock snapshot export interrupted", "op", op, "exported", count, "error", err)
+		panic(http.ErrAbortHandler)
+	}
+	log.Info("stock snapshots exported", "op", op, "format", format, "snapshots", count)
+}
+
+// stockSnapshotCSVRecord returns the snapshot as a row of stockSnapshotCSVHeader columns.
+func stockSnapshotCSVRecord(s *domain.StockSnapshot) []string {
+	return []string{
+		s.Day.Format(time.DateOnly),
+		s.ProductID.String(),
+		strconv.Itoa(s.Quantity),
+		strconv.Itoa(s.Received),
+		strconv.Itoa(s.Sold),
+		strconv.Itoa(s.Returned),
+		strconv.Itoa(s.Adjusted),
+		strconv.Itoa(s.StockoutSeconds),
+	}
+}
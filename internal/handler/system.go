@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+)
+
+// SystemHandler handles HTTP requests related to the operational state of the application.
+type SystemHandler struct {
+	service *service.SystemService
+	config  map[string]string // Effective configuration with secrets masked, by environment variable name
+	logger  logger.Logger
+}
+
+// NewSystemHandler creates a new system handler reporting the redacted configuration.
+func NewSystemHandler(s *service.SystemService, config map[string]string, l logger.Logger) *SystemHandler {
+	return &SystemHandler{service: s, config: config, logger: l}
+}
+
+// Status godoc
+// @Summary Get the operational status of the application
+// @Description Checks the database pools and the object storage, and reports pool statistics, queue backlogs, the heartbeats
+// @Description of the background runners and the loaded notification templates for on-call diagnosis.
+// @Description Failed checks and runners without a run for three intervals are reported with status degraded.
+// @Tags admin
+// @Produce  json
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  service.SystemStatus
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/system/status [get]
+func (h *SystemHandler) Status(w http.ResponseWriter, r *http.Request) {
+	const op = "SystemHandler.Status"
+	log := h.logger.WithTrace(r.Context())
+
+	status, err := h.service.Status(r.Context())
+	if err != nil {
+		log.Error("failed to get system status", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	if status.Status != service.SystemStatusOK {
+		log.Warn("system status degraded", "op", op, "dependencies", status.Dependencies)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.Error("failed to encode system status", "op", op, "error", err)
+	}
+}
+
+// Config godoc
+// @Summary Get the effective configuration
+// @Description Returns the configuration this instance loaded, by environment variable name, including defaults.
+// @Description Secrets are masked as [REDACTED] and passwords in URLs as xxxxx; unset secrets are empty.
+// @Tags admin
+// @Produce  json
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  map[string]string
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Router /admin/system/config [get]
+func (h *SystemHandler) Config(w http.ResponseWriter, r *http.Request) {
+	const op = "SystemHandler.Config"
+	log := h.logger.WithTrace(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(h.config); err != nil {
+		log.Error("failed to encode configuration", "op", op, "error", err)
+	}
+}
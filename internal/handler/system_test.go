@@ -0,0 +1,92 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/handler"
+	"product-api/internal/notification"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"product-api/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// getSystemStatus serves GET /admin/system/status of an instance with the workers and decodes the status.
+func getSystemStatus(t *testing.T, workers *service.Workers) service.SystemStatus {
+	t.Helper()
+	refunds := mocks.NewMockRefundRequestRepository(t)
+	refunds.EXPECT().CountPending(mock.Anything).Return(0, nil, nil)
+	objects, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	templates := notification.NewTemplates(mocks.NewMockNotificationTemplateRepository(t), time.Hour, newRecordingLogger())
+	systems := handler.NewSystemHandler(service.NewSystemService(nil, refunds, objects, templates, workers), nil, newRecordingLogger())
+
+	rec := httptest.NewRecorder()
+	systems.Status(rec, httptest.NewRequest(http.MethodGet, "/admin/system/status", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var status service.SystemStatus
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&status))
+	return status
+}
+
+func TestSystemStatus_ReportsWorkerHeartbeats(t *testing.T) {
+	workers := service.NewWorkers()
+	idle := workers.Register("accounting", time.Hour)
+	failing := workers.Register("stock_snapshots", time.Hour)
+	workers.Register("bulk_operations", time.Hour) // Started, not run yet
+	before := time.Now()
+	idle.Beat(nil)
+	failing.Beat(nil)
+	failing.Beat(errors.New("connection refused"))
+
+	status := getSystemStatus(t, workers)
+
+	assert.Equal(t, service.SystemStatusOK, status.Status, "no worker stalled")
+	require.Len(t, status.Workers, 3)
+	accounting, bulk, snapshots := status.Workers[0], status.Workers[1], status.Workers[2]
+	assert.Equal(t, "accounting", accounting.Name)
+	assert.Equal(t, time.Hour.Milliseconds(), accounting.IntervalMs)
+	require.NotNil(t, accounting.LastRunAt)
+	assert.False(t, accounting.LastRunAt.Before(before))
+	assert.Equal(t, accounting.LastRunAt, accounting.LastSuccessAt)
+	assert.Empty(t, accounting.LastError)
+
+	assert.Equal(t, "bulk_operations", bulk.Name)
+	assert.Nil(t, bulk.LastRunAt)
+	assert.Nil(t, bulk.LastSuccessAt)
+	assert.False(t, bulk.Stalled, "the first run is due within its interval")
+
+	assert.Equal(t, "stock_snapshots", snapshots.Name)
+	require.NotNil(t, snapshots.LastRunAt)
+	require.NotNil(t, snapshots.LastSuccessAt)
+	assert.False(t, snapshots.LastRunAt.Before(*snapshots.LastSuccessAt), "the success is of the run before")
+	assert.Equal(t, "connection refused", snapshots.LastError)
+}
+
+func TestSystemStatus_DegradedByStalledWorker(t *testing.T) {
+	workers := service.NewWorkers()
+	workers.Register("payment_expiry", time.Millisecond).Beat(nil)
+	time.Sleep(5 * time.Millisecond) // Over three intervals without a run
+
+	status := getSystemStatus(t, workers)
+
+	assert.Equal(t, service.SystemStatusDegraded, status.Status)
+	require.Len(t, status.Workers, 1)
+	assert.True(t, status.Workers[0].Stalled)
+}
+
+func TestSystemStatus_WithoutWorkers(t *testing.T) {
+	// Outside the primary region no runner is started
+	status := getSystemStatus(t, service.NewWorkers())
+
+	assert.Equal(t, service.SystemStatusOK, status.Status)
+	assert.Empty(t, status.Workers)
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+	customvalidator "product-api/pkg/validator"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RenameTagRequest contains the new name of a tag.
+type RenameTagRequest struct {
+	Name string `json:"name" example:"audio" validate:"required,max=100"`
+}
+
+// MergeTagsRequest contains tags to be replaced by the target tag.
+type MergeTagsRequest struct {
+	Sources []string `json:"sources" example:"headphones,speakers" validate:"required,min=1,max=100,dive,required"`
+	Target  string   `json:"target" example:"audio" validate:"required,max=100"`
+}
+
+// TagHandler handles HTTP requests related to product tags.
+type TagHandler struct {
+	service *service.TagService
+	logger  logger.Logger
+}
+
+// NewTagHandler creates a new tag handler.
+func NewTagHandler(s *service.TagService, l logger.Logger) *TagHandler {
+	return &TagHandler{service: s, logger: l}
+}
+
+// List godoc
+// @Summary List product tags
+// @Description Returns all tags in use ordered by name, with the number of products that have them.
+// @Tags admin
+// @Produce  json
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {array}   domain.TagCount
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/tags [get]
+func (h *TagHandler) List(w http.ResponseWriter, r *http.Request) {
+	const op = "TagHandler.List"
+	log := h.logger.WithTrace(r.Context())
+
+	tags, err := h.service.List(r.Context())
+	if err != nil {
+		log.Error("failed to list tags", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tags); err != nil {
+		log.Error("failed to encode tags", "op", op, "error", err)
+	}
+}
+
+// Rename godoc
+// @Summary Rename a tag
+// @Description Renames the tag in all products. Products that already have the new name keep it once.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   tag  path  string  true  "Current tag name"
+// @Param   name  body  RenameTagRequest  true  "New tag name"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  service.TagUpdateResult
+// @Failure 400  {object}  ErrorResponse "Invalid request body"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "Tag not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/tags/{tag}/rename [post]
+func (h *TagHandler) Rename(w http.ResponseWriter, r *http.Request) {
+	const op = "TagHandler.Rename"
+
+	var req RenameTagRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	result, err := h.service.Rename(r.Context(), callerID(r.Context()), chi.URLParam(r, "tag"), req.Name)
+	h.writeUpdateResult(w, r, op, result, err)
+}
+
+// Merge godoc
+// @Summary Merge tags
+// @Description Replaces the source tags with the target tag in all products, keeping each tag once per product.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   tags  body  MergeTagsRequest  true  "Source and target tags"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  service.TagUpdateResult
+// @Failure 400  {object}  ErrorResponse "Invalid request body"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "None of the source tags found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/tags/merge [post]
+func (h *TagHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	const op = "TagHandler.Merge"
+
+	var req MergeTagsRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	result, err := h.service.Merge(r.Context(), callerID(r.Context()), req.Sources, req.Target)
+	h.writeUpdateResult(w, r, op, result, err)
+}
+
+// writeUpdateResult writes the result of a rename or merge, or maps its error to a status code.
+func (h *TagHandler) writeUpdateResult(w http.ResponseWriter, r *http.Request, op string, result *service.TagUpdateResult, err error) {
+	log := h.logger.WithTrace(r.Context())
+
+	switch {
+	case errors.Is(err, service.ErrInvalidTag):
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidTag, err.Error())
+		return
+	case errors.Is(err, service.ErrTagNotFound):
+		respondError(w, r, http.StatusNotFound, ErrCodeTagNotFound, err.Error())
+		return
+	case err != nil:
+		log.Error("failed to update tags", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Error("failed to encode tag update result", "op", op, "error", err)
+	}
+}
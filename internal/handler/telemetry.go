@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+	"product-api/internal/telemetry"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RouteTelemetryMiddleware names the request span and Sentry transaction after the matched route pattern,
+// e.g. "GET /products/{id}", so their cardinality does not grow with IDs in paths. Requests matching
+// no route are named after their method only. Request metrics are labeled by HTTPMetricsMiddleware.
+// Must follow the OpenTelemetry and Sentry middlewares; the pattern is known once the request is routed.
+func RouteTelemetryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		route := routePattern(r)
+		name := r.Method
+		if route != "" {
+			name += " " + route
+		}
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(name)
+		if tx := sentry.TransactionFromContext(r.Context()); tx != nil {
+			tx.Name, tx.Source = name, sentry.SourceRoute
+			if route == "" {
+				tx.Source = sentry.SourceCustom
+			}
+		}
+		if route == "" {
+			return
+		}
+		span.SetAttributes(semconv.HTTPRoute(route))
+	})
+}
+
+// HTTPMetricsMiddleware records the count and duration of requests by route pattern and status code,
+// and the requests in flight. Must precede the panic recoverer to see the status of recovered requests.
+func HTTPMetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		done := telemetry.StartHTTPRequest(r.Context(), r.Method)
+		defer done()
+
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK // Nothing was written
+		}
+		telemetry.RecordHTTPRequest(r.Context(), r.Method, routePattern(r), status, time.Since(start))
+	})
+}
+
+// routePattern returns the route pattern matched by the request, empty if it matched no route.
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/handler"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// routeSpan serves the request by a router with a product route under RouteTelemetryMiddleware
+// and returns the ended request span.
+func routeSpan(t *testing.T, method, target string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	spans := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)).Tracer("test")
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := tracer.Start(r.Context(), "server")
+			defer span.End()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	r.Use(handler.RouteTelemetryMiddleware)
+	r.Get("/products/{id}", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, target, nil))
+
+	require.Len(t, spans.Ended(), 1)
+	return spans.Ended()[0]
+}
+
+func TestRouteTelemetryMiddleware_NamesSpansByRoute(t *testing.T) {
+	span := routeSpan(t, http.MethodGet, "/products/2c1b3e0e-5d1f-4a53-9b8e-0f3a6d1c2b4a")
+
+	assert.Equal(t, "GET /products/{id}", span.Name(), "IDs in paths do not end up in span names")
+	assert.Contains(t, span.Attributes(), attribute.String("http.route", "/products/{id}"))
+}
+
+func TestRouteTelemetryMiddleware_NamesUnmatchedRequestsByMethod(t *testing.T) {
+	span := routeSpan(t, http.MethodGet, "/unknown/42")
+
+	assert.Equal(t, "GET", span.Name())
+	for _, attr := range span.Attributes() {
+		assert.NotEqual(t, attribute.Key("http.route"), attr.Key)
+	}
+}
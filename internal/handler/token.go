@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"product-api/internal/logger"
+	"product-api/internal/service"
+
+	"github.com/google/uuid"
+)
+
+// IntrospectionResponse describes the state of a token (RFC 7662).
+// Only "active" is returned for inactive tokens.
+type IntrospectionResponse struct {
+	Active    bool     `json:"active" example:"true"`
+	TokenType string   `json:"token_type,omitempty" example:"Bearer"`
+	Subject   string   `json:"sub,omitempty" example:"3f1c2a8e-8d4b-4c1e-9a57-2b6d1c7e9f10"` // User ID
+	JTI       string   `json:"jti,omitempty" example:"9b2e4f6a-1c3d-4e5f-8a7b-6c5d4e3f2a1b"`
+	IssuedAt  int64    `json:"iat,omitempty" example:"1717228800"`
+	ExpiresAt int64    `json:"exp,omitempty" example:"1717315200"`
+	Roles     []string `json:"roles,omitempty" example:"customer"`
+}
+
+// JWKSResponse is the JSON Web Key Set (RFC 7517) of the public keys access tokens are signed with.
+type JWKSResponse struct {
+	Keys []service.JWK `json:"keys"` // Empty if tokens are signed with the shared secret
+}
+
+// TokenHandler handles token introspection and revocation requests.
+type TokenHandler struct {
+	service *service.TokenService
+	logger  logger.Logger
+}
+
+// NewTokenHandler creates a new token handler.
+func NewTokenHandler(s *service.TokenService, l logger.Logger) *TokenHandler {
+	return &TokenHandler{service: s, logger: l}
+}
+
+// Introspect godoc
+// @Summary Introspect an access token
+// @Description RFC 7662 token introspection for sibling services and the API gateway.
+// @Description A token is active if it is valid, not expired, not revoked and its user is active with the role of the token.
+// @Tags oauth
+// @Accept  x-www-form-urlencoded
+// @Produce  json
+// @Param   token  formData  string  true  "Token to introspect"
+// @Param   X-API-Key  header  string  true  "API key"
+// @Success 200  {object}  IntrospectionResponse
+// @Failure 400  {object}  ErrorResponse "Missing token"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /oauth/introspect [post]
+func (h *TokenHandler) Introspect(w http.ResponseWriter, r *http.Request) {
+	const op = "TokenHandler.Introspect"
+	log := h.logger.WithTrace(r.Context())
+
+	token := r.PostFormValue("token")
+	if token == "" {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "missing token")
+		return
+	}
+
+	claims, active, err := h.service.Introspect(r.Context(), token)
+	if err != nil {
+		log.Error("failed to introspect token", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	resp := IntrospectionResponse{Active: active}
+	if active {
+		resp.TokenType = "Bearer"
+		resp.Subject = claims.UserID.String()
+		resp.ExpiresAt = claims.ExpiresAt.Unix()
+		resp.Roles = claims.Roles
+		if claims.ID != uuid.Nil {
+			resp.JTI = claims.ID.String()
+		}
+		if !claims.IssuedAt.IsZero() {
+			resp.IssuedAt = claims.IssuedAt.Unix()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("failed to encode introspection response", "op", op, "error", err)
+	}
+}
+
+// JWKS godoc
+// @Summary Get the public keys of access tokens
+// @Description JSON Web Key Set of the keys access tokens are signed with, so other services can validate tokens
+// @Description without the shared secret. Tokens name their key in the kid header. Empty if tokens are signed with HS256.
+// @Tags oauth
+// @Produce  json
+// @Success 200  {object}  JWKSResponse
+// @Router /.well-known/jwks.json [get]
+func (h *TokenHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	// Keys change only with a restart, validators refetch them for tokens with an unknown kid
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	if err := json.NewEncoder(w).Encode(JWKSResponse{Keys: h.service.PublicKeys()}); err != nil {
+		h.logger.WithTrace(r.Context()).Error("failed to encode key set", "op", "TokenHandler.JWKS", "error", err)
+	}
+}
+
+// Revoke godoc
+// @Summary Revoke an access token
+// @Description RFC 7009 token revocation. Invalid or expired tokens are ignored.
+// @Tags oauth
+// @Accept  x-www-form-urlencoded
+// @Param   token  formData  string  true  "Token to revoke"
+// @Param   X-API-Key  header  string  true  "API key"
+// @Success 200
+// @Failure 400  {object}  ErrorResponse "Missing token"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /oauth/revoke [post]
+func (h *TokenHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	const op = "TokenHandler.Revoke"
+	log := h.logger.WithTrace(r.Context())
+
+	token := r.PostFormValue("token")
+	if token == "" {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "missing token")
+		return
+	}
+
+	if err := h.service.Revoke(r.Context(), token); err != nil {
+		log.Error("failed to revoke token", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Logout godoc
+// @Summary Log out the current user
+// @Description Revokes the access token used for this request.
+// @Tags users
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/logout [post]
+func (h *TokenHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	const op = "TokenHandler.Logout"
+	log := h.logger.WithTrace(r.Context())
+
+	claims, ok := r.Context().Value(TokenClaimsKey).(*service.TokenClaims)
+	if !ok {
+		log.Error("failed to get token claims from context", "op", op)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	if err := h.service.RevokeClaims(r.Context(), claims); err != nil {
+		log.Error("failed to revoke token", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
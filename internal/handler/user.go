@@ -4,41 +4,98 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"product-api/internal/domain"
 	"product-api/internal/logger"
+	"product-api/internal/money"
 	"product-api/internal/service"
 	customvalidator "product-api/pkg/validator"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Login history page size limits.
+const (
+	defaultLoginHistoryLimit = 50
+	maxLoginHistoryLimit     = 100
 )
 
 // RegisterRequest contains data for registering a new user.
 type RegisterRequest struct {
 	Email     string `json:"email" example:"user@example.com" validate:"required,email"`
+	Username  string `json:"username,omitempty" example:"john.doe" validate:"omitempty,max=32"` // Optional, case-insensitive
 	Password  string `json:"password" example:"password123" validate:"required,min=8"`
 	Firstname string `json:"firstname" example:"John" validate:"required"`
 	Lastname  string `json:"lastname" example:"Doe" validate:"required"`
-	Age       int    `json:"age" example:"25" validate:"required,gte=18"`
+	Birthdate string `json:"birthdate" example:"1999-04-21" validate:"required,datetime=2006-01-02"`
 	IsMarried bool   `json:"is_married" example:"false"`
+	Locale    string `json:"locale,omitempty" example:"de-AT" validate:"max=35"` // Optional locale of emails and documents, the configured one if omitted
+
+	AcceptedTermsVersion   string `json:"accepted_terms_version" example:"2024-06-01"`   // Version of terms of service accepted by the user
+	AcceptedPrivacyVersion string `json:"accepted_privacy_version" example:"2024-06-01"` // Version of privacy policy accepted by the user
 }
 
 // LoginRequest contains data for user authentication.
+// Either email or username must be provided.
 type LoginRequest struct {
-	Email    string `json:"email" example:"user@example.com" validate:"required,email"`
+	Email    string `json:"email,omitempty" example:"user@example.com" validate:"required_without=Username,omitempty,email"`
+	Username string `json:"username,omitempty" example:"john.doe" validate:"required_without=Email"`
 	Password string `json:"password" example:"password123" validate:"required"`
 }
 
+// UsernameAvailabilityResponse contains the result of a username availability check.
+type UsernameAvailabilityResponse struct {
+	Username  string `json:"username" example:"john.doe"` // Normalized username
+	Available bool   `json:"available" example:"true"`
+	Reason    string `json:"reason,omitempty" example:"username already taken"`
+}
+
 // LoginResponse contains JWT token for authenticated user.
 type LoginResponse struct {
 	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
 }
 
+// SetRoleRequest contains the role to assign to a user.
+type SetRoleRequest struct {
+	Role string `json:"role" example:"manager" validate:"required,oneof=admin manager customer"`
+}
+
+// SetSandboxRequest marks a user as a sandbox account or a regular one.
+type SetSandboxRequest struct {
+	Sandbox bool `json:"sandbox" example:"true"`
+}
+
+// SetLocaleRequest contains the locale a user's emails and documents are written in.
+type SetLocaleRequest struct {
+	Locale string `json:"locale" example:"de-AT" validate:"max=35"` // BCP 47 tag, empty for the configured locale
+}
+
+// UpdateProfileRequest contains the profile fields to change. Omitted fields are kept.
+type UpdateProfileRequest struct {
+	Firstname *string `json:"firstname,omitempty" example:"John" validate:"omitnil,min=1,max=100"`
+	Lastname  *string `json:"lastname,omitempty" example:"Doe" validate:"omitnil,min=1,max=100"`
+	Birthdate *string `json:"birthdate,omitempty" example:"1999-04-21" validate:"omitnil,datetime=2006-01-02"`
+	IsMarried *bool   `json:"is_married,omitempty" example:"true"`
+}
+
+// ChangePasswordRequest contains the current password and the one replacing it.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" example:"password123" validate:"required"`
+	NewPassword     string `json:"new_password" example:"correct-horse-battery" validate:"required,min=8"`
+}
+
 // UserHandler handles HTTP requests related to users.
 type UserHandler struct {
-	service *service.UsersService
-	logger  logger.Logger
+	service  *service.UsersService
+	consents *service.ConsentService
+	logger   logger.Logger
 }
 
 // NewUserHandler creates a new user handler.
-func NewUserHandler(s *service.UsersService, l logger.Logger) *UserHandler {
-	return &UserHandler{service: s, logger: l}
+func NewUserHandler(s *service.UsersService, c *service.ConsentService, l logger.Logger) *UserHandler {
+	return &UserHandler{service: s, consents: c, logger: l}
 }
 
 // Register godoc
@@ -48,31 +105,75 @@ func NewUserHandler(s *service.UsersService, l logger.Logger) *UserHandler {
 // @Produce  json
 // @Param   user  body      RegisterRequest  true  "User registration details"
 // @Success 201   {object}  domain.User
-// @Failure 400   {string}  string "Invalid request body or validation error"
-// @Failure 409   {string}  string "User with this email already exists"
-// @Failure 500   {string}  string "Internal server error"
+// @Failure 400   {object}  ErrorResponse "Invalid request body, validation error, invalid username, unsupported locale or user is under 18"
+// @Failure 409   {object}  ErrorResponse "User with this email or username already exists"
+// @Failure 428   {object}  ErrorResponse "Latest legal documents must be accepted, details list them"
+// @Failure 429   {object}  ErrorResponse "Too many registration attempts from the client IP"
+// @Failure 500   {object}  ErrorResponse "Internal server error"
 // @Router /users/register [post]
 func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 	log := h.logger.WithTrace(r.Context())
 
 	var req RegisterRequest
 	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
-		customvalidator.HandleValidationError(w, err)
+		respondValidationError(w, r, err)
+		return
+	}
+
+	// Registration requires acceptance of the latest published legal documents
+	accepted := map[string]string{
+		domain.DocumentTypeTerms:   req.AcceptedTermsVersion,
+		domain.DocumentTypePrivacy: req.AcceptedPrivacyVersion,
+	}
+	if err := h.consents.ValidateAcceptance(r.Context(), accepted); err != nil {
+		if errors.Is(err, service.ErrConsentRequired) {
+			h.respondConsentRequired(w, r)
+			return
+		}
+		log.Error("failed to validate consent", "err", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
 		return
 	}
 
-	user, err := h.service.Register(r.Context(), req.Email, req.Password, req.Firstname, req.Lastname, req.Age, req.IsMarried)
+	// Format is already checked by the validator
+	birthdate, _ := time.Parse(time.DateOnly, req.Birthdate)
+
+	user, err := h.service.Register(r.Context(), service.RegisterInput{
+		Email:     req.Email,
+		Username:  req.Username,
+		Password:  req.Password,
+		Firstname: req.Firstname,
+		Lastname:  req.Lastname,
+		Birthdate: birthdate,
+		IsMarried: req.IsMarried,
+		Locale:    req.Locale,
+	})
 	if err != nil {
 		switch {
-		case errors.Is(err, service.ErrUserAlreadyExists):
-			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, service.ErrUserAlreadyExists), errors.Is(err, service.ErrUsernameTaken):
+			respondError(w, r, http.StatusConflict, ErrCodeUserExists, err.Error())
+		case errors.Is(err, service.ErrUnderage),
+			errors.Is(err, domain.ErrUsernameInvalid),
+			errors.Is(err, domain.ErrUsernameReserved),
+			errors.Is(err, money.ErrUnknownLocale):
+			respondError(w, r, http.StatusBadRequest, ErrCodeUnknownLocale, err.Error())
 		default:
 			log.Error("failed to register user", "err", err)
-			http.Error(w, "internal server error", http.StatusInternalServerError)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
 		}
 		return
 	}
 
+	// Record consent history for the accepted document versions
+	for docType, version := range accepted {
+		if version == "" {
+			continue
+		}
+		if _, err := h.consents.Accept(r.Context(), user.ID, docType, version); err != nil && !errors.Is(err, service.ErrDocumentNotFound) {
+			log.Error("failed to record consent", "err", err, "user_id", user.ID, "type", docType)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(user); err != nil {
@@ -87,9 +188,12 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 // @Produce  json
 // @Param   credentials  body      LoginRequest  true  "User credentials"
 // @Success 200        {object}  LoginResponse
-// @Failure 400        {string}  string "Invalid request body"
-// @Failure 401        {string}  string "Invalid email or password"
-// @Failure 500        {string}  string "Internal server error"
+// @Failure 400        {object}  ErrorResponse "Invalid request body"
+// @Failure 401        {object}  ErrorResponse "Invalid login or password"
+// @Failure 403        {object}  ErrorResponse "Account is disabled"
+// @Failure 423        {object}  ErrorResponse "Account is temporarily locked after too many failed logins"
+// @Failure 429        {object}  ErrorResponse "Too many login attempts from the client IP"
+// @Failure 500        {object}  ErrorResponse "Internal server error"
 // @Router /users/login [post]
 func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	const op = "UserHandler.Login"
@@ -97,18 +201,32 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	var req LoginRequest
 	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
-		customvalidator.HandleValidationError(w, err)
+		respondValidationError(w, r, err)
 		return
 	}
 
-	token, err := h.service.Login(r.Context(), req.Email, req.Password)
+	meta := service.LoginMetadata{IPAddress: r.RemoteAddr, UserAgent: r.UserAgent()}
+	login := req.Email
+	if login == "" {
+		login = req.Username
+	}
+
+	token, err := h.service.Login(r.Context(), login, req.Password, meta)
 	if err != nil {
 		if errors.Is(err, service.ErrInvalidCredentials) {
-			http.Error(w, "Invalid email or password", http.StatusUnauthorized)
+			respondError(w, r, http.StatusUnauthorized, ErrCodeInvalidCredentials, "Invalid login or password")
+			return
+		}
+		if errors.Is(err, service.ErrAccountDisabled) {
+			respondError(w, r, http.StatusForbidden, ErrCodeAccountDisabled, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrAccountLocked) {
+			respondError(w, r, http.StatusLocked, ErrCodeAccountLocked, err.Error())
 			return
 		}
 		log.Error("failed to login user", "op", op, "error", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
 		return
 	}
 
@@ -117,3 +235,449 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		log.Error("failed to write login response", "op", op, "error", err)
 	}
 }
+
+// CheckUsername godoc
+// @Summary Check username availability
+// @Description Applies normalization rules (trimming, case folding, reserved names) and checks whether the username is free.
+// @Tags users
+// @Produce  json
+// @Param   username  query     string  true  "Username to check"
+// @Success 200  {object}  UsernameAvailabilityResponse
+// @Failure 400  {object}  ErrorResponse "Missing username"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/check-username [get]
+func (h *UserHandler) CheckUsername(w http.ResponseWriter, r *http.Request) {
+	const op = "UserHandler.CheckUsername"
+	log := h.logger.WithTrace(r.Context())
+
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "missing username")
+		return
+	}
+
+	resp := UsernameAvailabilityResponse{}
+	normalized, available, err := h.service.CheckUsername(r.Context(), username)
+	switch {
+	case errors.Is(err, domain.ErrUsernameInvalid), errors.Is(err, domain.ErrUsernameReserved):
+		resp.Username = username
+		resp.Reason = err.Error()
+	case err != nil:
+		log.Error("failed to check username", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	default:
+		resp.Username = normalized
+		resp.Available = available
+		if !available {
+			resp.Reason = service.ErrUsernameTaken.Error()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("failed to encode username availability", "op", op, "error", err)
+	}
+}
+
+// LoginHistory godoc
+// @Summary Get login history of the current user
+// @Tags users
+// @Produce  json
+// @Param   limit  query     int  false  "Maximum number of entries (1-100, default 50)"
+// @Security ApiKeyAuth
+// @Success 200  {array}   domain.LoginAttempt
+// @Failure 400  {object}  ErrorResponse "Invalid limit"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/login-history [get]
+func (h *UserHandler) LoginHistory(w http.ResponseWriter, r *http.Request) {
+	const op = "UserHandler.LoginHistory"
+	log := h.logger.WithTrace(r.Context())
+
+	limit := defaultLoginHistoryLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxLoginHistoryLimit {
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid limit")
+			return
+		}
+	}
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	attempts, err := h.service.LoginHistory(r.Context(), userID, limit)
+	if err != nil {
+		log.Error("failed to get login history", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	if attempts == nil {
+		attempts = []domain.LoginAttempt{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(attempts); err != nil {
+		log.Error("failed to encode login history", "op", op, "error", err)
+	}
+}
+
+// SetLocale godoc
+// @Summary Set the locale of the user's emails and documents
+// @Description Emails are rendered from the template versions of the locale or its language, with amounts and dates
+// @Description formatted in the locale. Orders keep the locale they were placed with, so do their invoices.
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param   locale  body  SetLocaleRequest  true  "Locale, e.g. de-AT"
+// @Security ApiKeyAuth
+// @Success 200  {object}  domain.User
+// @Failure 400  {object}  ErrorResponse "Invalid request body or unsupported locale"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/locale [put]
+func (h *UserHandler) SetLocale(w http.ResponseWriter, r *http.Request) {
+	const op = "UserHandler.SetLocale"
+	log := h.logger.WithTrace(r.Context())
+
+	var req SetLocaleRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	user, err := h.service.SetLocale(r.Context(), userID, req.Locale)
+	if err != nil {
+		if errors.Is(err, money.ErrUnknownLocale) {
+			respondError(w, r, http.StatusBadRequest, ErrCodeUnknownLocale, err.Error())
+			return
+		}
+		log.Error("failed to set user locale", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(user); err != nil {
+		log.Error("failed to encode user", "op", op, "error", err)
+	}
+}
+
+// Me godoc
+// @Summary Get the current user's profile
+// @Tags users
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200  {object}  domain.User
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me [get]
+func (h *UserHandler) Me(w http.ResponseWriter, r *http.Request) {
+	const op = "UserHandler.Me"
+	log := h.logger.WithTrace(r.Context())
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	user, err := h.service.GetUser(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			respondError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized, "invalid token")
+			return
+		}
+		log.Error("failed to get user", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(user); err != nil {
+		log.Error("failed to encode user", "op", op, "error", err)
+	}
+}
+
+// UpdateProfile godoc
+// @Summary Update the current user's profile
+// @Description Changes the fields present in the request, omitted fields are kept. Users must stay at least 18 years old.
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param   profile  body  UpdateProfileRequest  true  "Profile fields to change"
+// @Security ApiKeyAuth
+// @Success 200  {object}  domain.User
+// @Failure 400  {object}  ErrorResponse "Invalid request body, validation error or user would be under 18"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me [patch]
+func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	const op = "UserHandler.UpdateProfile"
+	log := h.logger.WithTrace(r.Context())
+
+	var req UpdateProfileRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	update := service.ProfileUpdate{Firstname: req.Firstname, Lastname: req.Lastname, IsMarried: req.IsMarried}
+	if req.Birthdate != nil {
+		// Format is already checked by the validator
+		birthdate, _ := time.Parse(time.DateOnly, *req.Birthdate)
+		update.Birthdate = &birthdate
+	}
+
+	user, err := h.service.UpdateProfile(r.Context(), userID, update)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnderage):
+			respondError(w, r, http.StatusBadRequest, ErrCodeUnderage, err.Error())
+		case errors.Is(err, service.ErrUserNotFound):
+			respondError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized, "invalid token")
+		default:
+			log.Error("failed to update profile", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(user); err != nil {
+		log.Error("failed to encode user", "op", op, "error", err)
+	}
+}
+
+// ChangePassword godoc
+// @Summary Change the current user's password
+// @Description Verifies the current password before setting the new one. Tokens issued before the change,
+// @Description including the one of this request, are rejected afterwards; the response carries a new token.
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param   password  body  ChangePasswordRequest  true  "Current and new password"
+// @Security ApiKeyAuth
+// @Success 200  {object}  LoginResponse
+// @Failure 400  {object}  ErrorResponse "Invalid request body, validation error or new password equals the current one"
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 403  {object}  ErrorResponse "Current password is wrong"
+// @Failure 429  {object}  ErrorResponse "Too many attempts"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me/password [post]
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	const op = "UserHandler.ChangePassword"
+	log := h.logger.WithTrace(r.Context())
+
+	var req ChangePasswordRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	meta := service.LoginMetadata{IPAddress: r.RemoteAddr, UserAgent: r.UserAgent()}
+	token, err := h.service.ChangePassword(r.Context(), userID, req.CurrentPassword, req.NewPassword, meta)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWrongPassword):
+			respondError(w, r, http.StatusForbidden, ErrCodeWrongPassword, err.Error())
+		case errors.Is(err, service.ErrSamePassword):
+			respondError(w, r, http.StatusBadRequest, ErrCodeSamePassword, err.Error())
+		case errors.Is(err, service.ErrUserNotFound):
+			respondError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized, "invalid token")
+		default:
+			log.Error("failed to change password", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(LoginResponse{Token: token}); err != nil {
+		log.Error("failed to write change password response", "op", op, "error", err)
+	}
+}
+
+// DeleteAccount godoc
+// @Summary Delete the current user's account
+// @Description Deletes the account with its consents, preferences, login history and notifications.
+// @Description Accounts of users who placed orders are kept, as orders are kept for accounting.
+// @Tags users
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 401  {object}  ErrorResponse "Unauthorized"
+// @Failure 409  {object}  ErrorResponse "User has orders"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /users/me [delete]
+func (h *UserHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	const op = "UserHandler.DeleteAccount"
+	log := h.logger.WithTrace(r.Context())
+
+	userID, err := userIDFromContext(r)
+	if err != nil {
+		log.Error("failed to get user id from context", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+
+	if err := h.service.DeleteAccount(r.Context(), userID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserHasOrders):
+			respondError(w, r, http.StatusConflict, ErrCodeUserHasOrders, err.Error())
+		case errors.Is(err, service.ErrUserNotFound):
+			respondError(w, r, http.StatusUnauthorized, ErrCodeUnauthorized, "invalid token")
+		default:
+			log.Error("failed to delete account", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetRole godoc
+// @Summary Assign a role to a user
+// @Description Admins and managers manage the catalog, customers browse and order products.
+// @Description Tokens issued with the previous role are rejected, so the user logs in again.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   id  path  string  true  "User ID"
+// @Param   role  body  SetRoleRequest  true  "Role"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  domain.User
+// @Failure 400  {object}  ErrorResponse "Invalid user ID or role"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "User not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/users/{id}/role [put]
+func (h *UserHandler) SetRole(w http.ResponseWriter, r *http.Request) {
+	const op = "UserHandler.SetRole"
+	log := h.logger.WithTrace(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid user ID")
+		return
+	}
+
+	var req SetRoleRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	user, err := h.service.SetRole(r.Context(), id, req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			respondError(w, r, http.StatusNotFound, ErrCodeUserNotFound, err.Error())
+		case errors.Is(err, service.ErrInvalidRole):
+			respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRole, err.Error())
+		default:
+			log.Error("failed to set user role", "op", op, "error", err)
+			respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		}
+		return
+	}
+	log.Info("user role changed", "op", op, "user_id", user.ID, "role", user.Role, "actor", callerID(r.Context()))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(user); err != nil {
+		log.Error("failed to encode user", "op", op, "error", err)
+	}
+}
+
+// SetSandbox godoc
+// @Summary Mark a user as a sandbox account
+// @Description Sandbox accounts let integration partners test checkouts end-to-end against production: their orders
+// @Description go through the same checks, but allocate no stock and are paid with the mock payment provider, whatever
+// @Description provider they select. Sandbox orders and their events carry "Sandbox": true and are left out of the
+// @Description accounting journal. Orders placed before the change keep their mode.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param   id  path  string  true  "User ID"
+// @Param   sandbox  body  SetSandboxRequest  true  "Sandbox mode"
+// @Param   X-API-Key  header  string  true  "Admin API key"
+// @Success 200  {object}  domain.User
+// @Failure 400  {object}  ErrorResponse "Invalid user ID or request body"
+// @Failure 401  {object}  ErrorResponse "Invalid API key"
+// @Failure 404  {object}  ErrorResponse "User not found"
+// @Failure 500  {object}  ErrorResponse "Internal server error"
+// @Router /admin/users/{id}/sandbox [put]
+func (h *UserHandler) SetSandbox(w http.ResponseWriter, r *http.Request) {
+	const op = "UserHandler.SetSandbox"
+	log := h.logger.WithTrace(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid user ID")
+		return
+	}
+
+	var req SetSandboxRequest
+	if err := customvalidator.DecodeAndValidate(r, &req); err != nil {
+		respondValidationError(w, r, err)
+		return
+	}
+
+	user, err := h.service.SetSandbox(r.Context(), id, req.Sandbox)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			respondError(w, r, http.StatusNotFound, ErrCodeUserNotFound, err.Error())
+			return
+		}
+		log.Error("failed to set user sandbox mode", "op", op, "error", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	log.Info("user sandbox mode changed", "op", op, "user_id", user.ID, "sandbox", user.Sandbox, "actor", callerID(r.Context()))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(user); err != nil {
+		log.Error("failed to encode user", "op", op, "error", err)
+	}
+}
+
+// respondConsentRequired sends a 428 consent_required error listing the latest legal documents to accept in details.
+func (h *UserHandler) respondConsentRequired(w http.ResponseWriter, r *http.Request) {
+	log := h.logger.WithTrace(r.Context())
+
+	docs, err := h.consents.LatestDocuments(r.Context())
+	if err != nil {
+		log.Error("failed to get latest legal documents", "err", err)
+		respondError(w, r, http.StatusInternalServerError, ErrCodeInternal, "internal server error")
+		return
+	}
+	respondErrorDetails(w, r, http.StatusPreconditionRequired, ErrCodeConsentRequired, "consent required", docs)
+}
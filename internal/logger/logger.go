@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"os"
 
+	"github.com/go-chi/chi/v5"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -14,7 +15,7 @@ type Logger interface {
 	Warn(msg string, args ...any)
 	Error(msg string, args ...any)
 	Debug(msg string, args ...any)
-	WithTrace(ctx context.Context) Logger // Creates a new logger with trace ID from context
+	WithTrace(ctx context.Context) Logger // Creates a new logger with trace ID and route pattern from context
 }
 
 // SlogAdapter is an adapter for the standard slog.Logger.
@@ -26,7 +27,8 @@ type SlogAdapter struct {
 // NewSlogAdapter creates a new logger adapter based on the environment.
 // For local environment uses text format with Debug level.
 // For dev and prod environments uses JSON format (Debug for dev, Info for prod).
-func NewSlogAdapter(env string) Logger {
+// Attributes given as key-value pairs are added to every record, e.g. the region of the instance.
+func NewSlogAdapter(env string, attrs ...any) Logger {
 	var handler slog.Handler
 
 	switch env {
@@ -44,7 +46,7 @@ func NewSlogAdapter(env string) Logger {
 		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})
 	}
 
-	log := slog.New(handler)
+	log := slog.New(handler).With(attrs...)
 
 	return &SlogAdapter{logger: log}
 }
@@ -69,14 +71,19 @@ func (s *SlogAdapter) Debug(msg string, args ...any) {
 	s.logger.Debug(msg, args...)
 }
 
-// WithTrace creates a new logger with trace ID from OpenTelemetry context.
-// Returns the original logger if trace ID is not present.
+// WithTrace creates a new logger with trace ID from OpenTelemetry context
+// and the route pattern of the request being handled, e.g. "/products/{id}".
+// Returns the original logger if neither is present.
 func (s *SlogAdapter) WithTrace(ctx context.Context) Logger {
-	span := trace.SpanFromContext(ctx)
-	if span.SpanContext().IsValid() {
-		return &SlogAdapter{
-			logger: s.logger.With("trace_id", span.SpanContext().TraceID().String()),
-		}
+	var args []any
+	if span := trace.SpanFromContext(ctx); span.SpanContext().IsValid() {
+		args = append(args, "trace_id", span.SpanContext().TraceID().String())
 	}
-	return s
+	if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+		args = append(args, "route", rctx.RoutePattern())
+	}
+	if len(args) == 0 {
+		return s
+	}
+	return &SlogAdapter{logger: s.logger.With(args...)}
 }
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// logRecord logs a message with the logger of ctx and returns the logged JSON record.
+func logRecord(t *testing.T, ctx context.Context) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	l := &SlogAdapter{logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+	l.WithTrace(ctx).Info("request handled")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	return record
+}
+
+func TestWithTrace_AddsTraceIDAndRoute(t *testing.T) {
+	traceID := trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+	}))
+	rctx := chi.NewRouteContext()
+	rctx.RoutePatterns = []string{"/products/{id}"}
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+	record := logRecord(t, ctx)
+	assert.Equal(t, traceID.String(), record["trace_id"])
+	assert.Equal(t, "/products/{id}", record["route"])
+}
+
+func TestWithTrace_WithoutTraceOrRoute(t *testing.T) {
+	// Requests matching no route have a route context without a pattern
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, chi.NewRouteContext())
+
+	record := logRecord(t, ctx)
+	assert.NotContains(t, record, "trace_id")
+	assert.NotContains(t, record, "route")
+}
@@ -0,0 +1,269 @@
+// Package money provides currency-aware amounts and locale-aware formatting of amounts,
+// numbers and dates for API responses and customer-facing documents (emails, invoices).
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnknownCurrency is returned for currency codes without formatting rules.
+	ErrUnknownCurrency = errors.New("unknown currency")
+	// ErrUnknownLocale is returned for locales without formatting rules.
+	ErrUnknownLocale = errors.New("unknown locale")
+)
+
+// Currency describes an ISO 4217 currency.
+type Currency struct {
+	Code       string // ISO 4217 code, e.g. "USD"
+	MinorUnits int    // Number of decimal places, e.g. 2 for cents
+	Symbol     string
+}
+
+// currencies contains supported currencies by code.
+var currencies = map[string]Currency{
+	"USD": {Code: "USD", MinorUnits: 2, Symbol: "$"},
+	"EUR": {Code: "EUR", MinorUnits: 2, Symbol: "€"},
+	"GBP": {Code: "GBP", MinorUnits: 2, Symbol: "£"},
+	"RUB": {Code: "RUB", MinorUnits: 2, Symbol: "₽"},
+	"JPY": {Code: "JPY", MinorUnits: 0, Symbol: "¥"},
+	"KWD": {Code: "KWD", MinorUnits: 3, Symbol: "KD"},
+}
+
+// LookupCurrency returns the currency with the given ISO 4217 code.
+func LookupCurrency(code string) (Currency, error) {
+	c, ok := currencies[strings.ToUpper(code)]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w: %s", ErrUnknownCurrency, code)
+	}
+	return c, nil
+}
+
+// Locale describes how amounts, numbers and dates are written in a locale.
+type Locale struct {
+	Tag         string // BCP 47 tag, e.g. "en-US"
+	Decimal     string // Decimal separator
+	Group       string // Thousands separator
+	SymbolFirst bool   // Currency symbol before the amount
+	SymbolSpace bool   // Space between the symbol and the amount
+	DateLayout  string // Layout of dates, see time.Layout
+}
+
+// locales contains supported locales by tag.
+var locales = map[string]Locale{
+	"en-US": {Tag: "en-US", Decimal: ".", Group: ",", SymbolFirst: true, DateLayout: "01/02/2006"},
+	"en-GB": {Tag: "en-GB", Decimal: ".", Group: ",", SymbolFirst: true, DateLayout: "02/01/2006"},
+	"de-DE": {Tag: "de-DE", Decimal: ",", Group: ".", SymbolSpace: true, DateLayout: "02.01.2006"},
+	"fr-FR": {Tag: "fr-FR", Decimal: ",", Group: " ", SymbolSpace: true, DateLayout: "02/01/2006"},
+	"ru-RU": {Tag: "ru-RU", Decimal: ",", Group: " ", SymbolSpace: true, DateLayout: "02.01.2006"},
+	"ja-JP": {Tag: "ja-JP", Decimal: ".", Group: ",", SymbolFirst: true, DateLayout: "2006/01/02"},
+}
+
+// languageLocales contains the locale used for each language when a tag has no locale of its own, e.g. "de-AT".
+var languageLocales = map[string]string{
+	"en": "en-US",
+	"de": "de-DE",
+	"fr": "fr-FR",
+	"ru": "ru-RU",
+	"ja": "ja-JP",
+}
+
+// LookupLocale returns the locale with the given BCP 47 tag.
+func LookupLocale(tag string) (Locale, error) {
+	l, ok := locales[tag]
+	if !ok {
+		return Locale{}, fmt.Errorf("%w: %s", ErrUnknownLocale, tag)
+	}
+	return l, nil
+}
+
+// CanonicalTag writes the language of a BCP 47 tag in lower case and its region in upper case,
+// e.g. "de_at" becomes "de-AT".
+func CanonicalTag(tag string) string {
+	lang, region, found := strings.Cut(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	if !found {
+		return strings.ToLower(lang)
+	}
+	return strings.ToLower(lang) + "-" + strings.ToUpper(region)
+}
+
+// Fallbacks returns the tags content in the given locale is looked up by, most specific first,
+// e.g. "de-AT" and "de". Returns nil for an empty tag.
+func Fallbacks(tag string) []string {
+	tag = CanonicalTag(tag)
+	if tag == "" {
+		return nil
+	}
+	lang, _, found := strings.Cut(tag, "-")
+	if !found {
+		return []string{tag}
+	}
+	return []string{tag, lang}
+}
+
+// ResolveLocale returns the locale with the given BCP 47 tag, or the locale of its language
+// if the tag has no formatting rules of its own, e.g. de-DE for "de-AT" or "de".
+func ResolveLocale(tag string) (Locale, error) {
+	for _, fallback := range Fallbacks(tag) {
+		if l, ok := locales[fallback]; ok {
+			return l, nil
+		}
+		if l, ok := locales[languageLocales[fallback]]; ok {
+			return l, nil
+		}
+	}
+	return Locale{}, fmt.Errorf("%w: %s", ErrUnknownLocale, tag)
+}
+
+// FormatNumber writes the number with the locale's separators and the given number of decimal places,
+// e.g. "1,234.5" or "1.234,5".
+func (l Locale) FormatNumber(n float64, decimals int) string {
+	return FromMajor(n, Currency{MinorUnits: decimals}).number(l.Decimal, l.Group)
+}
+
+// FormatDate writes the date of t according to the locale, e.g. "03/14/2026" or "14.03.2026".
+func (l Locale) FormatDate(t time.Time) string {
+	return t.Format(l.DateLayout)
+}
+
+// Amount is a monetary amount in minor units of a currency (e.g. cents).
+type Amount struct {
+	Minor    int64
+	Currency Currency
+}
+
+// FromMajor converts an amount in major units (e.g. dollars) to minor units, rounding half away from zero.
+func FromMajor(amount float64, c Currency) Amount {
+	return Amount{Minor: int64(math.Round(amount * math.Pow10(c.MinorUnits))), Currency: c}
+}
+
+// Major returns the amount in major units.
+func (a Amount) Major() float64 {
+	return float64(a.Minor) / math.Pow10(a.Currency.MinorUnits)
+}
+
+// String returns the amount in a locale-independent form, e.g. "1234.50 USD".
+func (a Amount) String() string {
+	return a.number(".", "") + " " + a.Currency.Code
+}
+
+// Format writes the amount with the currency symbol according to the locale, e.g. "$1,234.50" or "1.234,50 €".
+func (a Amount) Format(l Locale) string {
+	number := a.number(l.Decimal, l.Group)
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+
+	space := ""
+	if l.SymbolSpace {
+		space = " "
+	}
+	if l.SymbolFirst {
+		return sign + a.Currency.Symbol + space + number
+	}
+	return sign + number + space + a.Currency.Symbol
+}
+
+// number writes the amount with the given separators and exactly MinorUnits decimal places.
+func (a Amount) number(decimal, group string) string {
+	minor := a.Minor
+	sign := ""
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+
+	digits := strconv.FormatInt(minor, 10)
+	units := a.Currency.MinorUnits
+	if len(digits) <= units {
+		digits = strings.Repeat("0", units-len(digits)+1) + digits
+	}
+	intPart, fracPart := digits[:len(digits)-units], digits[len(digits)-units:]
+
+	if group != "" {
+		var b strings.Builder
+		for i, d := range intPart {
+			if i > 0 && (len(intPart)-i)%3 == 0 {
+				b.WriteString(group)
+			}
+			b.WriteRune(d)
+		}
+		intPart = b.String()
+	}
+
+	if units == 0 {
+		return sign + intPart
+	}
+	return sign + intPart + decimal + fracPart
+}
+
+// Money is the representation of an amount in API responses,
+// so clients do not have to guess decimal places.
+type Money struct {
+	Amount     int64  // Amount in minor units
+	Currency   string // ISO 4217 code
+	MinorUnits int    // Decimal places of the currency
+	Formatted  string // Amount formatted for display
+}
+
+// Formatter converts amounts in the configured currency for responses and documents.
+type Formatter struct {
+	Currency Currency
+	Locale   Locale
+}
+
+// NewFormatter creates a formatter for the currency code and locale tag.
+func NewFormatter(currencyCode, localeTag string) (*Formatter, error) {
+	c, err := LookupCurrency(currencyCode)
+	if err != nil {
+		return nil, err
+	}
+	l, err := LookupLocale(localeTag)
+	if err != nil {
+		return nil, err
+	}
+	return &Formatter{Currency: c, Locale: l}, nil
+}
+
+// In returns a formatter for the currency code and locale tag, e.g. recorded with an order.
+// Tags without formatting rules of their own use the locale of their language.
+// Empty values keep the formatter's currency or locale.
+func (f *Formatter) In(currencyCode, localeTag string) (*Formatter, error) {
+	in := *f
+	if currencyCode != "" {
+		c, err := LookupCurrency(currencyCode)
+		if err != nil {
+			return nil, err
+		}
+		in.Currency = c
+	}
+	if localeTag != "" {
+		l, err := ResolveLocale(localeTag)
+		if err != nil {
+			return nil, err
+		}
+		in.Locale = l
+	}
+	return &in, nil
+}
+
+// Format formats an amount in major units for display.
+func (f *Formatter) Format(amount float64) string {
+	return FromMajor(amount, f.Currency).Format(f.Locale)
+}
+
+// Money returns the response representation of an amount in major units.
+func (f *Formatter) Money(amount float64) Money {
+	a := FromMajor(amount, f.Currency)
+	return Money{
+		Amount:     a.Minor,
+		Currency:   f.Currency.Code,
+		MinorUnits: f.Currency.MinorUnits,
+		Formatted:  a.Format(f.Locale),
+	}
+}
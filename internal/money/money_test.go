@@ -0,0 +1,119 @@
+package money_test
+
+import (
+	"product-api/internal/money"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmountFormat(t *testing.T) {
+	usd, _ := money.LookupCurrency("USD")
+	eur, _ := money.LookupCurrency("EUR")
+	jpy, _ := money.LookupCurrency("JPY")
+	kwd, _ := money.LookupCurrency("KWD")
+	enUS, _ := money.LookupLocale("en-US")
+	deDE, _ := money.LookupLocale("de-DE")
+
+	tests := []struct {
+		name   string
+		amount money.Amount
+		locale money.Locale
+		want   string
+	}{
+		{name: "usd", amount: money.Amount{Minor: 123450, Currency: usd}, locale: enUS, want: "$1,234.50"},
+		{name: "usd cents only", amount: money.Amount{Minor: 5, Currency: usd}, locale: enUS, want: "$0.05"},
+		{name: "negative", amount: money.Amount{Minor: -1999, Currency: usd}, locale: enUS, want: "-$19.99"},
+		{name: "eur german", amount: money.Amount{Minor: 123456789, Currency: eur}, locale: deDE, want: "1.234.567,89 €"},
+		{name: "no minor units", amount: money.Amount{Minor: 1500, Currency: jpy}, locale: enUS, want: "¥1,500"},
+		{name: "three minor units", amount: money.Amount{Minor: 1234, Currency: kwd}, locale: enUS, want: "KD1.234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.amount.Format(tt.locale))
+		})
+	}
+}
+
+func TestFromMajor(t *testing.T) {
+	usd, _ := money.LookupCurrency("usd")
+
+	assert.Equal(t, int64(30), money.FromMajor(0.1+0.2, usd).Minor)
+	assert.Equal(t, int64(29997), money.FromMajor(99.99*3, usd).Minor)
+	assert.Equal(t, "299.97 USD", money.FromMajor(299.97, usd).String())
+	assert.Equal(t, 299.97, money.FromMajor(299.97, usd).Major())
+}
+
+func TestFormatter(t *testing.T) {
+	_, err := money.NewFormatter("XXX", "en-US")
+	assert.ErrorIs(t, err, money.ErrUnknownCurrency)
+	_, err = money.NewFormatter("USD", "xx-XX")
+	assert.ErrorIs(t, err, money.ErrUnknownLocale)
+
+	f, err := money.NewFormatter("USD", "en-US")
+	require.NoError(t, err)
+	assert.Equal(t, money.Money{Amount: 2099, Currency: "USD", MinorUnits: 2, Formatted: "$20.99"}, f.Money(20.99))
+}
+
+func TestFormatterIn(t *testing.T) {
+	f, err := money.NewFormatter("USD", "en-US")
+	require.NoError(t, err)
+
+	eur, err := f.In("EUR", "de-DE")
+	require.NoError(t, err)
+	assert.Equal(t, "1.234,50\u00a0€", eur.Format(1234.5))
+	assert.Equal(t, "$20.99", f.Format(20.99), "the formatter is not changed")
+
+	same, err := f.In("", "")
+	require.NoError(t, err)
+	assert.Equal(t, f, same)
+
+	_, err = f.In("XXX", "")
+	assert.ErrorIs(t, err, money.ErrUnknownCurrency)
+}
+
+func TestResolveLocale(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{tag: "de-DE", want: "de-DE"},
+		{tag: "en-GB", want: "en-GB"},
+		{tag: "de-AT", want: "de-DE"},
+		{tag: "de_at", want: "de-DE"},
+		{tag: "fr", want: "fr-FR"},
+		{tag: "EN", want: "en-US"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			l, err := money.ResolveLocale(tt.tag)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, l.Tag)
+		})
+	}
+
+	_, err := money.ResolveLocale("pt-BR")
+	assert.ErrorIs(t, err, money.ErrUnknownLocale)
+	_, err = money.ResolveLocale("")
+	assert.ErrorIs(t, err, money.ErrUnknownLocale)
+	assert.Equal(t, []string{"de-AT", "de"}, money.Fallbacks("de_at"))
+	assert.Nil(t, money.Fallbacks(""))
+}
+
+func TestLocaleFormat(t *testing.T) {
+	enUS, _ := money.LookupLocale("en-US")
+	deDE, _ := money.LookupLocale("de-DE")
+	ja, _ := money.LookupLocale("ja-JP")
+	date := time.Date(2026, time.March, 14, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "03/14/2026", enUS.FormatDate(date))
+	assert.Equal(t, "14.03.2026", deDE.FormatDate(date))
+	assert.Equal(t, "2026/03/14", ja.FormatDate(date))
+
+	assert.Equal(t, "1,234.5", enUS.FormatNumber(1234.5, 1))
+	assert.Equal(t, "1.234,50", deDE.FormatNumber(1234.5, 2))
+	assert.Equal(t, "-7", deDE.FormatNumber(-7, 0))
+}
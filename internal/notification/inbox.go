@@ -0,0 +1,37 @@
+package notification
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InboxSender is a Sender delivering messages to the user's inbox in the web app.
+type InboxSender struct {
+	repo repository.InboxRepository
+}
+
+// NewInboxSender creates a new inbox sender.
+func NewInboxSender(repo repository.InboxRepository) *InboxSender {
+	return &InboxSender{repo: repo}
+}
+
+// Channel returns domain.NotificationChannelInApp.
+func (s *InboxSender) Channel() string {
+	return domain.NotificationChannelInApp
+}
+
+// Send stores the message as an unread notification of the user.
+func (s *InboxSender) Send(ctx context.Context, user *domain.User, msg Message) error {
+	return s.repo.Create(ctx, &domain.InboxNotification{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Category:  msg.Category,
+		Subject:   msg.Subject,
+		Body:      msg.Body,
+		CreatedAt: time.Now(),
+	})
+}
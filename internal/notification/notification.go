@@ -0,0 +1,154 @@
+package notification
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Message contains a notification to deliver to a user.
+// Messages with a template get their subject and body rendered for the recipient.
+type Message struct {
+	Category string // One of domain.NotificationCategory* values
+	Subject  string
+	Body     string
+	Template string            // Name of the template, one of the Template* constants
+	Data     map[string]string // Template variables, besides those of the recipient
+	Locale   string            // Locale to render the template in, e.g. of an order; empty for the recipient's locale
+}
+
+// Sender delivers messages over a single channel (email, sms, push).
+type Sender interface {
+	Channel() string
+	Send(ctx context.Context, user *domain.User, msg Message) error
+}
+
+// Notifier defines the interface for sending notifications to users.
+type Notifier interface {
+	Notify(ctx context.Context, userID uuid.UUID, msg Message) error
+}
+
+// Dispatcher sends notifications through all configured channels
+// the user has opted in to for the message category.
+type Dispatcher struct {
+	users     repository.UserRepository
+	prefs     repository.PreferenceRepository
+	templates *Templates
+	senders   []Sender
+	logger    logger.Logger
+}
+
+// NewDispatcher creates a new notification dispatcher rendering message templates with templates.
+func NewDispatcher(users repository.UserRepository, prefs repository.PreferenceRepository, templates *Templates, logger logger.Logger, senders ...Sender) *Dispatcher {
+	return &Dispatcher{users: users, prefs: prefs, templates: templates, senders: senders, logger: logger}
+}
+
+// Notify consults the user's notification preferences and sends the message
+// through every channel the user opted in to. Channels the user opted out of are skipped,
+// and so is SMS for users without a verified phone number.
+// Returns a joined error of all failed deliveries.
+func (d *Dispatcher) Notify(ctx context.Context, userID uuid.UUID, msg Message) error {
+	const op = "Dispatcher.Notify"
+
+	stored, err := d.prefs.FindByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: could not load preferences: %w", op, err)
+	}
+	prefs := domain.DefaultNotificationPreferences().Merge(stored)
+
+	var user *domain.User
+	var errs []error
+	for _, sender := range d.senders {
+		if !prefs.Allows(msg.Category, sender.Channel()) {
+			d.logger.Debug("notification skipped by user preferences", "op", op, "user_id", userID, "category", msg.Category, "channel", sender.Channel())
+			continue
+		}
+
+		// Load the recipient only when at least one channel is allowed
+		if user == nil {
+			if user, err = d.users.FindByID(ctx, userID); err != nil {
+				return fmt.Errorf("%s: could not load user: %w", op, err)
+			}
+			if msg.Template != "" {
+				rendered, err := d.templates.Render(ctx, msg.Template, cmp.Or(msg.Locale, user.Locale), user, msg.Data)
+				if err != nil {
+					return fmt.Errorf("%s: render %s: %w", op, msg.Template, err)
+				}
+				msg.Subject, msg.Body = rendered.Subject, rendered.Body
+			}
+		}
+		if sender.Channel() == domain.NotificationChannelSMS && !user.HasVerifiedPhone() {
+			d.logger.Debug("notification skipped, user has no verified phone number", "op", op, "user_id", userID, "category", msg.Category)
+			continue
+		}
+
+		if err := sender.Send(ctx, user, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s channel: %w", sender.Channel(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// LogSender is a Sender that writes messages to the application log.
+// Used for channels without a configured delivery provider.
+type LogSender struct {
+	channel string
+	logger  logger.Logger
+}
+
+// NewLogSender creates a new log sender for the given channel.
+func NewLogSender(channel string, logger logger.Logger) *LogSender {
+	return &LogSender{channel: channel, logger: logger}
+}
+
+// Channel returns the channel this sender delivers to.
+func (s *LogSender) Channel() string {
+	return s.channel
+}
+
+// Send logs the message instead of delivering it.
+func (s *LogSender) Send(ctx context.Context, user *domain.User, msg Message) error {
+	s.logger.WithTrace(ctx).Info("notification sent",
+		"channel", s.channel,
+		"user_id", user.ID,
+		"email", user.Email,
+		"category", msg.Category,
+		"subject", msg.Subject,
+	)
+	return nil
+}
+
+// AdminNotifier sends operational alerts to the shop administrators.
+type AdminNotifier interface {
+	NotifyAdmins(ctx context.Context, msg Message) error
+}
+
+// AdminMailer is an AdminNotifier sending alerts to a fixed list of addresses.
+type AdminMailer struct {
+	sender    Sender
+	addresses []string
+}
+
+// NewAdminMailer creates a new admin mailer delivering through the email sender.
+func NewAdminMailer(sender Sender, addresses ...string) *AdminMailer {
+	return &AdminMailer{sender: sender, addresses: addresses}
+}
+
+// NotifyAdmins sends the message to every address. Admin alerts ignore notification preferences.
+// Returns a joined error of all failed deliveries.
+func (m *AdminMailer) NotifyAdmins(ctx context.Context, msg Message) error {
+	var errs []error
+	for _, address := range m.addresses {
+		if err := m.sender.Send(ctx, &domain.User{Email: address}, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", address, err))
+		}
+	}
+	return errors.Join(errs...)
+}
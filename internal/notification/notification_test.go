@@ -0,0 +1,72 @@
+package notification_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/notification"
+	notificationmocks "product-api/internal/notification/mocks"
+	"product-api/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcher_SendsSMSOnlyToVerifiedPhones(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewMockUserRepository(t)
+	prefs := mocks.NewMockPreferenceRepository(t)
+	templateRepo := mocks.NewMockNotificationTemplateRepository(t)
+	email := notificationmocks.NewMockSender(t)
+	sms := notificationmocks.NewMockSender(t)
+	email.EXPECT().Channel().Return(domain.NotificationChannelEmail)
+	sms.EXPECT().Channel().Return(domain.NotificationChannelSMS)
+	templateRepo.EXPECT().FindLatest(mock.Anything).Return(nil, nil)
+	dispatcher := notification.NewDispatcher(users, prefs, notification.NewTemplates(templateRepo, time.Hour, logger.NewSlogAdapter("local")),
+		logger.NewSlogAdapter("local"), email, sms)
+
+	verifiedAt := time.Now()
+	user := &domain.User{ID: uuid.New(), Firstname: "Ada", Email: "ada@example.com", Phone: "+442079460958"}
+	users.EXPECT().FindByID(mock.Anything, user.ID).Return(user, nil)
+	prefs.EXPECT().FindByUserID(mock.Anything, user.ID).Return(domain.NotificationPreferences{
+		domain.NotificationCategoryOrderUpdates: {domain.NotificationChannelSMS: true},
+	}, nil)
+	msg := notification.Message{
+		Category: domain.NotificationCategoryOrderUpdates,
+		Template: notification.TemplateOrderShipped,
+		Data:     map[string]string{"OrderNumber": "ORD-2024-000001"},
+	}
+	shipped := mock.MatchedBy(func(m notification.Message) bool {
+		return m.Body == "Your order ORD-2024-000001 has been shipped."
+	})
+
+	// Unverified numbers receive no SMS
+	email.EXPECT().Send(mock.Anything, user, shipped).Return(nil).Twice()
+	require.NoError(t, dispatcher.Notify(ctx, user.ID, msg))
+
+	user.PhoneVerifiedAt = &verifiedAt
+	sms.EXPECT().Send(mock.Anything, user, shipped).Return(nil).Once()
+	require.NoError(t, dispatcher.Notify(ctx, user.ID, msg))
+}
+
+func TestInboxSender_StoresUnreadNotification(t *testing.T) {
+	repo := mocks.NewMockInboxRepository(t)
+	sender := notification.NewInboxSender(repo)
+	user := &domain.User{ID: uuid.New()}
+
+	repo.EXPECT().Create(mock.Anything, mock.MatchedBy(func(n *domain.InboxNotification) bool {
+		return n.ID != uuid.Nil && n.UserID == user.ID && n.Category == domain.NotificationCategoryOrderUpdates &&
+			n.Subject == "Your order is on its way" && n.ReadAt == nil
+	})).Return(nil).Once()
+
+	assert.Equal(t, domain.NotificationChannelInApp, sender.Channel())
+	require.NoError(t, sender.Send(context.Background(), user, notification.Message{
+		Category: domain.NotificationCategoryOrderUpdates,
+		Subject:  "Your order is on its way",
+		Body:     "Your order ORD-2024-000001 has been shipped.",
+	}))
+}
@@ -0,0 +1,335 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/money"
+	"product-api/internal/repository"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Names of the notification templates sent by the application.
+const (
+	TemplateOrderConfirmation = "order_confirmation"
+	TemplateOrderShipped      = "order_shipped"
+	TemplateOrderDelivered    = "order_delivered"
+	TemplateInvoice           = "invoice"
+	TemplatePasswordReset     = "password_reset"
+	TemplateEmailVerification = "email_verification"
+	TemplatePhoneVerification = "phone_verification"
+	TemplateAnnouncement      = "announcement" // Marketing announcements admins send to segments of users
+)
+
+// TemplateSpec describes a notification template: its variables and its built-in content,
+// sent until an admin stores a version of the template.
+type TemplateSpec struct {
+	Name      string
+	Variables map[string]string // Sample value by variable name, used in previews
+	Subject   string
+	Body      string
+}
+
+// recipientVariables are available in every template, with values of the recipient.
+var recipientVariables = map[string]string{
+	"FirstName": "Jane",
+	"LastName":  "Doe",
+	"Email":     "jane.doe@example.com",
+}
+
+var templateSpecs = []TemplateSpec{
+	{
+		Name:      TemplateAnnouncement,
+		Variables: map[string]string{"Headline": "Summer sale", "Message": "All garden furniture is 20% off until Sunday."},
+		Subject:   "{{.Headline}}",
+		Body:      "Hi {{.FirstName}}, {{.Message}}",
+	},
+	{
+		Name:      TemplateEmailVerification,
+		Variables: map[string]string{"VerificationURL": "https://shop.example.com/verify?token=abc123"},
+		Subject:   "Verify your email address",
+		Body:      "Hi {{.FirstName}}, please verify your email address by opening {{.VerificationURL}}.",
+	},
+	{
+		Name: TemplateInvoice,
+		Variables: map[string]string{
+			"InvoiceNumber": "INV-2024-000001",
+			"OrderNumber":   "ORD-2024-000123",
+			"Total":         "$25.00",
+			"IssuedOn":      "03/14/2024",
+			"DocumentURL":   "https://shop.example.com/orders/3fa85f64-5717-4562-b3fc-2c963f66afa6/invoice/document",
+		},
+		Subject: "Your invoice {{.InvoiceNumber}}",
+		Body:    "Your invoice {{.InvoiceNumber}} for order {{.OrderNumber}} over {{.Total}} is available at {{.DocumentURL}}.",
+	},
+	{
+		Name:      TemplateOrderConfirmation,
+		Variables: map[string]string{"OrderNumber": "ORD-2024-000123", "Total": "$25.00"},
+		Subject:   "Order confirmation",
+		Body:      "Your order {{.OrderNumber}} for {{.Total}} has been placed.",
+	},
+	{
+		Name:      TemplateOrderDelivered,
+		Variables: map[string]string{"OrderNumber": "ORD-2024-000123"},
+		Subject:   "Your order has been delivered",
+		Body:      "Your order {{.OrderNumber}} has been delivered. Enjoy!",
+	},
+	{
+		Name:      TemplateOrderShipped,
+		Variables: map[string]string{"OrderNumber": "ORD-2024-000123"},
+		Subject:   "Your order is on its way",
+		Body:      "Your order {{.OrderNumber}} has been shipped.",
+	},
+	{
+		Name:      TemplatePasswordReset,
+		Variables: map[string]string{"ResetURL": "https://shop.example.com/reset?token=abc123", "ExpiresIn": "1 hour"},
+		Subject:   "Reset your password",
+		Body: "Hi {{.FirstName}}, reset your password by opening {{.ResetURL}} within {{.ExpiresIn}}. " +
+			"If you did not ask to reset it, ignore this email.",
+	},
+	{
+		Name:      TemplatePhoneVerification,
+		Variables: map[string]string{"Code": "123456", "ExpiresIn": "10 minutes"},
+		Subject:   "Verify your phone number",
+		Body:      "Your verification code is {{.Code}}. It expires in {{.ExpiresIn}}.",
+	},
+}
+
+// TemplateSpecs returns the specs of all templates, ordered by name.
+// Variables include those of the recipient.
+func TemplateSpecs() []TemplateSpec {
+	specs := make([]TemplateSpec, len(templateSpecs))
+	for i, spec := range templateSpecs {
+		spec.Variables = maps.Clone(spec.Variables)
+		maps.Copy(spec.Variables, recipientVariables)
+		specs[i] = spec
+	}
+	return specs
+}
+
+// LookupTemplateSpec returns the spec of the named template.
+// Returns domain.ErrUnknownTemplate if the application does not send such a template.
+func LookupTemplateSpec(name string) (TemplateSpec, error) {
+	for _, spec := range TemplateSpecs() {
+		if spec.Name == name {
+			return spec, nil
+		}
+	}
+	return TemplateSpec{}, fmt.Errorf("%w: %q", domain.ErrUnknownTemplate, name)
+}
+
+// CheckTemplateData checks that data sets every variable of the named template besides those of the recipient, and no other.
+// Returns domain.ErrUnknownTemplate or domain.ErrInvalidTemplate.
+func CheckTemplateData(name string, data map[string]string) error {
+	spec, err := LookupTemplateSpec(name)
+	if err != nil {
+		return err
+	}
+	for _, variable := range slices.Sorted(maps.Keys(spec.Variables)) {
+		if _, ok := data[variable]; !ok && recipientVariables[variable] == "" {
+			return fmt.Errorf("%w: missing variable %s", domain.ErrInvalidTemplate, variable)
+		}
+	}
+	for _, variable := range slices.Sorted(maps.Keys(data)) {
+		if _, ok := spec.Variables[variable]; !ok || recipientVariables[variable] != "" {
+			return fmt.Errorf("%w: unknown variable %s", domain.ErrInvalidTemplate, variable)
+		}
+	}
+	return nil
+}
+
+// compiledTemplate is a parsed template version.
+type compiledTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// compileTemplate parses the subject and body of the named template and renders them with sample values,
+// so templates with syntax errors or unknown variables are rejected before they are stored.
+// Returns domain.ErrUnknownTemplate or domain.ErrInvalidTemplate.
+func compileTemplate(name, subject, body string) (*compiledTemplate, error) {
+	spec, err := LookupTemplateSpec(name)
+	if err != nil {
+		return nil, err
+	}
+	compiled := &compiledTemplate{}
+	if compiled.subject, err = parseTemplate(name+" subject", subject); err != nil {
+		return nil, err
+	}
+	if compiled.body, err = parseTemplate(name+" body", body); err != nil {
+		return nil, err
+	}
+	if _, err := compiled.render(spec.Variables); err != nil {
+		return nil, err
+	}
+	return compiled, nil
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidTemplate, err)
+	}
+	return t, nil
+}
+
+// render executes the template with the variables.
+func (c *compiledTemplate) render(vars map[string]string) (Message, error) {
+	var subject, body strings.Builder
+	if err := c.subject.Execute(&subject, vars); err != nil {
+		return Message{}, fmt.Errorf("%w: %v", domain.ErrInvalidTemplate, err)
+	}
+	if err := c.body.Execute(&body, vars); err != nil {
+		return Message{}, fmt.Errorf("%w: %v", domain.ErrInvalidTemplate, err)
+	}
+	// Subjects are single header lines
+	return Message{Subject: strings.Join(strings.Fields(subject.String()), " "), Body: body.String()}, nil
+}
+
+// ValidateTemplate checks that the subject and body of the named template parse and use only its variables.
+// Returns domain.ErrUnknownTemplate or domain.ErrInvalidTemplate.
+func ValidateTemplate(name, subject, body string) error {
+	_, err := compileTemplate(name, subject, body)
+	return err
+}
+
+// PreviewTemplate renders the subject and body of the named template with its sample values overridden by vars.
+// Returns domain.ErrUnknownTemplate or domain.ErrInvalidTemplate.
+func PreviewTemplate(name, subject, body string, vars map[string]string) (Message, error) {
+	compiled, err := compileTemplate(name, subject, body)
+	if err != nil {
+		return Message{}, err
+	}
+	spec, _ := LookupTemplateSpec(name)
+	maps.Copy(spec.Variables, vars)
+	return compiled.render(spec.Variables)
+}
+
+// Templates renders messages from the latest versions of notification templates. Versions are reloaded
+// from the repository at most every reload interval, so edited templates are sent without redeploying.
+// Versions are stored per locale and resolved along the fallback chain of the recipient's locale,
+// e.g. "de-AT", "de" and then the default version. Templates without a stored version use their built-in content.
+type Templates struct {
+	repo     repository.NotificationTemplateRepository
+	interval time.Duration
+	logger   logger.Logger
+
+	mu       sync.Mutex
+	loadedAt time.Time                         // Zero if the next render reloads
+	stored   map[templateKey]*compiledTemplate // Latest stored versions by name and locale
+}
+
+// templateKey identifies the stored versions of a template in a locale, empty for the default locale.
+type templateKey struct {
+	name   string
+	locale string
+}
+
+// NewTemplates creates templates reloading stored versions after reloadInterval.
+func NewTemplates(repo repository.NotificationTemplateRepository, reloadInterval time.Duration, logger logger.Logger) *Templates {
+	return &Templates{repo: repo, interval: reloadInterval, logger: logger}
+}
+
+// Reload makes the next render load the latest stored versions, e.g. after a version was stored.
+func (t *Templates) Reload() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.loadedAt = time.Time{}
+}
+
+// TemplatesStatus describes the loaded versions of notification templates.
+type TemplatesStatus struct {
+	LoadedAt time.Time // Zero until versions are loaded
+	Stored   []string  // Templates with a valid stored version, e.g. "invoice" or "invoice/de"; others use their built-in content
+}
+
+// Status returns the state of the loaded versions, without reloading them.
+func (t *Templates) Status() TemplatesStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return TemplatesStatus{LoadedAt: t.loadedAt, Stored: storedNames(t.stored)}
+}
+
+// Render renders the named template in the locale for the recipient with the template variables in data.
+// Returns domain.ErrUnknownTemplate or domain.ErrInvalidTemplate if data lacks variables of the template.
+func (t *Templates) Render(ctx context.Context, name, locale string, recipient *domain.User, data map[string]string) (Message, error) {
+	spec, err := LookupTemplateSpec(name)
+	if err != nil {
+		return Message{}, err
+	}
+	compiled := t.latest(ctx, name, locale)
+	if compiled == nil {
+		if compiled, err = compileTemplate(name, spec.Subject, spec.Body); err != nil {
+			return Message{}, err
+		}
+	}
+
+	vars := map[string]string{
+		"FirstName": recipient.Firstname,
+		"LastName":  recipient.Lastname,
+		"Email":     recipient.Email,
+	}
+	maps.Copy(vars, data)
+	return compiled.render(vars)
+}
+
+// latest returns the latest stored version of the template in the most specific fallback of the locale,
+// reloading versions if they are stale. Returns nil if no valid version is stored.
+func (t *Templates) latest(ctx context.Context, name, locale string) *compiledTemplate {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if time.Since(t.loadedAt) >= t.interval {
+		// Failed reloads keep the loaded versions and are retried after the interval
+		t.loadedAt = time.Now()
+		if err := t.load(ctx); err != nil {
+			t.logger.WithTrace(ctx).Error("failed to reload notification templates", "error", err)
+		}
+	}
+	for _, fallback := range append(money.Fallbacks(locale), "") {
+		if compiled, ok := t.stored[templateKey{name: name, locale: fallback}]; ok {
+			return compiled
+		}
+	}
+	return nil
+}
+
+// load compiles the latest stored versions. Versions no longer valid, e.g. using removed variables, are skipped.
+func (t *Templates) load(ctx context.Context) error {
+	versions, err := t.repo.FindLatest(ctx)
+	if err != nil {
+		return err
+	}
+	stored := make(map[templateKey]*compiledTemplate, len(versions))
+	for _, v := range versions {
+		compiled, err := compileTemplate(v.Name, v.Subject, v.Body)
+		if err != nil {
+			t.logger.WithTrace(ctx).Warn("stored notification template skipped", "name", v.Name, "locale", v.Locale, "version", v.Version, "error", err)
+			continue
+		}
+		stored[templateKey{name: v.Name, locale: v.Locale}] = compiled
+	}
+	t.stored = stored
+	t.logger.Debug("notification templates loaded", "names", storedNames(stored))
+	return nil
+}
+
+// storedNames returns the names of the stored templates with their locale, ordered.
+func storedNames(stored map[templateKey]*compiledTemplate) []string {
+	names := make([]string, 0, len(stored))
+	for key := range stored {
+		name := key.name
+		if key.locale != "" {
+			name += "/" + key.locale
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,108 @@
+package notification_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/notification"
+	"product-api/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var recipient = &domain.User{Firstname: "Ada", Lastname: "Lovelace", Email: "ada@example.com"}
+
+func TestTemplates_Render(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockNotificationTemplateRepository(t)
+	templates := notification.NewTemplates(repo, time.Hour, logger.NewSlogAdapter("local"))
+	data := map[string]string{"OrderNumber": "ORD-2024-000001", "Total": "$10.00"}
+
+	// Built-in content until a version is stored
+	repo.EXPECT().FindLatest(mock.Anything).Return(nil, nil).Once()
+	msg, err := templates.Render(ctx, notification.TemplateOrderConfirmation, "", recipient, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Order confirmation", msg.Subject)
+	assert.Equal(t, "Your order ORD-2024-000001 for $10.00 has been placed.", msg.Body)
+
+	// Stored versions are used once reloaded, invalid ones are skipped
+	repo.EXPECT().FindLatest(mock.Anything).Return([]domain.NotificationTemplate{
+		{Name: notification.TemplateOrderConfirmation, Version: 2, Subject: "Thanks, {{.FirstName}}!", Body: "Order {{.OrderNumber}}: {{.Total}}"},
+		{Name: notification.TemplatePasswordReset, Version: 1, Subject: "Reset", Body: "{{.OrderNumber}}"},
+	}, nil).Once()
+	templates.Reload()
+	msg, err = templates.Render(ctx, notification.TemplateOrderConfirmation, "", recipient, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Thanks, Ada!", msg.Subject)
+	assert.Equal(t, "Order ORD-2024-000001: $10.00", msg.Body)
+
+	msg, err = templates.Render(ctx, notification.TemplatePasswordReset, "", recipient,
+		map[string]string{"ResetURL": "https://example.com/r", "ExpiresIn": "1 hour"})
+	require.NoError(t, err)
+	assert.Equal(t, "Reset your password", msg.Subject, "invalid stored version falls back to the built-in one")
+
+	_, err = templates.Render(ctx, notification.TemplateOrderConfirmation, "", recipient, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidTemplate, "missing variables")
+	_, err = templates.Render(ctx, "newsletter", "", recipient, nil)
+	assert.ErrorIs(t, err, domain.ErrUnknownTemplate)
+}
+
+func TestTemplates_RenderLocale(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockNotificationTemplateRepository(t)
+	templates := notification.NewTemplates(repo, time.Hour, logger.NewSlogAdapter("local"))
+	data := map[string]string{"OrderNumber": "ORD-2024-000001", "Total": "10,00 €"}
+
+	repo.EXPECT().FindLatest(mock.Anything).Return([]domain.NotificationTemplate{
+		{Name: notification.TemplateOrderConfirmation, Version: 1, Subject: "Order confirmation", Body: "Order {{.OrderNumber}}"},
+		{Name: notification.TemplateOrderConfirmation, Locale: "de", Version: 3, Subject: "Bestellbestätigung", Body: "Bestellung {{.OrderNumber}}: {{.Total}}"},
+		{Name: notification.TemplateOrderConfirmation, Locale: "de-CH", Version: 1, Subject: "Bestellbestätigung", Body: "Bestellung {{.OrderNumber}}"},
+	}, nil).Once()
+
+	// The most specific version of the fallback chain is used: locale, language, default
+	msg, err := templates.Render(ctx, notification.TemplateOrderConfirmation, "de-AT", recipient, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Bestellung ORD-2024-000001: 10,00 €", msg.Body)
+	msg, err = templates.Render(ctx, notification.TemplateOrderConfirmation, "de-CH", recipient, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Bestellung ORD-2024-000001", msg.Body)
+	msg, err = templates.Render(ctx, notification.TemplateOrderConfirmation, "fr-FR", recipient, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Order ORD-2024-000001", msg.Body)
+
+	// Templates without stored versions in any locale use the built-in content
+	msg, err = templates.Render(ctx, notification.TemplateOrderShipped, "de-AT", recipient, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Your order is on its way", msg.Subject)
+
+	assert.Equal(t, []string{"order_confirmation", "order_confirmation/de", "order_confirmation/de-CH"}, templates.Status().Stored)
+}
+
+func TestValidateTemplate(t *testing.T) {
+	name := notification.TemplateOrderConfirmation
+	assert.NoError(t, notification.ValidateTemplate(name, "Order {{.OrderNumber}}", "Hi {{.FirstName}}, {{.Total}}"))
+	assert.ErrorIs(t, notification.ValidateTemplate(name, "Order {{.OrderNumber", "body"), domain.ErrInvalidTemplate)
+	assert.ErrorIs(t, notification.ValidateTemplate(name, "Order", "{{.ResetURL}}"), domain.ErrInvalidTemplate)
+	assert.ErrorIs(t, notification.ValidateTemplate("newsletter", "s", "b"), domain.ErrUnknownTemplate)
+}
+
+func TestPreviewTemplate(t *testing.T) {
+	msg, err := notification.PreviewTemplate(notification.TemplateOrderConfirmation, "Order\n{{.OrderNumber}}",
+		"{{.FirstName}}: {{.Total}}", map[string]string{"Total": "€5.00"})
+	require.NoError(t, err)
+	assert.Equal(t, "Order ORD-2024-000123", msg.Subject, "subjects are single lines")
+	assert.Equal(t, "Jane: €5.00", msg.Body)
+}
+
+func TestCheckTemplateData(t *testing.T) {
+	name := notification.TemplateAnnouncement
+	assert.NoError(t, notification.CheckTemplateData(name, map[string]string{"Headline": "Sale", "Message": "20% off"}))
+	assert.ErrorIs(t, notification.CheckTemplateData(name, map[string]string{"Headline": "Sale"}), domain.ErrInvalidTemplate)
+	assert.ErrorIs(t, notification.CheckTemplateData(name, map[string]string{"Headline": "Sale", "Message": "20% off", "FirstName": "Joe"}),
+		domain.ErrInvalidTemplate, "recipient variables are set per recipient")
+	assert.ErrorIs(t, notification.CheckTemplateData("newsletter", nil), domain.ErrUnknownTemplate)
+}
@@ -0,0 +1,103 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"product-api/internal/domain"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoPhone is returned when a text message is sent to a user without a phone number.
+	ErrNoPhone = errors.New("user has no phone number")
+	// ErrNoSMSSender is returned when no sender is configured for the country of the phone number.
+	ErrNoSMSSender = errors.New("no sms sender for the country of the phone number")
+)
+
+// SMSSenders maps country calling codes (without "+", e.g. "1" or "44") to sender IDs of text messages:
+// phone numbers or alphanumeric IDs where the country allows them. The "*" entry is used for other countries.
+type SMSSenders map[string]string
+
+// Lookup returns the sender of text messages to the E.164 phone number, preferring the longest matching
+// calling code, so "1868" (Trinidad and Tobago) takes precedence over "1".
+func (s SMSSenders) Lookup(phone string) (string, bool) {
+	digits := strings.TrimPrefix(phone, "+")
+	var sender, code string
+	for prefix, from := range s {
+		if strings.HasPrefix(digits, prefix) && len(prefix) > len(code) {
+			sender, code = from, prefix
+		}
+	}
+	if sender == "" {
+		sender = s["*"]
+	}
+	return sender, sender != ""
+}
+
+// TwilioConfig contains Twilio account settings.
+type TwilioConfig struct {
+	BaseURL    string // e.g. https://api.twilio.com
+	AccountSID string
+	AuthToken  string
+}
+
+// TwilioSender is a Sender delivering text messages through the Twilio Programmable Messaging API.
+// Messages are sent to the phone number of the user; subjects are not sent.
+type TwilioSender struct {
+	cfg     TwilioConfig
+	senders SMSSenders
+	client  *http.Client
+}
+
+// NewTwilioSender creates a new Twilio sender sending from the senders of the recipients' countries.
+func NewTwilioSender(cfg TwilioConfig, senders SMSSenders) *TwilioSender {
+	return &TwilioSender{cfg: cfg, senders: senders, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Channel returns domain.NotificationChannelSMS.
+func (s *TwilioSender) Channel() string {
+	return domain.NotificationChannelSMS
+}
+
+// Send sends the message body to the user's phone number.
+// Returns ErrNoPhone or ErrNoSMSSender if the message cannot be addressed.
+func (s *TwilioSender) Send(ctx context.Context, user *domain.User, msg Message) error {
+	if user.Phone == "" {
+		return ErrNoPhone
+	}
+	from, ok := s.senders.Lookup(user.Phone)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoSMSSender, user.Phone)
+	}
+
+	form := url.Values{"To": {user.Phone}, "From": {from}, "Body": {msg.Body}}
+	endpoint := s.cfg.BaseURL + "/2010-04-01/Accounts/" + url.PathEscape(s.cfg.AccountSID) + "/Messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.cfg.AccountSID, s.cfg.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("twilio: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&apiErr)
+		return fmt.Errorf("twilio: status %d: error %d: %s", resp.StatusCode, apiErr.Code, apiErr.Message)
+	}
+	return nil
+}
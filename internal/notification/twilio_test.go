@@ -0,0 +1,70 @@
+package notification_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/domain"
+	"product-api/internal/notification"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMSSenders_Lookup(t *testing.T) {
+	senders := notification.SMSSenders{"1": "+15550100", "1868": "+18685550100", "44": "MyShop", "*": "+15550199"}
+
+	tests := []struct {
+		phone string
+		want  string
+	}{
+		{phone: "+12025550123", want: "+15550100"},
+		{phone: "+18685550123", want: "+18685550100"},
+		{phone: "+442079460958", want: "MyShop"},
+		{phone: "+4915123456789", want: "+15550199"},
+	}
+	for _, tt := range tests {
+		got, ok := senders.Lookup(tt.phone)
+		assert.True(t, ok, tt.phone)
+		assert.Equal(t, tt.want, got, tt.phone)
+	}
+
+	_, ok := notification.SMSSenders{"44": "MyShop"}.Lookup("+4915123456789")
+	assert.False(t, ok, "no sender for the country and no default")
+}
+
+func TestTwilioSender_Send(t *testing.T) {
+	var received http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		received = *r
+		if r.PostForm.Get("To") == "+15550000000" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code": 21211, "message": "Invalid 'To' Phone Number", "status": 400}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid": "SM123", "status": "queued"}`))
+	}))
+	defer server.Close()
+
+	sender := notification.NewTwilioSender(notification.TwilioConfig{BaseURL: server.URL, AccountSID: "AC123", AuthToken: "token"},
+		notification.SMSSenders{"44": "MyShop", "1": "+15550100"})
+	assert.Equal(t, domain.NotificationChannelSMS, sender.Channel())
+	msg := notification.Message{Subject: "Your order is on its way", Body: "Your order ORD-2024-000001 has been shipped."}
+
+	require.NoError(t, sender.Send(context.Background(), &domain.User{Phone: "+442079460958"}, msg))
+	assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", received.URL.Path)
+	sid, token, _ := received.BasicAuth()
+	assert.Equal(t, "AC123", sid)
+	assert.Equal(t, "token", token)
+	assert.Equal(t, "+442079460958", received.PostForm.Get("To"))
+	assert.Equal(t, "MyShop", received.PostForm.Get("From"), "sender of the recipient's country")
+	assert.Equal(t, msg.Body, received.PostForm.Get("Body"))
+
+	err := sender.Send(context.Background(), &domain.User{Phone: "+15550000000"}, msg)
+	assert.ErrorContains(t, err, "Invalid 'To' Phone Number")
+	assert.ErrorIs(t, sender.Send(context.Background(), &domain.User{Phone: "+4915123456789"}, msg), notification.ErrNoSMSSender)
+	assert.ErrorIs(t, sender.Send(context.Background(), &domain.User{}, msg), notification.ErrNoPhone)
+}
@@ -0,0 +1,160 @@
+// Package oidc implements the OpenID Connect relying-party flow (authorization code)
+// for enterprise identity providers such as Okta or Azure AD.
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+var (
+	// ErrInvalidNonce is returned when the ID token nonce does not match the one sent in the auth request.
+	ErrInvalidNonce = errors.New("id token nonce mismatch")
+	// ErrMissingIDToken is returned when the token response does not contain an ID token.
+	ErrMissingIDToken = errors.New("token response has no id_token")
+)
+
+// ProviderConfig contains settings of a single OIDC identity provider.
+type ProviderConfig struct {
+	Name         string // Provider name used in URLs, e.g. "okta"
+	IssuerURL    string // Issuer URL; discovery document is loaded from IssuerURL/.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // Callback URL registered at the provider
+}
+
+// Claims contains identity claims extracted from a verified ID token.
+type Claims struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	Birthdate     string `json:"birthdate"` // Optional standard claim, format YYYY-MM-DD
+}
+
+// Provider is an OIDC identity provider.
+// Discovery is performed lazily on first use and retried until it succeeds,
+// so an unreachable provider does not prevent the application from starting.
+type Provider struct {
+	cfg ProviderConfig
+
+	mu       sync.Mutex
+	oauth2   *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+// NewProvider creates a new OIDC provider.
+func NewProvider(cfg ProviderConfig) *Provider {
+	return &Provider{cfg: cfg}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return p.cfg.Name
+}
+
+// AuthCodeURL returns the provider URL to redirect the user to for authentication.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce string) (string, error) {
+	oauth2Cfg, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return oauth2Cfg.AuthCodeURL(state, gooidc.Nonce(nonce)), nil
+}
+
+// Exchange exchanges the authorization code for tokens and returns the verified ID token claims.
+func (p *Provider) Exchange(ctx context.Context, code, nonce string) (*Claims, error) {
+	oauth2Cfg, verifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := oauth2Cfg.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("code exchange failed: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, ErrMissingIDToken
+	}
+
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("id token verification failed: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, ErrInvalidNonce
+	}
+
+	var claims Claims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to parse id token claims: %w", err)
+	}
+	claims.Subject = idToken.Subject
+
+	return &claims, nil
+}
+
+// discover loads the provider discovery document once.
+func (p *Provider) discover(ctx context.Context) (*oauth2.Config, *gooidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth2 != nil {
+		return p.oauth2, p.verifier, nil
+	}
+
+	provider, err := gooidc.NewProvider(ctx, p.cfg.IssuerURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("oidc discovery for %s failed: %w", p.cfg.Name, err)
+	}
+
+	p.oauth2 = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       []string{gooidc.ScopeOpenID, "email", "profile"},
+	}
+	p.verifier = provider.Verifier(&gooidc.Config{ClientID: p.cfg.ClientID})
+
+	return p.oauth2, p.verifier, nil
+}
+
+// Registry holds configured OIDC providers by name.
+type Registry struct {
+	providers map[string]*Provider
+}
+
+// NewRegistry creates a registry of the given providers.
+func NewRegistry(configs ...ProviderConfig) *Registry {
+	r := &Registry{providers: make(map[string]*Provider, len(configs))}
+	for _, cfg := range configs {
+		r.providers[cfg.Name] = NewProvider(cfg)
+	}
+	return r
+}
+
+// Get returns the provider with the given name.
+func (r *Registry) Get(name string) (*Provider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// Names returns the names of all configured providers in alphabetical order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
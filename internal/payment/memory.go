@@ -0,0 +1,243 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"product-api/internal/logger"
+	"product-api/internal/money"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Payment sources the memory provider treats specially, for testing checkouts.
+const (
+	DeclinedSource = "declined" // Authorization is declined
+	PendingSource  = "pending"  // Capture is pending, its outcome is sent by webhook
+)
+
+// memoryAuthorization is an authorization kept by the memory provider.
+type memoryAuthorization struct {
+	amount   int64
+	pending  bool // Captures are pending
+	captured bool
+	voided   bool
+}
+
+// MemoryProvider is a mock Provider that keeps payments in memory and writes them to the application log.
+// It approves every payment except from DeclinedSource, and enforces that captures and refunds
+// do not exceed the authorized and captured amounts. Used when no real provider is configured and in tests.
+type MemoryProvider struct {
+	secret []byte // Webhooks are signed with HMAC-SHA256 of the body under this secret
+	logger logger.Logger
+
+	mu             sync.Mutex
+	authorizations map[string]*memoryAuthorization
+	captures       map[string]int64 // Captured minus refunded amount by capture ID
+	refunds        map[string]bool  // Refund IDs
+}
+
+// NewMemoryProvider creates a new memory provider. Webhooks are rejected if webhookSecret is empty.
+func NewMemoryProvider(webhookSecret string, logger logger.Logger) *MemoryProvider {
+	return &MemoryProvider{
+		secret:         []byte(webhookSecret),
+		logger:         logger,
+		authorizations: make(map[string]*memoryAuthorization),
+		captures:       make(map[string]int64),
+		refunds:        make(map[string]bool),
+	}
+}
+
+// Name returns "mock".
+func (p *MemoryProvider) Name() string {
+	return "mock"
+}
+
+func (p *MemoryProvider) Authorize(ctx context.Context, req AuthorizeRequest) (*Authorization, error) {
+	if req.Source == DeclinedSource {
+		return nil, ErrDeclined
+	}
+	id := "auth_" + req.IdempotencyKey
+
+	p.mu.Lock()
+	if _, ok := p.authorizations[id]; !ok {
+		p.authorizations[id] = &memoryAuthorization{amount: req.Amount.Minor, pending: req.Source == PendingSource}
+	}
+	p.mu.Unlock()
+
+	p.logger.WithTrace(ctx).Info("payment authorized",
+		"authorization_id", id,
+		"order_id", req.OrderID,
+		"user_id", req.UserID,
+		"amount", req.Amount.String(),
+	)
+	return &Authorization{ID: id, Method: "card"}, nil
+}
+
+func (p *MemoryProvider) Capture(ctx context.Context, req CaptureRequest) (*Capture, error) {
+	id := "cap_" + req.IdempotencyKey
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	auth, ok := p.authorizations[req.AuthorizationID]
+	switch {
+	case !ok || auth.voided:
+		return nil, fmt.Errorf("authorization %s not found", req.AuthorizationID)
+	case auth.captured:
+		if _, ok := p.captures[id]; ok {
+			return &Capture{ID: id, Pending: auth.pending}, nil // Repeated request
+		}
+		return nil, fmt.Errorf("authorization %s already captured", req.AuthorizationID)
+	case req.Amount.Minor > auth.amount:
+		return nil, fmt.Errorf("capture of %s exceeds the authorized amount", req.Amount)
+	}
+	auth.captured = true
+	p.captures[id] = req.Amount.Minor
+
+	p.logger.WithTrace(ctx).Info("payment captured", "capture_id", id, "authorization_id", req.AuthorizationID,
+		"amount", req.Amount.String(), "pending", auth.pending)
+	return &Capture{ID: id, Pending: auth.pending}, nil
+}
+
+func (p *MemoryProvider) Void(ctx context.Context, authorizationID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	auth, ok := p.authorizations[authorizationID]
+	switch {
+	case !ok:
+		return fmt.Errorf("authorization %s not found", authorizationID)
+	case auth.captured:
+		return fmt.Errorf("authorization %s already captured", authorizationID)
+	}
+	auth.voided = true
+
+	p.logger.WithTrace(ctx).Info("payment voided", "authorization_id", authorizationID)
+	return nil
+}
+
+func (p *MemoryProvider) Refund(ctx context.Context, req RefundRequest) (*Refund, error) {
+	id := "ref_" + req.IdempotencyKey
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.refunds[id] {
+		return &Refund{ID: id}, nil // Repeated request
+	}
+	remaining, ok := p.captures[req.CaptureID]
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("capture %s not found", req.CaptureID)
+	case req.Amount.Minor > remaining:
+		return nil, fmt.Errorf("refund of %s exceeds the captured amount", req.Amount)
+	}
+	p.captures[req.CaptureID] = remaining - req.Amount.Minor
+	p.refunds[id] = true
+
+	p.logger.WithTrace(ctx).Info("payment refunded", "refund_id", id, "capture_id", req.CaptureID,
+		"amount", req.Amount.String())
+	return &Refund{ID: id}, nil
+}
+
+// memoryWebhookHeader contains the hex HMAC-SHA256 of the webhook body.
+const memoryWebhookHeader = "Mock-Signature"
+
+// VerifyWebhook checks the Mock-Signature header and decodes the event from the body:
+// {"id": "...", "type": "...", "resource": {...}}. Events of type "dispute" have a resource
+// {"id": "...", "capture_id": "...", "status": "open", "reason": "...", "amount": 1050, "currency": "USD"}
+// and events of type "capture" a resource
+// {"id": "...", "order_id": "...", "status": "completed", "amount": 1050, "currency": "USD"},
+// with amounts in minor units.
+func (p *MemoryProvider) VerifyWebhook(_ context.Context, header http.Header, body []byte) (*WebhookEvent, error) {
+	signature, err := hex.DecodeString(header.Get(memoryWebhookHeader))
+	if err != nil || len(p.secret) == 0 || !hmac.Equal(signature, p.sign(body)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event struct {
+		ID       string          `json:"id"`
+		Type     string          `json:"type"`
+		Resource json.RawMessage `json:"resource"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("decode webhook event: %w", err)
+	}
+	result := &WebhookEvent{ID: event.ID, Type: event.Type, Resource: event.Resource}
+	switch event.Type {
+	case "dispute":
+		if result.Dispute, err = decodeMemoryDispute(event.Resource); err != nil {
+			return nil, err
+		}
+	case "capture":
+		if result.Capture, err = decodeMemoryCapture(event.Resource); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func decodeMemoryDispute(resource []byte) (*Dispute, error) {
+	var dispute struct {
+		ID        string `json:"id"`
+		CaptureID string `json:"capture_id"`
+		Status    string `json:"status"`
+		Reason    string `json:"reason"`
+		Amount    int64  `json:"amount"`
+		Currency  string `json:"currency"`
+	}
+	if err := json.Unmarshal(resource, &dispute); err != nil {
+		return nil, fmt.Errorf("decode dispute: %w", err)
+	}
+	currency, err := money.LookupCurrency(dispute.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("decode dispute: %w", err)
+	}
+	return &Dispute{
+		ID:        dispute.ID,
+		CaptureID: dispute.CaptureID,
+		Status:    dispute.Status,
+		Reason:    dispute.Reason,
+		Amount:    money.Amount{Minor: dispute.Amount, Currency: currency},
+	}, nil
+}
+
+func decodeMemoryCapture(resource []byte) (*CaptureUpdate, error) {
+	var capture struct {
+		ID       string    `json:"id"`
+		OrderID  uuid.UUID `json:"order_id"`
+		Status   string    `json:"status"`
+		Amount   int64     `json:"amount"`
+		Currency string    `json:"currency"`
+	}
+	if err := json.Unmarshal(resource, &capture); err != nil {
+		return nil, fmt.Errorf("decode capture: %w", err)
+	}
+	currency, err := money.LookupCurrency(capture.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("decode capture: %w", err)
+	}
+	return &CaptureUpdate{
+		ID:      capture.ID,
+		OrderID: capture.OrderID,
+		Status:  capture.Status,
+		Method:  "card",
+		Amount:  money.Amount{Minor: capture.Amount, Currency: currency},
+	}, nil
+}
+
+// SignWebhook returns the headers of a webhook with the body, as the provider would send them.
+func (p *MemoryProvider) SignWebhook(body []byte) http.Header {
+	header := http.Header{}
+	header.Set(memoryWebhookHeader, hex.EncodeToString(p.sign(body)))
+	return header
+}
+
+func (p *MemoryProvider) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,173 @@
+// Package payment defines the payment providers used by checkout and refunds.
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"product-api/internal/money"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrDeclined is returned when the payment provider declines a payment.
+	ErrDeclined = errors.New("payment declined")
+	// ErrInvalidSignature is returned when a webhook is not signed by the provider.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// AuthorizeRequest contains data for authorizing the amount of an order.
+type AuthorizeRequest struct {
+	OrderID        uuid.UUID
+	UserID         uuid.UUID
+	Amount         money.Amount
+	Source         string // Provider token of the buyer's payment method, e.g. a PayPal vault ID
+	IdempotencyKey string // Repeated requests with the same key must not authorize twice
+}
+
+// Authorization is an amount reserved at the payment provider, to be captured or voided.
+type Authorization struct {
+	ID       string            // Provider authorization ID
+	Method   string            // Payment method of the buyer, e.g. card or paypal
+	Metadata map[string]string // Provider-specific details stored with the payment
+}
+
+// CaptureRequest contains data for capturing an authorized amount.
+type CaptureRequest struct {
+	AuthorizationID string
+	Amount          money.Amount
+	IdempotencyKey  string // Repeated requests with the same key must not capture twice
+}
+
+// Capture is a captured payment at the provider.
+type Capture struct {
+	ID       string // Provider capture ID, refunds reference it
+	Pending  bool   // Accepted but not settled yet, e.g. under review or a bank debit; the outcome comes by webhook
+	Metadata map[string]string
+}
+
+// RefundRequest contains data for refunding part of a capture.
+type RefundRequest struct {
+	CaptureID      string
+	Amount         money.Amount
+	IdempotencyKey string // Repeated requests with the same key must not refund twice
+}
+
+// Refund is a successful refund at the payment provider.
+type Refund struct {
+	ID string // Provider refund ID
+}
+
+// Dispute statuses.
+const (
+	DisputeOpen = "open" // Under review, the disputed amount is held by the provider
+	DisputeWon  = "won"  // Resolved in favour of the merchant
+	DisputeLost = "lost" // Resolved in favour of the buyer, the amount was charged back
+)
+
+// Capture outcomes reported by webhook.
+const (
+	CaptureCompleted = "completed" // The funds were captured
+	CaptureFailed    = "failed"    // The capture was declined or reversed before settling
+)
+
+// WebhookEvent is a verified notification sent by the payment provider.
+type WebhookEvent struct {
+	ID       string         // Provider event ID, repeated deliveries of the event have the same ID
+	Type     string         // Provider event type, e.g. CUSTOMER.DISPUTE.CREATED
+	Resource []byte         // Provider-specific JSON of the resource the event is about
+	Dispute  *Dispute       // Set for events about a dispute or chargeback
+	Capture  *CaptureUpdate // Set for events about the outcome of a capture
+}
+
+// CaptureUpdate is the outcome of a capture, reported asynchronously for pending captures.
+type CaptureUpdate struct {
+	ID      string    // Provider capture ID
+	OrderID uuid.UUID // Order the capture pays, as passed to Authorize
+	Status  string    // One of the Capture* outcomes
+	Method  string    // Payment method of the buyer, e.g. card or paypal
+	Amount  money.Amount
+}
+
+// Dispute is a chargeback or dispute of a captured payment, as last reported by the provider.
+type Dispute struct {
+	ID        string // Provider dispute ID
+	CaptureID string // Disputed capture
+	Status    string // One of the Dispute* statuses
+	Reason    string // Provider reason code, e.g. MERCHANDISE_OR_SERVICE_NOT_RECEIVED
+	Amount    money.Amount
+}
+
+// Provider is a payment provider. Checkout authorizes the order amount and captures it,
+// an authorization that is not captured is voided and captured payments are reversed with refunds.
+type Provider interface {
+	Name() string
+	Authorize(ctx context.Context, req AuthorizeRequest) (*Authorization, error)
+	Capture(ctx context.Context, req CaptureRequest) (*Capture, error)
+	Void(ctx context.Context, authorizationID string) error
+	Refund(ctx context.Context, req RefundRequest) (*Refund, error)
+	// VerifyWebhook checks the signature of a webhook request and returns its event.
+	// Returns ErrInvalidSignature if the request was not sent by the provider.
+	VerifyWebhook(ctx context.Context, header http.Header, body []byte) (*WebhookEvent, error)
+}
+
+// Registry holds configured payment providers by name.
+type Registry struct {
+	providers map[string]Provider
+	fallback  string
+	sandbox   Provider // Pays orders of sandbox accounts, nil if none is configured
+}
+
+// NewRegistry creates a registry of the given providers. Checkouts that do not select a provider use fallback.
+func NewRegistry(fallback Provider, others ...Provider) *Registry {
+	r := &Registry{providers: map[string]Provider{fallback.Name(): fallback}, fallback: fallback.Name()}
+	for _, p := range others {
+		r.providers[p.Name()] = p
+	}
+	return r
+}
+
+// Get returns the provider with the given name, the default provider if name is empty.
+func (r *Registry) Get(name string) (Provider, bool) {
+	if name == "" {
+		name = r.fallback
+	}
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// WithSandbox sets the provider paying orders of sandbox accounts, whatever provider their checkout selects.
+// Unless also registered, it cannot be selected by checkouts; payments made with it are found with Lookup.
+func (r *Registry) WithSandbox(p Provider) *Registry {
+	r.sandbox = p
+	return r
+}
+
+// Sandbox returns the provider paying orders of sandbox accounts.
+func (r *Registry) Sandbox() (Provider, bool) {
+	return r.sandbox, r.sandbox != nil
+}
+
+// Lookup returns the provider a payment was made with, by name: a configured provider or the sandbox provider.
+// Refunds and webhooks of payments use it, checkouts select providers with Get.
+func (r *Registry) Lookup(name string) (Provider, bool) {
+	if p, ok := r.Get(name); ok {
+		return p, true
+	}
+	if r.sandbox != nil && name == r.sandbox.Name() {
+		return r.sandbox, true
+	}
+	return nil, false
+}
+
+// Names returns the names of all configured providers in alphabetical order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,281 @@
+package payment_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/logger"
+	"product-api/internal/money"
+	"product-api/internal/payment"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func usd(minor int64) money.Amount {
+	c, _ := money.LookupCurrency("USD")
+	return money.Amount{Minor: minor, Currency: c}
+}
+
+func TestMemoryProvider(t *testing.T) {
+	ctx := context.Background()
+	p := payment.NewMemoryProvider("secret", logger.NewSlogAdapter("local"))
+
+	_, err := p.Authorize(ctx, payment.AuthorizeRequest{Amount: usd(1000), Source: payment.DeclinedSource, IdempotencyKey: "o1"})
+	assert.ErrorIs(t, err, payment.ErrDeclined)
+
+	auth, err := p.Authorize(ctx, payment.AuthorizeRequest{Amount: usd(1000), IdempotencyKey: "o1"})
+	require.NoError(t, err)
+	_, err = p.Capture(ctx, payment.CaptureRequest{AuthorizationID: auth.ID, Amount: usd(1001), IdempotencyKey: "o1"})
+	assert.Error(t, err, "capture exceeds the authorization")
+	capture, err := p.Capture(ctx, payment.CaptureRequest{AuthorizationID: auth.ID, Amount: usd(1000), IdempotencyKey: "o1"})
+	require.NoError(t, err)
+	assert.Error(t, p.Void(ctx, auth.ID), "captured authorization cannot be voided")
+
+	_, err = p.Refund(ctx, payment.RefundRequest{CaptureID: capture.ID, Amount: usd(600), IdempotencyKey: "r1"})
+	require.NoError(t, err)
+	_, err = p.Refund(ctx, payment.RefundRequest{CaptureID: capture.ID, Amount: usd(600), IdempotencyKey: "r1"})
+	require.NoError(t, err, "repeated refund is not refunded twice")
+	_, err = p.Refund(ctx, payment.RefundRequest{CaptureID: capture.ID, Amount: usd(500), IdempotencyKey: "r2"})
+	assert.Error(t, err, "refunds exceed the capture")
+
+	pending, err := p.Authorize(ctx, payment.AuthorizeRequest{Amount: usd(1000), Source: payment.PendingSource, IdempotencyKey: "o2"})
+	require.NoError(t, err)
+	capture, err = p.Capture(ctx, payment.CaptureRequest{AuthorizationID: pending.ID, Amount: usd(1000), IdempotencyKey: "o2"})
+	require.NoError(t, err)
+	assert.True(t, capture.Pending)
+}
+
+func TestMemoryProvider_VerifyWebhook(t *testing.T) {
+	ctx := context.Background()
+	p := payment.NewMemoryProvider("secret", logger.NewSlogAdapter("local"))
+	body := []byte(`{"id":"evt_1","type":"dispute","resource":{"id":"dp_1","capture_id":"cap_1","status":"open","reason":"fraudulent","amount":1050,"currency":"USD"}}`)
+
+	event, err := p.VerifyWebhook(ctx, p.SignWebhook(body), body)
+	require.NoError(t, err)
+	assert.Equal(t, "evt_1", event.ID)
+	assert.Equal(t, "dispute", event.Type)
+	require.NotNil(t, event.Dispute)
+	assert.Equal(t, payment.Dispute{ID: "dp_1", CaptureID: "cap_1", Status: payment.DisputeOpen, Reason: "fraudulent", Amount: usd(1050)}, *event.Dispute)
+
+	other := payment.NewMemoryProvider("other", logger.NewSlogAdapter("local"))
+	_, err = p.VerifyWebhook(ctx, other.SignWebhook(body), body)
+	assert.ErrorIs(t, err, payment.ErrInvalidSignature)
+	_, err = payment.NewMemoryProvider("", logger.NewSlogAdapter("local")).VerifyWebhook(ctx, http.Header{}, body)
+	assert.ErrorIs(t, err, payment.ErrInvalidSignature)
+
+	orderID := uuid.New()
+	body = []byte(`{"id":"evt_2","type":"capture","resource":{"id":"cap_1","order_id":"` + orderID.String() + `","status":"completed","amount":1050,"currency":"USD"}}`)
+	event, err = p.VerifyWebhook(ctx, p.SignWebhook(body), body)
+	require.NoError(t, err)
+	require.NotNil(t, event.Capture)
+	assert.Equal(t, payment.CaptureUpdate{ID: "cap_1", OrderID: orderID, Status: payment.CaptureCompleted, Method: "card", Amount: usd(1050)}, *event.Capture)
+}
+
+func TestRegistry_Sandbox(t *testing.T) {
+	stripe := payment.NewStripeProvider(payment.StripeConfig{SecretKey: "sk_test"})
+	mock := payment.NewMemoryProvider("", logger.NewSlogAdapter("local"))
+	r := payment.NewRegistry(stripe).WithSandbox(mock)
+
+	_, ok := r.Get("mock")
+	assert.False(t, ok, "checkouts cannot select the sandbox provider")
+	sandbox, ok := r.Sandbox()
+	require.True(t, ok)
+	assert.Same(t, mock, sandbox)
+
+	found, ok := r.Lookup("mock")
+	require.True(t, ok, "payments of sandbox orders are refunded with it")
+	assert.Same(t, mock, found)
+	found, ok = r.Lookup("stripe")
+	require.True(t, ok)
+	assert.Same(t, stripe, found)
+	_, ok = r.Lookup("paypal")
+	assert.False(t, ok)
+}
+
+func TestPayPalProvider(t *testing.T) {
+	ctx := context.Background()
+	orderID := uuid.New()
+	var tokenRequests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		user, pass, _ := r.BasicAuth()
+		assert.Equal(t, "client:secret", user+":"+pass)
+		_, _ = w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+	})
+	mux.HandleFunc("POST /v2/checkout/orders", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, orderID.String(), r.Header.Get("PayPal-Request-Id"))
+		var body struct {
+			Intent        string `json:"intent"`
+			PurchaseUnits []struct {
+				Amount struct {
+					Value string `json:"value"`
+				} `json:"amount"`
+			} `json:"purchase_units"`
+			PaymentSource struct {
+				PayPal struct {
+					VaultID string `json:"vault_id"`
+				} `json:"paypal"`
+			} `json:"payment_source"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "AUTHORIZE", body.Intent)
+		assert.Equal(t, "12.50", body.PurchaseUnits[0].Amount.Value)
+		if body.PaymentSource.PayPal.VaultID == "declined" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"name":"UNPROCESSABLE_ENTITY","message":"INSTRUMENT_DECLINED"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"PO-1","status":"COMPLETED","purchase_units":[{"payments":{"authorizations":[{"id":"AUTH-1","status":"CREATED"}]}}]}`))
+	})
+	mux.HandleFunc("POST /v2/payments/authorizations/AUTH-1/capture", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"CAP-1","status":"COMPLETED"}`))
+	})
+	mux.HandleFunc("POST /v2/payments/captures/CAP-1/refund", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"REF-1","status":"COMPLETED"}`))
+	})
+	mux.HandleFunc("POST /v1/notifications/verify-webhook-signature", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			WebhookID       string `json:"webhook_id"`
+			TransmissionSig string `json:"transmission_sig"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "WH-1", body.WebhookID)
+		status := "FAILURE"
+		if body.TransmissionSig == "valid" {
+			status = "SUCCESS"
+		}
+		_, _ = w.Write([]byte(`{"verification_status":"` + status + `"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := payment.NewPayPalProvider(payment.PayPalConfig{BaseURL: server.URL, ClientID: "client", ClientSecret: "secret", WebhookID: "WH-1"})
+
+	_, err := p.Authorize(ctx, payment.AuthorizeRequest{OrderID: orderID, Amount: usd(1250), Source: "declined", IdempotencyKey: orderID.String()})
+	assert.ErrorIs(t, err, payment.ErrDeclined)
+
+	auth, err := p.Authorize(ctx, payment.AuthorizeRequest{OrderID: orderID, Amount: usd(1250), Source: "vault-1", IdempotencyKey: orderID.String()})
+	require.NoError(t, err)
+	assert.Equal(t, "AUTH-1", auth.ID)
+	assert.Equal(t, "paypal", auth.Method)
+	assert.Equal(t, "PO-1", auth.Metadata["paypal_order_id"])
+
+	capture, err := p.Capture(ctx, payment.CaptureRequest{AuthorizationID: auth.ID, Amount: usd(1250), IdempotencyKey: orderID.String()})
+	require.NoError(t, err)
+	assert.Equal(t, "CAP-1", capture.ID)
+
+	refund, err := p.Refund(ctx, payment.RefundRequest{CaptureID: capture.ID, Amount: usd(500), IdempotencyKey: "r1"})
+	require.NoError(t, err)
+	assert.Equal(t, "REF-1", refund.ID)
+	assert.Equal(t, 1, tokenRequests, "access token is reused")
+
+	body := []byte(`{"id":"WH-EVT-1","event_type":"CUSTOMER.DISPUTE.RESOLVED","resource":{"dispute_id":"PP-D-1",
+		"reason":"MERCHANDISE_OR_SERVICE_NOT_RECEIVED","status":"RESOLVED","disputed_transactions":[{"seller_transaction_id":"CAP-1"}],
+		"dispute_amount":{"currency_code":"USD","value":"12.50"},"dispute_outcome":{"outcome_code":"RESOLVED_BUYER_FAVOUR"}}}`)
+	header := http.Header{}
+	header.Set("Paypal-Transmission-Sig", "valid")
+	event, err := p.VerifyWebhook(ctx, header, body)
+	require.NoError(t, err)
+	assert.Equal(t, "CUSTOMER.DISPUTE.RESOLVED", event.Type)
+	require.NotNil(t, event.Dispute)
+	assert.Equal(t, payment.Dispute{ID: "PP-D-1", CaptureID: "CAP-1", Status: payment.DisputeLost,
+		Reason: "MERCHANDISE_OR_SERVICE_NOT_RECEIVED", Amount: usd(1250)}, *event.Dispute)
+
+	header.Set("Paypal-Transmission-Sig", "forged")
+	_, err = p.VerifyWebhook(ctx, header, body)
+	assert.ErrorIs(t, err, payment.ErrInvalidSignature)
+}
+
+func TestStripeProvider(t *testing.T) {
+	ctx := context.Background()
+	orderID := uuid.New()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/payment_intents", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer sk_test_1", r.Header.Get("Authorization"))
+		assert.Equal(t, orderID.String(), r.Header.Get("Idempotency-Key"))
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "1250", r.PostForm.Get("amount"))
+		assert.Equal(t, "usd", r.PostForm.Get("currency"))
+		assert.Equal(t, "manual", r.PostForm.Get("capture_method"))
+		assert.Equal(t, orderID.String(), r.PostForm.Get("metadata[order_id]"))
+		if r.PostForm.Get("payment_method") == "pm_card_chargeDeclined" {
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = w.Write([]byte(`{"error":{"type":"card_error","code":"card_declined","message":"Your card was declined."}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"pi_1","status":"requires_capture","latest_charge":"ch_1"}`))
+	})
+	mux.HandleFunc("POST /v1/payment_intents/pi_1/capture", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "1250", r.PostForm.Get("amount_to_capture"))
+		_, _ = w.Write([]byte(`{"id":"pi_1","status":"processing","latest_charge":"ch_1"}`))
+	})
+	mux.HandleFunc("POST /v1/refunds", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "pi_1", r.PostForm.Get("payment_intent"))
+		assert.Equal(t, "500", r.PostForm.Get("amount"))
+		_, _ = w.Write([]byte(`{"id":"re_1","status":"pending"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := payment.NewStripeProvider(payment.StripeConfig{BaseURL: server.URL, SecretKey: "sk_test_1", WebhookSecret: "whsec_1"})
+
+	_, err := p.Authorize(ctx, payment.AuthorizeRequest{OrderID: orderID, Amount: usd(1250), Source: "pm_card_chargeDeclined", IdempotencyKey: orderID.String()})
+	assert.ErrorIs(t, err, payment.ErrDeclined)
+
+	auth, err := p.Authorize(ctx, payment.AuthorizeRequest{OrderID: orderID, Amount: usd(1250), Source: "pm_card_visa", IdempotencyKey: orderID.String()})
+	require.NoError(t, err)
+	assert.Equal(t, "pi_1", auth.ID)
+	assert.Equal(t, "ch_1", auth.Metadata["stripe_charge_id"])
+
+	capture, err := p.Capture(ctx, payment.CaptureRequest{AuthorizationID: auth.ID, Amount: usd(1250), IdempotencyKey: orderID.String()})
+	require.NoError(t, err)
+	assert.Equal(t, "pi_1", capture.ID)
+	assert.True(t, capture.Pending, "processing captures are confirmed by webhook")
+
+	refund, err := p.Refund(ctx, payment.RefundRequest{CaptureID: capture.ID, Amount: usd(500), IdempotencyKey: "r1"})
+	require.NoError(t, err)
+	assert.Equal(t, "re_1", refund.ID)
+
+	body := []byte(`{"id":"evt_1","type":"payment_intent.succeeded","data":{"object":{"id":"pi_1","status":"succeeded",
+		"amount":1250,"amount_received":1250,"currency":"usd","metadata":{"order_id":"` + orderID.String() + `"}}}}`)
+	event, err := p.VerifyWebhook(ctx, stripeSignature("whsec_1", time.Now(), body), body)
+	require.NoError(t, err)
+	require.NotNil(t, event.Capture)
+	assert.Equal(t, payment.CaptureUpdate{ID: "pi_1", OrderID: orderID, Status: payment.CaptureCompleted, Method: "card", Amount: usd(1250)}, *event.Capture)
+
+	body = []byte(`{"id":"evt_2","type":"charge.dispute.closed","data":{"object":{"id":"dp_1","amount":1250,"currency":"usd",
+		"reason":"fraudulent","status":"lost","payment_intent":"pi_1"}}}`)
+	event, err = p.VerifyWebhook(ctx, stripeSignature("whsec_1", time.Now(), body), body)
+	require.NoError(t, err)
+	require.NotNil(t, event.Dispute)
+	assert.Equal(t, payment.Dispute{ID: "dp_1", CaptureID: "pi_1", Status: payment.DisputeLost, Reason: "fraudulent", Amount: usd(1250)}, *event.Dispute)
+
+	_, err = p.VerifyWebhook(ctx, stripeSignature("whsec_other", time.Now(), body), body)
+	assert.ErrorIs(t, err, payment.ErrInvalidSignature)
+	_, err = p.VerifyWebhook(ctx, stripeSignature("whsec_1", time.Now().Add(-time.Hour), body), body)
+	assert.ErrorIs(t, err, payment.ErrInvalidSignature, "replayed webhook")
+}
+
+// stripeSignature returns the headers of a Stripe webhook signed with the secret at the given time.
+func stripeSignature(secret string, at time.Time, body []byte) http.Header {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	header := http.Header{}
+	header.Set("Stripe-Signature", "t="+timestamp+",v1="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
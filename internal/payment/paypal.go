@@ -0,0 +1,348 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"product-api/internal/money"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PayPalConfig contains PayPal REST API credentials.
+type PayPalConfig struct {
+	BaseURL      string // e.g. https://api-m.paypal.com, or https://api-m.sandbox.paypal.com for the sandbox
+	ClientID     string
+	ClientSecret string
+	WebhookID    string // ID of the webhook registered for this application, used to verify webhook signatures
+}
+
+// PayPalProvider is a Provider using the PayPal Orders and Payments v2 APIs.
+// Buyers pay with a vaulted PayPal payment method, passed as AuthorizeRequest.Source.
+type PayPalProvider struct {
+	cfg    PayPalConfig
+	client *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewPayPalProvider creates a new PayPal provider.
+func NewPayPalProvider(cfg PayPalConfig) *PayPalProvider {
+	return &PayPalProvider{cfg: cfg, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Name returns "paypal".
+func (p *PayPalProvider) Name() string {
+	return "paypal"
+}
+
+// paypalAmount is an amount in PayPal requests, e.g. {"currency_code": "USD", "value": "10.50"}.
+type paypalAmount struct {
+	CurrencyCode string `json:"currency_code"`
+	Value        string `json:"value"`
+}
+
+func newPayPalAmount(a money.Amount) paypalAmount {
+	value, _, _ := strings.Cut(a.String(), " ")
+	return paypalAmount{CurrencyCode: a.Currency.Code, Value: value}
+}
+
+func (a paypalAmount) amount() (money.Amount, error) {
+	currency, err := money.LookupCurrency(a.CurrencyCode)
+	if err != nil {
+		return money.Amount{}, err
+	}
+	value, err := strconv.ParseFloat(a.Value, 64)
+	if err != nil {
+		return money.Amount{}, fmt.Errorf("invalid amount %q: %w", a.Value, err)
+	}
+	return money.FromMajor(value, currency), nil
+}
+
+// Authorize creates a PayPal order with the AUTHORIZE intent, paid with the vaulted payment method.
+func (p *PayPalProvider) Authorize(ctx context.Context, req AuthorizeRequest) (*Authorization, error) {
+	body := map[string]any{
+		"intent": "AUTHORIZE",
+		"purchase_units": []map[string]any{{
+			"reference_id": req.OrderID.String(),
+			"custom_id":    req.OrderID.String(),
+			"amount":       newPayPalAmount(req.Amount),
+		}},
+		"payment_source": map[string]any{"paypal": map[string]string{"vault_id": req.Source}},
+	}
+	var resp struct {
+		ID            string `json:"id"`
+		Status        string `json:"status"`
+		PurchaseUnits []struct {
+			Payments struct {
+				Authorizations []struct {
+					ID     string `json:"id"`
+					Status string `json:"status"`
+				} `json:"authorizations"`
+			} `json:"payments"`
+		} `json:"purchase_units"`
+	}
+	if err := p.do(ctx, "/v2/checkout/orders", req.IdempotencyKey, body, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.PurchaseUnits) == 0 || len(resp.PurchaseUnits[0].Payments.Authorizations) == 0 {
+		return nil, fmt.Errorf("%w: paypal order %s is %s without authorization", ErrDeclined, resp.ID, resp.Status)
+	}
+	auth := resp.PurchaseUnits[0].Payments.Authorizations[0]
+	if auth.Status == "DENIED" {
+		return nil, fmt.Errorf("%w: paypal authorization %s denied", ErrDeclined, auth.ID)
+	}
+	return &Authorization{ID: auth.ID, Method: "paypal", Metadata: map[string]string{"paypal_order_id": resp.ID}}, nil
+}
+
+func (p *PayPalProvider) Capture(ctx context.Context, req CaptureRequest) (*Capture, error) {
+	body := map[string]any{"amount": newPayPalAmount(req.Amount), "final_capture": true}
+	var resp struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	path := "/v2/payments/authorizations/" + url.PathEscape(req.AuthorizationID) + "/capture"
+	if err := p.do(ctx, path, req.IdempotencyKey, body, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status == "DECLINED" || resp.Status == "FAILED" {
+		return nil, fmt.Errorf("%w: paypal capture %s is %s", ErrDeclined, resp.ID, resp.Status)
+	}
+	return &Capture{ID: resp.ID, Pending: resp.Status == "PENDING", Metadata: map[string]string{"paypal_capture_status": resp.Status}}, nil
+}
+
+func (p *PayPalProvider) Void(ctx context.Context, authorizationID string) error {
+	return p.do(ctx, "/v2/payments/authorizations/"+url.PathEscape(authorizationID)+"/void", "", nil, nil)
+}
+
+func (p *PayPalProvider) Refund(ctx context.Context, req RefundRequest) (*Refund, error) {
+	body := map[string]any{"amount": newPayPalAmount(req.Amount)}
+	var resp struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := p.do(ctx, "/v2/payments/captures/"+url.PathEscape(req.CaptureID)+"/refund", req.IdempotencyKey, body, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status == "FAILED" || resp.Status == "CANCELLED" {
+		return nil, fmt.Errorf("%w: paypal refund %s is %s", ErrDeclined, resp.ID, resp.Status)
+	}
+	return &Refund{ID: resp.ID}, nil
+}
+
+// VerifyWebhook verifies the transmission signature headers with the PayPal verification API.
+func (p *PayPalProvider) VerifyWebhook(ctx context.Context, header http.Header, body []byte) (*WebhookEvent, error) {
+	if p.cfg.WebhookID == "" || header.Get("Paypal-Transmission-Sig") == "" {
+		return nil, ErrInvalidSignature
+	}
+	req := map[string]any{
+		"auth_algo":         header.Get("Paypal-Auth-Algo"),
+		"cert_url":          header.Get("Paypal-Cert-Url"),
+		"transmission_id":   header.Get("Paypal-Transmission-Id"),
+		"transmission_sig":  header.Get("Paypal-Transmission-Sig"),
+		"transmission_time": header.Get("Paypal-Transmission-Time"),
+		"webhook_id":        p.cfg.WebhookID,
+		"webhook_event":     json.RawMessage(body),
+	}
+	var resp struct {
+		VerificationStatus string `json:"verification_status"`
+	}
+	if err := p.do(ctx, "/v1/notifications/verify-webhook-signature", "", req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.VerificationStatus != "SUCCESS" {
+		return nil, ErrInvalidSignature
+	}
+
+	var event struct {
+		ID        string          `json:"id"`
+		EventType string          `json:"event_type"`
+		Resource  json.RawMessage `json:"resource"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("decode paypal webhook event: %w", err)
+	}
+	result := &WebhookEvent{ID: event.ID, Type: event.EventType, Resource: event.Resource}
+	switch {
+	case strings.HasPrefix(event.EventType, "CUSTOMER.DISPUTE."):
+		dispute, err := parsePayPalDispute(event.Resource)
+		if err != nil {
+			return nil, err
+		}
+		result.Dispute = dispute
+	case event.EventType == "PAYMENT.CAPTURE.COMPLETED" || event.EventType == "PAYMENT.CAPTURE.DENIED":
+		capture, err := parsePayPalCapture(event.Resource)
+		if err != nil {
+			return nil, err
+		}
+		result.Capture = capture
+	}
+	return result, nil
+}
+
+// parsePayPalCapture converts a PayPal capture resource. The order ID is the custom ID set by Authorize.
+func parsePayPalCapture(resource []byte) (*CaptureUpdate, error) {
+	var capture struct {
+		ID       string       `json:"id"`
+		Status   string       `json:"status"`
+		CustomID string       `json:"custom_id"`
+		Amount   paypalAmount `json:"amount"`
+	}
+	if err := json.Unmarshal(resource, &capture); err != nil {
+		return nil, fmt.Errorf("decode paypal capture: %w", err)
+	}
+	orderID, err := uuid.Parse(capture.CustomID)
+	if err != nil {
+		return nil, fmt.Errorf("paypal capture %s: invalid custom ID %q", capture.ID, capture.CustomID)
+	}
+	amount, err := capture.Amount.amount()
+	if err != nil {
+		return nil, fmt.Errorf("paypal capture %s: %w", capture.ID, err)
+	}
+
+	status := CaptureFailed
+	if capture.Status == "COMPLETED" {
+		status = CaptureCompleted
+	}
+	return &CaptureUpdate{ID: capture.ID, OrderID: orderID, Status: status, Method: "paypal", Amount: amount}, nil
+}
+
+// parsePayPalDispute converts a PayPal dispute resource. Disputes resolved with an outcome other than
+// RESOLVED_SELLER_FAVOUR are lost, unresolved disputes in any stage are open.
+func parsePayPalDispute(resource []byte) (*Dispute, error) {
+	var dispute struct {
+		DisputeID            string `json:"dispute_id"`
+		Reason               string `json:"reason"`
+		Status               string `json:"status"`
+		DisputedTransactions []struct {
+			SellerTransactionID string `json:"seller_transaction_id"`
+		} `json:"disputed_transactions"`
+		DisputeAmount  paypalAmount `json:"dispute_amount"`
+		DisputeOutcome struct {
+			OutcomeCode string `json:"outcome_code"`
+		} `json:"dispute_outcome"`
+	}
+	if err := json.Unmarshal(resource, &dispute); err != nil {
+		return nil, fmt.Errorf("decode paypal dispute: %w", err)
+	}
+	if len(dispute.DisputedTransactions) == 0 {
+		return nil, fmt.Errorf("paypal dispute %s has no disputed transaction", dispute.DisputeID)
+	}
+	amount, err := dispute.DisputeAmount.amount()
+	if err != nil {
+		return nil, fmt.Errorf("paypal dispute %s: %w", dispute.DisputeID, err)
+	}
+
+	status := DisputeOpen
+	if dispute.Status == "RESOLVED" {
+		status = DisputeLost
+		if dispute.DisputeOutcome.OutcomeCode == "RESOLVED_SELLER_FAVOUR" {
+			status = DisputeWon
+		}
+	}
+	return &Dispute{
+		ID:        dispute.DisputeID,
+		CaptureID: dispute.DisputedTransactions[0].SellerTransactionID,
+		Status:    status,
+		Reason:    dispute.Reason,
+		Amount:    amount,
+	}, nil
+}
+
+// do sends a POST request with the JSON body to the PayPal API and decodes the JSON response into out.
+// Requests with an idempotency key are safe to retry. Declined payments are returned as ErrDeclined.
+func (p *PayPalProvider) do(ctx context.Context, path, idempotencyKey string, body, out any) error {
+	token, err := p.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	var reqBody io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.BaseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Prefer", "return=representation")
+	if idempotencyKey != "" {
+		req.Header.Set("PayPal-Request-Id", idempotencyKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("paypal %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Name    string `json:"name"`
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&apiErr)
+		if resp.StatusCode == http.StatusUnprocessableEntity {
+			return fmt.Errorf("%w: paypal %s: %s", ErrDeclined, apiErr.Name, apiErr.Message)
+		}
+		return fmt.Errorf("paypal %s: status %d: %s %s", path, resp.StatusCode, apiErr.Name, apiErr.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// token returns an OAuth2 access token of the application, fetched with client credentials
+// and reused until shortly before it expires.
+func (p *PayPalProvider) token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.accessToken != "" && time.Now().Before(p.expiresAt) {
+		return p.accessToken, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.BaseURL+"/v1/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(p.cfg.ClientID, p.cfg.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("paypal token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("paypal token: status %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"` // Seconds
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("paypal token: %w", err)
+	}
+	p.accessToken = token.AccessToken
+	p.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return p.accessToken, nil
+}
@@ -0,0 +1,283 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"product-api/internal/money"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// stripeWebhookTolerance is the maximum age of a webhook signature, older deliveries may be replays.
+const stripeWebhookTolerance = 5 * time.Minute
+
+// StripeConfig contains Stripe API credentials.
+type StripeConfig struct {
+	BaseURL       string // e.g. https://api.stripe.com
+	SecretKey     string // sk_live_... or sk_test_... for test mode
+	WebhookSecret string // Signing secret of the webhook endpoint, whsec_...
+}
+
+// StripeProvider is a Provider using Stripe PaymentIntents with manual capture.
+// Buyers pay with a payment method collected by Stripe.js, passed as AuthorizeRequest.Source, e.g. pm_1Nv...
+// Captures and refunds reference the PaymentIntent.
+type StripeProvider struct {
+	cfg    StripeConfig
+	client *http.Client
+}
+
+// NewStripeProvider creates a new Stripe provider.
+func NewStripeProvider(cfg StripeConfig) *StripeProvider {
+	return &StripeProvider{cfg: cfg, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Name returns "stripe".
+func (p *StripeProvider) Name() string {
+	return "stripe"
+}
+
+// stripePaymentIntent is the part of a Stripe PaymentIntent the provider reads.
+type stripePaymentIntent struct {
+	ID             string            `json:"id"`
+	Status         string            `json:"status"`
+	Amount         int64             `json:"amount"`
+	AmountReceived int64             `json:"amount_received"`
+	Currency       string            `json:"currency"`
+	LatestCharge   string            `json:"latest_charge"`
+	Metadata       map[string]string `json:"metadata"`
+}
+
+// Authorize creates and confirms a PaymentIntent with manual capture, which reserves the amount on the card.
+// Payment methods requiring further buyer action, e.g. 3-D Secure, are declined.
+func (p *StripeProvider) Authorize(ctx context.Context, req AuthorizeRequest) (*Authorization, error) {
+	form := url.Values{
+		"amount":                 {strconv.FormatInt(req.Amount.Minor, 10)},
+		"currency":               {strings.ToLower(req.Amount.Currency.Code)},
+		"payment_method":         {req.Source},
+		"confirm":                {"true"},
+		"capture_method":         {"manual"},
+		"metadata[order_id]":     {req.OrderID.String()},
+		"metadata[user_id]":      {req.UserID.String()},
+		"payment_method_types[]": {"card"},
+	}
+	var intent stripePaymentIntent
+	if err := p.do(ctx, "/v1/payment_intents", req.IdempotencyKey, form, &intent); err != nil {
+		return nil, err
+	}
+	if intent.Status != "requires_capture" {
+		return nil, fmt.Errorf("%w: stripe payment intent %s is %s", ErrDeclined, intent.ID, intent.Status)
+	}
+	return &Authorization{ID: intent.ID, Method: "card", Metadata: map[string]string{"stripe_charge_id": intent.LatestCharge}}, nil
+}
+
+// Capture captures the PaymentIntent. Captures still processing are pending.
+func (p *StripeProvider) Capture(ctx context.Context, req CaptureRequest) (*Capture, error) {
+	form := url.Values{"amount_to_capture": {strconv.FormatInt(req.Amount.Minor, 10)}}
+	var intent stripePaymentIntent
+	path := "/v1/payment_intents/" + url.PathEscape(req.AuthorizationID) + "/capture"
+	if err := p.do(ctx, path, req.IdempotencyKey, form, &intent); err != nil {
+		return nil, err
+	}
+	switch intent.Status {
+	case "succeeded", "processing":
+	default:
+		return nil, fmt.Errorf("%w: stripe payment intent %s is %s", ErrDeclined, intent.ID, intent.Status)
+	}
+	return &Capture{ID: intent.ID, Pending: intent.Status == "processing", Metadata: map[string]string{"stripe_charge_id": intent.LatestCharge}}, nil
+}
+
+func (p *StripeProvider) Void(ctx context.Context, authorizationID string) error {
+	return p.do(ctx, "/v1/payment_intents/"+url.PathEscape(authorizationID)+"/cancel", "", url.Values{}, nil)
+}
+
+func (p *StripeProvider) Refund(ctx context.Context, req RefundRequest) (*Refund, error) {
+	form := url.Values{
+		"payment_intent": {req.CaptureID},
+		"amount":         {strconv.FormatInt(req.Amount.Minor, 10)},
+	}
+	var resp struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := p.do(ctx, "/v1/refunds", req.IdempotencyKey, form, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status == "failed" || resp.Status == "canceled" {
+		return nil, fmt.Errorf("%w: stripe refund %s is %s", ErrDeclined, resp.ID, resp.Status)
+	}
+	return &Refund{ID: resp.ID}, nil
+}
+
+// VerifyWebhook checks the Stripe-Signature header, an HMAC-SHA256 of the timestamp and body under the
+// endpoint's signing secret, and rejects signatures older than five minutes.
+func (p *StripeProvider) VerifyWebhook(_ context.Context, header http.Header, body []byte) (*WebhookEvent, error) {
+	if p.cfg.WebhookSecret == "" || !p.validSignature(header.Get("Stripe-Signature"), body) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Object json.RawMessage `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("decode stripe webhook event: %w", err)
+	}
+	result := &WebhookEvent{ID: event.ID, Type: event.Type, Resource: event.Data.Object}
+	var err error
+	switch event.Type {
+	case "charge.dispute.created", "charge.dispute.updated", "charge.dispute.closed":
+		result.Dispute, err = parseStripeDispute(event.Data.Object)
+	case "payment_intent.succeeded":
+		result.Capture, err = parseStripeCapture(event.Data.Object, CaptureCompleted)
+	case "payment_intent.payment_failed", "payment_intent.canceled":
+		result.Capture, err = parseStripeCapture(event.Data.Object, CaptureFailed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validSignature reports whether one of the v1 signatures of the header matches the body
+// and the signature timestamp is recent.
+func (p *StripeProvider) validSignature(header string, body []byte) bool {
+	var timestamp string
+	var signatures [][]byte
+	for part := range strings.SplitSeq(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(unix, 0)).Abs() > stripeWebhookTolerance {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.cfg.WebhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// stripeAmount converts an amount in minor units of a lowercase currency code.
+func stripeAmount(minor int64, currency string) (money.Amount, error) {
+	c, err := money.LookupCurrency(strings.ToUpper(currency))
+	if err != nil {
+		return money.Amount{}, err
+	}
+	return money.Amount{Minor: minor, Currency: c}, nil
+}
+
+// parseStripeDispute converts a Stripe dispute object. Disputes closed as won, or as inquiries that did not
+// become chargebacks, are won; lost disputes are lost and any other status is open.
+func parseStripeDispute(object []byte) (*Dispute, error) {
+	var dispute struct {
+		ID            string `json:"id"`
+		Amount        int64  `json:"amount"`
+		Currency      string `json:"currency"`
+		Reason        string `json:"reason"`
+		Status        string `json:"status"`
+		PaymentIntent string `json:"payment_intent"`
+	}
+	if err := json.Unmarshal(object, &dispute); err != nil {
+		return nil, fmt.Errorf("decode stripe dispute: %w", err)
+	}
+	amount, err := stripeAmount(dispute.Amount, dispute.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("stripe dispute %s: %w", dispute.ID, err)
+	}
+
+	status := DisputeOpen
+	switch dispute.Status {
+	case "won", "warning_closed":
+		status = DisputeWon
+	case "lost":
+		status = DisputeLost
+	}
+	return &Dispute{ID: dispute.ID, CaptureID: dispute.PaymentIntent, Status: status, Reason: dispute.Reason, Amount: amount}, nil
+}
+
+// parseStripeCapture converts a PaymentIntent object into the capture outcome. The order ID is the metadata set by Authorize.
+func parseStripeCapture(object []byte, status string) (*CaptureUpdate, error) {
+	var intent stripePaymentIntent
+	if err := json.Unmarshal(object, &intent); err != nil {
+		return nil, fmt.Errorf("decode stripe payment intent: %w", err)
+	}
+	orderID, err := uuid.Parse(intent.Metadata["order_id"])
+	if err != nil {
+		return nil, fmt.Errorf("stripe payment intent %s: invalid order ID %q", intent.ID, intent.Metadata["order_id"])
+	}
+	minor := intent.AmountReceived
+	if status == CaptureFailed {
+		minor = intent.Amount
+	}
+	amount, err := stripeAmount(minor, intent.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("stripe payment intent %s: %w", intent.ID, err)
+	}
+	return &CaptureUpdate{ID: intent.ID, OrderID: orderID, Status: status, Method: "card", Amount: amount}, nil
+}
+
+// do sends a POST request with the form to the Stripe API and decodes the JSON response into out.
+// Requests with an idempotency key are safe to retry. Declined payments are returned as ErrDeclined.
+func (p *StripeProvider) do(ctx context.Context, path, idempotencyKey string, form url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.BaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.cfg.SecretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Type    string `json:"type"`
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&apiErr)
+		if resp.StatusCode == http.StatusPaymentRequired || apiErr.Error.Type == "card_error" {
+			return fmt.Errorf("%w: stripe %s: %s", ErrDeclined, apiErr.Error.Code, apiErr.Error.Message)
+		}
+		return fmt.Errorf("stripe %s: status %d: %s %s", path, resp.StatusCode, apiErr.Error.Code, apiErr.Error.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
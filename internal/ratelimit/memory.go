@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the memory limiter forgets buckets that refilled completely.
+const sweepInterval = time.Minute
+
+// MemoryLimiter is a Limiter keeping buckets in memory, for development and single-instance deployments.
+// Every instance counts requests on its own, so N instances allow up to N times the limit.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// bucket is the state of a token bucket.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // When the bucket is full again and can be forgotten
+}
+
+// NewMemoryLimiter creates a new in-memory limiter.
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// Name returns "memory".
+func (l *MemoryLimiter) Name() string {
+	return "memory"
+}
+
+// Allow takes a token from the bucket of the key.
+func (l *MemoryLimiter) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Requests), updated: now}
+		l.buckets[key] = b
+	}
+	result, tokens := take(limit, b.tokens, now.Sub(b.updated))
+	b.tokens, b.updated, b.full = tokens, now, now.Add(result.ResetAfter)
+	return result, nil
+}
+
+// sweep forgets buckets that are full again, they are recreated full on their next request.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(now *time.Time) *MemoryLimiter {
+	l := NewMemoryLimiter()
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestMemoryLimiter_Allow(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now)
+	limit := Limit{Requests: 3, Per: 3 * time.Second}
+	ctx := context.Background()
+
+	// A full bucket allows a burst of the limit
+	for remaining := 2; remaining >= 0; remaining-- {
+		result, err := l.Allow(ctx, "ip:203.0.113.7", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, remaining, result.Remaining)
+		assert.Zero(t, result.RetryAfter)
+	}
+
+	result, err := l.Allow(ctx, "ip:203.0.113.7", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.ResetAfter)
+
+	// Other keys have their own buckets
+	result, err = l.Allow(ctx, "ip:198.51.100.1", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// Tokens refill evenly over the period
+	now = now.Add(1500 * time.Millisecond)
+	result, err = l.Allow(ctx, "ip:203.0.113.7", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result, err = l.Allow(ctx, "ip:203.0.113.7", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	// Refilled buckets hold at most the limit
+	now = now.Add(time.Hour)
+	result, err = l.Allow(ctx, "ip:203.0.113.7", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+}
+
+func TestMemoryLimiter_Sweep(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now)
+	ctx := context.Background()
+
+	_, err := l.Allow(ctx, "user:a", Limit{Requests: 10, Per: time.Second})
+	require.NoError(t, err)
+	_, err = l.Allow(ctx, "user:b", Limit{Requests: 10, Per: time.Hour})
+	require.NoError(t, err)
+
+	// The bucket of user:a is full again and forgotten, user:b is still refilling
+	now = now.Add(sweepInterval)
+	_, err = l.Allow(ctx, "user:c", Limit{Requests: 10, Per: time.Second})
+	require.NoError(t, err)
+	assert.NotContains(t, l.buckets, "user:a")
+	assert.Contains(t, l.buckets, "user:b")
+	assert.Contains(t, l.buckets, "user:c")
+}
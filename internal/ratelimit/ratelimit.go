@@ -0,0 +1,64 @@
+// Package ratelimit limits the rate of requests with token buckets, kept in memory or shared through Redis.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit is the size and refill rate of a token bucket: Requests tokens, refilled evenly over Per.
+// A full bucket allows a burst of Requests requests.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// interval returns the time one token takes to refill.
+func (l Limit) interval() time.Duration {
+	return l.Per / time.Duration(l.Requests)
+}
+
+// Result is the decision on a request and the state of its bucket afterwards.
+type Result struct {
+	Allowed    bool
+	Limit      int           // Size of the bucket
+	Remaining  int           // Whole tokens left in the bucket
+	RetryAfter time.Duration // Time until a token is available, 0 if the request is allowed
+	ResetAfter time.Duration // Time until the bucket is full again
+}
+
+// Limiter takes tokens from buckets identified by keys, e.g. "login:ip:203.0.113.7".
+// Buckets are created full and forgotten once they refill completely.
+type Limiter interface {
+	// Name returns the backend name, e.g. "redis".
+	Name() string
+	// Allow takes a token from the bucket of the key, if one is available.
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// take takes a token from a bucket holding tokens at the moment elapsed time after it was last updated,
+// returning the decision and the tokens left.
+func take(limit Limit, tokens float64, elapsed time.Duration) (Result, float64) {
+	tokens = math.Min(float64(limit.Requests), tokens+float64(elapsed)/float64(limit.interval()))
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	return newResult(limit, allowed, tokens), tokens
+}
+
+// newResult returns the result of a decision leaving tokens in the bucket.
+func newResult(limit Limit, allowed bool, tokens float64) Result {
+	interval := float64(limit.interval())
+	result := Result{
+		Allowed:    allowed,
+		Limit:      limit.Requests,
+		Remaining:  int(tokens),
+		ResetAfter: time.Duration((float64(limit.Requests) - tokens) * interval),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * interval)
+	}
+	return result
+}
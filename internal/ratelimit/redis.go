@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix namespaces the buckets in Redis.
+const keyPrefix = "ratelimit:"
+
+// takeScript takes a token from the bucket hash in KEYS[1] atomically, refilling it by the time passed
+// since its last update. ARGV[1] is the bucket size, ARGV[2] the refill interval of a token in microseconds.
+// The Redis server clock is used, so instances with skewed clocks share buckets consistently.
+// Buckets expire once they refill completely. Returns whether the token was taken and the tokens left.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or capacity
+local updated = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(now - updated, 0) / interval)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.max(math.ceil((capacity - tokens) * interval / 1000), 1))
+return {allowed, tostring(tokens)}
+`)
+
+// RedisLimiter is a Limiter keeping buckets in Redis, shared by all instances of the API.
+type RedisLimiter struct {
+	client redis.Scripter
+}
+
+// NewRedisLimiter creates a new limiter keeping buckets in Redis.
+func NewRedisLimiter(client redis.Scripter) *RedisLimiter {
+	return &RedisLimiter{client: client}
+}
+
+// Name returns "redis".
+func (l *RedisLimiter) Name() string {
+	return "redis"
+}
+
+// Allow takes a token from the bucket of the key.
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	reply, err := takeScript.Run(ctx, l.client, []string{keyPrefix + key},
+		limit.Requests, max(limit.interval().Microseconds(), 1)).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("run rate limit script: %w", err)
+	}
+	if len(reply) != 2 {
+		return Result{}, fmt.Errorf("unexpected rate limit script reply %v", reply)
+	}
+	allowed, _ := reply[0].(int64)
+	remaining, _ := reply[1].(string)
+	tokens, err := strconv.ParseFloat(remaining, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("parse rate limit tokens: %w", err)
+	}
+	return newResult(limit, allowed == 1, tokens), nil
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRedisTestLimiter connects to the Redis server of REDIS_TEST_URL, e.g. "redis://localhost:6379/15",
+// skipping the test if it is not set. The buckets of the keys are deleted before and after the test.
+func newRedisTestLimiter(t *testing.T, keys ...string) (*RedisLimiter, *redis.Client) {
+	t.Helper()
+	url := os.Getenv("REDIS_TEST_URL")
+	if url == "" {
+		t.Skip("REDIS_TEST_URL is not set")
+	}
+	options, err := redis.ParseURL(url)
+	require.NoError(t, err)
+	client := redis.NewClient(options)
+	t.Cleanup(func() { client.Close() })
+
+	ctx := context.Background()
+	require.NoError(t, client.Ping(ctx).Err())
+	for _, key := range keys {
+		require.NoError(t, client.Del(ctx, keyPrefix+key).Err())
+		t.Cleanup(func() { client.Del(context.Background(), keyPrefix+key) })
+	}
+	return NewRedisLimiter(client), client
+}
+
+func TestRedisLimiter_Allow(t *testing.T) {
+	l, client := newRedisTestLimiter(t, "test:ip:203.0.113.7", "test:ip:198.51.100.1")
+	limit := Limit{Requests: 3, Per: 600 * time.Millisecond}
+	ctx := context.Background()
+
+	// A full bucket allows a burst of the limit
+	for remaining := 2; remaining >= 0; remaining-- {
+		result, err := l.Allow(ctx, "test:ip:203.0.113.7", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, remaining, result.Remaining)
+	}
+
+	result, err := l.Allow(ctx, "test:ip:203.0.113.7", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Positive(t, result.RetryAfter)
+	assert.LessOrEqual(t, result.RetryAfter, 200*time.Millisecond)
+
+	// The bucket expires once it would be full again
+	ttl, err := client.PTTL(ctx, keyPrefix+"test:ip:203.0.113.7").Result()
+	require.NoError(t, err)
+	assert.Positive(t, ttl)
+	assert.LessOrEqual(t, ttl, 600*time.Millisecond)
+
+	// Other keys have their own buckets
+	result, err = l.Allow(ctx, "test:ip:198.51.100.1", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// Tokens refill by the Redis server clock
+	time.Sleep(250 * time.Millisecond)
+	result, err = l.Allow(ctx, "test:ip:203.0.113.7", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// Refilled buckets hold at most the limit
+	time.Sleep(time.Second)
+	for range 3 {
+		result, err = l.Allow(ctx, "test:ip:203.0.113.7", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+	result, err = l.Allow(ctx, "test:ip:203.0.113.7", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+}
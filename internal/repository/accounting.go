@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"time"
+)
+
+// AccountingRepository defines the interface for the double-entry journal of the payment ledger.
+type AccountingRepository interface {
+	// FindUnjournaledPayments returns up to limit payments without journal entries, oldest first.
+	// Payments of sandbox orders move no money and are never journaled.
+	FindUnjournaledPayments(ctx context.Context, limit int) ([]domain.Payment, error)
+	// CreateEntries stores journal entries at once. Lines already stored for their payment are skipped.
+	CreateEntries(ctx context.Context, entries []domain.AccountingEntry) error
+	// FindEntries returns the entries of payments made in [from, to), ordered by payment time, payment and line.
+	FindEntries(ctx context.Context, from, to time.Time) ([]domain.AccountingEntry, error)
+}
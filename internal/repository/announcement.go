@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrAnnouncementNotFound is returned when an announcement is not found, or no announcement is ready to be sent.
+	ErrAnnouncementNotFound = errors.New("announcement not found")
+	// ErrAnnouncementLeaseLost is returned when the lease of an announcement was taken over by another instance.
+	ErrAnnouncementLeaseLost = errors.New("announcement lease lost")
+)
+
+// AnnouncementRepository defines the interface for announcements sent to segments of users.
+type AnnouncementRepository interface {
+	Create(ctx context.Context, announcement *domain.Announcement) error
+	FindByID(ctx context.Context, id uuid.UUID) (*domain.Announcement, error)
+	List(ctx context.Context, offset, limit int) ([]domain.Announcement, int, error) // Newest first
+
+	// Claim returns the oldest announcement that is not completed and leases it for the lease duration,
+	// so other instances do not send its next batch. Returns ErrAnnouncementNotFound if none is ready.
+	Claim(ctx context.Context, lease time.Duration) (*domain.Announcement, error)
+	// SaveProgress stores the status, cursor and stats of the announcement and releases its lease, if the lease
+	// is still the one the announcement was claimed with. Returns ErrAnnouncementLeaseLost if another instance
+	// claimed the announcement since, or it does not exist.
+	SaveProgress(ctx context.Context, announcement *domain.Announcement) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+)
+
+var (
+	// ErrAttributeNotFound is returned when the category has no attribute with the name.
+	ErrAttributeNotFound = errors.New("attribute definition not found")
+)
+
+// AttributeRepository defines the interface for per-category product attribute definitions.
+type AttributeRepository interface {
+	FindByCategory(ctx context.Context, category string) ([]domain.AttributeDefinition, error) // Ordered by name
+	Save(ctx context.Context, definition *domain.AttributeDefinition) error                    // Create or replace
+	Delete(ctx context.Context, category, name string) error
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrBulkOperationNotFound is returned when a bulk operation is not found, or no bulk operation is pending.
+var ErrBulkOperationNotFound = errors.New("bulk operation not found")
+
+// BulkOperationRepository defines the interface for price and stock operations applied to products matching a filter.
+type BulkOperationRepository interface {
+	Create(ctx context.Context, operation *domain.BulkOperation) error
+	FindByID(ctx context.Context, id uuid.UUID) (*domain.BulkOperation, error)
+	// FindResults returns a page of the results of the operation in product ID order, of the status if it is not empty,
+	// and the number of such results.
+	FindResults(ctx context.Context, id uuid.UUID, status string, offset, limit int) ([]domain.BulkOperationResult, int, error)
+
+	// CountMatching returns the number of products the operation applies to.
+	CountMatching(ctx context.Context, operation *domain.BulkOperation) (int, error)
+	// FindMatchingTx locks and returns up to limit products the operation applies to after its cursor, in ID order.
+	FindMatchingTx(ctx context.Context, tx pgx.Tx, operation *domain.BulkOperation, limit int) ([]domain.Product, error)
+
+	// ClaimTx locks the oldest operation that is neither completed nor failed within the transaction, skipping
+	// operations locked by other instances. Returns ErrBulkOperationNotFound if none is pending.
+	ClaimTx(ctx context.Context, tx pgx.Tx) (*domain.BulkOperation, error)
+	// SaveProgressTx stores the status, cursor and counts of the operation and the results of its last batch,
+	// and resets its failed attempts.
+	SaveProgressTx(ctx context.Context, tx pgx.Tx, operation *domain.BulkOperation, results []domain.BulkOperationResult) error
+	// RecordFailure counts a failed batch attempt of the operation with its error and marks the operation failed
+	// once it reaches maxAttempts. Returns the updated operation or ErrBulkOperationNotFound.
+	RecordFailure(ctx context.Context, id uuid.UUID, cause string, maxAttempts int) (*domain.BulkOperation, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"time"
+)
+
+var (
+	// ErrChangeNotFound is returned when no change has the sequence number.
+	ErrChangeNotFound = errors.New("change not found")
+)
+
+// ChangeRepository defines the interface for the change feed of product, order and user mutations.
+type ChangeRepository interface {
+	// FindAfter returns changes of finished transactions following the change with sequence number since in feed order,
+	// or from the start of the feed if since is zero.
+	FindAfter(ctx context.Context, since int64, limit int) ([]domain.Change, error)
+	// DeleteBefore deletes up to limit changes of finished transactions made before the time
+	// and returns the number of deleted changes.
+	DeleteBefore(ctx context.Context, before time.Time, limit int) (int, error)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrDocumentNotFound is returned when legal document version is not found in the database.
+	ErrDocumentNotFound = errors.New("legal document not found")
+	// ErrDocumentAlreadyExists is returned when legal document version is already published.
+	ErrDocumentAlreadyExists = errors.New("legal document version already exists")
+)
+
+// ConsentRepository defines the interface for legal document and user consent database operations.
+type ConsentRepository interface {
+	CreateDocument(ctx context.Context, doc *domain.LegalDocument) error
+	FindDocument(ctx context.Context, docType, version string) (*domain.LegalDocument, error)
+	FindLatestDocuments(ctx context.Context) ([]domain.LegalDocument, error)                    // Latest published version of each document type
+	FindPendingDocuments(ctx context.Context, userID uuid.UUID) ([]domain.LegalDocument, error) // Latest versions not accepted by the user
+	CreateConsent(ctx context.Context, consent *domain.Consent) error
+	FindConsentsByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Consent, error)
+}
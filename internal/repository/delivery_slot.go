@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrDeliverySlotNotFound is returned when a delivery slot is not found.
+	ErrDeliverySlotNotFound = errors.New("delivery slot not found")
+	// ErrDeliverySlotExists is returned when the region already has a slot starting at the same time.
+	ErrDeliverySlotExists = errors.New("delivery slot already exists")
+	// ErrDeliverySlotFull is returned when a reservation would exceed the capacity of a delivery slot,
+	// or its capacity would be reduced below its reservations.
+	ErrDeliverySlotFull = errors.New("delivery slot is full")
+)
+
+// DeliverySlotRepository defines the interface for delivery slots and their reserved capacity.
+type DeliverySlotRepository interface {
+	Create(ctx context.Context, slot *domain.DeliverySlot) error
+	UpdateCapacity(ctx context.Context, id uuid.UUID, capacity int) (*domain.DeliverySlot, error)
+	// FindByRegion returns the slots of the region starting in [from, to), earliest first.
+	FindByRegion(ctx context.Context, region string, from, to time.Time) ([]domain.DeliverySlot, error)
+
+	// ReserveTx reserves one order of the slot's capacity within transaction and returns the slot.
+	// Returns ErrDeliverySlotFull if no capacity is left.
+	ReserveTx(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*domain.DeliverySlot, error)
+	// ReleaseTx releases one reservation of the slot within transaction, a slot without reservations is left unchanged.
+	// Returns ErrDeliverySlotNotFound if the slot does not exist.
+	ReleaseTx(ctx context.Context, tx pgx.Tx, id uuid.UUID) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// DisputeRepository defines the interface for disputes of order payments.
+type DisputeRepository interface {
+	// UpsertTx inserts the dispute or updates the status, reason and amount of the stored dispute
+	// with the same provider dispute ID, whose ID and creation time are copied to dispute.
+	// Returns the status before the update, empty for new disputes.
+	UpsertTx(ctx context.Context, tx pgx.Tx, dispute *domain.Dispute) (string, error)
+	FindByOrderIDTx(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) ([]domain.Dispute, error)
+	FindByOrderID(ctx context.Context, orderID uuid.UUID) ([]domain.Dispute, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ErrEmailVerificationNotFound is returned when no pending email verification matches.
+var ErrEmailVerificationNotFound = errors.New("email verification not found")
+
+// EmailVerificationRepository defines the interface for pending email address verifications.
+type EmailVerificationRepository interface {
+	// Upsert stores the verification, replacing a pending verification of the user.
+	Upsert(ctx context.Context, v *domain.EmailVerification) error
+	FindByTokenHash(ctx context.Context, tokenHash string) (*domain.EmailVerification, error)
+	Delete(ctx context.Context, userID uuid.UUID) error
+}
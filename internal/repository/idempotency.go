@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"time"
+)
+
+var (
+	// ErrIdempotencyKeyExists is returned when an unexpired record with the same scope and key already exists.
+	ErrIdempotencyKeyExists = errors.New("idempotency key already exists")
+	// ErrIdempotencyKeyNotFound is returned when idempotency record is not found in the database.
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+)
+
+// IdempotencyRepository defines the interface for idempotency record database operations.
+type IdempotencyRepository interface {
+	Create(ctx context.Context, record *domain.IdempotencyRecord) error // Replaces an expired record with the same key
+	Find(ctx context.Context, scope, key string) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *domain.IdempotencyRecord) error // Store the response
+	Delete(ctx context.Context, scope, key string) error
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int, error) // Delete up to limit records expired before the time, returns the number deleted
+}
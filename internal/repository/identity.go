@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+)
+
+var (
+	// ErrIdentityNotFound is returned when federated identity is not linked to any user.
+	ErrIdentityNotFound = errors.New("identity not found")
+)
+
+// IdentityRepository defines the interface for federated identity database operations.
+type IdentityRepository interface {
+	Create(ctx context.Context, identity *domain.Identity) error
+	Find(ctx context.Context, provider, subject string) (*domain.Identity, error)
+}
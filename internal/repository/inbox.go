@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInboxNotificationNotFound is returned when a notification is not in the user's inbox.
+var ErrInboxNotificationNotFound = errors.New("inbox notification not found")
+
+// InboxRepository defines the interface for notifications in inboxes of users.
+type InboxRepository interface {
+	Create(ctx context.Context, n *domain.InboxNotification) error
+	// List returns the user's notifications newest first, with the total number of matches.
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool, offset, limit int) ([]domain.InboxNotification, int, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	// MarkRead marks the user's notification read at the time, keeping the time of notifications read before.
+	MarkRead(ctx context.Context, userID, id uuid.UUID, at time.Time) error
+	// MarkAllRead marks all unread notifications of the user read.
+	MarkAllRead(ctx context.Context, userID uuid.UUID, at time.Time) error
+}
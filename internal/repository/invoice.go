@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrInvoiceNotFound is returned when the order has no invoice.
+	ErrInvoiceNotFound = errors.New("invoice not found")
+	// ErrInvoiceExists is returned when an invoice was already issued for the order.
+	ErrInvoiceExists = errors.New("invoice already issued")
+)
+
+// InvoiceRepository defines the interface for issued invoices.
+type InvoiceRepository interface {
+	// CreateTx assigns the next sequence and number of the invoice's fiscal year and inserts it.
+	// Other transactions issuing invoices of the year wait until the transaction ends.
+	CreateTx(ctx context.Context, tx pgx.Tx, invoice *domain.Invoice) error
+	FindByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.Invoice, error)
+}
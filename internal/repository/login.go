@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// LoginAttemptRepository defines the interface for login history database operations.
+type LoginAttemptRepository interface {
+	Create(ctx context.Context, attempt *domain.LoginAttempt) error
+	FindByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]domain.LoginAttempt, error) // Newest first
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+)
+
+// ErrTemplateVersionConflict is returned when another version of the template was stored concurrently.
+var ErrTemplateVersionConflict = errors.New("notification template version conflict")
+
+// NotificationTemplateRepository defines the interface for versions of notification templates.
+type NotificationTemplateRepository interface {
+	// Create stores the template as the next version of its name and locale, setting Version.
+	Create(ctx context.Context, template *domain.NotificationTemplate) error
+	// FindLatest returns the latest version of every stored template in every locale.
+	FindLatest(ctx context.Context) ([]domain.NotificationTemplate, error)
+	// FindVersions returns all versions of the template in the locale, newest first.
+	FindVersions(ctx context.Context, name, locale string) ([]domain.NotificationTemplate, error)
+}
@@ -2,15 +2,36 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"product-api/internal/domain"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 )
 
+var (
+	// ErrOrderNotFound is returned when order is not found in the database.
+	ErrOrderNotFound = errors.New("order not found")
+)
+
 // OrderRepository defines the interface for order database operations.
 // CreateTx works within a transaction to ensure operation atomicity.
 type OrderRepository interface {
-	CreateTx(ctx context.Context, tx pgx.Tx, order *domain.Order) error // Create order within transaction
+	CreateTx(ctx context.Context, tx pgx.Tx, order *domain.Order) error // Create order with items and options within transaction
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.Order, error)
+	UpdateStatusTx(ctx context.Context, tx pgx.Tx, order *domain.Order) error                // Update projected status and payment within transaction
+	FindTotalMismatches(ctx context.Context, limit int) ([]domain.OrderTotalMismatch, error) // Orders whose total differs from the sum of items and option fees
+	RepairTotal(ctx context.Context, id uuid.UUID) error                                     // Set total to the sum of items and option fees
+
+	// FindDuplicateTx returns the newest order of the order's user created since the time that is not cancelled
+	// and has the same total and the same products in the same quantities, or ErrOrderNotFound.
+	FindDuplicateTx(ctx context.Context, tx pgx.Tx, order *domain.Order, since time.Time) (*domain.Order, error)
+
+	// FindAwaitingPayment returns IDs of created orders whose last pending or failed capture was recorded before the time,
+	// oldest first.
+	FindAwaitingPayment(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+
+	// FindByUserID returns the user's orders with their items, options and delivery windows, newest first, with the number of the user's orders.
+	FindByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]domain.Order, int, error)
 }
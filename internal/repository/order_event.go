@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrOrderEventConflict is returned when an event with the same sequence was already appended to the order.
+	ErrOrderEventConflict = errors.New("order event sequence conflict")
+)
+
+// OrderEventRepository defines the interface for the append-only order event log.
+type OrderEventRepository interface {
+	AppendTx(ctx context.Context, tx pgx.Tx, events []domain.OrderEvent) error // Append within transaction
+	// FindByOrderID returns events of the order in sequence, only up to until if it is not zero.
+	FindByOrderID(ctx context.Context, orderID uuid.UUID, until time.Time) ([]domain.OrderEvent, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"product-api/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// OrderNumberRepository defines the interface for sequential order numbers and their format.
+type OrderNumberRepository interface {
+	// NextTx returns the next order number of the year. The number is taken only if the transaction commits,
+	// and other transactions taking numbers wait until it ends.
+	NextTx(ctx context.Context, tx pgx.Tx, year int) (string, error)
+	FindSettings(ctx context.Context) (*domain.OrderNumberSettings, error)
+	SaveSettings(ctx context.Context, settings *domain.OrderNumberSettings) error
+}
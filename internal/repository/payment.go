@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrPaymentNotFound is returned when a payment is not found.
+	ErrPaymentNotFound = errors.New("payment not found")
+)
+
+// PaymentRepository defines the interface for the append-only payment ledger of orders.
+type PaymentRepository interface {
+	CreateTx(ctx context.Context, tx pgx.Tx, payment *domain.Payment) error // Append within transaction
+	// FindByOrderIDTx locks the order and returns its payments in the order they were made.
+	// Other transactions changing the order's payments wait until the transaction ends.
+	// Returns ErrOrderNotFound if the order does not exist.
+	FindByOrderIDTx(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) ([]domain.Payment, error)
+	FindByOrderID(ctx context.Context, orderID uuid.UUID) ([]domain.Payment, error)
+	// FindChargeByReference returns the charge made through the provider with the reference, e.g. a capture ID.
+	// Returns ErrPaymentNotFound if there is none.
+	FindChargeByReference(ctx context.Context, provider, reference string) (*domain.Payment, error)
+}
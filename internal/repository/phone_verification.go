@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ErrPhoneVerificationNotFound is returned when the user has no pending phone verification.
+var ErrPhoneVerificationNotFound = errors.New("phone verification not found")
+
+// PhoneVerificationRepository defines the interface for pending phone number verifications.
+type PhoneVerificationRepository interface {
+	// Upsert stores the verification, replacing a pending verification of the user.
+	Upsert(ctx context.Context, v *domain.PhoneVerification) error
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*domain.PhoneVerification, error)
+	IncrementAttempts(ctx context.Context, userID uuid.UUID) error
+	Delete(ctx context.Context, userID uuid.UUID) error
+}
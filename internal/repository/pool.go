@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrPoolExhausted is returned when no database connection becomes available within the acquire timeout,
+// e.g. while slow queries hold all connections of the pool. Failed requests can be retried shortly.
+var ErrPoolExhausted = errors.New("database connection pool exhausted")
+
+// poolExhaustionKey is the context key of the pool exhaustion record of a request.
+type poolExhaustionKey struct{}
+
+// WithPoolExhaustion returns a context recording failures to acquire a database connection in time,
+// so layers not seeing the error, e.g. middleware around handlers responding 500, can tell them apart.
+func WithPoolExhaustion(ctx context.Context) context.Context {
+	return context.WithValue(ctx, poolExhaustionKey{}, new(atomic.Bool))
+}
+
+// RecordPoolExhaustion records that a connection could not be acquired in time, if ctx records them.
+func RecordPoolExhaustion(ctx context.Context) {
+	if exhausted, ok := ctx.Value(poolExhaustionKey{}).(*atomic.Bool); ok {
+		exhausted.Store(true)
+	}
+}
+
+// PoolExhausted reports whether a connection could not be acquired in time within ctx.
+func PoolExhausted(ctx context.Context) bool {
+	exhausted, ok := ctx.Value(poolExhaustionKey{}).(*atomic.Bool)
+	return ok && exhausted.Load()
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AccountingRepository implements repository.AccountingRepository interface for PostgreSQL.
+type AccountingRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAccountingRepository creates a new accounting repository for PostgreSQL.
+func NewAccountingRepository(db *pgxpool.Pool) *AccountingRepository {
+	return &AccountingRepository{db: db}
+}
+
+func (r *AccountingRepository) FindUnjournaledPayments(ctx context.Context, limit int) ([]domain.Payment, error) {
+	query := `
+        SELECT p.id, p.order_id, p.kind, p.method, COALESCE(p.reference, ''), p.amount, p.refund_of, COALESCE(p.provider, ''), p.metadata, p.created_at
+        FROM order_payments p
+        WHERE NOT EXISTS (SELECT 1 FROM accounting_entries e WHERE e.payment_id = p.id)
+          AND NOT EXISTS (SELECT 1 FROM orders o WHERE o.id = p.order_id AND o.sandbox)
+        ORDER BY p.created_at, p.id
+        LIMIT $1
+    `
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanPayments(rows)
+}
+
+// CreateEntries sends the entries in one batch, which runs in an implicit transaction.
+func (r *AccountingRepository) CreateEntries(ctx context.Context, entries []domain.AccountingEntry) error {
+	query := `
+        INSERT INTO accounting_entries (id, payment_id, order_id, order_number, line, account, debit, credit, currency, description, posted_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        ON CONFLICT (payment_id, line) DO NOTHING
+    `
+	batch := &pgx.Batch{}
+	for _, e := range entries {
+		batch.Queue(query, e.ID, e.PaymentID, e.OrderID, e.OrderNumber, e.Line, e.Account, e.Debit, e.Credit,
+			e.Currency, e.Description, e.PostedAt)
+	}
+	return r.db.SendBatch(ctx, batch).Close()
+}
+
+func (r *AccountingRepository) FindEntries(ctx context.Context, from, to time.Time) ([]domain.AccountingEntry, error) {
+	query := `
+        SELECT id, payment_id, order_id, order_number, line, account, debit, credit, currency, description, posted_at
+        FROM accounting_entries
+        WHERE posted_at >= $1 AND posted_at < $2
+        ORDER BY posted_at, payment_id, line
+    `
+	rows, err := r.db.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.AccountingEntry
+	for rows.Next() {
+		var e domain.AccountingEntry
+		if err := rows.Scan(&e.ID, &e.PaymentID, &e.OrderID, &e.OrderNumber, &e.Line, &e.Account, &e.Debit, &e.Credit,
+			&e.Currency, &e.Description, &e.PostedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
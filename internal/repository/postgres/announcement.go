@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// announcementColumns lists announcement columns in the order expected by scanAnnouncement.
+const announcementColumns = `id, template, data, segment, status, cursor_user_id, recipients, sent, opted_out, failed, created_at, completed_at, locked_until`
+
+// AnnouncementRepository implements repository.AnnouncementRepository interface for PostgreSQL.
+type AnnouncementRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAnnouncementRepository creates a new announcement repository for PostgreSQL.
+func NewAnnouncementRepository(db *pgxpool.Pool) *AnnouncementRepository {
+	return &AnnouncementRepository{db: db}
+}
+
+// scanAnnouncement scans a row selected with announcementColumns into an announcement.
+func scanAnnouncement(row pgx.Row, a *domain.Announcement) error {
+	var data, segment []byte
+	var cursor *uuid.UUID
+	if err := row.Scan(&a.ID, &a.Template, &data, &segment, &a.Status, &cursor,
+		&a.Stats.Recipients, &a.Stats.Sent, &a.Stats.OptedOut, &a.Stats.Failed, &a.CreatedAt, &a.CompletedAt, &a.LockedUntil); err != nil {
+		return err
+	}
+	if cursor != nil {
+		a.Cursor = *cursor
+	}
+	if err := json.Unmarshal(data, &a.Data); err != nil {
+		return fmt.Errorf("decode data of announcement %s: %w", a.ID, err)
+	}
+	if err := json.Unmarshal(segment, &a.Segment); err != nil {
+		return fmt.Errorf("decode segment of announcement %s: %w", a.ID, err)
+	}
+	return nil
+}
+
+func (r *AnnouncementRepository) Create(ctx context.Context, a *domain.Announcement) error {
+	data, err := json.Marshal(a.Data)
+	if err != nil {
+		return err
+	}
+	segment, err := json.Marshal(a.Segment)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO announcements (id, template, data, segment, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err = r.db.Exec(ctx, query, a.ID, a.Template, data, segment, a.Status, a.CreatedAt)
+	return err
+}
+
+func (r *AnnouncementRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Announcement, error) {
+	query := `SELECT ` + announcementColumns + ` FROM announcements WHERE id = $1`
+
+	var a domain.Announcement
+	if err := scanAnnouncement(r.db.QueryRow(ctx, query, id), &a); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrAnnouncementNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AnnouncementRepository) List(ctx context.Context, offset, limit int) ([]domain.Announcement, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM announcements`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + announcementColumns + ` FROM announcements ORDER BY created_at DESC, id OFFSET $1 LIMIT $2`
+	rows, err := r.db.Query(ctx, query, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var announcements []domain.Announcement
+	for rows.Next() {
+		var a domain.Announcement
+		if err := scanAnnouncement(rows, &a); err != nil {
+			return nil, 0, err
+		}
+		announcements = append(announcements, a)
+	}
+	return announcements, total, rows.Err()
+}
+
+func (r *AnnouncementRepository) Claim(ctx context.Context, lease time.Duration) (*domain.Announcement, error) {
+	query := `
+		UPDATE announcements SET locked_until = NOW() + make_interval(secs => $1)
+		WHERE id = (
+			SELECT id FROM announcements
+			WHERE status <> 'completed' AND (locked_until IS NULL OR locked_until < NOW())
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + announcementColumns
+
+	var a domain.Announcement
+	if err := scanAnnouncement(r.db.QueryRow(ctx, query, lease.Seconds()), &a); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrAnnouncementNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AnnouncementRepository) SaveProgress(ctx context.Context, a *domain.Announcement) error {
+	var cursor *uuid.UUID
+	if a.Cursor != uuid.Nil {
+		cursor = &a.Cursor
+	}
+	query := `
+		UPDATE announcements
+		SET status = $2, cursor_user_id = $3, recipients = $4, sent = $5, opted_out = $6, failed = $7,
+			completed_at = $8, locked_until = NULL
+		WHERE id = $1 AND locked_until = $9
+	`
+	tag, err := r.db.Exec(ctx, query, a.ID, a.Status, cursor, a.Stats.Recipients, a.Stats.Sent, a.Stats.OptedOut, a.Stats.Failed,
+		a.CompletedAt, a.LockedUntil)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrAnnouncementLeaseLost
+	}
+	a.LockedUntil = nil
+	return nil
+}
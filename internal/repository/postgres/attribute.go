@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AttributeRepository implements repository.AttributeRepository interface for PostgreSQL.
+type AttributeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAttributeRepository creates a new attribute repository for PostgreSQL.
+func NewAttributeRepository(db *pgxpool.Pool) *AttributeRepository {
+	return &AttributeRepository{db: db}
+}
+
+func (r *AttributeRepository) FindByCategory(ctx context.Context, category string) ([]domain.AttributeDefinition, error) {
+	query := `
+        SELECT category, name, type, allowed_values, unit, required
+        FROM attribute_definitions
+        WHERE category = $1
+        ORDER BY name
+    `
+	rows, err := r.db.Query(ctx, query, category)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	definitions := []domain.AttributeDefinition{}
+	for rows.Next() {
+		var d domain.AttributeDefinition
+		if err := rows.Scan(&d.Category, &d.Name, &d.Type, &d.AllowedValues, &d.Unit, &d.Required); err != nil {
+			return nil, err
+		}
+		if len(d.AllowedValues) == 0 {
+			d.AllowedValues = nil
+		}
+		definitions = append(definitions, d)
+	}
+	return definitions, rows.Err()
+}
+
+func (r *AttributeRepository) Save(ctx context.Context, d *domain.AttributeDefinition) error {
+	query := `
+        INSERT INTO attribute_definitions (category, name, type, allowed_values, unit, required)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (category, name) DO UPDATE
+        SET type = EXCLUDED.type, allowed_values = EXCLUDED.allowed_values,
+            unit = EXCLUDED.unit, required = EXCLUDED.required
+    `
+	allowed := d.AllowedValues
+	if allowed == nil {
+		allowed = []string{}
+	}
+	_, err := r.db.Exec(ctx, query, d.Category, d.Name, d.Type, allowed, d.Unit, d.Required)
+	return err
+}
+
+func (r *AttributeRepository) Delete(ctx context.Context, category, name string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM attribute_definitions WHERE category = $1 AND name = $2`, category, name)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrAttributeNotFound
+	}
+	return nil
+}
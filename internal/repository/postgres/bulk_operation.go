@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// bulkOperationColumns lists bulk operation columns in the order expected by scanBulkOperation.
+const bulkOperationColumns = `id, filter, kind, value, actor, status, cursor_product_id, matched, processed, updated, failed, attempts, error, created_at, completed_at`
+
+// bulkOperationMatch is the condition of products matching the filter of an operation: tags ($1), category ($2)
+// and, for stock operations ($3), products that are not bundles. Archived products are not changed.
+const bulkOperationMatch = `(COALESCE(cardinality($1::text[]), 0) = 0 OR tags @> $1)
+	AND ($2 = '' OR category = $2)
+	AND status <> 'archived'
+	AND (NOT $3 OR NOT EXISTS (SELECT 1 FROM product_bundle_components WHERE bundle_id = products.id))`
+
+// BulkOperationRepository implements repository.BulkOperationRepository interface for PostgreSQL.
+type BulkOperationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewBulkOperationRepository creates a new bulk operation repository for PostgreSQL.
+func NewBulkOperationRepository(db *pgxpool.Pool) *BulkOperationRepository {
+	return &BulkOperationRepository{db: db}
+}
+
+// scanBulkOperation scans a row selected with bulkOperationColumns into a bulk operation.
+func scanBulkOperation(row pgx.Row, o *domain.BulkOperation) error {
+	var filter []byte
+	var cursor *uuid.UUID
+	if err := row.Scan(&o.ID, &filter, &o.Kind, &o.Value, &o.Actor, &o.Status, &cursor,
+		&o.Matched, &o.Processed, &o.Updated, &o.Failed, &o.Attempts, &o.Error, &o.CreatedAt, &o.CompletedAt); err != nil {
+		return err
+	}
+	if cursor != nil {
+		o.Cursor = *cursor
+	}
+	if err := json.Unmarshal(filter, &o.Filter); err != nil {
+		return fmt.Errorf("decode filter of bulk operation %s: %w", o.ID, err)
+	}
+	return nil
+}
+
+func (r *BulkOperationRepository) Create(ctx context.Context, o *domain.BulkOperation) error {
+	filter, err := json.Marshal(o.Filter)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO bulk_operations (id, filter, kind, value, actor, status, matched, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err = r.db.Exec(ctx, query, o.ID, filter, o.Kind, o.Value, o.Actor, o.Status, o.Matched, o.CreatedAt)
+	return err
+}
+
+func (r *BulkOperationRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.BulkOperation, error) {
+	query := `SELECT ` + bulkOperationColumns + ` FROM bulk_operations WHERE id = $1`
+
+	var o domain.BulkOperation
+	if err := scanBulkOperation(r.db.QueryRow(ctx, query, id), &o); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrBulkOperationNotFound
+		}
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *BulkOperationRepository) FindResults(ctx context.Context, id uuid.UUID, status string, offset, limit int) ([]domain.BulkOperationResult, int, error) {
+	where := `WHERE operation_id = $1 AND ($2 = '' OR status = $2)`
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM bulk_operation_results `+where, id, status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT product_id, status, error, before_value, after_value FROM bulk_operation_results ` + where +
+		` ORDER BY product_id OFFSET $3 LIMIT $4`
+	rows, err := r.db.Query(ctx, query, id, status, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var results []domain.BulkOperationResult
+	for rows.Next() {
+		var res domain.BulkOperationResult
+		if err := rows.Scan(&res.ProductID, &res.Status, &res.Error, &res.Before, &res.After); err != nil {
+			return nil, 0, err
+		}
+		results = append(results, res)
+	}
+	return results, total, rows.Err()
+}
+
+func (r *BulkOperationRepository) CountMatching(ctx context.Context, o *domain.BulkOperation) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM products WHERE ` + bulkOperationMatch
+	err := r.db.QueryRow(ctx, query, o.Filter.Tags, o.Filter.Category, o.IsStockOperation()).Scan(&count)
+	return count, err
+}
+
+func (r *BulkOperationRepository) FindMatchingTx(ctx context.Context, tx pgx.Tx, o *domain.BulkOperation, limit int) ([]domain.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE ` + bulkOperationMatch + `
+		AND id > $4 ORDER BY id LIMIT $5 FOR UPDATE`
+
+	rows, err := tx.Query(ctx, query, o.Filter.Tags, o.Filter.Category, o.IsStockOperation(), o.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []domain.Product
+	for rows.Next() {
+		var p domain.Product
+		if err := scanProduct(rows, &p); err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+	return products, rows.Err()
+}
+
+// ClaimTx holds the row lock of the operation until the transaction ends, so other instances skip it
+// while the batch is applied.
+func (r *BulkOperationRepository) ClaimTx(ctx context.Context, tx pgx.Tx) (*domain.BulkOperation, error) {
+	query := `
+		SELECT ` + bulkOperationColumns + ` FROM bulk_operations
+		WHERE status NOT IN ('completed', 'failed')
+		ORDER BY created_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var o domain.BulkOperation
+	if err := scanBulkOperation(tx.QueryRow(ctx, query), &o); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrBulkOperationNotFound
+		}
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *BulkOperationRepository) SaveProgressTx(ctx context.Context, tx pgx.Tx, o *domain.BulkOperation, results []domain.BulkOperationResult) error {
+	var cursor *uuid.UUID
+	if o.Cursor != uuid.Nil {
+		cursor = &o.Cursor
+	}
+	query := `
+		UPDATE bulk_operations
+		SET status = $2, cursor_product_id = $3, processed = $4, updated = $5, failed = $6, completed_at = $7,
+		    attempts = 0, error = ''
+		WHERE id = $1
+	`
+	tag, err := tx.Exec(ctx, query, o.ID, o.Status, cursor, o.Processed, o.Updated, o.Failed, o.CompletedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrBulkOperationNotFound
+	}
+
+	rows := make([][]any, len(results))
+	for i, res := range results {
+		rows[i] = []any{o.ID, res.ProductID, res.Status, res.Error, res.Before, res.After}
+	}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"bulk_operation_results"},
+		[]string{"operation_id", "product_id", "status", "error", "before_value", "after_value"}, pgx.CopyFromRows(rows))
+	return err
+}
+
+func (r *BulkOperationRepository) RecordFailure(ctx context.Context, id uuid.UUID, cause string, maxAttempts int) (*domain.BulkOperation, error) {
+	query := `
+		UPDATE bulk_operations
+		SET attempts = attempts + 1, error = $2,
+		    status = CASE WHEN attempts + 1 >= $3 THEN 'failed' ELSE status END
+		WHERE id = $1
+		RETURNING ` + bulkOperationColumns
+
+	var o domain.BulkOperation
+	if err := scanBulkOperation(r.db.QueryRow(ctx, query, id, cause, maxAttempts), &o); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrBulkOperationNotFound
+		}
+		return nil, err
+	}
+	return &o, nil
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ChangeRepository implements repository.ChangeRepository interface for PostgreSQL.
+type ChangeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewChangeRepository creates a new change feed repository for PostgreSQL.
+func NewChangeRepository(db *pgxpool.Pool) *ChangeRepository {
+	return &ChangeRepository{db: db}
+}
+
+// FindAfter orders changes by transaction ID and sequence. Transactions still in progress
+// are excluded together with all later ones, so changes appear only after every change before them.
+func (r *ChangeRepository) FindAfter(ctx context.Context, since int64, limit int) ([]domain.Change, error) {
+	// Position of the first change is before any transaction
+	txid := "0"
+	if since > 0 {
+		err := r.db.QueryRow(ctx, `SELECT txid::text FROM change_log WHERE sequence = $1`, since).Scan(&txid)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, repository.ErrChangeNotFound
+			}
+			return nil, err
+		}
+	}
+
+	query := `
+        SELECT sequence, entity, entity_id, operation, data, changed_at
+        FROM change_log
+        WHERE (txid, sequence) > ($1::xid8, $2)
+          AND txid < pg_snapshot_xmin(pg_current_snapshot())
+        ORDER BY txid, sequence
+        LIMIT $3
+    `
+	rows, err := r.db.Query(ctx, query, txid, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []domain.Change{}
+	for rows.Next() {
+		var c domain.Change
+		if err := rows.Scan(&c.Sequence, &c.Entity, &c.EntityID, &c.Operation, &c.Data, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// DeleteBefore deletes the oldest changes first. Changes of transactions still in progress are kept,
+// as readers have not seen them yet.
+func (r *ChangeRepository) DeleteBefore(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `
+        DELETE FROM change_log
+        WHERE sequence IN (
+            SELECT sequence FROM change_log
+            WHERE changed_at < $1 AND txid < pg_snapshot_xmin(pg_current_snapshot())
+            ORDER BY txid, sequence
+            LIMIT $2
+        )
+    `
+	tag, err := r.db.Exec(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// uniqueViolationCode is the PostgreSQL error code for unique constraint violations.
+const uniqueViolationCode = "23505"
+
+// foreignKeyViolationCode is the PostgreSQL error code for foreign key violations, also of ON DELETE RESTRICT.
+const foreignKeyViolationCode = "23503"
+
+// ConsentRepository implements repository.ConsentRepository interface for PostgreSQL.
+type ConsentRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewConsentRepository creates a new consent repository for PostgreSQL.
+func NewConsentRepository(db *pgxpool.Pool) *ConsentRepository {
+	return &ConsentRepository{db: db}
+}
+
+func (r *ConsentRepository) CreateDocument(ctx context.Context, doc *domain.LegalDocument) error {
+	query := `INSERT INTO legal_documents (id, type, version, published_at) VALUES ($1, $2, $3, $4)`
+
+	_, err := r.db.Exec(ctx, query, doc.ID, doc.Type, doc.Version, doc.PublishedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return repository.ErrDocumentAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *ConsentRepository) FindDocument(ctx context.Context, docType, version string) (*domain.LegalDocument, error) {
+	query := `SELECT id, type, version, published_at FROM legal_documents WHERE type = $1 AND version = $2`
+
+	doc := &domain.LegalDocument{}
+	err := r.db.QueryRow(ctx, query, docType, version).Scan(&doc.ID, &doc.Type, &doc.Version, &doc.PublishedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrDocumentNotFound
+		}
+		return nil, err
+	}
+	return doc, nil
+}
+
+// FindLatestDocuments returns the most recently published version of each document type.
+func (r *ConsentRepository) FindLatestDocuments(ctx context.Context) ([]domain.LegalDocument, error) {
+	query := `
+		SELECT DISTINCT ON (type) id, type, version, published_at
+		FROM legal_documents
+		ORDER BY type, published_at DESC
+	`
+	return r.queryDocuments(ctx, query)
+}
+
+// FindPendingDocuments returns the latest document versions the user has not accepted yet.
+func (r *ConsentRepository) FindPendingDocuments(ctx context.Context, userID uuid.UUID) ([]domain.LegalDocument, error) {
+	query := `
+		SELECT d.id, d.type, d.version, d.published_at
+		FROM (
+			SELECT DISTINCT ON (type) id, type, version, published_at
+			FROM legal_documents
+			ORDER BY type, published_at DESC
+		) d
+		WHERE NOT EXISTS (
+			SELECT 1 FROM user_consents c
+			WHERE c.user_id = $1 AND c.document_type = d.type AND c.document_version = d.version
+		)
+	`
+	return r.queryDocuments(ctx, query, userID)
+}
+
+func (r *ConsentRepository) CreateConsent(ctx context.Context, consent *domain.Consent) error {
+	query := `INSERT INTO user_consents (id, user_id, document_type, document_version, accepted_at)
+			  VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := r.db.Exec(ctx, query, consent.ID, consent.UserID, consent.DocumentType, consent.DocumentVersion, consent.AcceptedAt)
+	return err
+}
+
+func (r *ConsentRepository) FindConsentsByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Consent, error) {
+	query := `
+		SELECT id, user_id, document_type, document_version, accepted_at
+		FROM user_consents
+		WHERE user_id = $1
+		ORDER BY accepted_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var consents []domain.Consent
+	for rows.Next() {
+		var c domain.Consent
+		if err := rows.Scan(&c.ID, &c.UserID, &c.DocumentType, &c.DocumentVersion, &c.AcceptedAt); err != nil {
+			return nil, err
+		}
+		consents = append(consents, c)
+	}
+
+	return consents, rows.Err()
+}
+
+func (r *ConsentRepository) queryDocuments(ctx context.Context, query string, args ...any) ([]domain.LegalDocument, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []domain.LegalDocument
+	for rows.Next() {
+		var d domain.LegalDocument
+		if err := rows.Scan(&d.ID, &d.Type, &d.Version, &d.PublishedAt); err != nil {
+			return nil, err
+		}
+		docs = append(docs, d)
+	}
+
+	return docs, rows.Err()
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// deliverySlotColumns lists delivery slot columns in the order expected by scanDeliverySlot.
+const deliverySlotColumns = `id, region, starts_at, ends_at, capacity, reserved, created_at`
+
+// DeliverySlotRepository implements repository.DeliverySlotRepository interface for PostgreSQL.
+type DeliverySlotRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewDeliverySlotRepository creates a new delivery slot repository for PostgreSQL.
+func NewDeliverySlotRepository(db *pgxpool.Pool) *DeliverySlotRepository {
+	return &DeliverySlotRepository{db: db}
+}
+
+// scanDeliverySlot scans a row selected with deliverySlotColumns into a slot.
+func scanDeliverySlot(row pgx.Row) (*domain.DeliverySlot, error) {
+	var s domain.DeliverySlot
+	if err := row.Scan(&s.ID, &s.Region, &s.StartsAt, &s.EndsAt, &s.Capacity, &s.Reserved, &s.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrDeliverySlotNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *DeliverySlotRepository) Create(ctx context.Context, s *domain.DeliverySlot) error {
+	query := `
+        INSERT INTO delivery_slots (id, region, starts_at, ends_at, capacity, reserved, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+    `
+	_, err := r.db.Exec(ctx, query, s.ID, s.Region, s.StartsAt, s.EndsAt, s.Capacity, s.Reserved, s.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return repository.ErrDeliverySlotExists
+	}
+	return err
+}
+
+func (r *DeliverySlotRepository) UpdateCapacity(ctx context.Context, id uuid.UUID, capacity int) (*domain.DeliverySlot, error) {
+	query := `UPDATE delivery_slots SET capacity = $2 WHERE id = $1 RETURNING ` + deliverySlotColumns
+	slot, err := scanDeliverySlot(r.db.QueryRow(ctx, query, id, capacity))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == "delivery_slots_capacity_check" {
+		return nil, repository.ErrDeliverySlotFull
+	}
+	return slot, err
+}
+
+func (r *DeliverySlotRepository) FindByRegion(ctx context.Context, region string, from, to time.Time) ([]domain.DeliverySlot, error) {
+	query := `
+        SELECT ` + deliverySlotColumns + `
+        FROM delivery_slots
+        WHERE region = $1 AND starts_at >= $2 AND starts_at < $3
+        ORDER BY starts_at
+    `
+	rows, err := r.db.Query(ctx, query, region, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slots := []domain.DeliverySlot{}
+	for rows.Next() {
+		slot, err := scanDeliverySlot(rows)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, *slot)
+	}
+	return slots, rows.Err()
+}
+
+func (r *DeliverySlotRepository) ReserveTx(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*domain.DeliverySlot, error) {
+	// The row lock of the update serializes concurrent reservations, the check constraint refuses overbooking
+	query := `UPDATE delivery_slots SET reserved = reserved + 1 WHERE id = $1 RETURNING ` + deliverySlotColumns
+	slot, err := scanDeliverySlot(tx.QueryRow(ctx, query, id))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == "delivery_slots_capacity_check" {
+		return nil, repository.ErrDeliverySlotFull
+	}
+	return slot, err
+}
+
+func (r *DeliverySlotRepository) ReleaseTx(ctx context.Context, tx pgx.Tx, id uuid.UUID) error {
+	// Releasing a slot without reservations is a no-op, so cancelling its order cannot fail on it
+	tag, err := tx.Exec(ctx, `UPDATE delivery_slots SET reserved = GREATEST(reserved - 1, 0) WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrDeliverySlotNotFound
+	}
+	return nil
+}
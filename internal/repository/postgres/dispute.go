@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// disputesQuery selects disputes of an order in the order scanDisputes expects.
+const disputesQuery = `
+    SELECT id, order_id, payment_id, provider, provider_dispute_id, status, reason, amount, created_at, updated_at
+    FROM order_disputes
+    WHERE order_id = $1
+    ORDER BY created_at, id
+`
+
+// DisputeRepository implements repository.DisputeRepository interface for PostgreSQL.
+type DisputeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewDisputeRepository creates a new dispute repository for PostgreSQL.
+func NewDisputeRepository(db *pgxpool.Pool) *DisputeRepository {
+	return &DisputeRepository{db: db}
+}
+
+// UpsertTx reads the previous status in the same statement, which sees the row as it was before the update.
+func (r *DisputeRepository) UpsertTx(ctx context.Context, tx pgx.Tx, dispute *domain.Dispute) (string, error) {
+	query := `
+        WITH previous AS (
+            SELECT status FROM order_disputes WHERE provider = $4 AND provider_dispute_id = $5
+        )
+        INSERT INTO order_disputes (id, order_id, payment_id, provider, provider_dispute_id, status, reason, amount, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+        ON CONFLICT (provider, provider_dispute_id) DO UPDATE
+            SET status = EXCLUDED.status, reason = EXCLUDED.reason, amount = EXCLUDED.amount, updated_at = EXCLUDED.updated_at
+        RETURNING id, created_at, COALESCE((SELECT status FROM previous), '')
+    `
+	var previous string
+	err := tx.QueryRow(ctx, query, dispute.ID, dispute.OrderID, dispute.PaymentID, dispute.Provider,
+		dispute.ProviderDisputeID, dispute.Status, dispute.Reason, dispute.Amount, dispute.UpdatedAt,
+	).Scan(&dispute.ID, &dispute.CreatedAt, &previous)
+	return previous, err
+}
+
+func (r *DisputeRepository) FindByOrderIDTx(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) ([]domain.Dispute, error) {
+	rows, err := tx.Query(ctx, disputesQuery, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return scanDisputes(rows)
+}
+
+func (r *DisputeRepository) FindByOrderID(ctx context.Context, orderID uuid.UUID) ([]domain.Dispute, error) {
+	rows, err := r.db.Query(ctx, disputesQuery, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return scanDisputes(rows)
+}
+
+// scanDisputes scans and closes rows selected with disputesQuery.
+func scanDisputes(rows pgx.Rows) ([]domain.Dispute, error) {
+	defer rows.Close()
+
+	var disputes []domain.Dispute
+	for rows.Next() {
+		var d domain.Dispute
+		err := rows.Scan(&d.ID, &d.OrderID, &d.PaymentID, &d.Provider, &d.ProviderDisputeID, &d.Status, &d.Reason,
+			&d.Amount, &d.CreatedAt, &d.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		disputes = append(disputes, d)
+	}
+	return disputes, rows.Err()
+}
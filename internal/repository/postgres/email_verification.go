@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EmailVerificationRepository implements repository.EmailVerificationRepository interface for PostgreSQL.
+type EmailVerificationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewEmailVerificationRepository creates a new email verification repository for PostgreSQL.
+func NewEmailVerificationRepository(db *pgxpool.Pool) *EmailVerificationRepository {
+	return &EmailVerificationRepository{db: db}
+}
+
+func (r *EmailVerificationRepository) Upsert(ctx context.Context, v *domain.EmailVerification) error {
+	query := `
+		INSERT INTO email_verifications (user_id, email, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET email = EXCLUDED.email, token_hash = EXCLUDED.token_hash,
+			expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at
+	`
+	_, err := r.db.Exec(ctx, query, v.UserID, v.Email, v.TokenHash, v.ExpiresAt, v.CreatedAt)
+	return err
+}
+
+func (r *EmailVerificationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.EmailVerification, error) {
+	query := `
+		SELECT user_id, email, token_hash, expires_at, created_at
+		FROM email_verifications
+		WHERE token_hash = $1
+	`
+	var v domain.EmailVerification
+	err := r.db.QueryRow(ctx, query, tokenHash).Scan(&v.UserID, &v.Email, &v.TokenHash, &v.ExpiresAt, &v.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrEmailVerificationNotFound
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *EmailVerificationRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	query := `DELETE FROM email_verifications WHERE user_id = $1`
+
+	_, err := r.db.Exec(ctx, query, userID)
+	return err
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IdempotencyRepository implements repository.IdempotencyRepository interface for PostgreSQL.
+type IdempotencyRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewIdempotencyRepository creates a new idempotency record repository for PostgreSQL.
+func NewIdempotencyRepository(db *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Create inserts the record, taking over an expired record with the same scope and key.
+// Concurrent requests with the same key are serialized by the primary key, only one of them succeeds.
+func (r *IdempotencyRepository) Create(ctx context.Context, record *domain.IdempotencyRecord) error {
+	query := `
+		INSERT INTO idempotency_keys (scope, key, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (scope, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, status_code = NULL, content_type = '', response_body = NULL,
+			created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+	`
+	tag, err := r.db.Exec(ctx, query, record.Scope, record.Key, record.RequestHash, record.CreatedAt, record.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrIdempotencyKeyExists
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) Find(ctx context.Context, scope, key string) (*domain.IdempotencyRecord, error) {
+	query := `
+		SELECT scope, key, request_hash, COALESCE(status_code, 0), content_type, COALESCE(response_body, ''::bytea), created_at, expires_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND key = $2
+	`
+	record := &domain.IdempotencyRecord{}
+	err := r.db.QueryRow(ctx, query, scope, key).Scan(&record.Scope, &record.Key, &record.RequestHash,
+		&record.StatusCode, &record.ContentType, &record.Body, &record.CreatedAt, &record.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrIdempotencyKeyNotFound
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	query := `UPDATE idempotency_keys SET status_code = $3, content_type = $4, response_body = $5 WHERE scope = $1 AND key = $2`
+
+	tag, err := r.db.Exec(ctx, query, record.Scope, record.Key, record.StatusCode, record.ContentType, record.Body)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrIdempotencyKeyNotFound
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) Delete(ctx context.Context, scope, key string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2`, scope, key)
+	return err
+}
+
+// DeleteExpired skips records locked by a concurrent Create taking them over.
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE (scope, key) IN (
+			SELECT scope, key FROM idempotency_keys
+			WHERE expires_at < $1
+			ORDER BY expires_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	`
+	tag, err := r.db.Exec(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IdentityRepository implements repository.IdentityRepository interface for PostgreSQL.
+type IdentityRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewIdentityRepository creates a new federated identity repository for PostgreSQL.
+func NewIdentityRepository(db *pgxpool.Pool) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+func (r *IdentityRepository) Create(ctx context.Context, identity *domain.Identity) error {
+	query := `INSERT INTO user_identities (provider, subject, user_id, email, created_at) VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := r.db.Exec(ctx, query, identity.Provider, identity.Subject, identity.UserID, identity.Email, identity.CreatedAt)
+	return err
+}
+
+func (r *IdentityRepository) Find(ctx context.Context, provider, subject string) (*domain.Identity, error) {
+	query := `SELECT provider, subject, user_id, email, created_at FROM user_identities WHERE provider = $1 AND subject = $2`
+
+	identity := &domain.Identity{}
+	err := r.db.QueryRow(ctx, query, provider, subject).Scan(&identity.Provider, &identity.Subject, &identity.UserID, &identity.Email, &identity.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrIdentityNotFound
+		}
+		return nil, err
+	}
+	return identity, nil
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// InboxRepository implements repository.InboxRepository interface for PostgreSQL.
+type InboxRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewInboxRepository creates a new inbox repository for PostgreSQL.
+func NewInboxRepository(db *pgxpool.Pool) *InboxRepository {
+	return &InboxRepository{db: db}
+}
+
+func (r *InboxRepository) Create(ctx context.Context, n *domain.InboxNotification) error {
+	query := `
+		INSERT INTO inbox_notifications (id, user_id, category, subject, body, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.Exec(ctx, query, n.ID, n.UserID, n.Category, n.Subject, n.Body, n.CreatedAt)
+	return err
+}
+
+func (r *InboxRepository) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, offset, limit int) ([]domain.InboxNotification, int, error) {
+	where := `WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)`
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM inbox_notifications `+where, userID, unreadOnly).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, user_id, category, subject, body, created_at, read_at
+		FROM inbox_notifications ` + where + `
+		ORDER BY created_at DESC, id
+		OFFSET $3 LIMIT $4
+	`
+	rows, err := r.db.Query(ctx, query, userID, unreadOnly, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var notifications []domain.InboxNotification
+	for rows.Next() {
+		var n domain.InboxNotification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Category, &n.Subject, &n.Body, &n.CreatedAt, &n.ReadAt); err != nil {
+			return nil, 0, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, total, rows.Err()
+}
+
+func (r *InboxRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM inbox_notifications WHERE user_id = $1 AND read_at IS NULL`
+
+	var count int
+	err := r.db.QueryRow(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *InboxRepository) MarkRead(ctx context.Context, userID, id uuid.UUID, at time.Time) error {
+	query := `UPDATE inbox_notifications SET read_at = COALESCE(read_at, $3) WHERE id = $1 AND user_id = $2`
+
+	tag, err := r.db.Exec(ctx, query, id, userID, at)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrInboxNotificationNotFound
+	}
+	return nil
+}
+
+func (r *InboxRepository) MarkAllRead(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	query := `UPDATE inbox_notifications SET read_at = $2 WHERE user_id = $1 AND read_at IS NULL`
+
+	_, err := r.db.Exec(ctx, query, userID, at)
+	return err
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// InvoiceRepository implements repository.InvoiceRepository interface for PostgreSQL.
+type InvoiceRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewInvoiceRepository creates a new invoice repository for PostgreSQL.
+func NewInvoiceRepository(db *pgxpool.Pool) *InvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+// CreateTx takes the next value of the fiscal year counter, whose row stays locked until the transaction ends.
+// If the invoice cannot be inserted, the transaction fails and the value is released with it.
+func (r *InvoiceRepository) CreateTx(ctx context.Context, tx pgx.Tx, invoice *domain.Invoice) error {
+	counterQuery := `
+        INSERT INTO invoice_counters (fiscal_year, last_value) VALUES ($1, 1)
+        ON CONFLICT (fiscal_year) DO UPDATE SET last_value = invoice_counters.last_value + 1
+        RETURNING last_value
+    `
+	if err := tx.QueryRow(ctx, counterQuery, invoice.FiscalYear).Scan(&invoice.Sequence); err != nil {
+		return err
+	}
+	invoice.Number = domain.InvoiceNumber(invoice.FiscalYear, invoice.Sequence)
+
+	query := `INSERT INTO invoices (id, number, fiscal_year, sequence, order_id, amount, currency, locale, tax_region, issued_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10)`
+	_, err := tx.Exec(ctx, query, invoice.ID, invoice.Number, invoice.FiscalYear, invoice.Sequence, invoice.OrderID,
+		invoice.Amount, invoice.Currency, invoice.Locale, invoice.TaxRegion, invoice.IssuedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == "invoices_order_id_key" {
+			return repository.ErrInvoiceExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *InvoiceRepository) FindByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.Invoice, error) {
+	query := `
+        SELECT id, number, fiscal_year, sequence, order_id, amount, currency, COALESCE(locale, ''), COALESCE(tax_region, ''), issued_at
+        FROM invoices
+        WHERE order_id = $1
+    `
+	inv := &domain.Invoice{}
+	err := r.db.QueryRow(ctx, query, orderID).Scan(&inv.ID, &inv.Number, &inv.FiscalYear, &inv.Sequence, &inv.OrderID,
+		&inv.Amount, &inv.Currency, &inv.Locale, &inv.TaxRegion, &inv.IssuedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+	return inv, nil
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LoginAttemptRepository implements repository.LoginAttemptRepository interface for PostgreSQL.
+type LoginAttemptRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewLoginAttemptRepository creates a new login attempt repository for PostgreSQL.
+func NewLoginAttemptRepository(db *pgxpool.Pool) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+func (r *LoginAttemptRepository) Create(ctx context.Context, attempt *domain.LoginAttempt) error {
+	query := `
+		INSERT INTO login_attempts (id, user_id, login, ip_address, user_agent, success, failure_reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+	`
+	_, err := r.db.Exec(ctx, query, attempt.ID, attempt.UserID, attempt.Login, attempt.IPAddress,
+		attempt.UserAgent, attempt.Success, attempt.FailureReason, attempt.CreatedAt)
+	return err
+}
+
+func (r *LoginAttemptRepository) FindByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]domain.LoginAttempt, error) {
+	query := `
+		SELECT id, user_id, login, ip_address, user_agent, success, COALESCE(failure_reason, ''), created_at
+		FROM login_attempts
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []domain.LoginAttempt
+	for rows.Next() {
+		var a domain.LoginAttempt
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Login, &a.IPAddress, &a.UserAgent, &a.Success, &a.FailureReason, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, a)
+	}
+
+	return attempts, rows.Err()
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NotificationTemplateRepository implements repository.NotificationTemplateRepository interface for PostgreSQL.
+type NotificationTemplateRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewNotificationTemplateRepository creates a new notification template repository for PostgreSQL.
+func NewNotificationTemplateRepository(db *pgxpool.Pool) *NotificationTemplateRepository {
+	return &NotificationTemplateRepository{db: db}
+}
+
+func (r *NotificationTemplateRepository) Create(ctx context.Context, t *domain.NotificationTemplate) error {
+	query := `
+		INSERT INTO notification_templates (name, locale, version, subject, body, created_by, created_at)
+		SELECT $1, $2, COALESCE(MAX(version), 0) + 1, $3, $4, $5, $6
+		FROM notification_templates WHERE name = $1 AND locale = $2
+		RETURNING version`
+
+	err := r.db.QueryRow(ctx, query, t.Name, t.Locale, t.Subject, t.Body, t.CreatedBy, t.CreatedAt).Scan(&t.Version)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return repository.ErrTemplateVersionConflict
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *NotificationTemplateRepository) FindLatest(ctx context.Context) ([]domain.NotificationTemplate, error) {
+	query := `
+		SELECT DISTINCT ON (name, locale) name, locale, version, subject, body, created_by, created_at
+		FROM notification_templates
+		ORDER BY name, locale, version DESC`
+	return r.find(ctx, query)
+}
+
+func (r *NotificationTemplateRepository) FindVersions(ctx context.Context, name, locale string) ([]domain.NotificationTemplate, error) {
+	query := `
+		SELECT name, locale, version, subject, body, created_by, created_at
+		FROM notification_templates
+		WHERE name = $1 AND locale = $2
+		ORDER BY version DESC`
+	return r.find(ctx, query, name, locale)
+}
+
+func (r *NotificationTemplateRepository) find(ctx context.Context, query string, args ...any) ([]domain.NotificationTemplate, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []domain.NotificationTemplate
+	for rows.Next() {
+		var t domain.NotificationTemplate
+		if err := rows.Scan(&t.Name, &t.Locale, &t.Version, &t.Subject, &t.Body, &t.CreatedBy, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
@@ -2,7 +2,12 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -19,42 +24,124 @@ func NewOrderRepository(db *pgxpool.Pool) *OrderRepository {
 	return &OrderRepository{db: db}
 }
 
-// CreateTx creates an order and all its items within a transaction.
-// First creates the order record, then all order items.
+// CreateTx creates an order with all its items and options within a transaction.
+// First creates the order record referencing its delivery slot, then all order items and options.
 func (r *OrderRepository) CreateTx(ctx context.Context, tx pgx.Tx, order *domain.Order) error {
 	// Create order record
-	orderQuery := `INSERT INTO orders (id, user_id, created_at, total_amount) VALUES ($1, $2, $3, $4)`
-	_, err := tx.Exec(ctx, orderQuery, order.ID, order.UserID, order.CreatedAt, order.TotalAmount)
+	orderQuery := `INSERT INTO orders (id, number, user_id, status, created_at, total_amount, currency, locale, tax_region, region, delivery_slot_id, sandbox)
+				   VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''), NULLIF($10, ''), $11, $12)`
+	var slotID *uuid.UUID
+	if order.Delivery != nil {
+		slotID = &order.Delivery.SlotID
+	}
+	_, err := tx.Exec(ctx, orderQuery, order.ID, order.Number, order.UserID, order.Status, order.CreatedAt, order.TotalAmount,
+		order.Currency, order.Locale, order.TaxRegion, order.Region, slotID, order.Sandbox)
 	if err != nil {
 		return err
 	}
 
 	// Create order items
-	itemQuery := `INSERT INTO order_items (id, order_id, product_id, quantity, price_at_purchase)
-				  VALUES ($1, $2, $3, $4, $5)`
+	// Bundle lines precede their component lines, which reference them
+	itemQuery := `INSERT INTO order_items (id, order_id, product_id, quantity, price_at_purchase, bundle, bundle_item_id)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7)`
 	for _, item := range order.Items {
-		_, err := tx.Exec(ctx, itemQuery, item.ID, order.ID, item.ProductID, item.Quantity, item.PriceAtPurchase)
+		_, err := tx.Exec(ctx, itemQuery, item.ID, order.ID, item.ProductID, item.Quantity, item.PriceAtPurchase, item.Bundle, item.BundleItemID)
 		if err != nil {
 			return err
 		}
 	}
+
+	optionQuery := `INSERT INTO order_options (order_id, option, position, fee, message) VALUES ($1, $2, $3, $4, $5)`
+	for i, option := range order.Options {
+		if _, err := tx.Exec(ctx, optionQuery, order.ID, option.Option, i+1, option.Fee, option.Message); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// findOptions loads the options of the orders, indexed by their IDs.
+func (r *OrderRepository) findOptions(ctx context.Context, orders []domain.Order, index map[uuid.UUID]int) error {
+	ids := make([]uuid.UUID, len(orders))
+	for i, order := range orders {
+		ids[i] = order.ID
+	}
+	query := `
+        SELECT order_id, option, fee, message
+        FROM order_options
+        WHERE order_id = ANY($1)
+        ORDER BY order_id, position
+    `
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			orderID uuid.UUID
+			option  domain.OrderOption
+		)
+		if err := rows.Scan(&orderID, &option.Option, &option.Fee, &option.Message); err != nil {
+			return err
+		}
+		order := &orders[index[orderID]]
+		order.Options = append(order.Options, option)
+	}
+	return rows.Err()
+}
+
+// findDeliveries loads the delivery windows of the orders, indexed by their IDs.
+func (r *OrderRepository) findDeliveries(ctx context.Context, orders []domain.Order, index map[uuid.UUID]int) error {
+	ids := make([]uuid.UUID, len(orders))
+	for i, order := range orders {
+		ids[i] = order.ID
+	}
+	query := `
+        SELECT o.id, s.id, s.region, s.starts_at, s.ends_at
+        FROM orders o
+        JOIN delivery_slots s ON s.id = o.delivery_slot_id
+        WHERE o.id = ANY($1)
+    `
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			orderID  uuid.UUID
+			delivery domain.OrderDelivery
+		)
+		if err := rows.Scan(&orderID, &delivery.SlotID, &delivery.Region, &delivery.StartsAt, &delivery.EndsAt); err != nil {
+			return err
+		}
+		orders[index[orderID]].Delivery = &delivery
+	}
+	return rows.Err()
+}
+
 func (r *OrderRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
 	query := `
-        SELECT id, user_id, created_at, total_amount
+        SELECT id, number, user_id, status, COALESCE(payment_id, ''), COALESCE(pending_payment, ''), created_at, total_amount,
+               COALESCE(currency, ''), COALESCE(locale, ''), COALESCE(tax_region, ''), COALESCE(region, ''), sandbox
         FROM orders
         WHERE id = $1
     `
 	order := &domain.Order{}
-	err := r.db.QueryRow(ctx, query, id).Scan(&order.ID, &order.UserID, &order.CreatedAt, &order.TotalAmount)
+	err := r.db.QueryRow(ctx, query, id).Scan(&order.ID, &order.Number, &order.UserID, &order.Status, &order.PaymentID, &order.PendingPayment, &order.CreatedAt, &order.TotalAmount,
+		&order.Currency, &order.Locale, &order.TaxRegion, &order.Region, &order.Sandbox)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrOrderNotFound
+		}
 		return nil, err
 	}
 
 	itemsQuery := `
-        SELECT id, product_id, quantity, price_at_purchase
+        SELECT id, product_id, quantity, price_at_purchase, bundle, bundle_item_id
         FROM order_items
         WHERE order_id = $1
     `
@@ -66,12 +153,226 @@ func (r *OrderRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.O
 
 	for rows.Next() {
 		item := domain.OrderItem{}
-		err := rows.Scan(&item.ID, &item.ProductID, &item.Quantity, &item.PriceAtPurchase)
+		err := rows.Scan(&item.ID, &item.ProductID, &item.Quantity, &item.PriceAtPurchase, &item.Bundle, &item.BundleItemID)
 		if err != nil {
 			return nil, err
 		}
 		order.Items = append(order.Items, item)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	orders := []domain.Order{*order}
+	index := map[uuid.UUID]int{order.ID: 0}
+	if err := r.findOptions(ctx, orders, index); err != nil {
+		return nil, err
+	}
+	if err := r.findDeliveries(ctx, orders, index); err != nil {
+		return nil, err
+	}
+	return &orders[0], nil
+}
+
+func (r *OrderRepository) FindByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]domain.Order, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM orders WHERE user_id = $1`, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+        SELECT id, number, user_id, status, COALESCE(payment_id, ''), COALESCE(pending_payment, ''), created_at, total_amount,
+               COALESCE(currency, ''), COALESCE(locale, ''), COALESCE(tax_region, ''), COALESCE(region, ''), sandbox
+        FROM orders
+        WHERE user_id = $1
+        ORDER BY created_at DESC, id
+        OFFSET $2 LIMIT $3
+    `
+	rows, err := r.db.Query(ctx, query, userID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var orders []domain.Order
+	index := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var order domain.Order
+		if err := rows.Scan(&order.ID, &order.Number, &order.UserID, &order.Status, &order.PaymentID, &order.PendingPayment, &order.CreatedAt, &order.TotalAmount,
+			&order.Currency, &order.Locale, &order.TaxRegion, &order.Region, &order.Sandbox); err != nil {
+			return nil, 0, err
+		}
+		index[order.ID] = len(orders)
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(orders) == 0 {
+		return nil, total, nil
+	}
+
+	ids := make([]uuid.UUID, len(orders))
+	for i, order := range orders {
+		ids[i] = order.ID
+	}
+	itemsQuery := `
+        SELECT order_id, id, product_id, quantity, price_at_purchase, bundle, bundle_item_id
+        FROM order_items
+        WHERE order_id = ANY($1)
+    `
+	itemRows, err := r.db.Query(ctx, itemsQuery, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer itemRows.Close()
 
-	return order, nil
+	for itemRows.Next() {
+		var (
+			orderID uuid.UUID
+			item    domain.OrderItem
+		)
+		err := itemRows.Scan(&orderID, &item.ID, &item.ProductID, &item.Quantity, &item.PriceAtPurchase, &item.Bundle, &item.BundleItemID)
+		if err != nil {
+			return nil, 0, err
+		}
+		order := &orders[index[orderID]]
+		order.Items = append(order.Items, item)
+	}
+	if err := itemRows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.findOptions(ctx, orders, index); err != nil {
+		return nil, 0, err
+	}
+	if err := r.findDeliveries(ctx, orders, index); err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+func (r *OrderRepository) UpdateStatusTx(ctx context.Context, tx pgx.Tx, order *domain.Order) error {
+	query := `UPDATE orders SET status = $2, payment_id = NULLIF($3, ''), pending_payment = NULLIF($4, '') WHERE id = $1`
+	tag, err := tx.Exec(ctx, query, order.ID, order.Status, order.PaymentID, order.PendingPayment)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrOrderNotFound
+	}
+	return nil
+}
+
+func (r *OrderRepository) FindTotalMismatches(ctx context.Context, limit int) ([]domain.OrderTotalMismatch, error) {
+	query := `
+        SELECT id, total_amount, computed_total
+        FROM (
+            SELECT o.id, o.total_amount, o.created_at,
+                   (SELECT COALESCE(SUM(price_at_purchase * quantity), 0) FROM order_items WHERE order_id = o.id)
+                   + (SELECT COALESCE(SUM(fee), 0) FROM order_options WHERE order_id = o.id) AS computed_total
+            FROM orders o
+        ) totals
+        WHERE total_amount <> computed_total
+        ORDER BY created_at
+        LIMIT $1
+    `
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mismatches []domain.OrderTotalMismatch
+	for rows.Next() {
+		var m domain.OrderTotalMismatch
+		if err := rows.Scan(&m.OrderID, &m.StoredTotal, &m.ComputedTotal); err != nil {
+			return nil, err
+		}
+		mismatches = append(mismatches, m)
+	}
+	return mismatches, rows.Err()
+}
+
+func (r *OrderRepository) FindAwaitingPayment(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+        SELECT o.id
+        FROM orders o
+        JOIN LATERAL (
+            SELECT MAX(e.created_at) AS attempted_at
+            FROM order_events e
+            WHERE e.order_id = o.id AND e.type IN ('payment_pending', 'payment_failed')
+        ) last ON last.attempted_at < $1
+        WHERE o.status = 'created'
+        ORDER BY last.attempted_at
+        LIMIT $2
+    `
+	rows, err := r.db.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *OrderRepository) RepairTotal(ctx context.Context, id uuid.UUID) error {
+	query := `
+        UPDATE orders
+        SET total_amount = (SELECT COALESCE(SUM(price_at_purchase * quantity), 0) FROM order_items WHERE order_id = $1)
+            + (SELECT COALESCE(SUM(fee), 0) FROM order_options WHERE order_id = $1)
+        WHERE id = $1
+    `
+	tag, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrOrderNotFound
+	}
+	return nil
+}
+
+// FindDuplicateTx compares the ordered lines, bundle lines but not their components, as "product:quantity"
+// sorted bytewise, so lines are matched regardless of the order they were added in.
+func (r *OrderRepository) FindDuplicateTx(ctx context.Context, tx pgx.Tx, order *domain.Order, since time.Time) (*domain.Order, error) {
+	var lines []string
+	for _, item := range order.Items {
+		if item.BundleItemID == nil {
+			lines = append(lines, item.ProductID.String()+":"+strconv.Itoa(item.Quantity))
+		}
+	}
+	slices.Sort(lines)
+
+	query := `
+        SELECT o.id, o.number, o.user_id, o.status, o.created_at, o.total_amount
+        FROM orders o
+        WHERE o.user_id = $1 AND o.status <> 'cancelled' AND o.created_at >= $2 AND o.total_amount = $3
+          AND ARRAY(
+              SELECT i.product_id::text || ':' || i.quantity
+              FROM order_items i
+              WHERE i.order_id = o.id AND i.bundle_item_id IS NULL
+              ORDER BY (i.product_id::text || ':' || i.quantity) COLLATE "C"
+          ) = $4::text[]
+        ORDER BY o.created_at DESC
+        LIMIT 1
+    `
+	var duplicate domain.Order
+	err := tx.QueryRow(ctx, query, order.UserID, since, order.TotalAmount, lines).Scan(
+		&duplicate.ID, &duplicate.Number, &duplicate.UserID, &duplicate.Status, &duplicate.CreatedAt, &duplicate.TotalAmount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return &duplicate, nil
 }
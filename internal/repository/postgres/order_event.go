@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OrderEventRepository implements repository.OrderEventRepository interface for PostgreSQL.
+type OrderEventRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewOrderEventRepository creates a new order event repository for PostgreSQL.
+func NewOrderEventRepository(db *pgxpool.Pool) *OrderEventRepository {
+	return &OrderEventRepository{db: db}
+}
+
+// orderEventPayload contains event data stored in the payload column.
+type orderEventPayload struct {
+	UserID    *uuid.UUID            `json:",omitempty"`
+	Number    string                `json:",omitempty"`
+	Settings  *domain.OrderSettings `json:",omitempty"`
+	Delivery  *domain.OrderDelivery `json:",omitempty"`
+	Item      *domain.OrderItem     `json:",omitempty"`
+	Option    *domain.OrderOption   `json:",omitempty"`
+	PaymentID string                `json:",omitempty"`
+	Region    string                `json:",omitempty"`
+}
+
+func (r *OrderEventRepository) AppendTx(ctx context.Context, tx pgx.Tx, events []domain.OrderEvent) error {
+	query := `INSERT INTO order_events (id, order_id, sequence, type, payload, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6)`
+	for _, e := range events {
+		payload := orderEventPayload{Number: e.Number, Settings: e.Settings, Delivery: e.Delivery, Item: e.Item, Option: e.Option, PaymentID: e.PaymentID, Region: e.Region}
+		if e.UserID != uuid.Nil {
+			payload.UserID = &e.UserID
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, query, e.ID, e.OrderID, e.Sequence, e.Type, data, e.CreatedAt)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+				return repository.ErrOrderEventConflict
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *OrderEventRepository) FindByOrderID(ctx context.Context, orderID uuid.UUID, until time.Time) ([]domain.OrderEvent, error) {
+	query := `
+        SELECT id, order_id, sequence, type, payload, created_at
+        FROM order_events
+        WHERE order_id = $1 AND ($2::timestamptz IS NULL OR created_at <= $2)
+        ORDER BY sequence
+    `
+	var untilArg *time.Time
+	if !until.IsZero() {
+		untilArg = &until
+	}
+
+	rows, err := r.db.Query(ctx, query, orderID, untilArg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []domain.OrderEvent{}
+	for rows.Next() {
+		var (
+			e       domain.OrderEvent
+			data    []byte
+			payload orderEventPayload
+		)
+		if err := rows.Scan(&e.ID, &e.OrderID, &e.Sequence, &e.Type, &data, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+		if payload.UserID != nil {
+			e.UserID = *payload.UserID
+		}
+		e.Number, e.Settings, e.Delivery, e.Item, e.Option = payload.Number, payload.Settings, payload.Delivery, payload.Item, payload.Option
+		e.PaymentID, e.Region = payload.PaymentID, payload.Region
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"product-api/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OrderNumberRepository implements repository.OrderNumberRepository interface for PostgreSQL.
+type OrderNumberRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewOrderNumberRepository creates a new order number repository for PostgreSQL.
+func NewOrderNumberRepository(db *pgxpool.Pool) *OrderNumberRepository {
+	return &OrderNumberRepository{db: db}
+}
+
+// NextTx increments the counter of the year. The counter row stays locked until the transaction ends,
+// which keeps the sequence gapless.
+func (r *OrderNumberRepository) NextTx(ctx context.Context, tx pgx.Tx, year int) (string, error) {
+	query := `
+        WITH counter AS (
+            INSERT INTO order_number_counters (year, last_value) VALUES ($1, 1)
+            ON CONFLICT (year) DO UPDATE SET last_value = order_number_counters.last_value + 1
+            RETURNING last_value
+        )
+        SELECT counter.last_value, s.prefix, s.padding
+        FROM counter, order_number_settings s
+    `
+	var (
+		sequence int64
+		settings domain.OrderNumberSettings
+	)
+	if err := tx.QueryRow(ctx, query, year).Scan(&sequence, &settings.Prefix, &settings.Padding); err != nil {
+		return "", err
+	}
+	return settings.Number(year, sequence), nil
+}
+
+func (r *OrderNumberRepository) FindSettings(ctx context.Context) (*domain.OrderNumberSettings, error) {
+	settings := &domain.OrderNumberSettings{}
+	err := r.db.QueryRow(ctx, `SELECT prefix, padding FROM order_number_settings`).Scan(&settings.Prefix, &settings.Padding)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *OrderNumberRepository) SaveSettings(ctx context.Context, settings *domain.OrderNumberSettings) error {
+	query := `
+        INSERT INTO order_number_settings (prefix, padding) VALUES ($1, $2)
+        ON CONFLICT (id) DO UPDATE SET prefix = EXCLUDED.prefix, padding = EXCLUDED.padding
+    `
+	_, err := r.db.Exec(ctx, query, settings.Prefix, settings.Padding)
+	return err
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// paymentsQuery selects payments of an order in the order scanPayments expects.
+const paymentsQuery = `
+    SELECT id, order_id, kind, method, COALESCE(reference, ''), amount, refund_of, COALESCE(provider, ''), metadata, created_at
+    FROM order_payments
+    WHERE order_id = $1
+    ORDER BY created_at, id
+`
+
+// PaymentRepository implements repository.PaymentRepository interface for PostgreSQL.
+type PaymentRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPaymentRepository creates a new payment repository for PostgreSQL.
+func NewPaymentRepository(db *pgxpool.Pool) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+func (r *PaymentRepository) CreateTx(ctx context.Context, tx pgx.Tx, payment *domain.Payment) error {
+	metadata := []byte("{}")
+	if payment.Metadata != nil {
+		var err error
+		if metadata, err = json.Marshal(payment.Metadata); err != nil {
+			return err
+		}
+	}
+	query := `INSERT INTO order_payments (id, order_id, kind, method, reference, amount, refund_of, provider, metadata, created_at)
+			  VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, NULLIF($8, ''), $9, $10)`
+	_, err := tx.Exec(ctx, query, payment.ID, payment.OrderID, payment.Kind, payment.Method, payment.Reference,
+		payment.Amount, payment.RefundOf, payment.Provider, metadata, payment.CreatedAt)
+	return err
+}
+
+// FindByOrderIDTx locks the order row, so concurrent payments and refunds of the order are serialized.
+func (r *PaymentRepository) FindByOrderIDTx(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) ([]domain.Payment, error) {
+	var id uuid.UUID
+	if err := tx.QueryRow(ctx, `SELECT id FROM orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, paymentsQuery, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return scanPayments(rows)
+}
+
+func (r *PaymentRepository) FindByOrderID(ctx context.Context, orderID uuid.UUID) ([]domain.Payment, error) {
+	rows, err := r.db.Query(ctx, paymentsQuery, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return scanPayments(rows)
+}
+
+func (r *PaymentRepository) FindChargeByReference(ctx context.Context, provider, reference string) (*domain.Payment, error) {
+	query := `
+        SELECT id, order_id, kind, method, COALESCE(reference, ''), amount, refund_of, COALESCE(provider, ''), metadata, created_at
+        FROM order_payments
+        WHERE kind = 'charge' AND provider = $1 AND reference = $2
+    `
+	rows, err := r.db.Query(ctx, query, provider, reference)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := scanPayments(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(payments) == 0 {
+		return nil, repository.ErrPaymentNotFound
+	}
+	return &payments[0], nil
+}
+
+// scanPayments scans and closes rows selected with paymentsQuery.
+func scanPayments(rows pgx.Rows) ([]domain.Payment, error) {
+	defer rows.Close()
+
+	var payments []domain.Payment
+	for rows.Next() {
+		var p domain.Payment
+		var metadata []byte
+		err := rows.Scan(&p.ID, &p.OrderID, &p.Kind, &p.Method, &p.Reference, &p.Amount, &p.RefundOf, &p.Provider,
+			&metadata, &p.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(metadata, &p.Metadata); err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
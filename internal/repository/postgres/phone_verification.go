@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PhoneVerificationRepository implements repository.PhoneVerificationRepository interface for PostgreSQL.
+type PhoneVerificationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPhoneVerificationRepository creates a new phone verification repository for PostgreSQL.
+func NewPhoneVerificationRepository(db *pgxpool.Pool) *PhoneVerificationRepository {
+	return &PhoneVerificationRepository{db: db}
+}
+
+func (r *PhoneVerificationRepository) Upsert(ctx context.Context, v *domain.PhoneVerification) error {
+	query := `
+		INSERT INTO phone_verifications (user_id, phone, code_hash, attempts, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET phone = EXCLUDED.phone, code_hash = EXCLUDED.code_hash, attempts = EXCLUDED.attempts,
+			expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at
+	`
+	_, err := r.db.Exec(ctx, query, v.UserID, v.Phone, v.CodeHash, v.Attempts, v.ExpiresAt, v.CreatedAt)
+	return err
+}
+
+func (r *PhoneVerificationRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*domain.PhoneVerification, error) {
+	query := `
+		SELECT user_id, phone, code_hash, attempts, expires_at, created_at
+		FROM phone_verifications
+		WHERE user_id = $1
+	`
+	var v domain.PhoneVerification
+	err := r.db.QueryRow(ctx, query, userID).Scan(&v.UserID, &v.Phone, &v.CodeHash, &v.Attempts, &v.ExpiresAt, &v.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrPhoneVerificationNotFound
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *PhoneVerificationRepository) IncrementAttempts(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE phone_verifications SET attempts = attempts + 1 WHERE user_id = $1`
+
+	_, err := r.db.Exec(ctx, query, userID)
+	return err
+}
+
+func (r *PhoneVerificationRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	query := `DELETE FROM phone_verifications WHERE user_id = $1`
+
+	_, err := r.db.Exec(ctx, query, userID)
+	return err
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"product-api/internal/telemetry"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AcquireTimeoutTracer implements pgxpool.AcquireTracer and bounds the wait for a connection of the pool.
+// Pool methods failing to acquire one in time return repository.ErrPoolExhausted instead of waiting for the
+// caller's deadline; exhaustions are logged, counted and recorded in the context (see repository.PoolExhausted).
+// Its query tracing methods do nothing, so it can be combined with other tracers by multitracer.
+type AcquireTimeoutTracer struct {
+	pool    string // Pool name of logs and metrics, e.g. "primary"
+	timeout time.Duration
+	logger  logger.Logger
+}
+
+// NewAcquireTimeoutTracer creates a tracer failing acquisitions of the named pool after timeout.
+func NewAcquireTimeoutTracer(pool string, timeout time.Duration, l logger.Logger) *AcquireTimeoutTracer {
+	return &AcquireTimeoutTracer{pool: pool, timeout: timeout, logger: l}
+}
+
+// acquireContextKey is the context key of the acquireContext of an acquisition.
+type acquireContextKey struct{}
+
+// acquireContext bounds an acquisition. Once its own timeout expires, Err returns repository.ErrPoolExhausted,
+// which the pool returns as is.
+type acquireContext struct {
+	context.Context
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+func (c *acquireContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && c.parent.Err() == nil {
+		return repository.ErrPoolExhausted
+	}
+	return err
+}
+
+func (c *acquireContext) Value(key any) any {
+	if key == (acquireContextKey{}) {
+		return c
+	}
+	return c.Context.Value(key)
+}
+
+// TraceAcquireStart starts the acquire timeout.
+func (t *AcquireTimeoutTracer) TraceAcquireStart(ctx context.Context, _ *pgxpool.Pool, _ pgxpool.TraceAcquireStartData) context.Context {
+	timeoutCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	return &acquireContext{Context: timeoutCtx, parent: ctx, cancel: cancel}
+}
+
+// TraceAcquireEnd stops the acquire timeout and reports exhaustion.
+func (t *AcquireTimeoutTracer) TraceAcquireEnd(ctx context.Context, pool *pgxpool.Pool, data pgxpool.TraceAcquireEndData) {
+	if c, ok := ctx.Value(acquireContextKey{}).(*acquireContext); ok {
+		c.cancel()
+	}
+	if !errors.Is(data.Err, repository.ErrPoolExhausted) {
+		return
+	}
+
+	repository.RecordPoolExhaustion(ctx)
+	telemetry.RecordPoolExhaustion(ctx, t.pool)
+	stat := pool.Stat()
+	t.logger.WithTrace(ctx).Warn("database connection pool exhausted", "pool", t.pool,
+		"acquired_conns", stat.AcquiredConns(), "max_conns", stat.MaxConns(), "timeout_ms", t.timeout.Milliseconds())
+}
+
+func (t *AcquireTimeoutTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return ctx
+}
+
+func (t *AcquireTimeoutTracer) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PreferenceRepository implements repository.PreferenceRepository interface for PostgreSQL.
+type PreferenceRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPreferenceRepository creates a new notification preference repository for PostgreSQL.
+func NewPreferenceRepository(db *pgxpool.Pool) *PreferenceRepository {
+	return &PreferenceRepository{db: db}
+}
+
+func (r *PreferenceRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (domain.NotificationPreferences, error) {
+	query := `SELECT category, channel, enabled FROM notification_preferences WHERE user_id = $1`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prefs := domain.NotificationPreferences{}
+	for rows.Next() {
+		var (
+			category, channel string
+			enabled           bool
+		)
+		if err := rows.Scan(&category, &channel, &enabled); err != nil {
+			return nil, err
+		}
+		if prefs[category] == nil {
+			prefs[category] = map[string]bool{}
+		}
+		prefs[category][channel] = enabled
+	}
+
+	return prefs, rows.Err()
+}
+
+// Upsert stores all given preferences in a single batch.
+func (r *PreferenceRepository) Upsert(ctx context.Context, userID uuid.UUID, prefs domain.NotificationPreferences) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, category, channel, enabled, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (user_id, category, channel) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = NOW()
+	`
+	batch := &pgx.Batch{}
+	for category, channels := range prefs {
+		for channel, enabled := range channels {
+			batch.Queue(query, userID, category, channel, enabled)
+		}
+	}
+	return r.db.SendBatch(ctx, batch).Close()
+}
@@ -2,15 +2,26 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"product-api/internal/domain"
 	"product-api/internal/repository"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// productColumns lists product columns in the order expected by scanProduct.
+// Bundle components are selected as parallel arrays of component IDs and quantities.
+const productColumns = `id, description, tags, quantity, price, age_restriction, status, COALESCE(barcode, ''),
+	COALESCE(category, ''), attributes,
+	ARRAY(SELECT component_id FROM product_bundle_components WHERE bundle_id = products.id ORDER BY component_id),
+	ARRAY(SELECT quantity FROM product_bundle_components WHERE bundle_id = products.id ORDER BY component_id)`
+
 // ProductRepository implements repository.ProductRepository interface for PostgreSQL.
 type ProductRepository struct {
 	db *pgxpool.Pool
@@ -21,18 +32,141 @@ func NewProductRepository(db *pgxpool.Pool) *ProductRepository {
 	return &ProductRepository{db: db}
 }
 
+// scanProduct scans a row selected with productColumns into a product.
+func scanProduct(row pgx.Row, p *domain.Product) error {
+	var (
+		attributes   []byte
+		componentIDs []uuid.UUID
+		quantities   []int
+	)
+	err := row.Scan(&p.ID, &p.Description, &p.Tags, &p.Quantity, &p.Price, &p.AgeRestriction, &p.Status, &p.Barcode,
+		&p.Category, &attributes, &componentIDs, &quantities)
+	if err != nil {
+		return err
+	}
+	p.Attributes = nil
+	if err := json.Unmarshal(attributes, &p.Attributes); err != nil {
+		return err
+	}
+	p.Components = nil
+	for i, id := range componentIDs {
+		p.Components = append(p.Components, domain.BundleComponent{ProductID: id, Quantity: quantities[i]})
+	}
+	return nil
+}
+
+// Create inserts the product with its bundle components and records its initial quantity as a stock receipt in the ledger.
 func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) error {
-	query := `INSERT INTO products (id, description, tags, quantity, price)
-			  VALUES ($1, $2, $3, $4, $5)`
-	_, err := r.db.Exec(ctx, query, product.ID, product.Description, product.Tags, product.Quantity, product.Price)
-	return err
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		attributes, err := marshalAttributes(product.Attributes)
+		if err != nil {
+			return err
+		}
+		query := `INSERT INTO products (id, description, tags, quantity, price, age_restriction, status, barcode, category, attributes)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10)`
+		_, err = tx.Exec(ctx, query, product.ID, product.Description, product.Tags, product.Quantity, product.Price,
+			product.AgeRestriction, product.Status, product.Barcode, product.Category, attributes)
+		if err != nil {
+			return productError(err)
+		}
+
+		componentQuery := `INSERT INTO product_bundle_components (bundle_id, component_id, quantity) VALUES ($1, $2, $3)`
+		for _, c := range product.Components {
+			if _, err := tx.Exec(ctx, componentQuery, product.ID, c.ProductID, c.Quantity); err != nil {
+				return err
+			}
+		}
+		if product.Quantity == 0 {
+			return nil
+		}
+
+		return insertStockMovement(ctx, tx, &domain.StockMovement{
+			ID:        uuid.New(),
+			ProductID: product.ID,
+			Delta:     product.Quantity,
+			Reason:    domain.StockReasonReceipt,
+			Note:      "initial stock",
+			CreatedAt: time.Now(),
+		})
+	})
+}
+
+// CreateMany copies the products and their initial stock receipts in one transaction.
+// Bundle components are not inserted, imported products are not bundles.
+func (r *ProductRepository) CreateMany(ctx context.Context, products []domain.Product) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		rows := make([][]any, len(products))
+		var movements [][]any
+		now := time.Now()
+		for i, p := range products {
+			attributes, err := marshalAttributes(p.Attributes)
+			if err != nil {
+				return err
+			}
+			rows[i] = []any{p.ID, p.Description, p.Tags, p.Quantity, p.Price, p.AgeRestriction, p.Status,
+				nullIfEmpty(p.Barcode), nullIfEmpty(p.Category), attributes}
+			if p.Quantity != 0 {
+				movements = append(movements, []any{uuid.New(), p.ID, p.Quantity, domain.StockReasonReceipt, "initial stock", now})
+			}
+		}
+
+		columns := []string{"id", "description", "tags", "quantity", "price", "age_restriction", "status", "barcode", "category", "attributes"}
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{"products"}, columns, pgx.CopyFromRows(rows)); err != nil {
+			return productError(err)
+		}
+		movementColumns := []string{"id", "product_id", "delta", "reason", "note", "created_at"}
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{"stock_movements"}, movementColumns, pgx.CopyFromRows(movements)); err != nil {
+			return fmt.Errorf("copy stock movements: %w", err)
+		}
+		return nil
+	})
+}
+
+// nullIfEmpty returns nil for empty strings, to copy them as NULL.
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func (r *ProductRepository) FindTakenBarcodes(ctx context.Context, barcodes []string) ([]string, error) {
+	rows, err := r.db.Query(ctx, "SELECT barcode FROM products WHERE barcode = ANY($1)", barcodes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taken []string
+	for rows.Next() {
+		var barcode string
+		if err := rows.Scan(&barcode); err != nil {
+			return nil, err
+		}
+		taken = append(taken, barcode)
+	}
+	return taken, rows.Err()
 }
 
 func (r *ProductRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
-	query := `SELECT id, description, tags, quantity, price FROM products WHERE id = $1`
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = $1`
+
+	p := &domain.Product{}
+	err := scanProduct(r.db.QueryRow(ctx, query, id), p)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrProductNotFound
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *ProductRepository) FindByBarcode(ctx context.Context, barcode string) (*domain.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE barcode = $1`
 
 	p := &domain.Product{}
-	err := r.db.QueryRow(ctx, query, id).Scan(&p.ID, &p.Description, &p.Tags, &p.Quantity, &p.Price)
+	err := scanProduct(r.db.QueryRow(ctx, query, barcode), p)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, repository.ErrProductNotFound
@@ -43,7 +177,7 @@ func (r *ProductRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain
 }
 
 func (r *ProductRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Product, error) {
-	rows, err := r.db.Query(ctx, "SELECT id, description, tags, quantity, price FROM products WHERE id = ANY($1)", ids)
+	rows, err := r.db.Query(ctx, "SELECT "+productColumns+" FROM products WHERE id = ANY($1)", ids)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, repository.ErrProductNotFound
@@ -55,7 +189,7 @@ func (r *ProductRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]d
 	var products []domain.Product
 	for rows.Next() {
 		var p domain.Product
-		if err := rows.Scan(&p.ID, &p.Description, &p.Tags, &p.Quantity, &p.Price); err != nil {
+		if err := scanProduct(rows, &p); err != nil {
 			return nil, err
 		}
 		products = append(products, p)
@@ -72,20 +206,102 @@ func (r *ProductRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]d
 	return products, nil
 }
 
+// productInStock is the condition of products with stock. Bundles have no stock of their own,
+// they are in stock if every component has stock for one bundle.
+const productInStock = `CASE WHEN EXISTS (SELECT 1 FROM product_bundle_components WHERE bundle_id = products.id)
+	THEN NOT EXISTS (
+		SELECT 1 FROM product_bundle_components c JOIN products component ON component.id = c.component_id
+		WHERE c.bundle_id = products.id AND component.quantity < c.quantity
+	)
+	ELSE quantity > 0 END`
+
+func (r *ProductRepository) List(ctx context.Context, filter repository.ProductFilter, offset, limit int) ([]domain.Product, int, error) {
+	where := `WHERE ($1 = '' OR status = $1)
+		AND (COALESCE(cardinality($2::text[]), 0) = 0 OR tags @> $2)
+		AND ($3::numeric IS NULL OR price >= $3)
+		AND ($4::numeric IS NULL OR price <= $4)
+		AND (NOT $5 OR ` + productInStock + `)`
+	args := []any{filter.Status, filter.Tags, filter.MinPrice, filter.MaxPrice, filter.InStock}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM products `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + productColumns + ` FROM products ` + where + ` ORDER BY created_at DESC, id OFFSET $6 LIMIT $7`
+	rows, err := r.db.Query(ctx, query, append(args, offset, limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var products []domain.Product
+	for rows.Next() {
+		var p domain.Product
+		if err := scanProduct(rows, &p); err != nil {
+			return nil, 0, err
+		}
+		products = append(products, p)
+	}
+
+	return products, total, rows.Err()
+}
+
+// updateProductQuery updates product details. Quantity is not updated, it only changes through the stock ledger.
+const updateProductQuery = `UPDATE products SET description = $2, tags = $3, price = $4, age_restriction = $5, status = $6,
+	barcode = NULLIF($7, ''), category = NULLIF($8, ''), attributes = $9 WHERE id = $1`
+
+// updateProductArgs returns arguments of updateProductQuery for the product.
+func updateProductArgs(product *domain.Product) ([]any, error) {
+	attributes, err := marshalAttributes(product.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	return []any{product.ID, product.Description, product.Tags, product.Price, product.AgeRestriction, product.Status,
+		product.Barcode, product.Category, attributes}, nil
+}
+
+// marshalAttributes encodes product attributes for the attributes column, which is never null.
+func marshalAttributes(attributes map[string]any) ([]byte, error) {
+	if attributes == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(attributes)
+}
+
+// Update updates product details. Quantity is not updated, it only changes through the stock ledger.
 func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
-	query := `UPDATE products SET description = $2, tags = $3, quantity = $4, price = $5 WHERE id = $1`
+	args, err := updateProductArgs(product)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(ctx, updateProductQuery, args...)
+	return productError(err)
+}
 
-	_, err := r.db.Exec(ctx, query, product.ID, product.Description, product.Tags, product.Quantity, product.Price)
-	return err
+// UpdateTx updates product details within a transaction.
+func (r *ProductRepository) UpdateTx(ctx context.Context, tx pgx.Tx, product *domain.Product) error {
+	args, err := updateProductArgs(product)
+	if err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, updateProductQuery, args...)
+	if err != nil {
+		return productError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrProductNotFound
+	}
+	return nil
 }
 
 // FindByIDTx finds a product by ID within a transaction with row lock (FOR UPDATE).
 // Used to prevent race conditions when updating product quantity.
 func (r *ProductRepository) FindByIDTx(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*domain.Product, error) {
-	query := `SELECT id, description, tags, quantity, price FROM products WHERE id = $1 FOR UPDATE`
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = $1 FOR UPDATE`
 
 	p := &domain.Product{}
-	err := tx.QueryRow(ctx, query, id).Scan(&p.ID, &p.Description, &p.Tags, &p.Quantity, &p.Price)
+	err := scanProduct(tx.QueryRow(ctx, query, id), p)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, repository.ErrProductNotFound
@@ -95,9 +311,204 @@ func (r *ProductRepository) FindByIDTx(ctx context.Context, tx pgx.Tx, id uuid.U
 	return p, nil
 }
 
-func (r *ProductRepository) UpdateTx(ctx context.Context, tx pgx.Tx, product *domain.Product) error {
-	query := `UPDATE products SET quantity = $2 WHERE id = $1`
+// FindByIDsTx finds products by IDs within a transaction with row locks (FOR UPDATE).
+// Rows are locked in ID order, so concurrent bulk operations cannot deadlock. Missing IDs are skipped.
+func (r *ProductRepository) FindByIDsTx(ctx context.Context, tx pgx.Tx, ids []uuid.UUID) ([]domain.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+
+	rows, err := tx.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []domain.Product
+	for rows.Next() {
+		var p domain.Product
+		if err := scanProduct(rows, &p); err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+	return products, rows.Err()
+}
+
+// exportBatchSize is the number of products read per query of an export.
+const exportBatchSize = 1000
+
+// Export reads products in batches within a read-only repeatable read transaction,
+// so all batches see the catalog as of the start of the export.
+func (r *ProductRepository) Export(ctx context.Context, after uuid.UUID, fn func(*domain.Product) error) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `SELECT ` + productColumns + ` FROM products WHERE id > $1 ORDER BY id LIMIT $2`
+	for {
+		rows, err := tx.Query(ctx, query, after, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		var products []domain.Product
+		for rows.Next() {
+			var p domain.Product
+			if err := scanProduct(rows, &p); err != nil {
+				rows.Close()
+				return err
+			}
+			products = append(products, p)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for i := range products {
+			if err := fn(&products[i]); err != nil {
+				return err
+			}
+		}
+		if len(products) < exportBatchSize {
+			return nil
+		}
+		after = products[len(products)-1].ID
+	}
+}
+
+// DeleteTx checks references before deleting, so the error does not depend on which foreign key fails first.
+func (r *ProductRepository) DeleteTx(ctx context.Context, tx pgx.Tx, id uuid.UUID) error {
+	var inUse bool
+	query := `SELECT EXISTS (SELECT 1 FROM order_items WHERE product_id = $1)
+			  OR EXISTS (SELECT 1 FROM product_bundle_components WHERE component_id = $1)`
+	if err := tx.QueryRow(ctx, query, id).Scan(&inUse); err != nil {
+		return err
+	}
+	if inUse {
+		return repository.ErrProductInUse
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM products WHERE id = $1`, id)
+	if err != nil {
+		return productError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrProductNotFound
+	}
+	return nil
+}
+
+func (r *ProductRepository) DeleteOrphanedDrafts(ctx context.Context, createdBefore time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+        DELETE FROM products
+        WHERE id IN (
+            SELECT id FROM products p
+            WHERE status = 'draft' AND created_at < $1
+              AND NOT EXISTS (SELECT 1 FROM order_items WHERE product_id = p.id)
+              AND NOT EXISTS (SELECT 1 FROM product_bundle_components WHERE component_id = p.id)
+            ORDER BY created_at
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id
+    `
+	rows, err := r.db.Query(ctx, query, createdBefore, limit)
+	if err != nil {
+		return nil, productError(err)
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, productError(err)
+	}
+	return ids, nil
+}
+
+// catalogIssues selects the issues of draft and active products found by the rules in $1, every rule if it is empty,
+// as product_id, rule and detail. Rule names match the domain.CatalogIssue constants.
+const catalogIssues = `
+	WITH issues (product_id, rule, detail) AS (
+		SELECT id, 'zero_price', '' FROM products WHERE price <= 0
+		UNION ALL
+		SELECT id, 'no_tags', '' FROM products WHERE COALESCE(cardinality(tags), 0) = 0
+		UNION ALL
+		SELECT product_id, 'negative_stock', 'ledger quantity ' || SUM(delta)
+		FROM stock_movements GROUP BY product_id HAVING SUM(delta) < 0
+		UNION ALL
+		SELECT b.bundle_id, 'inactive_component', string_agg(c.id || ' is ' || c.status, ', ' ORDER BY c.id)
+		FROM product_bundle_components b JOIN products c ON c.id = b.component_id
+		WHERE c.status <> 'active' GROUP BY b.bundle_id
+	)
+	SELECT i.product_id, p.description, p.status, i.rule, i.detail
+	FROM issues i JOIN products p ON p.id = i.product_id
+	WHERE p.status <> 'archived' AND (COALESCE(cardinality($1::text[]), 0) = 0 OR i.rule = ANY($1))`
+
+// FindIssues runs the count and page queries within a read-only repeatable read transaction,
+// so the counts match the issues of the page.
+func (r *ProductRepository) FindIssues(ctx context.Context, rules []string, offset, limit int) ([]domain.CatalogIssue, map[string]int, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
 
-	_, err := tx.Exec(ctx, query, product.ID, product.Quantity)
+	counts := map[string]int{}
+	rows, err := tx.Query(ctx, `SELECT rule, COUNT(*) FROM (`+catalogIssues+`) AS found GROUP BY rule`, rules)
+	if err != nil {
+		return nil, nil, err
+	}
+	for rows.Next() {
+		var rule string
+		var count int
+		if err := rows.Scan(&rule, &count); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		counts[rule] = count
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	rows, err = tx.Query(ctx, catalogIssues+` ORDER BY i.product_id, i.rule OFFSET $2 LIMIT $3`, rules, offset, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var issues []domain.CatalogIssue
+	for rows.Next() {
+		var issue domain.CatalogIssue
+		if err := rows.Scan(&issue.ProductID, &issue.Description, &issue.Status, &issue.Rule, &issue.Detail); err != nil {
+			return nil, nil, err
+		}
+		issues = append(issues, issue)
+	}
+	return issues, counts, rows.Err()
+}
+
+// productError maps unique violations of product columns, and foreign key violations of deleted products
+// still referenced, e.g. by an order placed concurrently, to repository errors.
+func productError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch {
+	case pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == "idx_products_barcode":
+		return repository.ErrBarcodeTaken
+	case pgErr.Code == foreignKeyViolationCode:
+		return repository.ErrProductInUse
+	}
 	return err
 }
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ProductRevisionRepository implements repository.ProductRevisionRepository interface for PostgreSQL.
+type ProductRevisionRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewProductRevisionRepository creates a new product revision repository for PostgreSQL.
+func NewProductRevisionRepository(db *pgxpool.Pool) *ProductRevisionRepository {
+	return &ProductRevisionRepository{db: db}
+}
+
+func (r *ProductRevisionRepository) AppendTx(ctx context.Context, tx pgx.Tx, revisions []domain.ProductRevision) error {
+	if len(revisions) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	query := `INSERT INTO product_revisions (id, product_id, actor, changed_at, changes)
+			  VALUES ($1, $2, $3, $4, $5)`
+	for _, rev := range revisions {
+		data, err := json.Marshal(rev.Changes)
+		if err != nil {
+			return err
+		}
+		batch.Queue(query, rev.ID, rev.ProductID, rev.Actor, rev.ChangedAt, data)
+	}
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+func (r *ProductRevisionRepository) FindByProductID(ctx context.Context, productID uuid.UUID, limit int) ([]domain.ProductRevision, error) {
+	query := `
+        SELECT id, product_id, actor, changed_at, changes
+        FROM product_revisions
+        WHERE product_id = $1
+        ORDER BY changed_at DESC, id
+        LIMIT $2
+    `
+	return r.query(ctx, query, productID, limit)
+}
+
+func (r *ProductRevisionRepository) FindByField(ctx context.Context, productID uuid.UUID, field string) ([]domain.ProductRevision, error) {
+	query := `
+        SELECT id, product_id, actor, changed_at, changes
+        FROM product_revisions
+        WHERE product_id = $1 AND changes @> jsonb_build_array(jsonb_build_object('Field', $2::text))
+        ORDER BY changed_at, id
+    `
+	return r.query(ctx, query, productID, field)
+}
+
+// query reads the revisions selected by the query.
+func (r *ProductRevisionRepository) query(ctx context.Context, query string, args ...any) ([]domain.ProductRevision, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []domain.ProductRevision{}
+	for rows.Next() {
+		var (
+			rev  domain.ProductRevision
+			data []byte
+		)
+		if err := rows.Scan(&rev.ID, &rev.ProductID, &rev.Actor, &rev.ChangedAt, &data); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &rev.Changes); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
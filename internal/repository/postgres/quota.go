@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// QuotaRepository implements repository.QuotaRepository interface for PostgreSQL.
+type QuotaRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewQuotaRepository creates a new API quota repository for PostgreSQL.
+func NewQuotaRepository(db *pgxpool.Pool) *QuotaRepository {
+	return &QuotaRepository{db: db}
+}
+
+func (r *QuotaRepository) FindLimits(ctx context.Context, client string) ([]domain.APIQuota, error) {
+	query := `SELECT client, period, quota_limit FROM api_quotas WHERE client = $1`
+
+	rows, err := r.db.Query(ctx, query, client)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quotas []domain.APIQuota
+	for rows.Next() {
+		var q domain.APIQuota
+		if err := rows.Scan(&q.Client, &q.Period, &q.Limit); err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, q)
+	}
+	return quotas, rows.Err()
+}
+
+func (r *QuotaRepository) SetLimit(ctx context.Context, quota domain.APIQuota) error {
+	query := `
+		INSERT INTO api_quotas (client, period, quota_limit, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (client, period) DO UPDATE SET quota_limit = EXCLUDED.quota_limit, updated_at = NOW()
+	`
+	_, err := r.db.Exec(ctx, query, quota.Client, quota.Period, quota.Limit)
+	return err
+}
+
+// Increment atomically increments the window counter, so concurrent calls are all counted.
+func (r *QuotaRepository) Increment(ctx context.Context, client, period string, windowStart time.Time) (int, error) {
+	query := `
+		INSERT INTO api_quota_usage (client, period, window_start, calls)
+		VALUES ($1, $2, $3, 1)
+		ON CONFLICT (client, period, window_start) DO UPDATE SET calls = api_quota_usage.calls + 1
+		RETURNING calls
+	`
+	var calls int
+	err := r.db.QueryRow(ctx, query, client, period, windowStart).Scan(&calls)
+	return calls, err
+}
+
+func (r *QuotaRepository) FindUsage(ctx context.Context, client, period string, windowStart time.Time) (int, error) {
+	query := `SELECT calls FROM api_quota_usage WHERE client = $1 AND period = $2 AND window_start = $3`
+
+	var calls int
+	err := r.db.QueryRow(ctx, query, client, period, windowStart).Scan(&calls)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return calls, err
+}
+
+func (r *QuotaRepository) ResetUsage(ctx context.Context, client string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM api_quota_usage WHERE client = $1`, client)
+	return err
+}
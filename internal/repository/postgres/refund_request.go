@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// refundRequestColumns are the refund request columns in the order scanRefundRequests expects.
+const refundRequestColumns = `id, order_id, payment_id, amount, items, reason, status, requested_by,
+    COALESCE(decided_by, ''), decided_at, refund_id, created_at`
+
+// rowQuerier is implemented by both the pool and transactions.
+type rowQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// RefundRequestRepository implements repository.RefundRequestRepository interface for PostgreSQL.
+type RefundRequestRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewRefundRequestRepository creates a new refund request repository for PostgreSQL.
+func NewRefundRequestRepository(db *pgxpool.Pool) *RefundRequestRepository {
+	return &RefundRequestRepository{db: db}
+}
+
+func (r *RefundRequestRepository) CreateTx(ctx context.Context, tx pgx.Tx, request *domain.RefundRequest) error {
+	items, err := json.Marshal(request.Items)
+	if err != nil {
+		return err
+	}
+	if request.Items == nil {
+		items = []byte("[]")
+	}
+	query := `INSERT INTO refund_requests (id, order_id, payment_id, amount, items, reason, status, requested_by, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err = tx.Exec(ctx, query, request.ID, request.OrderID, request.PaymentID, request.Amount, items, request.Reason,
+		request.Status, request.RequestedBy, request.CreatedAt)
+	return err
+}
+
+func (r *RefundRequestRepository) UpdateTx(ctx context.Context, tx pgx.Tx, request *domain.RefundRequest) error {
+	query := `UPDATE refund_requests SET status = $2, decided_by = NULLIF($3, ''), decided_at = $4, refund_id = $5 WHERE id = $1`
+	tag, err := tx.Exec(ctx, query, request.ID, request.Status, request.DecidedBy, request.DecidedAt, request.RefundID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRefundRequestNotFound
+	}
+	return nil
+}
+
+func (r *RefundRequestRepository) FindByIDTx(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*domain.RefundRequest, error) {
+	return findRefundRequest(ctx, tx, `SELECT `+refundRequestColumns+` FROM refund_requests WHERE id = $1 FOR UPDATE`, id)
+}
+
+func (r *RefundRequestRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.RefundRequest, error) {
+	return findRefundRequest(ctx, r.db, `SELECT `+refundRequestColumns+` FROM refund_requests WHERE id = $1`, id)
+}
+
+func (r *RefundRequestRepository) FindByOrderID(ctx context.Context, orderID uuid.UUID) ([]domain.RefundRequest, error) {
+	return findRefundRequests(ctx, r.db, `SELECT `+refundRequestColumns+` FROM refund_requests
+        WHERE order_id = $1 ORDER BY created_at, id`, orderID)
+}
+
+func (r *RefundRequestRepository) FindByOrderIDTx(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) ([]domain.RefundRequest, error) {
+	return findRefundRequests(ctx, tx, `SELECT `+refundRequestColumns+` FROM refund_requests
+        WHERE order_id = $1 ORDER BY created_at, id`, orderID)
+}
+
+func (r *RefundRequestRepository) FindPending(ctx context.Context, limit int) ([]domain.RefundRequest, error) {
+	return findRefundRequests(ctx, r.db, `SELECT `+refundRequestColumns+` FROM refund_requests
+        WHERE status = 'requested' ORDER BY created_at, id LIMIT $1`, limit)
+}
+
+func (r *RefundRequestRepository) CountPending(ctx context.Context) (int, *time.Time, error) {
+	query := `SELECT COUNT(*), MIN(created_at) FROM refund_requests WHERE status = 'requested'`
+
+	var count int
+	var oldest *time.Time
+	err := r.db.QueryRow(ctx, query).Scan(&count, &oldest)
+	return count, oldest, err
+}
+
+const insertRefundRequestEventQuery = `INSERT INTO refund_request_events (id, request_id, action, actor, note, created_at)
+    VALUES ($1, $2, $3, $4, $5, $6)`
+
+func (r *RefundRequestRepository) AppendEventTx(ctx context.Context, tx pgx.Tx, event *domain.RefundRequestEvent) error {
+	_, err := tx.Exec(ctx, insertRefundRequestEventQuery, event.ID, event.RequestID, event.Action, event.Actor,
+		event.Note, event.CreatedAt)
+	return err
+}
+
+func (r *RefundRequestRepository) AppendEvent(ctx context.Context, event *domain.RefundRequestEvent) error {
+	_, err := r.db.Exec(ctx, insertRefundRequestEventQuery, event.ID, event.RequestID, event.Action, event.Actor,
+		event.Note, event.CreatedAt)
+	return err
+}
+
+// findRefundRequest selects a single request, returning ErrRefundRequestNotFound if there is none.
+func findRefundRequest(ctx context.Context, q rowQuerier, query string, id uuid.UUID) (*domain.RefundRequest, error) {
+	requests, err := findRefundRequests(ctx, q, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, repository.ErrRefundRequestNotFound
+	}
+	return &requests[0], nil
+}
+
+// findRefundRequests selects requests with refundRequestColumns and loads their audit events.
+func findRefundRequests(ctx context.Context, q rowQuerier, query string, args ...any) ([]domain.RefundRequest, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := scanRefundRequests(rows)
+	if err != nil || len(requests) == 0 {
+		return requests, err
+	}
+
+	ids := make([]uuid.UUID, len(requests))
+	index := make(map[uuid.UUID]int, len(requests))
+	for i, req := range requests {
+		ids[i] = req.ID
+		index[req.ID] = i
+	}
+	rows, err = q.Query(ctx, `
+        SELECT id, request_id, action, actor, note, created_at
+        FROM refund_request_events
+        WHERE request_id = ANY($1)
+        ORDER BY created_at, id
+    `, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e domain.RefundRequestEvent
+		if err := rows.Scan(&e.ID, &e.RequestID, &e.Action, &e.Actor, &e.Note, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		req := &requests[index[e.RequestID]]
+		req.Events = append(req.Events, e)
+	}
+	return requests, rows.Err()
+}
+
+// scanRefundRequests scans and closes rows selected with refundRequestColumns.
+func scanRefundRequests(rows pgx.Rows) ([]domain.RefundRequest, error) {
+	defer rows.Close()
+
+	var requests []domain.RefundRequest
+	for rows.Next() {
+		var req domain.RefundRequest
+		var items []byte
+		err := rows.Scan(&req.ID, &req.OrderID, &req.PaymentID, &req.Amount, &items, &req.Reason, &req.Status,
+			&req.RequestedBy, &req.DecidedBy, &req.DecidedAt, &req.RefundID, &req.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(items, &req.Items); err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+	return requests, rows.Err()
+}
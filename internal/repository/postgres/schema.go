@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// undefinedTableCode is the PostgreSQL error code for missing tables.
+const undefinedTableCode = "42P01"
+
+// SchemaVersion returns the version of the last migration applied by golang-migrate, and whether it failed
+// half-way (dirty). Returns version 0 if no migration was applied.
+func SchemaVersion(ctx context.Context, db *pgxpool.Pool) (version uint, dirty bool, err error) {
+	var v int64
+	err = db.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&v, &dirty)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.Is(err, pgx.ErrNoRows) || (errors.As(err, &pgErr) && pgErr.Code == undefinedTableCode) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return uint(v), dirty, nil
+}
@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// sources are the files of the package, parsed to list the statements the binary runs.
+//
+//go:embed *.go
+var sources embed.FS
+
+// statementKeywords start the string literals that are SQL statements.
+var statementKeywords = []string{"SELECT", "INSERT", "UPDATE", "DELETE", "WITH"}
+
+// Statement is an SQL statement run by the repositories.
+type Statement struct {
+	Func string // Function running the statement, e.g. "OrderRepository.FindByID"
+	Pos  string // File and line of the statement, e.g. "order.go:42"
+	SQL  string
+}
+
+// Statements returns the SQL statements of the repositories: string expressions of the package starting with
+// an SQL keyword. Concatenations are resolved with string constants and local variables, e.g. column lists.
+func Statements() ([]Statement, error) {
+	names, err := fs.Glob(sources, "*.go")
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		src, err := sources.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, name, src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	consts := packageConstants(files)
+	var statements []Statement
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			locals := map[string]string{}
+			var unresolved error
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.AssignStmt:
+					// Statements are often assembled from fragments assigned earlier, e.g. a WHERE clause
+					for i, lhs := range n.Lhs {
+						if ident, ok := lhs.(*ast.Ident); ok && len(n.Rhs) == len(n.Lhs) {
+							if value, ok := evalString(n.Rhs[i], consts, locals); ok {
+								locals[ident.Name] = value
+							}
+						}
+					}
+				case *ast.BinaryExpr, *ast.BasicLit:
+					sql, ok := evalString(n.(ast.Expr), consts, locals)
+					if !ok {
+						if first, ok := firstLiteral(n.(ast.Expr)); ok && isStatement(first) && unresolved == nil {
+							unresolved = fmt.Errorf("statement at %s cannot be resolved", fset.Position(n.Pos()))
+						}
+						return unresolved == nil
+					}
+					if isStatement(sql) {
+						pos := fset.Position(n.Pos())
+						statements = append(statements, Statement{
+							Func: funcName(fn),
+							Pos:  pos.Filename + ":" + strconv.Itoa(pos.Line),
+							SQL:  strings.TrimSpace(sql),
+						})
+					}
+					return false
+				}
+				return true
+			})
+			if unresolved != nil {
+				return nil, unresolved
+			}
+		}
+	}
+	return statements, nil
+}
+
+// packageConstants returns the string constants declared at package level.
+func packageConstants(files []*ast.File) map[string]string {
+	consts := map[string]string{}
+	// Constants may be built from others declared later, so declarations are evaluated until nothing changes
+	for changed := true; changed; {
+		changed = false
+		for _, file := range files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.CONST {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					vs := spec.(*ast.ValueSpec)
+					for i, name := range vs.Names {
+						if _, done := consts[name.Name]; done || i >= len(vs.Values) {
+							continue
+						}
+						if value, ok := evalString(vs.Values[i], consts, nil); ok {
+							consts[name.Name] = value
+							changed = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return consts
+}
+
+// evalString evaluates a string expression of literals, constants and local variables.
+func evalString(expr ast.Expr, consts, locals map[string]string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.Ident:
+		if s, ok := locals[e.Name]; ok {
+			return s, true
+		}
+		s, ok := consts[e.Name]
+		return s, ok
+	case *ast.ParenExpr:
+		return evalString(e.X, consts, locals)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		x, ok := evalString(e.X, consts, locals)
+		if !ok {
+			return "", false
+		}
+		y, ok := evalString(e.Y, consts, locals)
+		return x + y, ok
+	}
+	return "", false
+}
+
+// firstLiteral returns the leftmost string literal of a concatenation.
+func firstLiteral(expr ast.Expr) (string, bool) {
+	for {
+		switch e := expr.(type) {
+		case *ast.BinaryExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.BasicLit:
+			s, err := strconv.Unquote(e.Value)
+			return s, e.Kind == token.STRING && err == nil
+		default:
+			return "", false
+		}
+	}
+}
+
+func isStatement(s string) bool {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(s), " ")
+	keyword, _, _ = strings.Cut(keyword, "\n")
+	return slices.Contains(statementKeywords, keyword)
+}
+
+// funcName returns the name of the function, qualified with the receiver type of methods.
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// StatementError is a statement failing to prepare.
+type StatementError struct {
+	Statement
+	Err error
+}
+
+// PrepareStatements prepares the statements in tx without running them, so statements referencing missing
+// tables, columns or functions fail. Each statement is prepared in a savepoint, so failures do not abort tx.
+// Returns the statements failing to prepare.
+func PrepareStatements(ctx context.Context, tx pgx.Tx, statements []Statement) ([]StatementError, error) {
+	var failed []StatementError
+	for _, s := range statements {
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// Unnamed statements are replaced by the next prepare and not cached by pgx
+		_, prepareErr := savepoint.Conn().PgConn().Prepare(ctx, "", s.SQL, nil)
+		if err := savepoint.Rollback(ctx); err != nil {
+			return nil, err
+		}
+		if prepareErr != nil {
+			failed = append(failed, StatementError{Statement: s, Err: prepareErr})
+		}
+	}
+	return failed, nil
+}
@@ -0,0 +1,26 @@
+package postgres_test
+
+import (
+	"product-api/internal/repository/postgres"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatements(t *testing.T) {
+	statements, err := postgres.Statements()
+	require.NoError(t, err)
+
+	byFunc := make(map[string]postgres.Statement, len(statements))
+	for _, s := range statements {
+		assert.NotEmpty(t, s.Func)
+		assert.Regexp(t, `^\w+\.go:\d+$`, s.Pos)
+		byFunc[s.Func] = s
+	}
+	assert.Contains(t, byFunc, "SchemaVersion")
+	assert.Contains(t, byFunc["OrderEventRepository.AppendTx"].SQL, "INSERT INTO order_events")
+	assert.NotContains(t, byFunc, "NewSlowQueryTracer", "log messages are not statements")
+	assert.Contains(t, byFunc["UserRepository.FindByUsername"].SQL, "SELECT id, firstname", "column constants are resolved")
+	assert.Contains(t, byFunc["UserRepository.List"].SQL, "FROM users WHERE", "local fragments are resolved")
+}
@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// StockRepository implements repository.StockRepository interface for PostgreSQL.
+type StockRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewStockRepository creates a new stock repository for PostgreSQL.
+func NewStockRepository(db *pgxpool.Pool) *StockRepository {
+	return &StockRepository{db: db}
+}
+
+func (r *StockRepository) Record(ctx context.Context, movement *domain.StockMovement) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		return r.RecordTx(ctx, tx, movement)
+	})
+}
+
+// RecordTx appends a movement to the ledger and applies it to the cached product quantity.
+func (r *StockRepository) RecordTx(ctx context.Context, tx pgx.Tx, movement *domain.StockMovement) error {
+	// Quantity is only decreased if enough stock is left
+	updateQuery := `UPDATE products SET quantity = quantity + $2 WHERE id = $1 AND quantity + $2 >= 0`
+	tag, err := tx.Exec(ctx, updateQuery, movement.ProductID, movement.Delta)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)`, movement.ProductID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return repository.ErrProductNotFound
+		}
+		return repository.ErrNegativeStock
+	}
+
+	return insertStockMovement(ctx, tx, movement)
+}
+
+// insertStockMovement appends a movement to the ledger without touching the product quantity.
+func insertStockMovement(ctx context.Context, tx pgx.Tx, movement *domain.StockMovement) error {
+	query := `INSERT INTO stock_movements (id, product_id, delta, reason, order_id, note, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := tx.Exec(ctx, query, movement.ID, movement.ProductID, movement.Delta, movement.Reason,
+		movement.OrderID, movement.Note, movement.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert stock movement: %w", err)
+	}
+	return nil
+}
+
+func (r *StockRepository) FindByProductID(ctx context.Context, productID uuid.UUID, limit int) ([]domain.StockMovement, error) {
+	query := `
+        SELECT id, product_id, delta, reason, order_id, note, created_at
+        FROM stock_movements
+        WHERE product_id = $1
+        ORDER BY created_at DESC
+        LIMIT $2
+    `
+	rows, err := r.db.Query(ctx, query, productID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := []domain.StockMovement{}
+	for rows.Next() {
+		var m domain.StockMovement
+		if err := rows.Scan(&m.ID, &m.ProductID, &m.Delta, &m.Reason, &m.OrderID, &m.Note, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+func (r *StockRepository) FindDrift(ctx context.Context, limit int) ([]domain.StockDrift, error) {
+	query := `
+        SELECT p.id, p.quantity, COALESCE(SUM(sm.delta), 0) AS ledger_quantity
+        FROM products p
+        LEFT JOIN stock_movements sm ON sm.product_id = p.id
+        GROUP BY p.id, p.quantity
+        HAVING p.quantity <> COALESCE(SUM(sm.delta), 0)
+        ORDER BY p.id
+        LIMIT $1
+    `
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drifts []domain.StockDrift
+	for rows.Next() {
+		var d domain.StockDrift
+		if err := rows.Scan(&d.ProductID, &d.CachedQuantity, &d.LedgerQuantity); err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, d)
+	}
+	return drifts, rows.Err()
+}
+
+// RebuildQuantity locks the product, so the returned change is not mixed up with concurrent movements.
+func (r *StockRepository) RebuildQuantity(ctx context.Context, productID uuid.UUID) (int, error) {
+	query := `
+        WITH previous AS (SELECT id, quantity FROM products WHERE id = $1 FOR UPDATE)
+        UPDATE products p
+        SET quantity = (SELECT COALESCE(SUM(delta), 0) FROM stock_movements WHERE product_id = $1)
+        FROM previous
+        WHERE p.id = previous.id
+        RETURNING p.quantity - previous.quantity
+    `
+	var delta int
+	if err := r.db.QueryRow(ctx, query, productID).Scan(&delta); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, repository.ErrProductNotFound
+		}
+		return 0, err
+	}
+	return delta, nil
+}
+
+// ImportQuantities applies all items with a single statement, locking the imported products until the transaction ends.
+func (r *StockRepository) ImportQuantities(ctx context.Context, items []repository.StockImportItem, absolute bool, note string) ([]repository.StockImportChange, error) {
+	barcodes := make([]string, len(items))
+	quantities := make([]int, len(items))
+	movementIDs := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		barcodes[i], quantities[i], movementIDs[i] = item.Barcode, item.Quantity, uuid.New()
+	}
+
+	query := `
+        WITH input AS (
+            SELECT * FROM unnest($1::text[], $2::int[], $3::uuid[]) AS i(barcode, quantity, movement_id)
+        ), target AS (
+            SELECT i.barcode, i.movement_id, p.id, p.quantity AS previous,
+                   CASE WHEN $4::boolean THEN i.quantity ELSE p.quantity + i.quantity END AS quantity
+            FROM input i
+            JOIN products p ON p.barcode = i.barcode
+            FOR UPDATE OF p
+        ), updated AS (
+            UPDATE products p
+            SET quantity = t.quantity
+            FROM target t
+            WHERE p.id = t.id AND t.quantity >= 0 AND t.quantity <> t.previous
+            RETURNING p.id
+        ), recorded AS (
+            INSERT INTO stock_movements (id, product_id, delta, reason, note, created_at)
+            SELECT t.movement_id, t.id, t.quantity - t.previous, $5, $6, $7
+            FROM target t
+            JOIN updated u ON u.id = t.id
+        )
+        SELECT barcode, id, previous, quantity FROM target
+    `
+	var changes []repository.StockImportChange
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, barcodes, quantities, movementIDs, absolute,
+			domain.StockReasonAdjustment, note, time.Now())
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var c repository.StockImportChange
+			if err := rows.Scan(&c.Barcode, &c.ProductID, &c.Previous, &c.Quantity); err != nil {
+				return err
+			}
+			changes = append(changes, c)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("import stock quantities: %w", err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// StockSnapshotRepository implements repository.StockSnapshotRepository interface for PostgreSQL.
+type StockSnapshotRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewStockSnapshotRepository creates a new stock snapshot repository for PostgreSQL.
+func NewStockSnapshotRepository(db *pgxpool.Pool) *StockSnapshotRepository {
+	return &StockSnapshotRepository{db: db}
+}
+
+func (r *StockSnapshotRepository) LastDay(ctx context.Context) (time.Time, error) {
+	var day *time.Time
+	if err := r.db.QueryRow(ctx, `SELECT MAX(day) FROM stock_snapshots`).Scan(&day); err != nil || day == nil {
+		return time.Time{}, err
+	}
+	return *day, nil
+}
+
+// Snapshot opens the day with the closing quantity of the previous day, or the sum of the ledger if the previous
+// day was not snapshotted, and replays the movements of the day over it. The product is out of stock while
+// the balance is zero, counted for products created during the day from their creation.
+func (r *StockSnapshotRepository) Snapshot(ctx context.Context, day time.Time) (int, error) {
+	query := `
+        WITH opening AS (
+            SELECT p.id AS product_id, GREATEST(p.created_at, $2::timestamptz) AS since,
+                   COALESCE(prev.quantity, (SELECT COALESCE(SUM(m.delta), 0) FROM stock_movements m
+                                            WHERE m.product_id = p.id AND m.created_at < $2)) AS quantity
+            FROM products p
+            LEFT JOIN stock_snapshots prev ON prev.product_id = p.id AND prev.day = $1::date - 1
+            WHERE p.created_at < $3
+              AND NOT EXISTS (SELECT 1 FROM product_bundle_components c WHERE c.bundle_id = p.id)
+        ),
+        movements AS (
+            SELECT m.product_id, m.delta, m.reason, m.created_at,
+                   o.quantity + SUM(m.delta) OVER w AS balance,
+                   LEAD(m.created_at, 1, $3::timestamptz) OVER w AS until
+            FROM stock_movements m
+            JOIN opening o ON o.product_id = m.product_id
+            WHERE m.created_at >= $2 AND m.created_at < $3
+            WINDOW w AS (PARTITION BY m.product_id ORDER BY m.created_at, m.id)
+        )
+        INSERT INTO stock_snapshots (day, product_id, quantity, received, sold, returned, adjusted, stockout_seconds)
+        SELECT $1::date, o.product_id,
+               o.quantity + COALESCE(SUM(m.delta), 0),
+               COALESCE(SUM(m.delta) FILTER (WHERE m.reason = 'receipt'), 0),
+               -COALESCE(SUM(m.delta) FILTER (WHERE m.reason IN ('allocation', 'release')), 0),
+               COALESCE(SUM(m.delta) FILTER (WHERE m.reason = 'return'), 0),
+               COALESCE(SUM(m.delta) FILTER (WHERE m.reason = 'adjustment'), 0),
+               ROUND(COALESCE(SUM(EXTRACT(EPOCH FROM m.until - m.created_at)) FILTER (WHERE m.balance <= 0), 0)
+                   + CASE WHEN o.quantity <= 0 THEN EXTRACT(EPOCH FROM COALESCE(MIN(m.created_at), $3::timestamptz) - o.since) ELSE 0 END)
+        FROM opening o
+        LEFT JOIN movements m ON m.product_id = o.product_id
+        GROUP BY o.product_id, o.since, o.quantity
+        ON CONFLICT (day, product_id) DO NOTHING
+    `
+	tag, err := r.db.Exec(ctx, query, day, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *StockSnapshotRepository) Export(ctx context.Context, from, to time.Time, productID *uuid.UUID, fn func(*domain.StockSnapshot) error) error {
+	query := `
+        SELECT day, product_id, quantity, received, sold, returned, adjusted, stockout_seconds
+        FROM stock_snapshots
+        WHERE day BETWEEN $1::date AND $2::date AND ($3::uuid IS NULL OR product_id = $3)
+        ORDER BY day, product_id
+    `
+	rows, err := r.db.Query(ctx, query, from, to, productID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s domain.StockSnapshot
+		if err := rows.Scan(&s.Day, &s.ProductID, &s.Quantity, &s.Received, &s.Sold, &s.Returned, &s.Adjusted,
+			&s.StockoutSeconds); err != nil {
+			return err
+		}
+		if err := fn(&s); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"product-api/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TagRepository implements repository.TagRepository interface for PostgreSQL.
+type TagRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTagRepository creates a new tag repository for PostgreSQL.
+func NewTagRepository(db *pgxpool.Pool) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+func (r *TagRepository) FindAll(ctx context.Context) ([]domain.TagCount, error) {
+	query := `SELECT tag, COUNT(DISTINCT id) FROM products, unnest(tags) AS tag
+			  GROUP BY tag ORDER BY tag`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []domain.TagCount
+	for rows.Next() {
+		var t domain.TagCount
+		if err := rows.Scan(&t.Name, &t.Products); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// MergeTx rewrites the tags of all affected products in one statement.
+// Tags keep the position of their first occurrence, so the target takes the place of the first replaced tag.
+func (r *TagRepository) MergeTx(ctx context.Context, tx pgx.Tx, sources []string, target string) ([]domain.TagChange, error) {
+	query := `WITH old AS (
+				  SELECT id, tags FROM products WHERE tags && $1::text[] FOR UPDATE
+			  )
+			  UPDATE products p SET tags = ARRAY(
+				  SELECT tag FROM (
+					  SELECT CASE WHEN t = ANY($1) THEN $2 ELSE t END AS tag, ord
+					  FROM unnest(p.tags) WITH ORDINALITY AS u(t, ord)
+				  ) renamed
+				  GROUP BY tag ORDER BY MIN(ord)
+			  )
+			  FROM old
+			  WHERE p.id = old.id
+			  RETURNING p.id, old.tags, p.tags`
+
+	rows, err := tx.Query(ctx, query, sources, target)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []domain.TagChange
+	for rows.Next() {
+		var c domain.TagChange
+		if err := rows.Scan(&c.ProductID, &c.Before, &c.After); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TokenRepository implements repository.TokenRepository interface for PostgreSQL.
+type TokenRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTokenRepository creates a new revoked token repository for PostgreSQL.
+func NewTokenRepository(db *pgxpool.Pool) *TokenRepository {
+	return &TokenRepository{db: db}
+}
+
+func (r *TokenRepository) Revoke(ctx context.Context, jti, userID uuid.UUID, expiresAt time.Time) error {
+	query := `INSERT INTO revoked_tokens (jti, user_id, expires_at) VALUES ($1, $2, $3) ON CONFLICT (jti) DO NOTHING`
+
+	_, err := r.db.Exec(ctx, query, jti, userID, expiresAt)
+	return err
+}
+
+func (r *TokenRepository) IsRevoked(ctx context.Context, jti uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)`
+
+	var revoked bool
+	err := r.db.QueryRow(ctx, query, jti).Scan(&revoked)
+	return revoked, err
+}
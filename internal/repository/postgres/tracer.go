@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"product-api/internal/logger"
+	"product-api/internal/telemetry"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// maxLoggedStatementLength limits the length of SQL statements in slow query logs.
+const maxLoggedStatementLength = 200
+
+// slowQueryStartKey is the context key for the query start time.
+type slowQueryStartKey struct{}
+
+// slowQueryStart holds data of an in-progress query.
+type slowQueryStart struct {
+	at   time.Time
+	sql  string
+	args []any
+}
+
+// SlowQueryTracer implements pgx.QueryTracer and logs queries exceeding a duration threshold.
+// Argument values are not logged, only their types, to avoid leaking personal data.
+type SlowQueryTracer struct {
+	threshold time.Duration
+	logger    logger.Logger
+}
+
+// NewSlowQueryTracer creates a tracer logging queries slower than threshold.
+func NewSlowQueryTracer(threshold time.Duration, l logger.Logger) *SlowQueryTracer {
+	return &SlowQueryTracer{threshold: threshold, logger: l}
+}
+
+// TraceQueryStart remembers the query start time.
+func (t *SlowQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, slowQueryStartKey{}, slowQueryStart{at: time.Now(), sql: data.SQL, args: data.Args})
+}
+
+// TraceQueryEnd logs and counts the query if it took longer than the threshold.
+func (t *SlowQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(slowQueryStartKey{}).(slowQueryStart)
+	if !ok {
+		return
+	}
+
+	duration := time.Since(start.at)
+	if duration < t.threshold {
+		return
+	}
+
+	statement := statementName(start.sql)
+	telemetry.RecordSlowQuery(ctx, statement)
+
+	args := []any{
+		"statement", statement,
+		"duration_ms", duration.Milliseconds(),
+		"args", summarizeArgs(start.args),
+	}
+	if data.Err != nil {
+		args = append(args, "error", data.Err)
+	}
+	t.logger.WithTrace(ctx).Warn("slow query", args...)
+}
+
+// statementName collapses whitespace of the SQL statement and truncates it.
+func statementName(sql string) string {
+	name := strings.Join(strings.Fields(sql), " ")
+	if len(name) > maxLoggedStatementLength {
+		name = name[:maxLoggedStatementLength] + "..."
+	}
+	return name
+}
+
+// summarizeArgs describes query arguments by their types, e.g. "3 args: uuid.UUID, string, int".
+func summarizeArgs(args []any) string {
+	if len(args) == 0 {
+		return "no args"
+	}
+
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = fmt.Sprintf("%T", arg)
+	}
+	return fmt.Sprintf("%d args: %s", len(args), strings.Join(types, ", "))
+}
@@ -0,0 +1,72 @@
+package postgres_test
+
+import (
+	"context"
+	"product-api/internal/logger"
+	"product-api/internal/repository/postgres"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowQueryLog keeps the arguments of logged warnings by key.
+type slowQueryLog struct {
+	warnings *[]map[string]any
+}
+
+func (l slowQueryLog) Warn(_ string, args ...any) {
+	record := make(map[string]any)
+	for i := 0; i+1 < len(args); i += 2 {
+		record[args[i].(string)] = args[i+1]
+	}
+	*l.warnings = append(*l.warnings, record)
+}
+
+func (l slowQueryLog) Info(string, ...any)                     {}
+func (l slowQueryLog) Error(string, ...any)                    {}
+func (l slowQueryLog) Debug(string, ...any)                    {}
+func (l slowQueryLog) WithTrace(context.Context) logger.Logger { return l }
+
+// traceQuery traces a query by the tracer and returns the logged warnings.
+func traceQuery(threshold time.Duration, sql string, args []any, err error) []map[string]any {
+	log := slowQueryLog{warnings: &[]map[string]any{}}
+	tracer := postgres.NewSlowQueryTracer(threshold, log)
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: sql, Args: args})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: err})
+	return *log.warnings
+}
+
+func TestSlowQueryTracer_LogsSlowQueries(t *testing.T) {
+	warnings := traceQuery(0, "SELECT id\n\t FROM users\n WHERE email = $1 AND age > $2", []any{"user@example.com", 18}, nil)
+
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "SELECT id FROM users WHERE email = $1 AND age > $2", warnings[0]["statement"])
+	assert.Equal(t, "2 args: string, int", warnings[0]["args"], "argument values are not logged")
+	assert.NotContains(t, warnings[0], "error")
+
+	assert.Empty(t, traceQuery(time.Hour, "SELECT 1", nil, nil), "queries under the threshold")
+}
+
+func TestSlowQueryTracer_TruncatesStatements(t *testing.T) {
+	sql := "SELECT " + strings.Repeat("a, ", 100) + "b FROM t WHERE id = $1"
+	warnings := traceQuery(0, sql, []any{uuid.New()}, assert.AnError)
+
+	require.Len(t, warnings, 1)
+	statement := warnings[0]["statement"].(string)
+	assert.Len(t, statement, 200+len("..."))
+	assert.True(t, strings.HasPrefix(sql, strings.TrimSuffix(statement, "...")))
+	assert.Equal(t, "1 args: uuid.UUID", warnings[0]["args"])
+	assert.Equal(t, assert.AnError, warnings[0]["error"])
+}
+
+func TestSlowQueryTracer_IgnoresQueriesNotStarted(t *testing.T) {
+	log := slowQueryLog{warnings: &[]map[string]any{}}
+	postgres.NewSlowQueryTracer(0, log).TraceQueryEnd(context.Background(), nil, pgx.TraceQueryEndData{})
+
+	assert.Empty(t, *log.warnings)
+}
@@ -5,12 +5,19 @@ import (
 	"errors"
 	"product-api/internal/domain"
 	"product-api/internal/repository"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// userColumns lists user columns in the order expected by scanUser.
+const userColumns = `id, firstname, lastname, email, COALESCE(username, ''), birthdate, is_married, password_hash,
+	is_active, COALESCE(external_id, ''), last_login_at, COALESCE(phone, ''), phone_verified_at, role, email_verified_at, locale,
+	failed_login_count, locked_until, password_changed_at, sandbox`
+
 // UserRepository implements repository.UserRepository interface for PostgreSQL.
 type UserRepository struct {
 	db *pgxpool.Pool
@@ -21,21 +28,111 @@ func NewUserRepository(db *pgxpool.Pool) *UserRepository {
 	return &UserRepository{db: db}
 }
 
+// scanUser scans a row selected with userColumns into a user.
+func scanUser(row pgx.Row, user *domain.User) error {
+	return row.Scan(
+		&user.ID,
+		&user.Firstname,
+		&user.Lastname,
+		&user.Email,
+		&user.Username,
+		&user.Birthdate,
+		&user.IsMarried,
+		&user.PasswordHash,
+		&user.IsActive,
+		&user.ExternalID,
+		&user.LastLoginAt,
+		&user.Phone,
+		&user.PhoneVerifiedAt,
+		&user.Role,
+		&user.EmailVerifiedAt,
+		&user.Locale,
+		&user.FailedLogins,
+		&user.LockedUntil,
+		&user.PasswordChangedAt,
+		&user.Sandbox,
+	)
+}
+
 func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, firstname, lastname, email, age, is_married, password_hash)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO users (id, firstname, lastname, email, username, birthdate, is_married, password_hash, is_active, external_id, role, locale)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, NULLIF($10, ''), $11, $12)
 	`
-	_, err := r.db.Exec(ctx, query, user.ID, user.Firstname, user.Lastname, user.Email, user.Age, user.IsMarried, user.PasswordHash)
+	_, err := r.db.Exec(ctx, query, user.ID, user.Firstname, user.Lastname, user.Email, user.Username,
+		user.Birthdate, user.IsMarried, user.PasswordHash, user.IsActive, user.ExternalID, user.Role, user.Locale)
+	return mapUserWriteError(err)
+}
+
+// Update saves all mutable user fields except the password hash. A changed email is no longer verified.
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	query := `
+		UPDATE users
+		SET firstname = $2, lastname = $3, email = $4, username = NULLIF($5, ''), birthdate = $6,
+			is_married = $7, is_active = $8, external_id = NULLIF($9, ''), updated_at = NOW(),
+			email_verified_at = CASE WHEN email = $4 THEN email_verified_at END
+		WHERE id = $1
+	`
+	tag, err := r.db.Exec(ctx, query, user.ID, user.Firstname, user.Lastname, user.Email, user.Username,
+		user.Birthdate, user.IsMarried, user.IsActive, user.ExternalID)
+	if err != nil {
+		return mapUserWriteError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrUserNotFound
+	}
+	return nil
+}
+
+// List returns users matching the filter ordered by creation time, with the total number of matches.
+func (r *UserRepository) List(ctx context.Context, filter repository.UserFilter, offset, limit int) ([]domain.User, int, error) {
+	where := `WHERE ($1 = '' OR email = $1) AND ($2 = '' OR username = $2) AND ($3 = '' OR external_id = $3)`
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM users `+where, filter.Email, filter.Username, filter.ExternalID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users ` + where + ` ORDER BY created_at, id OFFSET $4 LIMIT $5`
+	rows, err := r.db.Query(ctx, query, filter.Email, filter.Username, filter.ExternalID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var user domain.User
+		if err := scanUser(rows, &user); err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+
+	return users, total, rows.Err()
+}
+
+// mapUserWriteError converts unique constraint violations into repository errors.
+func mapUserWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		switch pgErr.ConstraintName {
+		case "idx_users_username":
+			return repository.ErrUsernameTaken
+		case "users_email_key":
+			return repository.ErrEmailTaken
+		case "idx_users_external_id":
+			return repository.ErrExternalIDTaken
+		}
+	}
 	return err
 }
 
 func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
-	query := `SELECT id, firstname, lastname, email, age, is_married, password_hash
-			  FROM users WHERE id = $1`
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
 
 	var user domain.User
-	err := r.db.QueryRow(ctx, query, id).Scan(&user.ID, &user.Firstname, &user.Lastname, &user.Email, &user.Age, &user.IsMarried, &user.PasswordHash)
+	err := scanUser(r.db.QueryRow(ctx, query, id), &user)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, repository.ErrUserNotFound
@@ -47,20 +144,12 @@ func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Us
 
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
-		SELECT id, firstname, lastname, email, age, is_married, password_hash
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE email = $1
 	`
 	user := &domain.User{}
-	err := r.db.QueryRow(ctx, query, email).Scan(
-		&user.ID,
-		&user.Firstname,
-		&user.Lastname,
-		&user.Email,
-		&user.Age,
-		&user.IsMarried,
-		&user.PasswordHash,
-	)
+	err := scanUser(r.db.QueryRow(ctx, query, email), user)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, repository.ErrUserNotFound
@@ -69,3 +158,178 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*domain
 	}
 	return user, nil
 }
+
+// FindByUsername finds a user by normalized username.
+func (r *UserRepository) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE username = $1`
+
+	user := &domain.User{}
+	err := scanUser(r.db.QueryRow(ctx, query, username), user)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// UpdateLastLogin sets the time of the user's last successful login and resets the failed login count.
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE users SET last_login_at = $2, failed_login_count = 0 WHERE id = $1`
+
+	_, err := r.db.Exec(ctx, query, id, at)
+	return err
+}
+
+// RecordFailedLogin increments the failed login count in one statement, so concurrent failures are all counted
+// and exactly one of them locks the account.
+func (r *UserRepository) RecordFailedLogin(ctx context.Context, id uuid.UUID, maxFailures int, lockedUntil time.Time) (bool, error) {
+	query := `
+		UPDATE users SET
+			failed_login_count = CASE WHEN failed_login_count + 1 >= $2 THEN 0 ELSE failed_login_count + 1 END,
+			locked_until = CASE WHEN failed_login_count + 1 >= $2 THEN $3 ELSE locked_until END
+		WHERE id = $1
+		RETURNING failed_login_count = 0
+	`
+	var locked bool
+	err := r.db.QueryRow(ctx, query, id, maxFailures, lockedUntil).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, repository.ErrUserNotFound
+	}
+	return locked, err
+}
+
+// UpdatePhone sets the user's phone number and the time it was verified.
+func (r *UserRepository) UpdatePhone(ctx context.Context, id uuid.UUID, phone string, verifiedAt *time.Time) error {
+	query := `UPDATE users SET phone = NULLIF($2, ''), phone_verified_at = $3, updated_at = NOW() WHERE id = $1`
+
+	tag, err := r.db.Exec(ctx, query, id, phone, verifiedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string, at time.Time) error {
+	query := `UPDATE users SET email_verified_at = $3, updated_at = NOW() WHERE id = $1 AND email = $2`
+
+	tag, err := r.db.Exec(ctx, query, id, email, at)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+			return repository.ErrUserHasOrders
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrUserNotFound
+	}
+	return nil
+}
+
+// UpdatePassword sets the password hash and the time it was changed. A changed password also resets
+// the failed login count.
+func (r *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string, changedAt time.Time) error {
+	query := `
+		UPDATE users SET password_hash = $2, password_changed_at = $3, failed_login_count = 0, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	tag, err := r.db.Exec(ctx, query, id, passwordHash, changedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, id uuid.UUID, role string) error {
+	query := `UPDATE users SET role = $2, updated_at = NOW() WHERE id = $1`
+
+	tag, err := r.db.Exec(ctx, query, id, role)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdateSandbox(ctx context.Context, id uuid.UUID, sandbox bool) error {
+	query := `UPDATE users SET sandbox = $2, updated_at = NOW() WHERE id = $1`
+
+	tag, err := r.db.Exec(ctx, query, id, sandbox)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdateLocale(ctx context.Context, id uuid.UUID, locale string) error {
+	query := `UPDATE users SET locale = $2, updated_at = NOW() WHERE id = $1`
+
+	tag, err := r.db.Exec(ctx, query, id, locale)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) FindBySegment(ctx context.Context, segment domain.AudienceSegment, after uuid.UUID, limit int) ([]domain.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE is_active AND id > $1
+			AND ($2::timestamptz IS NULL OR created_at >= $2)
+			AND ($3::timestamptz IS NULL OR created_at < $3)
+			AND ($4::timestamptz IS NULL OR last_login_at >= $4)
+			AND ($5::timestamptz IS NULL OR last_login_at IS NULL OR last_login_at < $5)
+			AND (cardinality($6::text[]) = 0 OR role = ANY($6))
+		ORDER BY id
+		LIMIT $7
+	`
+	roles := segment.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	rows, err := r.db.Query(ctx, query, after, segment.RegisteredAfter, segment.RegisteredBefore,
+		segment.ActiveSince, segment.InactiveSince, roles, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var user domain.User
+		if err := scanUser(rows, &user); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
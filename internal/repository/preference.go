@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// PreferenceRepository defines the interface for notification preference database operations.
+type PreferenceRepository interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) (domain.NotificationPreferences, error) // Only explicitly stored preferences
+	Upsert(ctx context.Context, userID uuid.UUID, prefs domain.NotificationPreferences) error
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"product-api/internal/domain"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -12,15 +13,56 @@ import (
 var (
 	// ErrProductNotFound is returned when product is not found in the database.
 	ErrProductNotFound = errors.New("product not found")
+	// ErrBarcodeTaken is returned when another product already has the barcode.
+	ErrBarcodeTaken = errors.New("barcode already taken")
+	// ErrProductInUse is returned when deleting a product referenced by order items or bundles.
+	ErrProductInUse = errors.New("product is referenced by orders or bundles")
 )
 
+// ProductFilter contains optional criteria for listing products.
+// Zero fields are ignored.
+type ProductFilter struct {
+	Status   string   // Exact status, e.g. active
+	Tags     []string // Products having all the tags
+	MinPrice *float64 // Inclusive
+	MaxPrice *float64 // Inclusive
+	InStock  bool     // Products with stock; bundles whose components have stock for one bundle
+}
+
 // ProductRepository defines the interface for product database operations.
 // Methods with Tx suffix work within a transaction.
 type ProductRepository interface {
 	Create(ctx context.Context, product *domain.Product) error
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.Product, error)
 	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Product, error)
-	Update(ctx context.Context, product *domain.Product) error
-	UpdateTx(ctx context.Context, tx pgx.Tx, product *domain.Product) error // Update within transaction
-	FindByIDTx(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*domain.Product, error) // Find with row lock (FOR UPDATE)
+	FindByBarcode(ctx context.Context, barcode string) (*domain.Product, error)
+	Update(ctx context.Context, product *domain.Product) error                             // Quantity is only changed through StockRepository
+	FindByIDTx(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*domain.Product, error)      // Find with row lock (FOR UPDATE)
+	FindByIDsTx(ctx context.Context, tx pgx.Tx, ids []uuid.UUID) ([]domain.Product, error) // Find existing products with row locks (FOR UPDATE)
+	UpdateTx(ctx context.Context, tx pgx.Tx, product *domain.Product) error                // Update within transaction, quantity is not changed
+
+	// List returns products matching the filter, newest first, with the number of matching products.
+	List(ctx context.Context, filter ProductFilter, offset, limit int) ([]domain.Product, int, error)
+
+	// Export calls fn for every product with ID greater than after, in ID order, reading from a single snapshot.
+	// Iteration stops at the first error returned by fn.
+	Export(ctx context.Context, after uuid.UUID, fn func(*domain.Product) error) error
+
+	// DeleteTx deletes the product with its stock movements and revisions.
+	// Returns ErrProductInUse if order items or bundles reference the product.
+	DeleteTx(ctx context.Context, tx pgx.Tx, id uuid.UUID) error
+
+	// CreateMany inserts products without bundle components in one transaction with COPY, and records
+	// their initial quantities as stock receipts in the ledger. Returns ErrBarcodeTaken if a barcode is taken.
+	CreateMany(ctx context.Context, products []domain.Product) error
+	// FindTakenBarcodes returns the barcodes that products already have, in no particular order.
+	FindTakenBarcodes(ctx context.Context, barcodes []string) ([]string, error)
+
+	// DeleteOrphanedDrafts deletes up to limit drafts created before the time that no bundle references,
+	// oldest first, and returns their IDs. Drafts cannot be ordered, so no order references them.
+	DeleteOrphanedDrafts(ctx context.Context, createdBefore time.Time, limit int) ([]uuid.UUID, error)
+
+	// FindIssues returns the data-quality issues of draft and active products found by the rules, every rule
+	// if rules is empty, in product ID and rule order, with the number of issues found by each rule.
+	FindIssues(ctx context.Context, rules []string, offset, limit int) ([]domain.CatalogIssue, map[string]int, error)
 }
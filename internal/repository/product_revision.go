@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ProductRevisionRepository defines the interface for the history of product edits.
+type ProductRevisionRepository interface {
+	AppendTx(ctx context.Context, tx pgx.Tx, revisions []domain.ProductRevision) error                     // Append within transaction
+	FindByProductID(ctx context.Context, productID uuid.UUID, limit int) ([]domain.ProductRevision, error) // Newest first
+	FindByField(ctx context.Context, productID uuid.UUID, field string) ([]domain.ProductRevision, error)  // Edits changing the field, oldest first
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"time"
+)
+
+// QuotaRepository defines the interface for API quota and usage counter database operations.
+type QuotaRepository interface {
+	FindLimits(ctx context.Context, client string) ([]domain.APIQuota, error) // Only explicitly stored quotas
+	SetLimit(ctx context.Context, quota domain.APIQuota) error
+	// Increment counts a call in the window and returns the number of calls made in it, including this one.
+	Increment(ctx context.Context, client, period string, windowStart time.Time) (int, error)
+	FindUsage(ctx context.Context, client, period string, windowStart time.Time) (int, error)
+	ResetUsage(ctx context.Context, client string) error // Clear counters of all windows
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrRefundRequestNotFound is returned when a refund request is not found.
+	ErrRefundRequestNotFound = errors.New("refund request not found")
+)
+
+// RefundRequestRepository defines the interface for refund requests and their append-only audit trail.
+// Requests are returned with their audit events.
+type RefundRequestRepository interface {
+	CreateTx(ctx context.Context, tx pgx.Tx, request *domain.RefundRequest) error // Insert within transaction
+	UpdateTx(ctx context.Context, tx pgx.Tx, request *domain.RefundRequest) error // Save the decision within transaction
+	// FindByIDTx returns the request with a row lock held until the transaction ends.
+	FindByIDTx(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*domain.RefundRequest, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*domain.RefundRequest, error)
+	FindByOrderID(ctx context.Context, orderID uuid.UUID) ([]domain.RefundRequest, error)
+	FindByOrderIDTx(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) ([]domain.RefundRequest, error)
+	FindPending(ctx context.Context, limit int) ([]domain.RefundRequest, error) // Oldest first
+	// CountPending returns the number of requests waiting for a decision and the creation time of the oldest, nil if none.
+	CountPending(ctx context.Context) (int, *time.Time, error)
+	AppendEventTx(ctx context.Context, tx pgx.Tx, event *domain.RefundRequestEvent) error
+	AppendEvent(ctx context.Context, event *domain.RefundRequestEvent) error
+}
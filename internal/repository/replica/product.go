@@ -0,0 +1,53 @@
+package replica
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"product-api/internal/telemetry"
+
+	"github.com/google/uuid"
+)
+
+// ProductRepository is a repository.ProductRepository serving FindByID from read replicas.
+// Other methods, including writes and reads that must see them, use the primary.
+type ProductRepository struct {
+	repository.ProductRepository // Primary
+	replicas                     *replicas[repository.ProductRepository]
+	logger                       logger.Logger
+}
+
+// NewProductRepository creates a product repository reading products by ID from replicas and using primary otherwise.
+// Without replicas, all methods use primary.
+func NewProductRepository(primary repository.ProductRepository, replicaRepos []repository.ProductRepository, opts Options, logger logger.Logger) *ProductRepository {
+	return &ProductRepository{
+		ProductRepository: primary,
+		replicas:          &replicas[repository.ProductRepository]{repos: replicaRepos, opts: opts},
+		logger:            logger,
+	}
+}
+
+// FindByID reads the product from replicas, and from the primary if they fail,
+// e.g. for products created after the replicas last caught up.
+func (r *ProductRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	if len(r.replicas.repos) == 0 {
+		return r.ProductRepository.FindByID(ctx, id)
+	}
+	product, source, err := read(ctx, r.replicas, func(ctx context.Context, repo repository.ProductRepository) (*domain.Product, error) {
+		return repo.FindByID(ctx, id)
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		if !errors.Is(err, repository.ErrProductNotFound) {
+			r.logger.WithTrace(ctx).Warn("replica read failed, reading from primary", "repository", "products", "op", "FindByID", "error", err)
+		}
+		product, err = r.ProductRepository.FindByID(ctx, id)
+		source = SourcePrimary
+	}
+	telemetry.RecordReplicaRead(ctx, "products", "FindByID", source)
+	return product, err
+}
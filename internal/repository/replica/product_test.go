@@ -0,0 +1,103 @@
+package replica_test
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/repository/replica"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newReplicaRepository(t *testing.T, opts replica.Options, replicas int) (*replica.ProductRepository, *mocks.MockProductRepository, []*mocks.MockProductRepository) {
+	primary := mocks.NewMockProductRepository(t)
+	mockReplicas := make([]*mocks.MockProductRepository, replicas)
+	repos := make([]repository.ProductRepository, replicas)
+	for i := range mockReplicas {
+		mockReplicas[i] = mocks.NewMockProductRepository(t)
+		repos[i] = mockReplicas[i]
+	}
+	return replica.NewProductRepository(primary, repos, opts, logger.NewSlogAdapter("local")), primary, mockReplicas
+}
+
+// slowFindByID returns the product after delay, or the context error if the read is cancelled first.
+func slowFindByID(delay time.Duration, product *domain.Product) func(context.Context, uuid.UUID) (*domain.Product, error) {
+	return func(ctx context.Context, _ uuid.UUID) (*domain.Product, error) {
+		select {
+		case <-time.After(delay):
+			return product, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func TestProductRepository_ReadsFromReplica(t *testing.T) {
+	repo, _, replicas := newReplicaRepository(t, replica.Options{}, 1)
+	id := uuid.New()
+	replicas[0].EXPECT().FindByID(mock.Anything, id).Return(&domain.Product{ID: id, Description: "replica"}, nil)
+
+	product, err := repo.FindByID(context.Background(), id)
+
+	require.NoError(t, err)
+	assert.Equal(t, "replica", product.Description)
+}
+
+func TestProductRepository_FallsBackToPrimary(t *testing.T) {
+	id := uuid.New()
+	for name, replicaErr := range map[string]error{
+		"not replicated yet":  repository.ErrProductNotFound,
+		"replica unavailable": errors.New("connection refused"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo, primary, replicas := newReplicaRepository(t, replica.Options{}, 1)
+			replicas[0].EXPECT().FindByID(mock.Anything, id).Return(nil, replicaErr)
+			primary.EXPECT().FindByID(mock.Anything, id).Return(&domain.Product{ID: id, Description: "primary"}, nil)
+
+			product, err := repo.FindByID(context.Background(), id)
+
+			require.NoError(t, err)
+			assert.Equal(t, "primary", product.Description)
+		})
+	}
+}
+
+func TestProductRepository_HedgesSlowReads(t *testing.T) {
+	repo, _, replicas := newReplicaRepository(t, replica.Options{Hedge: true, HedgeDelay: 10 * time.Millisecond}, 2)
+	id := uuid.New()
+	// Replicas are used in turn, so either can be asked first
+	replicas[0].EXPECT().FindByID(mock.Anything, id).RunAndReturn(slowFindByID(time.Minute, &domain.Product{ID: id})).Maybe()
+	replicas[1].EXPECT().FindByID(mock.Anything, id).RunAndReturn(slowFindByID(time.Minute, &domain.Product{ID: id})).Maybe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := repo.FindByID(ctx, id)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	replicas[0].AssertNumberOfCalls(t, "FindByID", 1)
+	replicas[1].AssertNumberOfCalls(t, "FindByID", 1)
+}
+
+func TestProductRepository_FirstHedgedResultWins(t *testing.T) {
+	repo, _, replicas := newReplicaRepository(t, replica.Options{Hedge: true, HedgeDelay: 10 * time.Millisecond}, 2)
+	id := uuid.New()
+	replicas[0].EXPECT().FindByID(mock.Anything, id).RunAndReturn(slowFindByID(time.Millisecond, &domain.Product{ID: id, Description: "fast"})).Maybe()
+	replicas[1].EXPECT().FindByID(mock.Anything, id).RunAndReturn(slowFindByID(time.Minute, &domain.Product{ID: id, Description: "stalled"})).Maybe()
+
+	// Whichever replica is asked first, the stalled one never answers first
+	for range 2 {
+		start := time.Now()
+		product, err := repo.FindByID(context.Background(), id)
+
+		require.NoError(t, err)
+		assert.Equal(t, "fast", product.Description)
+		assert.Less(t, time.Since(start), time.Second)
+	}
+}
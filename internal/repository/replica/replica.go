@@ -0,0 +1,147 @@
+// Package replica provides repository decorators serving latency-sensitive reads from read replicas.
+// Reads are spread over the replicas and, with hedging, also sent to a second replica once the first one
+// is slower than usual; the first successful result is used. Reads failing on replicas, e.g. of rows
+// not replicated yet, are retried on the primary, so replicas cannot fail requests the primary would serve.
+package replica
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Options contains settings of reads from replicas.
+type Options struct {
+	Hedge      bool          // Send reads still running after the hedge delay to a second replica, requires two replicas
+	HedgeDelay time.Duration // Wait before hedging, 0 uses the p95 latency of recent reads
+}
+
+// Sources of read results recorded in metrics.
+const (
+	SourceReplica = "replica" // The first replica asked
+	SourceHedge   = "hedge"   // The second replica asked, after the hedge delay or a failure of the first
+	SourcePrimary = "primary" // The primary, after replicas failed
+)
+
+const (
+	latencySamples        = 512                   // Recent read latencies the p95 is computed from
+	latencyUpdateEvery    = 32                    // Reads between updates of the p95
+	defaultHedgeDelay     = 50 * time.Millisecond // Hedge delay until enough reads were observed
+	minAdaptiveHedgeDelay = time.Millisecond
+)
+
+// latencies keeps recent read latencies and their p95.
+type latencies struct {
+	mu      sync.Mutex
+	samples []time.Duration // Ring buffer
+	next    int             // Index of the next sample
+	count   int             // Samples observed since the last p95 update
+	p95     atomic.Int64    // Nanoseconds, 0 until enough reads were observed
+}
+
+func (l *latencies) observe(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.samples) < latencySamples {
+		l.samples = append(l.samples, d)
+	} else {
+		l.samples[l.next] = d
+	}
+	l.next = (l.next + 1) % latencySamples
+	if l.count++; l.count < latencyUpdateEvery {
+		return
+	}
+	l.count = 0
+	sorted := slices.Sorted(slices.Values(l.samples))
+	l.p95.Store(int64(sorted[len(sorted)*95/100]))
+}
+
+// hedgeDelay returns how long a read runs before it is hedged.
+func (l *latencies) hedgeDelay(opts Options) time.Duration {
+	if opts.HedgeDelay > 0 {
+		return opts.HedgeDelay
+	}
+	if p95 := time.Duration(l.p95.Load()); p95 > 0 {
+		return max(p95, minAdaptiveHedgeDelay)
+	}
+	return defaultHedgeDelay
+}
+
+// replicas routes reads to replicas of type R.
+type replicas[R any] struct {
+	repos     []R
+	opts      Options
+	next      atomic.Uint64 // Round-robin counter of the first replica of reads
+	latencies latencies
+}
+
+// readResult is the result of a read from one replica.
+type readResult[T any] struct {
+	value T
+	err   error
+	hedge bool
+	took  time.Duration
+}
+
+// read calls fn on replicas and returns the first successful result with its source, or the last error.
+// Without hedging, a single replica is asked.
+func read[R, T any](ctx context.Context, r *replicas[R], fn func(ctx context.Context, repo R) (T, error)) (T, string, error) {
+	first := int(r.next.Add(1) % uint64(len(r.repos)))
+	if !r.opts.Hedge || len(r.repos) < 2 {
+		start := time.Now()
+		value, err := fn(ctx, r.repos[first])
+		if err == nil {
+			r.latencies.observe(time.Since(start))
+		}
+		return value, SourceReplica, err
+	}
+
+	// The losing read is cancelled on return; results has room for both, so it never blocks
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan readResult[T], 2)
+	send := func(repo R, hedge bool) {
+		go func() {
+			start := time.Now()
+			value, err := fn(ctx, repo)
+			results <- readResult[T]{value: value, err: err, hedge: hedge, took: time.Since(start)}
+		}()
+	}
+
+	send(r.repos[first], false)
+	timer := time.NewTimer(r.latencies.hedgeDelay(r.opts))
+	defer timer.Stop()
+	hedge := timer.C
+	hedgeNow := func() {
+		send(r.repos[(first+1)%len(r.repos)], true)
+		hedge = nil
+	}
+
+	var zero T
+	var lastErr error
+	for sent, received := 1, 0; received < sent; {
+		select {
+		case <-hedge:
+			hedgeNow()
+			sent++
+		case res := <-results:
+			received++
+			if res.err == nil {
+				r.latencies.observe(res.took)
+				if res.hedge {
+					return res.value, SourceHedge, nil
+				}
+				return res.value, SourceReplica, nil
+			}
+			lastErr = res.err
+			if hedge != nil {
+				// The first replica failed before the hedge delay, e.g. it is down
+				hedgeNow()
+				sent++
+			}
+		}
+	}
+	return zero, SourceReplica, lastErr
+}
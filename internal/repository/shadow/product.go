@@ -0,0 +1,118 @@
+package shadow
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ProductRepository is a repository.ProductRepository mirroring reads, and optionally writes, to a secondary.
+// Methods within a transaction of the primary database, exports, imports, draft cleanups and issue reports are not mirrored.
+type ProductRepository struct {
+	primary   repository.ProductRepository
+	secondary repository.ProductRepository
+	*shadow
+}
+
+// NewProductRepository creates a product repository returning results of primary and mirroring calls to secondary.
+func NewProductRepository(primary, secondary repository.ProductRepository, opts Options, logger logger.Logger) *ProductRepository {
+	return &ProductRepository{primary: primary, secondary: secondary, shadow: newShadow("products", opts, logger)}
+}
+
+func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) error {
+	err := r.primary.Create(ctx, product)
+	mirrored := *product
+	r.mirrorWrite(ctx, "Create", product.ID, err, func(ctx context.Context) error {
+		return r.secondary.Create(ctx, &mirrored)
+	})
+	return err
+}
+
+func (r *ProductRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	product, err := r.primary.FindByID(ctx, id)
+	compareRead(ctx, r.shadow, "FindByID", id, product, err, func(ctx context.Context) (*domain.Product, error) {
+		return r.secondary.FindByID(ctx, id)
+	})
+	return product, err
+}
+
+func (r *ProductRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Product, error) {
+	products, err := r.primary.FindByIDs(ctx, ids)
+	compareRead(ctx, r.shadow, "FindByIDs", len(ids), products, err, func(ctx context.Context) ([]domain.Product, error) {
+		return r.secondary.FindByIDs(ctx, ids)
+	})
+	return products, err
+}
+
+func (r *ProductRepository) FindByBarcode(ctx context.Context, barcode string) (*domain.Product, error) {
+	product, err := r.primary.FindByBarcode(ctx, barcode)
+	compareRead(ctx, r.shadow, "FindByBarcode", barcode, product, err, func(ctx context.Context) (*domain.Product, error) {
+		return r.secondary.FindByBarcode(ctx, barcode)
+	})
+	return product, err
+}
+
+// productPage is the result of List compared between repositories.
+type productPage struct {
+	products []domain.Product
+	total    int
+}
+
+func (r *ProductRepository) List(ctx context.Context, filter repository.ProductFilter, offset, limit int) ([]domain.Product, int, error) {
+	products, total, err := r.primary.List(ctx, filter, offset, limit)
+	compareRead(ctx, r.shadow, "List", offset, productPage{products, total}, err, func(ctx context.Context) (productPage, error) {
+		products, total, err := r.secondary.List(ctx, filter, offset, limit)
+		return productPage{products, total}, err
+	})
+	return products, total, err
+}
+
+func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
+	err := r.primary.Update(ctx, product)
+	mirrored := *product
+	r.mirrorWrite(ctx, "Update", product.ID, err, func(ctx context.Context) error {
+		return r.secondary.Update(ctx, &mirrored)
+	})
+	return err
+}
+
+func (r *ProductRepository) FindByIDTx(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*domain.Product, error) {
+	return r.primary.FindByIDTx(ctx, tx, id)
+}
+
+func (r *ProductRepository) FindByIDsTx(ctx context.Context, tx pgx.Tx, ids []uuid.UUID) ([]domain.Product, error) {
+	return r.primary.FindByIDsTx(ctx, tx, ids)
+}
+
+func (r *ProductRepository) UpdateTx(ctx context.Context, tx pgx.Tx, product *domain.Product) error {
+	return r.primary.UpdateTx(ctx, tx, product)
+}
+
+func (r *ProductRepository) DeleteTx(ctx context.Context, tx pgx.Tx, id uuid.UUID) error {
+	return r.primary.DeleteTx(ctx, tx, id)
+}
+
+func (r *ProductRepository) DeleteOrphanedDrafts(ctx context.Context, createdBefore time.Time, limit int) ([]uuid.UUID, error) {
+	return r.primary.DeleteOrphanedDrafts(ctx, createdBefore, limit)
+}
+
+func (r *ProductRepository) Export(ctx context.Context, after uuid.UUID, fn func(*domain.Product) error) error {
+	return r.primary.Export(ctx, after, fn)
+}
+
+func (r *ProductRepository) CreateMany(ctx context.Context, products []domain.Product) error {
+	return r.primary.CreateMany(ctx, products)
+}
+
+func (r *ProductRepository) FindTakenBarcodes(ctx context.Context, barcodes []string) ([]string, error) {
+	return r.primary.FindTakenBarcodes(ctx, barcodes)
+}
+
+func (r *ProductRepository) FindIssues(ctx context.Context, rules []string, offset, limit int) ([]domain.CatalogIssue, map[string]int, error) {
+	return r.primary.FindIssues(ctx, rules, offset, limit)
+}
@@ -0,0 +1,97 @@
+package shadow_test
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/repository/shadow"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newShadowRepository(t *testing.T, opts shadow.Options) (*shadow.ProductRepository, *mocks.MockProductRepository, *mocks.MockProductRepository) {
+	primary := mocks.NewMockProductRepository(t)
+	secondary := mocks.NewMockProductRepository(t)
+	opts.Timeout = time.Second
+	opts.MaxInFlight = 4
+	return shadow.NewProductRepository(primary, secondary, opts, logger.NewSlogAdapter("local")), primary, secondary
+}
+
+func TestProductRepository_ReadsReturnPrimaryResult(t *testing.T) {
+	repo, primary, secondary := newShadowRepository(t, shadow.Options{SampleRate: 1})
+	id := uuid.New()
+	primary.EXPECT().FindByID(mock.Anything, id).Return(&domain.Product{ID: id, Description: "primary"}, nil)
+	secondary.EXPECT().FindByID(mock.Anything, id).Return(&domain.Product{ID: id, Description: "secondary"}, nil)
+
+	product, err := repo.FindByID(context.Background(), id)
+	repo.Wait()
+
+	require.NoError(t, err)
+	assert.Equal(t, "primary", product.Description)
+}
+
+func TestProductRepository_SecondaryOutlivesRequest(t *testing.T) {
+	repo, primary, secondary := newShadowRepository(t, shadow.Options{SampleRate: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // e.g. the client disconnected once the response was written
+	primary.EXPECT().FindByBarcode(mock.Anything, "4006381333931").Return(nil, repository.ErrProductNotFound)
+	secondary.EXPECT().FindByBarcode(mock.Anything, "4006381333931").
+		RunAndReturn(func(ctx context.Context, _ string) (*domain.Product, error) {
+			assert.NoError(t, ctx.Err())
+			return nil, repository.ErrProductNotFound
+		})
+
+	_, err := repo.FindByBarcode(ctx, "4006381333931")
+	repo.Wait()
+
+	assert.ErrorIs(t, err, repository.ErrProductNotFound)
+}
+
+func TestProductRepository_UnsampledReadsAreNotMirrored(t *testing.T) {
+	repo, primary, _ := newShadowRepository(t, shadow.Options{SampleRate: 0})
+	primary.EXPECT().List(mock.Anything, repository.ProductFilter{}, 0, 20).Return([]domain.Product{}, 0, nil)
+
+	_, _, err := repo.List(context.Background(), repository.ProductFilter{}, 0, 20)
+	repo.Wait()
+
+	require.NoError(t, err)
+}
+
+func TestProductRepository_WritesMirroredWhenEnabled(t *testing.T) {
+	product := &domain.Product{ID: uuid.New(), Description: "Widget"}
+
+	t.Run("enabled", func(t *testing.T) {
+		repo, primary, secondary := newShadowRepository(t, shadow.Options{Writes: true})
+		primary.EXPECT().Update(mock.Anything, product).Return(nil)
+		secondary.EXPECT().Update(mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.ID == product.ID && p != product
+		})).Return(errors.New("secondary unavailable"))
+
+		require.NoError(t, repo.Update(context.Background(), product))
+		repo.Wait()
+	})
+
+	t.Run("primary failed", func(t *testing.T) {
+		repo, primary, _ := newShadowRepository(t, shadow.Options{Writes: true})
+		primary.EXPECT().Update(mock.Anything, product).Return(repository.ErrProductNotFound)
+
+		assert.ErrorIs(t, repo.Update(context.Background(), product), repository.ErrProductNotFound)
+		repo.Wait()
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		repo, primary, _ := newShadowRepository(t, shadow.Options{})
+		primary.EXPECT().Create(mock.Anything, product).Return(nil)
+
+		require.NoError(t, repo.Create(context.Background(), product))
+		repo.Wait()
+	})
+}
@@ -0,0 +1,120 @@
+// Package shadow provides repository decorators mirroring traffic to a secondary implementation,
+// e.g. a new backend during a migration. Results of the primary are returned to callers unchanged;
+// the secondary is called asynchronously and its results are compared, so it cannot slow down or fail requests.
+package shadow
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"product-api/internal/logger"
+	"product-api/internal/telemetry"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Options contains settings of shadow traffic.
+type Options struct {
+	SampleRate  float64       // Share of reads mirrored to the secondary, 0 to 1
+	Writes      bool          // Mirror successful writes to the secondary (dual-write)
+	Timeout     time.Duration // Timeout of calls to the secondary
+	MaxInFlight int           // Calls to the secondary in progress at most, further calls are skipped
+}
+
+// Comparison outcomes recorded in metrics.
+const (
+	OutcomeMatch    = "match"    // The secondary returned the same result
+	OutcomeMismatch = "mismatch" // The secondary returned a different result or error
+	OutcomeSkipped  = "skipped"  // Too many calls to the secondary were in progress
+	OutcomeWritten  = "written"  // A write was mirrored
+	OutcomeFailed   = "failed"   // A mirrored write failed
+)
+
+// shadow runs calls to the secondary in the background.
+type shadow struct {
+	name     string // Repository name of logs and metrics, e.g. "products"
+	opts     Options
+	logger   logger.Logger
+	inFlight chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newShadow(name string, opts Options, logger logger.Logger) *shadow {
+	return &shadow{name: name, opts: opts, logger: logger, inFlight: make(chan struct{}, max(opts.MaxInFlight, 1))}
+}
+
+// Wait waits for calls to the secondary in progress, e.g. before shutdown.
+func (s *shadow) Wait() {
+	s.wg.Wait()
+}
+
+// run calls fn with a context detached from the request's cancellation, unless too many calls are in progress.
+func (s *shadow) run(ctx context.Context, op string, fn func(ctx context.Context)) {
+	select {
+	case s.inFlight <- struct{}{}:
+	default:
+		telemetry.RecordShadowCall(ctx, s.name, op, OutcomeSkipped)
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() { <-s.inFlight }()
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.opts.Timeout)
+		defer cancel()
+		fn(ctx)
+	}()
+}
+
+// sampled reports whether a read is mirrored.
+func (s *shadow) sampled() bool {
+	return s.opts.SampleRate >= 1 || rand.Float64() < s.opts.SampleRate
+}
+
+// compareRead mirrors a sampled read to the secondary and compares its result with the primary's.
+// Errors match if the secondary error wraps the primary one, e.g. the same not found error.
+// key identifies the read in logs, e.g. the requested ID.
+func compareRead[T any](ctx context.Context, s *shadow, op string, key any, want T, wantErr error, read func(ctx context.Context) (T, error)) {
+	if !s.sampled() {
+		return
+	}
+	s.run(ctx, op, func(ctx context.Context) {
+		got, err := read(ctx)
+		outcome := OutcomeMatch
+		switch {
+		case wantErr != nil || err != nil:
+			if wantErr == nil || !errors.Is(err, wantErr) {
+				outcome = OutcomeMismatch
+				s.logger.WithTrace(ctx).Warn("shadow read error mismatch", "repository", s.name, "op", op, "key", key,
+					"primary_error", errString(wantErr), "secondary_error", errString(err))
+			}
+		case !reflect.DeepEqual(want, got):
+			outcome = OutcomeMismatch
+			s.logger.WithTrace(ctx).Warn("shadow read result mismatch", "repository", s.name, "op", op, "key", key)
+		}
+		telemetry.RecordShadowCall(ctx, s.name, op, outcome)
+	})
+}
+
+// mirrorWrite mirrors a successful write to the secondary if writes are mirrored.
+func (s *shadow) mirrorWrite(ctx context.Context, op string, key any, primaryErr error, write func(ctx context.Context) error) {
+	if !s.opts.Writes || primaryErr != nil {
+		return
+	}
+	s.run(ctx, op, func(ctx context.Context) {
+		outcome := OutcomeWritten
+		if err := write(ctx); err != nil {
+			outcome = OutcomeFailed
+			s.logger.WithTrace(ctx).Warn("shadow write failed", "repository", s.name, "op", op, "key", key, "error", err)
+		}
+		telemetry.RecordShadowCall(ctx, s.name, op, outcome)
+	})
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrNegativeStock is returned when a stock movement would make the product quantity negative.
+	ErrNegativeStock = errors.New("stock quantity cannot be negative")
+)
+
+// StockRepository defines the interface for the stock movement ledger.
+// Recording a movement also updates the cached product quantity.
+type StockRepository interface {
+	Record(ctx context.Context, movement *domain.StockMovement) error
+	RecordTx(ctx context.Context, tx pgx.Tx, movement *domain.StockMovement) error // Record within transaction
+	FindByProductID(ctx context.Context, productID uuid.UUID, limit int) ([]domain.StockMovement, error)
+	FindDrift(ctx context.Context, limit int) ([]domain.StockDrift, error) // Products whose quantity differs from the ledger
+	RebuildQuantity(ctx context.Context, productID uuid.UUID) (int, error) // Set quantity to the sum of the ledger, returns the change
+
+	// ImportQuantities sets (absolute) or changes the quantities of products by barcode in one transaction,
+	// recording an adjustment movement with the note for every changed quantity. Changes that would make
+	// a quantity negative are not applied. Returns the changes of items whose barcode matches a product.
+	ImportQuantities(ctx context.Context, items []StockImportItem, absolute bool, note string) ([]StockImportChange, error)
+}
+
+// StockImportItem is an imported quantity, or change of quantity, of the product with the barcode.
+type StockImportItem struct {
+	Barcode  string
+	Quantity int
+}
+
+// StockImportChange is the quantity change of an imported product.
+// Changes to a negative quantity are not applied.
+type StockImportChange struct {
+	Barcode   string
+	ProductID uuid.UUID
+	Previous  int // Quantity before the import
+	Quantity  int // Quantity after the import
+}
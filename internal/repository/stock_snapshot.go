@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockSnapshotRepository defines the interface for daily stock snapshots of products.
+type StockSnapshotRepository interface {
+	// LastDay returns the latest day with snapshots, the zero time if none were taken.
+	LastDay(ctx context.Context) (time.Time, error)
+	// Snapshot records the snapshots of the day (midnight UTC) from the stock ledger for all products but bundles
+	// created before the end of the day, and returns the number recorded. Products already snapshotted on the day are skipped.
+	Snapshot(ctx context.Context, day time.Time) (int, error)
+	// Export calls fn for every snapshot of the days in [from, to], of the product if productID is not nil,
+	// ordered by day and product.
+	Export(ctx context.Context, from, to time.Time, productID *uuid.UUID, fn func(*domain.StockSnapshot) error) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"product-api/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TagRepository defines the interface for set-based operations on product tags.
+type TagRepository interface {
+	FindAll(ctx context.Context) ([]domain.TagCount, error) // Tags ordered by name
+	// MergeTx replaces the source tags with the target in all products within a transaction,
+	// keeping each tag once per product. Returns tags of the changed products before and after the merge.
+	MergeTx(ctx context.Context, tx pgx.Tx, sources []string, target string) ([]domain.TagChange, error)
+}
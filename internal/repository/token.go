@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenRepository defines the interface for revoked token database operations.
+type TokenRepository interface {
+	Revoke(ctx context.Context, jti, userID uuid.UUID, expiresAt time.Time) error // Idempotent
+	IsRevoked(ctx context.Context, jti uuid.UUID) (bool, error)
+}
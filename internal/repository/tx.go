@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+//go:generate sh -c "cd ../.. && mockery"
+
+// TxBeginner starts database transactions for repository methods with Tx suffix.
+// Implemented by *pgxpool.Pool.
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
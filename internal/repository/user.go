@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"product-api/internal/domain"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,11 +12,59 @@ import (
 var (
 	// ErrUserNotFound is returned when user is not found in the database.
 	ErrUserNotFound = errors.New("user not found")
+	// ErrUsernameTaken is returned when username is already used by another user.
+	ErrUsernameTaken = errors.New("username already taken")
+	// ErrEmailTaken is returned when email is already used by another user.
+	ErrEmailTaken = errors.New("email already taken")
+	// ErrExternalIDTaken is returned when external identity provider ID is already linked to another user.
+	ErrExternalIDTaken = errors.New("external id already taken")
+	// ErrUserHasOrders is returned when deleting a user who placed orders.
+	ErrUserHasOrders = errors.New("user has orders")
 )
 
+// UserFilter contains optional exact-match criteria for listing users.
+// Empty fields are ignored.
+type UserFilter struct {
+	Email      string
+	Username   string
+	ExternalID string
+}
+
 // UserRepository defines the interface for user database operations.
 type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
+	FindByUsername(ctx context.Context, username string) (*domain.User, error)
+	Update(ctx context.Context, user *domain.User) error // Update all fields except password hash, see UpdatePassword
+	List(ctx context.Context, filter UserFilter, offset, limit int) ([]domain.User, int, error)
+	UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error // Also resets the count of failed logins
+
+	// Delete deletes the user with their consents, preferences, login history, identities and notifications.
+	// Returns ErrUserNotFound, and ErrUserHasOrders as orders are kept.
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// RecordFailedLogin counts a failed password login of the user. The maxFailures-th consecutive failure locks
+	// the account until lockedUntil and resets the count. Returns whether the failure locked the account, and ErrUserNotFound.
+	RecordFailedLogin(ctx context.Context, id uuid.UUID, maxFailures int, lockedUntil time.Time) (bool, error)
+	UpdatePhone(ctx context.Context, id uuid.UUID, phone string, verifiedAt *time.Time) error // An empty phone removes it
+
+	// MarkEmailVerified sets the verification time of the user's email if it is still the given address.
+	// Returns ErrUserNotFound if there is no such user or the email changed.
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, email string, at time.Time) error
+
+	// UpdatePassword replaces the password hash of the user and records when it was changed. Returns ErrUserNotFound.
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string, changedAt time.Time) error
+
+	// UpdateRole changes the role of the user. Returns ErrUserNotFound.
+	UpdateRole(ctx context.Context, id uuid.UUID, role string) error
+
+	// UpdateSandbox marks the user as a sandbox account or a regular one. Returns ErrUserNotFound.
+	UpdateSandbox(ctx context.Context, id uuid.UUID, sandbox bool) error
+
+	// UpdateLocale changes the preferred locale of the user, an empty locale removes it. Returns ErrUserNotFound.
+	UpdateLocale(ctx context.Context, id uuid.UUID, locale string) error
+
+	// FindBySegment returns up to limit active users of the segment with IDs after the given one, ordered by ID.
+	FindBySegment(ctx context.Context, segment domain.AudienceSegment, after uuid.UUID, limit int) ([]domain.User, error)
 }
@@ -0,0 +1,335 @@
+// Package scim implements the SCIM 2.0 (RFC 7643/7644) resource model for users
+// and the mapping between SCIM attributes and domain users.
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SCIM schema URNs.
+const (
+	SchemaUser          = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaUserExtension = "urn:productapi:params:scim:schemas:extension:2.0:User" // Product API specific attributes
+	SchemaListResponse  = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp       = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError         = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// ContentType is the media type of SCIM requests and responses.
+const ContentType = "application/scim+json"
+
+var (
+	// ErrInvalidValue is returned when a SCIM attribute has a missing or malformed value.
+	ErrInvalidValue = errors.New("invalid attribute value")
+	// ErrInvalidFilter is returned when a filter expression is not supported.
+	ErrInvalidFilter = errors.New("unsupported filter")
+	// ErrInvalidPath is returned when a PATCH operation targets an unsupported attribute.
+	ErrInvalidPath = errors.New("unsupported attribute path")
+)
+
+// User is the SCIM representation of a user.
+type User struct {
+	Schemas    []string       `json:"schemas"`
+	ID         string         `json:"id,omitempty"`
+	ExternalID string         `json:"externalId,omitempty"`
+	UserName   string         `json:"userName"`
+	Name       *Name          `json:"name,omitempty"`
+	Emails     []Email        `json:"emails,omitempty"`
+	Active     *bool          `json:"active,omitempty"`
+	Meta       *Meta          `json:"meta,omitempty"`
+	Extension  *UserExtension `json:"urn:productapi:params:scim:schemas:extension:2.0:User,omitempty"`
+}
+
+// Name contains the components of a user's name.
+type Name struct {
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// Email contains a user email address.
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Meta contains resource metadata.
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location,omitempty"`
+}
+
+// UserExtension contains Product API specific user attributes.
+type UserExtension struct {
+	Birthdate string `json:"birthdate,omitempty"` // Format: YYYY-MM-DD
+}
+
+// ListResponse is a page of SCIM resources.
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []User   `json:"Resources"`
+}
+
+// PatchRequest contains a list of SCIM PATCH operations.
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation is a single SCIM PATCH operation.
+// Value is either a single attribute value (when Path is set) or an object of attributes.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Error is the SCIM error response body.
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// NewError creates a SCIM error response body.
+func NewError(status int, scimType, detail string) Error {
+	return Error{Schemas: []string{SchemaError}, Status: strconv.Itoa(status), ScimType: scimType, Detail: detail}
+}
+
+// Filter contains supported criteria of a SCIM filter expression.
+type Filter struct {
+	UserName   string
+	Email      string
+	ExternalID string
+}
+
+// filterPattern matches simple equality filters, e.g. userName eq "john".
+var filterPattern = regexp.MustCompile(`^\s*(userName|externalId|emails\.value|emails)\s+eq\s+"([^"]*)"\s*$`)
+
+// ParseFilter parses a filter expression. Only equality on userName, externalId
+// and emails.value is supported; an empty expression matches all users.
+func ParseFilter(expr string) (Filter, error) {
+	var f Filter
+	if strings.TrimSpace(expr) == "" {
+		return f, nil
+	}
+
+	m := filterPattern.FindStringSubmatch(expr)
+	if m == nil {
+		return f, fmt.Errorf("%w: %s", ErrInvalidFilter, expr)
+	}
+
+	switch m[1] {
+	case "userName":
+		f.UserName = m[2]
+	case "externalId":
+		f.ExternalID = m[2]
+	default:
+		f.Email = m[2]
+	}
+	return f, nil
+}
+
+// Mapper translates between SCIM user resources and domain users.
+//
+// Attribute mapping:
+//   - userName: username, or email when the user has no username; a userName containing '@' is treated as email
+//   - emails (primary, or first): email
+//   - name.givenName / name.familyName: firstname / lastname
+//   - externalId: external identity provider ID
+//   - active: account active flag
+//   - extension birthdate: birthdate (required to enforce the 18+ rule)
+type Mapper struct {
+	BaseURL string // Prefix of resource locations, e.g. https://api.example.com/scim/v2
+}
+
+// ToResource converts a domain user into a SCIM user resource.
+func (m Mapper) ToResource(u *domain.User) User {
+	userName := u.Username
+	if userName == "" {
+		userName = u.Email
+	}
+	active := u.IsActive
+
+	return User{
+		Schemas:    []string{SchemaUser, SchemaUserExtension},
+		ID:         u.ID.String(),
+		ExternalID: u.ExternalID,
+		UserName:   userName,
+		Name:       &Name{GivenName: u.Firstname, FamilyName: u.Lastname},
+		Emails:     []Email{{Value: u.Email, Type: "work", Primary: true}},
+		Active:     &active,
+		Meta:       &Meta{ResourceType: "User", Location: m.BaseURL + "/Users/" + u.ID.String()},
+		Extension:  &UserExtension{Birthdate: u.Birthdate.Format(time.DateOnly)},
+	}
+}
+
+// Apply replaces the user's mapped attributes with values from the resource (SCIM PUT/POST semantics).
+// Returns ErrInvalidValue if required attributes are missing or malformed.
+func (m Mapper) Apply(res *User, u *domain.User) error {
+	if res.UserName == "" {
+		return fmt.Errorf("%w: userName is required", ErrInvalidValue)
+	}
+
+	email := primaryEmail(res.Emails)
+	username := res.UserName
+	if strings.Contains(res.UserName, "@") {
+		username = ""
+		if email == "" {
+			email = res.UserName
+		}
+	}
+	if email == "" {
+		return fmt.Errorf("%w: an email is required", ErrInvalidValue)
+	}
+
+	if res.Extension == nil || res.Extension.Birthdate == "" {
+		return fmt.Errorf("%w: %s:birthdate is required", ErrInvalidValue, SchemaUserExtension)
+	}
+	birthdate, err := time.Parse(time.DateOnly, res.Extension.Birthdate)
+	if err != nil {
+		return fmt.Errorf("%w: birthdate must have format YYYY-MM-DD", ErrInvalidValue)
+	}
+
+	u.Email = email
+	u.Username = username
+	u.ExternalID = res.ExternalID
+	u.Birthdate = birthdate
+	u.Firstname, u.Lastname = "", ""
+	if res.Name != nil {
+		u.Firstname, u.Lastname = res.Name.GivenName, res.Name.FamilyName
+	}
+	u.IsActive = res.Active == nil || *res.Active
+	return nil
+}
+
+// ApplyPatch applies SCIM PATCH operations to the user.
+// Supports "add" and "replace" of active, userName, externalId, name.givenName,
+// name.familyName and emails, both path-based and as a value object without path.
+func (m Mapper) ApplyPatch(ops []PatchOperation, u *domain.User) error {
+	for _, op := range ops {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+		default:
+			return fmt.Errorf("%w: operation %q is not supported", ErrInvalidPath, op.Op)
+		}
+
+		if op.Path != "" {
+			if err := applyPath(op.Path, op.Value, u); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Without path the value is an object of attribute -> value
+		var attrs map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attrs); err != nil {
+			return fmt.Errorf("%w: value must be an object when path is omitted", ErrInvalidValue)
+		}
+		for path, value := range attrs {
+			if err := applyPath(path, value, u); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyPath sets a single attribute identified by a SCIM path.
+func applyPath(path string, value json.RawMessage, u *domain.User) error {
+	switch path {
+	case "active":
+		active, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		u.IsActive = active
+	case "userName":
+		userName, err := parseString(value)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(userName, "@") {
+			u.Email, u.Username = userName, ""
+		} else {
+			u.Username = userName
+		}
+	case "externalId":
+		return unmarshalString(value, &u.ExternalID)
+	case "name.givenName":
+		return unmarshalString(value, &u.Firstname)
+	case "name.familyName":
+		return unmarshalString(value, &u.Lastname)
+	case "name":
+		var name Name
+		if err := json.Unmarshal(value, &name); err != nil {
+			return fmt.Errorf("%w: name", ErrInvalidValue)
+		}
+		u.Firstname, u.Lastname = name.GivenName, name.FamilyName
+	case "emails":
+		var emails []Email
+		if err := json.Unmarshal(value, &emails); err != nil || primaryEmail(emails) == "" {
+			return fmt.Errorf("%w: emails", ErrInvalidValue)
+		}
+		u.Email = primaryEmail(emails)
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidPath, path)
+	}
+	return nil
+}
+
+// primaryEmail returns the primary email, or the first one if none is marked primary.
+func primaryEmail(emails []Email) string {
+	for _, e := range emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(emails) > 0 {
+		return emails[0].Value
+	}
+	return ""
+}
+
+// parseBool parses a boolean that some identity providers (Azure AD) send as a string.
+func parseBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(strings.ToLower(s)); err == nil {
+			return b, nil
+		}
+	}
+	return false, fmt.Errorf("%w: active must be a boolean", ErrInvalidValue)
+}
+
+func parseString(value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", fmt.Errorf("%w: expected a string", ErrInvalidValue)
+	}
+	return s, nil
+}
+
+func unmarshalString(value json.RawMessage, dst *string) error {
+	s, err := parseString(value)
+	if err != nil {
+		return err
+	}
+	*dst = s
+	return nil
+}
@@ -0,0 +1,66 @@
+package scim_test
+
+import (
+	"encoding/json"
+	"product-api/internal/domain"
+	"product-api/internal/scim"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapper_Apply(t *testing.T) {
+	var res scim.User
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"externalId": "00u1",
+		"userName": "jane@example.com",
+		"name": {"givenName": "Jane", "familyName": "Doe"},
+		"emails": [{"value": "jane@example.com", "primary": true}],
+		"urn:productapi:params:scim:schemas:extension:2.0:User": {"birthdate": "1990-02-03"}
+	}`), &res))
+
+	var u domain.User
+	require.NoError(t, scim.Mapper{}.Apply(&res, &u))
+	assert.Equal(t, "jane@example.com", u.Email)
+	assert.Empty(t, u.Username)
+	assert.Equal(t, "00u1", u.ExternalID)
+	assert.Equal(t, "Jane", u.Firstname)
+	assert.True(t, u.IsActive)
+	assert.Equal(t, 1990, u.Birthdate.Year())
+
+	res.Extension = nil
+	assert.ErrorIs(t, scim.Mapper{}.Apply(&res, &u), scim.ErrInvalidValue)
+}
+
+func TestMapper_ApplyPatch(t *testing.T) {
+	u := domain.User{IsActive: true, Firstname: "Jane"}
+
+	// Okta style: value object without path
+	ops := []scim.PatchOperation{{Op: "replace", Value: json.RawMessage(`{"active": false, "name.givenName": "Janet"}`)}}
+	require.NoError(t, scim.Mapper{}.ApplyPatch(ops, &u))
+	assert.False(t, u.IsActive)
+	assert.Equal(t, "Janet", u.Firstname)
+
+	// Azure AD style: path with string boolean
+	ops = []scim.PatchOperation{{Op: "Replace", Path: "active", Value: json.RawMessage(`"True"`)}}
+	require.NoError(t, scim.Mapper{}.ApplyPatch(ops, &u))
+	assert.True(t, u.IsActive)
+
+	ops = []scim.PatchOperation{{Op: "remove", Path: "active"}}
+	assert.ErrorIs(t, scim.Mapper{}.ApplyPatch(ops, &u), scim.ErrInvalidPath)
+}
+
+func TestParseFilter(t *testing.T) {
+	f, err := scim.ParseFilter(`userName eq "john.doe"`)
+	require.NoError(t, err)
+	assert.Equal(t, "john.doe", f.UserName)
+
+	f, err = scim.ParseFilter(`emails.value eq "john@example.com"`)
+	require.NoError(t, err)
+	assert.Equal(t, "john@example.com", f.Email)
+
+	_, err = scim.ParseFilter(`userName sw "jo"`)
+	assert.ErrorIs(t, err, scim.ErrInvalidFilter)
+}
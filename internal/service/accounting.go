@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidJournalFormat is returned for journal export formats other than xero and quickbooks.
+var ErrInvalidJournalFormat = errors.New("journal export format must be xero or quickbooks")
+
+// Journal export formats.
+const (
+	JournalFormatXero       = "xero"       // Xero manual journal import
+	JournalFormatQuickBooks = "quickbooks" // QuickBooks Online journal entry import
+)
+
+// xeroJournalHeader lists columns of the Xero manual journal import, one row per entry.
+// Rows with the same narration and date form one journal; debits are positive amounts, credits negative.
+// Xero journals amounts in the base currency of the organisation, so Currency marks rows to convert before the import.
+var xeroJournalHeader = []string{"*Narration", "*Date", "Description", "*AccountCode", "*TaxRate", "*Amount", "Currency"}
+
+// quickBooksJournalHeader lists columns of the QuickBooks Online journal entry import, one row per entry.
+// Rows with the same journal number form one journal entry.
+var quickBooksJournalHeader = []string{"JournalNo", "JournalDate", "Currency", "Account", "Debits", "Credits", "Description"}
+
+// AccountingPolicy contains the rates and ledger accounts payments are journaled with.
+type AccountingPolicy struct {
+	Currency string                        // Currency of orders placed before settings were recorded
+	TaxRates map[string]float64            // Tax rates in percent included in prices, by tax region
+	Fees     map[string]domain.FeeSchedule // Fees of payment providers, by provider name
+	Accounts map[string]string             // Ledger account of each journal account, codes in Xero or names in QuickBooks
+}
+
+// AccountingService keeps a double-entry journal of the payment ledger for accounting software.
+// Payments are journaled by the batch runner on a cash basis: charges are revenue once received, refunds once made.
+// Provider fees are estimated from the fee schedules, as providers do not report them with captures.
+type AccountingService struct {
+	entries   repository.AccountingRepository
+	orders    repository.OrderRepository
+	policy    AccountingPolicy
+	batchSize int
+	logger    logger.Logger
+}
+
+// NewAccountingService creates a new accounting service journaling batchSize payments at a time.
+func NewAccountingService(entries repository.AccountingRepository, orders repository.OrderRepository, policy AccountingPolicy, batchSize int, logger logger.Logger) *AccountingService {
+	return &AccountingService{entries: entries, orders: orders, policy: policy, batchSize: batchSize, logger: logger}
+}
+
+// Run journals batches of new payments until ctx is done, checking for new ones every pollInterval while idle.
+// Each batch is recorded by hb.
+func (s *AccountingService) Run(ctx context.Context, pollInterval time.Duration, hb *Heartbeat) {
+	const op = "AccountingService.Run"
+
+	for {
+		journaled, err := s.JournalBatch(ctx)
+		hb.Beat(err)
+		if err != nil {
+			s.logger.Error("failed to journal payments", "op", op, "error", err)
+		}
+		if journaled == s.batchSize && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// JournalBatch journals the oldest payments without journal entries, up to the batch size,
+// and returns the number of journaled payments.
+func (s *AccountingService) JournalBatch(ctx context.Context) (int, error) {
+	const op = "AccountingService.JournalBatch"
+
+	payments, err := s.entries.FindUnjournaledPayments(ctx, s.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	orders := make(map[uuid.UUID]*domain.Order)
+	var entries []domain.AccountingEntry
+	for i := range payments {
+		p := &payments[i]
+		order, ok := orders[p.OrderID]
+		if !ok {
+			if order, err = s.orders.FindByID(ctx, p.OrderID); err != nil {
+				return 0, fmt.Errorf("%s: order %s: %w", op, p.OrderID, err)
+			}
+			if order.Currency == "" {
+				order.Currency = s.policy.Currency
+			}
+			orders[p.OrderID] = order
+		}
+
+		var fee float64
+		if p.Kind == domain.PaymentKindCharge {
+			fee = s.policy.Fees[p.Provider].Fee(p.Amount)
+		}
+		entries = append(entries, domain.JournalPayment(order, p, s.policy.TaxRates[order.TaxRegion], fee)...)
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	if err := s.entries.CreateEntries(ctx, entries); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return len(payments), nil
+}
+
+// ExportJournal writes the journal entries of payments made in [from, to) to w as CSV importable
+// by the accounting software of the format, xero or quickbooks. Dates are written as YYYY-MM-DD in UTC.
+// Returns ErrInvalidJournalFormat for unknown formats.
+func (s *AccountingService) ExportJournal(ctx context.Context, w io.Writer, format string, from, to time.Time) error {
+	const op = "AccountingService.ExportJournal"
+
+	var header []string
+	var row func(*domain.AccountingEntry) []string
+	switch format {
+	case JournalFormatXero:
+		header, row = xeroJournalHeader, s.xeroRow
+	case JournalFormatQuickBooks:
+		header, row = quickBooksJournalHeader, s.quickBooksRow
+	default:
+		return ErrInvalidJournalFormat
+	}
+
+	entries, err := s.entries.FindEntries(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write(header)
+	for i := range entries {
+		cw.Write(row(&entries[i]))
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("%s: encode journal: %w", op, err)
+	}
+	return nil
+}
+
+// xeroRow returns the entry as a row of xeroJournalHeader columns. The payment ID makes narrations unique,
+// so each payment is one journal. Tax is journaled to its own account, so lines carry no tax rate.
+func (s *AccountingService) xeroRow(e *domain.AccountingEntry) []string {
+	return []string{
+		e.Description + ", payment " + e.PaymentID.String(),
+		e.PostedAt.UTC().Format(time.DateOnly),
+		e.Description,
+		s.ledgerAccount(e.Account),
+		"Tax Exempt",
+		strconv.FormatFloat(domain.RoundCents(e.Debit-e.Credit), 'f', 2, 64),
+		e.Currency,
+	}
+}
+
+// quickBooksRow returns the entry as a row of quickBooksJournalHeader columns. Journal numbers are derived
+// from the payment ID, as QuickBooks limits them to 21 characters.
+func (s *AccountingService) quickBooksRow(e *domain.AccountingEntry) []string {
+	amount := func(v float64) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+	return []string{
+		strings.ToUpper(strings.ReplaceAll(e.PaymentID.String(), "-", "")[:16]),
+		e.PostedAt.UTC().Format(time.DateOnly),
+		e.Currency,
+		s.ledgerAccount(e.Account),
+		amount(e.Debit),
+		amount(e.Credit),
+		e.Description,
+	}
+}
+
+// ledgerAccount returns the ledger account of a journal account, the journal account itself if none is configured.
+func (s *AccountingService) ledgerAccount(account string) string {
+	if ledger, ok := s.policy.Accounts[account]; ok {
+		return ledger
+	}
+	return account
+}
@@ -0,0 +1,164 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"product-api/internal/repository/postgres"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"product-api/internal/testutil/testdb"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/suite"
+)
+
+type AccountingServiceTestSuite struct {
+	suite.Suite
+	dbpool      *pgxpool.Pool
+	entries     *postgres.AccountingRepository
+	orderRepo   repository.OrderRepository
+	productRepo repository.ProductRepository
+	userRepo    repository.UserRepository
+	service     *service.AccountingService
+}
+
+func (s *AccountingServiceTestSuite) SetupTest() {
+	s.dbpool = testdb.New(s.T())
+	s.entries = postgres.NewAccountingRepository(s.dbpool)
+	s.orderRepo = postgres.NewOrderRepository(s.dbpool)
+	s.productRepo = postgres.NewProductRepository(s.dbpool)
+	s.userRepo = postgres.NewUserRepository(s.dbpool)
+	s.service = service.NewAccountingService(s.entries, s.orderRepo, testAccountingPolicy, 2, discardLogger{})
+}
+
+// createOrder creates an order placed with the settings.
+func (s *AccountingServiceTestSuite) createOrder(settings domain.OrderSettings) *domain.Order {
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(119))
+	return factory.CreateOrder(s.T(), s.dbpool, s.orderRepo, user.ID, factory.WithItem(product, 1), factory.WithSettings(settings))
+}
+
+// createPayment records a card charge of the order made at createdAt.
+func (s *AccountingServiceTestSuite) createPayment(order *domain.Order, amount float64, createdAt time.Time) *domain.Payment {
+	ctx := context.Background()
+	payment := &domain.Payment{
+		ID:        uuid.New(),
+		OrderID:   order.ID,
+		Kind:      domain.PaymentKindCharge,
+		Method:    domain.PaymentMethodCard,
+		Amount:    amount,
+		Provider:  "stripe",
+		CreatedAt: createdAt,
+	}
+	tx, err := s.dbpool.Begin(ctx)
+	s.Require().NoError(err)
+	defer func() { _ = tx.Rollback(ctx) }()
+	s.Require().NoError(postgres.NewPaymentRepository(s.dbpool).CreateTx(ctx, tx, payment))
+	s.Require().NoError(tx.Commit(ctx))
+	return payment
+}
+
+func (s *AccountingServiceTestSuite) TestFindUnjournaledPayments() {
+	ctx := context.Background()
+	day := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	order := s.createOrder(domain.OrderSettings{Currency: "EUR", TaxRegion: "DE"})
+	second := s.createPayment(order, 20, day.Add(time.Hour))
+	first := s.createPayment(order, 119, day)
+	sandbox := s.createOrder(domain.OrderSettings{Currency: "EUR", TaxRegion: "DE"})
+	_, err := s.dbpool.Exec(ctx, `UPDATE orders SET sandbox = TRUE WHERE id = $1`, sandbox.ID)
+	s.Require().NoError(err)
+	s.createPayment(sandbox, 119, day)
+
+	payments, err := s.entries.FindUnjournaledPayments(ctx, 10)
+	s.Require().NoError(err)
+	s.Equal([]uuid.UUID{first.ID, second.ID}, paymentIDs(payments), "oldest first, without sandbox payments")
+
+	payments, err = s.entries.FindUnjournaledPayments(ctx, 1)
+	s.Require().NoError(err)
+	s.Equal([]uuid.UUID{first.ID}, paymentIDs(payments))
+
+	s.Require().NoError(s.entries.CreateEntries(ctx, domain.JournalPayment(order, first, 19, 0)))
+	payments, err = s.entries.FindUnjournaledPayments(ctx, 10)
+	s.Require().NoError(err)
+	s.Equal([]uuid.UUID{second.ID}, paymentIDs(payments), "journaled payments are skipped")
+}
+
+func (s *AccountingServiceTestSuite) TestCreateEntriesIsIdempotent() {
+	ctx := context.Background()
+	day := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	order := s.createOrder(domain.OrderSettings{Currency: "EUR", TaxRegion: "DE"})
+	payment := s.createPayment(order, 119, day)
+
+	s.Require().NoError(s.entries.CreateEntries(ctx, domain.JournalPayment(order, payment, 19, 2.04)))
+	// A batch journaled twice, e.g. by two regions, gets new entry IDs but the same payment lines
+	s.Require().NoError(s.entries.CreateEntries(ctx, domain.JournalPayment(order, payment, 19, 2.04)))
+
+	entries, err := s.entries.FindEntries(ctx, day, day.AddDate(0, 0, 1))
+	s.Require().NoError(err)
+	s.Require().Len(entries, 4)
+	var debits, credits float64
+	for i, e := range entries {
+		s.Equal(i+1, e.Line)
+		s.Equal("EUR", e.Currency)
+		s.True(e.PostedAt.Equal(day))
+		debits += e.Debit
+		credits += e.Credit
+	}
+	s.InDelta(debits, credits, 0.001, "the journal is balanced")
+
+	entries, err = s.entries.FindEntries(ctx, day.AddDate(0, 0, 1), day.AddDate(0, 0, 2))
+	s.Require().NoError(err)
+	s.Empty(entries, "entries are found by the day they were posted")
+}
+
+func (s *AccountingServiceTestSuite) TestJournalBatchAndExport() {
+	ctx := context.Background()
+	day := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	euro := s.createOrder(domain.OrderSettings{Currency: "EUR", TaxRegion: "DE"})
+	dollar := s.createOrder(domain.OrderSettings{Currency: "USD"})
+	s.createPayment(euro, 119, day)
+	s.createPayment(euro, 20, day.Add(time.Minute))
+	s.createPayment(dollar, 50, day.Add(2*time.Minute))
+
+	// Batches of two journal the oldest payments first
+	for _, want := range []int{2, 1, 0} {
+		journaled, err := s.service.JournalBatch(ctx)
+		s.Require().NoError(err)
+		s.Equal(want, journaled)
+	}
+
+	var out bytes.Buffer
+	s.Require().NoError(s.service.ExportJournal(ctx, &out, service.JournalFormatXero, day, day.AddDate(0, 0, 1)))
+	rows, err := csv.NewReader(&out).ReadAll()
+	s.Require().NoError(err)
+	s.Require().Len(rows, 1+4+4+3, "header, card charges with fees, and the dollar charge without tax")
+	currencies := make(map[string]float64)
+	for _, row := range rows[1:] {
+		s.Len(row, len(rows[0]))
+		amount, err := strconv.ParseFloat(row[5], 64)
+		s.Require().NoError(err)
+		currencies[row[6]] += amount
+	}
+	s.Len(currencies, 2, "rows carry the currency of their order")
+	s.InDelta(0, currencies["EUR"], 0.001, "each currency balances on its own")
+	s.InDelta(0, currencies["USD"], 0.001)
+}
+
+func TestAccountingServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountingServiceTestSuite))
+}
+
+func paymentIDs(payments []domain.Payment) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(payments))
+	for _, p := range payments {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
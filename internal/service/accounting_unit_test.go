@@ -0,0 +1,108 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testAccountingPolicy = service.AccountingPolicy{
+	Currency: "USD",
+	TaxRates: map[string]float64{"DE": 19},
+	Fees:     map[string]domain.FeeSchedule{"stripe": {Percent: 1.5, Fixed: 0.25}},
+	Accounts: map[string]string{domain.AccountPaymentClearing: "610", domain.AccountRevenue: "200", domain.AccountSalesTax: "820"},
+}
+
+func TestAccountingService_Unit_JournalBatch(t *testing.T) {
+	entries := mocks.NewMockAccountingRepository(t)
+	orders := mocks.NewMockOrderRepository(t)
+	svc := service.NewAccountingService(entries, orders, testAccountingPolicy, 10, discardLogger{})
+	ctx := context.Background()
+
+	german := &domain.Order{ID: uuid.New(), Number: "ORD-2024-000001", OrderSettings: domain.OrderSettings{Currency: "EUR", TaxRegion: "DE"}}
+	legacy := &domain.Order{ID: uuid.New(), Number: "ORD-2023-000001"} // Placed before settings were recorded
+	card := domain.Payment{ID: uuid.New(), OrderID: german.ID, Kind: domain.PaymentKindCharge, Method: domain.PaymentMethodCard, Amount: 119, Provider: "stripe"}
+	refund := domain.Payment{ID: uuid.New(), OrderID: german.ID, Kind: domain.PaymentKindRefund, Method: domain.PaymentMethodCard, Amount: 11.9, RefundOf: &card.ID, Provider: "stripe"}
+	cash := domain.Payment{ID: uuid.New(), OrderID: legacy.ID, Kind: domain.PaymentKindCharge, Method: domain.PaymentMethodCash, Amount: 20}
+
+	entries.EXPECT().FindUnjournaledPayments(mock.Anything, 10).Return([]domain.Payment{card, refund, cash}, nil)
+	orders.EXPECT().FindByID(mock.Anything, german.ID).Return(german, nil).Once() // Orders are loaded once per batch
+	orders.EXPECT().FindByID(mock.Anything, legacy.ID).Return(legacy, nil).Once()
+	var stored []domain.AccountingEntry
+	entries.EXPECT().CreateEntries(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, e []domain.AccountingEntry) error {
+		stored = e
+		return nil
+	})
+
+	journaled, err := svc.JournalBatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, journaled)
+
+	byPayment := make(map[uuid.UUID][]domain.AccountingEntry)
+	for _, e := range stored {
+		byPayment[e.PaymentID] = append(byPayment[e.PaymentID], e)
+	}
+	assert.Equal(t, 2.04, byPayment[card.ID][1].Debit, "stripe fee of the charge")
+	assert.Equal(t, domain.AccountPaymentFees, byPayment[card.ID][1].Account)
+	assert.Equal(t, 19.0, byPayment[card.ID][3].Credit, "tax of the German tax region")
+	assert.Len(t, byPayment[refund.ID], 3, "refunds return no fee")
+	assert.Equal(t, 1.9, byPayment[refund.ID][1].Debit)
+	require.Len(t, byPayment[cash.ID], 2, "no fee or tax")
+	assert.Equal(t, "USD", byPayment[cash.ID][0].Currency, "configured currency of orders without settings")
+}
+
+func TestAccountingService_Unit_JournalBatchNothingNew(t *testing.T) {
+	entries := mocks.NewMockAccountingRepository(t)
+	svc := service.NewAccountingService(entries, mocks.NewMockOrderRepository(t), testAccountingPolicy, 10, discardLogger{})
+
+	entries.EXPECT().FindUnjournaledPayments(mock.Anything, 10).Return(nil, nil)
+
+	journaled, err := svc.JournalBatch(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, journaled) // No entries are stored
+}
+
+func TestAccountingService_Unit_ExportJournal(t *testing.T) {
+	entries := mocks.NewMockAccountingRepository(t)
+	svc := service.NewAccountingService(entries, mocks.NewMockOrderRepository(t), testAccountingPolicy, 10, discardLogger{})
+	ctx := context.Background()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	order := &domain.Order{ID: uuid.New(), Number: "ORD-2024-000001", OrderSettings: domain.OrderSettings{Currency: "EUR", TaxRegion: "DE"}}
+	cash := domain.Payment{
+		ID:        uuid.MustParse("0b6a3f1e-93c4-4d7e-9f1a-2c5b8e7d6a40"),
+		Kind:      domain.PaymentKindCharge,
+		Method:    domain.PaymentMethodCash,
+		Amount:    119,
+		CreatedAt: time.Date(2024, 3, 15, 23, 30, 0, 0, time.FixedZone("CET", 3600)),
+	}
+	entries.EXPECT().FindEntries(mock.Anything, from, to).Return(domain.JournalPayment(order, &cash, 19, 0), nil)
+
+	var xero bytes.Buffer
+	require.NoError(t, svc.ExportJournal(ctx, &xero, service.JournalFormatXero, from, to))
+	assert.Equal(t, `*Narration,*Date,Description,*AccountCode,*TaxRate,*Amount,Currency
+"Order ORD-2024-000001 charge (cash), payment 0b6a3f1e-93c4-4d7e-9f1a-2c5b8e7d6a40",2024-03-15,Order ORD-2024-000001 charge (cash),610,Tax Exempt,119.00,EUR
+"Order ORD-2024-000001 charge (cash), payment 0b6a3f1e-93c4-4d7e-9f1a-2c5b8e7d6a40",2024-03-15,Order ORD-2024-000001 charge (cash),200,Tax Exempt,-100.00,EUR
+"Order ORD-2024-000001 charge (cash), payment 0b6a3f1e-93c4-4d7e-9f1a-2c5b8e7d6a40",2024-03-15,Order ORD-2024-000001 charge (cash),820,Tax Exempt,-19.00,EUR
+`, xero.String())
+
+	var quickBooks bytes.Buffer
+	require.NoError(t, svc.ExportJournal(ctx, &quickBooks, service.JournalFormatQuickBooks, from, to))
+	assert.Equal(t, `JournalNo,JournalDate,Currency,Account,Debits,Credits,Description
+0B6A3F1E93C44D7E,2024-03-15,EUR,610,119.00,,Order ORD-2024-000001 charge (cash)
+0B6A3F1E93C44D7E,2024-03-15,EUR,200,,100.00,Order ORD-2024-000001 charge (cash)
+0B6A3F1E93C44D7E,2024-03-15,EUR,820,,19.00,Order ORD-2024-000001 charge (cash)
+`, quickBooks.String())
+
+	assert.ErrorIs(t, svc.ExportJournal(ctx, &bytes.Buffer{}, "sage", from, to), service.ErrInvalidJournalFormat)
+}
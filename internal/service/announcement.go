@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/notification"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrAnnouncementNotFound is returned when an announcement is not found.
+var ErrAnnouncementNotFound = errors.New("announcement not found")
+
+// AnnouncementInput contains the template variables of an announcement and the segment of users it is sent to.
+type AnnouncementInput struct {
+	Data    map[string]string // Variables of the announcement template, e.g. Headline and Message
+	Segment domain.AudienceSegment
+}
+
+// AnnouncementPage is a page of announcements.
+type AnnouncementPage struct {
+	Announcements []domain.Announcement // Newest first
+	Total         int                   // Number of announcements
+}
+
+// AnnouncementService sends templated marketing announcements to segments of users.
+// Announcements are queued by admins and sent by the batch runner of any instance, one batch of users at a time,
+// so large segments neither block requests nor flood the delivery providers. Users who opted out of marketing
+// notifications on every channel are skipped; the notifier skips the channels users opted out of.
+type AnnouncementService struct {
+	announcements repository.AnnouncementRepository
+	users         repository.UserRepository
+	prefs         repository.PreferenceRepository
+	notifier      notification.Notifier
+	batchSize     int
+	lease         time.Duration
+	logger        logger.Logger
+}
+
+// NewAnnouncementService creates a new announcement service sending batchSize users at a time.
+// An instance sending a batch holds the announcement for lease, after which another instance resumes it.
+func NewAnnouncementService(announcements repository.AnnouncementRepository, users repository.UserRepository, prefs repository.PreferenceRepository, notifier notification.Notifier, batchSize int, lease time.Duration, logger logger.Logger) *AnnouncementService {
+	return &AnnouncementService{
+		announcements: announcements,
+		users:         users,
+		prefs:         prefs,
+		notifier:      notifier,
+		batchSize:     batchSize,
+		lease:         lease,
+		logger:        logger,
+	}
+}
+
+// Create queues an announcement for the batch runner.
+// Returns domain.ErrInvalidSegment, or domain.ErrInvalidTemplate if the data does not set exactly the variables of the template.
+func (s *AnnouncementService) Create(ctx context.Context, in AnnouncementInput) (*domain.Announcement, error) {
+	if err := in.Segment.Validate(); err != nil {
+		return nil, err
+	}
+	if err := notification.CheckTemplateData(notification.TemplateAnnouncement, in.Data); err != nil {
+		return nil, err
+	}
+
+	announcement := &domain.Announcement{
+		ID:        uuid.New(),
+		Template:  notification.TemplateAnnouncement,
+		Data:      in.Data,
+		Segment:   in.Segment,
+		Status:    domain.AnnouncementQueued,
+		CreatedAt: time.Now(),
+	}
+	if err := s.announcements.Create(ctx, announcement); err != nil {
+		return nil, fmt.Errorf("AnnouncementService.Create: %w", err)
+	}
+	return announcement, nil
+}
+
+// Get returns the announcement with its delivery stats. Returns ErrAnnouncementNotFound.
+func (s *AnnouncementService) Get(ctx context.Context, id uuid.UUID) (*domain.Announcement, error) {
+	announcement, err := s.announcements.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrAnnouncementNotFound) {
+			return nil, ErrAnnouncementNotFound
+		}
+		return nil, fmt.Errorf("AnnouncementService.Get: %w", err)
+	}
+	return announcement, nil
+}
+
+// List returns a page of announcements, newest first.
+func (s *AnnouncementService) List(ctx context.Context, offset, limit int) (*AnnouncementPage, error) {
+	announcements, total, err := s.announcements.List(ctx, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("AnnouncementService.List: %w", err)
+	}
+	if announcements == nil {
+		announcements = []domain.Announcement{}
+	}
+	return &AnnouncementPage{Announcements: announcements, Total: total}, nil
+}
+
+// Run sends batches of pending announcements until ctx is done, checking for new ones every pollInterval while idle.
+// Each batch is recorded by hb.
+func (s *AnnouncementService) Run(ctx context.Context, pollInterval time.Duration, hb *Heartbeat) {
+	const op = "AnnouncementService.Run"
+
+	for {
+		sent, err := s.SendBatch(ctx)
+		hb.Beat(err)
+		if err != nil {
+			s.logger.Error("failed to send announcement batch", "op", op, "error", err)
+		}
+		if sent && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// SendBatch sends the oldest pending announcement to its next batch of users and stores its progress.
+// The announcement is completed once a batch is shorter than the batch size. If ctx is cancelled
+// mid-batch, the progress so far is stored and the rest of the batch is sent later. If the batch outlasted its
+// lease and another instance claimed the announcement, the progress is not stored and an error is returned.
+// Returns false if no announcement is pending.
+func (s *AnnouncementService) SendBatch(ctx context.Context) (bool, error) {
+	const op = "AnnouncementService.SendBatch"
+
+	announcement, err := s.announcements.Claim(ctx, s.lease)
+	if err != nil {
+		if errors.Is(err, repository.ErrAnnouncementNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	users, err := s.users.FindBySegment(ctx, announcement.Segment, announcement.Cursor, s.batchSize)
+	if err != nil {
+		return true, fmt.Errorf("%s: announcement %s: %w", op, announcement.ID, err)
+	}
+
+	msg := notification.Message{
+		Category: domain.NotificationCategoryMarketing,
+		Template: announcement.Template,
+		Data:     announcement.Data,
+	}
+	announcement.Status = domain.AnnouncementSending
+	complete := len(users) < s.batchSize
+	for i := range users {
+		if ctx.Err() != nil {
+			complete = false
+			break
+		}
+		user := &users[i]
+		s.send(ctx, announcement, user.ID, msg)
+		announcement.Cursor = user.ID
+		announcement.Stats.Recipients++
+	}
+	if complete {
+		now := time.Now()
+		announcement.Status = domain.AnnouncementCompleted
+		announcement.CompletedAt = &now
+	}
+
+	// Progress must be stored even if the batch was interrupted, so its users are not sent the announcement twice
+	if err := s.announcements.SaveProgress(context.WithoutCancel(ctx), announcement); err != nil {
+		if errors.Is(err, repository.ErrAnnouncementLeaseLost) {
+			// Another instance resumed the announcement from the stored cursor and sends the batch again
+			return true, fmt.Errorf("%s: announcement %s: batch outlasted the lease of %s, its users may receive it twice: %w",
+				op, announcement.ID, s.lease, err)
+		}
+		return true, fmt.Errorf("%s: announcement %s: %w", op, announcement.ID, err)
+	}
+	if complete {
+		s.logger.Info("announcement sent", "op", op, "announcement_id", announcement.ID, "recipients", announcement.Stats.Recipients,
+			"sent", announcement.Stats.Sent, "opted_out", announcement.Stats.OptedOut, "failed", announcement.Stats.Failed)
+	}
+	return true, nil
+}
+
+// send delivers the announcement to the user unless the user opted out of marketing notifications on every channel,
+// and counts the outcome in the announcement's stats.
+func (s *AnnouncementService) send(ctx context.Context, announcement *domain.Announcement, userID uuid.UUID, msg notification.Message) {
+	const op = "AnnouncementService.send"
+	log := s.logger.WithTrace(ctx)
+
+	stored, err := s.prefs.FindByUserID(ctx, userID)
+	if err != nil {
+		log.Error("failed to load notification preferences", "op", op, "announcement_id", announcement.ID, "user_id", userID, "error", err)
+		announcement.Stats.Failed++
+		return
+	}
+	prefs := domain.DefaultNotificationPreferences().Merge(stored)
+	optedIn := false
+	for _, channel := range domain.NotificationChannels {
+		optedIn = optedIn || prefs.Allows(domain.NotificationCategoryMarketing, channel)
+	}
+	if !optedIn {
+		announcement.Stats.OptedOut++
+		return
+	}
+
+	if err := s.notifier.Notify(ctx, userID, msg); err != nil {
+		log.Warn("failed to deliver announcement", "op", op, "announcement_id", announcement.ID, "user_id", userID, "error", err)
+		announcement.Stats.Failed++
+		return
+	}
+	announcement.Stats.Sent++
+}
@@ -0,0 +1,79 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/notification"
+	"product-api/internal/repository"
+	"product-api/internal/repository/postgres"
+	"product-api/internal/testutil/testdb"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/suite"
+)
+
+// AnnouncementLeaseTestSuite covers the leases of announcements sent by several instances.
+type AnnouncementLeaseTestSuite struct {
+	suite.Suite
+	dbpool *pgxpool.Pool
+	repo   repository.AnnouncementRepository
+}
+
+func (s *AnnouncementLeaseTestSuite) SetupTest() {
+	s.dbpool = testdb.New(s.T())
+	s.repo = postgres.NewAnnouncementRepository(s.dbpool)
+}
+
+func (s *AnnouncementLeaseTestSuite) TestSaveProgressRequiresTheClaimedLease() {
+	ctx := context.Background()
+	announcement := &domain.Announcement{
+		ID:        uuid.New(),
+		Template:  notification.TemplateAnnouncement,
+		Data:      map[string]string{"Headline": "Summer sale", "Message": "20% off"},
+		Status:    domain.AnnouncementQueued,
+		CreatedAt: time.Now(),
+	}
+	s.Require().NoError(s.repo.Create(ctx, announcement))
+
+	slow, err := s.repo.Claim(ctx, time.Minute)
+	s.Require().NoError(err)
+	s.Require().NotNil(slow.LockedUntil)
+	_, err = s.repo.Claim(ctx, time.Minute)
+	s.ErrorIs(err, repository.ErrAnnouncementNotFound, "leased announcements are not claimed")
+
+	// The batch outlasts the lease, another instance resumes the announcement
+	_, err = s.dbpool.Exec(ctx, `UPDATE announcements SET locked_until = NOW() - interval '1 second' WHERE id = $1`, announcement.ID)
+	s.Require().NoError(err)
+	resumed, err := s.repo.Claim(ctx, time.Minute)
+	s.Require().NoError(err)
+	s.NotEqual(*slow.LockedUntil, *resumed.LockedUntil)
+
+	slow.Status, slow.Cursor, slow.Stats.Sent = domain.AnnouncementSending, uuid.New(), 10
+	s.ErrorIs(s.repo.SaveProgress(ctx, slow), repository.ErrAnnouncementLeaseLost)
+
+	stored, err := s.repo.FindByID(ctx, announcement.ID)
+	s.Require().NoError(err)
+	s.Equal(uuid.Nil, stored.Cursor)
+	s.Zero(stored.Stats.Sent)
+
+	resumed.Status, resumed.Cursor, resumed.Stats.Sent = domain.AnnouncementSending, uuid.New(), 5
+	s.Require().NoError(s.repo.SaveProgress(ctx, resumed))
+
+	stored, err = s.repo.FindByID(ctx, announcement.ID)
+	s.Require().NoError(err)
+	s.Equal(resumed.Cursor, stored.Cursor)
+	s.Equal(5, stored.Stats.Sent)
+	s.Nil(stored.LockedUntil, "the lease is released")
+
+	// The released lease cannot be used again
+	s.ErrorIs(s.repo.SaveProgress(ctx, stored), repository.ErrAnnouncementLeaseLost)
+	_, err = s.repo.Claim(ctx, time.Minute)
+	s.NoError(err)
+}
+
+func TestAnnouncementLeaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AnnouncementLeaseTestSuite))
+}
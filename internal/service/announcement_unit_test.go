@@ -0,0 +1,124 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/notification"
+	notificationmocks "product-api/internal/notification/mocks"
+	"product-api/internal/repository"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type announcementMocks struct {
+	announcements *mocks.MockAnnouncementRepository
+	users         *mocks.MockUserRepository
+	prefs         *mocks.MockPreferenceRepository
+	notifier      *notificationmocks.MockNotifier
+}
+
+func newAnnouncementServiceWithMocks(t *testing.T, batchSize int) (*service.AnnouncementService, *announcementMocks) {
+	m := &announcementMocks{
+		announcements: mocks.NewMockAnnouncementRepository(t),
+		users:         mocks.NewMockUserRepository(t),
+		prefs:         mocks.NewMockPreferenceRepository(t),
+		notifier:      notificationmocks.NewMockNotifier(t),
+	}
+	svc := service.NewAnnouncementService(m.announcements, m.users, m.prefs, m.notifier, batchSize, time.Minute, discardLogger{})
+	return svc, m
+}
+
+func TestAnnouncementService_Unit_Create(t *testing.T) {
+	svc, m := newAnnouncementServiceWithMocks(t, 10)
+	ctx := context.Background()
+	data := map[string]string{"Headline": "Summer sale", "Message": "20% off"}
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := after.AddDate(-1, 0, 0)
+
+	_, err := svc.Create(ctx, service.AnnouncementInput{Data: map[string]string{"Headline": "Summer sale"}})
+	assert.ErrorIs(t, err, domain.ErrInvalidTemplate)
+	_, err = svc.Create(ctx, service.AnnouncementInput{Data: data, Segment: domain.AudienceSegment{RegisteredAfter: &after, RegisteredBefore: &before}})
+	assert.ErrorIs(t, err, domain.ErrInvalidSegment)
+
+	segment := domain.AudienceSegment{RegisteredAfter: &after, Roles: []string{domain.RoleCustomer}}
+	m.announcements.EXPECT().Create(mock.Anything, mock.MatchedBy(func(a *domain.Announcement) bool {
+		return a.Status == domain.AnnouncementQueued && a.Template == notification.TemplateAnnouncement && a.Segment.Roles[0] == domain.RoleCustomer
+	})).Return(nil)
+
+	announcement, err := svc.Create(ctx, service.AnnouncementInput{Data: data, Segment: segment})
+
+	require.NoError(t, err)
+	assert.Equal(t, data, announcement.Data)
+}
+
+func TestAnnouncementService_Unit_SendBatch(t *testing.T) {
+	svc, m := newAnnouncementServiceWithMocks(t, 3)
+	ctx := context.Background()
+	optedIn, optedOut, failing := uuid.New(), uuid.New(), uuid.New()
+	announcement := &domain.Announcement{
+		ID: uuid.New(), Template: notification.TemplateAnnouncement, Status: domain.AnnouncementQueued,
+		Data: map[string]string{"Headline": "Summer sale", "Message": "20% off"},
+	}
+	marketingByEmail := domain.NotificationPreferences{domain.NotificationCategoryMarketing: {domain.NotificationChannelEmail: true}}
+
+	m.announcements.EXPECT().Claim(mock.Anything, time.Minute).Return(announcement, nil).Once()
+	m.users.EXPECT().FindBySegment(mock.Anything, announcement.Segment, uuid.Nil, 3).Return([]domain.User{{ID: optedIn}, {ID: optedOut}, {ID: failing}}, nil)
+	m.prefs.EXPECT().FindByUserID(mock.Anything, optedIn).Return(marketingByEmail, nil)
+	m.prefs.EXPECT().FindByUserID(mock.Anything, optedOut).Return(nil, nil)
+	m.prefs.EXPECT().FindByUserID(mock.Anything, failing).Return(marketingByEmail, nil)
+	m.notifier.EXPECT().Notify(mock.Anything, optedIn, mock.MatchedBy(func(msg notification.Message) bool {
+		return msg.Category == domain.NotificationCategoryMarketing && msg.Template == notification.TemplateAnnouncement && msg.Data["Headline"] == "Summer sale"
+	})).Return(nil)
+	m.notifier.EXPECT().Notify(mock.Anything, failing, mock.Anything).Return(errors.New("smtp unavailable"))
+	m.announcements.EXPECT().SaveProgress(mock.Anything, mock.MatchedBy(func(a *domain.Announcement) bool {
+		return a.Status == domain.AnnouncementSending && a.Cursor == failing && a.CompletedAt == nil
+	})).Return(nil).Once()
+
+	sent, err := svc.SendBatch(ctx)
+
+	require.NoError(t, err)
+	assert.True(t, sent)
+	assert.Equal(t, domain.AnnouncementStats{Recipients: 3, Sent: 1, OptedOut: 1, Failed: 1}, announcement.Stats)
+
+	// The next batch is past the last user of the segment
+	m.announcements.EXPECT().Claim(mock.Anything, time.Minute).Return(announcement, nil).Once()
+	m.users.EXPECT().FindBySegment(mock.Anything, announcement.Segment, failing, 3).Return(nil, nil)
+	m.announcements.EXPECT().SaveProgress(mock.Anything, mock.MatchedBy(func(a *domain.Announcement) bool {
+		return a.Status == domain.AnnouncementCompleted && a.CompletedAt != nil
+	})).Return(nil).Once()
+
+	sent, err = svc.SendBatch(ctx)
+	require.NoError(t, err)
+	assert.True(t, sent)
+
+	m.announcements.EXPECT().Claim(mock.Anything, time.Minute).Return(nil, repository.ErrAnnouncementNotFound).Once()
+	sent, err = svc.SendBatch(ctx)
+	require.NoError(t, err)
+	assert.False(t, sent, "no announcement is pending")
+}
+
+func TestAnnouncementService_Unit_SendBatchAfterLeaseLost(t *testing.T) {
+	svc, m := newAnnouncementServiceWithMocks(t, 3)
+	lockedUntil := time.Now().Add(time.Minute)
+	announcement := &domain.Announcement{
+		ID: uuid.New(), Template: notification.TemplateAnnouncement, Status: domain.AnnouncementSending,
+		Data: map[string]string{"Headline": "Summer sale", "Message": "20% off"}, LockedUntil: &lockedUntil,
+	}
+
+	m.announcements.EXPECT().Claim(mock.Anything, time.Minute).Return(announcement, nil)
+	m.users.EXPECT().FindBySegment(mock.Anything, announcement.Segment, uuid.Nil, 3).Return(nil, nil)
+	m.announcements.EXPECT().SaveProgress(mock.Anything, announcement).Return(repository.ErrAnnouncementLeaseLost)
+
+	sent, err := svc.SendBatch(context.Background())
+
+	require.ErrorIs(t, err, repository.ErrAnnouncementLeaseLost)
+	assert.True(t, sent)
+}
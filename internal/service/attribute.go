@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+)
+
+var (
+	// ErrAttributeNotFound is returned when the category has no attribute with the name.
+	ErrAttributeNotFound = errors.New("attribute definition not found")
+)
+
+// AttributeService manages typed attribute definitions of product categories.
+// Definitions apply to products changed after they are saved; existing products are not revalidated.
+type AttributeService struct {
+	repo repository.AttributeRepository
+}
+
+// NewAttributeService creates a new attribute service.
+func NewAttributeService(repo repository.AttributeRepository) *AttributeService {
+	return &AttributeService{repo: repo}
+}
+
+// List returns attribute definitions of the category ordered by name.
+func (s *AttributeService) List(ctx context.Context, category string) ([]domain.AttributeDefinition, error) {
+	definitions, err := s.repo.FindByCategory(ctx, category)
+	if err != nil {
+		return nil, fmt.Errorf("AttributeService.List: %w", err)
+	}
+	return definitions, nil
+}
+
+// Define creates or replaces an attribute definition.
+// Returns domain.ErrInvalidAttributeDefinition if the definition is inconsistent.
+func (s *AttributeService) Define(ctx context.Context, definition *domain.AttributeDefinition) error {
+	if err := definition.Validate(); err != nil {
+		return err
+	}
+	if err := s.repo.Save(ctx, definition); err != nil {
+		return fmt.Errorf("AttributeService.Define: %w", err)
+	}
+	return nil
+}
+
+// Delete removes an attribute definition. Products keep their values until they are changed.
+func (s *AttributeService) Delete(ctx context.Context, category, name string) error {
+	if err := s.repo.Delete(ctx, category, name); err != nil {
+		if errors.Is(err, repository.ErrAttributeNotFound) {
+			return ErrAttributeNotFound
+		}
+		return fmt.Errorf("AttributeService.Delete: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/event"
+	"product-api/internal/logger"
+	"product-api/internal/money"
+	"product-api/internal/notification"
+	"product-api/internal/payment"
+	"product-api/internal/repository/postgres"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"product-api/internal/testutil/testdb"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// discardLogger drops log output so it does not distort benchmark results.
+type discardLogger struct{}
+
+func (discardLogger) Info(string, ...any)                       {}
+func (discardLogger) Warn(string, ...any)                       {}
+func (discardLogger) Error(string, ...any)                      {}
+func (discardLogger) Debug(string, ...any)                      {}
+func (l discardLogger) WithTrace(context.Context) logger.Logger { return l }
+
+// usdFormatter formats amounts in US dollars for en-US.
+func usdFormatter() *money.Formatter {
+	f, err := money.NewFormatter("USD", "en-US")
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// defaultCheckoutPipeline returns the pipeline of the default checkout validators without quantity limits.
+func defaultCheckoutPipeline() *service.CheckoutPipeline {
+	p, err := service.NewCheckoutPipelineOf(service.DefaultCheckoutValidators, service.QuantityLimits{})
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// orderOptionFees returns the fees of all order options: gift wrap 4.99, a free gift message and priority handling 9.99.
+func orderOptionFees() service.OrderOptionFees {
+	return service.OrderOptionFees{
+		domain.OrderOptionGiftWrap:         4.99,
+		domain.OrderOptionGiftMessage:      0,
+		domain.OrderOptionPriorityHandling: 9.99,
+	}
+}
+
+func BenchmarkCreateOrder(b *testing.B) {
+	dbpool := testdb.New(b)
+	userRepo := postgres.NewUserRepository(dbpool)
+	productRepo := postgres.NewProductRepository(dbpool)
+	notifier := notification.NewDispatcher(userRepo, postgres.NewPreferenceRepository(dbpool), notification.NewTemplates(postgres.NewNotificationTemplateRepository(dbpool), time.Minute, discardLogger{}), discardLogger{},
+		notification.NewLogSender(domain.NotificationChannelEmail, discardLogger{}))
+	orderService := service.NewOrderService(service.OrderServiceDeps{
+		DB:          dbpool,
+		OrderRepo:   postgres.NewOrderRepository(dbpool),
+		EventRepo:   postgres.NewOrderEventRepository(dbpool),
+		Numbers:     postgres.NewOrderNumberRepository(dbpool),
+		PaymentRepo: postgres.NewPaymentRepository(dbpool),
+		RefundRepo:  postgres.NewRefundRequestRepository(dbpool),
+		DisputeRepo: postgres.NewDisputeRepository(dbpool),
+		ProductRepo: productRepo,
+		StockRepo:   postgres.NewStockRepository(dbpool),
+		SlotRepo:    postgres.NewDeliverySlotRepository(dbpool),
+		UserRepo:    userRepo,
+		Notifier:    notifier,
+		Providers:   payment.NewRegistry(payment.NewMemoryProvider("", discardLogger{})),
+		Formatter:   usdFormatter(),
+		Checks:      defaultCheckoutPipeline(),
+		OptionFees:  orderOptionFees(),
+		Events:      event.NewBus(discardLogger{}),
+		Logger:      discardLogger{},
+	})
+
+	user := factory.CreateUser(b, userRepo)
+
+	for _, itemCount := range []int{1, 10, 50} {
+		b.Run(fmt.Sprintf("items=%d", itemCount), func(b *testing.B) {
+			items := make([]service.OrderItemInput, itemCount)
+			for i := range items {
+				product := factory.CreateProduct(b, productRepo, factory.WithQuantity(1_000_000_000))
+				items[i] = service.OrderItemInput{ProductID: product.ID, Quantity: 1}
+			}
+
+			ctx := context.Background()
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := orderService.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFindProducts(b *testing.B) {
+	dbpool := testdb.New(b)
+	productRepo := postgres.NewProductRepository(dbpool)
+	productService := service.NewProductService(dbpool, productRepo, postgres.NewProductRevisionRepository(dbpool), postgres.NewAttributeRepository(dbpool))
+
+	ids := make([]uuid.UUID, 100)
+	for i := range ids {
+		ids[i] = factory.CreateProduct(b, productRepo, factory.WithTags("audio", "wireless")).ID
+	}
+	ctx := context.Background()
+
+	b.Run("by_id", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := productService.GetProductByID(ctx, ids[0]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("by_ids=100", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := productRepo.FindByIDs(ctx, ids); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
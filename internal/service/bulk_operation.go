@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/event"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrBulkOperationNotFound is returned when a bulk operation is not found.
+var ErrBulkOperationNotFound = errors.New("bulk operation not found")
+
+// BulkOperationInput contains the filter of the products an operation applies to and the change applied to them.
+type BulkOperationInput struct {
+	Filter domain.BulkOperationFilter
+	Kind   string  // price_percent, price_set or stock_adjust
+	Value  float64 // Percent, price or units, depending on the kind
+}
+
+// BulkOperationPreview is the outcome of a dry run: the number of products an operation would change.
+type BulkOperationPreview struct {
+	Matched int // Products matching the filter; stock adjustments skip bundles
+}
+
+// BulkOperationResultPage is a page of the per-product results of a bulk operation.
+type BulkOperationResultPage struct {
+	Results []domain.BulkOperationResult // In product ID order
+	Total   int                          // Number of results, of the requested status if one was given
+}
+
+// BulkOperationService applies price and stock changes to all products matching a filter of tags and category.
+// Operations are queued by admins and applied by the batch runner of the primary region, one batch of products at
+// a time. A batch is applied, and its results and the progress stored, in one transaction: a failed batch is retried
+// as a whole and no product is changed twice, until it failed domain.MaxBulkBatchAttempts times and the operation
+// fails. Archived products never match. Price changes are recorded in the product history, stock adjustments
+// in the stock ledger.
+type BulkOperationService struct {
+	db        repository.TxBeginner
+	repo      repository.BulkOperationRepository
+	products  *ProductService
+	stock     repository.StockRepository
+	events    event.Publisher
+	batchSize int
+	logger    logger.Logger
+}
+
+// NewBulkOperationService creates a new bulk operation service applying operations to batchSize products at a time.
+// Recorded stock movements are published to events.
+func NewBulkOperationService(db repository.TxBeginner, repo repository.BulkOperationRepository, products *ProductService, stock repository.StockRepository, events event.Publisher, batchSize int, logger logger.Logger) *BulkOperationService {
+	return &BulkOperationService{
+		db:        db,
+		repo:      repo,
+		products:  products,
+		stock:     stock,
+		events:    events,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// Preview returns the number of products the operation would change, without queuing it.
+// Returns domain.ErrInvalidBulkOperation.
+func (s *BulkOperationService) Preview(ctx context.Context, in BulkOperationInput) (*BulkOperationPreview, error) {
+	operation := &domain.BulkOperation{Filter: in.Filter, Kind: in.Kind, Value: in.Value}
+	if err := operation.Validate(); err != nil {
+		return nil, err
+	}
+	matched, err := s.repo.CountMatching(ctx, operation)
+	if err != nil {
+		return nil, fmt.Errorf("BulkOperationService.Preview: %w", err)
+	}
+	return &BulkOperationPreview{Matched: matched}, nil
+}
+
+// Create queues the operation for the batch runner, created by actor. Returns domain.ErrInvalidBulkOperation.
+func (s *BulkOperationService) Create(ctx context.Context, actor string, in BulkOperationInput) (*domain.BulkOperation, error) {
+	const op = "BulkOperationService.Create"
+
+	operation := &domain.BulkOperation{
+		ID:        uuid.New(),
+		Filter:    in.Filter,
+		Kind:      in.Kind,
+		Value:     in.Value,
+		Actor:     actor,
+		Status:    domain.BulkOperationQueued,
+		CreatedAt: time.Now(),
+	}
+	if err := operation.Validate(); err != nil {
+		return nil, err
+	}
+	matched, err := s.repo.CountMatching(ctx, operation)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	operation.Matched = matched
+	if err := s.repo.Create(ctx, operation); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return operation, nil
+}
+
+// Get returns the operation with its progress. Returns ErrBulkOperationNotFound.
+func (s *BulkOperationService) Get(ctx context.Context, id uuid.UUID) (*domain.BulkOperation, error) {
+	operation, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrBulkOperationNotFound) {
+			return nil, ErrBulkOperationNotFound
+		}
+		return nil, fmt.Errorf("BulkOperationService.Get: %w", err)
+	}
+	return operation, nil
+}
+
+// Results returns a page of the per-product results of the operation, of the status if it is not empty.
+// Returns ErrBulkOperationNotFound.
+func (s *BulkOperationService) Results(ctx context.Context, id uuid.UUID, status string, offset, limit int) (*BulkOperationResultPage, error) {
+	const op = "BulkOperationService.Results"
+
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	results, total, err := s.repo.FindResults(ctx, id, status, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if results == nil {
+		results = []domain.BulkOperationResult{}
+	}
+	return &BulkOperationResultPage{Results: results, Total: total}, nil
+}
+
+// Run applies batches of pending operations until ctx is done, checking for new ones every pollInterval while idle.
+// Each batch is recorded by hb.
+func (s *BulkOperationService) Run(ctx context.Context, pollInterval time.Duration, hb *Heartbeat) {
+	const op = "BulkOperationService.Run"
+
+	for {
+		applied, err := s.ApplyBatch(ctx)
+		hb.Beat(err)
+		if err != nil {
+			s.logger.Error("failed to apply bulk operation batch", "op", op, "error", err)
+		}
+		if applied && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// ApplyBatch applies the oldest pending operation to its next batch of matching products and stores the results
+// and progress in the same transaction. The operation is completed once a batch is shorter than the batch size.
+// A failed batch is counted against the operation after the transaction is rolled back, and the operation fails
+// after domain.MaxBulkBatchAttempts failed batches in a row, so it does not block the operations queued behind it.
+// Returns false if no operation is pending.
+func (s *BulkOperationService) ApplyBatch(ctx context.Context) (bool, error) {
+	const op = "BulkOperationService.ApplyBatch"
+
+	operation, err := s.applyBatch(ctx)
+	if err == nil || operation == nil {
+		return operation != nil, err
+	}
+	failed, recordErr := s.repo.RecordFailure(ctx, operation.ID, err.Error(), domain.MaxBulkBatchAttempts)
+	if recordErr != nil {
+		return true, fmt.Errorf("%w (recording the failure: %w)", err, recordErr)
+	}
+	if failed.Status == domain.BulkOperationFailed {
+		s.logger.Error("bulk operation failed", "op", op, "operation_id", operation.ID, "kind", operation.Kind,
+			"attempts", failed.Attempts, "processed", failed.Processed, "error", err)
+	}
+	return true, err
+}
+
+// applyBatch applies the next batch of the oldest pending operation and returns the operation,
+// nil if none is pending.
+func (s *BulkOperationService) applyBatch(ctx context.Context) (_ *domain.BulkOperation, err error) {
+	const op = "BulkOperationService.ApplyBatch"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not begin transaction: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	operation, err := s.repo.ClaimTx(ctx, tx)
+	if err != nil {
+		if errors.Is(err, repository.ErrBulkOperationNotFound) {
+			return nil, tx.Rollback(ctx)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	products, err := s.repo.FindMatchingTx(ctx, tx, operation, s.batchSize)
+	if err != nil {
+		return operation, fmt.Errorf("%s: operation %s: %w", op, operation.ID, err)
+	}
+
+	var results []domain.BulkOperationResult
+	var movements []domain.StockMovement
+	if operation.IsStockOperation() {
+		results, movements, err = s.adjustStockTx(ctx, tx, operation, products)
+	} else {
+		results, err = s.changePricesTx(ctx, tx, operation, products)
+	}
+	if err != nil {
+		return operation, fmt.Errorf("%s: operation %s: %w", op, operation.ID, err)
+	}
+
+	operation.Status = domain.BulkOperationRunning
+	if len(products) > 0 {
+		operation.Cursor = products[len(products)-1].ID
+	}
+	for _, res := range results {
+		operation.Processed++
+		if res.Status == domain.BulkResultUpdated {
+			operation.Updated++
+		} else {
+			operation.Failed++
+		}
+	}
+	complete := len(products) < s.batchSize
+	if complete {
+		now := time.Now()
+		operation.Status = domain.BulkOperationCompleted
+		operation.CompletedAt = &now
+	}
+
+	if err = s.repo.SaveProgressTx(ctx, tx, operation, results); err != nil {
+		return operation, fmt.Errorf("%s: operation %s: %w", op, operation.ID, err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return operation, fmt.Errorf("%s: could not commit transaction: %w", op, err)
+	}
+
+	for _, m := range movements {
+		s.events.Publish(ctx, event.StockChanged(m))
+	}
+	if complete {
+		s.logger.Info("bulk operation completed", "op", op, "operation_id", operation.ID, "kind", operation.Kind,
+			"processed", operation.Processed, "updated", operation.Updated, "failed", operation.Failed)
+	}
+	return operation, nil
+}
+
+// changePricesTx applies a price operation to the products within tx and returns their results.
+// Products whose price would exceed domain.MaxBulkPrice are left unchanged.
+func (s *BulkOperationService) changePricesTx(ctx context.Context, tx pgx.Tx, operation *domain.BulkOperation, products []domain.Product) ([]domain.BulkOperationResult, error) {
+	var results []domain.BulkOperationResult
+	var ids []uuid.UUID
+	for _, p := range products {
+		if operation.NewPrice(p.Price) > domain.MaxBulkPrice {
+			results = append(results, domain.BulkOperationResult{
+				ProductID: p.ID,
+				Status:    domain.BulkResultFailed,
+				Error:     fmt.Sprintf("price would exceed %.2f", domain.MaxBulkPrice),
+				Before:    p.Price,
+				After:     p.Price,
+			})
+			continue
+		}
+		ids = append(ids, p.ID)
+	}
+	if len(ids) == 0 {
+		return results, nil
+	}
+
+	outcomes, err := s.products.ApplyTx(ctx, tx, operation.Actor, ids, func(p *domain.Product) domain.ProductChange {
+		price := operation.NewPrice(p.Price)
+		return domain.ProductChange{Price: &price}
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, outcome := range outcomes {
+		res := domain.BulkOperationResult{
+			ProductID: outcome.Before.ID,
+			Status:    domain.BulkResultUpdated,
+			Before:    outcome.Before.Price,
+			After:     outcome.Before.Price,
+		}
+		if outcome.Err != nil {
+			res.Status, res.Error = domain.BulkResultFailed, outcome.Err.Error()
+		} else {
+			res.After = outcome.After.Price
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// adjustStockTx records an adjustment of the operation for each product within tx and returns their results
+// with the recorded movements. Products without enough stock to remove are left unchanged.
+func (s *BulkOperationService) adjustStockTx(ctx context.Context, tx pgx.Tx, operation *domain.BulkOperation, products []domain.Product) ([]domain.BulkOperationResult, []domain.StockMovement, error) {
+	now := time.Now()
+	delta := int(operation.Value)
+	results := make([]domain.BulkOperationResult, len(products))
+	var movements []domain.StockMovement
+	for i, p := range products {
+		results[i] = domain.BulkOperationResult{
+			ProductID: p.ID,
+			Status:    domain.BulkResultUpdated,
+			Before:    float64(p.Quantity),
+			After:     float64(p.Quantity + delta),
+		}
+		movement := domain.StockMovement{
+			ID:        uuid.New(),
+			ProductID: p.ID,
+			Delta:     delta,
+			Reason:    domain.StockReasonAdjustment,
+			Note:      "bulk operation " + operation.ID.String(),
+			CreatedAt: now,
+		}
+		if err := s.stock.RecordTx(ctx, tx, &movement); err != nil {
+			if !errors.Is(err, repository.ErrNegativeStock) {
+				return nil, nil, fmt.Errorf("record adjustment of product %s: %w", p.ID, err)
+			}
+			results[i].Status, results[i].Error, results[i].After = domain.BulkResultFailed, "not enough stock", results[i].Before
+			continue
+		}
+		movements = append(movements, movement)
+	}
+	return results, movements, nil
+}
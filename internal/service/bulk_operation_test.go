@@ -0,0 +1,249 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/event"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"product-api/internal/repository/postgres"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"product-api/internal/testutil/testdb"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+const bulkActor = "client:admin"
+
+type BulkOperationServiceTestSuite struct {
+	suite.Suite
+	productRepo repository.ProductRepository
+	revisions   repository.ProductRevisionRepository
+	stock       *service.StockService
+	products    *service.ProductService
+	service     *service.BulkOperationService
+}
+
+// Batches of two products make the runner page through the matching products.
+func (s *BulkOperationServiceTestSuite) SetupTest() {
+	dbpool := testdb.New(s.T())
+	log := logger.NewSlogAdapter("local")
+	bus := event.NewBus(log)
+	stockRepo := postgres.NewStockRepository(dbpool)
+	s.productRepo = postgres.NewProductRepository(dbpool)
+	s.revisions = postgres.NewProductRevisionRepository(dbpool)
+	s.stock = service.NewStockService(stockRepo, bus, log)
+	s.products = service.NewProductService(dbpool, s.productRepo, s.revisions, postgres.NewAttributeRepository(dbpool))
+	s.service = service.NewBulkOperationService(dbpool, postgres.NewBulkOperationRepository(dbpool), s.products, stockRepo, bus, 2, log)
+}
+
+func (s *BulkOperationServiceTestSuite) TestPricePercent() {
+	ctx := context.Background()
+	matching := []*domain.Product{
+		factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("summer", "sale"), factory.WithCategory("shoes"), factory.WithPrice(100)),
+		factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("summer"), factory.WithCategory("shoes"), factory.WithPrice(50)),
+		factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("summer"), factory.WithCategory("shoes"), factory.WithPrice(20)),
+	}
+	slices.SortFunc(matching, func(a, b *domain.Product) int { return compareIDs(a.ID, b.ID) })
+	others := []*domain.Product{
+		factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("winter"), factory.WithCategory("shoes"), factory.WithPrice(100)),
+		factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("summer"), factory.WithCategory("bags"), factory.WithPrice(100)),
+		factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("summer"), factory.WithCategory("shoes"), factory.WithPrice(100),
+			factory.WithStatus(domain.ProductStatusArchived)),
+	}
+
+	operation, err := s.service.Create(ctx, bulkActor, service.BulkOperationInput{
+		Filter: domain.BulkOperationFilter{Tags: []string{"summer"}, Category: "shoes"},
+		Kind:   domain.BulkOpPricePercent,
+		Value:  10,
+	})
+	s.Require().NoError(err)
+	s.Equal(3, operation.Matched)
+
+	// The first batch changes the two products with the lowest IDs and stops at the cursor
+	applied, err := s.service.ApplyBatch(ctx)
+	s.Require().NoError(err)
+	s.True(applied)
+
+	progress, err := s.service.Get(ctx, operation.ID)
+	s.Require().NoError(err)
+	s.Equal(domain.BulkOperationRunning, progress.Status)
+	s.Equal(matching[1].ID, progress.Cursor)
+	s.Equal(2, progress.Processed)
+	s.assertPrice(matching[2], matching[2].Price)
+
+	applied, err = s.service.ApplyBatch(ctx)
+	s.Require().NoError(err)
+	s.True(applied)
+
+	progress, err = s.service.Get(ctx, operation.ID)
+	s.Require().NoError(err)
+	s.Equal(domain.BulkOperationCompleted, progress.Status)
+	s.NotNil(progress.CompletedAt)
+	s.Equal(3, progress.Processed)
+	s.Equal(3, progress.Updated)
+	s.Zero(progress.Failed)
+
+	applied, err = s.service.ApplyBatch(ctx)
+	s.Require().NoError(err)
+	s.False(applied)
+
+	page, err := s.service.Results(ctx, operation.ID, "", 0, 10)
+	s.Require().NoError(err)
+	s.Equal(3, page.Total)
+	for i, p := range matching {
+		after := domain.RoundCents(p.Price * 1.1)
+		s.Equal(domain.BulkOperationResult{ProductID: p.ID, Status: domain.BulkResultUpdated, Before: p.Price, After: after}, page.Results[i])
+		s.assertPrice(p, after)
+
+		revisions, err := s.revisions.FindByProductID(ctx, p.ID, 10)
+		s.Require().NoError(err)
+		s.Require().Len(revisions, 1)
+		s.Equal(bulkActor, revisions[0].Actor)
+		s.Equal([]domain.FieldDiff{{Field: domain.ProductFieldPrice, Before: p.Price, After: after}}, revisions[0].Changes)
+	}
+	for _, p := range others {
+		s.assertPrice(p, p.Price)
+
+		revisions, err := s.revisions.FindByProductID(ctx, p.ID, 10)
+		s.Require().NoError(err)
+		s.Empty(revisions)
+	}
+}
+
+func (s *BulkOperationServiceTestSuite) TestPriceAtAfterPriceSet() {
+	ctx := context.Background()
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("summer"), factory.WithPrice(100))
+	price, description := 80.0, "Renamed"
+	for _, change := range []domain.ProductChange{{Price: &price}, {Description: &description}} {
+		report, err := s.products.BulkUpdate(ctx, "user:manager", []service.ProductChangeInput{{ID: product.ID, Change: change}}, true)
+		s.Require().NoError(err)
+		s.Require().Equal(service.BulkItemUpdated, report.Results[0].Status)
+	}
+	_, err := s.service.Create(ctx, bulkActor, service.BulkOperationInput{
+		Filter: domain.BulkOperationFilter{Tags: []string{"summer"}},
+		Kind:   domain.BulkOpPriceSet,
+		Value:  60,
+	})
+	s.Require().NoError(err)
+	applied, err := s.service.ApplyBatch(ctx)
+	s.Require().NoError(err)
+	s.Require().True(applied)
+
+	revisions, err := s.revisions.FindByField(ctx, product.ID, domain.ProductFieldPrice)
+	s.Require().NoError(err)
+	s.Require().Len(revisions, 2, "the description edit does not change the price")
+	s.Equal([]domain.FieldDiff{{Field: domain.ProductFieldPrice, Before: 100.0, After: 80.0}}, revisions[0].Changes)
+	s.Equal([]domain.FieldDiff{{Field: domain.ProductFieldPrice, Before: 80.0, After: 60.0}}, revisions[1].Changes)
+	s.Equal(bulkActor, revisions[1].Actor)
+
+	before, err := s.products.PriceAt(ctx, product.ID, revisions[0].ChangedAt.Add(-time.Millisecond))
+	s.Require().NoError(err)
+	s.Equal(100.0, before.Price)
+	s.Nil(before.SetAt, "the price the product was created with")
+
+	edited, err := s.products.PriceAt(ctx, product.ID, revisions[1].ChangedAt.Add(-time.Microsecond))
+	s.Require().NoError(err)
+	s.Equal(80.0, edited.Price)
+	s.Equal("user:manager", edited.SetBy)
+
+	current, err := s.products.PriceAt(ctx, product.ID, time.Now())
+	s.Require().NoError(err)
+	s.Equal(60.0, current.Price)
+	s.Equal(bulkActor, current.SetBy)
+	s.Require().NotNil(current.SetAt)
+	s.True(current.SetAt.Equal(revisions[1].ChangedAt))
+}
+
+func (s *BulkOperationServiceTestSuite) TestStockAdjust() {
+	ctx := context.Background()
+	stocked := factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("summer"), factory.WithQuantity(10))
+	short := factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("summer"), factory.WithQuantity(2))
+	other := factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("summer", "sale"), factory.WithQuantity(5))
+	bundle := factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("summer"), factory.WithQuantity(0),
+		factory.WithComponents(domain.BundleComponent{ProductID: stocked.ID, Quantity: 1}))
+	archived := factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("summer"), factory.WithQuantity(10),
+		factory.WithStatus(domain.ProductStatusArchived))
+
+	operation, err := s.service.Create(ctx, bulkActor, service.BulkOperationInput{
+		Filter: domain.BulkOperationFilter{Tags: []string{"summer"}},
+		Kind:   domain.BulkOpStockAdjust,
+		Value:  -3,
+	})
+	s.Require().NoError(err)
+	s.Equal(3, operation.Matched)
+
+	for range 2 {
+		applied, err := s.service.ApplyBatch(ctx)
+		s.Require().NoError(err)
+		s.True(applied)
+	}
+
+	progress, err := s.service.Get(ctx, operation.ID)
+	s.Require().NoError(err)
+	s.Equal(domain.BulkOperationCompleted, progress.Status)
+	s.Equal(3, progress.Processed)
+	s.Equal(2, progress.Updated)
+	s.Equal(1, progress.Failed)
+
+	page, err := s.service.Results(ctx, operation.ID, domain.BulkResultFailed, 0, 10)
+	s.Require().NoError(err)
+	s.Equal(1, page.Total)
+	s.Equal([]domain.BulkOperationResult{
+		{ProductID: short.ID, Status: domain.BulkResultFailed, Error: "not enough stock", Before: 2, After: 2},
+	}, page.Results)
+
+	page, err = s.service.Results(ctx, operation.ID, domain.BulkResultUpdated, 0, 10)
+	s.Require().NoError(err)
+	s.Equal(2, page.Total)
+	s.ElementsMatch([]domain.BulkOperationResult{
+		{ProductID: stocked.ID, Status: domain.BulkResultUpdated, Before: 10, After: 7},
+		{ProductID: other.ID, Status: domain.BulkResultUpdated, Before: 5, After: 2},
+	}, page.Results)
+
+	s.assertQuantity(stocked, 7)
+	s.assertQuantity(short, 2)
+	s.assertQuantity(other, 2)
+	s.assertQuantity(archived, 10)
+
+	// The adjustments are recorded in the ledger, the bundle and the archived product get none
+	movements, err := s.stock.History(ctx, stocked.ID, 10)
+	s.Require().NoError(err)
+	s.Require().Len(movements, 2)
+	s.Equal(-3, movements[0].Delta)
+	s.Equal(domain.StockReasonAdjustment, movements[0].Reason)
+	s.Equal("bulk operation "+operation.ID.String(), movements[0].Note)
+	for _, p := range []*domain.Product{bundle, archived} {
+		movements, err := s.stock.History(ctx, p.ID, 10)
+		s.Require().NoError(err)
+		for _, m := range movements {
+			s.NotEqual(domain.StockReasonAdjustment, m.Reason)
+		}
+	}
+}
+
+func (s *BulkOperationServiceTestSuite) assertPrice(product *domain.Product, price float64) {
+	updated, err := s.productRepo.FindByID(context.Background(), product.ID)
+	s.Require().NoError(err)
+	s.Equal(price, updated.Price)
+}
+
+func (s *BulkOperationServiceTestSuite) assertQuantity(product *domain.Product, quantity int) {
+	updated, err := s.productRepo.FindByID(context.Background(), product.ID)
+	s.Require().NoError(err)
+	s.Equal(quantity, updated.Quantity)
+}
+
+// compareIDs orders UUIDs like PostgreSQL does.
+func compareIDs(a, b uuid.UUID) int {
+	return slices.Compare(a[:], b[:])
+}
+
+func TestBulkOperationServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(BulkOperationServiceTestSuite))
+}
@@ -0,0 +1,241 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/event"
+	"product-api/internal/repository"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// bulkOperationServiceMocks contains mocked dependencies of BulkOperationService.
+type bulkOperationServiceMocks struct {
+	db        *mocks.MockTxBeginner
+	tx        *mocks.MockTx
+	repo      *mocks.MockBulkOperationRepository
+	products  *mocks.MockProductRepository
+	revisions *mocks.MockProductRevisionRepository
+	stock     *mocks.MockStockRepository
+	events    *event.Bus
+}
+
+func newBulkOperationServiceWithMocks(t *testing.T, batchSize int) (*service.BulkOperationService, *bulkOperationServiceMocks) {
+	m := &bulkOperationServiceMocks{
+		db:        mocks.NewMockTxBeginner(t),
+		tx:        mocks.NewMockTx(t),
+		repo:      mocks.NewMockBulkOperationRepository(t),
+		products:  mocks.NewMockProductRepository(t),
+		revisions: mocks.NewMockProductRevisionRepository(t),
+		stock:     mocks.NewMockStockRepository(t),
+		events:    event.NewBus(discardLogger{}),
+	}
+	products := service.NewProductService(m.db, m.products, m.revisions, mocks.NewMockAttributeRepository(t))
+	return service.NewBulkOperationService(m.db, m.repo, products, m.stock, m.events, batchSize, discardLogger{}), m
+}
+
+func TestBulkOperationCreate_Unit_RejectsInvalidOperations(t *testing.T) {
+	svc, _ := newBulkOperationServiceWithMocks(t, 10)
+	summer := domain.BulkOperationFilter{Tags: []string{"summer"}}
+
+	for name, in := range map[string]service.BulkOperationInput{
+		"empty filter":        {Kind: domain.BulkOpPricePercent, Value: 10},
+		"unknown kind":        {Filter: summer, Kind: "price_double", Value: 2},
+		"zero percent":        {Filter: summer, Kind: domain.BulkOpPricePercent},
+		"free products":       {Filter: summer, Kind: domain.BulkOpPricePercent, Value: -100},
+		"negative price":      {Filter: summer, Kind: domain.BulkOpPriceSet, Value: -5},
+		"fractional units":    {Filter: summer, Kind: domain.BulkOpStockAdjust, Value: 1.5},
+		"too many units":      {Filter: summer, Kind: domain.BulkOpStockAdjust, Value: domain.MaxBulkStockAdjustment + 1},
+		"price out of range":  {Filter: summer, Kind: domain.BulkOpPriceSet, Value: domain.MaxBulkPrice + 1},
+		"zero stock adjusted": {Filter: summer, Kind: domain.BulkOpStockAdjust},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.Create(context.Background(), productActor, in)
+			assert.ErrorIs(t, err, domain.ErrInvalidBulkOperation)
+			_, err = svc.Preview(context.Background(), in)
+			assert.ErrorIs(t, err, domain.ErrInvalidBulkOperation)
+		})
+	}
+}
+
+func TestBulkOperationCreate_Unit_QueuesWithMatchedCount(t *testing.T) {
+	svc, m := newBulkOperationServiceWithMocks(t, 10)
+	in := service.BulkOperationInput{
+		Filter: domain.BulkOperationFilter{Tags: []string{"summer"}, Category: "shoes"},
+		Kind:   domain.BulkOpPricePercent,
+		Value:  10,
+	}
+
+	m.repo.EXPECT().CountMatching(mock.Anything, mock.Anything).Return(42, nil)
+	m.repo.EXPECT().Create(mock.Anything, mock.MatchedBy(func(o *domain.BulkOperation) bool {
+		return o.Status == domain.BulkOperationQueued && o.Matched == 42 && o.Actor == productActor
+	})).Return(nil)
+
+	operation, err := svc.Create(context.Background(), productActor, in)
+	require.NoError(t, err)
+	assert.Equal(t, 42, operation.Matched)
+	assert.Equal(t, in.Filter, operation.Filter)
+}
+
+func TestBulkOperationApplyBatch_Unit_NothingPending(t *testing.T) {
+	svc, m := newBulkOperationServiceWithMocks(t, 10)
+
+	m.db.EXPECT().Begin(mock.Anything).Return(m.tx, nil)
+	m.repo.EXPECT().ClaimTx(mock.Anything, m.tx).Return(nil, repository.ErrBulkOperationNotFound)
+	m.tx.EXPECT().Rollback(mock.Anything).Return(nil)
+
+	applied, err := svc.ApplyBatch(context.Background())
+	require.NoError(t, err)
+	assert.False(t, applied)
+}
+
+func TestBulkOperationApplyBatch_Unit_ChangesPricesAndRecordsResults(t *testing.T) {
+	svc, m := newBulkOperationServiceWithMocks(t, 2)
+	cheap := factory.NewProduct(factory.WithPrice(10))
+	pricey := factory.NewProduct(factory.WithPrice(domain.MaxBulkPrice))
+	operation := &domain.BulkOperation{
+		ID:     uuid.New(),
+		Filter: domain.BulkOperationFilter{Tags: []string{"summer"}},
+		Kind:   domain.BulkOpPricePercent,
+		Value:  10,
+		Actor:  productActor,
+		Status: domain.BulkOperationQueued,
+	}
+
+	m.db.EXPECT().Begin(mock.Anything).Return(m.tx, nil)
+	m.repo.EXPECT().ClaimTx(mock.Anything, m.tx).Return(operation, nil)
+	m.repo.EXPECT().FindMatchingTx(mock.Anything, m.tx, operation, 2).Return([]domain.Product{*cheap, *pricey}, nil)
+	m.products.EXPECT().FindByIDsTx(mock.Anything, m.tx, []uuid.UUID{cheap.ID}).Return([]domain.Product{*cheap}, nil)
+	m.products.EXPECT().UpdateTx(mock.Anything, m.tx, mock.MatchedBy(func(p *domain.Product) bool {
+		return p.ID == cheap.ID && p.Price == 11
+	})).Return(nil)
+	m.revisions.EXPECT().AppendTx(mock.Anything, m.tx, mock.MatchedBy(func(revs []domain.ProductRevision) bool {
+		return len(revs) == 1 && revs[0].ProductID == cheap.ID && revs[0].Actor == productActor
+	})).Return(nil)
+	m.repo.EXPECT().SaveProgressTx(mock.Anything, m.tx, operation, mock.MatchedBy(func(results []domain.BulkOperationResult) bool {
+		return len(results) == 2
+	})).Run(func(_ context.Context, _ pgx.Tx, _ *domain.BulkOperation, results []domain.BulkOperationResult) {
+		byProduct := map[uuid.UUID]domain.BulkOperationResult{}
+		for _, res := range results {
+			byProduct[res.ProductID] = res
+		}
+		assert.Equal(t, domain.BulkOperationResult{ProductID: cheap.ID, Status: domain.BulkResultUpdated, Before: 10, After: 11}, byProduct[cheap.ID])
+		assert.Equal(t, domain.BulkResultFailed, byProduct[pricey.ID].Status)
+		assert.Equal(t, domain.MaxBulkPrice, byProduct[pricey.ID].After)
+	}).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil)
+
+	applied, err := svc.ApplyBatch(context.Background())
+	require.NoError(t, err)
+	assert.True(t, applied)
+
+	// A full batch may be followed by more products, the operation keeps running from the last one
+	assert.Equal(t, domain.BulkOperationRunning, operation.Status)
+	assert.Equal(t, pricey.ID, operation.Cursor)
+	assert.Equal(t, 2, operation.Processed)
+	assert.Equal(t, 1, operation.Updated)
+	assert.Equal(t, 1, operation.Failed)
+	assert.Nil(t, operation.CompletedAt)
+}
+
+func TestBulkOperationApplyBatch_Unit_AdjustsStockAndCompletes(t *testing.T) {
+	svc, m := newBulkOperationServiceWithMocks(t, 10)
+	stocked := factory.NewProduct(factory.WithQuantity(8))
+	empty := factory.NewProduct(factory.WithQuantity(2))
+	operation := &domain.BulkOperation{
+		ID:        uuid.New(),
+		Filter:    domain.BulkOperationFilter{Category: "shoes"},
+		Kind:      domain.BulkOpStockAdjust,
+		Value:     -5,
+		Status:    domain.BulkOperationRunning,
+		Processed: 10,
+		Updated:   10,
+	}
+
+	var published []event.ProductStockChanged
+	event.Subscribe(m.events, "test", func(_ context.Context, e event.ProductStockChanged) error {
+		published = append(published, e)
+		return nil
+	})
+
+	m.db.EXPECT().Begin(mock.Anything).Return(m.tx, nil)
+	m.repo.EXPECT().ClaimTx(mock.Anything, m.tx).Return(operation, nil)
+	m.repo.EXPECT().FindMatchingTx(mock.Anything, m.tx, operation, 10).Return([]domain.Product{*stocked, *empty}, nil)
+	m.stock.EXPECT().RecordTx(mock.Anything, m.tx, mock.MatchedBy(func(mv *domain.StockMovement) bool {
+		return mv.ProductID == stocked.ID && mv.Delta == -5 && mv.Reason == domain.StockReasonAdjustment
+	})).Return(nil)
+	m.stock.EXPECT().RecordTx(mock.Anything, m.tx, mock.MatchedBy(func(mv *domain.StockMovement) bool {
+		return mv.ProductID == empty.ID
+	})).Return(repository.ErrNegativeStock)
+	m.repo.EXPECT().SaveProgressTx(mock.Anything, m.tx, operation, []domain.BulkOperationResult{
+		{ProductID: stocked.ID, Status: domain.BulkResultUpdated, Before: 8, After: 3},
+		{ProductID: empty.ID, Status: domain.BulkResultFailed, Error: "not enough stock", Before: 2, After: 2},
+	}).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil)
+
+	applied, err := svc.ApplyBatch(context.Background())
+	require.NoError(t, err)
+	assert.True(t, applied)
+
+	assert.Equal(t, domain.BulkOperationCompleted, operation.Status)
+	assert.NotNil(t, operation.CompletedAt)
+	assert.Equal(t, 12, operation.Processed)
+	assert.Equal(t, 11, operation.Updated)
+	assert.Equal(t, 1, operation.Failed)
+
+	// Only the recorded adjustment is published, after the commit
+	require.Len(t, published, 1)
+	assert.Equal(t, stocked.ID, published[0].ProductID)
+	assert.Equal(t, -5, published[0].Delta)
+}
+
+func TestBulkOperationApplyBatch_Unit_RollsBackFailedBatch(t *testing.T) {
+	svc, m := newBulkOperationServiceWithMocks(t, 10)
+	product := factory.NewProduct(factory.WithQuantity(8))
+	operation := &domain.BulkOperation{ID: uuid.New(), Kind: domain.BulkOpStockAdjust, Value: 1, Status: domain.BulkOperationQueued}
+
+	m.db.EXPECT().Begin(mock.Anything).Return(m.tx, nil)
+	m.repo.EXPECT().ClaimTx(mock.Anything, m.tx).Return(operation, nil)
+	m.repo.EXPECT().FindMatchingTx(mock.Anything, m.tx, operation, 10).Return([]domain.Product{*product}, nil)
+	m.stock.EXPECT().RecordTx(mock.Anything, m.tx, mock.Anything).Return(assert.AnError)
+	m.tx.EXPECT().Rollback(mock.Anything).Return(nil)
+	m.repo.EXPECT().RecordFailure(mock.Anything, operation.ID, mock.Anything, domain.MaxBulkBatchAttempts).
+		Return(&domain.BulkOperation{ID: operation.ID, Status: domain.BulkOperationRunning, Attempts: 1}, nil)
+
+	applied, err := svc.ApplyBatch(context.Background())
+	require.ErrorIs(t, err, assert.AnError)
+	assert.True(t, applied)
+}
+
+func TestBulkOperationApplyBatch_Unit_RecordsFailureAfterRollback(t *testing.T) {
+	svc, m := newBulkOperationServiceWithMocks(t, 10)
+	operation := &domain.BulkOperation{ID: uuid.New(), Kind: domain.BulkOpPricePercent, Value: 10, Status: domain.BulkOperationRunning}
+
+	m.db.EXPECT().Begin(mock.Anything).Return(m.tx, nil)
+	m.repo.EXPECT().ClaimTx(mock.Anything, m.tx).Return(operation, nil)
+	m.repo.EXPECT().FindMatchingTx(mock.Anything, m.tx, operation, 10).Return(nil, assert.AnError)
+	rolledBack := false
+	m.tx.EXPECT().Rollback(mock.Anything).RunAndReturn(func(context.Context) error {
+		rolledBack = true
+		return nil
+	})
+	m.repo.EXPECT().RecordFailure(mock.Anything, operation.ID, mock.Anything, domain.MaxBulkBatchAttempts).
+		RunAndReturn(func(_ context.Context, id uuid.UUID, cause string, _ int) (*domain.BulkOperation, error) {
+			// The failure is recorded outside the rolled back batch transaction
+			assert.True(t, rolledBack)
+			assert.Contains(t, cause, assert.AnError.Error())
+			return &domain.BulkOperation{ID: id, Status: domain.BulkOperationFailed, Attempts: domain.MaxBulkBatchAttempts, Error: cause}, nil
+		})
+
+	applied, err := svc.ApplyBatch(context.Background())
+	require.ErrorIs(t, err, assert.AnError)
+	assert.True(t, applied)
+}
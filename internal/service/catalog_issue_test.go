@@ -0,0 +1,113 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"product-api/internal/repository/postgres"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"product-api/internal/testutil/testdb"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/suite"
+)
+
+type CatalogIssueTestSuite struct {
+	suite.Suite
+	dbpool      *pgxpool.Pool
+	productRepo repository.ProductRepository
+	service     *service.ProductService
+}
+
+func (s *CatalogIssueTestSuite) SetupTest() {
+	s.dbpool = testdb.New(s.T())
+	s.productRepo = postgres.NewProductRepository(s.dbpool)
+	s.service = service.NewProductService(s.dbpool, s.productRepo, postgres.NewProductRevisionRepository(s.dbpool), postgres.NewAttributeRepository(s.dbpool))
+}
+
+// createIssues creates a product with the issue of each rule, a product without issues
+// and an archived product with issues, and returns the issues to be found in product ID order.
+func (s *CatalogIssueTestSuite) createIssues() []domain.CatalogIssue {
+	ctx := context.Background()
+	factory.CreateProduct(s.T(), s.productRepo, factory.WithDescription("Clean"))
+	factory.CreateProduct(s.T(), s.productRepo, factory.WithDescription("Archived"), factory.WithStatus(domain.ProductStatusArchived),
+		factory.WithPrice(0), factory.WithTags())
+
+	zeroPrice := factory.CreateProduct(s.T(), s.productRepo, factory.WithDescription("Free"), factory.WithPrice(0))
+	noTags := factory.CreateProduct(s.T(), s.productRepo, factory.WithDescription("Untagged"), factory.WithTags())
+	negativeStock := factory.CreateProduct(s.T(), s.productRepo, factory.WithDescription("Oversold"), factory.WithQuantity(2))
+	// A movement that bypassed the quantity check, e.g. recorded before it existed
+	_, err := s.dbpool.Exec(ctx, `INSERT INTO stock_movements (id, product_id, delta, reason) VALUES ($1, $2, -5, 'adjustment')`,
+		uuid.New(), negativeStock.ID)
+	s.Require().NoError(err)
+	draft := factory.CreateProduct(s.T(), s.productRepo, factory.WithDescription("Draft"), factory.WithStatus(domain.ProductStatusDraft))
+	bundle := factory.CreateProduct(s.T(), s.productRepo, factory.WithDescription("Bundle"), factory.WithQuantity(0),
+		factory.WithComponents(domain.BundleComponent{ProductID: draft.ID, Quantity: 1}))
+
+	issues := []domain.CatalogIssue{
+		{ProductID: zeroPrice.ID, Description: "Free", Status: domain.ProductStatusActive, Rule: domain.CatalogIssueZeroPrice},
+		{ProductID: noTags.ID, Description: "Untagged", Status: domain.ProductStatusActive, Rule: domain.CatalogIssueNoTags},
+		{ProductID: negativeStock.ID, Description: "Oversold", Status: domain.ProductStatusActive, Rule: domain.CatalogIssueNegativeStock,
+			Detail: "ledger quantity -3"},
+		{ProductID: bundle.ID, Description: "Bundle", Status: domain.ProductStatusActive, Rule: domain.CatalogIssueInactiveComponent,
+			Detail: draft.ID.String() + " is draft"},
+	}
+	sortIssues(issues)
+	return issues
+}
+
+func (s *CatalogIssueTestSuite) TestIssues() {
+	ctx := context.Background()
+	want := s.createIssues()
+
+	report, err := s.service.Issues(ctx, nil, 0, 10)
+	s.Require().NoError(err)
+	s.Equal(want, report.Issues, "one issue per rule, none of the archived product")
+	s.Equal(map[string]int{
+		domain.CatalogIssueZeroPrice:         1,
+		domain.CatalogIssueNoTags:            1,
+		domain.CatalogIssueNegativeStock:     1,
+		domain.CatalogIssueInactiveComponent: 1,
+	}, report.Counts)
+	s.Equal(4, report.Total)
+
+	// Counts cover every page
+	report, err = s.service.Issues(ctx, nil, 1, 2)
+	s.Require().NoError(err)
+	s.Equal(want[1:3], report.Issues)
+	s.Equal(4, report.Total)
+}
+
+func (s *CatalogIssueTestSuite) TestIssuesOfRules() {
+	ctx := context.Background()
+	s.createIssues()
+
+	report, err := s.service.Issues(ctx, []string{domain.CatalogIssueNoTags, domain.CatalogIssueZeroPrice}, 0, 10)
+	s.Require().NoError(err)
+	s.Require().Len(report.Issues, 2)
+	for _, issue := range report.Issues {
+		s.Contains([]string{domain.CatalogIssueNoTags, domain.CatalogIssueZeroPrice}, issue.Rule)
+	}
+	s.Equal(map[string]int{domain.CatalogIssueNoTags: 1, domain.CatalogIssueZeroPrice: 1}, report.Counts)
+	s.Equal(2, report.Total)
+}
+
+func TestCatalogIssueTestSuite(t *testing.T) {
+	suite.Run(t, new(CatalogIssueTestSuite))
+}
+
+// sortIssues sorts issues in the product ID and rule order they are reported in.
+func sortIssues(issues []domain.CatalogIssue) {
+	slices.SortFunc(issues, func(a, b domain.CatalogIssue) int {
+		if c := bytes.Compare(a.ProductID[:], b.ProductID[:]); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Rule, b.Rule)
+	})
+}
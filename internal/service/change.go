@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"time"
+)
+
+var (
+	// ErrChangeNotFound is returned when the feed is read after an unknown sequence number.
+	ErrChangeNotFound = errors.New("change not found")
+)
+
+// changeCleanupBatch is the number of changes deleted per statement, so a cleanup after a busy day
+// does not hold the locks of all its changes at once.
+const changeCleanupBatch = 1000
+
+// ChangeService provides the change feed of product, order and user mutations for incremental sync.
+// Changes are kept for retention; readers further behind get ErrChangeNotFound and start over from a full export.
+type ChangeService struct {
+	repo      repository.ChangeRepository
+	retention time.Duration
+	logger    logger.Logger
+}
+
+// NewChangeService creates a new change feed service.
+func NewChangeService(repo repository.ChangeRepository, retention time.Duration, logger logger.Logger) *ChangeService {
+	return &ChangeService{repo: repo, retention: retention, logger: logger}
+}
+
+// Run deletes changes older than the retention every interval until ctx is done. Each cleanup is recorded by hb.
+func (s *ChangeService) Run(ctx context.Context, interval time.Duration, hb *Heartbeat) {
+	const op = "ChangeService.Run"
+
+	for {
+		deleted, err := s.Cleanup(ctx, time.Now())
+		hb.Beat(err)
+		if err != nil {
+			s.logger.Error("failed to delete old changes", "op", op, "deleted", deleted, "error", err)
+		} else if deleted > 0 {
+			s.logger.Info("old changes deleted", "op", op, "deleted", deleted)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Cleanup deletes the changes made more than the retention before now, in batches,
+// and returns the number of deleted changes.
+func (s *ChangeService) Cleanup(ctx context.Context, now time.Time) (int, error) {
+	deleted := 0
+	for {
+		n, err := s.repo.DeleteBefore(ctx, now.Add(-s.retention), changeCleanupBatch)
+		deleted += n
+		if err != nil {
+			return deleted, fmt.Errorf("ChangeService.Cleanup: %w", err)
+		}
+		if n < changeCleanupBatch || ctx.Err() != nil {
+			return deleted, nil
+		}
+	}
+}
+
+// ChangePage is a page of the change feed.
+type ChangePage struct {
+	Changes []domain.Change
+	Next    int64 // Sequence number to read the following page after, equal to since for empty pages
+}
+
+// Feed returns up to limit changes following the change with sequence number since, or the first changes if it is zero.
+// Changes appear in the feed once their transaction and all transactions before it are finished,
+// so reading the following pages never skips a change.
+// Returns ErrChangeNotFound if no change has the sequence number, also once it is older than the retention.
+func (s *ChangeService) Feed(ctx context.Context, since int64, limit int) (*ChangePage, error) {
+	changes, err := s.repo.FindAfter(ctx, since, limit)
+	if err != nil {
+		if errors.Is(err, repository.ErrChangeNotFound) {
+			return nil, ErrChangeNotFound
+		}
+		return nil, fmt.Errorf("ChangeService.Feed: %w", err)
+	}
+
+	page := &ChangePage{Changes: changes, Next: since}
+	if len(changes) > 0 {
+		page.Next = changes[len(changes)-1].Sequence
+	}
+	return page, nil
+}
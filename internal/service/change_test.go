@@ -0,0 +1,133 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"product-api/internal/repository/postgres"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"product-api/internal/testutil/testdb"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChangeFeedTestSuite struct {
+	suite.Suite
+	dbpool   *pgxpool.Pool
+	userRepo repository.UserRepository
+	service  *service.ChangeService
+}
+
+func (s *ChangeFeedTestSuite) SetupTest() {
+	s.dbpool = testdb.New(s.T())
+	s.userRepo = postgres.NewUserRepository(s.dbpool)
+	s.service = service.NewChangeService(postgres.NewChangeRepository(s.dbpool), 24*time.Hour, discardLogger{})
+}
+
+// userChanges reads the whole feed after since and returns the changes of users.
+func (s *ChangeFeedTestSuite) userChanges(since int64) []domain.Change {
+	page, err := s.service.Feed(context.Background(), since, 100)
+	s.Require().NoError(err)
+	var changes []domain.Change
+	for _, c := range page.Changes {
+		if c.Entity == domain.ChangeEntityUser {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+func (s *ChangeFeedTestSuite) TestFeedPages() {
+	ctx := context.Background()
+	for range 3 {
+		factory.CreateUser(s.T(), s.userRepo)
+	}
+	all := s.userChanges(0)
+	s.Require().Len(all, 3)
+
+	var read []domain.Change
+	since := int64(0)
+	for {
+		page, err := s.service.Feed(ctx, since, 2)
+		s.Require().NoError(err)
+		if len(page.Changes) == 0 {
+			s.Equal(since, page.Next, "the position stays at the end of the feed")
+			break
+		}
+		read = append(read, page.Changes...)
+		since = page.Next
+	}
+	s.Equal(all, read, "pages continue after the last change of the previous page")
+
+	_, err := s.service.Feed(ctx, all[2].Sequence+1000, 2)
+	s.ErrorIs(err, service.ErrChangeNotFound)
+}
+
+func (s *ChangeFeedTestSuite) TestFeedData() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	s.Require().NoError(s.userRepo.Delete(ctx, user.ID))
+
+	changes := s.userChanges(0)
+	s.Require().Len(changes, 2)
+	s.Equal(user.ID, changes[0].EntityID)
+	s.Equal(domain.ChangeOperationInsert, changes[0].Operation)
+	s.Equal(user.Email, changes[0].Data["email"])
+	s.NotContains(changes[0].Data, "password_hash", "credentials are not in the feed")
+	s.Equal(domain.ChangeOperationDelete, changes[1].Operation)
+	s.Nil(changes[1].Data)
+}
+
+func (s *ChangeFeedTestSuite) TestFeedHidesOpenTransactions() {
+	ctx := context.Background()
+	open := factory.CreateUser(s.T(), s.userRepo)
+	start := s.userChanges(0)
+
+	tx, err := s.dbpool.Begin(ctx)
+	s.Require().NoError(err)
+	defer func() { _ = tx.Rollback(ctx) }()
+	_, err = tx.Exec(ctx, `UPDATE users SET firstname = 'Pending' WHERE id = $1`, open.ID)
+	s.Require().NoError(err)
+	// Committed after the open transaction started, so it must wait for it
+	later := factory.CreateUser(s.T(), s.userRepo)
+
+	s.Empty(s.userChanges(start[0].Sequence), "changes wait for the open transaction")
+
+	s.Require().NoError(tx.Commit(ctx))
+	s.Equal([]uuid.UUID{open.ID, later.ID}, changeEntityIDs(s.userChanges(start[0].Sequence)))
+}
+
+func (s *ChangeFeedTestSuite) TestCleanup() {
+	ctx := context.Background()
+	old := factory.CreateUser(s.T(), s.userRepo)
+	recent := factory.CreateUser(s.T(), s.userRepo)
+	_, err := s.dbpool.Exec(ctx, `UPDATE change_log SET changed_at = NOW() - INTERVAL '25 hours' WHERE entity_id = $1`, old.ID)
+	s.Require().NoError(err)
+	oldChange := s.userChanges(0)[0]
+
+	deleted, err := s.service.Cleanup(ctx, time.Now())
+	s.Require().NoError(err)
+	s.Equal(1, deleted)
+
+	s.Equal([]uuid.UUID{recent.ID}, changeEntityIDs(s.userChanges(0)))
+	_, err = s.service.Feed(ctx, oldChange.Sequence, 10)
+	s.ErrorIs(err, service.ErrChangeNotFound, "readers behind the retention start over")
+}
+
+func TestChangeFeedTestSuite(t *testing.T) {
+	suite.Run(t, new(ChangeFeedTestSuite))
+}
+
+// changeEntityIDs returns the entity IDs of the changes in feed order.
+func changeEntityIDs(changes []domain.Change) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(changes))
+	for _, c := range changes {
+		ids = append(ids, c.EntityID)
+	}
+	return ids
+}
@@ -0,0 +1,66 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeFeed_Unit_NextPosition(t *testing.T) {
+	repo := mocks.NewMockChangeRepository(t)
+	svc := service.NewChangeService(repo, 24*time.Hour, discardLogger{})
+
+	// Sequences are not assigned in feed order, the next page follows the last change of the page
+	repo.EXPECT().FindAfter(mock.Anything, int64(0), 2).Return([]domain.Change{{Sequence: 7}, {Sequence: 5}}, nil)
+	repo.EXPECT().FindAfter(mock.Anything, int64(5), 2).Return([]domain.Change{}, nil)
+	repo.EXPECT().FindAfter(mock.Anything, int64(9), 2).Return(nil, repository.ErrChangeNotFound)
+
+	page, err := svc.Feed(context.Background(), 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), page.Next)
+
+	page, err = svc.Feed(context.Background(), 5, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), page.Next)
+	assert.Empty(t, page.Changes)
+
+	_, err = svc.Feed(context.Background(), 9, 2)
+	assert.ErrorIs(t, err, service.ErrChangeNotFound)
+}
+
+func TestChangeCleanup_Unit_DeletesInBatches(t *testing.T) {
+	repo := mocks.NewMockChangeRepository(t)
+	svc := service.NewChangeService(repo, 24*time.Hour, discardLogger{})
+	now := time.Now()
+	before := now.Add(-24 * time.Hour)
+
+	repo.EXPECT().DeleteBefore(mock.Anything, before, 1000).Return(1000, nil).Twice()
+	repo.EXPECT().DeleteBefore(mock.Anything, before, 1000).Return(3, nil).Once()
+
+	deleted, err := svc.Cleanup(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2003, deleted) // The short batch ends the cleanup
+}
+
+func TestChangeCleanup_Unit_Fails(t *testing.T) {
+	repo := mocks.NewMockChangeRepository(t)
+	svc := service.NewChangeService(repo, 24*time.Hour, discardLogger{})
+	now := time.Now()
+
+	repo.EXPECT().DeleteBefore(mock.Anything, now.Add(-24*time.Hour), 1000).Return(1000, nil).Once()
+	repo.EXPECT().DeleteBefore(mock.Anything, now.Add(-24*time.Hour), 1000).Return(0, assert.AnError).Once()
+
+	deleted, err := svc.Cleanup(context.Background(), now)
+
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1000, deleted, "changes of earlier batches stay deleted")
+}
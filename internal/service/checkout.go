@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/telemetry"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// ErrQuantityLimit is returned when an order line orders fewer or more items than checkouts allow.
+var ErrQuantityLimit = errors.New("ordered quantity is outside the allowed range")
+
+// Names of the built-in checkout validators.
+const (
+	CheckoutValidatorAvailability   = "availability"    // Products are published and not archived
+	CheckoutValidatorAgeRestriction = "age_restriction" // The buyer meets the age restrictions of products and bundle components
+	CheckoutValidatorQuantity       = "quantity"        // Order lines are within the configured quantity limits
+	CheckoutValidatorStock          = "stock"           // Stock covers the ordered products and bundle components
+)
+
+// DefaultCheckoutValidators lists the built-in checkout validators in the order they run by default.
+var DefaultCheckoutValidators = []string{
+	CheckoutValidatorAvailability,
+	CheckoutValidatorAgeRestriction,
+	CheckoutValidatorQuantity,
+	CheckoutValidatorStock,
+}
+
+// requiredCheckoutValidators cannot be left out: the order transaction relies on their checks.
+var requiredCheckoutValidators = []string{CheckoutValidatorAvailability, CheckoutValidatorStock}
+
+// Checkout is an order being reserved, as checkout validators see it: the buyer and the ordered products,
+// locked in the reservation transaction, and the chosen order options.
+type Checkout struct {
+	Buyer   *domain.User
+	Lines   []CheckoutLine
+	Options []domain.OrderOption
+}
+
+// CheckoutLine is an ordered product with the quantity ordered. Lines of bundles list the components
+// with the quantities allocated of them.
+type CheckoutLine struct {
+	Product    *domain.Product
+	Quantity   int
+	Components []CheckoutLine // Empty for products that are not bundles
+}
+
+// CheckoutValidator checks a business rule of checkouts before stock is allocated, e.g. the buyer's age.
+// Validators see the locked products and must not modify the checkout.
+type CheckoutValidator interface {
+	// Name returns the name the validator is enabled with, e.g. "stock".
+	Name() string
+	// Validate returns an error if the checkout breaks the rule, which fails the checkout.
+	Validate(ctx context.Context, c *Checkout) error
+}
+
+// CheckoutPipeline runs checkout validators in order, the first failing validator fails the checkout.
+type CheckoutPipeline struct {
+	validators []CheckoutValidator
+}
+
+// NewCheckoutPipeline creates a pipeline running the validators in the given order.
+func NewCheckoutPipeline(validators ...CheckoutValidator) *CheckoutPipeline {
+	return &CheckoutPipeline{validators: validators}
+}
+
+// QuantityLimits bound the items an order line may order, 0 means no limit.
+type QuantityLimits struct {
+	Min int
+	Max int
+}
+
+// NewCheckoutPipelineOf creates a pipeline of the named built-in validators in the given order.
+// The availability and stock validators are required.
+func NewCheckoutPipelineOf(names []string, limits QuantityLimits) (*CheckoutPipeline, error) {
+	for _, name := range requiredCheckoutValidators {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("checkout validator %q is required", name)
+		}
+	}
+	validators := make([]CheckoutValidator, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case CheckoutValidatorAvailability:
+			validators = append(validators, availabilityValidator{})
+		case CheckoutValidatorAgeRestriction:
+			validators = append(validators, ageRestrictionValidator{})
+		case CheckoutValidatorQuantity:
+			validators = append(validators, quantityValidator{limits: limits})
+		case CheckoutValidatorStock:
+			validators = append(validators, stockValidator{})
+		default:
+			return nil, fmt.Errorf("unknown checkout validator %q", name)
+		}
+	}
+	return NewCheckoutPipeline(validators...), nil
+}
+
+// Names returns the names of the validators in the order they run.
+func (p *CheckoutPipeline) Names() []string {
+	names := make([]string, len(p.validators))
+	for i, v := range p.validators {
+		names[i] = v.Name()
+	}
+	return names
+}
+
+// Validate runs the validators in order and returns the error of the first failing one.
+func (p *CheckoutPipeline) Validate(ctx context.Context, c *Checkout) error {
+	for _, v := range p.validators {
+		if err := v.Validate(ctx, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// availabilityValidator fails checkouts of products that are not orderable. Bundle components are not checked,
+// they are sold as part of the bundle.
+type availabilityValidator struct{}
+
+func (availabilityValidator) Name() string { return CheckoutValidatorAvailability }
+
+func (availabilityValidator) Validate(_ context.Context, c *Checkout) error {
+	for _, line := range c.Lines {
+		if !line.Product.IsOrderable() {
+			return fmt.Errorf("%w: product %s is %s", ErrProductUnavailable, line.Product.ID, line.Product.Status)
+		}
+	}
+	return nil
+}
+
+// ageRestrictionValidator fails checkouts of buyers younger than the age restriction of a product
+// or of a component of an ordered bundle.
+type ageRestrictionValidator struct{}
+
+func (ageRestrictionValidator) Name() string { return CheckoutValidatorAgeRestriction }
+
+func (ageRestrictionValidator) Validate(_ context.Context, c *Checkout) error {
+	age := c.Buyer.Age()
+	for _, line := range c.Lines {
+		for _, product := range append([]*domain.Product{line.Product}, componentProducts(line)...) {
+			if product.IsAgeRestricted() && age < product.AgeRestriction {
+				return fmt.Errorf("%w: product %s requires age %d", ErrAgeRestricted, product.ID, product.AgeRestriction)
+			}
+		}
+	}
+	return nil
+}
+
+// quantityValidator fails checkouts with lines ordering fewer or more items than the limits allow.
+type quantityValidator struct {
+	limits QuantityLimits
+}
+
+func (quantityValidator) Name() string { return CheckoutValidatorQuantity }
+
+func (v quantityValidator) Validate(_ context.Context, c *Checkout) error {
+	for _, line := range c.Lines {
+		if line.Quantity < v.limits.Min || (v.limits.Max > 0 && line.Quantity > v.limits.Max) {
+			return fmt.Errorf("%w: %d items of product %s ordered", ErrQuantityLimit, line.Quantity, line.Product.ID)
+		}
+	}
+	return nil
+}
+
+// stockValidator fails checkouts whose products or bundle components lack stock, adding up the quantities
+// of products ordered on several lines or in several bundles. Bundles have no stock of their own.
+type stockValidator struct{}
+
+func (stockValidator) Name() string { return CheckoutValidatorStock }
+
+func (stockValidator) Validate(ctx context.Context, c *Checkout) error {
+	demand := make(map[uuid.UUID]int)
+	for _, line := range c.Lines {
+		stocked := line.Components
+		if len(stocked) == 0 {
+			stocked = []CheckoutLine{line}
+		}
+		for _, l := range stocked {
+			demand[l.Product.ID] += l.Quantity
+			if l.Product.Quantity < demand[l.Product.ID] {
+				telemetry.RecordStockDecrementFailure(ctx, "insufficient_stock")
+				return fmt.Errorf("%w: insufficient stock for product %s", ErrInsufficientStock, l.Product.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// componentProducts returns the component products of a bundle line.
+func componentProducts(line CheckoutLine) []*domain.Product {
+	products := make([]*domain.Product, len(line.Components))
+	for i, c := range line.Components {
+		products[i] = c.Product
+	}
+	return products
+}
@@ -0,0 +1,129 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/domain"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingValidator records that it ran and fails with err.
+type recordingValidator struct {
+	name string
+	err  error
+	ran  *[]string
+}
+
+func (v recordingValidator) Name() string { return v.name }
+
+func (v recordingValidator) Validate(context.Context, *service.Checkout) error {
+	*v.ran = append(*v.ran, v.name)
+	return v.err
+}
+
+func TestCheckoutPipeline_Unit_RunsInOrderUntilFailure(t *testing.T) {
+	var ran []string
+	errFraud := errors.New("suspected fraud")
+	p := service.NewCheckoutPipeline(
+		recordingValidator{name: "address", ran: &ran},
+		recordingValidator{name: "fraud", err: errFraud, ran: &ran},
+		recordingValidator{name: "coupon", ran: &ran},
+	)
+
+	err := p.Validate(context.Background(), &service.Checkout{})
+	assert.ErrorIs(t, err, errFraud)
+	assert.Equal(t, []string{"address", "fraud"}, ran)
+	assert.Equal(t, []string{"address", "fraud", "coupon"}, p.Names())
+}
+
+func TestNewCheckoutPipelineOf_Unit(t *testing.T) {
+	p, err := service.NewCheckoutPipelineOf([]string{"stock", "availability"}, service.QuantityLimits{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stock", "availability"}, p.Names())
+
+	_, err = service.NewCheckoutPipelineOf([]string{"availability", "age_restriction"}, service.QuantityLimits{})
+	assert.ErrorContains(t, err, `"stock" is required`)
+
+	_, err = service.NewCheckoutPipelineOf([]string{"availability", "stock", "coupon"}, service.QuantityLimits{})
+	assert.ErrorContains(t, err, `unknown checkout validator "coupon"`)
+}
+
+func TestCheckoutPipeline_Unit_BuiltInValidators(t *testing.T) {
+	adult := factory.NewUser()
+	minor := factory.NewUser()
+	minor.Birthdate = time.Now().AddDate(-16, 0, 0)
+
+	product := func(quantity int) *domain.Product {
+		return &domain.Product{ID: uuid.New(), Quantity: quantity, Price: 10, Status: domain.ProductStatusActive}
+	}
+	wine := product(10)
+	wine.AgeRestriction = 18
+	draft := product(10)
+	draft.Status = domain.ProductStatusDraft
+	glass := product(5)
+	giftSet := product(0) // Bundles have no stock of their own
+	giftSet.Components = []domain.BundleComponent{{ProductID: wine.ID, Quantity: 1}, {ProductID: glass.ID, Quantity: 2}}
+	giftSetLine := func(quantity int) service.CheckoutLine {
+		return service.CheckoutLine{Product: giftSet, Quantity: quantity, Components: []service.CheckoutLine{
+			{Product: wine, Quantity: quantity},
+			{Product: glass, Quantity: 2 * quantity},
+		}}
+	}
+
+	p, err := service.NewCheckoutPipelineOf(service.DefaultCheckoutValidators, service.QuantityLimits{Min: 1, Max: 3})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		buyer   *domain.User
+		lines   []service.CheckoutLine
+		wantErr error
+	}{
+		{
+			name:  "valid",
+			buyer: adult,
+			lines: []service.CheckoutLine{{Product: glass, Quantity: 3}, giftSetLine(1)},
+		},
+		{
+			name:    "draft product",
+			buyer:   adult,
+			lines:   []service.CheckoutLine{{Product: draft, Quantity: 1}},
+			wantErr: service.ErrProductUnavailable,
+		},
+		{
+			name:    "age restricted bundle component",
+			buyer:   minor,
+			lines:   []service.CheckoutLine{giftSetLine(1)},
+			wantErr: service.ErrAgeRestricted,
+		},
+		{
+			name:    "over the quantity limit",
+			buyer:   adult,
+			lines:   []service.CheckoutLine{{Product: glass, Quantity: 4}},
+			wantErr: service.ErrQuantityLimit,
+		},
+		{
+			name:    "stock of a product ordered alone and in bundles",
+			buyer:   adult,
+			lines:   []service.CheckoutLine{{Product: glass, Quantity: 2}, giftSetLine(2)},
+			wantErr: service.ErrInsufficientStock,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.Validate(context.Background(), &service.Checkout{Buyer: tt.buyer, Lines: tt.lines})
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrConsentRequired is returned when the user has not accepted the latest legal document versions.
+	ErrConsentRequired = errors.New("acceptance of the latest legal documents is required")
+	// ErrDocumentNotFound is returned when legal document version is not found.
+	ErrDocumentNotFound = errors.New("legal document not found")
+	// ErrDocumentAlreadyExists is returned when publishing a legal document version that already exists.
+	ErrDocumentAlreadyExists = errors.New("legal document version already exists")
+)
+
+// ConsentService provides business logic for legal document publishing and user consent tracking.
+type ConsentService struct {
+	repo repository.ConsentRepository
+}
+
+// NewConsentService creates a new consent service.
+func NewConsentService(repo repository.ConsentRepository) *ConsentService {
+	return &ConsentService{repo: repo}
+}
+
+// PublishDocument publishes a new version of a legal document.
+// Users who have not accepted it will be re-prompted on their next request.
+func (s *ConsentService) PublishDocument(ctx context.Context, docType, version string) (*domain.LegalDocument, error) {
+	doc := &domain.LegalDocument{
+		ID:          uuid.New(),
+		Type:        docType,
+		Version:     version,
+		PublishedAt: time.Now(),
+	}
+
+	if err := s.repo.CreateDocument(ctx, doc); err != nil {
+		if errors.Is(err, repository.ErrDocumentAlreadyExists) {
+			return nil, ErrDocumentAlreadyExists
+		}
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// LatestDocuments returns the current version of each published legal document.
+func (s *ConsentService) LatestDocuments(ctx context.Context) ([]domain.LegalDocument, error) {
+	return s.repo.FindLatestDocuments(ctx)
+}
+
+// ValidateAcceptance checks that accepted (document type -> version) covers
+// the latest version of every published legal document.
+// Returns ErrConsentRequired otherwise.
+func (s *ConsentService) ValidateAcceptance(ctx context.Context, accepted map[string]string) error {
+	docs, err := s.repo.FindLatestDocuments(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		if accepted[doc.Type] != doc.Version {
+			return fmt.Errorf("%w: %s version %s", ErrConsentRequired, doc.Type, doc.Version)
+		}
+	}
+	return nil
+}
+
+// Accept records the user's acceptance of a legal document version.
+// Returns ErrDocumentNotFound if the version was never published.
+func (s *ConsentService) Accept(ctx context.Context, userID uuid.UUID, docType, version string) (*domain.Consent, error) {
+	if _, err := s.repo.FindDocument(ctx, docType, version); err != nil {
+		if errors.Is(err, repository.ErrDocumentNotFound) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, err
+	}
+
+	consent := &domain.Consent{
+		ID:              uuid.New(),
+		UserID:          userID,
+		DocumentType:    docType,
+		DocumentVersion: version,
+		AcceptedAt:      time.Now(),
+	}
+
+	if err := s.repo.CreateConsent(ctx, consent); err != nil {
+		return nil, err
+	}
+
+	return consent, nil
+}
+
+// PendingDocuments returns the latest legal document versions the user has not accepted yet.
+func (s *ConsentService) PendingDocuments(ctx context.Context, userID uuid.UUID) ([]domain.LegalDocument, error) {
+	return s.repo.FindPendingDocuments(ctx, userID)
+}
+
+// History returns all consents given by the user, newest first.
+func (s *ConsentService) History(ctx context.Context, userID uuid.UUID) ([]domain.Consent, error) {
+	return s.repo.FindConsentsByUserID(ctx, userID)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrDeliverySlotNotFound is returned when a delivery slot is not found.
+	ErrDeliverySlotNotFound = errors.New("delivery slot not found")
+	// ErrDeliverySlotExists is returned when the region already has a slot starting at the same time.
+	ErrDeliverySlotExists = errors.New("delivery slot already exists")
+	// ErrDeliverySlotUnavailable is returned when a checkout chooses a delivery slot that is full, has started or does not exist.
+	ErrDeliverySlotUnavailable = errors.New("delivery slot is not available")
+	// ErrDeliverySlotCapacity is returned when the capacity of a slot is reduced below its reserved orders.
+	ErrDeliverySlotCapacity = errors.New("capacity is below the reserved orders of the slot")
+	// ErrInvalidSlotWindow is returned when the listed window ends before it starts or is longer than MaxDeliverySlotWindow.
+	ErrInvalidSlotWindow = errors.New("invalid delivery slot window")
+)
+
+// Delivery slot listing windows.
+const (
+	DefaultDeliverySlotWindow = 14 * 24 * time.Hour // Slots listed when no end is given
+	MaxDeliverySlotWindow     = 62 * 24 * time.Hour // Longest window slots are listed for
+)
+
+// DeliverySlotService manages the delivery windows buyers choose at checkout. Admins configure the slots
+// and their capacity per region; checkouts reserve capacity in the order transaction (see OrderService.CreateOrder).
+type DeliverySlotService struct {
+	repo repository.DeliverySlotRepository
+}
+
+// NewDeliverySlotService creates a new delivery slot service.
+func NewDeliverySlotService(repo repository.DeliverySlotRepository) *DeliverySlotService {
+	return &DeliverySlotService{repo: repo}
+}
+
+// Create adds a delivery slot of the region taking capacity orders.
+// Returns domain.ErrInvalidDeliverySlot and ErrDeliverySlotExists if the region has a slot starting at the same time.
+func (s *DeliverySlotService) Create(ctx context.Context, region string, startsAt, endsAt time.Time, capacity int) (*domain.DeliverySlot, error) {
+	slot := &domain.DeliverySlot{
+		ID:        uuid.New(),
+		Region:    region,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		Capacity:  capacity,
+		CreatedAt: time.Now(),
+	}
+	if err := slot.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, slot); err != nil {
+		if errors.Is(err, repository.ErrDeliverySlotExists) {
+			return nil, ErrDeliverySlotExists
+		}
+		return nil, fmt.Errorf("DeliverySlotService.Create: %w", err)
+	}
+	return slot, nil
+}
+
+// UpdateCapacity changes the number of orders the slot takes. A capacity of zero closes the slot for new orders.
+// Returns ErrDeliverySlotNotFound and ErrDeliverySlotCapacity if the slot has more reserved orders.
+func (s *DeliverySlotService) UpdateCapacity(ctx context.Context, id uuid.UUID, capacity int) (*domain.DeliverySlot, error) {
+	if capacity < 0 {
+		return nil, fmt.Errorf("%w: capacity must not be negative", domain.ErrInvalidDeliverySlot)
+	}
+	slot, err := s.repo.UpdateCapacity(ctx, id, capacity)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDeliverySlotNotFound):
+			return nil, ErrDeliverySlotNotFound
+		case errors.Is(err, repository.ErrDeliverySlotFull):
+			return nil, ErrDeliverySlotCapacity
+		}
+		return nil, fmt.Errorf("DeliverySlotService.UpdateCapacity: %w", err)
+	}
+	return slot, nil
+}
+
+// List returns all slots of the region starting in [from, to), earliest first, including full ones.
+// Returns ErrInvalidSlotWindow.
+func (s *DeliverySlotService) List(ctx context.Context, region string, from, to time.Time) ([]domain.DeliverySlot, error) {
+	if !from.Before(to) || to.Sub(from) > MaxDeliverySlotWindow {
+		return nil, ErrInvalidSlotWindow
+	}
+	slots, err := s.repo.FindByRegion(ctx, region, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("DeliverySlotService.List: %w", err)
+	}
+	return slots, nil
+}
+
+// Available returns the slots of the region buyers can choose: those starting in [from, to) that have not
+// started yet and have capacity left, earliest first. Returns ErrInvalidSlotWindow.
+func (s *DeliverySlotService) Available(ctx context.Context, region string, from, to time.Time) ([]domain.DeliverySlot, error) {
+	if !from.Before(to) || to.Sub(from) > MaxDeliverySlotWindow {
+		return nil, ErrInvalidSlotWindow
+	}
+	available := []domain.DeliverySlot{}
+	if now := time.Now(); from.Before(now) {
+		if !now.Before(to) {
+			return available, nil
+		}
+		from = now
+	}
+	slots, err := s.List(ctx, region, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, slot := range slots {
+		if slot.Available() > 0 {
+			available = append(available, slot)
+		}
+	}
+	return available, nil
+}
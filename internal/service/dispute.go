@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/notification"
+	"product-api/internal/payment"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidWebhook is returned when a webhook is not signed by the payment provider it claims to come from.
+var ErrInvalidWebhook = errors.New("invalid webhook signature")
+
+// DisputeService applies payment provider webhooks about disputes and chargebacks to orders.
+// Refunds of a disputed charge are frozen until the dispute is won (see domain.Order.IsRefundFrozen).
+// Webhooks about the outcome of captures are passed to the order service.
+type DisputeService struct {
+	db          repository.TxBeginner
+	paymentRepo repository.PaymentRepository
+	disputeRepo repository.DisputeRepository
+	orders      *OrderService
+	providers   *payment.Registry
+	admins      notification.AdminNotifier
+	logger      logger.Logger
+}
+
+// NewDisputeService creates a new dispute service.
+func NewDisputeService(db repository.TxBeginner, paymentRepo repository.PaymentRepository, disputeRepo repository.DisputeRepository, orders *OrderService, providers *payment.Registry, admins notification.AdminNotifier, logger logger.Logger) *DisputeService {
+	return &DisputeService{
+		db:          db,
+		paymentRepo: paymentRepo,
+		disputeRepo: disputeRepo,
+		orders:      orders,
+		providers:   providers,
+		admins:      admins,
+		logger:      logger,
+	}
+}
+
+// HandleWebhook verifies a webhook of the named payment provider and records the dispute it reports
+// on the disputed order. Admins are notified when a dispute is opened or its status changes.
+// Capture outcomes confirm or decline pending payments of orders (see OrderService.ConfirmCapture), including those
+// of sandbox orders paid with the sandbox provider.
+// Other events and disputes of unknown payments are acknowledged and ignored, so the provider stops resending them.
+// Returns ErrUnknownPaymentProvider if the provider is not configured and ErrInvalidWebhook if the signature is invalid.
+func (s *DisputeService) HandleWebhook(ctx context.Context, providerName string, header http.Header, body []byte) error {
+	const op = "DisputeService.HandleWebhook"
+	log := s.logger.WithTrace(ctx)
+
+	provider, ok := s.providers.Lookup(providerName)
+	if !ok || providerName == "" {
+		return fmt.Errorf("%w: %q", ErrUnknownPaymentProvider, providerName)
+	}
+	event, err := provider.VerifyWebhook(ctx, header, body)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			return ErrInvalidWebhook
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if event.Capture != nil {
+		return s.orders.ConfirmCapture(ctx, providerName, event.Capture)
+	}
+	if event.Dispute == nil {
+		log.Debug("payment webhook ignored", "op", op, "provider", providerName, "event_id", event.ID, "type", event.Type)
+		return nil
+	}
+
+	charge, err := s.paymentRepo.FindChargeByReference(ctx, providerName, event.Dispute.CaptureID)
+	if err != nil {
+		if errors.Is(err, repository.ErrPaymentNotFound) {
+			log.Warn("dispute of unknown payment", "op", op, "provider", providerName, "dispute_id", event.Dispute.ID,
+				"capture_id", event.Dispute.CaptureID)
+			return nil
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	dispute := &domain.Dispute{
+		ID:                uuid.New(),
+		OrderID:           charge.OrderID,
+		PaymentID:         charge.ID,
+		Provider:          providerName,
+		ProviderDisputeID: event.Dispute.ID,
+		Status:            event.Dispute.Status,
+		Reason:            event.Dispute.Reason,
+		Amount:            event.Dispute.Amount.Major(),
+		UpdatedAt:         time.Now(),
+	}
+	previous, err := s.recordDispute(ctx, dispute)
+	if err != nil {
+		return err
+	}
+	if previous == dispute.Status {
+		return nil // Repeated delivery or an update not changing the status
+	}
+
+	log.Warn("payment dispute", "op", op, "order_id", dispute.OrderID, "payment_id", dispute.PaymentID,
+		"dispute_id", dispute.ProviderDisputeID, "status", dispute.Status, "previous_status", previous)
+	msg := notification.Message{
+		Category: domain.NotificationCategoryOrderUpdates,
+		Subject:  fmt.Sprintf("Payment dispute of order %s is %s", dispute.OrderID, dispute.Status),
+		Body: fmt.Sprintf("%s dispute %s of %s (reason: %s) is %s. Refunds of the payment are frozen unless the dispute is won.",
+			providerName, dispute.ProviderDisputeID, event.Dispute.Amount, dispute.Reason, dispute.Status),
+	}
+	if err := s.admins.NotifyAdmins(ctx, msg); err != nil {
+		// The dispute is recorded, the alert is not worth a webhook retry
+		log.Error("failed to notify admins of dispute", "op", op, "order_id", dispute.OrderID, "error", err)
+	}
+	return nil
+}
+
+// recordDispute upserts the dispute while holding the order lock, so it cannot interleave with refunds of the order.
+// Returns the status before the update, empty for new disputes.
+func (s *DisputeService) recordDispute(ctx context.Context, dispute *domain.Dispute) (_ string, err error) {
+	const op = "DisputeService.recordDispute"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%s: could not begin transaction: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				s.logger.Error("error rolling back transaction", "rollback_error", rbErr, "original_error", err)
+			}
+		}
+	}()
+
+	if _, err = s.paymentRepo.FindByOrderIDTx(ctx, tx, dispute.OrderID); err != nil {
+		return "", fmt.Errorf("%s: lock order: %w", op, err)
+	}
+	previous, err := s.disputeRepo.UpsertTx(ctx, tx, dispute)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("%s: could not commit transaction: %w", op, err)
+	}
+	return previous, nil
+}
@@ -0,0 +1,78 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"product-api/internal/domain"
+	"product-api/internal/notification"
+	notificationmocks "product-api/internal/notification/mocks"
+	"product-api/internal/payment"
+	"product-api/internal/repository"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const disputeWebhook = `{"id":"evt_1","type":"dispute","resource":{"id":"dp_1","capture_id":"cap_1","status":"open","reason":"fraudulent","amount":1050,"currency":"USD"}}`
+
+func newDisputeServiceWithMocks(t *testing.T) (*service.DisputeService, *payment.MemoryProvider, *orderServiceMocks, *notificationmocks.MockAdminNotifier) {
+	m := &orderServiceMocks{
+		db:       mocks.NewMockTxBeginner(t),
+		tx:       mocks.NewMockTx(t),
+		ledger:   mocks.NewMockPaymentRepository(t),
+		disputes: mocks.NewMockDisputeRepository(t),
+	}
+	admins := notificationmocks.NewMockAdminNotifier(t)
+	provider := payment.NewMemoryProvider("secret", discardLogger{})
+	svc := service.NewDisputeService(m.db, m.ledger, m.disputes, nil, payment.NewRegistry(provider), admins, discardLogger{})
+	return svc, provider, m, admins
+}
+
+func TestDisputeWebhook_Unit_NotifiesAdminsOfStatusChanges(t *testing.T) {
+	svc, provider, m, admins := newDisputeServiceWithMocks(t)
+	charge := &domain.Payment{ID: uuid.New(), OrderID: uuid.New(), Kind: domain.PaymentKindCharge, Provider: "mock", Reference: "cap_1"}
+	body := []byte(disputeWebhook)
+
+	m.ledger.EXPECT().FindChargeByReference(mock.Anything, "mock", "cap_1").Return(charge, nil)
+	m.db.EXPECT().Begin(mock.Anything).Return(m.tx, nil)
+	m.ledger.EXPECT().FindByOrderIDTx(mock.Anything, m.tx, charge.OrderID).Return([]domain.Payment{*charge}, nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil)
+	m.disputes.EXPECT().UpsertTx(mock.Anything, m.tx, mock.MatchedBy(func(d *domain.Dispute) bool {
+		return d.OrderID == charge.OrderID && d.PaymentID == charge.ID && d.ProviderDisputeID == "dp_1" &&
+			d.Status == domain.DisputeStatusOpen && d.Amount == 10.5
+	})).Return("", nil).Once()
+	admins.EXPECT().NotifyAdmins(mock.Anything, mock.MatchedBy(func(msg notification.Message) bool {
+		return strings.Contains(msg.Subject, charge.OrderID.String()) && strings.Contains(msg.Body, "10.50 USD")
+	})).Return(errors.New("smtp down")).Once()
+
+	require.NoError(t, svc.HandleWebhook(context.Background(), "mock", provider.SignWebhook(body), body),
+		"failed alerts do not fail the webhook")
+
+	// Resent by the provider: recorded again, admins are not notified twice
+	m.disputes.EXPECT().UpsertTx(mock.Anything, m.tx, mock.Anything).Return(domain.DisputeStatusOpen, nil).Once()
+	require.NoError(t, svc.HandleWebhook(context.Background(), "mock", provider.SignWebhook(body), body))
+}
+
+func TestDisputeWebhook_Unit_Ignored(t *testing.T) {
+	svc, provider, m, _ := newDisputeServiceWithMocks(t)
+	ctx := context.Background()
+	body := []byte(disputeWebhook)
+
+	err := svc.HandleWebhook(ctx, "mock", http.Header{}, body)
+	assert.ErrorIs(t, err, service.ErrInvalidWebhook)
+	err = svc.HandleWebhook(ctx, "paypal", provider.SignWebhook(body), body)
+	assert.ErrorIs(t, err, service.ErrUnknownPaymentProvider)
+
+	other := []byte(`{"id":"evt_2","type":"capture.completed","resource":{}}`)
+	require.NoError(t, svc.HandleWebhook(ctx, "mock", provider.SignWebhook(other), other))
+
+	m.ledger.EXPECT().FindChargeByReference(mock.Anything, "mock", "cap_1").Return(nil, repository.ErrPaymentNotFound)
+	require.NoError(t, svc.HandleWebhook(ctx, "mock", provider.SignWebhook(body), body), "disputes of unknown payments are acknowledged")
+}
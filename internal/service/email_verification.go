@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"product-api/internal/domain"
+	"product-api/internal/notification"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrEmailAlreadyVerified is returned when a verification email is requested for a verified address.
+	ErrEmailAlreadyVerified = errors.New("email address is already verified")
+	// ErrEmailVerificationInvalid is returned when a verification link is unknown, was replaced by a newer one
+	// or was sent to an address the user no longer has.
+	ErrEmailVerificationInvalid = errors.New("invalid verification link")
+	// ErrEmailVerificationExpired is returned when a verification link expired.
+	ErrEmailVerificationExpired = errors.New("verification link expired, request a new one")
+)
+
+// emailVerificationTTL is the validity of verification links.
+const emailVerificationTTL = 48 * time.Hour
+
+// EmailVerificationService verifies the email addresses of users with links sent to them.
+type EmailVerificationService struct {
+	users         repository.UserRepository
+	verifications repository.EmailVerificationRepository
+	templates     *notification.Templates
+	email         notification.Sender
+	verifyURL     string
+}
+
+// NewEmailVerificationService creates a new email verification service sending links to verifyURL
+// through the email sender, e.g. "https://shop.example.com/users/email/verify".
+func NewEmailVerificationService(users repository.UserRepository, verifications repository.EmailVerificationRepository, templates *notification.Templates, email notification.Sender, verifyURL string) *EmailVerificationService {
+	return &EmailVerificationService{users: users, verifications: verifications, templates: templates, email: email, verifyURL: verifyURL}
+}
+
+// Send sends a verification link to the user's email address, replacing the link sent before.
+// Verification emails are sent regardless of notification preferences.
+// Returns ErrUserNotFound and ErrEmailAlreadyVerified.
+func (s *EmailVerificationService) Send(ctx context.Context, userID uuid.UUID) (*domain.EmailVerification, error) {
+	const op = "EmailVerificationService.Send"
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if user.HasVerifiedEmail() {
+		return nil, ErrEmailAlreadyVerified
+	}
+
+	token, err := emailVerificationToken()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	now := time.Now()
+	verification := &domain.EmailVerification{
+		UserID:    userID,
+		Email:     user.Email,
+		TokenHash: hashEmailVerificationToken(token),
+		ExpiresAt: now.Add(emailVerificationTTL),
+		CreatedAt: now,
+	}
+	if err := s.verifications.Upsert(ctx, verification); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	msg, err := s.templates.Render(ctx, notification.TemplateEmailVerification, user.Locale, user, map[string]string{
+		"VerificationURL": s.verifyURL + "?" + url.Values{"token": {token}}.Encode(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := s.email.Send(ctx, user, msg); err != nil {
+		return nil, fmt.Errorf("%s: send link: %w", op, err)
+	}
+	return verification, nil
+}
+
+// Verify marks the email address the link was sent to as verified.
+// Returns the updated user, ErrEmailVerificationInvalid and ErrEmailVerificationExpired.
+func (s *EmailVerificationService) Verify(ctx context.Context, token string) (*domain.User, error) {
+	const op = "EmailVerificationService.Verify"
+
+	verification, err := s.verifications.FindByTokenHash(ctx, hashEmailVerificationToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrEmailVerificationNotFound) {
+			return nil, ErrEmailVerificationInvalid
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if time.Now().After(verification.ExpiresAt) {
+		return nil, ErrEmailVerificationExpired
+	}
+
+	// The user may have changed the email since the link was sent
+	if err := s.users.MarkEmailVerified(ctx, verification.UserID, verification.Email, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrEmailVerificationInvalid
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := s.verifications.Delete(ctx, verification.UserID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.users.FindByID(ctx, verification.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
+
+// emailVerificationToken generates a random token of a verification link.
+func emailVerificationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate verification token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashEmailVerificationToken returns the hex-encoded SHA-256 hash of a verification token.
+func hashEmailVerificationToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,96 @@
+package service_test
+
+import (
+	"context"
+	"net/url"
+	"product-api/internal/domain"
+	"product-api/internal/notification"
+	notificationmocks "product-api/internal/notification/mocks"
+	"product-api/internal/repository"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type emailVerificationServiceMocks struct {
+	users         *mocks.MockUserRepository
+	verifications *mocks.MockEmailVerificationRepository
+	email         *notificationmocks.MockSender
+}
+
+func newEmailVerificationServiceWithMocks(t *testing.T) (*service.EmailVerificationService, *emailVerificationServiceMocks) {
+	m := &emailVerificationServiceMocks{
+		users:         mocks.NewMockUserRepository(t),
+		verifications: mocks.NewMockEmailVerificationRepository(t),
+		email:         notificationmocks.NewMockSender(t),
+	}
+	templateRepo := mocks.NewMockNotificationTemplateRepository(t)
+	templateRepo.EXPECT().FindLatest(mock.Anything).Return(nil, nil).Maybe()
+	templates := notification.NewTemplates(templateRepo, time.Hour, discardLogger{})
+	return service.NewEmailVerificationService(m.users, m.verifications, templates, m.email, "https://shop.example.com/users/email/verify"), m
+}
+
+func TestEmailVerificationService_Unit_VerifiesSentLink(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newEmailVerificationServiceWithMocks(t)
+	user := &domain.User{ID: uuid.New(), Firstname: "Ada", Email: "ada@example.com"}
+
+	m.users.EXPECT().FindByID(mock.Anything, user.ID).Return(user, nil).Once()
+	var stored *domain.EmailVerification
+	m.verifications.EXPECT().Upsert(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, v *domain.EmailVerification) { stored = v }).Return(nil).Once()
+	var token string
+	m.email.EXPECT().Send(mock.Anything, user, mock.Anything).Run(func(_ context.Context, _ *domain.User, msg notification.Message) {
+		link, err := url.Parse(regexp.MustCompile(`https://\S+[^.\s]`).FindString(msg.Body))
+		require.NoError(t, err)
+		assert.Equal(t, "/users/email/verify", link.Path)
+		token = link.Query().Get("token")
+	}).Return(nil).Once()
+
+	_, err := svc.Send(ctx, user.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, token, "link is sent to the user")
+	assert.Equal(t, "ada@example.com", stored.Email)
+	assert.NotEqual(t, token, stored.TokenHash, "tokens are stored hashed")
+
+	m.verifications.EXPECT().FindByTokenHash(mock.Anything, stored.TokenHash).Return(stored, nil).Once()
+	m.users.EXPECT().MarkEmailVerified(mock.Anything, user.ID, "ada@example.com", mock.AnythingOfType("time.Time")).Return(nil).Once()
+	m.verifications.EXPECT().Delete(mock.Anything, user.ID).Return(nil).Once()
+	m.users.EXPECT().FindByID(mock.Anything, user.ID).Return(user, nil).Once()
+	_, err = svc.Verify(ctx, token)
+	require.NoError(t, err)
+}
+
+func TestEmailVerificationService_Unit_RejectsStaleLinks(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newEmailVerificationServiceWithMocks(t)
+	userID := uuid.New()
+
+	m.verifications.EXPECT().FindByTokenHash(mock.Anything, mock.Anything).Return(nil, repository.ErrEmailVerificationNotFound).Once()
+	_, err := svc.Verify(ctx, "unknown")
+	assert.ErrorIs(t, err, service.ErrEmailVerificationInvalid)
+
+	expired := &domain.EmailVerification{UserID: userID, Email: "ada@example.com", ExpiresAt: time.Now().Add(-time.Minute)}
+	m.verifications.EXPECT().FindByTokenHash(mock.Anything, mock.Anything).Return(expired, nil).Once()
+	_, err = svc.Verify(ctx, "expired")
+	assert.ErrorIs(t, err, service.ErrEmailVerificationExpired)
+
+	// The user changed the email after the link was sent
+	pending := &domain.EmailVerification{UserID: userID, Email: "ada@example.com", ExpiresAt: time.Now().Add(time.Hour)}
+	m.verifications.EXPECT().FindByTokenHash(mock.Anything, mock.Anything).Return(pending, nil).Once()
+	m.users.EXPECT().MarkEmailVerified(mock.Anything, userID, "ada@example.com", mock.Anything).Return(repository.ErrUserNotFound).Once()
+	_, err = svc.Verify(ctx, "changed")
+	assert.ErrorIs(t, err, service.ErrEmailVerificationInvalid)
+
+	verifiedAt := time.Now()
+	m.users.EXPECT().FindByID(mock.Anything, userID).Return(&domain.User{ID: userID, EmailVerifiedAt: &verifiedAt}, nil).Once()
+	_, err = svc.Send(ctx, userID)
+	assert.ErrorIs(t, err, service.ErrEmailAlreadyVerified)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"time"
+)
+
+var (
+	// ErrIdempotencyKeyInProgress is returned when the original request with the key has not completed yet.
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is in progress")
+	// ErrIdempotencyKeyReused is returned when the key was already used with a different request body.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used with a different request")
+)
+
+// idempotencyCleanupBatch is the number of expired keys deleted per statement, so a cleanup
+// does not hold the locks of a day of keys at once.
+const idempotencyCleanupBatch = 1000
+
+// IdempotencyService deduplicates retried write requests by their Idempotency-Key.
+// The first request with a key is executed and its response stored for ttl;
+// retries within ttl get the stored response. Expired keys are deleted by the cleanup runner.
+type IdempotencyService struct {
+	repo   repository.IdempotencyRepository
+	ttl    time.Duration
+	logger logger.Logger
+}
+
+// NewIdempotencyService creates a new idempotency service.
+func NewIdempotencyService(repo repository.IdempotencyRepository, ttl time.Duration, logger logger.Logger) *IdempotencyService {
+	return &IdempotencyService{repo: repo, ttl: ttl, logger: logger}
+}
+
+// Run deletes expired keys every interval until ctx is done. Each cleanup is recorded by hb.
+func (s *IdempotencyService) Run(ctx context.Context, interval time.Duration, hb *Heartbeat) {
+	const op = "IdempotencyService.Run"
+
+	for {
+		deleted, err := s.Cleanup(ctx, time.Now())
+		hb.Beat(err)
+		if err != nil {
+			s.logger.Error("failed to delete expired idempotency keys", "op", op, "deleted", deleted, "error", err)
+		} else if deleted > 0 {
+			s.logger.Info("expired idempotency keys deleted", "op", op, "deleted", deleted)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Cleanup deletes the keys expired before now, in batches, and returns the number of deleted keys.
+// Keys taken over by a new request in the meantime are kept.
+func (s *IdempotencyService) Cleanup(ctx context.Context, now time.Time) (int, error) {
+	deleted := 0
+	for {
+		n, err := s.repo.DeleteExpired(ctx, now, idempotencyCleanupBatch)
+		deleted += n
+		if err != nil {
+			return deleted, fmt.Errorf("IdempotencyService.Cleanup: %w", err)
+		}
+		if n < idempotencyCleanupBatch || ctx.Err() != nil {
+			return deleted, nil
+		}
+	}
+}
+
+// Begin reserves the key for a request within the scope.
+// Returns nil if the request should be executed, or the stored record whose response should be replayed.
+// Returns ErrIdempotencyKeyInProgress if the original request is still running
+// and ErrIdempotencyKeyReused if the key was used with a different request body.
+func (s *IdempotencyService) Begin(ctx context.Context, scope, key, requestHash string) (*domain.IdempotencyRecord, error) {
+	const op = "IdempotencyService.Begin"
+
+	now := time.Now()
+	err := s.repo.Create(ctx, &domain.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	})
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, repository.ErrIdempotencyKeyExists) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	existing, err := s.repo.Find(ctx, scope, key)
+	if err != nil {
+		if errors.Is(err, repository.ErrIdempotencyKeyNotFound) {
+			// Released by a failed original request in the meantime
+			return nil, ErrIdempotencyKeyInProgress
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	switch {
+	case existing.RequestHash != requestHash:
+		return nil, ErrIdempotencyKeyReused
+	case !existing.IsCompleted():
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	return existing, nil
+}
+
+// Complete stores the response of the request executed after Begin.
+// Server errors are not stored: the key is released, so the client can retry.
+func (s *IdempotencyService) Complete(ctx context.Context, scope, key string, statusCode int, contentType string, body []byte) error {
+	const op = "IdempotencyService.Complete"
+
+	if statusCode >= 500 {
+		return s.Release(ctx, scope, key)
+	}
+
+	err := s.repo.Complete(ctx, &domain.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		StatusCode:  statusCode,
+		ContentType: contentType,
+		Body:        body,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Release frees the key of a request that did not complete, so it can be retried.
+func (s *IdempotencyService) Release(ctx context.Context, scope, key string) error {
+	if err := s.repo.Delete(ctx, scope, key); err != nil {
+		return fmt.Errorf("IdempotencyService.Release: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/repository/postgres"
+	"product-api/internal/service"
+	"product-api/internal/testutil/testdb"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const idempotencyScope = "user:1 POST /orders"
+
+func TestIdempotencyService_Begin_NewKey(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(t)
+	svc := service.NewIdempotencyService(repo, time.Hour, discardLogger{})
+
+	repo.EXPECT().Create(mock.Anything, mock.MatchedBy(func(r *domain.IdempotencyRecord) bool {
+		return r.Key == "k1" && r.RequestHash == "h1" && r.ExpiresAt.Sub(r.CreatedAt) == time.Hour
+	})).Return(nil)
+
+	stored, err := svc.Begin(context.Background(), idempotencyScope, "k1", "h1")
+
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func TestIdempotencyService_Begin_Replay(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(t)
+	svc := service.NewIdempotencyService(repo, time.Hour, discardLogger{})
+	completed := &domain.IdempotencyRecord{Scope: idempotencyScope, Key: "k1", RequestHash: "h1", StatusCode: 201, Body: []byte(`{}`)}
+
+	repo.EXPECT().Create(mock.Anything, mock.Anything).Return(repository.ErrIdempotencyKeyExists)
+	repo.EXPECT().Find(mock.Anything, idempotencyScope, "k1").Return(completed, nil)
+
+	stored, err := svc.Begin(context.Background(), idempotencyScope, "k1", "h1")
+
+	require.NoError(t, err)
+	assert.Equal(t, completed, stored)
+}
+
+func TestIdempotencyService_Begin_Conflicts(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(t)
+	svc := service.NewIdempotencyService(repo, time.Hour, discardLogger{})
+	inProgress := &domain.IdempotencyRecord{Scope: idempotencyScope, Key: "k1", RequestHash: "h1"}
+
+	repo.EXPECT().Create(mock.Anything, mock.Anything).Return(repository.ErrIdempotencyKeyExists)
+	repo.EXPECT().Find(mock.Anything, idempotencyScope, "k1").Return(inProgress, nil)
+
+	_, err := svc.Begin(context.Background(), idempotencyScope, "k1", "h1")
+	assert.ErrorIs(t, err, service.ErrIdempotencyKeyInProgress)
+
+	_, err = svc.Begin(context.Background(), idempotencyScope, "k1", "h2")
+	assert.ErrorIs(t, err, service.ErrIdempotencyKeyReused)
+}
+
+func TestIdempotencyService_Complete_ServerErrorReleasesKey(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(t)
+	svc := service.NewIdempotencyService(repo, time.Hour, discardLogger{})
+
+	repo.EXPECT().Delete(mock.Anything, idempotencyScope, "k1").Return(nil)
+
+	err := svc.Complete(context.Background(), idempotencyScope, "k1", 503, "text/plain", []byte("unavailable"))
+
+	require.NoError(t, err)
+	// No response is expected to be stored by the mock
+}
+
+func TestIdempotencyService_Cleanup_DeletesInBatches(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(t)
+	svc := service.NewIdempotencyService(repo, time.Hour, discardLogger{})
+	now := time.Now()
+
+	repo.EXPECT().DeleteExpired(mock.Anything, now, 1000).Return(1000, nil).Twice()
+	repo.EXPECT().DeleteExpired(mock.Anything, now, 1000).Return(12, nil).Once()
+
+	deleted, err := svc.Cleanup(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2012, deleted) // The short batch ends the cleanup
+}
+
+func TestIdempotencyService_Cleanup_Fails(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(t)
+	svc := service.NewIdempotencyService(repo, time.Hour, discardLogger{})
+	now := time.Now()
+
+	repo.EXPECT().DeleteExpired(mock.Anything, now, 1000).Return(1000, nil).Once()
+	repo.EXPECT().DeleteExpired(mock.Anything, now, 1000).Return(0, assert.AnError).Once()
+
+	deleted, err := svc.Cleanup(context.Background(), now)
+
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1000, deleted, "keys of earlier batches stay deleted")
+}
+
+type IdempotencyCleanupTestSuite struct {
+	suite.Suite
+	repo    *postgres.IdempotencyRepository
+	service *service.IdempotencyService
+}
+
+func (s *IdempotencyCleanupTestSuite) SetupTest() {
+	s.repo = postgres.NewIdempotencyRepository(testdb.New(s.T()))
+	s.service = service.NewIdempotencyService(s.repo, time.Hour, discardLogger{})
+}
+
+func (s *IdempotencyCleanupTestSuite) TestCleanup() {
+	ctx := context.Background()
+	_, err := s.service.Begin(ctx, idempotencyScope, "current", "h1")
+	s.Require().NoError(err)
+	now := time.Now()
+	for _, key := range []string{"expired", "taken-over"} {
+		s.Require().NoError(s.repo.Create(ctx, &domain.IdempotencyRecord{
+			Scope: idempotencyScope, Key: key, RequestHash: "h1", CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour),
+		}))
+	}
+	// A retry after the key expired takes it over
+	_, err = s.service.Begin(ctx, idempotencyScope, "taken-over", "h2")
+	s.Require().NoError(err)
+
+	deleted, err := s.service.Cleanup(ctx, time.Now())
+	s.Require().NoError(err)
+	s.Equal(1, deleted)
+
+	_, err = s.repo.Find(ctx, idempotencyScope, "expired")
+	s.ErrorIs(err, repository.ErrIdempotencyKeyNotFound)
+	for _, key := range []string{"current", "taken-over"} {
+		_, err = s.repo.Find(ctx, idempotencyScope, key)
+		s.NoError(err, key)
+	}
+}
+
+func TestIdempotencyCleanupTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyCleanupTestSuite))
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInboxNotificationNotFound is returned when a notification is not in the user's inbox.
+var ErrInboxNotificationNotFound = errors.New("notification not found")
+
+// InboxPage is a page of the user's inbox.
+type InboxPage struct {
+	Notifications []domain.InboxNotification // Newest first
+	Total         int                        // Number of notifications matching the filter
+	Unread        int                        // Number of unread notifications
+}
+
+// InboxService provides the inbox of notifications in the web app.
+// Notifications are delivered to it by the notification dispatcher through the in_app channel.
+type InboxService struct {
+	repo repository.InboxRepository
+}
+
+// NewInboxService creates a new inbox service.
+func NewInboxService(repo repository.InboxRepository) *InboxService {
+	return &InboxService{repo: repo}
+}
+
+// List returns a page of the user's notifications, only unread ones if unreadOnly is set.
+func (s *InboxService) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, offset, limit int) (*InboxPage, error) {
+	notifications, total, err := s.repo.List(ctx, userID, unreadOnly, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("InboxService.List: %w", err)
+	}
+	unread, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("InboxService.List: %w", err)
+	}
+	if notifications == nil {
+		notifications = []domain.InboxNotification{}
+	}
+	return &InboxPage{Notifications: notifications, Total: total, Unread: unread}, nil
+}
+
+// UnreadCount returns the number of unread notifications of the user.
+func (s *InboxService) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("InboxService.UnreadCount: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks the user's notification read. Marking a read notification again keeps the time it was read.
+// Returns ErrInboxNotificationNotFound.
+func (s *InboxService) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.repo.MarkRead(ctx, userID, id, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrInboxNotificationNotFound) {
+			return ErrInboxNotificationNotFound
+		}
+		return fmt.Errorf("InboxService.MarkRead: %w", err)
+	}
+	return nil
+}
+
+// MarkAllRead marks all unread notifications of the user read.
+func (s *InboxService) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	if err := s.repo.MarkAllRead(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("InboxService.MarkAllRead: %w", err)
+	}
+	return nil
+}
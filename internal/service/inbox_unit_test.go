@@ -0,0 +1,39 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/repository"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboxService_Unit_List(t *testing.T) {
+	repo := mocks.NewMockInboxRepository(t)
+	svc := service.NewInboxService(repo)
+	userID := uuid.New()
+
+	repo.EXPECT().List(mock.Anything, userID, true, 20, 10).Return(nil, 23, nil)
+	repo.EXPECT().CountUnread(mock.Anything, userID).Return(23, nil)
+
+	page, err := svc.List(context.Background(), userID, true, 20, 10)
+	require.NoError(t, err)
+	assert.NotNil(t, page.Notifications, "empty pages encode as an empty list")
+	assert.Equal(t, 23, page.Total)
+	assert.Equal(t, 23, page.Unread)
+}
+
+func TestInboxService_Unit_MarkReadOfOtherUser(t *testing.T) {
+	repo := mocks.NewMockInboxRepository(t)
+	svc := service.NewInboxService(repo)
+	userID, id := uuid.New(), uuid.New()
+
+	repo.EXPECT().MarkRead(mock.Anything, userID, id, mock.Anything).Return(repository.ErrInboxNotificationNotFound)
+
+	assert.ErrorIs(t, svc.MarkRead(context.Background(), userID, id), service.ErrInboxNotificationNotFound)
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/money"
+	"product-api/internal/notification"
+	"product-api/internal/repository"
+	"product-api/internal/storage"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvoiceNotFound is returned when no invoice was issued for the order.
+	ErrInvoiceNotFound = errors.New("invoice not found")
+	// ErrInvoiceExists is returned when an invoice was already issued for the order.
+	ErrInvoiceExists = errors.New("invoice already issued for the order")
+	// ErrOrderNotInvoiceable is returned when the order is not paid.
+	ErrOrderNotInvoiceable = errors.New("only paid, shipped or delivered orders can be invoiced")
+)
+
+// InvoiceService issues invoices of paid orders with gapless numbers per fiscal year.
+type InvoiceService struct {
+	invoices        repository.InvoiceRepository
+	orders          repository.OrderRepository
+	db              repository.TxBeginner
+	documents       storage.Storage
+	notifier        notification.Notifier
+	money           *money.Formatter
+	ordersURL       string
+	fiscalYearStart time.Month
+	downloadTTL     time.Duration
+}
+
+// NewInvoiceService creates a new invoice service for fiscal years starting in fiscalYearStart.
+// Invoices are in the currency of their order, or in the currency of f for orders without recorded settings.
+// Invoice documents are archived in documents, with download URLs valid for downloadTTL.
+// Invoice emails link to the documents of the customer's orders under ordersURL, e.g. "https://shop.example.com/orders".
+func NewInvoiceService(db repository.TxBeginner, invoices repository.InvoiceRepository, orders repository.OrderRepository, documents storage.Storage, notifier notification.Notifier, f *money.Formatter, ordersURL string, fiscalYearStart time.Month, downloadTTL time.Duration) *InvoiceService {
+	return &InvoiceService{
+		db:              db,
+		invoices:        invoices,
+		orders:          orders,
+		documents:       documents,
+		notifier:        notifier,
+		money:           f,
+		ordersURL:       ordersURL,
+		fiscalYearStart: fiscalYearStart,
+		downloadTTL:     downloadTTL,
+	}
+}
+
+// InvoiceDocument is the archived document of an invoice: a download URL, or its contents
+// if the storage backend cannot presign URLs.
+type InvoiceDocument struct {
+	URL    string          // Presigned download URL
+	Object *storage.Object // Set if URL is empty, its body must be closed by the caller
+}
+
+// invoiceDocument is the archived document of an invoice, with its total, option fees and date
+// written in the invoice's locale.
+type invoiceDocument struct {
+	domain.Invoice
+	Options  []invoiceOption `json:",omitempty"` // Order options charged with the order
+	Total    string          // e.g. "1.234,50 €"
+	IssuedOn string          // e.g. "14.03.2026"
+}
+
+// invoiceOption is an order option on an invoice document, with its fee written in the invoice's locale.
+type invoiceOption struct {
+	Option  string
+	Fee     string // e.g. "4,99 €"
+	Message string `json:",omitempty"` // Gift message
+}
+
+// Issue issues the invoice of a paid, shipped or delivered order over its total
+// in the currency, locale and tax region the order was placed with.
+// Returns ErrOrderNotFound, ErrOrderNotInvoiceable if the order is not paid,
+// and ErrInvoiceExists if the order already has an invoice.
+func (s *InvoiceService) Issue(ctx context.Context, orderID uuid.UUID) (_ *domain.Invoice, err error) {
+	const op = "InvoiceService.Issue"
+
+	order, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !slices.Contains([]string{domain.OrderStatusPaid, domain.OrderStatusShipped, domain.OrderStatusDelivered}, order.Status) {
+		return nil, fmt.Errorf("%w: order is %s", ErrOrderNotInvoiceable, order.Status)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not begin transaction: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	now := time.Now()
+	invoice := &domain.Invoice{
+		ID:         uuid.New(),
+		FiscalYear: domain.FiscalYear(now, s.fiscalYearStart),
+		OrderID:    order.ID,
+		Amount:     order.TotalAmount,
+		Currency:   cmp.Or(order.Currency, s.money.Currency.Code),
+		Locale:     order.Locale,
+		TaxRegion:  order.TaxRegion,
+		IssuedAt:   now,
+	}
+	if err = s.invoices.CreateTx(ctx, tx, invoice); err != nil {
+		if errors.Is(err, repository.ErrInvoiceExists) {
+			return nil, ErrInvoiceExists
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: could not commit transaction: %w", op, err)
+	}
+	return invoice, nil
+}
+
+// Get returns the invoice of the order.
+// Returns ErrInvoiceNotFound if no invoice was issued for it.
+func (s *InvoiceService) Get(ctx context.Context, orderID uuid.UUID) (*domain.Invoice, error) {
+	invoice, err := s.invoices.FindByOrderID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvoiceNotFound) {
+			return nil, ErrInvoiceNotFound
+		}
+		return nil, fmt.Errorf("InvoiceService.Get: %w", err)
+	}
+	return invoice, nil
+}
+
+// Document returns the document of the order's invoice, archiving it in the object storage on first access.
+// The document lists the order options charged and is written in the currency and locale of the invoice.
+// Invoices cannot be changed once issued, so an archived document is never replaced.
+// Returns ErrInvoiceNotFound if no invoice was issued for the order.
+func (s *InvoiceService) Document(ctx context.Context, orderID uuid.UUID) (*InvoiceDocument, error) {
+	const op = "InvoiceService.Document"
+
+	invoice, err := s.Get(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	key := storage.PrefixInvoices + invoice.Number + ".json"
+	if _, err := s.documents.Stat(ctx, key); errors.Is(err, storage.ErrNotFound) {
+		f, err := s.money.In(invoice.Currency, invoice.Locale)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invoice %s: %w", op, invoice.Number, err)
+		}
+		order, err := s.orders.FindByID(ctx, orderID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		options := make([]invoiceOption, len(order.Options))
+		for i, option := range order.Options {
+			options[i] = invoiceOption{Option: option.Option, Fee: f.Format(option.Fee), Message: option.Message}
+		}
+		doc, err := json.MarshalIndent(invoiceDocument{
+			Invoice:  *invoice,
+			Options:  options,
+			Total:    f.Format(invoice.Amount),
+			IssuedOn: f.Locale.FormatDate(invoice.IssuedAt),
+		}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := s.documents.Put(ctx, key, bytes.NewReader(doc), int64(len(doc)), "application/json"); err != nil {
+			return nil, fmt.Errorf("%s: archive %s: %w", op, invoice.Number, err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	url, err := s.documents.PresignGet(ctx, key, s.downloadTTL)
+	if err == nil {
+		return &InvoiceDocument{URL: url}, nil
+	}
+	if !errors.Is(err, storage.ErrPresignNotSupported) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	obj, err := s.documents.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &InvoiceDocument{Object: obj}, nil
+}
+
+// CustomerDocument returns the document of the invoice of an order of the customer, like Document.
+// Returns ErrInvoiceNotFound if the order has no invoice or belongs to another customer.
+func (s *InvoiceService) CustomerDocument(ctx context.Context, userID, orderID uuid.UUID) (*InvoiceDocument, error) {
+	order, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, ErrInvoiceNotFound
+		}
+		return nil, fmt.Errorf("InvoiceService.CustomerDocument: %w", err)
+	}
+	if order.UserID != userID {
+		return nil, ErrInvoiceNotFound
+	}
+	return s.Document(ctx, orderID)
+}
+
+// Send emails the invoice of the order to the customer with a link to its document, e.g. when the customer
+// lost it, through the channels the customer opted in to for order updates. The email is written in the locale
+// of the invoice rather than the customer's current one.
+// Returns ErrInvoiceNotFound if no invoice was issued for the order.
+func (s *InvoiceService) Send(ctx context.Context, orderID uuid.UUID) (*domain.Invoice, error) {
+	const op = "InvoiceService.Send"
+
+	invoice, err := s.Get(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	f, err := s.money.In(invoice.Currency, invoice.Locale)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invoice %s: %w", op, invoice.Number, err)
+	}
+
+	msg := notification.Message{
+		Category: domain.NotificationCategoryOrderUpdates,
+		Template: notification.TemplateInvoice,
+		Data: map[string]string{
+			"InvoiceNumber": invoice.Number,
+			"OrderNumber":   order.Number,
+			"Total":         f.Format(invoice.Amount),
+			"IssuedOn":      f.Locale.FormatDate(invoice.IssuedAt),
+			"DocumentURL":   s.ordersURL + "/" + orderID.String() + "/invoice/document",
+		},
+		Locale: invoice.Locale,
+	}
+	if err := s.notifier.Notify(ctx, order.UserID, msg); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return invoice, nil
+}
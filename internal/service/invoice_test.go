@@ -0,0 +1,211 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/notification"
+	notificationmocks "product-api/internal/notification/mocks"
+	"product-api/internal/repository"
+	"product-api/internal/repository/postgres"
+	"product-api/internal/service"
+	"product-api/internal/storage"
+	"product-api/internal/testutil/factory"
+	"product-api/internal/testutil/testdb"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type InvoiceServiceTestSuite struct {
+	suite.Suite
+	dbpool      *pgxpool.Pool
+	orderRepo   repository.OrderRepository
+	productRepo repository.ProductRepository
+	userRepo    repository.UserRepository
+	documents   *storage.LocalStorage
+	notifier    *notificationmocks.MockNotifier
+	service     *service.InvoiceService
+}
+
+func (s *InvoiceServiceTestSuite) SetupTest() {
+	s.dbpool = testdb.New(s.T())
+	s.orderRepo = postgres.NewOrderRepository(s.dbpool)
+	s.productRepo = postgres.NewProductRepository(s.dbpool)
+	s.userRepo = postgres.NewUserRepository(s.dbpool)
+	documents, err := storage.NewLocalStorage(s.T().TempDir())
+	s.Require().NoError(err)
+	s.documents = documents
+	s.notifier = notificationmocks.NewMockNotifier(s.T())
+	s.service = service.NewInvoiceService(s.dbpool, postgres.NewInvoiceRepository(s.dbpool), s.orderRepo, documents, s.notifier, usdFormatter(), "https://shop.example.com/orders", time.January, time.Minute)
+}
+
+// createOrder creates an order with the status directly in the database.
+func (s *InvoiceServiceTestSuite) createOrder(status string) *domain.Order {
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(12.5))
+	order := factory.CreateOrder(s.T(), s.dbpool, s.orderRepo, user.ID, factory.WithItem(product, 2))
+	_, err := s.dbpool.Exec(context.Background(), `UPDATE orders SET status = $2 WHERE id = $1`, order.ID, status)
+	s.Require().NoError(err)
+	return order
+}
+
+func (s *InvoiceServiceTestSuite) TestIssue_NumbersAreGapless() {
+	ctx := context.Background()
+	year := time.Now().Year()
+	first, second := s.createOrder(domain.OrderStatusPaid), s.createOrder(domain.OrderStatusShipped)
+
+	invoice, err := s.service.Issue(ctx, first.ID)
+	s.Require().NoError(err)
+	s.Equal(fmt.Sprintf("INV-%d-000001", year), invoice.Number)
+	s.Equal(25.0, invoice.Amount)
+
+	// A failed issue does not consume a number
+	_, err = s.service.Issue(ctx, first.ID)
+	s.ErrorIs(err, service.ErrInvoiceExists)
+
+	invoice, err = s.service.Issue(ctx, second.ID)
+	s.Require().NoError(err)
+	s.Equal(fmt.Sprintf("INV-%d-000002", year), invoice.Number)
+
+	stored, err := s.service.Get(ctx, second.ID)
+	s.Require().NoError(err)
+	s.Equal(invoice.Number, stored.Number)
+}
+
+func (s *InvoiceServiceTestSuite) TestIssue_UsesOrderSettings() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo)
+	settings := domain.OrderSettings{Currency: "EUR", Locale: "de-DE", TaxRegion: "DE"}
+	order := factory.CreateOrder(s.T(), s.dbpool, s.orderRepo, user.ID, factory.WithItem(product, 1), factory.WithSettings(settings))
+	_, err := s.dbpool.Exec(ctx, `UPDATE orders SET status = 'paid' WHERE id = $1`, order.ID)
+	s.Require().NoError(err)
+
+	invoice, err := s.service.Issue(ctx, order.ID)
+	s.Require().NoError(err)
+	stored, err := s.service.Get(ctx, order.ID)
+	s.Require().NoError(err)
+	s.Equal(invoice, stored)
+	s.Equal([]string{"EUR", "de-DE", "DE"}, []string{stored.Currency, stored.Locale, stored.TaxRegion})
+
+	// Orders placed before settings were recorded are invoiced in the configured currency
+	invoice, err = s.service.Issue(ctx, s.createOrder(domain.OrderStatusPaid).ID)
+	s.Require().NoError(err)
+	s.Equal("USD", invoice.Currency)
+	s.Empty(invoice.Locale)
+}
+
+func (s *InvoiceServiceTestSuite) TestIssue_Errors() {
+	ctx := context.Background()
+
+	_, err := s.service.Issue(ctx, s.createOrder(domain.OrderStatusCreated).ID)
+	s.ErrorIs(err, service.ErrOrderNotInvoiceable)
+
+	_, err = s.service.Get(ctx, s.createOrder(domain.OrderStatusPaid).ID)
+	s.ErrorIs(err, service.ErrInvoiceNotFound)
+}
+
+func (s *InvoiceServiceTestSuite) TestInvoicesAreImmutable() {
+	order := s.createOrder(domain.OrderStatusPaid)
+	_, err := s.service.Issue(context.Background(), order.ID)
+	s.Require().NoError(err)
+
+	_, err = s.dbpool.Exec(context.Background(), `DELETE FROM invoices WHERE order_id = $1`, order.ID)
+	s.Error(err)
+}
+
+func (s *InvoiceServiceTestSuite) TestDocument_IsArchived() {
+	ctx := context.Background()
+	order := s.createOrder(domain.OrderStatusPaid)
+	_, err := s.service.Document(ctx, order.ID)
+	s.ErrorIs(err, service.ErrInvoiceNotFound)
+
+	invoice, err := s.service.Issue(ctx, order.ID)
+	s.Require().NoError(err)
+	doc, err := s.service.Document(ctx, order.ID)
+	s.Require().NoError(err)
+	s.Empty(doc.URL, "local storage cannot presign")
+	defer doc.Object.Body.Close()
+
+	var archived struct {
+		domain.Invoice
+		Total    string
+		IssuedOn string
+	}
+	s.Require().NoError(json.NewDecoder(doc.Object.Body).Decode(&archived))
+	s.Equal(invoice.Number, archived.Number)
+	s.Equal("$25.00", archived.Total, "written in the configured locale")
+	s.Equal(invoice.IssuedAt.Format("01/02/2006"), archived.IssuedOn)
+	info, err := s.documents.Stat(ctx, storage.PrefixInvoices+invoice.Number+".json")
+	s.Require().NoError(err)
+	s.Equal("application/json", info.ContentType)
+}
+
+func (s *InvoiceServiceTestSuite) TestDocument_ListsOrderOptions() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(12.5))
+	order := factory.CreateOrder(s.T(), s.dbpool, s.orderRepo, user.ID, factory.WithItem(product, 1),
+		factory.WithOption(domain.OrderOptionGiftWrap, 4.99), factory.WithOption(domain.OrderOptionPriorityHandling, 9.99))
+	_, err := s.dbpool.Exec(ctx, `UPDATE orders SET status = 'paid' WHERE id = $1`, order.ID)
+	s.Require().NoError(err)
+
+	_, err = s.service.Issue(ctx, order.ID)
+	s.Require().NoError(err)
+	doc, err := s.service.Document(ctx, order.ID)
+	s.Require().NoError(err)
+	defer doc.Object.Body.Close()
+
+	var archived struct {
+		Options []struct{ Option, Fee string }
+		Total   string
+	}
+	s.Require().NoError(json.NewDecoder(doc.Object.Body).Decode(&archived))
+	s.Equal("$27.48", archived.Total)
+	s.Require().Len(archived.Options, 2)
+	s.Equal(domain.OrderOptionGiftWrap, archived.Options[0].Option)
+	s.Equal("$4.99", archived.Options[0].Fee)
+	s.Equal(domain.OrderOptionPriorityHandling, archived.Options[1].Option)
+}
+
+func (s *InvoiceServiceTestSuite) TestSend_LinksCustomerDocument() {
+	ctx := context.Background()
+	order := s.createOrder(domain.OrderStatusPaid)
+	_, err := s.service.Send(ctx, order.ID)
+	s.ErrorIs(err, service.ErrInvoiceNotFound)
+
+	invoice, err := s.service.Issue(ctx, order.ID)
+	s.Require().NoError(err)
+	s.notifier.EXPECT().Notify(mock.Anything, order.UserID, notification.Message{
+		Category: domain.NotificationCategoryOrderUpdates,
+		Template: notification.TemplateInvoice,
+		Data: map[string]string{
+			"InvoiceNumber": invoice.Number,
+			"OrderNumber":   order.Number,
+			"Total":         "$25.00",
+			"IssuedOn":      invoice.IssuedAt.Format("01/02/2006"),
+			"DocumentURL":   "https://shop.example.com/orders/" + order.ID.String() + "/invoice/document",
+		},
+	}).Return(nil).Once()
+	_, err = s.service.Send(ctx, order.ID)
+	s.Require().NoError(err)
+
+	doc, err := s.service.CustomerDocument(ctx, order.UserID, order.ID)
+	s.Require().NoError(err)
+	doc.Object.Body.Close()
+
+	// Invoices of other customers are not found
+	_, err = s.service.CustomerDocument(ctx, uuid.New(), order.ID)
+	s.ErrorIs(err, service.ErrInvoiceNotFound)
+}
+
+func TestInvoiceServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(InvoiceServiceTestSuite))
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/money"
+	"product-api/internal/notification"
+	"product-api/internal/repository"
+	"slices"
+	"time"
+)
+
+// ErrTemplateVersionConflict is returned when another version of the template is saved concurrently.
+var ErrTemplateVersionConflict = errors.New("template was changed concurrently, retry")
+
+// TemplatePreviewInput contains a template to preview. An empty subject or body previews the current one
+// sent to users of the locale.
+type TemplatePreviewInput struct {
+	Locale    string // BCP 47 tag, empty for the default locale
+	Subject   string
+	Body      string
+	Variables map[string]string // Override sample values of the template variables
+}
+
+// NotificationTemplateService manages versions of notification templates.
+// Saved versions are sent once templates reload, without redeploying.
+type NotificationTemplateService struct {
+	repo      repository.NotificationTemplateRepository
+	templates *notification.Templates
+}
+
+// NewNotificationTemplateService creates a new notification template service.
+func NewNotificationTemplateService(repo repository.NotificationTemplateRepository, templates *notification.Templates) *NotificationTemplateService {
+	return &NotificationTemplateService{repo: repo, templates: templates}
+}
+
+// List returns the current version of every template ordered by name, built-in templates with version 0,
+// each followed by its current versions in other locales ordered by locale.
+func (s *NotificationTemplateService) List(ctx context.Context) ([]domain.NotificationTemplate, error) {
+	stored, err := s.repo.FindLatest(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("NotificationTemplateService.List: %w", err)
+	}
+	latest := make(map[string]domain.NotificationTemplate, len(stored))
+	localized := make(map[string][]domain.NotificationTemplate)
+	for _, t := range stored {
+		if t.Locale == "" {
+			latest[t.Name] = t
+			continue
+		}
+		localized[t.Name] = append(localized[t.Name], t)
+	}
+
+	specs := notification.TemplateSpecs()
+	current := make([]domain.NotificationTemplate, 0, len(stored)+len(specs))
+	for _, spec := range specs {
+		t, ok := latest[spec.Name]
+		if !ok {
+			t = domain.NotificationTemplate{Name: spec.Name, Subject: spec.Subject, Body: spec.Body}
+		}
+		current = append(current, t)
+		current = append(current, slices.SortedFunc(slices.Values(localized[spec.Name]), func(a, b domain.NotificationTemplate) int {
+			return cmp.Compare(a.Locale, b.Locale)
+		})...)
+	}
+	return current, nil
+}
+
+// Versions returns the stored versions of the template in the locale, newest first.
+// Returns domain.ErrUnknownTemplate and money.ErrUnknownLocale.
+func (s *NotificationTemplateService) Versions(ctx context.Context, name, locale string) ([]domain.NotificationTemplate, error) {
+	if _, err := notification.LookupTemplateSpec(name); err != nil {
+		return nil, err
+	}
+	locale, err := supportedLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := s.repo.FindVersions(ctx, name, locale)
+	if err != nil {
+		return nil, fmt.Errorf("NotificationTemplateService.Versions: %w", err)
+	}
+	return versions, nil
+}
+
+// Save stores a new version of the template in the locale on behalf of actor, the empty locale is the default version
+// sent to users without a version in their locale. Reverting to an earlier version saves it again.
+// Returns domain.ErrUnknownTemplate, domain.ErrInvalidTemplate if the template does not parse
+// or uses unknown variables, money.ErrUnknownLocale and ErrTemplateVersionConflict.
+func (s *NotificationTemplateService) Save(ctx context.Context, actor, name, locale, subject, body string) (*domain.NotificationTemplate, error) {
+	if err := notification.ValidateTemplate(name, subject, body); err != nil {
+		return nil, err
+	}
+	locale, err := supportedLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	template := &domain.NotificationTemplate{
+		Name:      name,
+		Locale:    locale,
+		Subject:   subject,
+		Body:      body,
+		CreatedBy: actor,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Create(ctx, template); err != nil {
+		if errors.Is(err, repository.ErrTemplateVersionConflict) {
+			return nil, ErrTemplateVersionConflict
+		}
+		return nil, fmt.Errorf("NotificationTemplateService.Save: %w", err)
+	}
+	// Other instances pick up the version when their templates reload
+	s.templates.Reload()
+	return template, nil
+}
+
+// Preview renders the template with sample values of its variables, overridden by the input.
+// Returns domain.ErrUnknownTemplate, domain.ErrInvalidTemplate and money.ErrUnknownLocale.
+func (s *NotificationTemplateService) Preview(ctx context.Context, name string, input TemplatePreviewInput) (notification.Message, error) {
+	if _, err := notification.LookupTemplateSpec(name); err != nil {
+		return notification.Message{}, err
+	}
+	locale, err := supportedLocale(input.Locale)
+	if err != nil {
+		return notification.Message{}, err
+	}
+	if input.Subject == "" || input.Body == "" {
+		current, err := s.List(ctx)
+		if err != nil {
+			return notification.Message{}, err
+		}
+		// The most specific version of the locale's fallback chain is sent
+		for _, fallback := range append(money.Fallbacks(locale), "") {
+			i := slices.IndexFunc(current, func(t domain.NotificationTemplate) bool { return t.Name == name && t.Locale == fallback })
+			if i >= 0 {
+				input.Subject = cmp.Or(input.Subject, current[i].Subject)
+				input.Body = cmp.Or(input.Body, current[i].Body)
+				break
+			}
+		}
+	}
+	return notification.PreviewTemplate(name, input.Subject, input.Body, input.Variables)
+}
@@ -0,0 +1,56 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/money"
+	"product-api/internal/notification"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationTemplateService_Unit_Locales(t *testing.T) {
+	ctx := context.Background()
+	repo := mocks.NewMockNotificationTemplateRepository(t)
+	svc := service.NewNotificationTemplateService(repo, notification.NewTemplates(repo, time.Hour, discardLogger{}))
+	name := notification.TemplateOrderShipped
+
+	repo.EXPECT().FindLatest(mock.Anything).Return([]domain.NotificationTemplate{
+		{Name: name, Locale: "fr", Version: 1, Subject: "Commande expédiée", Body: "Votre commande {{.OrderNumber}} est en route."},
+		{Name: name, Locale: "de", Version: 2, Subject: "Bestellung versandt", Body: "Ihre Bestellung {{.OrderNumber}} ist unterwegs."},
+	}, nil)
+
+	// Localized versions follow the default version of their template
+	current, err := svc.List(ctx)
+	require.NoError(t, err)
+	i := 0
+	for current[i].Name != name {
+		i++
+	}
+	assert.Equal(t, []string{"", "de", "fr"}, []string{current[i].Locale, current[i+1].Locale, current[i+2].Locale})
+	assert.Zero(t, current[i].Version, "built-in default version")
+
+	// Previews render the version sent to users of the locale
+	msg, err := svc.Preview(ctx, name, service.TemplatePreviewInput{Locale: "de-AT"})
+	require.NoError(t, err)
+	assert.Equal(t, "Ihre Bestellung ORD-2024-000123 ist unterwegs.", msg.Body)
+	msg, err = svc.Preview(ctx, name, service.TemplatePreviewInput{Locale: "en-GB"})
+	require.NoError(t, err)
+	assert.Equal(t, "Your order is on its way", msg.Subject)
+
+	repo.EXPECT().Create(mock.Anything, mock.MatchedBy(func(t *domain.NotificationTemplate) bool {
+		return t.Locale == "de-AT" && t.CreatedBy == "client:admin"
+	})).Return(nil).Once()
+	saved, err := svc.Save(ctx, "client:admin", name, "de_at", "Versandt", "Bestellung {{.OrderNumber}}")
+	require.NoError(t, err)
+	assert.Equal(t, "de-AT", saved.Locale, "tags are canonical")
+
+	_, err = svc.Save(ctx, "client:admin", name, "pt-BR", "Enviado", "Pedido {{.OrderNumber}}")
+	assert.ErrorIs(t, err, money.ErrUnknownLocale)
+}
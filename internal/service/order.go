@@ -1,39 +1,150 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"product-api/internal/domain"
+	"product-api/internal/event"
 	"product-api/internal/logger"
+	"product-api/internal/money"
+	"product-api/internal/notification"
+	"product-api/internal/payment"
 	"product-api/internal/repository"
+	"product-api/internal/telemetry"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
 	// ErrInsufficientStock is returned when there is insufficient stock to create an order.
 	ErrInsufficientStock = errors.New("insufficient stock for a product")
+	// ErrProductUnavailable is returned when an ordered product is archived or not published yet.
+	ErrProductUnavailable = errors.New("product is not available for ordering")
+	// ErrAgeRestricted is returned when the buyer is younger than a product's age restriction.
+	ErrAgeRestricted = errors.New("buyer does not meet the product age restriction")
+	// ErrOrderNotFound is returned when order is not found.
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrOrderConflict is returned when the order was changed concurrently.
+	ErrOrderConflict = errors.New("order was changed concurrently")
+	// ErrPaymentFailed is returned when the order could not be charged. At checkout, the order is cancelled.
+	ErrPaymentFailed = errors.New("payment failed")
+	// ErrPaymentPending is returned when an order is paid while a payment of it awaits confirmation by the provider.
+	ErrPaymentPending = errors.New("payment of the order is pending")
+	// ErrNotOrderOwner is returned when a user pays an order of another user.
+	ErrNotOrderOwner = errors.New("order belongs to another user")
+	// ErrUnknownPaymentProvider is returned when the checkout selects a payment provider that is not configured.
+	ErrUnknownPaymentProvider = errors.New("unknown payment provider")
+	// ErrPaymentNotFound is returned when the order has no charge with the ID.
+	ErrPaymentNotFound = errors.New("payment not found")
+	// ErrRefundFailed is returned when the payment provider rejects a refund. Nothing is refunded.
+	ErrRefundFailed = errors.New("refund failed")
+	// ErrOrderNotConfirmed is returned when a confirmation is resent for an order that was never paid.
+	ErrOrderNotConfirmed = errors.New("only paid, shipped or delivered orders were confirmed")
+	// ErrPossibleDuplicateOrder is returned when a checkout repeats an order the user placed shortly before,
+	// e.g. a form submitted twice on a flaky network. See DuplicateOrderError.
+	ErrPossibleDuplicateOrder = errors.New("order looks like a duplicate of a recent order")
 )
 
+// DuplicateOrderError is returned by CreateOrder when the user placed an order with the same products,
+// quantities and total within the duplicate window. It wraps ErrPossibleDuplicateOrder.
+type DuplicateOrderError struct {
+	Order *domain.Order // The recent order, with its ID, number, status, creation time and total
+}
+
+func (e *DuplicateOrderError) Error() string {
+	return fmt.Sprintf("%v: order %s", ErrPossibleDuplicateOrder, e.Order.Number)
+}
+
+func (e *DuplicateOrderError) Unwrap() error {
+	return ErrPossibleDuplicateOrder
+}
+
 // OrderService provides business logic for order operations.
-// Uses transactions to ensure data integrity when creating orders.
+// Uses transactions to ensure data integrity and compensating actions for steps outside the database.
 type OrderService struct {
 	orderRepo   repository.OrderRepository
+	eventRepo   repository.OrderEventRepository
+	numbers     repository.OrderNumberRepository
+	paymentRepo repository.PaymentRepository
+	refundRepo  repository.RefundRequestRepository
+	disputeRepo repository.DisputeRepository
 	productRepo repository.ProductRepository
-	db          *pgxpool.Pool
+	stockRepo   repository.StockRepository
+	slotRepo    repository.DeliverySlotRepository
+	userRepo    repository.UserRepository
+	db          repository.TxBeginner
+	notifier    notification.Notifier
+	providers   *payment.Registry
+	money       *money.Formatter
+	taxRegion   string
+	region      string // Deployment region recorded on orders and events
+	checks      *CheckoutPipeline
+	optionFees  OrderOptionFees
+	dupWindow   time.Duration // Window checkouts repeating an order of the user need confirmation in, 0 disables the check
+	events      event.Publisher
 	logger      logger.Logger
 }
 
-// NewOrderService creates a new order service.
-func NewOrderService(db *pgxpool.Pool, orderRepo repository.OrderRepository, productRepo repository.ProductRepository, logger logger.Logger) *OrderService {
+// OrderServiceDeps contains the repositories, collaborators and settings of an OrderService.
+type OrderServiceDeps struct {
+	DB          repository.TxBeginner
+	OrderRepo   repository.OrderRepository
+	EventRepo   repository.OrderEventRepository
+	Numbers     repository.OrderNumberRepository
+	PaymentRepo repository.PaymentRepository
+	RefundRepo  repository.RefundRequestRepository
+	DisputeRepo repository.DisputeRepository
+	ProductRepo repository.ProductRepository
+	StockRepo   repository.StockRepository
+	SlotRepo    repository.DeliverySlotRepository
+	UserRepo    repository.UserRepository
+	Notifier    notification.Notifier
+	Providers   *payment.Registry
+	Formatter   *money.Formatter // Currency and locale recorded on new orders
+	TaxRegion   string           // Tax region recorded on new orders, empty if not configured
+	Region      string           // Deployment region recorded on new orders and order events, empty if not configured
+	Checks      *CheckoutPipeline
+	OptionFees  OrderOptionFees // Order options offered at checkout
+	// Window checkouts repeating an order of the user need confirmation in, 0 disables the check
+	DuplicateWindow time.Duration
+	Events          event.Publisher // Receives created orders and stock movements
+	Logger          logger.Logger
+}
+
+// NewOrderService creates a new order service with the dependencies of deps. New orders record the currency
+// and locale of the formatter, the tax region and the deployment region, which order events also record.
+// Checkouts are validated by the checks pipeline and offer the order options of the option fees.
+// Created orders and stock movements are published to events.
+func NewOrderService(deps OrderServiceDeps) *OrderService {
 	return &OrderService{
-		db:          db,
-		orderRepo:   orderRepo,
-		productRepo: productRepo,
-		logger:      logger,
+		db:          deps.DB,
+		orderRepo:   deps.OrderRepo,
+		eventRepo:   deps.EventRepo,
+		numbers:     deps.Numbers,
+		paymentRepo: deps.PaymentRepo,
+		refundRepo:  deps.RefundRepo,
+		disputeRepo: deps.DisputeRepo,
+		productRepo: deps.ProductRepo,
+		stockRepo:   deps.StockRepo,
+		slotRepo:    deps.SlotRepo,
+		userRepo:    deps.UserRepo,
+		notifier:    deps.Notifier,
+		providers:   deps.Providers,
+		money:       deps.Formatter,
+		taxRegion:   deps.TaxRegion,
+		region:      deps.Region,
+		checks:      deps.Checks,
+		optionFees:  deps.OptionFees,
+		dupWindow:   deps.DuplicateWindow,
+		events:      deps.Events,
+		logger:      deps.Logger,
 	}
 }
 
@@ -43,15 +154,178 @@ type OrderItemInput struct {
 	Quantity  int       `json:"quantity" validate:"required,gt=0"`
 }
 
-// CreateOrder creates a new order for a user.
-// Uses a transaction to ensure atomicity of operations:
-// - Check product availability in stock
-// - Update product quantities
-// - Create order and order items
-// On any error, the transaction is rolled back.
-func (s *OrderService) CreateOrder(ctx context.Context, userID uuid.UUID, items []OrderItemInput) (*domain.Order, error) {
+// PaymentSource selects how a checkout is paid. The zero value pays with the default provider.
+type PaymentSource struct {
+	Provider string // Payment provider name, empty for the default provider
+	Token    string // Provider token of the buyer's payment method, e.g. a PayPal vault ID
+}
+
+// CreateOrder creates and pays a new order for a user as a checkout saga:
+//  1. Reserve stock: a transaction locks the ordered products, validates the checkout with the checkout pipeline,
+//     e.g. product availability, buyer age restrictions and stock, allocates stock in the ledger
+//     and creates the order with the chosen options and its creation events. Option fees are part of the total.
+//     A chosen delivery slot has one order of its capacity reserved in the same transaction.
+//  2. Authorize and capture the payment through the selected payment provider.
+//  3. Confirm the reservation by recording the payment in the payment ledger, which marks the order paid.
+//     A capture the provider has not settled yet is recorded as pending instead, the order stays created
+//     until the provider reports the outcome by webhook (see ConfirmCapture).
+//
+// Payment cannot share the database transaction, so failed steps are compensated instead:
+// a declined payment cancels the order and releases its stock and delivery slot, a failed capture also voids the authorization
+// and a failed confirmation also refunds the capture.
+// After confirmation, an order confirmation is sent according to the user's notification preferences.
+// Returns ErrUnknownPaymentProvider before reserving anything if the source selects an unknown provider,
+// ErrOrderOptionUnavailable or ErrGiftMessageTooLong if the options cannot be chosen,
+// and ErrDeliverySlotUnavailable if deliverySlot is full, has started or is in another region. uuid.Nil chooses no delivery slot.
+// Orders of sandbox accounts go through the same steps, but allocate no stock, take no delivery slot capacity
+// and are paid with the sandbox provider whatever provider the source selects.
+// Unless confirmDuplicate is set, returns a *DuplicateOrderError without reserving or charging anything
+// if the user placed an order with the same products, quantities and total within the duplicate window
+// that was not cancelled.
+func (s *OrderService) CreateOrder(ctx context.Context, userID uuid.UUID, items []OrderItemInput, options OrderOptionsInput, deliverySlot uuid.UUID, confirmDuplicate bool, source PaymentSource) (_ *domain.Order, err error) {
 	const op = "OrderService.CreateOrder"
 
+	provider, ok := s.providers.Get(source.Provider)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPaymentProvider, source.Provider)
+	}
+	chosen, err := s.optionFees.resolve(options)
+	if err != nil {
+		return nil, err
+	}
+	// Emails and documents of the order are written in the buyer's locale
+	buyer, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not load buyer: %w", op, err)
+	}
+	if provider, err = s.payingProvider(buyer.Sandbox, provider); err != nil {
+		return nil, err
+	}
+
+	ctx, span := telemetry.StartSpan(ctx, op, trace.WithAttributes(
+		attribute.String("user_id", userID.String()),
+		attribute.Int("order.item_count", len(items)),
+		attribute.String("payment.provider", provider.Name()),
+		attribute.Bool("order.sandbox", buyer.Sandbox),
+	))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	start := time.Now()
+	var totalAmount float64
+	defer func() {
+		telemetry.RecordOrderCreation(ctx, time.Since(start), orderOutcome(err), totalAmount)
+	}()
+
+	// Step 1: reserve stock
+	order, err := s.reserveOrder(ctx, buyer, items, chosen, deliverySlot, !confirmDuplicate && s.dupWindow > 0)
+	if err != nil {
+		return nil, err
+	}
+	totalAmount = order.TotalAmount
+	span.SetAttributes(
+		attribute.String("order.id", order.ID.String()),
+		attribute.Float64("order.amount", totalAmount),
+	)
+
+	// Steps 2 and 3: authorize, capture and confirm payment
+	// New orders record the configured currency and locale
+	paid, err := s.charge(ctx, order, provider, source.Token, order.TotalAmount, s.money.Currency, order.ID.String(),
+		func(ctx context.Context, reversePayment func(context.Context) error, cause error) {
+			s.compensate(ctx, order.ID, reversePayment, cause)
+		})
+	if err != nil {
+		return nil, err
+	}
+	if paid.Status == domain.OrderStatusPaid {
+		s.sendConfirmation(ctx, op, paid)
+	}
+	return paid, nil
+}
+
+// charge authorizes and captures the amount due of the order through the provider and records the payment,
+// which marks the order paid once payments cover its total. A pending capture is recorded as awaiting
+// confirmation by the provider instead, the order then stays created.
+// Failed steps are undone by compensate, with the reversal of the payment if one was made.
+// Each provider call is sent with its own key derived from idempotencyKey, since providers reject
+// a key reused with other parameters.
+// Returns ErrPaymentFailed if the provider declines the payment.
+func (s *OrderService) charge(ctx context.Context, order *domain.Order, provider payment.Provider, source string, due float64, currency money.Currency, idempotencyKey string, compensate func(ctx context.Context, reversePayment func(context.Context) error, cause error)) (*domain.Order, error) {
+	const op = "OrderService.charge"
+
+	amount := money.FromMajor(due, currency)
+	auth, err := provider.Authorize(ctx, payment.AuthorizeRequest{
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		Amount:         amount,
+		Source:         source,
+		IdempotencyKey: idempotencyKey + ":authorize",
+	})
+	if err != nil {
+		compensate(ctx, nil, err)
+		return nil, fmt.Errorf("%w: %w", ErrPaymentFailed, err)
+	}
+	capture, err := provider.Capture(ctx, payment.CaptureRequest{
+		AuthorizationID: auth.ID,
+		Amount:          amount,
+		IdempotencyKey:  idempotencyKey + ":capture",
+	})
+	if err != nil {
+		compensate(ctx, func(ctx context.Context) error { return provider.Void(ctx, auth.ID) }, err)
+		return nil, fmt.Errorf("%w: %w", ErrPaymentFailed, err)
+	}
+
+	if capture.Pending {
+		pending, err := s.applyEvent(ctx, order.ID, domain.OrderEvent{Type: domain.OrderEventPaymentPending, PaymentID: capture.ID})
+		if err != nil {
+			// A capture completing later is not recorded for an order not awaiting it, see ConfirmCapture
+			compensate(ctx, func(ctx context.Context) error {
+				_, err := provider.Refund(ctx, payment.RefundRequest{CaptureID: capture.ID, Amount: amount, IdempotencyKey: idempotencyKey + ":refund"})
+				return err
+			}, err)
+			return nil, fmt.Errorf("%s: record pending payment: %w", op, err)
+		}
+		s.logger.WithTrace(ctx).Info("payment pending", "op", op, "order_id", order.ID, "provider", provider.Name(), "capture_id", capture.ID)
+		return pending, nil
+	}
+
+	metadata := map[string]string{"authorization_id": auth.ID}
+	maps.Copy(metadata, auth.Metadata)
+	maps.Copy(metadata, capture.Metadata)
+	paid, err := s.recordPayment(ctx, order.ID, domain.Payment{
+		Kind:      domain.PaymentKindCharge,
+		Method:    auth.Method,
+		Reference: capture.ID,
+		Amount:    due,
+		Provider:  provider.Name(),
+		Metadata:  metadata,
+	})
+	if err != nil {
+		compensate(ctx, func(ctx context.Context) error {
+			_, err := provider.Refund(ctx, payment.RefundRequest{CaptureID: capture.ID, Amount: amount, IdempotencyKey: idempotencyKey + ":refund"})
+			return err
+		}, err)
+		return nil, fmt.Errorf("%s: confirm order: %w", op, err)
+	}
+	return paid, nil
+}
+
+// sendConfirmation sends the confirmation of the paid order to the customer.
+// Notification failures must not fail the already completed order, they are logged.
+func (s *OrderService) sendConfirmation(ctx context.Context, op string, order *domain.Order) {
+	f, err := s.orderMoney(order)
+	if err == nil {
+		err = s.notifier.Notify(ctx, order.UserID, confirmationMessage(order, f))
+	}
+	if err != nil {
+		s.logger.WithTrace(ctx).Error("failed to send order confirmation", "op", op, "order_id", order.ID, "error", err)
+	}
+}
+
+// reserveOrder allocates stock, reserves the delivery slot unless it is uuid.Nil and creates the buyer's order with its
+// creation events in one transaction, then publishes event.OrderCreated and the stock allocations.
+// Orders of sandbox accounts are checked the same way, but allocate no stock and leave the slot's capacity unchanged.
+// On any error, the transaction is rolled back.
+func (s *OrderService) reserveOrder(ctx context.Context, buyer *domain.User, items []OrderItemInput, options []domain.OrderOption, deliverySlot uuid.UUID, checkDuplicate bool) (_ *domain.Order, err error) {
 	// Begin transaction
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
@@ -66,57 +340,607 @@ func (s *OrderService) CreateOrder(ctx context.Context, userID uuid.UUID, items
 		}
 	}()
 
-	var totalAmount float64
 	order := &domain.Order{
 		ID:        uuid.New(),
-		UserID:    userID,
+		UserID:    buyer.ID,
+		Status:    domain.OrderStatusCreated,
 		CreatedAt: time.Now(),
+		OrderSettings: domain.OrderSettings{
+			Currency:  s.money.Currency.Code,
+			Locale:    cmp.Or(buyer.Locale, s.money.Locale.Tag),
+			TaxRegion: s.taxRegion,
+			Region:    s.region,
+			Sandbox:   buyer.Sandbox,
+		},
+		Options: options,
 	}
-
-	// Process each item in the order
+	// Lock the ordered products and bundle components, so checks and allocations see the same stock
+	checkout := &Checkout{Buyer: buyer, Options: options}
 	for _, item := range items {
-		// Get product with row lock (FOR UPDATE) to prevent race condition
-		product, err := s.productRepo.FindByIDTx(ctx, tx, item.ProductID)
+		product, err := s.lockProduct(ctx, tx, item.ProductID)
 		if err != nil {
-			if errors.Is(err, repository.ErrProductNotFound) {
-				return nil, ErrProductNotFound
+			return nil, err
+		}
+		line := CheckoutLine{Product: product, Quantity: item.Quantity}
+		for _, c := range product.Components {
+			component, err := s.lockProduct(ctx, tx, c.ProductID)
+			if err != nil {
+				return nil, err
 			}
-			return nil, fmt.Errorf("%s: %w", op, err)
+			line.Components = append(line.Components, CheckoutLine{Product: component, Quantity: item.Quantity * c.Quantity})
 		}
+		checkout.Lines = append(checkout.Lines, line)
+	}
 
-		// Check if sufficient quantity is available
-		if product.Quantity < item.Quantity {
-			return nil, fmt.Errorf("%w: insufficient stock for product %s", ErrInsufficientStock, product.ID)
+	if err = s.checks.Validate(ctx, checkout); err != nil {
+		return nil, err
+	}
+	if deliverySlot != uuid.Nil {
+		if order.Delivery, err = s.reserveSlot(ctx, tx, deliverySlot, order); err != nil {
+			return nil, err
 		}
+		// Sandbox orders are checked against the slot's capacity without taking any of it
+		if order.Sandbox {
+			if err = s.slotRepo.ReleaseTx(ctx, tx, deliverySlot); err != nil {
+				return nil, fmt.Errorf("could not release delivery slot of sandbox order: %w", err)
+			}
+		}
+	}
 
-		// Decrease product quantity in stock
-		product.Quantity -= item.Quantity
-		if err = s.productRepo.UpdateTx(ctx, tx, product); err != nil {
-			return nil, fmt.Errorf("could not update product quantity: %w", err)
+	state := &reservation{tx: tx, order: order}
+	for _, line := range checkout.Lines {
+		if !line.Product.IsBundle() {
+			if err = s.allocate(ctx, state, line.Product, line.Quantity, nil); err != nil {
+				return nil, err
+			}
+			continue
 		}
 
-		// Add item to order
-		orderItem := domain.OrderItem{
+		// Bundle line carries the price, its components carry the stock
+		bundleLine := domain.OrderItem{
 			ID:              uuid.New(),
-			ProductID:       item.ProductID,
-			Quantity:        item.Quantity,
-			PriceAtPurchase: product.Price, // Save price at time of purchase
+			ProductID:       line.Product.ID,
+			Quantity:        line.Quantity,
+			PriceAtPurchase: line.Product.Price,
+			Bundle:          true,
+		}
+		order.Items = append(order.Items, bundleLine)
+		for _, c := range line.Components {
+			if err = s.allocate(ctx, state, c.Product, c.Quantity, &bundleLine.ID); err != nil {
+				return nil, err
+			}
 		}
-		order.Items = append(order.Items, orderItem)
-		totalAmount += product.Price * float64(item.Quantity)
 	}
 
-	order.TotalAmount = totalAmount
+	// Computed from rounded line totals and option fees, as the database verifies it at commit
+	order.TotalAmount = order.ComputeTotal()
+
+	// The ordered products are locked, so a concurrent submission of the same order sees this one once it commits
+	if checkDuplicate {
+		if err = s.checkDuplicate(ctx, tx, order); err != nil {
+			return nil, err
+		}
+	}
+
+	// Taken last, as it blocks other checkouts taking a number until commit
+	if order.Number, err = s.numbers.NextTx(ctx, tx, order.CreatedAt.Year()); err != nil {
+		return nil, fmt.Errorf("could not assign order number: %w", err)
+	}
 
 	// Create order in database
 	if err = s.orderRepo.CreateTx(ctx, tx, order); err != nil {
 		return nil, fmt.Errorf("could not create order: %w", err)
 	}
+	events := order.CreationEvents()
+	for i := range events {
+		events[i].Region = s.region
+	}
+	if err = s.eventRepo.AppendTx(ctx, tx, events); err != nil {
+		return nil, fmt.Errorf("could not append order events: %w", err)
+	}
 
 	// Commit transaction
 	if err = tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("could not commit transaction: %w", err)
 	}
 
+	s.events.Publish(ctx, event.OrderCreated{Order: order})
+	s.publishStockChanges(ctx, state.movements)
+	return order, nil
+}
+
+// checkDuplicate returns a *DuplicateOrderError if the user placed an order like order within the duplicate window.
+func (s *OrderService) checkDuplicate(ctx context.Context, tx pgx.Tx, order *domain.Order) error {
+	duplicate, err := s.orderRepo.FindDuplicateTx(ctx, tx, order, order.CreatedAt.Add(-s.dupWindow))
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil
+		}
+		return fmt.Errorf("could not check for duplicate orders: %w", err)
+	}
+	return &DuplicateOrderError{Order: duplicate}
+}
+
+// publishStockChanges publishes committed stock movements.
+func (s *OrderService) publishStockChanges(ctx context.Context, movements []domain.StockMovement) {
+	for _, m := range movements {
+		s.events.Publish(ctx, event.StockChanged(m))
+	}
+}
+
+// NumberSettings returns the format of new order numbers.
+func (s *OrderService) NumberSettings(ctx context.Context) (*domain.OrderNumberSettings, error) {
+	settings, err := s.numbers.FindSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("OrderService.NumberSettings: %w", err)
+	}
+	return settings, nil
+}
+
+// ConfigureNumbers changes the format of new order numbers. Existing orders keep their numbers
+// and the sequence of the year continues.
+// Returns domain.ErrInvalidOrderNumberSettings if the format is invalid.
+func (s *OrderService) ConfigureNumbers(ctx context.Context, settings *domain.OrderNumberSettings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	if err := s.numbers.SaveSettings(ctx, settings); err != nil {
+		return fmt.Errorf("OrderService.ConfigureNumbers: %w", err)
+	}
+	return nil
+}
+
+// reservation is the state of an order being reserved.
+type reservation struct {
+	tx        pgx.Tx
+	order     *domain.Order
+	movements []domain.StockMovement // Allocations, published after commit
+}
+
+// lockProduct finds the product with a row lock (FOR UPDATE), so concurrent checkouts cannot allocate its stock.
+func (s *OrderService) lockProduct(ctx context.Context, tx pgx.Tx, productID uuid.UUID) (*domain.Product, error) {
+	const op = "OrderService.lockProduct"
+
+	product, err := s.productRepo.FindByIDTx(ctx, tx, productID)
+	if err != nil {
+		if errors.Is(err, repository.ErrProductNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return product, nil
+}
+
+// reserveSlot reserves one order of the delivery slot's capacity and returns it as the order's delivery window.
+// The reservation locks the slot until commit, so concurrent checkouts cannot overbook it.
+// Returns ErrDeliverySlotUnavailable if the slot does not exist, is full, starts before the order is placed
+// or belongs to another region than the order. Orders without a region, when none is configured, take slots of any region.
+func (s *OrderService) reserveSlot(ctx context.Context, tx pgx.Tx, slotID uuid.UUID, order *domain.Order) (*domain.OrderDelivery, error) {
+	slot, err := s.slotRepo.ReserveTx(ctx, tx, slotID)
+	if err != nil {
+		if errors.Is(err, repository.ErrDeliverySlotNotFound) || errors.Is(err, repository.ErrDeliverySlotFull) {
+			return nil, fmt.Errorf("%w: %w", ErrDeliverySlotUnavailable, err)
+		}
+		return nil, fmt.Errorf("could not reserve delivery slot: %w", err)
+	}
+	if !slot.StartsAt.After(order.CreatedAt) {
+		return nil, fmt.Errorf("%w: slot %s has started", ErrDeliverySlotUnavailable, slotID)
+	}
+	if order.Region != "" && slot.Region != order.Region {
+		return nil, fmt.Errorf("%w: slot %s is in region %q, not %q", ErrDeliverySlotUnavailable, slotID, slot.Region, order.Region)
+	}
+	return slot.Delivery(), nil
+}
+
+// allocate allocates stock of the locked product to the order and adds the order line.
+// Component lines of a bundle reference the bundle line and have no price of their own.
+// Stock was checked by the checkout pipeline, the ledger still refuses to make it negative.
+// Sandbox orders only add the line, their stock is not allocated.
+func (s *OrderService) allocate(ctx context.Context, c *reservation, product *domain.Product, quantity int, bundleItemID *uuid.UUID) error {
+	if !c.order.Sandbox {
+		// Allocate stock to the order in the ledger, which also decreases the product quantity
+		allocation := &domain.StockMovement{
+			ID:        uuid.New(),
+			ProductID: product.ID,
+			Delta:     -quantity,
+			Reason:    domain.StockReasonAllocation,
+			OrderID:   &c.order.ID,
+			CreatedAt: c.order.CreatedAt,
+		}
+		if err := s.stockRepo.RecordTx(ctx, c.tx, allocation); err != nil {
+			if errors.Is(err, repository.ErrNegativeStock) {
+				telemetry.RecordStockDecrementFailure(ctx, "insufficient_stock")
+				return fmt.Errorf("%w: insufficient stock for product %s", ErrInsufficientStock, product.ID)
+			}
+			telemetry.RecordStockDecrementFailure(ctx, "error")
+			return fmt.Errorf("could not allocate stock: %w", err)
+		}
+		c.movements = append(c.movements, *allocation)
+		product.Quantity -= quantity
+	}
+
+	item := domain.OrderItem{
+		ID:           uuid.New(),
+		ProductID:    product.ID,
+		Quantity:     quantity,
+		BundleItemID: bundleItemID,
+	}
+	if bundleItemID == nil {
+		item.PriceAtPurchase = product.Price // Save price at time of purchase
+	}
+	c.order.Items = append(c.order.Items, item)
+	return nil
+}
+
+// compensate undoes completed checkout steps after a failure: reverses the payment, if any,
+// and cancels the order, which releases its stock. Compensation failures are logged,
+// the order then stays reserved and needs manual attention.
+func (s *OrderService) compensate(ctx context.Context, orderID uuid.UUID, reversePayment func(context.Context) error, cause error) {
+	const op = "OrderService.compensate"
+
+	// Compensation must complete even if the request was cancelled
+	ctx = context.WithoutCancel(ctx)
+	log := s.logger.WithTrace(ctx)
+	trace.SpanFromContext(ctx).AddEvent("checkout.compensate", trace.WithAttributes(
+		attribute.String("order.id", orderID.String()),
+		attribute.String("cause", cause.Error()),
+	))
+
+	if reversePayment != nil {
+		if err := reversePayment(ctx); err != nil {
+			log.Error("failed to reverse payment", "op", op, "order_id", orderID, "error", err)
+		}
+	}
+	if _, err := s.applyEvent(ctx, orderID, domain.OrderEvent{Type: domain.OrderEventCancelled}); err != nil {
+		log.Error("failed to cancel order", "op", op, "order_id", orderID, "error", err)
+	}
+}
+
+// orderStatusTemplates lists the notification templates of status changes customers are notified of.
+var orderStatusTemplates = map[string]string{
+	domain.OrderEventShipped:   notification.TemplateOrderShipped,
+	domain.OrderEventDelivered: notification.TemplateOrderDelivered,
+}
+
+// ChangeStatus applies a status event (paid, shipped, delivered or cancelled) to the order.
+// Customers are notified of shipped and delivered orders through the channels they opted in to, e.g. SMS.
+// Cancelling releases the order's stock and refunds the rest of its payments made through payment providers.
+// Returns domain.ErrInvalidOrderTransition if the event is not allowed in the current status.
+func (s *OrderService) ChangeStatus(ctx context.Context, orderID uuid.UUID, eventType string) (*domain.Order, error) {
+	const op = "OrderService.ChangeStatus"
+
+	if !domain.IsOrderStatusEvent(eventType) {
+		return nil, fmt.Errorf("%w: unknown event type %q", domain.ErrInvalidOrderTransition, eventType)
+	}
+
+	order, err := s.applyEvent(ctx, orderID, domain.OrderEvent{Type: eventType})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachPayments(ctx, order, time.Time{}); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Notification failures must not fail the already changed status
+	if template, ok := orderStatusTemplates[eventType]; ok {
+		msg := notification.Message{
+			Category: domain.NotificationCategoryOrderUpdates,
+			Template: template,
+			Data:     map[string]string{"OrderNumber": order.Number},
+			Locale:   order.Locale,
+		}
+		if err := s.notifier.Notify(ctx, order.UserID, msg); err != nil {
+			s.logger.WithTrace(ctx).Error("failed to send order status notification", "op", op, "order_id", orderID, "status", order.Status, "error", err)
+		}
+	}
+	if eventType != domain.OrderEventCancelled {
+		return order, nil
+	}
+
+	// Refund failures must not fail the already cancelled order, the rest can be refunded manually
+	for _, charge := range order.Payments {
+		if charge.Kind != domain.PaymentKindCharge || !charge.ThroughProvider() {
+			continue
+		}
+		refundable := order.RefundableAmount(charge.ID)
+		if refundable == 0 {
+			continue
+		}
+		refunded, err := s.Refund(ctx, orderID, charge.ID, refundable, "")
+		if err != nil {
+			s.logger.WithTrace(ctx).Error("failed to refund payment of cancelled order", "op", op,
+				"order_id", orderID, "payment_id", charge.ID, "error", err)
+			continue
+		}
+		order.Payments = refunded.Payments
+	}
 	return order, nil
 }
+
+// applyEvent appends the event to the order's event log and updates the order projection in one transaction.
+// Cancellation also releases the allocated stock in the ledger and the reserved delivery slot.
+func (s *OrderService) applyEvent(ctx context.Context, orderID uuid.UUID, event domain.OrderEvent) (_ *domain.Order, err error) {
+	const op = "OrderService.applyEvent"
+
+	order, version, err := s.loadOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if event, err = nextEvent(order, version, event); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not begin transaction: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				s.logger.Error("error rolling back transaction", "rollback_error", rbErr, "original_error", err)
+			}
+		}
+	}()
+
+	released, err := s.appendEventTx(ctx, tx, order, event)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: could not commit transaction: %w", op, err)
+	}
+	s.publishStockChanges(ctx, released)
+	return order, nil
+}
+
+// loadOrder rebuilds the current order from its event log.
+// Returns the order and the number of its events.
+func (s *OrderService) loadOrder(ctx context.Context, orderID uuid.UUID) (*domain.Order, int, error) {
+	const op = "OrderService.loadOrder"
+
+	events, err := s.eventRepo.FindByOrderID(ctx, orderID, time.Time{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(events) == 0 {
+		return nil, 0, ErrOrderNotFound
+	}
+	order, err := domain.ReplayOrder(events)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: replay order %s: %w", op, orderID, err)
+	}
+	return order, len(events), nil
+}
+
+// orderMoney returns the formatter of the currency and locale the order was placed with.
+// Orders without recorded settings use the configured ones.
+func (s *OrderService) orderMoney(order *domain.Order) (*money.Formatter, error) {
+	f, err := s.money.In(order.Currency, order.Locale)
+	if err != nil {
+		return nil, fmt.Errorf("order %s: %w", order.ID, err)
+	}
+	return f, nil
+}
+
+// nextEvent fills in the identity of the event following version events of the order and applies it to the order.
+func nextEvent(order *domain.Order, version int, event domain.OrderEvent) (domain.OrderEvent, error) {
+	event.ID = uuid.New()
+	event.OrderID = order.ID
+	event.Sequence = version + 1
+	event.CreatedAt = time.Now()
+	return event, order.Apply(event)
+}
+
+// appendEventTx appends the applied event and updates the order projection within the transaction.
+// Cancellation also releases the allocated stock in the ledger, the releases are returned for publishing after commit,
+// and the order's reservation of its delivery slot. Sandbox orders hold neither.
+func (s *OrderService) appendEventTx(ctx context.Context, tx pgx.Tx, order *domain.Order, event domain.OrderEvent) ([]domain.StockMovement, error) {
+	const op = "OrderService.appendEventTx"
+
+	event.Region = s.region
+	if err := s.eventRepo.AppendTx(ctx, tx, []domain.OrderEvent{event}); err != nil {
+		if errors.Is(err, repository.ErrOrderEventConflict) {
+			return nil, ErrOrderConflict
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	var released []domain.StockMovement
+	if event.Type == domain.OrderEventCancelled && !order.Sandbox {
+		for _, item := range order.Items {
+			if !item.AllocatesStock() {
+				continue
+			}
+			release := &domain.StockMovement{
+				ID:        uuid.New(),
+				ProductID: item.ProductID,
+				Delta:     item.Quantity,
+				Reason:    domain.StockReasonRelease,
+				OrderID:   &order.ID,
+				CreatedAt: event.CreatedAt,
+			}
+			if err := s.stockRepo.RecordTx(ctx, tx, release); err != nil {
+				return nil, fmt.Errorf("%s: release stock: %w", op, err)
+			}
+			released = append(released, *release)
+		}
+		if order.Delivery != nil {
+			if err := s.slotRepo.ReleaseTx(ctx, tx, order.Delivery.SlotID); err != nil {
+				return nil, fmt.Errorf("%s: release delivery slot: %w", op, err)
+			}
+		}
+	}
+	if err := s.orderRepo.UpdateStatusTx(ctx, tx, order); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return released, nil
+}
+
+// GetOrder returns the current order with its payment ledger and disputes.
+// Returns ErrOrderNotFound.
+func (s *OrderService) GetOrder(ctx context.Context, orderID uuid.UUID) (*domain.Order, error) {
+	const op = "OrderService.GetOrder"
+
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := s.attachPayments(ctx, order, time.Time{}); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return order, nil
+}
+
+// ResendConfirmation sends the confirmation of a paid order to the customer again, e.g. when it was lost,
+// through the channels the customer opted in to for order updates.
+// Returns ErrOrderNotFound and ErrOrderNotConfirmed if the order was not paid or was cancelled.
+func (s *OrderService) ResendConfirmation(ctx context.Context, orderID uuid.UUID) (*domain.Order, error) {
+	const op = "OrderService.ResendConfirmation"
+
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	switch order.Status {
+	case domain.OrderStatusPaid, domain.OrderStatusShipped, domain.OrderStatusDelivered:
+	default:
+		return nil, fmt.Errorf("%w: order is %s", ErrOrderNotConfirmed, order.Status)
+	}
+
+	f, err := s.orderMoney(order)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := s.notifier.Notify(ctx, order.UserID, confirmationMessage(order, f)); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return order, nil
+}
+
+// confirmationMessage returns the confirmation of the paid order in its locale, with its total formatted by f.
+func confirmationMessage(order *domain.Order, f *money.Formatter) notification.Message {
+	return notification.Message{
+		Category: domain.NotificationCategoryOrderUpdates,
+		Template: notification.TemplateOrderConfirmation,
+		Data:     map[string]string{"OrderNumber": order.Number, "Total": f.Format(order.TotalAmount)},
+		Locale:   order.Locale,
+	}
+}
+
+// OrderPage is a page of a user's orders.
+type OrderPage struct {
+	Orders []domain.Order // Newest first, without payments
+	Total  int            // Number of the user's orders
+}
+
+// ListUserOrders returns a page of the user's orders with their items.
+func (s *OrderService) ListUserOrders(ctx context.Context, userID uuid.UUID, offset, limit int) (*OrderPage, error) {
+	orders, total, err := s.orderRepo.FindByUserID(ctx, userID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("OrderService.ListUserOrders: %w", err)
+	}
+	if orders == nil {
+		orders = []domain.Order{}
+	}
+	return &OrderPage{Orders: orders, Total: total}, nil
+}
+
+// Events returns the event log of the order in sequence.
+func (s *OrderService) Events(ctx context.Context, orderID uuid.UUID) ([]domain.OrderEvent, error) {
+	const op = "OrderService.Events"
+
+	events, err := s.eventRepo.FindByOrderID(ctx, orderID, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(events) == 0 {
+		return nil, ErrOrderNotFound
+	}
+	return events, nil
+}
+
+// StateAt rebuilds the order as it was at the given time from its event log.
+// Returns ErrOrderNotFound if the order did not exist at that time.
+func (s *OrderService) StateAt(ctx context.Context, orderID uuid.UUID, at time.Time) (*domain.Order, error) {
+	const op = "OrderService.StateAt"
+
+	events, err := s.eventRepo.FindByOrderID(ctx, orderID, at)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(events) == 0 {
+		return nil, ErrOrderNotFound
+	}
+	order, err := domain.ReplayOrder(events)
+	if err != nil {
+		return nil, fmt.Errorf("%s: replay order %s: %w", op, orderID, err)
+	}
+	if err := s.attachPayments(ctx, order, at); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return order, nil
+}
+
+// orderOutcome classifies the result of an order creation for metrics.
+func orderOutcome(err error) string {
+	switch {
+	case err == nil:
+		return telemetry.OutcomeSuccess
+	case errors.Is(err, ErrProductNotFound):
+		return "product_not_found"
+	case errors.Is(err, ErrProductUnavailable):
+		return "product_unavailable"
+	case errors.Is(err, ErrInsufficientStock):
+		return "insufficient_stock"
+	case errors.Is(err, ErrAgeRestricted):
+		return "age_restricted"
+	case errors.Is(err, ErrQuantityLimit):
+		return "quantity_limit"
+	case errors.Is(err, ErrPaymentFailed):
+		return "payment_failed"
+	case errors.Is(err, ErrPossibleDuplicateOrder):
+		return "possible_duplicate"
+	default:
+		return "error"
+	}
+}
+
+// OrderTotalsReport contains orders whose stored total differs from the sum of their items.
+type OrderTotalsReport struct {
+	Mismatches []domain.OrderTotalMismatch
+	Repaired   int // Number of repaired orders, 0 unless repair was requested
+}
+
+// CheckTotals finds up to limit orders whose total does not match the sum of item price × quantity.
+// If repair is true, their totals are recomputed from the items.
+func (s *OrderService) CheckTotals(ctx context.Context, limit int, repair bool) (*OrderTotalsReport, error) {
+	const op = "OrderService.CheckTotals"
+
+	mismatches, err := s.orderRepo.FindTotalMismatches(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	report := &OrderTotalsReport{Mismatches: mismatches}
+	if report.Mismatches == nil {
+		report.Mismatches = []domain.OrderTotalMismatch{}
+	}
+	for _, m := range mismatches {
+		s.logger.WithTrace(ctx).Warn("order total mismatch", "op", op, "order_id", m.OrderID,
+			"stored_total", m.StoredTotal, "computed_total", m.ComputedTotal)
+		if !repair {
+			continue
+		}
+		if err := s.orderRepo.RepairTotal(ctx, m.OrderID); err != nil {
+			return report, fmt.Errorf("%s: repair order %s: %w", op, m.OrderID, err)
+		}
+		report.Repaired++
+	}
+	return report, nil
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"product-api/internal/storage"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrOrderExportPending is returned while the customer's order export is generated in the background.
+	ErrOrderExportPending = errors.New("order export is being generated")
+	// ErrInvalidExportFormat is returned for export formats other than csv and json.
+	ErrInvalidExportFormat = errors.New("export format must be csv or json")
+)
+
+// Order export formats.
+const (
+	OrderExportCSV  = "csv"
+	OrderExportJSON = "json"
+)
+
+// orderExportPageSize is the number of orders read from the database at once while generating an export.
+const orderExportPageSize = 500
+
+// orderCSVHeader lists columns of the CSV order export, one row per order item.
+// Order options are repeated on every item row, separated by semicolons.
+var orderCSVHeader = []string{
+	"order_id", "number", "created_at", "status", "currency", "total_amount", "product_id", "quantity", "price_at_purchase", "bundle",
+	"options", "gift_message",
+}
+
+// orderExportContentTypes maps export formats to the content types of their documents.
+var orderExportContentTypes = map[string]string{
+	OrderExportCSV:  "text/csv",
+	OrderExportJSON: "application/json",
+}
+
+// OrderExport is a generated export of the customer's order history: a download URL, or its contents
+// if the storage backend cannot presign URLs.
+type OrderExport struct {
+	URL         string          // Presigned download URL
+	Object      *storage.Object // Set if URL is empty, its body must be closed by the caller
+	GeneratedAt time.Time
+}
+
+// orderExportRecord is an order in the JSON order export.
+type orderExportRecord struct {
+	ID          uuid.UUID
+	Number      string
+	Status      string
+	CreatedAt   time.Time
+	Currency    string // Empty for orders placed before settings were recorded
+	TotalAmount float64
+	Items       []domain.OrderItem
+	Options     []domain.OrderOption `json:",omitempty"`
+}
+
+// OrderExportService exports customers' own order history, as part of their self-service access to their data.
+// Exports are kept in the object storage and generated at most once per interval for each customer and format,
+// so repeated requests download the stored export instead of reading the whole history again.
+// Exports of customers with many orders are generated in the background.
+type OrderExportService struct {
+	orders      repository.OrderRepository
+	objects     storage.Storage
+	syncLimit   int
+	interval    time.Duration
+	downloadTTL time.Duration
+	logger      logger.Logger
+
+	mu      sync.Mutex
+	running map[string]bool // Keys of exports being generated by this instance
+}
+
+// NewOrderExportService creates a new order export service. Exports of customers with more than syncLimit orders
+// are generated in the background, exports are regenerated after interval and their download URLs are valid for downloadTTL.
+func NewOrderExportService(orders repository.OrderRepository, objects storage.Storage, syncLimit int, interval, downloadTTL time.Duration, logger logger.Logger) *OrderExportService {
+	return &OrderExportService{
+		orders:      orders,
+		objects:     objects,
+		syncLimit:   syncLimit,
+		interval:    interval,
+		downloadTTL: downloadTTL,
+		logger:      logger,
+		running:     make(map[string]bool),
+	}
+}
+
+// Export returns the export of the user's orders in the format, csv or json, generating it if the stored one
+// is older than the interval. Exports of users with many orders are generated in the background: ErrOrderExportPending
+// is returned until the export is stored. Background exports interrupted by a restart are started again by the next request.
+// Returns ErrInvalidExportFormat for unknown formats.
+func (s *OrderExportService) Export(ctx context.Context, userID uuid.UUID, format string) (*OrderExport, error) {
+	const op = "OrderExportService.Export"
+
+	if _, ok := orderExportContentTypes[format]; !ok {
+		return nil, ErrInvalidExportFormat
+	}
+	key := storage.PrefixExports + "orders/" + userID.String() + "." + format
+	info, err := s.objects.Stat(ctx, key)
+	switch {
+	case err == nil && time.Since(info.LastModified) < s.interval:
+		return s.download(ctx, key, info.LastModified)
+	case err != nil && !errors.Is(err, storage.ErrNotFound):
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !s.start(key) {
+		return nil, ErrOrderExportPending
+	}
+	_, total, err := s.orders.FindByUserID(ctx, userID, 0, 1)
+	if err != nil {
+		s.finish(key)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if total > s.syncLimit {
+		go func() {
+			defer s.finish(key)
+			// The export outlives the request that started it
+			ctx := context.WithoutCancel(ctx)
+			if err := s.generate(ctx, userID, format, key); err != nil {
+				s.logger.WithTrace(ctx).Error("failed to generate order export", "op", op, "user_id", userID, "format", format, "error", err)
+				return
+			}
+			s.logger.WithTrace(ctx).Info("order export generated", "op", op, "user_id", userID, "format", format, "orders", total)
+		}()
+		return nil, ErrOrderExportPending
+	}
+
+	defer s.finish(key)
+	if err := s.generate(ctx, userID, format, key); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return s.download(ctx, key, time.Now())
+}
+
+// start marks the export with the key as being generated, false if it already is.
+func (s *OrderExportService) start(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[key] {
+		return false
+	}
+	s.running[key] = true
+	return true
+}
+
+// finish marks the export with the key as no longer being generated.
+func (s *OrderExportService) finish(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, key)
+}
+
+// generate reads all orders of the user, newest first, and stores them under the key in the format.
+func (s *OrderExportService) generate(ctx context.Context, userID uuid.UUID, format, key string) error {
+	var buf bytes.Buffer
+	var records []orderExportRecord
+	cw := csv.NewWriter(&buf)
+	if format == OrderExportCSV {
+		cw.Write(orderCSVHeader)
+	}
+
+	for offset := 0; ; offset += orderExportPageSize {
+		orders, total, err := s.orders.FindByUserID(ctx, userID, offset, orderExportPageSize)
+		if err != nil {
+			return fmt.Errorf("load orders: %w", err)
+		}
+		for i := range orders {
+			if format == OrderExportCSV {
+				for _, row := range orderCSVRows(&orders[i]) {
+					cw.Write(row)
+				}
+				continue
+			}
+			o := &orders[i]
+			records = append(records, orderExportRecord{
+				ID: o.ID, Number: o.Number, Status: o.Status, CreatedAt: o.CreatedAt,
+				Currency: o.Currency, TotalAmount: o.TotalAmount, Items: o.Items, Options: o.Options,
+			})
+		}
+		if len(orders) == 0 || offset+len(orders) >= total {
+			break
+		}
+	}
+
+	if format == OrderExportCSV {
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return fmt.Errorf("encode orders: %w", err)
+		}
+	} else {
+		if records == nil {
+			records = []orderExportRecord{}
+		}
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(records); err != nil {
+			return fmt.Errorf("encode orders: %w", err)
+		}
+	}
+
+	if err := s.objects.Put(ctx, key, bytes.NewReader(buf.Bytes()), int64(buf.Len()), orderExportContentTypes[format]); err != nil {
+		return fmt.Errorf("store export: %w", err)
+	}
+	return nil
+}
+
+// orderCSVRows returns the order as rows of orderCSVHeader columns, one per item.
+func orderCSVRows(o *domain.Order) [][]string {
+	order := []string{
+		o.ID.String(),
+		o.Number,
+		o.CreatedAt.UTC().Format(time.RFC3339),
+		o.Status,
+		o.Currency,
+		strconv.FormatFloat(o.TotalAmount, 'f', 2, 64),
+	}
+	options := []string{o.OptionNames(";"), o.GiftMessage()}
+	if len(o.Items) == 0 {
+		return [][]string{append(append(order, "", "", "", ""), options...)}
+	}
+	rows := make([][]string, 0, len(o.Items))
+	for _, item := range o.Items {
+		row := append(order[:len(order):len(order)],
+			item.ProductID.String(),
+			strconv.Itoa(item.Quantity),
+			strconv.FormatFloat(item.PriceAtPurchase, 'f', 2, 64),
+			strconv.FormatBool(item.Bundle),
+		)
+		rows = append(rows, append(row, options...))
+	}
+	return rows
+}
+
+// download returns the stored export with the key, generated at the given time.
+func (s *OrderExportService) download(ctx context.Context, key string, generatedAt time.Time) (*OrderExport, error) {
+	const op = "OrderExportService.download"
+
+	url, err := s.objects.PresignGet(ctx, key, s.downloadTTL)
+	if err == nil {
+		return &OrderExport{URL: url, GeneratedAt: generatedAt}, nil
+	}
+	if !errors.Is(err, storage.ErrPresignNotSupported) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	obj, err := s.objects.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &OrderExport{Object: obj, GeneratedAt: obj.LastModified}, nil
+}
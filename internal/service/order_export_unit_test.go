@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"product-api/internal/domain"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"product-api/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newOrderExportService(t *testing.T, syncLimit int) (*service.OrderExportService, *mocks.MockOrderRepository) {
+	objects, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	orders := mocks.NewMockOrderRepository(t)
+	return service.NewOrderExportService(orders, objects, syncLimit, time.Hour, time.Minute, discardLogger{}), orders
+}
+
+// readExport returns the contents of an export served by a backend without presigned URLs.
+func readExport(t *testing.T, export *service.OrderExport) string {
+	require.NotNil(t, export.Object)
+	defer export.Object.Body.Close()
+	body, err := io.ReadAll(export.Object.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestOrderExport_Unit_StoredExportIsReused(t *testing.T) {
+	svc, orders := newOrderExportService(t, 10)
+	ctx := context.Background()
+	userID := uuid.New()
+	order := domain.Order{
+		ID: uuid.New(), Number: "ORD-2024-000001", UserID: userID, Status: domain.OrderStatusPaid,
+		CreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), TotalAmount: 29.99, OrderSettings: domain.OrderSettings{Currency: "USD"},
+		Items: []domain.OrderItem{
+			{ProductID: uuid.New(), Quantity: 2, PriceAtPurchase: 10},
+			{ProductID: uuid.New(), Quantity: 1, PriceAtPurchase: 5},
+		},
+		Options: []domain.OrderOption{
+			{Option: domain.OrderOptionGiftWrap, Fee: 4.99},
+			{Option: domain.OrderOptionGiftMessage, Message: "Happy birthday!"},
+		},
+	}
+	orders.EXPECT().FindByUserID(mock.Anything, userID, 0, mock.Anything).Return([]domain.Order{order}, 1, nil).Times(2)
+
+	export, err := svc.Export(ctx, userID, service.OrderExportCSV)
+
+	require.NoError(t, err)
+	assert.Equal(t, "order_id,number,created_at,status,currency,total_amount,product_id,quantity,price_at_purchase,bundle,options,gift_message\n"+
+		order.ID.String()+",ORD-2024-000001,2024-03-01T12:00:00Z,paid,USD,29.99,"+order.Items[0].ProductID.String()+",2,10.00,false,gift_wrap;gift_message,Happy birthday!\n"+
+		order.ID.String()+",ORD-2024-000001,2024-03-01T12:00:00Z,paid,USD,29.99,"+order.Items[1].ProductID.String()+",1,5.00,false,gift_wrap;gift_message,Happy birthday!\n",
+		readExport(t, export))
+
+	export, err = svc.Export(ctx, userID, service.OrderExportCSV)
+	require.NoError(t, err)
+	assert.Contains(t, readExport(t, export), "ORD-2024-000001", "stored export is served without reading orders again")
+
+	_, err = svc.Export(ctx, userID, "xml")
+	assert.ErrorIs(t, err, service.ErrInvalidExportFormat)
+}
+
+func TestOrderExport_Unit_LargeHistoryIsExportedInBackground(t *testing.T) {
+	svc, orders := newOrderExportService(t, 1)
+	ctx := context.Background()
+	userID := uuid.New()
+	history := []domain.Order{
+		{ID: uuid.New(), Number: "ORD-2024-000002", UserID: userID, Status: domain.OrderStatusCreated},
+		{ID: uuid.New(), Number: "ORD-2024-000001", UserID: userID, Status: domain.OrderStatusDelivered},
+	}
+	release := make(chan struct{})
+	orders.EXPECT().FindByUserID(mock.Anything, userID, 0, 1).Return(history[:1], 2, nil).Once()
+	orders.EXPECT().FindByUserID(mock.Anything, userID, 0, mock.Anything).RunAndReturn(
+		func(context.Context, uuid.UUID, int, int) ([]domain.Order, int, error) {
+			<-release
+			return history, 2, nil
+		}).Once()
+
+	_, err := svc.Export(ctx, userID, service.OrderExportJSON)
+	require.ErrorIs(t, err, service.ErrOrderExportPending)
+	_, err = svc.Export(ctx, userID, service.OrderExportJSON)
+	require.ErrorIs(t, err, service.ErrOrderExportPending, "export is generated once")
+	close(release)
+
+	var export *service.OrderExport
+	require.Eventually(t, func() bool {
+		export, err = svc.Export(ctx, userID, service.OrderExportJSON)
+		return !errors.Is(err, service.ErrOrderExportPending)
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, err)
+	var records []struct{ Number string }
+	require.NoError(t, json.Unmarshal([]byte(readExport(t, export)), &records))
+	assert.Equal(t, []struct{ Number string }{{"ORD-2024-000002"}, {"ORD-2024-000001"}}, records)
+}
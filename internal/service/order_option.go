@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"slices"
+	"unicode/utf8"
+)
+
+var (
+	// ErrOrderOptionUnavailable is returned when a checkout chooses an order option that is not offered.
+	ErrOrderOptionUnavailable = errors.New("order option is not offered")
+	// ErrGiftMessageTooLong is returned when a gift message exceeds domain.MaxGiftMessageLength characters.
+	ErrGiftMessageTooLong = errors.New("gift message is too long")
+)
+
+// orderOptionNames lists the known order options in the order they are recorded on orders.
+var orderOptionNames = []string{domain.OrderOptionGiftWrap, domain.OrderOptionGiftMessage, domain.OrderOptionPriorityHandling}
+
+// OrderOptionFees are the fees of the offered order options by option name, in the currency of prices.
+// Options not listed are not offered, options listed with a zero fee are free.
+type OrderOptionFees map[string]float64
+
+// NewOrderOptionFees returns the fees of the offered order options. Returns an error for unknown options.
+func NewOrderOptionFees(fees map[string]float64) (OrderOptionFees, error) {
+	for option := range fees {
+		if !slices.Contains(orderOptionNames, option) {
+			return nil, fmt.Errorf("unknown order option %q", option)
+		}
+	}
+	return fees, nil
+}
+
+// OrderOptionsInput selects the order-level options of a checkout. The zero value selects none.
+type OrderOptionsInput struct {
+	GiftWrap         bool
+	GiftMessage      string // Printed on a gift card in the parcel, empty for none
+	PriorityHandling bool
+}
+
+// resolve returns the chosen options with their fees.
+// Returns ErrOrderOptionUnavailable if an option is not offered and ErrGiftMessageTooLong.
+func (f OrderOptionFees) resolve(in OrderOptionsInput) ([]domain.OrderOption, error) {
+	if utf8.RuneCountInString(in.GiftMessage) > domain.MaxGiftMessageLength {
+		return nil, ErrGiftMessageTooLong
+	}
+
+	var options []domain.OrderOption
+	for _, option := range orderOptionNames {
+		chosen := option == domain.OrderOptionGiftWrap && in.GiftWrap ||
+			option == domain.OrderOptionGiftMessage && in.GiftMessage != "" ||
+			option == domain.OrderOptionPriorityHandling && in.PriorityHandling
+		if !chosen {
+			continue
+		}
+		fee, ok := f[option]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrOrderOptionUnavailable, option)
+		}
+		o := domain.OrderOption{Option: option, Fee: domain.RoundCents(fee)}
+		if option == domain.OrderOptionGiftMessage {
+			o.Message = in.GiftMessage
+		}
+		options = append(options, o)
+	}
+	return options, nil
+}
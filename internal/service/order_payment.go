@@ -0,0 +1,463 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/money"
+	"product-api/internal/payment"
+	"product-api/internal/repository"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// PaymentInput contains a payment of an order made outside checkout, e.g. a gift card or a deposit.
+type PaymentInput struct {
+	Method    string
+	Reference string // Gift card code, transfer reference or ID of a payment taken outside the API
+	Amount    float64
+}
+
+// RecordPayment adds a payment made outside checkout to the order's payment ledger.
+// The payment covering the rest of the total marks the order paid.
+// Returns domain.ErrInvalidPayment if the order does not accept the payment.
+func (s *OrderService) RecordPayment(ctx context.Context, orderID uuid.UUID, input PaymentInput) (*domain.Order, error) {
+	return s.recordPayment(ctx, orderID, domain.Payment{
+		Kind:      domain.PaymentKindCharge,
+		Method:    input.Method,
+		Reference: input.Reference,
+		Amount:    domain.RoundCents(input.Amount),
+	})
+}
+
+// PayOrder pays the outstanding amount of the user's order awaiting payment, e.g. after its pending payment
+// was declined, through the selected payment provider. Unlike at checkout, a declined payment leaves
+// the order awaiting payment, so the customer can try another payment method.
+// The order is confirmed to the customer once paid. Sandbox orders are paid with the sandbox provider.
+// Returns ErrOrderNotFound, ErrNotOrderOwner if the order belongs to another user, ErrUnknownPaymentProvider,
+// ErrPaymentPending if a payment of the order awaits confirmation, domain.ErrInvalidPayment if the order
+// does not await payment and ErrPaymentFailed if the payment is declined.
+func (s *OrderService) PayOrder(ctx context.Context, userID, orderID uuid.UUID, source PaymentSource) (*domain.Order, error) {
+	const op = "OrderService.PayOrder"
+
+	provider, ok := s.providers.Get(source.Provider)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPaymentProvider, source.Provider)
+	}
+	order, sequence, err := s.loadOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, ErrNotOrderOwner
+	}
+	if provider, err = s.payingProvider(order.Sandbox, provider); err != nil {
+		return nil, err
+	}
+	if err := s.attachPayments(ctx, order, time.Time{}); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	switch {
+	case order.PendingPayment != "":
+		return nil, ErrPaymentPending
+	case order.Status != domain.OrderStatusCreated || order.OutstandingAmount() <= 0:
+		return nil, fmt.Errorf("%w: %s order does not await payment", domain.ErrInvalidPayment, order.Status)
+	}
+	orderMoney, err := s.orderMoney(order)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Concurrent payments of the order are caught when the second one is recorded, and refunded
+	paid, err := s.charge(ctx, order, provider, source.Token, order.OutstandingAmount(), orderMoney.Currency, payAttemptKey(order.ID, sequence, provider.Name(), source.Token),
+		func(ctx context.Context, reversePayment func(context.Context) error, cause error) {
+			if reversePayment == nil {
+				return
+			}
+			if err := reversePayment(context.WithoutCancel(ctx)); err != nil {
+				s.logger.WithTrace(ctx).Error("failed to reverse payment", "op", op, "order_id", orderID, "cause", cause, "error", err)
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+	if paid.Status == domain.OrderStatusPaid {
+		s.sendConfirmation(ctx, op, paid)
+	}
+	return paid, nil
+}
+
+// paymentExpiryBatch is the most orders ExpirePayments cancels per call.
+const paymentExpiryBatch = 100
+
+// RunPaymentExpiry cancels orders whose payment is pending or failed for longer than timeout until ctx is done,
+// checking every pollInterval. Each check is recorded by hb.
+func (s *OrderService) RunPaymentExpiry(ctx context.Context, timeout, pollInterval time.Duration, hb *Heartbeat) {
+	const op = "OrderService.RunPaymentExpiry"
+
+	for {
+		_, err := s.ExpirePayments(ctx, time.Now().Add(-timeout))
+		hb.Beat(err)
+		if err != nil {
+			s.logger.Error("failed to expire payments", "op", op, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// ExpirePayments cancels up to paymentExpiryBatch created orders whose last pending or failed capture was recorded
+// before the time, which releases their stock and delivery slots and refunds their partial payments, and returns
+// the number of cancelled orders.
+// A pending capture completing after its order is cancelled is logged by ConfirmCapture, to be checked for a refund.
+// Orders paid or cancelled concurrently are skipped.
+func (s *OrderService) ExpirePayments(ctx context.Context, before time.Time) (int, error) {
+	const op = "OrderService.ExpirePayments"
+
+	ids, err := s.orderRepo.FindAwaitingPayment(ctx, before, paymentExpiryBatch)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	cancelled := 0
+	for _, id := range ids {
+		order, err := s.ChangeStatus(ctx, id, domain.OrderEventCancelled)
+		if errors.Is(err, domain.ErrInvalidOrderTransition) {
+			continue
+		}
+		if err != nil {
+			return cancelled, fmt.Errorf("%s: cancel order %s: %w", op, id, err)
+		}
+		s.logger.WithTrace(ctx).Warn("order payment expired, order cancelled", "op", op, "order_id", id, "pending_payment", order.PendingPayment)
+		cancelled++
+	}
+	return cancelled, nil
+}
+
+// payAttemptKey returns the provider idempotency key of a payment of the order with its event log at sequence.
+// Retries of a payment with the same payment method get the same key, so they charge the customer once;
+// another payment method or a payment after a failed pending capture, which appends events, gets a new key.
+func payAttemptKey(orderID uuid.UUID, sequence int, provider, token string) string {
+	method := sha256.Sum256([]byte(provider + ":" + token))
+	return fmt.Sprintf("%s:pay:%d:%s", orderID, sequence, hex.EncodeToString(method[:8]))
+}
+
+// payingProvider returns the sandbox provider for orders of sandbox accounts, otherwise the selected provider.
+// Returns ErrUnknownPaymentProvider if no sandbox provider is configured.
+func (s *OrderService) payingProvider(sandbox bool, selected payment.Provider) (payment.Provider, error) {
+	if !sandbox {
+		return selected, nil
+	}
+	provider, ok := s.providers.Sandbox()
+	if !ok {
+		return nil, fmt.Errorf("%w: no sandbox provider is configured", ErrUnknownPaymentProvider)
+	}
+	return provider, nil
+}
+
+// ConfirmCapture applies the outcome of a capture reported by the named payment provider's webhook.
+// A completed pending capture is recorded in the payment ledger, which marks the order paid, and the order is
+// confirmed to the customer; a failed one leaves the order awaiting payment, it can be paid again with PayOrder.
+// Outcomes of captures already recorded by checkout, e.g. repeated deliveries, and of captures the order does not
+// await are acknowledged and ignored; a completed capture of a cancelled order is logged, to be checked for a refund.
+// Returns ErrOrderConflict if the capture may still be recorded by a checkout in progress, so the provider retries.
+func (s *OrderService) ConfirmCapture(ctx context.Context, providerName string, update *payment.CaptureUpdate) error {
+	const op = "OrderService.ConfirmCapture"
+	log := s.logger.WithTrace(ctx)
+	attrs := []any{"op", op, "provider", providerName, "order_id", update.OrderID, "capture_id", update.ID}
+
+	_, err := s.paymentRepo.FindChargeByReference(ctx, providerName, update.ID)
+	if err == nil {
+		return nil // Recorded by checkout or an earlier delivery
+	}
+	if !errors.Is(err, repository.ErrPaymentNotFound) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	order, _, err := s.loadOrder(ctx, update.OrderID)
+	if err != nil {
+		if errors.Is(err, ErrOrderNotFound) {
+			log.Warn("capture of unknown order", attrs...)
+			return nil
+		}
+		return err
+	}
+
+	switch {
+	case order.PendingPayment == update.ID && update.Status == payment.CaptureFailed:
+		if _, err := s.applyEvent(ctx, order.ID, domain.OrderEvent{Type: domain.OrderEventPaymentFailed, PaymentID: update.ID}); err != nil {
+			return err
+		}
+		log.Warn("pending payment declined, order awaits payment", attrs...)
+		return nil
+	case order.PendingPayment == update.ID:
+		paid, err := s.recordPayment(ctx, order.ID, domain.Payment{
+			Kind:      domain.PaymentKindCharge,
+			Method:    update.Method,
+			Reference: update.ID,
+			Amount:    update.Amount.Major(),
+			Provider:  providerName,
+		})
+		if err != nil {
+			return err
+		}
+		log.Info("pending payment completed", append(attrs, "status", paid.Status)...)
+		if paid.Status == domain.OrderStatusPaid {
+			s.sendConfirmation(ctx, op, paid)
+		}
+		return nil
+	case update.Status == payment.CaptureFailed:
+		return nil // e.g. an authorization voided by checkout
+	case order.Status == domain.OrderStatusCreated:
+		return fmt.Errorf("%w: capture %s is not recorded yet", ErrOrderConflict, update.ID)
+	case order.Status == domain.OrderStatusCancelled:
+		// Checkout refunds captures it fails to confirm, others need a manual refund
+		log.Warn("captured payment of cancelled order", append(attrs, "amount", update.Amount.String())...)
+		return nil
+	default:
+		log.Warn("capture of order not awaiting payment", append(attrs, "status", order.Status)...)
+		return nil
+	}
+}
+
+// recordPayment appends the charge to the order's payment ledger. If the charge covers the rest of the total,
+// the paid event is appended in the same transaction.
+func (s *OrderService) recordPayment(ctx context.Context, orderID uuid.UUID, charge domain.Payment) (_ *domain.Order, err error) {
+	const op = "OrderService.recordPayment"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not begin transaction: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				s.logger.Error("error rolling back transaction", "rollback_error", rbErr, "original_error", err)
+			}
+		}
+	}()
+
+	order, version, err := s.lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	charge.ID = uuid.New()
+	charge.OrderID = orderID
+	charge.CreatedAt = time.Now()
+	if err = order.AddPayment(charge); err != nil {
+		return nil, err
+	}
+	if err = s.paymentRepo.CreateTx(ctx, tx, &charge); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if order.OutstandingAmount() == 0 {
+		paid := domain.OrderEvent{Type: domain.OrderEventPaid}
+		if charge.Provider != "" {
+			paid.PaymentID = charge.Reference
+		}
+		if paid, err = nextEvent(order, version, paid); err != nil {
+			return nil, err
+		}
+		if _, err = s.appendEventTx(ctx, tx, order, paid); err != nil { // Payments release no stock
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: could not commit transaction: %w", op, err)
+	}
+	return order, nil
+}
+
+// Refund refunds part of a charge of the order. Charges made through a payment provider are refunded
+// through it, refunds of other charges, including legacy card payments, are only recorded.
+// The refund is keyed by the caller's idempotency key (see refundID): a retry with the same key returns the order
+// without refunding again, without a key retries refund once as long as the first attempt was not recorded.
+// Returns ErrPaymentNotFound if the order has no such charge, domain.ErrInvalidRefund if the amount
+// exceeds the part of the charge not refunded yet, domain.ErrPaymentDisputed if the charge is disputed,
+// and ErrRefundFailed if the payment provider rejects it.
+func (s *OrderService) Refund(ctx context.Context, orderID, chargeID uuid.UUID, amount float64, idempotencyKey string) (_ *domain.Order, err error) {
+	const op = "OrderService.Refund"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not begin transaction: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				s.logger.Error("error rolling back transaction", "rollback_error", rbErr, "original_error", err)
+			}
+		}
+	}()
+
+	order, _, err := s.lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	id := refundID(order, chargeID, amount, idempotencyKey)
+	if slices.ContainsFunc(order.Payments, func(p domain.Payment) bool { return p.ID == id }) {
+		// Refunded by an earlier request with the key
+		if err = tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("%s: could not commit transaction: %w", op, err)
+		}
+		return order, nil
+	}
+	refund, err := s.refundTx(ctx, tx, order, id, chargeID, amount)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		s.logUnrecordedRefund(ctx, op, refund, err)
+		return nil, fmt.Errorf("%s: could not commit transaction: %w", op, err)
+	}
+	return order, nil
+}
+
+// refundID returns the ID of a refund of the amount of the charge, which is also its provider idempotency key.
+// Requests with an idempotency key get the ID of the key, so retries refund once. Without a key, the ID is
+// derived from the refunds of the charge recorded so far: a retry after a refund the provider made but the ledger
+// did not record gets the same ID, a refund after a recorded one gets a new ID.
+func refundID(order *domain.Order, chargeID uuid.UUID, amount float64, idempotencyKey string) uuid.UUID {
+	name := fmt.Sprintf("%s:refund:%.2f:", order.ID, domain.RoundCents(amount))
+	if idempotencyKey != "" {
+		name += "key:" + idempotencyKey
+	} else {
+		refunds := 0
+		for _, p := range order.Payments {
+			if p.RefundOf != nil && *p.RefundOf == chargeID {
+				refunds++
+			}
+		}
+		name += fmt.Sprintf("seq:%d", refunds)
+	}
+	return uuid.NewSHA1(chargeID, []byte(name))
+}
+
+// refundTx refunds part of a charge of the locked order through its payment provider, if any,
+// and appends the refund with the ID to the payment ledger within the transaction.
+// The refund ID is the provider's idempotency key, so retrying with the same ID does not refund twice.
+func (s *OrderService) refundTx(ctx context.Context, tx pgx.Tx, order *domain.Order, refundID, chargeID uuid.UUID, amount float64) (*domain.Payment, error) {
+	const op = "OrderService.refundTx"
+
+	charge := order.FindCharge(chargeID)
+	if charge == nil {
+		return nil, ErrPaymentNotFound
+	}
+
+	refund := domain.Payment{
+		ID:        refundID,
+		OrderID:   order.ID,
+		Kind:      domain.PaymentKindRefund,
+		Method:    charge.Method,
+		Amount:    domain.RoundCents(amount),
+		RefundOf:  &charge.ID,
+		Provider:  charge.Provider,
+		CreatedAt: time.Now(),
+	}
+	if err := order.AddRefund(refund); err != nil {
+		return nil, err
+	}
+
+	// The order stays locked during the provider call, so concurrent refunds cannot exceed the charge
+	if charge.Provider == domain.PaymentProviderLegacy {
+		s.logger.WithTrace(ctx).Warn("refund of legacy payment must be paid out-of-band", "op", op,
+			"order_id", order.ID, "payment_id", charge.ID, "refund_id", refund.ID, "amount", refund.Amount)
+	}
+	if charge.ThroughProvider() {
+		provider, ok := s.providers.Lookup(charge.Provider)
+		if !ok {
+			return nil, fmt.Errorf("%w: payment provider %q is not configured", ErrRefundFailed, charge.Provider)
+		}
+		orderMoney, err := s.orderMoney(order)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		refunded, err := provider.Refund(ctx, payment.RefundRequest{
+			CaptureID:      charge.Reference,
+			Amount:         money.FromMajor(refund.Amount, orderMoney.Currency),
+			IdempotencyKey: refund.ID.String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRefundFailed, err)
+		}
+		refund.Reference = refunded.ID
+		order.Payments[len(order.Payments)-1].Reference = refunded.ID
+	}
+
+	if err := s.paymentRepo.CreateTx(ctx, tx, &refund); err != nil {
+		s.logUnrecordedRefund(ctx, op, &refund, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &refund, nil
+}
+
+// logUnrecordedRefund logs a refund that may have been made by the payment provider but was not recorded.
+func (s *OrderService) logUnrecordedRefund(ctx context.Context, op string, refund *domain.Payment, err error) {
+	if refund.Reference == "" {
+		return
+	}
+	// Retrying with the same refund ID would not refund twice
+	s.logger.WithTrace(ctx).Error("failed to record refund", "op", op, "order_id", refund.OrderID,
+		"refund_id", refund.ID, "reference", refund.Reference, "error", err)
+}
+
+// lockOrder locks the order's payment ledger and rebuilds the order with its payments and disputes.
+// Returns the order and the number of its events.
+func (s *OrderService) lockOrder(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) (*domain.Order, int, error) {
+	const op = "OrderService.lockOrder"
+
+	payments, err := s.paymentRepo.FindByOrderIDTx(ctx, tx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, 0, ErrOrderNotFound
+		}
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	// Rebuilt after taking the lock, status changes of the order lock it as well
+	order, version, err := s.loadOrder(ctx, orderID)
+	if err != nil {
+		return nil, 0, err
+	}
+	order.Payments = payments
+	if order.Disputes, err = s.disputeRepo.FindByOrderIDTx(ctx, tx, orderID); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return order, version, nil
+}
+
+// attachPayments loads the order's payment ledger and disputes, only those created up to until if it is not zero.
+// Disputes have their current status.
+func (s *OrderService) attachPayments(ctx context.Context, order *domain.Order, until time.Time) error {
+	payments, err := s.paymentRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("load payments: %w", err)
+	}
+	order.Payments = nil
+	for _, p := range payments {
+		if until.IsZero() || !p.CreatedAt.After(until) {
+			order.Payments = append(order.Payments, p)
+		}
+	}
+
+	disputes, err := s.disputeRepo.FindByOrderID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("load disputes: %w", err)
+	}
+	order.Disputes = nil
+	for _, d := range disputes {
+		if until.IsZero() || !d.CreatedAt.After(until) {
+			order.Disputes = append(order.Disputes, d)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrRefundRequestNotFound is returned when a refund request is not found.
+var ErrRefundRequestNotFound = errors.New("refund request not found")
+
+// RefundRequestInput contains a refund requested by support.
+type RefundRequestInput struct {
+	PaymentID uuid.UUID // Charge to refund
+	Amount    float64
+	Items     []domain.RefundItem // Returned order lines to restock on approval
+	Reason    string
+}
+
+// RequestRefund records a refund request of the order on behalf of actor, to be approved or rejected by finance.
+// Nothing is refunded until the request is approved.
+// Returns domain.ErrInvalidRefundRequest if the charge, amount or returned items do not match the order.
+func (s *OrderService) RequestRefund(ctx context.Context, actor string, orderID uuid.UUID, input RefundRequestInput) (_ *domain.RefundRequest, err error) {
+	const op = "OrderService.RequestRefund"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not begin transaction: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				s.logger.Error("error rolling back transaction", "rollback_error", rbErr, "original_error", err)
+			}
+		}
+	}()
+
+	// The order lock serializes requests of the order, so pending requests cannot exceed the charge
+	order, _, err := s.lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	others, err := s.refundRepo.FindByOrderIDTx(ctx, tx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	request := &domain.RefundRequest{
+		ID:          uuid.New(),
+		OrderID:     orderID,
+		PaymentID:   input.PaymentID,
+		Amount:      domain.RoundCents(input.Amount),
+		Items:       input.Items,
+		Reason:      input.Reason,
+		Status:      domain.RefundRequestRequested,
+		RequestedBy: actor,
+		CreatedAt:   time.Now(),
+	}
+	if err = request.Validate(order, others); err != nil {
+		return nil, err
+	}
+	if err = s.refundRepo.CreateTx(ctx, tx, request); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = s.auditRefundTx(ctx, tx, request, domain.RefundActionRequested, actor, input.Reason); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: could not commit transaction: %w", op, err)
+	}
+	return request, nil
+}
+
+// ApproveRefund approves the pending refund request on behalf of actor: refunds the charge through its
+// payment provider and returns the returned items to stock, unless the order is a sandbox order, all recorded
+// in one transaction.
+// If the refund fails, the failure is added to the audit trail and the request stays pending.
+// Returns ErrRefundRequestNotFound, domain.ErrRefundRequestDecided if the request is not pending,
+// domain.ErrSelfApproval if actor requested it, domain.ErrInvalidRefund if the charge was refunded since,
+// domain.ErrPaymentDisputed if the charge is disputed and ErrRefundFailed if the payment provider rejects the refund.
+func (s *OrderService) ApproveRefund(ctx context.Context, actor string, requestID uuid.UUID, note string) (*domain.RefundRequest, error) {
+	const op = "OrderService.ApproveRefund"
+
+	request, err := s.approveRefund(ctx, actor, requestID, note)
+	if err != nil && (errors.Is(err, ErrRefundFailed) || errors.Is(err, domain.ErrInvalidRefund) || errors.Is(err, domain.ErrPaymentDisputed)) {
+		// Recorded after the approval transaction is rolled back, which releases the request lock
+		failure := &domain.RefundRequestEvent{
+			ID:        uuid.New(),
+			RequestID: requestID,
+			Action:    domain.RefundActionRefundFailed,
+			Actor:     actor,
+			Note:      err.Error(),
+			CreatedAt: time.Now(),
+		}
+		if auditErr := s.refundRepo.AppendEvent(ctx, failure); auditErr != nil {
+			s.logger.WithTrace(ctx).Error("failed to audit refund failure", "op", op, "request_id", requestID,
+				"error", auditErr, "refund_error", err)
+		}
+	}
+	return request, err
+}
+
+func (s *OrderService) approveRefund(ctx context.Context, actor string, requestID uuid.UUID, note string) (_ *domain.RefundRequest, err error) {
+	const op = "OrderService.approveRefund"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not begin transaction: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				s.logger.Error("error rolling back transaction", "rollback_error", rbErr, "original_error", err)
+			}
+		}
+	}()
+
+	request, err := s.lockRefundRequest(ctx, tx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err = request.Decide(domain.RefundRequestApproved, actor, now); err != nil {
+		return nil, err
+	}
+	order, _, err := s.lockOrder(ctx, tx, request.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The request ID keys the refund, so approving again after a failed commit does not refund twice
+	refund, err := s.refundTx(ctx, tx, order, request.ID, request.PaymentID, request.Amount)
+	if err != nil {
+		return nil, err
+	}
+	request.RefundID = &refund.ID
+
+	// Sandbox orders allocated no stock, so their returns restock nothing
+	var returns []domain.StockMovement
+	if !order.Sandbox {
+		returns = request.ReturnMovements(order, now)
+	}
+	for _, movement := range returns {
+		if err = s.stockRepo.RecordTx(ctx, tx, &movement); err != nil {
+			return nil, fmt.Errorf("%s: restock product %s: %w", op, movement.ProductID, err)
+		}
+	}
+	if err = s.refundRepo.UpdateTx(ctx, tx, request); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = s.auditRefundTx(ctx, tx, request, domain.RefundActionApproved, actor, note); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		s.logUnrecordedRefund(ctx, op, refund, err)
+		return nil, fmt.Errorf("%s: could not commit transaction: %w", op, err)
+	}
+	s.publishStockChanges(ctx, returns)
+	return request, nil
+}
+
+// RejectRefund rejects the pending refund request on behalf of actor. Nothing is refunded.
+// Returns ErrRefundRequestNotFound, domain.ErrRefundRequestDecided if the request is not pending
+// and domain.ErrSelfApproval if actor requested it.
+func (s *OrderService) RejectRefund(ctx context.Context, actor string, requestID uuid.UUID, note string) (_ *domain.RefundRequest, err error) {
+	const op = "OrderService.RejectRefund"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not begin transaction: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				s.logger.Error("error rolling back transaction", "rollback_error", rbErr, "original_error", err)
+			}
+		}
+	}()
+
+	request, err := s.lockRefundRequest(ctx, tx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if err = request.Decide(domain.RefundRequestRejected, actor, time.Now()); err != nil {
+		return nil, err
+	}
+	if err = s.refundRepo.UpdateTx(ctx, tx, request); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = s.auditRefundTx(ctx, tx, request, domain.RefundActionRejected, actor, note); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: could not commit transaction: %w", op, err)
+	}
+	return request, nil
+}
+
+// RefundRequest returns the refund request with its audit trail.
+func (s *OrderService) RefundRequest(ctx context.Context, requestID uuid.UUID) (*domain.RefundRequest, error) {
+	const op = "OrderService.RefundRequest"
+
+	request, err := s.refundRepo.FindByID(ctx, requestID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRefundRequestNotFound) {
+			return nil, ErrRefundRequestNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return request, nil
+}
+
+// RefundRequests returns the refund requests of the order with their audit trails, oldest first.
+func (s *OrderService) RefundRequests(ctx context.Context, orderID uuid.UUID) ([]domain.RefundRequest, error) {
+	const op = "OrderService.RefundRequests"
+
+	requests, err := s.refundRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return requests, nil
+}
+
+// PendingRefundRequests returns up to limit refund requests waiting for a decision, oldest first.
+func (s *OrderService) PendingRefundRequests(ctx context.Context, limit int) ([]domain.RefundRequest, error) {
+	const op = "OrderService.PendingRefundRequests"
+
+	requests, err := s.refundRepo.FindPending(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return requests, nil
+}
+
+// lockRefundRequest finds the refund request with a row lock, so concurrent decisions are serialized.
+func (s *OrderService) lockRefundRequest(ctx context.Context, tx pgx.Tx, requestID uuid.UUID) (*domain.RefundRequest, error) {
+	const op = "OrderService.lockRefundRequest"
+
+	request, err := s.refundRepo.FindByIDTx(ctx, tx, requestID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRefundRequestNotFound) {
+			return nil, ErrRefundRequestNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return request, nil
+}
+
+// auditRefundTx appends the action to the request's audit trail within the transaction.
+func (s *OrderService) auditRefundTx(ctx context.Context, tx pgx.Tx, request *domain.RefundRequest, action, actor, note string) error {
+	event := domain.RefundRequestEvent{
+		ID:        uuid.New(),
+		RequestID: request.ID,
+		Action:    action,
+		Actor:     actor,
+		Note:      note,
+		CreatedAt: time.Now(),
+	}
+	if err := s.refundRepo.AppendEventTx(ctx, tx, &event); err != nil {
+		return fmt.Errorf("audit %s: %w", action, err)
+	}
+	request.Events = append(request.Events, event)
+	return nil
+}
@@ -2,19 +2,20 @@ package service_test
 
 import (
 	"context"
-	"log"
-	"os"
+	"fmt"
 	"product-api/internal/domain"
+	"product-api/internal/event"
 	"product-api/internal/logger"
+	"product-api/internal/notification"
+	"product-api/internal/payment"
 	"product-api/internal/repository"
 	"product-api/internal/repository/postgres"
 	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"product-api/internal/testutil/testdb"
 	"testing"
 	"time"
 
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/suite"
@@ -26,83 +27,57 @@ type OrderServiceTestSuite struct {
 	orderRepo   repository.OrderRepository
 	productRepo repository.ProductRepository
 	userRepo    repository.UserRepository
+	slotRepo    repository.DeliverySlotRepository
 	service     *service.OrderService
 }
 
-func (s *OrderServiceTestSuite) SetupSuite() {
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbName := os.Getenv("DB_NAME") + "_test"
-	maintenanceDbUrl := "postgres://" + dbUser + ":" + dbPassword + "@localhost:5434/postgres?sslmode=disable"
-	testDbUrl := "postgres://" + dbUser + ":" + dbPassword + "@localhost:5434/" + dbName + "?sslmode=disable"
-
-	var err error
-	var maintenanceDb *pgxpool.Pool
-
-	for i := 0; i < 10; i++ {
-		maintenanceDb, err = pgxpool.New(context.Background(), maintenanceDbUrl)
-		if err == nil {
-			break
-		}
-		log.Printf("Failed to connect to maintenance db, retrying in 2 seconds...: %v", err)
-		time.Sleep(2 * time.Second)
-	}
-	s.Require().NoError(err, "Failed to connect to maintenance database after retries")
-
-	_, err = maintenanceDb.Exec(context.Background(), "DROP DATABASE IF EXISTS "+dbName)
-	s.Require().NoError(err)
-	_, err = maintenanceDb.Exec(context.Background(), "CREATE DATABASE "+dbName)
-	s.Require().NoError(err)
-	maintenanceDb.Close()
-
-	s.dbpool, err = pgxpool.New(context.Background(), testDbUrl)
-	s.Require().NoError(err)
-
-	m, err := migrate.New("file://../../migrations", testDbUrl)
-	s.Require().NoError(err)
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		s.Require().NoError(err)
-	}
+func (s *OrderServiceTestSuite) SetupTest() {
+	s.dbpool = testdb.New(s.T())
 
 	s.orderRepo = postgres.NewOrderRepository(s.dbpool)
 	s.productRepo = postgres.NewProductRepository(s.dbpool)
 	s.userRepo = postgres.NewUserRepository(s.dbpool)
+	s.slotRepo = postgres.NewDeliverySlotRepository(s.dbpool)
 
 	testLogger := logger.NewSlogAdapter("local")
-	s.service = service.NewOrderService(s.dbpool, s.orderRepo, s.productRepo, testLogger)
-}
-
-func (s *OrderServiceTestSuite) TearDownSuite() {
-	s.dbpool.Close()
-}
-
-func (s *OrderServiceTestSuite) TearDownTest() {
-	_, err := s.dbpool.Exec(context.Background(), "TRUNCATE TABLE users, products, orders, order_items RESTART IDENTITY CASCADE")
-	s.Require().NoError(err)
+	mock := payment.NewMemoryProvider("", testLogger)
+	notifier := notification.NewDispatcher(s.userRepo, postgres.NewPreferenceRepository(s.dbpool), notification.NewTemplates(postgres.NewNotificationTemplateRepository(s.dbpool), time.Minute, testLogger), testLogger,
+		notification.NewLogSender(domain.NotificationChannelEmail, testLogger))
+	s.service = service.NewOrderService(service.OrderServiceDeps{
+		DB:          s.dbpool,
+		OrderRepo:   s.orderRepo,
+		EventRepo:   postgres.NewOrderEventRepository(s.dbpool),
+		Numbers:     postgres.NewOrderNumberRepository(s.dbpool),
+		PaymentRepo: postgres.NewPaymentRepository(s.dbpool),
+		RefundRepo:  postgres.NewRefundRequestRepository(s.dbpool),
+		DisputeRepo: postgres.NewDisputeRepository(s.dbpool),
+		ProductRepo: s.productRepo,
+		StockRepo:   postgres.NewStockRepository(s.dbpool),
+		SlotRepo:    s.slotRepo,
+		UserRepo:    s.userRepo,
+		Notifier:    notifier,
+		Providers:   payment.NewRegistry(mock).WithSandbox(mock),
+		Formatter:   usdFormatter(),
+		TaxRegion:   "US-CA",
+		Region:      "eu-west-1",
+		Checks:      defaultCheckoutPipeline(),
+		OptionFees:  orderOptionFees(),
+		Events:      event.NewBus(testLogger),
+		Logger:      testLogger,
+	})
 }
 
 func (s *OrderServiceTestSuite) TestCreateOrder_Success() {
 	ctx := context.Background()
 
-	user := &domain.User{
-		ID:        uuid.New(),
-		Email:     "test-success@example.com",
-		Firstname: "Test", Lastname: "User", Age: 30, IsMarried: false, PasswordHash: "hash",
-	}
-	s.Require().NoError(s.userRepo.Create(ctx, user))
+	user := factory.CreateUser(s.T(), s.userRepo)
 
-	product := &domain.Product{
-		ID:          uuid.New(),
-		Description: "Test Product",
-		Quantity:    10,
-		Price:       99.99,
-	}
-	s.Require().NoError(s.productRepo.Create(ctx, product))
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(10))
 
 	items := []service.OrderItemInput{
 		{ProductID: product.ID, Quantity: 3},
 	}
-	order, err := s.service.CreateOrder(ctx, user.ID, items)
+	order, err := s.service.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
 
 	s.Assert().NoError(err)
 	s.Assert().NotNil(order)
@@ -110,33 +85,33 @@ func (s *OrderServiceTestSuite) TestCreateOrder_Success() {
 	s.Assert().Equal(product.Price, order.Items[0].PriceAtPurchase)
 	s.Assert().Equal(user.ID, order.UserID)
 
+	stored, err := s.orderRepo.FindByID(ctx, order.ID)
+	s.Require().NoError(err)
+	s.Assert().Equal(domain.OrderSettings{Currency: "USD", Locale: "en-US", TaxRegion: "US-CA", Region: "eu-west-1"}, stored.OrderSettings)
+
 	updatedProduct, err := s.productRepo.FindByID(ctx, product.ID)
 	s.Require().NoError(err)
 	s.Assert().Equal(7, updatedProduct.Quantity)
+
+	movements, err := postgres.NewStockRepository(s.dbpool).FindByProductID(ctx, product.ID, 1)
+	s.Require().NoError(err)
+	s.Require().Len(movements, 1)
+	s.Assert().Equal(-3, movements[0].Delta)
+	s.Assert().Equal(domain.StockReasonAllocation, movements[0].Reason)
+	s.Assert().Equal(&order.ID, movements[0].OrderID)
 }
 
 func (s *OrderServiceTestSuite) TestCreateOrder_InsufficientStock() {
 	ctx := context.Background()
 
-	user := &domain.User{
-		ID:        uuid.New(),
-		Email:     "test-fail@example.com",
-		Firstname: "Test", Lastname: "User", Age: 30, IsMarried: false, PasswordHash: "hash",
-	}
-	s.Require().NoError(s.userRepo.Create(ctx, user))
+	user := factory.CreateUser(s.T(), s.userRepo)
 
-	product := &domain.Product{
-		ID:          uuid.New(),
-		Description: "Test Product Fail",
-		Quantity:    5,
-		Price:       10.00,
-	}
-	s.Require().NoError(s.productRepo.Create(ctx, product))
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(5))
 
 	items := []service.OrderItemInput{
 		{ProductID: product.ID, Quantity: 10},
 	}
-	_, err := s.service.CreateOrder(ctx, user.ID, items)
+	_, err := s.service.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
 
 	s.Assert().Error(err)
 	s.Assert().ErrorIs(err, service.ErrInsufficientStock)
@@ -146,6 +121,531 @@ func (s *OrderServiceTestSuite) TestCreateOrder_InsufficientStock() {
 	s.Assert().Equal(5, updatedProduct.Quantity)
 }
 
+func (s *OrderServiceTestSuite) TestCreateOrder_NumbersAreGapless() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(5))
+	year := time.Now().Year()
+
+	first, err := s.service.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 1}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+	s.Require().NoError(err)
+	// Rolled back orders do not take a number
+	_, err = s.service.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 10}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+	s.Require().ErrorIs(err, service.ErrInsufficientStock)
+
+	s.Require().NoError(s.service.ConfigureNumbers(ctx, &domain.OrderNumberSettings{Prefix: "WEB", Padding: 4}))
+	second, err := s.service.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 1}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+	s.Require().NoError(err)
+
+	s.Assert().Equal(fmt.Sprintf("ORD-%d-000001", year), first.Number)
+	s.Assert().Equal(fmt.Sprintf("WEB-%d-0002", year), second.Number)
+	stored, err := s.orderRepo.FindByID(ctx, second.ID)
+	s.Require().NoError(err)
+	s.Assert().Equal(second.Number, stored.Number)
+}
+
+func (s *OrderServiceTestSuite) TestCreateOrder_PaymentProvider() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(5))
+	items := []service.OrderItemInput{{ProductID: product.ID, Quantity: 1}}
+
+	_, err := s.service.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{Provider: "stripe"})
+	s.ErrorIs(err, service.ErrUnknownPaymentProvider)
+
+	_, err = s.service.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{Provider: "mock", Token: payment.DeclinedSource})
+	s.ErrorIs(err, service.ErrPaymentFailed)
+
+	order, err := s.service.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{Provider: "mock"})
+	s.Require().NoError(err)
+	stored, err := s.service.StateAt(ctx, order.ID, time.Now())
+	s.Require().NoError(err)
+	s.Require().Len(stored.Payments, 1)
+	s.Equal("mock", stored.Payments[0].Provider)
+	s.Equal(order.PaymentID, stored.Payments[0].Reference)
+	s.NotEmpty(stored.Payments[0].Metadata["authorization_id"])
+
+	updated, err := s.productRepo.FindByID(ctx, product.ID)
+	s.Require().NoError(err)
+	s.Equal(4, updated.Quantity)
+}
+
+func (s *OrderServiceTestSuite) TestCreateOrder_PendingCapture() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(5))
+	items := []service.OrderItemInput{{ProductID: product.ID, Quantity: 2}}
+
+	order, err := s.service.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{Token: payment.PendingSource})
+	s.Require().NoError(err)
+	s.Equal(domain.OrderStatusCreated, order.Status)
+	s.NotEmpty(order.PendingPayment)
+
+	stored, err := s.orderRepo.FindByID(ctx, order.ID)
+	s.Require().NoError(err)
+	s.Equal(domain.OrderStatusCreated, stored.Status, "the order is not cancelled")
+	s.Equal(order.PendingPayment, stored.PendingPayment)
+
+	// The provider reports the capture failed, the order awaits another payment
+	err = s.service.ConfirmCapture(ctx, "mock", &payment.CaptureUpdate{ID: order.PendingPayment, OrderID: order.ID, Status: payment.CaptureFailed})
+	s.Require().NoError(err)
+	failed, err := s.service.StateAt(ctx, order.ID, time.Now())
+	s.Require().NoError(err)
+	s.Equal(domain.OrderStatusCreated, failed.Status)
+	s.Empty(failed.PendingPayment)
+}
+
+func (s *OrderServiceTestSuite) TestExpirePayments() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(5))
+	items := []service.OrderItemInput{{ProductID: product.ID, Quantity: 2}}
+
+	pending, err := s.service.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{Token: payment.PendingSource})
+	s.Require().NoError(err)
+	paid, err := s.service.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+	s.Require().NoError(err)
+
+	expired, err := s.service.ExpirePayments(ctx, time.Now().Add(-time.Hour))
+	s.Require().NoError(err)
+	s.Zero(expired, "payment pending for less than the timeout")
+
+	expired, err = s.service.ExpirePayments(ctx, time.Now().Add(time.Minute))
+	s.Require().NoError(err)
+	s.Equal(1, expired)
+
+	cancelled, err := s.orderRepo.FindByID(ctx, pending.ID)
+	s.Require().NoError(err)
+	s.Equal(domain.OrderStatusCancelled, cancelled.Status)
+	stillPaid, err := s.orderRepo.FindByID(ctx, paid.ID)
+	s.Require().NoError(err)
+	s.Equal(domain.OrderStatusPaid, stillPaid.Status)
+	updated, err := s.productRepo.FindByID(ctx, product.ID)
+	s.Require().NoError(err)
+	s.Equal(3, updated.Quantity, "stock of the expired order is released")
+
+	expired, err = s.service.ExpirePayments(ctx, time.Now().Add(time.Minute))
+	s.Require().NoError(err)
+	s.Zero(expired)
+}
+
+func (s *OrderServiceTestSuite) TestCreateOrder_AgeRestricted() {
+	ctx := context.Background()
+
+	user := factory.CreateUser(s.T(), s.userRepo, factory.WithAge(19))
+
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(5), factory.WithAgeRestriction(21))
+
+	items := []service.OrderItemInput{
+		{ProductID: product.ID, Quantity: 1},
+	}
+	_, err := s.service.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+
+	s.Assert().ErrorIs(err, service.ErrAgeRestricted)
+
+	updatedProduct, err := s.productRepo.FindByID(ctx, product.ID)
+	s.Require().NoError(err)
+	s.Assert().Equal(5, updatedProduct.Quantity)
+}
+
+func (s *OrderServiceTestSuite) TestCreateOrder_TotalRoundedToCents() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	cheap := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(0.1))
+	other := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(0.2))
+
+	order, err := s.service.CreateOrder(ctx, user.ID, []service.OrderItemInput{
+		{ProductID: cheap.ID, Quantity: 1},
+		{ProductID: other.ID, Quantity: 1},
+	}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+	s.Require().NoError(err)
+	s.Equal(0.3, order.TotalAmount)
+}
+
+func (s *OrderServiceTestSuite) TestOrderTotal_MismatchRejected() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(10))
+	order := factory.CreateOrder(s.T(), s.dbpool, s.orderRepo, user.ID, factory.WithItem(product, 2))
+
+	_, err := s.dbpool.Exec(ctx, "UPDATE orders SET total_amount = 25 WHERE id = $1", order.ID)
+	s.Error(err)
+}
+
+func (s *OrderServiceTestSuite) TestCheckTotals() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(10))
+	order := factory.CreateOrder(s.T(), s.dbpool, s.orderRepo, user.ID, factory.WithItem(product, 2))
+	factory.CreateOrder(s.T(), s.dbpool, s.orderRepo, user.ID, factory.WithItem(product, 1))
+
+	// Simulate drift from before the integrity check existed
+	_, err := s.dbpool.Exec(ctx, `
+		ALTER TABLE orders DISABLE TRIGGER orders_total_matches_items;
+		UPDATE orders SET total_amount = 19.99 WHERE id = '`+order.ID.String()+`';
+		ALTER TABLE orders ENABLE TRIGGER orders_total_matches_items;`)
+	s.Require().NoError(err)
+
+	report, err := s.service.CheckTotals(ctx, 100, false)
+	s.Require().NoError(err)
+	s.Require().Len(report.Mismatches, 1)
+	s.Equal(order.ID, report.Mismatches[0].OrderID)
+	s.Equal(19.99, report.Mismatches[0].StoredTotal)
+	s.Equal(20.0, report.Mismatches[0].ComputedTotal)
+	s.Zero(report.Repaired)
+
+	report, err = s.service.CheckTotals(ctx, 100, true)
+	s.Require().NoError(err)
+	s.Equal(1, report.Repaired)
+
+	report, err = s.service.CheckTotals(ctx, 100, false)
+	s.Require().NoError(err)
+	s.Empty(report.Mismatches)
+}
+
+func (s *OrderServiceTestSuite) TestChangeStatus() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(5))
+
+	order, err := s.service.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 2}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+	s.Require().NoError(err)
+	s.Equal(domain.OrderStatusPaid, order.Status)
+	s.NotEmpty(order.PaymentID)
+	paidAt := time.Now()
+
+	shipped, err := s.service.ChangeStatus(ctx, order.ID, domain.OrderEventShipped)
+	s.Require().NoError(err)
+	s.Equal(domain.OrderStatusShipped, shipped.Status)
+	s.Equal(10.0, shipped.TotalAmount)
+
+	_, err = s.service.ChangeStatus(ctx, order.ID, domain.OrderEventCancelled)
+	s.ErrorIs(err, domain.ErrInvalidOrderTransition)
+	_, err = s.service.ChangeStatus(ctx, uuid.New(), domain.OrderEventShipped)
+	s.ErrorIs(err, service.ErrOrderNotFound)
+
+	// The projection follows the event log
+	stored, err := s.orderRepo.FindByID(ctx, order.ID)
+	s.Require().NoError(err)
+	s.Equal(domain.OrderStatusShipped, stored.Status)
+	s.Equal(order.PaymentID, stored.PaymentID)
+
+	events, err := s.service.Events(ctx, order.ID)
+	s.Require().NoError(err)
+	s.Len(events, 4)
+
+	// Temporal query before shipping
+	before, err := s.service.StateAt(ctx, order.ID, paidAt)
+	s.Require().NoError(err)
+	s.Equal(domain.OrderStatusPaid, before.Status)
+	s.Len(before.Items, 1)
+
+	_, err = s.service.StateAt(ctx, order.ID, order.CreatedAt.Add(-time.Second))
+	s.ErrorIs(err, service.ErrOrderNotFound)
+}
+
+func (s *OrderServiceTestSuite) TestCancelReleasesStock() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(10))
+
+	order, err := s.service.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 3}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+	s.Require().NoError(err)
+
+	cancelled, err := s.service.ChangeStatus(ctx, order.ID, domain.OrderEventCancelled)
+	s.Require().NoError(err)
+	s.Equal(domain.OrderStatusCancelled, cancelled.Status)
+
+	restored, err := s.productRepo.FindByID(ctx, product.ID)
+	s.Require().NoError(err)
+	s.Equal(10, restored.Quantity)
+}
+
+func (s *OrderServiceTestSuite) TestDeliverySlotCapacity() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(10))
+	slots := service.NewDeliverySlotService(s.slotRepo)
+	startsAt := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	slot, err := slots.Create(ctx, "eu-west-1", startsAt, startsAt.Add(3*time.Hour), 1)
+	s.Require().NoError(err)
+	items := []service.OrderItemInput{{ProductID: product.ID, Quantity: 1}}
+
+	order, err := s.service.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, slot.ID, false, service.PaymentSource{})
+	s.Require().NoError(err)
+	stored, err := s.orderRepo.FindByID(ctx, order.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(stored.Delivery)
+	s.Equal(slot.ID, stored.Delivery.SlotID)
+
+	_, err = s.service.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, slot.ID, false, service.PaymentSource{})
+	s.ErrorIs(err, service.ErrDeliverySlotUnavailable)
+	available, err := slots.Available(ctx, "eu-west-1", time.Now(), startsAt.Add(time.Hour))
+	s.Require().NoError(err)
+	s.Empty(available)
+	_, err = slots.UpdateCapacity(ctx, slot.ID, 0)
+	s.ErrorIs(err, service.ErrDeliverySlotCapacity, "capacity cannot drop below the reserved order")
+
+	_, err = s.service.ChangeStatus(ctx, order.ID, domain.OrderEventCancelled)
+	s.Require().NoError(err)
+	available, err = slots.Available(ctx, "eu-west-1", time.Now(), startsAt.Add(time.Hour))
+	s.Require().NoError(err)
+	s.Require().Len(available, 1)
+	s.Equal(0, available[0].Reserved, "cancelling releases the slot")
+}
+
+func (s *OrderServiceTestSuite) TestCancelOrderOfSlotWithoutReservations() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(10))
+	startsAt := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	slot, err := service.NewDeliverySlotService(s.slotRepo).Create(ctx, "eu-west-1", startsAt, startsAt.Add(3*time.Hour), 1)
+	s.Require().NoError(err)
+	order, err := s.service.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 1}}, service.OrderOptionsInput{}, slot.ID, false, service.PaymentSource{})
+	s.Require().NoError(err)
+	// Reservations reset by hand, e.g. while fixing the capacity of the slot
+	_, err = s.dbpool.Exec(ctx, `UPDATE delivery_slots SET reserved = 0 WHERE id = $1`, slot.ID)
+	s.Require().NoError(err)
+
+	_, err = s.service.ChangeStatus(ctx, order.ID, domain.OrderEventCancelled)
+	s.Require().NoError(err, "releasing a slot without reservations is a no-op")
+	slots, err := s.slotRepo.FindByRegion(ctx, "eu-west-1", time.Now(), startsAt.Add(time.Hour))
+	s.Require().NoError(err)
+	s.Require().Len(slots, 1)
+	s.Zero(slots[0].Reserved)
+}
+
+func (s *OrderServiceTestSuite) TestDeliverySlotOfOtherRegion() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(10))
+	startsAt := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	slot, err := service.NewDeliverySlotService(s.slotRepo).Create(ctx, "us-east-1", startsAt, startsAt.Add(3*time.Hour), 1)
+	s.Require().NoError(err)
+
+	_, err = s.service.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 1}}, service.OrderOptionsInput{}, slot.ID, false, service.PaymentSource{})
+	s.ErrorIs(err, service.ErrDeliverySlotUnavailable, "orders are placed in eu-west-1")
+	updated, err := s.productRepo.FindByID(ctx, product.ID)
+	s.Require().NoError(err)
+	s.Equal(10, updated.Quantity, "nothing is reserved")
+}
+
+func (s *OrderServiceTestSuite) TestSandboxOrder() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	partner := factory.CreateUser(s.T(), s.userRepo)
+	s.Require().NoError(s.userRepo.UpdateSandbox(ctx, partner.ID, true))
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(10))
+	slots := service.NewDeliverySlotService(s.slotRepo)
+	startsAt := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	slot, err := slots.Create(ctx, "eu-west-1", startsAt, startsAt.Add(3*time.Hour), 2)
+	s.Require().NoError(err)
+	items := []service.OrderItemInput{{ProductID: product.ID, Quantity: 3}}
+
+	regular, err := s.service.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+	s.Require().NoError(err)
+	sandbox, err := s.service.CreateOrder(ctx, partner.ID, items, service.OrderOptionsInput{}, slot.ID, false, service.PaymentSource{})
+	s.Require().NoError(err)
+	s.True(sandbox.Sandbox)
+	s.Equal(domain.OrderStatusPaid, sandbox.Status)
+
+	stored, err := s.orderRepo.FindByID(ctx, sandbox.ID)
+	s.Require().NoError(err)
+	s.True(stored.Sandbox)
+	listed, _, err := s.orderRepo.FindByUserID(ctx, partner.ID, 0, 10)
+	s.Require().NoError(err)
+	s.Require().Len(listed, 1)
+	s.True(listed[0].Sandbox)
+
+	// Only the regular order allocated stock
+	updated, err := s.productRepo.FindByID(ctx, product.ID)
+	s.Require().NoError(err)
+	s.Equal(7, updated.Quantity)
+	movements, err := postgres.NewStockRepository(s.dbpool).FindByProductID(ctx, product.ID, 10)
+	s.Require().NoError(err)
+	s.Require().Len(movements, 1)
+	s.Equal(&regular.ID, movements[0].OrderID)
+
+	available, err := s.slotRepo.FindByRegion(ctx, "eu-west-1", time.Now(), startsAt.Add(time.Hour))
+	s.Require().NoError(err)
+	s.Require().Len(available, 1)
+	s.Zero(available[0].Reserved, "sandbox orders take no capacity")
+
+	unjournaled, err := postgres.NewAccountingRepository(s.dbpool).FindUnjournaledPayments(ctx, 100)
+	s.Require().NoError(err)
+	s.Require().Len(unjournaled, 1, "sandbox payments are not journaled")
+	s.Equal(regular.ID, unjournaled[0].OrderID)
+}
+
+func (s *OrderServiceTestSuite) TestFindDuplicate() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	first := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(10))
+	second := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(4))
+	order, err := s.service.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: first.ID, Quantity: 2}, {ProductID: second.ID, Quantity: 1}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+	s.Require().NoError(err)
+
+	findDuplicate := func(items []domain.OrderItem, total float64, since time.Time) (*domain.Order, error) {
+		tx, err := s.dbpool.Begin(ctx)
+		s.Require().NoError(err)
+		defer tx.Rollback(ctx)
+		return s.orderRepo.FindDuplicateTx(ctx, tx, &domain.Order{UserID: user.ID, Items: items, TotalAmount: total}, since)
+	}
+	since := time.Now().Add(-10 * time.Minute)
+
+	// Lines match in any order
+	duplicate, err := findDuplicate([]domain.OrderItem{{ProductID: second.ID, Quantity: 1}, {ProductID: first.ID, Quantity: 2}}, 24, since)
+	s.Require().NoError(err)
+	s.Equal(order.ID, duplicate.ID)
+	s.Equal(order.Number, duplicate.Number)
+
+	_, err = findDuplicate([]domain.OrderItem{{ProductID: first.ID, Quantity: 2}, {ProductID: second.ID, Quantity: 2}}, 28, since)
+	s.ErrorIs(err, repository.ErrOrderNotFound, "different quantities")
+	_, err = findDuplicate([]domain.OrderItem{{ProductID: first.ID, Quantity: 2}, {ProductID: second.ID, Quantity: 1}}, 24, time.Now())
+	s.ErrorIs(err, repository.ErrOrderNotFound, "placed before the window")
+
+	_, err = s.service.ChangeStatus(ctx, order.ID, domain.OrderEventCancelled)
+	s.Require().NoError(err)
+	_, err = findDuplicate([]domain.OrderItem{{ProductID: first.ID, Quantity: 2}, {ProductID: second.ID, Quantity: 1}}, 24, since)
+	s.ErrorIs(err, repository.ErrOrderNotFound, "cancelled orders are not duplicated")
+}
+
+func (s *OrderServiceTestSuite) TestRefundsAreDerivedFromLedger() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(10))
+
+	order, err := s.service.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 3}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+	s.Require().NoError(err)
+	s.Require().Len(order.Payments, 1)
+	charge := order.Payments[0]
+	s.Equal(30.0, order.PaidAmount())
+
+	refunded, err := s.service.Refund(ctx, order.ID, charge.ID, 12.5, "")
+	s.Require().NoError(err)
+	s.Equal(12.5, refunded.RefundedAmount())
+	_, err = s.service.Refund(ctx, order.ID, charge.ID, 20, "")
+	s.ErrorIs(err, domain.ErrInvalidRefund)
+
+	// Cancelling voids the charge, recorded as a refund of the rest
+	cancelled, err := s.service.ChangeStatus(ctx, order.ID, domain.OrderEventCancelled)
+	s.Require().NoError(err)
+	s.Equal(30.0, cancelled.PaidAmount())
+	s.Equal(30.0, cancelled.RefundedAmount())
+	s.Zero(cancelled.RefundableAmount(charge.ID))
+}
+
+func (s *OrderServiceTestSuite) TestRefundRequestWorkflow() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(10), factory.WithQuantity(5))
+
+	order, err := s.service.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 3}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+	s.Require().NoError(err)
+	charge := order.Payments[0]
+	_, err = s.service.ChangeStatus(ctx, order.ID, domain.OrderEventShipped)
+	s.Require().NoError(err)
+
+	request, err := s.service.RequestRefund(ctx, "client:support", order.ID, service.RefundRequestInput{
+		PaymentID: charge.ID,
+		Amount:    10,
+		Items:     []domain.RefundItem{{ItemID: order.Items[0].ID, Quantity: 1}},
+		Reason:    "Damaged in transit",
+	})
+	s.Require().NoError(err)
+	s.Equal(domain.RefundRequestRequested, request.Status)
+
+	// The pending request holds its amount, nothing is refunded yet
+	_, err = s.service.RequestRefund(ctx, "client:support", order.ID, service.RefundRequestInput{PaymentID: charge.ID, Amount: 20.01, Reason: "Late"})
+	s.ErrorIs(err, domain.ErrInvalidRefundRequest)
+	_, err = s.service.ApproveRefund(ctx, "client:support", request.ID, "")
+	s.ErrorIs(err, domain.ErrSelfApproval)
+
+	approved, err := s.service.ApproveRefund(ctx, "client:finance", request.ID, "Return received")
+	s.Require().NoError(err)
+	s.Equal(domain.RefundRequestApproved, approved.Status)
+	_, err = s.service.RejectRefund(ctx, "client:finance", request.ID, "Too late")
+	s.ErrorIs(err, domain.ErrRefundRequestDecided)
+
+	stored, err := s.service.RefundRequest(ctx, request.ID)
+	s.Require().NoError(err)
+	s.Equal(approved.RefundID, stored.RefundID)
+	s.Require().Len(stored.Events, 2)
+	s.Equal(domain.RefundActionRequested, stored.Events[0].Action)
+	s.Equal(domain.RefundActionApproved, stored.Events[1].Action)
+	s.Equal("client:finance", stored.Events[1].Actor)
+
+	refunded, err := s.service.StateAt(ctx, order.ID, time.Now())
+	s.Require().NoError(err)
+	s.Equal(10.0, refunded.RefundedAmount())
+	restocked, err := s.productRepo.FindByID(ctx, product.ID)
+	s.Require().NoError(err)
+	s.Equal(3, restocked.Quantity, "one of three ordered items is back in stock")
+
+	pending, err := s.service.PendingRefundRequests(ctx, 1000)
+	s.Require().NoError(err)
+	for _, p := range pending {
+		s.NotEqual(request.ID, p.ID)
+	}
+}
+
+func (s *OrderServiceTestSuite) TestRefundOfCancelledOrderRestocksOnce() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithPrice(10), factory.WithQuantity(5))
+
+	order, err := s.service.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 3}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+	s.Require().NoError(err)
+	charge := order.Payments[0]
+
+	// Items of a paid order are not returned, cancelling it releases them
+	_, err = s.service.RequestRefund(ctx, "client:support", order.ID, service.RefundRequestInput{
+		PaymentID: charge.ID,
+		Amount:    10,
+		Items:     []domain.RefundItem{{ItemID: order.Items[0].ID, Quantity: 1}},
+		Reason:    "Changed mind",
+	})
+	s.ErrorIs(err, domain.ErrInvalidRefundRequest)
+	request, err := s.service.RequestRefund(ctx, "client:support", order.ID, service.RefundRequestInput{PaymentID: charge.ID, Amount: 10, Reason: "Goodwill"})
+	s.Require().NoError(err)
+	_, err = s.service.ApproveRefund(ctx, "client:finance", request.ID, "")
+	s.Require().NoError(err)
+
+	_, err = s.service.ChangeStatus(ctx, order.ID, domain.OrderEventCancelled)
+	s.Require().NoError(err)
+
+	restocked, err := s.productRepo.FindByID(ctx, product.ID)
+	s.Require().NoError(err)
+	s.Equal(5, restocked.Quantity, "cancelled items are restocked once")
+	movements, err := postgres.NewStockRepository(s.dbpool).FindByProductID(ctx, product.ID, 10)
+	s.Require().NoError(err)
+	for _, m := range movements {
+		s.NotEqual(domain.StockReasonReturn, m.Reason)
+	}
+}
+
+func (s *OrderServiceTestSuite) TestOrderedProductCannotBeDeleted() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	ordered := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(10))
+	unordered := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(10))
+	products := service.NewProductService(s.dbpool, s.productRepo, postgres.NewProductRevisionRepository(s.dbpool), postgres.NewAttributeRepository(s.dbpool))
+
+	_, err := s.service.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: ordered.ID, Quantity: 1}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+	s.Require().NoError(err)
+
+	s.ErrorIs(products.Delete(ctx, ordered.ID), service.ErrProductInUse)
+	_, err = s.productRepo.FindByID(ctx, ordered.ID)
+	s.NoError(err)
+
+	// Stock movements of the unordered product are deleted with it
+	s.Require().NoError(products.Delete(ctx, unordered.ID))
+	_, err = s.productRepo.FindByID(ctx, unordered.ID)
+	s.ErrorIs(err, repository.ErrProductNotFound)
+	s.ErrorIs(products.Delete(ctx, unordered.ID), service.ErrProductNotFound)
+}
+
 func TestOrderServiceTestSuite(t *testing.T) {
+	t.Parallel()
 	suite.Run(t, new(OrderServiceTestSuite))
 }
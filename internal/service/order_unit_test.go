@@ -0,0 +1,1062 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"product-api/internal/domain"
+	"product-api/internal/event"
+	"product-api/internal/money"
+	"product-api/internal/notification"
+	notificationmocks "product-api/internal/notification/mocks"
+	"product-api/internal/payment"
+	paymentmocks "product-api/internal/payment/mocks"
+	"product-api/internal/repository"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// orderServiceMocks contains mocked dependencies of OrderService.
+type orderServiceMocks struct {
+	db          *mocks.MockTxBeginner
+	tx          *mocks.MockTx
+	orderRepo   *mocks.MockOrderRepository
+	eventRepo   *mocks.MockOrderEventRepository
+	numbers     *mocks.MockOrderNumberRepository
+	ledger      *mocks.MockPaymentRepository
+	refunds     *mocks.MockRefundRequestRepository
+	disputes    *mocks.MockDisputeRepository
+	productRepo *mocks.MockProductRepository
+	stockRepo   *mocks.MockStockRepository
+	slotRepo    *mocks.MockDeliverySlotRepository
+	userRepo    *mocks.MockUserRepository
+	notifier    *notificationmocks.MockNotifier
+	provider    *paymentmocks.MockProvider
+	sandbox     *paymentmocks.MockProvider // Pays orders of sandbox accounts
+	events      *event.Bus
+	buyers      map[uuid.UUID]*domain.User // Buyers by ID, others are adults without a preferred locale
+}
+
+func newOrderServiceWithMocks(t *testing.T) (*service.OrderService, *orderServiceMocks) {
+	return newOrderServiceWithDuplicateWindow(t, 0)
+}
+
+// newOrderServiceWithDuplicateWindow returns an order service with mocks checking checkouts for duplicates
+// of orders placed within window.
+func newOrderServiceWithDuplicateWindow(t *testing.T, window time.Duration) (*service.OrderService, *orderServiceMocks) {
+	return newOrderServiceWithRegistry(t, window, nil)
+}
+
+// newOrderServiceWithRegistry returns an order service with mocks paying through the providers of registry,
+// or through the provider mocks if it is nil.
+func newOrderServiceWithRegistry(t *testing.T, window time.Duration, registry *payment.Registry) (*service.OrderService, *orderServiceMocks) {
+	m := &orderServiceMocks{
+		db:          mocks.NewMockTxBeginner(t),
+		tx:          mocks.NewMockTx(t),
+		orderRepo:   mocks.NewMockOrderRepository(t),
+		eventRepo:   mocks.NewMockOrderEventRepository(t),
+		numbers:     mocks.NewMockOrderNumberRepository(t),
+		ledger:      mocks.NewMockPaymentRepository(t),
+		refunds:     mocks.NewMockRefundRequestRepository(t),
+		disputes:    mocks.NewMockDisputeRepository(t),
+		productRepo: mocks.NewMockProductRepository(t),
+		stockRepo:   mocks.NewMockStockRepository(t),
+		slotRepo:    mocks.NewMockDeliverySlotRepository(t),
+		userRepo:    mocks.NewMockUserRepository(t),
+		notifier:    notificationmocks.NewMockNotifier(t),
+		provider:    paymentmocks.NewMockProvider(t),
+		sandbox:     paymentmocks.NewMockProvider(t),
+		events:      event.NewBus(discardLogger{}),
+		buyers:      make(map[uuid.UUID]*domain.User),
+	}
+	m.db.EXPECT().Begin(mock.Anything).Return(m.tx, nil).Maybe() // Reads do not begin transactions
+	m.numbers.EXPECT().NextTx(mock.Anything, m.tx, time.Now().Year()).Return(testOrderNumber, nil).Maybe()
+	m.provider.EXPECT().Name().Return("mock").Maybe()
+	m.sandbox.EXPECT().Name().Return("sandbox").Maybe()
+	m.disputes.EXPECT().FindByOrderIDTx(mock.Anything, m.tx, mock.Anything).Return(nil, nil).Maybe()
+	m.disputes.EXPECT().FindByOrderID(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	m.userRepo.EXPECT().FindByID(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, id uuid.UUID) (*domain.User, error) {
+		if buyer, ok := m.buyers[id]; ok {
+			return buyer, nil
+		}
+		return &domain.User{ID: id, Birthdate: time.Now().AddDate(-30, 0, 0)}, nil
+	}).Maybe()
+	if registry == nil {
+		registry = payment.NewRegistry(m.provider).WithSandbox(m.sandbox)
+	}
+	svc := service.NewOrderService(service.OrderServiceDeps{
+		DB:              m.db,
+		OrderRepo:       m.orderRepo,
+		EventRepo:       m.eventRepo,
+		Numbers:         m.numbers,
+		PaymentRepo:     m.ledger,
+		RefundRepo:      m.refunds,
+		DisputeRepo:     m.disputes,
+		ProductRepo:     m.productRepo,
+		StockRepo:       m.stockRepo,
+		SlotRepo:        m.slotRepo,
+		UserRepo:        m.userRepo,
+		Notifier:        m.notifier,
+		Providers:       registry,
+		Formatter:       usdFormatter(),
+		TaxRegion:       "US-CA",
+		Region:          "eu-west-1",
+		Checks:          defaultCheckoutPipeline(),
+		OptionFees:      orderOptionFees(),
+		DuplicateWindow: window,
+		Events:          m.events,
+		Logger:          discardLogger{},
+	})
+	return svc, m
+}
+
+// testOrderNumber is the number assigned to orders created with mocks.
+const testOrderNumber = "ORD-2024-000123"
+
+// expectEventLog makes the event repository mock keep appended events in memory and returns them.
+// Appending an event of failType fails with errAppend.
+func (m *orderServiceMocks) expectEventLog(failType string) *[]domain.OrderEvent {
+	var log []domain.OrderEvent
+	m.eventRepo.EXPECT().AppendTx(mock.Anything, m.tx, mock.Anything).RunAndReturn(
+		func(_ context.Context, _ pgx.Tx, events []domain.OrderEvent) error {
+			if events[0].Type == failType {
+				return errAppend
+			}
+			log = append(log, events...)
+			return nil
+		})
+	m.eventRepo.EXPECT().FindByOrderID(mock.Anything, mock.Anything, time.Time{}).RunAndReturn(
+		func(context.Context, uuid.UUID, time.Time) ([]domain.OrderEvent, error) {
+			return slices.Clone(log), nil
+		}).Maybe()
+	return &log
+}
+
+var errAppend = errors.New("append failed")
+
+// expectPayment makes the provider mock approve the authorization and capture of the checkout.
+func (m *orderServiceMocks) expectPayment() {
+	m.provider.EXPECT().Authorize(mock.Anything, mock.Anything).Return(&payment.Authorization{ID: "auth_1", Method: domain.PaymentMethodCard}, nil)
+	m.provider.EXPECT().Capture(mock.Anything, mock.MatchedBy(func(req payment.CaptureRequest) bool {
+		return req.AuthorizationID == "auth_1"
+	})).Return(&payment.Capture{ID: "cap_1"}, nil)
+}
+
+// expectLedger makes the payment repository mock keep recorded payments in memory.
+func (m *orderServiceMocks) expectLedger() {
+	var ledger []domain.Payment
+	m.ledger.EXPECT().FindByOrderIDTx(mock.Anything, m.tx, mock.Anything).RunAndReturn(
+		func(context.Context, pgx.Tx, uuid.UUID) ([]domain.Payment, error) {
+			return slices.Clone(ledger), nil
+		})
+	m.ledger.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).RunAndReturn(
+		func(_ context.Context, _ pgx.Tx, p *domain.Payment) error {
+			ledger = append(ledger, *p)
+			return nil
+		})
+}
+
+// stockMovement matches a stock movement of the product with the given reason and delta.
+func stockMovement(productID uuid.UUID, reason string, delta int) any {
+	return mock.MatchedBy(func(sm *domain.StockMovement) bool {
+		return sm.ProductID == productID && sm.Reason == reason && sm.Delta == delta && sm.OrderID != nil
+	})
+}
+
+// orderWithStatus matches an order projection with the given status.
+func orderWithStatus(status string) any {
+	return mock.MatchedBy(func(o *domain.Order) bool { return o.Status == status })
+}
+
+func TestCreateOrder_Unit_Success(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	user := factory.NewUser()
+	product := factory.NewProduct(factory.WithQuantity(10), factory.WithPrice(2.5))
+
+	events := m.expectEventLog("")
+	m.expectLedger()
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonAllocation, -4)).Return(nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.provider.EXPECT().Authorize(mock.Anything, mock.MatchedBy(func(req payment.AuthorizeRequest) bool {
+		return req.Amount.Minor == 1000 && req.Amount.Currency.Code == "USD" && req.UserID == user.ID
+	})).Return(&payment.Authorization{ID: "auth_1", Method: domain.PaymentMethodCard}, nil)
+	m.provider.EXPECT().Capture(mock.Anything, mock.MatchedBy(func(req payment.CaptureRequest) bool {
+		return req.AuthorizationID == "auth_1" && req.Amount.Minor == 1000
+	})).Return(&payment.Capture{ID: "cap_1"}, nil)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, orderWithStatus(domain.OrderStatusPaid)).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Times(2)
+	m.notifier.EXPECT().Notify(mock.Anything, user.ID, mock.MatchedBy(func(msg notification.Message) bool {
+		return msg.Category == domain.NotificationCategoryOrderUpdates && msg.Template == notification.TemplateOrderConfirmation &&
+			msg.Data["OrderNumber"] == testOrderNumber && msg.Data["Total"] == "10,00 $" && msg.Locale == "de-AT"
+	})).Return(nil)
+	user.Locale = "de-AT"
+	m.buyers[user.ID] = user
+
+	order, err := svc.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 4}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+
+	require.NoError(t, err)
+	assert.Equal(t, testOrderNumber, order.Number)
+	assert.Equal(t, 10.0, order.TotalAmount)
+	assert.Len(t, order.Items, 1)
+	assert.Equal(t, domain.OrderStatusPaid, order.Status)
+	assert.Equal(t, "cap_1", order.PaymentID)
+	require.Len(t, order.Payments, 1)
+	assert.Equal(t, "mock", order.Payments[0].Provider)
+	assert.Equal(t, "auth_1", order.Payments[0].Metadata["authorization_id"])
+	assert.Equal(t, 10.0, order.PaidAmount())
+	assert.Equal(t, domain.OrderSettings{Currency: "USD", Locale: "de-AT", TaxRegion: "US-CA", Region: "eu-west-1"}, order.OrderSettings,
+		"amounts stay in the shop currency, written in the buyer's locale")
+	for _, e := range *events {
+		assert.Equal(t, "eu-west-1", e.Region, "event %d", e.Sequence)
+	}
+}
+
+func TestCreateOrder_Unit_PaymentDeclinedReleasesStock(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	product := factory.NewProduct(factory.WithQuantity(10))
+
+	m.expectEventLog("")
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonAllocation, -4)).Return(nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.provider.EXPECT().Authorize(mock.Anything, mock.Anything).Return(nil, payment.ErrDeclined)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonRelease, 4)).Return(nil)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, orderWithStatus(domain.OrderStatusCancelled)).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Times(2)
+
+	_, err := svc.CreateOrder(ctx, factory.NewUser().ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 4}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+
+	assert.ErrorIs(t, err, service.ErrPaymentFailed)
+	assert.ErrorIs(t, err, payment.ErrDeclined)
+	// No void or notification is expected by the mocks
+}
+
+func TestCreateOrder_Unit_PublishesEvents(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	product := factory.NewProduct(factory.WithQuantity(10))
+
+	var published []event.Event
+	record := func(_ context.Context, e event.Event) error {
+		published = append(published, e)
+		return nil
+	}
+	m.events.Subscribe("order.created", "test", record)
+	m.events.Subscribe("product.stock_changed", "test", record)
+
+	m.expectEventLog("")
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.provider.EXPECT().Authorize(mock.Anything, mock.Anything).Return(nil, payment.ErrDeclined)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Times(2)
+
+	_, err := svc.CreateOrder(ctx, factory.NewUser().ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 4}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+	require.ErrorIs(t, err, service.ErrPaymentFailed)
+
+	// The order is created and its stock allocated, then released once the payment is declined
+	require.Len(t, published, 3)
+	created, ok := published[0].(event.OrderCreated)
+	require.True(t, ok)
+	assert.Equal(t, testOrderNumber, created.Order.Number)
+	for i, want := range []struct {
+		reason string
+		delta  int
+	}{{domain.StockReasonAllocation, -4}, {domain.StockReasonRelease, 4}} {
+		changed, ok := published[i+1].(event.ProductStockChanged)
+		require.True(t, ok)
+		assert.Equal(t, product.ID, changed.ProductID)
+		assert.Equal(t, want.reason, changed.Reason)
+		assert.Equal(t, want.delta, changed.Delta)
+		assert.Equal(t, created.Order.ID, *changed.OrderID)
+	}
+}
+
+func TestCreateOrder_Unit_ConfirmFailureRefundsCapture(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	product := factory.NewProduct(factory.WithQuantity(10))
+
+	m.expectEventLog(domain.OrderEventPaid)
+	m.expectLedger()
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonAllocation, -1)).Return(nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.expectPayment()
+	m.tx.EXPECT().Rollback(mock.Anything).Return(nil).Once()
+	m.provider.EXPECT().Refund(mock.Anything, mock.MatchedBy(func(req payment.RefundRequest) bool {
+		return req.CaptureID == "cap_1"
+	})).Return(&payment.Refund{ID: "ref_1"}, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonRelease, 1)).Return(nil)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, orderWithStatus(domain.OrderStatusCancelled)).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Times(2)
+
+	_, err := svc.CreateOrder(ctx, factory.NewUser().ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 1}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+
+	assert.ErrorIs(t, err, errAppend)
+}
+
+func TestCreateOrder_Unit_CaptureFailureVoidsAuthorization(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	product := factory.NewProduct(factory.WithQuantity(10))
+
+	m.expectEventLog("")
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonAllocation, -2)).Return(nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.provider.EXPECT().Authorize(mock.Anything, mock.Anything).Return(&payment.Authorization{ID: "auth_1", Method: domain.PaymentMethodCard}, nil)
+	m.provider.EXPECT().Capture(mock.Anything, mock.Anything).Return(nil, payment.ErrDeclined)
+	m.provider.EXPECT().Void(mock.Anything, "auth_1").Return(nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonRelease, 2)).Return(nil)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, orderWithStatus(domain.OrderStatusCancelled)).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Times(2)
+
+	_, err := svc.CreateOrder(ctx, factory.NewUser().ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 2}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+
+	assert.ErrorIs(t, err, service.ErrPaymentFailed)
+	// Nothing was captured, so nothing is refunded
+}
+
+func TestCreateOrder_Unit_StripeCallsUseDistinctIdempotencyKeys(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		keys = make(map[string]string) // Idempotency keys by path
+	)
+	stub := http.NewServeMux()
+	record := func(response string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			keys[r.URL.Path] = r.Header.Get("Idempotency-Key")
+			mu.Unlock()
+			_, _ = w.Write([]byte(response))
+		}
+	}
+	stub.HandleFunc("POST /v1/payment_intents", record(`{"id":"pi_1","status":"requires_capture","latest_charge":"ch_1"}`))
+	stub.HandleFunc("POST /v1/payment_intents/pi_1/capture", record(`{"id":"pi_1","status":"succeeded","latest_charge":"ch_1"}`))
+	stub.HandleFunc("POST /v1/refunds", record(`{"id":"re_1","status":"succeeded"}`))
+	server := httptest.NewServer(stub)
+	defer server.Close()
+	stripe := payment.NewStripeProvider(payment.StripeConfig{BaseURL: server.URL, SecretKey: "sk_test_1"})
+	svc, m := newOrderServiceWithRegistry(t, 0, payment.NewRegistry(stripe))
+	ctx := context.Background()
+	product := factory.NewProduct(factory.WithQuantity(10))
+
+	// Recording the payment fails, so the capture is refunded
+	m.expectEventLog(domain.OrderEventPaid)
+	m.expectLedger()
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonAllocation, -1)).Return(nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.tx.EXPECT().Rollback(mock.Anything).Return(nil).Once()
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonRelease, 1)).Return(nil)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, orderWithStatus(domain.OrderStatusCancelled)).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Times(2)
+
+	_, err := svc.CreateOrder(ctx, factory.NewUser().ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 1}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{Token: "pm_card_visa"})
+
+	assert.ErrorIs(t, err, errAppend)
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, keys, 3)
+	authorize, capture, refund := keys["/v1/payment_intents"], keys["/v1/payment_intents/pi_1/capture"], keys["/v1/refunds"]
+	assert.NotEmpty(t, authorize)
+	assert.NotEqual(t, authorize, capture, "Stripe rejects a key reused on another endpoint")
+	assert.NotEqual(t, authorize, refund)
+	assert.NotEqual(t, capture, refund)
+}
+
+// usd returns an amount of US cents.
+func usd(cents int64) money.Amount {
+	c, _ := money.LookupCurrency("USD")
+	return money.Amount{Minor: cents, Currency: c}
+}
+
+func TestCreateOrder_Unit_PendingCaptureAwaitsConfirmation(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	user := factory.NewUser()
+	product := factory.NewProduct(factory.WithQuantity(10), factory.WithPrice(2.5))
+
+	m.expectEventLog("")
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonAllocation, -4)).Return(nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.provider.EXPECT().Authorize(mock.Anything, mock.Anything).Return(&payment.Authorization{ID: "auth_1", Method: domain.PaymentMethodCard}, nil)
+	m.provider.EXPECT().Capture(mock.Anything, mock.Anything).Return(&payment.Capture{ID: "cap_1", Pending: true}, nil)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, mock.MatchedBy(func(o *domain.Order) bool {
+		return o.Status == domain.OrderStatusCreated && o.PendingPayment == "cap_1"
+	})).Return(nil).Once()
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil)
+
+	order, err := svc.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 4}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusCreated, order.Status, "not paid before the capture completes")
+	assert.Equal(t, "cap_1", order.PendingPayment)
+	m.ledger.EXPECT().FindByOrderID(mock.Anything, order.ID).Return(nil, nil).Once()
+	_, err = svc.PayOrder(ctx, user.ID, order.ID, service.PaymentSource{})
+	assert.ErrorIs(t, err, service.ErrPaymentPending)
+
+	// The provider confirms the capture by webhook
+	m.expectLedger()
+	m.ledger.EXPECT().FindChargeByReference(mock.Anything, "mock", "cap_1").Return(nil, repository.ErrPaymentNotFound)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, mock.MatchedBy(func(o *domain.Order) bool {
+		return o.Status == domain.OrderStatusPaid && o.PendingPayment == "" && o.PaymentID == "cap_1"
+	})).Return(nil)
+	m.notifier.EXPECT().Notify(mock.Anything, user.ID, mock.MatchedBy(func(msg notification.Message) bool {
+		return msg.Template == notification.TemplateOrderConfirmation && msg.Data["Total"] == "$10.00"
+	})).Return(nil)
+
+	err = svc.ConfirmCapture(ctx, "mock", &payment.CaptureUpdate{
+		ID: "cap_1", OrderID: order.ID, Status: payment.CaptureCompleted, Method: domain.PaymentMethodCard, Amount: usd(1000),
+	})
+
+	require.NoError(t, err)
+}
+
+func TestCreateOrder_Unit_PendingRecordFailureRefundsCapture(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	product := factory.NewProduct(factory.WithQuantity(10))
+
+	m.expectEventLog(domain.OrderEventPaymentPending)
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonAllocation, -1)).Return(nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.provider.EXPECT().Authorize(mock.Anything, mock.Anything).Return(&payment.Authorization{ID: "auth_1", Method: domain.PaymentMethodCard}, nil)
+	m.provider.EXPECT().Capture(mock.Anything, mock.Anything).Return(&payment.Capture{ID: "cap_1", Pending: true}, nil)
+	m.tx.EXPECT().Rollback(mock.Anything).Return(nil).Once()
+	// The capture may still complete, so it is refunded with the order cancelled
+	m.provider.EXPECT().Refund(mock.Anything, mock.MatchedBy(func(req payment.RefundRequest) bool {
+		return req.CaptureID == "cap_1" && strings.HasSuffix(req.IdempotencyKey, ":refund")
+	})).Return(&payment.Refund{ID: "ref_1"}, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonRelease, 1)).Return(nil)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, orderWithStatus(domain.OrderStatusCancelled)).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Times(2)
+
+	_, err := svc.CreateOrder(ctx, factory.NewUser().ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 1}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+
+	assert.ErrorIs(t, err, errAppend)
+}
+
+func TestConfirmCapture_Unit_DeclinedCaptureIsPaidAgain(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	user := factory.NewUser()
+	product := factory.NewProduct(factory.WithQuantity(10), factory.WithPrice(2.5))
+
+	m.expectEventLog("")
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil)
+	m.provider.EXPECT().Authorize(mock.Anything, mock.MatchedBy(func(req payment.AuthorizeRequest) bool {
+		return req.Source == "bank-debit"
+	})).Return(&payment.Authorization{ID: "auth_1", Method: domain.PaymentMethodBankTransfer}, nil)
+	m.provider.EXPECT().Capture(mock.Anything, mock.MatchedBy(func(req payment.CaptureRequest) bool {
+		return req.AuthorizationID == "auth_1"
+	})).Return(&payment.Capture{ID: "cap_1", Pending: true}, nil)
+	order, err := svc.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 4}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{Token: "bank-debit"})
+	require.NoError(t, err)
+
+	m.ledger.EXPECT().FindChargeByReference(mock.Anything, "mock", "cap_1").Return(nil, repository.ErrPaymentNotFound)
+	err = svc.ConfirmCapture(ctx, "mock", &payment.CaptureUpdate{ID: "cap_1", OrderID: order.ID, Status: payment.CaptureFailed, Amount: usd(1000)})
+	require.NoError(t, err)
+
+	_, err = svc.PayOrder(ctx, uuid.New(), order.ID, service.PaymentSource{Token: "card"})
+	assert.ErrorIs(t, err, service.ErrNotOrderOwner)
+
+	m.expectLedger()
+	m.ledger.EXPECT().FindByOrderID(mock.Anything, order.ID).Return(nil, nil)
+	m.provider.EXPECT().Authorize(mock.Anything, mock.MatchedBy(func(req payment.AuthorizeRequest) bool {
+		return req.Source == "card" && req.Amount.Minor == 1000 && req.IdempotencyKey != order.ID.String()
+	})).Return(&payment.Authorization{ID: "auth_2", Method: domain.PaymentMethodCard}, nil)
+	m.provider.EXPECT().Capture(mock.Anything, mock.MatchedBy(func(req payment.CaptureRequest) bool {
+		return req.AuthorizationID == "auth_2"
+	})).Return(&payment.Capture{ID: "cap_2"}, nil)
+	m.notifier.EXPECT().Notify(mock.Anything, user.ID, mock.Anything).Return(nil)
+
+	paid, err := svc.PayOrder(ctx, user.ID, order.ID, service.PaymentSource{Token: "card"})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusPaid, paid.Status)
+	assert.Equal(t, "cap_2", paid.PaymentID)
+	_, err = svc.PayOrder(ctx, user.ID, order.ID, service.PaymentSource{Token: "card"})
+	assert.ErrorIs(t, err, domain.ErrInvalidPayment, "paid orders do not await payment")
+}
+
+func TestPayOrder_Unit_RetriesReuseIdempotencyKey(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	user := factory.NewUser()
+	product := factory.NewProduct(factory.WithQuantity(10), factory.WithPrice(2.5))
+
+	m.expectEventLog("")
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil)
+	m.provider.EXPECT().Authorize(mock.Anything, mock.MatchedBy(func(req payment.AuthorizeRequest) bool {
+		return req.Source == "bank-debit"
+	})).Return(&payment.Authorization{ID: "auth_1", Method: domain.PaymentMethodBankTransfer}, nil).Once()
+	m.provider.EXPECT().Capture(mock.Anything, mock.Anything).Return(&payment.Capture{ID: "cap_1", Pending: true}, nil).Once()
+	order, err := svc.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 4}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{Token: "bank-debit"})
+	require.NoError(t, err)
+
+	m.ledger.EXPECT().FindChargeByReference(mock.Anything, "mock", mock.Anything).Return(nil, repository.ErrPaymentNotFound)
+	require.NoError(t, svc.ConfirmCapture(ctx, "mock", &payment.CaptureUpdate{ID: "cap_1", OrderID: order.ID, Status: payment.CaptureFailed, Amount: usd(1000)}))
+
+	var keys []string // Keys of card payments
+	m.ledger.EXPECT().FindByOrderID(mock.Anything, order.ID).Return(nil, nil)
+	m.provider.EXPECT().Authorize(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req payment.AuthorizeRequest) (*payment.Authorization, error) {
+		if req.Source == "bank-debit" {
+			return &payment.Authorization{ID: "auth_2", Method: domain.PaymentMethodBankTransfer}, nil
+		}
+		keys = append(keys, req.IdempotencyKey)
+		return nil, payment.ErrDeclined
+	})
+	m.provider.EXPECT().Capture(mock.Anything, mock.Anything).Return(&payment.Capture{ID: "cap_2", Pending: true}, nil).Once()
+	pay := func(token string) error {
+		_, err := svc.PayOrder(ctx, user.ID, order.ID, service.PaymentSource{Token: token})
+		return err
+	}
+
+	require.ErrorIs(t, pay("card"), service.ErrPaymentFailed)
+	require.ErrorIs(t, pay("card"), service.ErrPaymentFailed) // Client retry
+	require.ErrorIs(t, pay("other-card"), service.ErrPaymentFailed)
+	require.NoError(t, pay("bank-debit"))
+	require.NoError(t, svc.ConfirmCapture(ctx, "mock", &payment.CaptureUpdate{ID: "cap_2", OrderID: order.ID, Status: payment.CaptureFailed, Amount: usd(1000)}))
+	require.ErrorIs(t, pay("card"), service.ErrPaymentFailed)
+
+	require.Len(t, keys, 4)
+	assert.Equal(t, keys[0], keys[1], "a retry does not charge twice")
+	assert.NotEqual(t, keys[0], keys[2], "another payment method is a new payment")
+	assert.NotEqual(t, keys[0], keys[3], "a payment after a failed capture is a new payment")
+	assert.Contains(t, keys[0], order.ID.String())
+}
+
+func TestCreateOrder_Unit_InsufficientStockRollsBack(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	product := factory.NewProduct(factory.WithQuantity(1))
+
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.tx.EXPECT().Rollback(mock.Anything).Return(nil)
+
+	_, err := svc.CreateOrder(ctx, factory.NewUser().ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 2}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+
+	assert.ErrorIs(t, err, service.ErrInsufficientStock)
+	// No commit, stock update or notification is expected by the mocks
+}
+
+func TestCreateOrder_Unit_OptionFeesCharged(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	product := factory.NewProduct(factory.WithQuantity(10), factory.WithPrice(2.5))
+
+	m.expectEventLog("")
+	m.expectLedger()
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonAllocation, -2)).Return(nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.MatchedBy(func(o *domain.Order) bool {
+		return len(o.Options) == 2 && o.TotalAmount == 14.99
+	})).Return(nil)
+	m.provider.EXPECT().Authorize(mock.Anything, mock.MatchedBy(func(req payment.AuthorizeRequest) bool {
+		return req.Amount.Minor == 1499
+	})).Return(&payment.Authorization{ID: "auth_1", Method: domain.PaymentMethodCard}, nil)
+	m.provider.EXPECT().Capture(mock.Anything, mock.Anything).Return(&payment.Capture{ID: "cap_1"}, nil)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, orderWithStatus(domain.OrderStatusPaid)).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Times(2)
+	m.notifier.EXPECT().Notify(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	options := service.OrderOptionsInput{GiftMessage: "Happy birthday!", PriorityHandling: true}
+	order, err := svc.CreateOrder(ctx, factory.NewUser().ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 2}}, options, uuid.Nil, false, service.PaymentSource{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 14.99, order.TotalAmount, "priority handling is charged, gift messages are free")
+	assert.Equal(t, "Happy birthday!", order.GiftMessage())
+	assert.Equal(t, "gift_message;priority_handling", order.OptionNames(";"))
+}
+
+func TestCreateOrder_Unit_GiftMessageTooLong(t *testing.T) {
+	svc, _ := newOrderServiceWithMocks(t)
+	options := service.OrderOptionsInput{GiftMessage: strings.Repeat("ü", domain.MaxGiftMessageLength+1)}
+
+	_, err := svc.CreateOrder(context.Background(), factory.NewUser().ID, []service.OrderItemInput{{ProductID: uuid.New(), Quantity: 1}}, options, uuid.Nil, false, service.PaymentSource{})
+
+	assert.ErrorIs(t, err, service.ErrGiftMessageTooLong)
+	// Nothing is reserved, no repository call is expected by the mocks
+}
+
+func TestCreateOrder_Unit_DeliverySlotReleasedOnDecline(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	product := factory.NewProduct(factory.WithQuantity(10))
+	slot := &domain.DeliverySlot{ID: uuid.New(), Region: "eu-west-1", StartsAt: time.Now().Add(24 * time.Hour), EndsAt: time.Now().Add(27 * time.Hour), Capacity: 5, Reserved: 1}
+
+	events := m.expectEventLog("")
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.slotRepo.EXPECT().ReserveTx(mock.Anything, m.tx, slot.ID).Return(slot, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonAllocation, -1)).Return(nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.MatchedBy(func(o *domain.Order) bool {
+		return o.Delivery != nil && o.Delivery.SlotID == slot.ID && o.Delivery.Region == "eu-west-1"
+	})).Return(nil)
+	m.provider.EXPECT().Authorize(mock.Anything, mock.Anything).Return(nil, payment.ErrDeclined)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonRelease, 1)).Return(nil)
+	m.slotRepo.EXPECT().ReleaseTx(mock.Anything, m.tx, slot.ID).Return(nil)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, orderWithStatus(domain.OrderStatusCancelled)).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Times(2)
+
+	_, err := svc.CreateOrder(ctx, factory.NewUser().ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 1}}, service.OrderOptionsInput{}, slot.ID, false, service.PaymentSource{})
+
+	assert.ErrorIs(t, err, service.ErrPaymentFailed)
+	assert.Equal(t, slot.ID, (*events)[0].Delivery.SlotID, "the delivery window is recorded in the event log")
+}
+
+func TestCreateOrder_Unit_DeliverySlotUnavailable(t *testing.T) {
+	started := &domain.DeliverySlot{ID: uuid.New(), Region: "eu-west-1", StartsAt: time.Now().Add(-time.Hour), EndsAt: time.Now().Add(2 * time.Hour), Capacity: 5}
+	elsewhere := &domain.DeliverySlot{ID: uuid.New(), Region: "us-east-1", StartsAt: time.Now().Add(24 * time.Hour), EndsAt: time.Now().Add(27 * time.Hour), Capacity: 5}
+	tests := []struct {
+		name string
+		slot *domain.DeliverySlot
+		err  error
+	}{
+		{name: "full", err: repository.ErrDeliverySlotFull},
+		{name: "not found", err: repository.ErrDeliverySlotNotFound},
+		{name: "started", slot: started},
+		{name: "other region", slot: elsewhere},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := newOrderServiceWithMocks(t)
+			product := factory.NewProduct(factory.WithQuantity(10))
+
+			m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+			m.slotRepo.EXPECT().ReserveTx(mock.Anything, m.tx, mock.Anything).Return(tt.slot, tt.err)
+			m.tx.EXPECT().Rollback(mock.Anything).Return(nil)
+
+			_, err := svc.CreateOrder(context.Background(), factory.NewUser().ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 1}}, service.OrderOptionsInput{}, started.ID, false, service.PaymentSource{})
+
+			assert.ErrorIs(t, err, service.ErrDeliverySlotUnavailable)
+			// No stock is allocated, no allocation is expected by the mocks
+		})
+	}
+}
+
+func TestCreateOrder_Unit_PossibleDuplicate(t *testing.T) {
+	svc, m := newOrderServiceWithDuplicateWindow(t, 10*time.Minute)
+	ctx := context.Background()
+	user := factory.NewUser()
+	product := factory.NewProduct(factory.WithQuantity(10), factory.WithPrice(2.5))
+	items := []service.OrderItemInput{{ProductID: product.ID, Quantity: 2}}
+	recent := &domain.Order{ID: uuid.New(), Number: "ORD-2024-000122", UserID: user.ID, Status: domain.OrderStatusPaid, CreatedAt: time.Now().Add(-time.Minute), TotalAmount: 5}
+
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonAllocation, -2)).Return(nil)
+	m.orderRepo.EXPECT().FindDuplicateTx(mock.Anything, m.tx, mock.MatchedBy(func(o *domain.Order) bool {
+		return o.UserID == user.ID && o.TotalAmount == 5
+	}), mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) > 9*time.Minute && time.Since(since) < 11*time.Minute
+	})).Return(recent, nil).Once()
+	m.tx.EXPECT().Rollback(mock.Anything).Return(nil).Once()
+
+	_, err := svc.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+
+	require.ErrorIs(t, err, service.ErrPossibleDuplicateOrder)
+	var duplicate *service.DuplicateOrderError
+	require.ErrorAs(t, err, &duplicate)
+	assert.Equal(t, recent.ID, duplicate.Order.ID)
+	// Nothing is charged, no payment is expected by the mocks
+
+	// A confirmed checkout is placed without the check
+	m.expectEventLog("")
+	m.expectLedger()
+	m.expectPayment()
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonAllocation, -2)).Return(nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, orderWithStatus(domain.OrderStatusPaid)).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Times(2)
+	m.notifier.EXPECT().Notify(mock.Anything, user.ID, mock.Anything).Return(nil)
+
+	order, err := svc.CreateOrder(ctx, user.ID, items, service.OrderOptionsInput{}, uuid.Nil, true, service.PaymentSource{})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusPaid, order.Status)
+}
+
+func TestCreateOrder_Unit_ArchivedProductRejected(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	product := factory.NewProduct(factory.WithStatus(domain.ProductStatusArchived))
+
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.tx.EXPECT().Rollback(mock.Anything).Return(nil)
+
+	_, err := svc.CreateOrder(ctx, factory.NewUser().ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 1}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+
+	assert.ErrorIs(t, err, service.ErrProductUnavailable)
+}
+
+func TestCreateOrder_Unit_BundleAllocatesComponents(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	user := factory.NewUser()
+	speaker := factory.NewProduct(factory.WithQuantity(10))
+	cable := factory.NewProduct(factory.WithQuantity(10))
+	bundle := factory.NewProduct(factory.WithQuantity(0), factory.WithPrice(50),
+		factory.WithComponents(domain.BundleComponent{ProductID: speaker.ID, Quantity: 2}, domain.BundleComponent{ProductID: cable.ID, Quantity: 1}))
+
+	m.expectEventLog("")
+	m.expectLedger()
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, bundle.ID).Return(bundle, nil)
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, speaker.ID).Return(speaker, nil)
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, cable.ID).Return(cable, nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(speaker.ID, domain.StockReasonAllocation, -6)).Return(nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(cable.ID, domain.StockReasonAllocation, -3)).Return(nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.expectPayment()
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, orderWithStatus(domain.OrderStatusPaid)).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Times(2)
+	m.notifier.EXPECT().Notify(mock.Anything, user.ID, mock.Anything).Return(nil)
+
+	order, err := svc.CreateOrder(ctx, user.ID, []service.OrderItemInput{{ProductID: bundle.ID, Quantity: 3}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 150.0, order.TotalAmount)
+	require.Len(t, order.Items, 3)
+	assert.True(t, order.Items[0].Bundle)
+	assert.Equal(t, &order.Items[0].ID, order.Items[1].BundleItemID)
+	assert.Equal(t, 6, order.Items[1].Quantity)
+	assert.Zero(t, order.Items[1].PriceAtPurchase)
+}
+
+func TestCreateOrder_Unit_SandboxOrderAllocatesNoStock(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	partner := factory.NewUser()
+	partner.Sandbox = true
+	m.buyers[partner.ID] = partner
+	product := factory.NewProduct(factory.WithQuantity(10), factory.WithPrice(2.5))
+
+	var published []event.Event
+	record := func(_ context.Context, e event.Event) error {
+		published = append(published, e)
+		return nil
+	}
+	m.events.Subscribe("order.created", "test", record)
+	m.events.Subscribe("product.stock_changed", "test", record)
+
+	// No stock movement and no payment through the selected provider are expected by the mocks
+	events := m.expectEventLog("")
+	m.expectLedger()
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.MatchedBy(func(o *domain.Order) bool { return o.Sandbox })).Return(nil)
+	m.sandbox.EXPECT().Authorize(mock.Anything, mock.Anything).Return(&payment.Authorization{ID: "auth_1", Method: domain.PaymentMethodCard}, nil)
+	m.sandbox.EXPECT().Capture(mock.Anything, mock.Anything).Return(&payment.Capture{ID: "cap_1"}, nil)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, orderWithStatus(domain.OrderStatusPaid)).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Times(2)
+	m.notifier.EXPECT().Notify(mock.Anything, partner.ID, mock.Anything).Return(nil)
+
+	order, err := svc.CreateOrder(ctx, partner.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 4}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{Provider: "mock"})
+
+	require.NoError(t, err)
+	assert.True(t, order.Sandbox)
+	assert.Equal(t, domain.OrderStatusPaid, order.Status)
+	require.Len(t, order.Payments, 1)
+	assert.Equal(t, "sandbox", order.Payments[0].Provider)
+	assert.True(t, (*events)[0].Settings.Sandbox, "the event log marks the order")
+
+	require.Len(t, published, 1, "no stock change is published")
+	created, ok := published[0].(event.OrderCreated)
+	require.True(t, ok)
+	assert.True(t, created.Order.Sandbox)
+}
+
+func TestCreateOrder_Unit_SandboxOrderDeclinedReleasesNothing(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	partner := factory.NewUser()
+	partner.Sandbox = true
+	m.buyers[partner.ID] = partner
+	product := factory.NewProduct(factory.WithQuantity(10))
+	slot := &domain.DeliverySlot{ID: uuid.New(), Region: "eu-west-1", StartsAt: time.Now().Add(24 * time.Hour), EndsAt: time.Now().Add(27 * time.Hour), Capacity: 5, Reserved: 1}
+
+	m.expectEventLog("")
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	// The slot's capacity is checked and given back in the checkout transaction, cancelling releases it no more
+	m.slotRepo.EXPECT().ReserveTx(mock.Anything, m.tx, slot.ID).Return(slot, nil).Once()
+	m.slotRepo.EXPECT().ReleaseTx(mock.Anything, m.tx, slot.ID).Return(nil).Once()
+	m.orderRepo.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.sandbox.EXPECT().Authorize(mock.Anything, mock.Anything).Return(nil, payment.ErrDeclined)
+	m.orderRepo.EXPECT().UpdateStatusTx(mock.Anything, m.tx, orderWithStatus(domain.OrderStatusCancelled)).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Times(2)
+
+	_, err := svc.CreateOrder(ctx, partner.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 1}}, service.OrderOptionsInput{}, slot.ID, false, service.PaymentSource{})
+
+	assert.ErrorIs(t, err, service.ErrPaymentFailed)
+	// No stock release is expected by the mocks
+}
+
+func TestCreateOrder_Unit_SandboxOrderChecksStock(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	partner := factory.NewUser()
+	partner.Sandbox = true
+	m.buyers[partner.ID] = partner
+	product := factory.NewProduct(factory.WithQuantity(2))
+
+	m.productRepo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.tx.EXPECT().Rollback(mock.Anything).Return(nil)
+
+	_, err := svc.CreateOrder(context.Background(), partner.ID, []service.OrderItemInput{{ProductID: product.ID, Quantity: 3}}, service.OrderOptionsInput{}, uuid.Nil, false, service.PaymentSource{})
+
+	assert.ErrorIs(t, err, service.ErrInsufficientStock, "checkouts behave as in production")
+}
+
+func TestRefund_Unit_PartialCardRefund(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	order := factory.NewOrder(uuid.New(), factory.WithItem(factory.NewProduct(factory.WithPrice(10)), 2))
+	events := append(order.CreationEvents(), domain.OrderEvent{Type: domain.OrderEventPaid, PaymentID: "cap_1"})
+	card := domain.Payment{ID: uuid.New(), OrderID: order.ID, Kind: domain.PaymentKindCharge, Method: domain.PaymentMethodCard,
+		Reference: "cap_1", Amount: 20, Provider: "mock"}
+
+	m.eventRepo.EXPECT().FindByOrderID(mock.Anything, order.ID, time.Time{}).Return(events, nil)
+	m.ledger.EXPECT().FindByOrderIDTx(mock.Anything, m.tx, order.ID).Return([]domain.Payment{card}, nil)
+	m.provider.EXPECT().Refund(mock.Anything, mock.MatchedBy(func(req payment.RefundRequest) bool {
+		return req.CaptureID == "cap_1" && req.Amount.Minor == 550
+	})).Return(&payment.Refund{ID: "re_1"}, nil).Once()
+	m.ledger.EXPECT().CreateTx(mock.Anything, m.tx, mock.MatchedBy(func(p *domain.Payment) bool {
+		return p.Kind == domain.PaymentKindRefund && *p.RefundOf == card.ID && p.Reference == "re_1"
+	})).Return(nil).Once()
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Once()
+	m.tx.EXPECT().Rollback(mock.Anything).Return(nil).Twice()
+
+	refunded, err := svc.Refund(ctx, order.ID, card.ID, 5.5, "")
+	require.NoError(t, err)
+	assert.Equal(t, 20.0, refunded.PaidAmount())
+	assert.Equal(t, 5.5, refunded.RefundedAmount())
+
+	// Neither exceeding the charge nor refunding an unknown payment reaches the provider
+	_, err = svc.Refund(ctx, order.ID, card.ID, 20.01, "")
+	assert.ErrorIs(t, err, domain.ErrInvalidRefund)
+	_, err = svc.Refund(ctx, order.ID, uuid.New(), 1, "")
+	assert.ErrorIs(t, err, service.ErrPaymentNotFound)
+}
+
+func TestRefund_Unit_RetryWithKeyRefundsOnce(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	order := factory.NewOrder(uuid.New(), factory.WithItem(factory.NewProduct(factory.WithPrice(10)), 2))
+	events := append(order.CreationEvents(), domain.OrderEvent{Type: domain.OrderEventPaid, PaymentID: "cap_1"})
+	card := domain.Payment{ID: uuid.New(), OrderID: order.ID, Kind: domain.PaymentKindCharge, Method: domain.PaymentMethodCard,
+		Reference: "cap_1", Amount: 20, Provider: "mock"}
+	ledger := []domain.Payment{card}
+
+	m.eventRepo.EXPECT().FindByOrderID(mock.Anything, order.ID, time.Time{}).Return(events, nil)
+	m.ledger.EXPECT().FindByOrderIDTx(mock.Anything, m.tx, order.ID).RunAndReturn(func(context.Context, pgx.Tx, uuid.UUID) ([]domain.Payment, error) {
+		return slices.Clone(ledger), nil
+	})
+	var providerKey string
+	m.provider.EXPECT().Refund(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, req payment.RefundRequest) (*payment.Refund, error) {
+		providerKey = req.IdempotencyKey
+		return &payment.Refund{ID: "re_1"}, nil
+	}).Once()
+	m.ledger.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).RunAndReturn(func(_ context.Context, _ pgx.Tx, p *domain.Payment) error {
+		ledger = append(ledger, *p)
+		return nil
+	}).Once()
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Twice()
+
+	// A double-submitted request, e.g. retried after its stored response expired
+	for range 2 {
+		refunded, err := svc.Refund(ctx, order.ID, card.ID, 5, "refund-42")
+		require.NoError(t, err)
+		assert.Equal(t, 5.0, refunded.RefundedAmount())
+	}
+	require.Len(t, ledger, 2)
+	assert.Equal(t, ledger[1].ID.String(), providerKey, "the refund ID is the provider's idempotency key")
+}
+
+func TestRefund_Unit_LegacyPaymentIsOnlyRecorded(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	order := factory.NewOrder(uuid.New(), factory.WithItem(factory.NewProduct(factory.WithPrice(10)), 2))
+	events := append(order.CreationEvents(), domain.OrderEvent{Type: domain.OrderEventPaid})
+	card := domain.Payment{ID: uuid.New(), OrderID: order.ID, Kind: domain.PaymentKindCharge, Method: domain.PaymentMethodCard,
+		Amount: 20, Provider: domain.PaymentProviderLegacy}
+
+	m.eventRepo.EXPECT().FindByOrderID(mock.Anything, order.ID, time.Time{}).Return(events, nil)
+	m.ledger.EXPECT().FindByOrderIDTx(mock.Anything, m.tx, order.ID).Return([]domain.Payment{card}, nil)
+	m.ledger.EXPECT().CreateTx(mock.Anything, m.tx, mock.MatchedBy(func(p *domain.Payment) bool {
+		return p.Kind == domain.PaymentKindRefund && p.Provider == domain.PaymentProviderLegacy && p.Reference == ""
+	})).Return(nil).Once()
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Once()
+
+	// No provider is called, finance pays the refund out-of-band
+	refunded, err := svc.Refund(ctx, order.ID, card.ID, 20, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, 20.0, refunded.RefundedAmount())
+}
+
+func TestRefund_Unit_UsesOrderCurrency(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	// Placed while the shop sold in yen, the service is now configured for dollars
+	order := factory.NewOrder(uuid.New(), factory.WithItem(factory.NewProduct(factory.WithPrice(1500)), 1))
+	order.OrderSettings = domain.OrderSettings{Currency: "JPY", Locale: "ja-JP"}
+	events := append(order.CreationEvents(), domain.OrderEvent{Type: domain.OrderEventPaid, PaymentID: "cap_1"})
+	card := domain.Payment{ID: uuid.New(), OrderID: order.ID, Kind: domain.PaymentKindCharge, Method: domain.PaymentMethodCard,
+		Reference: "cap_1", Amount: 1500, Provider: "mock"}
+
+	m.eventRepo.EXPECT().FindByOrderID(mock.Anything, order.ID, time.Time{}).Return(events, nil)
+	m.ledger.EXPECT().FindByOrderIDTx(mock.Anything, m.tx, order.ID).Return([]domain.Payment{card}, nil)
+	m.provider.EXPECT().Refund(mock.Anything, mock.MatchedBy(func(req payment.RefundRequest) bool {
+		return req.Amount.Currency.Code == "JPY" && req.Amount.Minor == 500
+	})).Return(&payment.Refund{ID: "re_1"}, nil).Once()
+	m.ledger.EXPECT().CreateTx(mock.Anything, m.tx, mock.Anything).Return(nil).Once()
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil).Once()
+
+	refunded, err := svc.Refund(context.Background(), order.ID, card.ID, 500, "")
+	require.NoError(t, err)
+	assert.Equal(t, "JPY", refunded.Currency)
+}
+
+func TestResendConfirmation_Unit(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	order := factory.NewOrder(uuid.New(), factory.WithItem(factory.NewProduct(factory.WithPrice(1500)), 1))
+	order.OrderSettings = domain.OrderSettings{Currency: "JPY", Locale: "ja-JP"}
+	order.Status = domain.OrderStatusShipped
+
+	m.orderRepo.EXPECT().FindByID(mock.Anything, order.ID).Return(order, nil).Once()
+	m.notifier.EXPECT().Notify(mock.Anything, order.UserID, notification.Message{
+		Category: domain.NotificationCategoryOrderUpdates,
+		Template: notification.TemplateOrderConfirmation,
+		Data:     map[string]string{"OrderNumber": order.Number, "Total": "¥1,500"},
+		Locale:   "ja-JP",
+	}).Return(nil).Once()
+	_, err := svc.ResendConfirmation(ctx, order.ID)
+	require.NoError(t, err)
+
+	// Unpaid orders were never confirmed
+	order.Status = domain.OrderStatusCancelled
+	m.orderRepo.EXPECT().FindByID(mock.Anything, order.ID).Return(order, nil).Once()
+	_, err = svc.ResendConfirmation(ctx, order.ID)
+	assert.ErrorIs(t, err, service.ErrOrderNotConfirmed)
+
+	m.orderRepo.EXPECT().FindByID(mock.Anything, mock.Anything).Return(nil, repository.ErrOrderNotFound).Once()
+	_, err = svc.ResendConfirmation(ctx, uuid.New())
+	assert.ErrorIs(t, err, service.ErrOrderNotFound)
+}
+
+func TestApproveRefund_Unit_RefundsAndRestocks(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	product := factory.NewProduct(factory.WithPrice(10))
+	order := factory.NewOrder(uuid.New(), factory.WithItem(product, 2))
+	events := append(order.CreationEvents(), domain.OrderEvent{Type: domain.OrderEventPaid, PaymentID: "cap_1"},
+		domain.OrderEvent{Type: domain.OrderEventShipped})
+	card := domain.Payment{ID: uuid.New(), OrderID: order.ID, Kind: domain.PaymentKindCharge, Method: domain.PaymentMethodCard,
+		Reference: "cap_1", Amount: 20, Provider: "mock"}
+	request := &domain.RefundRequest{ID: uuid.New(), OrderID: order.ID, PaymentID: card.ID, Amount: 10,
+		Items: []domain.RefundItem{{ItemID: order.Items[0].ID, Quantity: 1}}, Status: domain.RefundRequestRequested,
+		RequestedBy: "client:support"}
+
+	m.refunds.EXPECT().FindByIDTx(mock.Anything, m.tx, request.ID).Return(request, nil)
+	m.ledger.EXPECT().FindByOrderIDTx(mock.Anything, m.tx, order.ID).Return([]domain.Payment{card}, nil)
+	m.eventRepo.EXPECT().FindByOrderID(mock.Anything, order.ID, time.Time{}).Return(events, nil)
+	m.provider.EXPECT().Refund(mock.Anything, mock.MatchedBy(func(req payment.RefundRequest) bool {
+		return req.CaptureID == "cap_1" && req.Amount.Minor == 1000 && req.IdempotencyKey == request.ID.String()
+	})).Return(&payment.Refund{ID: "re_1"}, nil)
+	m.ledger.EXPECT().CreateTx(mock.Anything, m.tx, mock.MatchedBy(func(p *domain.Payment) bool {
+		return p.Kind == domain.PaymentKindRefund && p.ID == request.ID && p.Reference == "re_1"
+	})).Return(nil)
+	m.stockRepo.EXPECT().RecordTx(mock.Anything, m.tx, stockMovement(product.ID, domain.StockReasonReturn, 1)).Return(nil)
+	m.refunds.EXPECT().UpdateTx(mock.Anything, m.tx, request).Return(nil)
+	m.refunds.EXPECT().AppendEventTx(mock.Anything, m.tx, mock.MatchedBy(func(e *domain.RefundRequestEvent) bool {
+		return e.Action == domain.RefundActionApproved && e.Actor == "client:finance"
+	})).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil)
+
+	approved, err := svc.ApproveRefund(ctx, "client:finance", request.ID, "return received")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.RefundRequestApproved, approved.Status)
+	assert.Equal(t, request.ID, *approved.RefundID)
+	assert.Equal(t, "client:finance", approved.DecidedBy)
+}
+
+func TestApproveRefund_Unit_ProviderFailureIsAudited(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	ctx := context.Background()
+	order := factory.NewOrder(uuid.New(), factory.WithItem(factory.NewProduct(factory.WithPrice(10)), 2))
+	events := append(order.CreationEvents(), domain.OrderEvent{Type: domain.OrderEventPaid, PaymentID: "cap_1"})
+	card := domain.Payment{ID: uuid.New(), OrderID: order.ID, Kind: domain.PaymentKindCharge, Method: domain.PaymentMethodCard,
+		Reference: "cap_1", Amount: 20, Provider: "mock"}
+	request := &domain.RefundRequest{ID: uuid.New(), OrderID: order.ID, PaymentID: card.ID, Amount: 10,
+		Status: domain.RefundRequestRequested, RequestedBy: "client:support"}
+
+	m.refunds.EXPECT().FindByIDTx(mock.Anything, m.tx, request.ID).Return(request, nil)
+	m.ledger.EXPECT().FindByOrderIDTx(mock.Anything, m.tx, order.ID).Return([]domain.Payment{card}, nil)
+	m.eventRepo.EXPECT().FindByOrderID(mock.Anything, order.ID, time.Time{}).Return(events, nil)
+	m.provider.EXPECT().Refund(mock.Anything, mock.Anything).Return(nil, errors.New("capture disputed"))
+	m.tx.EXPECT().Rollback(mock.Anything).Return(nil)
+	m.refunds.EXPECT().AppendEvent(mock.Anything, mock.MatchedBy(func(e *domain.RefundRequestEvent) bool {
+		return e.RequestID == request.ID && e.Action == domain.RefundActionRefundFailed && strings.Contains(e.Note, "capture disputed")
+	})).Return(nil)
+
+	_, err := svc.ApproveRefund(ctx, "client:finance", request.ID, "")
+
+	assert.ErrorIs(t, err, service.ErrRefundFailed)
+}
+
+func TestGetOrder_Unit_AttachesPayments(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	order := factory.NewOrder(uuid.New(), factory.WithItem(factory.NewProduct(factory.WithPrice(10)), 2))
+	card := domain.Payment{ID: uuid.New(), OrderID: order.ID, Kind: domain.PaymentKindCharge, Method: domain.PaymentMethodCard, Amount: 20}
+
+	m.orderRepo.EXPECT().FindByID(mock.Anything, order.ID).Return(order, nil)
+	m.ledger.EXPECT().FindByOrderID(mock.Anything, order.ID).Return([]domain.Payment{card}, nil)
+
+	got, err := svc.GetOrder(context.Background(), order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, order.UserID, got.UserID)
+	assert.Equal(t, 20.0, got.PaidAmount())
+
+	m.orderRepo.EXPECT().FindByID(mock.Anything, mock.Anything).Return(nil, repository.ErrOrderNotFound)
+	_, err = svc.GetOrder(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, service.ErrOrderNotFound)
+}
+
+func TestListUserOrders_Unit_EmptyPage(t *testing.T) {
+	svc, m := newOrderServiceWithMocks(t)
+	userID := uuid.New()
+
+	m.orderRepo.EXPECT().FindByUserID(mock.Anything, userID, 20, 10).Return(nil, 20, nil)
+
+	page, err := svc.ListUserOrders(context.Background(), userID, 20, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 20, page.Total)
+	assert.NotNil(t, page.Orders) // Encoded as an empty list
+	assert.Empty(t, page.Orders)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"product-api/internal/domain"
+	"product-api/internal/notification"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrPhoneVerificationNotFound is returned when a code is entered without a pending phone verification.
+	ErrPhoneVerificationNotFound = errors.New("no pending phone verification, request a new code")
+	// ErrPhoneCodeInvalid is returned when the entered verification code does not match the sent one.
+	ErrPhoneCodeInvalid = errors.New("invalid verification code")
+	// ErrPhoneCodeExpired is returned when the verification code expired or too many wrong codes were entered.
+	ErrPhoneCodeExpired = errors.New("verification code expired, request a new code")
+	// ErrPhoneCodeTooSoon is returned when a new code is requested shortly after the last one was sent.
+	ErrPhoneCodeTooSoon = errors.New("a verification code was sent recently, retry later")
+	// ErrPhoneCountryUnsupported is returned when text messages cannot be sent to the country of the phone number.
+	ErrPhoneCountryUnsupported = errors.New("text messages cannot be sent to this country")
+)
+
+const (
+	phoneCodeTTL            = 10 * time.Minute // Validity of verification codes
+	phoneCodeResendInterval = time.Minute      // Minimum time between codes sent to a user, limits SMS costs
+	maxPhoneCodeAttempts    = 5                // Wrong codes accepted before a new code must be requested
+)
+
+// PhoneService manages phone numbers users receive SMS notifications on.
+// A number is stored on the user only once the user enters the code sent to it.
+type PhoneService struct {
+	users         repository.UserRepository
+	verifications repository.PhoneVerificationRepository
+	templates     *notification.Templates
+	sms           notification.Sender
+}
+
+// NewPhoneService creates a new phone service sending verification codes through the sms sender.
+func NewPhoneService(users repository.UserRepository, verifications repository.PhoneVerificationRepository, templates *notification.Templates, sms notification.Sender) *PhoneService {
+	return &PhoneService{users: users, verifications: verifications, templates: templates, sms: sms}
+}
+
+// RequestVerification sends a verification code to the phone number, replacing a pending verification of the user.
+// The user's current number, if any, is kept until the new one is verified.
+// Returns domain.ErrPhoneInvalid, ErrUserNotFound, ErrPhoneCodeTooSoon and ErrPhoneCountryUnsupported.
+func (s *PhoneService) RequestVerification(ctx context.Context, userID uuid.UUID, phone string) (*domain.PhoneVerification, error) {
+	const op = "PhoneService.RequestVerification"
+
+	normalized, err := domain.NormalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pending, err := s.verifications.FindByUserID(ctx, userID)
+	switch {
+	case err == nil:
+		if time.Since(pending.CreatedAt) < phoneCodeResendInterval {
+			return nil, ErrPhoneCodeTooSoon
+		}
+	case !errors.Is(err, repository.ErrPhoneVerificationNotFound):
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	code, err := phoneCode()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	now := time.Now()
+	verification := &domain.PhoneVerification{
+		UserID:    userID,
+		Phone:     normalized,
+		CodeHash:  hashPhoneCode(code),
+		ExpiresAt: now.Add(phoneCodeTTL),
+		CreatedAt: now,
+	}
+	if err := s.verifications.Upsert(ctx, verification); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	msg, err := s.templates.Render(ctx, notification.TemplatePhoneVerification, user.Locale, user, map[string]string{
+		"Code":      code,
+		"ExpiresIn": "10 minutes",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	// The code is sent to the number being verified, regardless of notification preferences
+	recipient := *user
+	recipient.Phone = normalized
+	if err := s.sms.Send(ctx, &recipient, msg); err != nil {
+		// Undelivered codes do not count towards the resend interval
+		_ = s.verifications.Delete(ctx, userID)
+		if errors.Is(err, notification.ErrNoSMSSender) {
+			return nil, ErrPhoneCountryUnsupported
+		}
+		return nil, fmt.Errorf("%s: send code: %w", op, err)
+	}
+	return verification, nil
+}
+
+// Verify checks the code sent to the pending phone number and stores the number on the user.
+// Returns the updated user, ErrPhoneVerificationNotFound, ErrPhoneCodeExpired and ErrPhoneCodeInvalid.
+func (s *PhoneService) Verify(ctx context.Context, userID uuid.UUID, code string) (*domain.User, error) {
+	const op = "PhoneService.Verify"
+
+	verification, err := s.verifications.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrPhoneVerificationNotFound) {
+			return nil, ErrPhoneVerificationNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if time.Now().After(verification.ExpiresAt) || verification.Attempts >= maxPhoneCodeAttempts {
+		return nil, ErrPhoneCodeExpired
+	}
+	if subtle.ConstantTimeCompare([]byte(hashPhoneCode(code)), []byte(verification.CodeHash)) != 1 {
+		if err := s.verifications.IncrementAttempts(ctx, userID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return nil, ErrPhoneCodeInvalid
+	}
+
+	now := time.Now()
+	if err := s.users.UpdatePhone(ctx, userID, verification.Phone, &now); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := s.verifications.Delete(ctx, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
+
+// Remove removes the user's phone number and pending verification, the user receives no more SMS.
+// Returns ErrUserNotFound.
+func (s *PhoneService) Remove(ctx context.Context, userID uuid.UUID) error {
+	const op = "PhoneService.Remove"
+
+	if err := s.verifications.Delete(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := s.users.UpdatePhone(ctx, userID, "", nil); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// phoneCode generates a random 6-digit verification code.
+func phoneCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// hashPhoneCode returns the hex-encoded SHA-256 hash of a verification code.
+func hashPhoneCode(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/notification"
+	notificationmocks "product-api/internal/notification/mocks"
+	"product-api/internal/repository"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type phoneServiceMocks struct {
+	users         *mocks.MockUserRepository
+	verifications *mocks.MockPhoneVerificationRepository
+	sms           *notificationmocks.MockSender
+}
+
+func newPhoneServiceWithMocks(t *testing.T) (*service.PhoneService, *phoneServiceMocks) {
+	m := &phoneServiceMocks{
+		users:         mocks.NewMockUserRepository(t),
+		verifications: mocks.NewMockPhoneVerificationRepository(t),
+		sms:           notificationmocks.NewMockSender(t),
+	}
+	templateRepo := mocks.NewMockNotificationTemplateRepository(t)
+	templateRepo.EXPECT().FindLatest(mock.Anything).Return(nil, nil).Maybe()
+	templates := notification.NewTemplates(templateRepo, time.Hour, discardLogger{})
+	return service.NewPhoneService(m.users, m.verifications, templates, m.sms), m
+}
+
+func TestPhoneService_Unit_VerifiesSentCode(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newPhoneServiceWithMocks(t)
+	user := &domain.User{ID: uuid.New(), Firstname: "Ada", Phone: "+15550100199"}
+
+	m.users.EXPECT().FindByID(mock.Anything, user.ID).Return(user, nil).Once()
+	m.verifications.EXPECT().FindByUserID(mock.Anything, user.ID).Return(nil, repository.ErrPhoneVerificationNotFound).Once()
+	var stored *domain.PhoneVerification
+	m.verifications.EXPECT().Upsert(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, v *domain.PhoneVerification) { stored = v }).Return(nil).Once()
+	var code string
+	m.sms.EXPECT().Send(mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		return u.ID == user.ID && u.Phone == "+442079460958"
+	}), mock.Anything).Run(func(_ context.Context, _ *domain.User, msg notification.Message) {
+		code = regexp.MustCompile(`\d{6}`).FindString(msg.Body)
+	}).Return(nil).Once()
+
+	verification, err := svc.RequestVerification(ctx, user.ID, "+44 20 7946 0958")
+	require.NoError(t, err)
+	assert.Equal(t, "+442079460958", verification.Phone)
+	require.Len(t, code, 6, "code is sent to the new number")
+	assert.NotContains(t, stored.CodeHash, code, "codes are stored hashed")
+	assert.Equal(t, "+15550100199", user.Phone, "current number is kept until the new one is verified")
+
+	// Wrong codes count as attempts
+	m.verifications.EXPECT().FindByUserID(mock.Anything, user.ID).Return(stored, nil)
+	m.verifications.EXPECT().IncrementAttempts(mock.Anything, user.ID).Return(nil).Once()
+	_, err = svc.Verify(ctx, user.ID, "abcdef")
+	assert.ErrorIs(t, err, service.ErrPhoneCodeInvalid)
+
+	m.users.EXPECT().UpdatePhone(mock.Anything, user.ID, "+442079460958", mock.AnythingOfType("*time.Time")).Return(nil).Once()
+	m.verifications.EXPECT().Delete(mock.Anything, user.ID).Return(nil).Once()
+	m.users.EXPECT().FindByID(mock.Anything, user.ID).Return(user, nil).Once()
+	_, err = svc.Verify(ctx, user.ID, code)
+	require.NoError(t, err)
+}
+
+func TestPhoneService_Unit_LimitsCodes(t *testing.T) {
+	ctx := context.Background()
+	svc, m := newPhoneServiceWithMocks(t)
+	userID := uuid.New()
+
+	_, err := svc.RequestVerification(ctx, userID, "12345")
+	assert.ErrorIs(t, err, domain.ErrPhoneInvalid)
+
+	// A code sent less than a minute ago is not resent
+	m.users.EXPECT().FindByID(mock.Anything, userID).Return(&domain.User{ID: userID}, nil).Once()
+	m.verifications.EXPECT().FindByUserID(mock.Anything, userID).
+		Return(&domain.PhoneVerification{UserID: userID, CreatedAt: time.Now().Add(-30 * time.Second)}, nil).Once()
+	_, err = svc.RequestVerification(ctx, userID, "+442079460958")
+	assert.ErrorIs(t, err, service.ErrPhoneCodeTooSoon)
+
+	m.verifications.EXPECT().FindByUserID(mock.Anything, userID).
+		Return(&domain.PhoneVerification{UserID: userID, ExpiresAt: time.Now().Add(-time.Second)}, nil).Once()
+	_, err = svc.Verify(ctx, userID, "123456")
+	assert.ErrorIs(t, err, service.ErrPhoneCodeExpired)
+
+	m.verifications.EXPECT().FindByUserID(mock.Anything, userID).
+		Return(&domain.PhoneVerification{UserID: userID, Attempts: 5, ExpiresAt: time.Now().Add(time.Minute)}, nil).Once()
+	_, err = svc.Verify(ctx, userID, "123456")
+	assert.ErrorIs(t, err, service.ErrPhoneCodeExpired, "too many wrong codes")
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidPreference is returned when a notification preference refers to an unknown category or channel.
+	ErrInvalidPreference = errors.New("unknown notification category or channel")
+)
+
+// PreferenceService provides business logic for user notification preferences.
+type PreferenceService struct {
+	repo repository.PreferenceRepository
+}
+
+// NewPreferenceService creates a new notification preference service.
+func NewPreferenceService(repo repository.PreferenceRepository) *PreferenceService {
+	return &PreferenceService{repo: repo}
+}
+
+// Get returns the user's effective notification preferences:
+// defaults overridden by the values the user has explicitly set.
+func (s *PreferenceService) Get(ctx context.Context, userID uuid.UUID) (domain.NotificationPreferences, error) {
+	stored, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.DefaultNotificationPreferences().Merge(stored), nil
+}
+
+// Update stores the given preferences (partial updates are allowed)
+// and returns the resulting effective preferences.
+// Returns ErrInvalidPreference for unknown categories or channels.
+func (s *PreferenceService) Update(ctx context.Context, userID uuid.UUID, prefs domain.NotificationPreferences) (domain.NotificationPreferences, error) {
+	for category, channels := range prefs {
+		for channel := range channels {
+			if !domain.IsValidNotificationPreference(category, channel) {
+				return nil, fmt.Errorf("%w: %s/%s", ErrInvalidPreference, category, channel)
+			}
+		}
+	}
+
+	if err := s.repo.Upsert(ctx, userID, prefs); err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, userID)
+}
@@ -3,46 +3,202 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"product-api/internal/domain"
 	"product-api/internal/repository"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 var (
 	// ErrProductNotFound is returned when product is not found in the database.
 	ErrProductNotFound = errors.New("product not found")
+	// ErrBarcodeTaken is returned when another product already has the barcode.
+	ErrBarcodeTaken = errors.New("barcode already taken")
+	// ErrInvalidPriceRange is returned when a price filter is negative or its minimum exceeds its maximum.
+	ErrInvalidPriceRange = errors.New("invalid price range")
+	// ErrInvalidIssueRule is returned when catalog issues are requested for an unknown rule.
+	ErrInvalidIssueRule = errors.New("unknown catalog issue rule")
+	// ErrProductInUse is returned when deleting a product referenced by orders or bundles.
+	// Such products keep order history intact, they can only be archived.
+	ErrProductInUse = errors.New("product is referenced by orders or bundles, archive it instead")
 )
 
 // ProductService provides business logic for product operations.
+// Edits of product details are recorded in the product history.
+// Attributes are validated against the attribute definitions of the product category when they are set.
 type ProductService struct {
-	repo repository.ProductRepository
+	repo       repository.ProductRepository
+	revisions  repository.ProductRevisionRepository
+	attributes repository.AttributeRepository
+	db         repository.TxBeginner
 }
 
 // NewProductService creates a new product service.
-func NewProductService(repo repository.ProductRepository) *ProductService {
-	return &ProductService{repo: repo}
+func NewProductService(db repository.TxBeginner, repo repository.ProductRepository, revisions repository.ProductRevisionRepository, attributes repository.AttributeRepository) *ProductService {
+	return &ProductService{db: db, repo: repo, revisions: revisions, attributes: attributes}
+}
+
+// CreateProductInput contains data for creating a product.
+type CreateProductInput struct {
+	Description    string
+	Tags           []string
+	Quantity       int
+	Price          float64
+	AgeRestriction int    // Minimum buyer age, 0 for unrestricted products
+	Status         string // draft or active, empty for active
+	Barcode        string // Optional EAN-8, UPC-A, EAN-13 or GTIN-14 code
+	Category       string
+	Attributes     map[string]any
 }
 
 // CreateProduct creates a new product in the database.
-func (s *ProductService) CreateProduct(ctx context.Context, description string, tags []string, quantity int, price float64) (*domain.Product, error) {
+// Returns domain.ErrInvalidProductTransition for statuses other than draft or active,
+// domain.ErrInvalidBarcode and ErrBarcodeTaken if the barcode is invalid or used by another product
+// and domain.ErrInvalidAttributes if the attributes do not match the definitions of the category.
+func (s *ProductService) CreateProduct(ctx context.Context, in CreateProductInput) (*domain.Product, error) {
+	status, err := initialStatus(in.Status)
+	if err != nil {
+		return nil, err
+	}
+	barcode, err := optionalBarcode(in.Barcode)
+	if err != nil {
+		return nil, err
+	}
+
 	product := &domain.Product{
-		ID:          uuid.New(),
-		Description: description,
-		Tags:        tags,
-		Quantity:    quantity,
-		Price:       price,
+		ID:             uuid.New(),
+		Description:    in.Description,
+		Tags:           in.Tags,
+		Quantity:       in.Quantity,
+		Price:          in.Price,
+		AgeRestriction: in.AgeRestriction,
+		Status:         status,
+		Barcode:        barcode,
+		Category:       in.Category,
+		Attributes:     in.Attributes,
+	}
+	if err := s.validateAttributes(ctx, product, nil); err != nil {
+		return nil, err
 	}
 
 	if err := s.repo.Create(ctx, product); err != nil {
+		if errors.Is(err, repository.ErrBarcodeTaken) {
+			return nil, ErrBarcodeTaken
+		}
 		return nil, err
 	}
 
 	return product, nil
 }
 
-// GetProductByID retrieves a product by its ID.
-// Returns ErrProductNotFound if product is not found.
+// CreateBundleInput contains data for creating a bundle product.
+type CreateBundleInput struct {
+	Description    string
+	Tags           []string
+	Price          float64 // Price of the whole bundle
+	AgeRestriction int
+	Status         string // draft or active, empty for active
+	Barcode        string
+	Category       string
+	Attributes     map[string]any
+	Components     []domain.BundleComponent
+}
+
+// CreateBundle creates a product composed of existing products. Bundles have no stock of their own.
+// Returns domain.ErrInvalidBundle if a component is unknown, repeated or a bundle itself.
+func (s *ProductService) CreateBundle(ctx context.Context, in CreateBundleInput) (*domain.Product, error) {
+	const op = "ProductService.CreateBundle"
+
+	status, err := initialStatus(in.Status)
+	if err != nil {
+		return nil, err
+	}
+	barcode, err := optionalBarcode(in.Barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(in.Components))
+	for i, c := range in.Components {
+		ids[i] = c.ProductID
+	}
+	found, err := s.repo.FindByIDs(ctx, ids)
+	if err != nil && !errors.Is(err, repository.ErrProductNotFound) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	components := make(map[uuid.UUID]*domain.Product, len(found))
+	for i := range found {
+		components[found[i].ID] = &found[i]
+	}
+	if err := domain.ValidateComponents(in.Components, components); err != nil {
+		return nil, err
+	}
+
+	bundle := &domain.Product{
+		ID:             uuid.New(),
+		Description:    in.Description,
+		Tags:           in.Tags,
+		Price:          domain.RoundCents(in.Price),
+		AgeRestriction: in.AgeRestriction,
+		Status:         status,
+		Barcode:        barcode,
+		Category:       in.Category,
+		Attributes:     in.Attributes,
+		Components:     in.Components,
+	}
+	if err := s.validateAttributes(ctx, bundle, nil); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, bundle); err != nil {
+		if errors.Is(err, repository.ErrBarcodeTaken) {
+			return nil, ErrBarcodeTaken
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return bundle, nil
+}
+
+// initialStatus returns the status of a new product: draft or active, active if empty.
+func initialStatus(status string) (string, error) {
+	switch status {
+	case "":
+		return domain.ProductStatusActive, nil
+	case domain.ProductStatusDraft, domain.ProductStatusActive:
+		return status, nil
+	}
+	return "", fmt.Errorf("%w: products cannot be created as %q", domain.ErrInvalidProductTransition, status)
+}
+
+// validateAttributes checks product attributes against the definitions of its category.
+// Definitions are looked up once per category and kept in cache, if it is not nil.
+func (s *ProductService) validateAttributes(ctx context.Context, product *domain.Product, cache map[string][]domain.AttributeDefinition) error {
+	definitions, ok := cache[product.Category]
+	if !ok && product.Category != "" {
+		var err error
+		if definitions, err = s.attributes.FindByCategory(ctx, product.Category); err != nil {
+			return fmt.Errorf("find attribute definitions of %q: %w", product.Category, err)
+		}
+		if cache != nil {
+			cache[product.Category] = definitions
+		}
+	}
+	return domain.ValidateAttributes(product.Attributes, definitions)
+}
+
+// optionalBarcode normalizes the barcode of a new product, if it has one.
+func optionalBarcode(barcode string) (string, error) {
+	if barcode == "" {
+		return "", nil
+	}
+	return domain.NormalizeBarcode(barcode)
+}
+
+// GetProductByID retrieves a product visible in the storefront by its ID.
+// Returns ErrProductNotFound if product is not found or is a draft.
 func (s *ProductService) GetProductByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
 	product, err := s.repo.FindByID(ctx, id)
 	if err != nil {
@@ -51,5 +207,454 @@ func (s *ProductService) GetProductByID(ctx context.Context, id uuid.UUID) (*dom
 		}
 		return nil, err
 	}
+	if !product.IsVisible() {
+		return nil, ErrProductNotFound
+	}
 	return product, nil
 }
+
+// ProductListInput contains criteria of a catalog page. Zero fields are ignored.
+type ProductListInput struct {
+	Tags     []string // Products having all the tags
+	MinPrice *float64
+	MaxPrice *float64
+	InStock  bool // Products that can be ordered from stock
+	Offset   int
+	Limit    int
+}
+
+// ProductPage is a page of the catalog.
+type ProductPage struct {
+	Products []domain.Product // Newest first
+	Total    int              // Number of products matching the criteria
+}
+
+// ListProducts returns a page of active products matching the criteria, so clients can browse the catalog.
+// Drafts and archived products are not listed.
+// Returns ErrInvalidPriceRange.
+func (s *ProductService) ListProducts(ctx context.Context, in ProductListInput) (*ProductPage, error) {
+	if (in.MinPrice != nil && *in.MinPrice < 0) || (in.MaxPrice != nil && *in.MaxPrice < 0) ||
+		(in.MinPrice != nil && in.MaxPrice != nil && *in.MinPrice > *in.MaxPrice) {
+		return nil, ErrInvalidPriceRange
+	}
+
+	products, total, err := s.repo.List(ctx, repository.ProductFilter{
+		Status:   domain.ProductStatusActive,
+		Tags:     in.Tags,
+		MinPrice: in.MinPrice,
+		MaxPrice: in.MaxPrice,
+		InStock:  in.InStock,
+	}, in.Offset, in.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("ProductService.ListProducts: %w", err)
+	}
+	if products == nil {
+		products = []domain.Product{}
+	}
+	return &ProductPage{Products: products, Total: total}, nil
+}
+
+// GetProductByBarcode retrieves a product visible in the storefront by its barcode, e.g. for warehouse scanners.
+// Returns domain.ErrInvalidBarcode if the code is not a valid barcode
+// and ErrProductNotFound if no visible product has it.
+func (s *ProductService) GetProductByBarcode(ctx context.Context, code string) (*domain.Product, error) {
+	barcode, err := domain.NormalizeBarcode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.repo.FindByBarcode(ctx, barcode)
+	if err != nil {
+		if errors.Is(err, repository.ErrProductNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("ProductService.GetProductByBarcode: %w", err)
+	}
+	if !product.IsVisible() {
+		return nil, ErrProductNotFound
+	}
+	return product, nil
+}
+
+// ChangeStatus publishes, archives or restores the product on behalf of actor.
+// Returns ErrProductNotFound if product is not found
+// and domain.ErrInvalidProductTransition if the product cannot move to the status.
+func (s *ProductService) ChangeStatus(ctx context.Context, actor string, id uuid.UUID, status string) (_ *domain.Product, err error) {
+	const op = "ProductService.ChangeStatus"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not begin transaction: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	product, err := s.repo.FindByIDTx(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrProductNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	before := *product
+	if err = product.Apply(domain.ProductChange{Status: &status}); err != nil {
+		return nil, err
+	}
+	if err = s.repo.UpdateTx(ctx, tx, product); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = s.recordRevisions(ctx, tx, actor, []domain.Product{before}, []*domain.Product{product}); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: could not commit transaction: %w", op, err)
+	}
+	return product, nil
+}
+
+// Bulk product update item results.
+const (
+	BulkItemUpdated  = "updated"
+	BulkItemFailed   = "failed"
+	BulkItemRejected = "rejected" // Valid, but not applied because another item failed in atomic mode
+)
+
+// ProductChangeInput is a change of a single product in a bulk update.
+type ProductChangeInput struct {
+	ID     uuid.UUID
+	Change domain.ProductChange
+}
+
+// BulkUpdateResult is the outcome of a single item of a bulk update, in request order.
+type BulkUpdateResult struct {
+	ID      uuid.UUID
+	Status  string          // updated, failed or rejected
+	Error   string          `json:",omitempty"` // Reason of the failure
+	Product *domain.Product `json:",omitempty"` // Product after the change, set for updated items
+}
+
+// BulkUpdateReport contains per-item results of a bulk update.
+type BulkUpdateReport struct {
+	Results []BulkUpdateResult
+	Updated int
+	Failed  int
+}
+
+// BulkUpdate applies changes to many products in a single transaction.
+// Items of unknown products or with invalid changes fail; other items are applied.
+// If atomic is true, no item is applied when any item fails.
+// Several changes of the same product are applied in order.
+// Attributes are validated only for changes of the category or attributes, so existing products stay editable
+// after their attribute definitions change.
+// Returns ErrBarcodeTaken and applies nothing if a changed barcode is used by another product.
+func (s *ProductService) BulkUpdate(ctx context.Context, actor string, changes []ProductChangeInput, atomic bool) (_ *BulkUpdateReport, err error) {
+	const op = "ProductService.BulkUpdate"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not begin transaction: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	ids := make([]uuid.UUID, len(changes))
+	for i, c := range changes {
+		ids[i] = c.ID
+	}
+	found, err := s.repo.FindByIDsTx(ctx, tx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	products := make(map[uuid.UUID]*domain.Product, len(found))
+	originals := make(map[uuid.UUID]domain.Product, len(found))
+	for i := range found {
+		original := found[i]
+		original.Tags = slices.Clone(original.Tags)
+		originals[found[i].ID] = original
+		products[found[i].ID] = &found[i]
+	}
+
+	// Apply changes in memory first, so every product is written once
+	report := &BulkUpdateReport{Results: make([]BulkUpdateResult, len(changes))}
+	changed := map[uuid.UUID]bool{}
+	definitions := map[string][]domain.AttributeDefinition{}
+	for i, c := range changes {
+		result := &report.Results[i]
+		result.ID = c.ID
+
+		product, ok := products[c.ID]
+		if !ok {
+			result.Status, result.Error = BulkItemFailed, ErrProductNotFound.Error()
+			report.Failed++
+			continue
+		}
+		previous := *product
+		previous.Tags = slices.Clone(product.Tags)
+		if err := product.Apply(c.Change); err != nil {
+			result.Status, result.Error = BulkItemFailed, err.Error()
+			report.Failed++
+			continue
+		}
+		if c.Change.Category != nil || len(c.Change.Attributes) > 0 {
+			if err := s.validateAttributes(ctx, product, definitions); err != nil {
+				if !errors.Is(err, domain.ErrInvalidAttributes) {
+					return nil, fmt.Errorf("%s: %w", op, err)
+				}
+				*product = previous
+				result.Status, result.Error = BulkItemFailed, err.Error()
+				report.Failed++
+				continue
+			}
+		}
+		result.Status = BulkItemUpdated
+		changed[c.ID] = true
+		report.Updated++
+	}
+
+	if atomic && report.Failed > 0 {
+		for i := range report.Results {
+			if report.Results[i].Status == BulkItemUpdated {
+				report.Results[i].Status = BulkItemRejected
+			}
+		}
+		report.Updated = 0
+		if err := tx.Rollback(ctx); err != nil {
+			return nil, fmt.Errorf("%s: could not roll back transaction: %w", op, err)
+		}
+		return report, nil
+	}
+
+	var before []domain.Product
+	var after []*domain.Product
+	for id := range changed {
+		if err = s.repo.UpdateTx(ctx, tx, products[id]); err != nil {
+			if errors.Is(err, repository.ErrBarcodeTaken) {
+				return nil, fmt.Errorf("%w: product %s", ErrBarcodeTaken, id)
+			}
+			return nil, fmt.Errorf("%s: update product %s: %w", op, id, err)
+		}
+		before, after = append(before, originals[id]), append(after, products[id])
+	}
+	if err = s.recordRevisions(ctx, tx, actor, before, after); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: could not commit transaction: %w", op, err)
+	}
+
+	for i := range report.Results {
+		if report.Results[i].Status == BulkItemUpdated {
+			report.Results[i].Product = products[report.Results[i].ID]
+		}
+	}
+	return report, nil
+}
+
+// ProductChangeOutcome is the outcome of a change applied by ApplyTx.
+type ProductChangeOutcome struct {
+	Before domain.Product
+	After  *domain.Product // Product after the change, nil if it failed
+	Err    error           // Reason the change failed
+}
+
+// ApplyTx locks the products of ids within tx and applies the change returned by change for each of them,
+// recording the revisions as actor. Unknown products are skipped, invalid changes fail without failing the others.
+// Returns the outcomes in ID order, and ErrBarcodeTaken if a changed barcode is used by another product.
+func (s *ProductService) ApplyTx(ctx context.Context, tx pgx.Tx, actor string, ids []uuid.UUID, change func(*domain.Product) domain.ProductChange) ([]ProductChangeOutcome, error) {
+	const op = "ProductService.ApplyTx"
+
+	found, err := s.repo.FindByIDsTx(ctx, tx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	outcomes := make([]ProductChangeOutcome, len(found))
+	definitions := map[string][]domain.AttributeDefinition{}
+	var before []domain.Product
+	var after []*domain.Product
+	for i := range found {
+		product, outcome := &found[i], &outcomes[i]
+		outcome.Before = *product
+		outcome.Before.Tags = slices.Clone(product.Tags)
+
+		c := change(product)
+		if outcome.Err = product.Apply(c); outcome.Err != nil {
+			continue
+		}
+		if c.Category != nil || len(c.Attributes) > 0 {
+			if err := s.validateAttributes(ctx, product, definitions); err != nil {
+				if !errors.Is(err, domain.ErrInvalidAttributes) {
+					return nil, fmt.Errorf("%s: %w", op, err)
+				}
+				outcome.Err = err
+				continue
+			}
+		}
+		if err := s.repo.UpdateTx(ctx, tx, product); err != nil {
+			if errors.Is(err, repository.ErrBarcodeTaken) {
+				return nil, fmt.Errorf("%w: product %s", ErrBarcodeTaken, product.ID)
+			}
+			return nil, fmt.Errorf("%s: update product %s: %w", op, product.ID, err)
+		}
+		outcome.After = product
+		before, after = append(before, outcome.Before), append(after, product)
+	}
+	if err := s.recordRevisions(ctx, tx, actor, before, after); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return outcomes, nil
+}
+
+// Delete deletes the product with its stock ledger and history.
+// Returns ErrProductNotFound if product is not found
+// and ErrProductInUse if orders or bundles reference it.
+func (s *ProductService) Delete(ctx context.Context, id uuid.UUID) (err error) {
+	const op = "ProductService.Delete"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: could not begin transaction: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	// The row lock keeps orders from referencing the product until it is deleted
+	if _, err = s.repo.FindByIDTx(ctx, tx, id); err != nil {
+		if errors.Is(err, repository.ErrProductNotFound) {
+			return ErrProductNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err = s.repo.DeleteTx(ctx, tx, id); err != nil {
+		if errors.Is(err, repository.ErrProductInUse) {
+			return ErrProductInUse
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: could not commit transaction: %w", op, err)
+	}
+	return nil
+}
+
+// CleanupDrafts deletes up to limit drafts created more than olderThan ago that no bundle references,
+// oldest first, and returns their IDs. Drafts were never orderable, so no order references them.
+func (s *ProductService) CleanupDrafts(ctx context.Context, olderThan time.Duration, limit int) ([]uuid.UUID, error) {
+	ids, err := s.repo.DeleteOrphanedDrafts(ctx, time.Now().Add(-olderThan), limit)
+	if err != nil {
+		return nil, fmt.Errorf("ProductService.CleanupDrafts: %w", err)
+	}
+	return ids, nil
+}
+
+// CatalogIssueReport is a page of the data-quality issues of the catalog.
+type CatalogIssueReport struct {
+	Issues []domain.CatalogIssue // In product ID and rule order
+	Counts map[string]int        // Issues found by each requested rule
+	Total  int                   // Issues found by the requested rules
+}
+
+// Issues runs the catalog issue rules, only the given ones if rules is not empty, on draft and active products
+// and returns a page of the issues found, so merchandisers can fix the catalog. Returns ErrInvalidIssueRule.
+func (s *ProductService) Issues(ctx context.Context, rules []string, offset, limit int) (*CatalogIssueReport, error) {
+	for _, rule := range rules {
+		if !domain.IsValidCatalogIssueRule(rule) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidIssueRule, rule)
+		}
+	}
+
+	issues, counts, err := s.repo.FindIssues(ctx, rules, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ProductService.Issues: %w", err)
+	}
+	report := &CatalogIssueReport{Issues: issues, Counts: map[string]int{}}
+	if report.Issues == nil {
+		report.Issues = []domain.CatalogIssue{}
+	}
+	if len(rules) == 0 {
+		rules = domain.CatalogIssueRules
+	}
+	for _, rule := range rules {
+		if _, seen := report.Counts[rule]; !seen {
+			report.Counts[rule] = counts[rule]
+			report.Total += counts[rule]
+		}
+	}
+	return report, nil
+}
+
+// History returns the most recent edits of the product, newest first.
+func (s *ProductService) History(ctx context.Context, id uuid.UUID, limit int) ([]domain.ProductRevision, error) {
+	revisions, err := s.revisions.FindByProductID(ctx, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ProductService.History: %w", err)
+	}
+	return revisions, nil
+}
+
+// PriceAt returns the price the product had at a point in time, resolved from the history of its price edits.
+// Returns ErrProductNotFound if product is not found.
+func (s *ProductService) PriceAt(ctx context.Context, id uuid.UUID, at time.Time) (*domain.EffectivePrice, error) {
+	const op = "ProductService.PriceAt"
+
+	product, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrProductNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	revisions, err := s.revisions.FindByField(ctx, id, domain.ProductFieldPrice)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	price, ok := domain.PriceAt(id, product.Price, revisions, at)
+	if !ok {
+		return nil, fmt.Errorf("%s: price history of product %s holds a non-numeric price", op, id)
+	}
+	return &price, nil
+}
+
+// Export calls fn for every product of the catalog, including drafts and archived products, in ID order.
+// The export starts after the product with the cursor ID, or at the beginning for uuid.Nil,
+// so an interrupted export can be resumed from the last product received.
+// All products are read from the same database snapshot.
+func (s *ProductService) Export(ctx context.Context, cursor uuid.UUID, fn func(*domain.Product) error) error {
+	if err := s.repo.Export(ctx, cursor, fn); err != nil {
+		return fmt.Errorf("ProductService.Export: %w", err)
+	}
+	return nil
+}
+
+// recordRevisions appends field diffs of the edited products to their history. Unchanged products are skipped.
+func (s *ProductService) recordRevisions(ctx context.Context, tx pgx.Tx, actor string, before []domain.Product, after []*domain.Product) error {
+	now := time.Now()
+	var revisions []domain.ProductRevision
+	for i := range before {
+		diffs := domain.DiffProducts(&before[i], after[i])
+		if len(diffs) == 0 {
+			continue
+		}
+		revisions = append(revisions, domain.ProductRevision{
+			ID:        uuid.New(),
+			ProductID: after[i].ID,
+			Actor:     actor,
+			ChangedAt: now,
+			Changes:   diffs,
+		})
+	}
+	if err := s.revisions.AppendTx(ctx, tx, revisions); err != nil {
+		return fmt.Errorf("record product history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"product-api/internal/repository/postgres"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"product-api/internal/testutil/testdb"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProductExportTestSuite struct {
+	suite.Suite
+	productRepo repository.ProductRepository
+	service     *service.ProductService
+}
+
+func (s *ProductExportTestSuite) SetupTest() {
+	dbpool := testdb.New(s.T())
+	s.productRepo = postgres.NewProductRepository(dbpool)
+	s.service = service.NewProductService(dbpool, s.productRepo, postgres.NewProductRevisionRepository(dbpool), postgres.NewAttributeRepository(dbpool))
+}
+
+// export returns the IDs of the products exported after the cursor.
+func (s *ProductExportTestSuite) export(cursor uuid.UUID) []uuid.UUID {
+	var ids []uuid.UUID
+	s.Require().NoError(s.service.Export(context.Background(), cursor, func(p *domain.Product) error {
+		ids = append(ids, p.ID)
+		return nil
+	}))
+	return ids
+}
+
+func (s *ProductExportTestSuite) TestExport() {
+	var ids []uuid.UUID
+	for _, status := range []string{domain.ProductStatusActive, domain.ProductStatusDraft, domain.ProductStatusArchived, domain.ProductStatusActive} {
+		ids = append(ids, factory.CreateProduct(s.T(), s.productRepo, factory.WithStatus(status)).ID)
+	}
+	slices.SortFunc(ids, compareIDs)
+
+	s.Equal(ids, s.export(uuid.Nil), "all products in ID order, including drafts and archived products")
+	s.Equal(ids[2:], s.export(ids[1]), "products after the cursor")
+	s.Empty(s.export(ids[3]))
+}
+
+func (s *ProductExportTestSuite) TestExportStopsOnError() {
+	for range 3 {
+		factory.CreateProduct(s.T(), s.productRepo)
+	}
+
+	exported := 0
+	err := s.service.Export(context.Background(), uuid.Nil, func(*domain.Product) error {
+		exported++
+		return assert.AnError
+	})
+	s.ErrorIs(err, assert.AnError)
+	s.Equal(1, exported)
+}
+
+func TestProductExportTestSuite(t *testing.T) {
+	suite.Run(t, new(ProductExportTestSuite))
+}
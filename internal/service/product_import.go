@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidProductImport is returned when a product import file cannot be read.
+	// Invalid rows do not fail the import, they are reported.
+	ErrInvalidProductImport = errors.New("invalid product import")
+)
+
+// Product import row statuses.
+const (
+	ProductImportCreated = "created"
+	ProductImportFailed  = "failed"
+)
+
+const (
+	// MaxProductImportRows is the maximum number of rows of a product import file.
+	MaxProductImportRows = 100_000
+	// productImportBatchSize is the number of products created in one transaction.
+	productImportBatchSize = 1000
+	// maxImportedPrice is the exclusive upper bound of prices, as stored with 10 digits and 2 decimals.
+	maxImportedPrice = 100_000_000
+	// productImportTagSeparator separates the tags of a product within the tags column.
+	productImportTagSeparator = "|"
+)
+
+// ProductImportResult is the outcome of a row of a product import, in file order.
+type ProductImportResult struct {
+	Line      int        // Line in the file, starting at 1 with the header
+	Barcode   string     `json:",omitempty"`
+	ProductID *uuid.UUID `json:",omitempty"` // Set for created products
+	Status    string     // created or failed
+	Error     string     `json:",omitempty"` // Reason of the failure
+}
+
+// ProductImportReport lists the products created by an import and the rows rejected.
+type ProductImportReport struct {
+	Created int
+	Failed  int
+	// Line the import failed at: the row that could not be read, or the first row of the batch that could not
+	// be created. Rows of the batch are reported as failed, later rows were not read. 0 if the import completed.
+	StoppedAt int `json:",omitempty"`
+	Results   []ProductImportResult
+}
+
+// productImportColumns are the indexes of the columns named by the header of a product import file, -1 if missing.
+type productImportColumns struct {
+	description, price, quantity, tags, ageRestriction, status, barcode, category, attributes int
+}
+
+// productImport is an import in progress, with the rows of the next batch.
+type productImport struct {
+	report     *ProductImportReport
+	barcodes   map[string]bool // Barcodes of earlier rows, repeated ones fail
+	attributes map[string][]domain.AttributeDefinition
+	batch      []domain.Product
+	results    []int // Indexes of the results of the batch products
+}
+
+// ImportProducts creates the products of a CSV file as it is read, in batches of separate transactions.
+// The header names the columns in any order: "description" and "price" are required, "quantity", "tags"
+// (separated by "|"), "age_restriction", "status" (draft or active), "barcode", "category" and "attributes"
+// (a JSON object) are optional, other columns are ignored. Invalid rows and barcodes repeated in the file
+// or already taken fail without affecting other rows, and are reported.
+// If a batch fails or the file turns out to be invalid, the error is returned with the report of the rows read,
+// whose StoppedAt is the failing line; earlier batches stay created. Returns ErrInvalidProductImport if the file
+// is not valid CSV, lacks a required column or has more than MaxProductImportRows rows.
+func (s *ProductService) ImportProducts(ctx context.Context, r io.Reader) (_ *ProductImportReport, err error) {
+	const op = "ProductService.ImportProducts"
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: empty file", ErrInvalidProductImport)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProductImport, err)
+	}
+	columns, err := parseProductImportHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &productImport{
+		report:     &ProductImportReport{},
+		barcodes:   make(map[string]bool),
+		attributes: make(map[string][]domain.AttributeDefinition),
+	}
+	line := 1 // Line of the last row read
+	batchFailed := false
+	defer func() {
+		if err != nil {
+			imp.stop(line, batchFailed)
+		}
+	}()
+	for rows := 0; ; rows++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			line++
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				line = parseErr.StartLine
+			}
+			return imp.report, fmt.Errorf("%w: %w", ErrInvalidProductImport, err)
+		}
+		line, _ = reader.FieldPos(0)
+		if rows == MaxProductImportRows {
+			return imp.report, fmt.Errorf("%w: more than %d rows", ErrInvalidProductImport, MaxProductImportRows)
+		}
+
+		if err := s.importRow(ctx, imp, columns, record, line); err != nil {
+			return imp.report, fmt.Errorf("%s: %w", op, err)
+		}
+		if len(imp.batch) == productImportBatchSize {
+			if err := s.importBatch(ctx, imp); err != nil {
+				batchFailed = true
+				return imp.report, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+	if err := s.importBatch(ctx, imp); err != nil {
+		batchFailed = true
+		return imp.report, fmt.Errorf("%s: %w", op, err)
+	}
+	return imp.report, nil
+}
+
+// parseProductImportHeader returns the indexes of the columns named by the header.
+func parseProductImportHeader(header []string) (productImportColumns, error) {
+	columns := productImportColumns{-1, -1, -1, -1, -1, -1, -1, -1, -1}
+	for i, name := range header {
+		// Spreadsheet applications may start files with a byte order mark
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) {
+		case "description":
+			columns.description = i
+		case "price":
+			columns.price = i
+		case "quantity":
+			columns.quantity = i
+		case "tags":
+			columns.tags = i
+		case "age_restriction":
+			columns.ageRestriction = i
+		case "status":
+			columns.status = i
+		case "barcode":
+			columns.barcode = i
+		case "category":
+			columns.category = i
+		case "attributes":
+			columns.attributes = i
+		}
+	}
+	if columns.description < 0 || columns.price < 0 {
+		return columns, fmt.Errorf("%w: header must name the description and price columns", ErrInvalidProductImport)
+	}
+	return columns, nil
+}
+
+// importRow validates a row and adds its product to the batch, or records why it failed.
+func (s *ProductService) importRow(ctx context.Context, imp *productImport, columns productImportColumns, record []string, line int) error {
+	field := func(i int) string {
+		if i < 0 {
+			return ""
+		}
+		return strings.TrimSpace(csvField(record, i))
+	}
+	imp.report.Results = append(imp.report.Results, ProductImportResult{Line: line, Barcode: field(columns.barcode)})
+	result := &imp.report.Results[len(imp.report.Results)-1]
+
+	product, reason := parseImportedProduct(field, columns)
+	if reason == "" && product.Barcode != "" && imp.barcodes[product.Barcode] {
+		reason = "barcode repeated in the file"
+	}
+	if reason == "" {
+		err := s.validateAttributes(ctx, product, imp.attributes)
+		switch {
+		case errors.Is(err, domain.ErrInvalidAttributes):
+			reason = err.Error()
+		case err != nil:
+			return err
+		}
+	}
+	if reason != "" {
+		result.Status, result.Error = ProductImportFailed, reason
+		imp.report.Failed++
+		return nil
+	}
+
+	if product.Barcode != "" {
+		result.Barcode = product.Barcode
+		imp.barcodes[product.Barcode] = true
+	}
+	imp.batch = append(imp.batch, *product)
+	imp.results = append(imp.results, len(imp.report.Results)-1)
+	return nil
+}
+
+// parseImportedProduct parses the fields of a row into a product, or returns why the row is invalid.
+// The limits are those of products created through the API, except that products may be imported out of stock.
+func parseImportedProduct(field func(int) string, columns productImportColumns) (*domain.Product, string) {
+	product := &domain.Product{
+		ID:          uuid.New(),
+		Description: field(columns.description),
+		Tags:        []string{},
+		Category:    field(columns.category),
+	}
+	if product.Description == "" {
+		return nil, "missing description"
+	}
+
+	price, err := strconv.ParseFloat(field(columns.price), 64)
+	if err != nil || price <= 0 || price >= maxImportedPrice {
+		return nil, "price must be a positive number below 100000000"
+	}
+	product.Price = price
+
+	if v := field(columns.quantity); v != "" {
+		if product.Quantity, err = strconv.Atoi(v); err != nil || product.Quantity < 0 {
+			return nil, "quantity must be a non-negative integer"
+		}
+	}
+	if v := field(columns.ageRestriction); v != "" {
+		if product.AgeRestriction, err = strconv.Atoi(v); err != nil || product.AgeRestriction < 0 || product.AgeRestriction > 99 {
+			return nil, "age_restriction must be an integer from 0 to 99"
+		}
+	}
+	for _, tag := range strings.Split(field(columns.tags), productImportTagSeparator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			product.Tags = append(product.Tags, tag)
+		}
+	}
+	if len(product.Category) > 100 {
+		return nil, "category must be at most 100 characters"
+	}
+	if v := field(columns.attributes); v != "" {
+		if err := json.Unmarshal([]byte(v), &product.Attributes); err != nil || product.Attributes == nil {
+			return nil, "attributes must be a JSON object"
+		}
+	}
+
+	if product.Status, err = initialStatus(field(columns.status)); err != nil {
+		return nil, err.Error()
+	}
+	if product.Barcode, err = optionalBarcode(field(columns.barcode)); err != nil {
+		return nil, err.Error()
+	}
+	return product, ""
+}
+
+// stop reports the rows of the batch in progress as failed after the import failed at the line, or at the
+// first row of the batch if the batch could not be created.
+func (imp *productImport) stop(line int, batchFailed bool) {
+	imp.report.StoppedAt = line
+	first := true
+	for i := range imp.report.Results {
+		result := &imp.report.Results[i]
+		if result.Status != "" {
+			continue
+		}
+		if batchFailed && first {
+			imp.report.StoppedAt = result.Line
+		}
+		first = false
+		result.Status, result.Error = ProductImportFailed, "not imported, the import stopped"
+		imp.report.Failed++
+	}
+}
+
+// importBatch creates the products of the batch whose barcode is not taken, records the results and empties the batch.
+func (s *ProductService) importBatch(ctx context.Context, imp *productImport) error {
+	if len(imp.batch) == 0 {
+		return nil
+	}
+
+	var barcodes []string
+	for _, p := range imp.batch {
+		if p.Barcode != "" {
+			barcodes = append(barcodes, p.Barcode)
+		}
+	}
+	taken := make(map[string]bool)
+	if len(barcodes) > 0 {
+		found, err := s.repo.FindTakenBarcodes(ctx, barcodes)
+		if err != nil {
+			return err
+		}
+		for _, barcode := range found {
+			taken[barcode] = true
+		}
+	}
+
+	products := make([]domain.Product, 0, len(imp.batch))
+	created := make([]int, 0, len(imp.batch))
+	for i, p := range imp.batch {
+		result := &imp.report.Results[imp.results[i]]
+		if taken[p.Barcode] {
+			result.Status, result.Error = ProductImportFailed, ErrBarcodeTaken.Error()
+			imp.report.Failed++
+			continue
+		}
+		products = append(products, p)
+		created = append(created, imp.results[i])
+	}
+	imp.batch, imp.results = imp.batch[:0], imp.results[:0]
+	if len(products) == 0 {
+		return nil
+	}
+
+	if err := s.repo.CreateMany(ctx, products); err != nil {
+		// A product created with one of the barcodes since they were looked up
+		if errors.Is(err, repository.ErrBarcodeTaken) {
+			return ErrBarcodeTaken
+		}
+		return err
+	}
+	for i, idx := range created {
+		result := &imp.report.Results[idx]
+		result.ProductID, result.Status = &products[i].ID, ProductImportCreated
+	}
+	imp.report.Created += len(products)
+	return nil
+}
@@ -0,0 +1,91 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/event"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"product-api/internal/repository/postgres"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"product-api/internal/testutil/testdb"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ProductImportTestSuite struct {
+	suite.Suite
+	productRepo repository.ProductRepository
+	stock       *service.StockService
+	service     *service.ProductService
+}
+
+func (s *ProductImportTestSuite) SetupTest() {
+	dbpool := testdb.New(s.T())
+	log := logger.NewSlogAdapter("local")
+	s.productRepo = postgres.NewProductRepository(dbpool)
+	s.stock = service.NewStockService(postgres.NewStockRepository(dbpool), event.NewBus(log), log)
+	s.service = service.NewProductService(dbpool, s.productRepo, postgres.NewProductRevisionRepository(dbpool), postgres.NewAttributeRepository(dbpool))
+}
+
+func (s *ProductImportTestSuite) TestImportProducts() {
+	ctx := context.Background()
+	factory.CreateProduct(s.T(), s.productRepo, factory.WithBarcode("96385074"))
+
+	report, err := s.service.ImportProducts(ctx, strings.NewReader(
+		"description,price,quantity,tags,status,barcode\n"+
+			"Wireless headphones,99.99,100,audio|wireless,active,4006381333931\n"+
+			"Cable,4.99,5,,draft,96385074\n"+
+			"Speaker,59.99,0,,,\n"))
+
+	s.Require().NoError(err)
+	s.Equal(2, report.Created)
+	s.Equal(1, report.Failed)
+	s.Zero(report.StoppedAt)
+	s.Equal(service.ErrBarcodeTaken.Error(), report.Results[1].Error)
+
+	headphones, err := s.productRepo.FindByID(ctx, *report.Results[0].ProductID)
+	s.Require().NoError(err)
+	s.Equal("Wireless headphones", headphones.Description)
+	s.Equal([]string{"audio", "wireless"}, headphones.Tags)
+	s.Equal(100, headphones.Quantity)
+	s.Equal(99.99, headphones.Price)
+	s.Equal(domain.ProductStatusActive, headphones.Status)
+	s.Equal("4006381333931", headphones.Barcode)
+
+	// The initial quantity is recorded as a stock receipt, products without stock get none
+	movements, err := s.stock.History(ctx, headphones.ID, 10)
+	s.Require().NoError(err)
+	s.Require().Len(movements, 1)
+	s.Equal(100, movements[0].Delta)
+	s.Equal(domain.StockReasonReceipt, movements[0].Reason)
+
+	speaker, err := s.productRepo.FindByID(ctx, *report.Results[2].ProductID)
+	s.Require().NoError(err)
+	s.Empty(speaker.Barcode)
+	movements, err = s.stock.History(ctx, speaker.ID, 10)
+	s.Require().NoError(err)
+	s.Empty(movements)
+}
+
+func (s *ProductImportTestSuite) TestCreateManyIsAtomic() {
+	ctx := context.Background()
+	factory.CreateProduct(s.T(), s.productRepo, factory.WithBarcode("96385074"))
+	products := []domain.Product{
+		*factory.NewProduct(factory.WithBarcode("4006381333931")),
+		*factory.NewProduct(factory.WithBarcode("96385074")),
+	}
+
+	err := s.productRepo.CreateMany(ctx, products)
+
+	s.ErrorIs(err, repository.ErrBarcodeTaken)
+	_, err = s.productRepo.FindByID(ctx, products[0].ID)
+	s.ErrorIs(err, repository.ErrProductNotFound)
+}
+
+func TestProductImportTestSuite(t *testing.T) {
+	suite.Run(t, new(ProductImportTestSuite))
+}
@@ -0,0 +1,108 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportProducts_Unit_ReportsRejectedRows(t *testing.T) {
+	repo := mocks.NewMockProductRepository(t)
+	attributes := mocks.NewMockAttributeRepository(t)
+	svc := service.NewProductService(mocks.NewMockTxBeginner(t), repo, mocks.NewMockProductRevisionRepository(t), attributes)
+
+	attributes.EXPECT().FindByCategory(mock.Anything, "headphones").Return([]domain.AttributeDefinition{
+		{Category: "headphones", Name: "wireless", Type: domain.AttributeTypeBoolean},
+	}, nil).Once()
+	repo.EXPECT().FindTakenBarcodes(mock.Anything, []string{"4006381333931", "96385074"}).Return([]string{"96385074"}, nil)
+	var created []domain.Product
+	repo.EXPECT().CreateMany(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, products []domain.Product) { created = products }).Return(nil)
+
+	report, err := svc.ImportProducts(context.Background(), strings.NewReader(
+		"\ufeffSKU Name,Description,Price,Quantity,Tags,Barcode,Category,Attributes\n"+
+			"x,Wireless headphones,99.99,100,audio | wireless,4006381333931,headphones,\"{\"\"wireless\"\": true}\"\n"+
+			"x,Headphones,49.99,0,audio,4006381333931,,\n"+
+			"x,Wired headphones,19.99,5,,96385074,headphones,\"{\"\"wireless\"\": \"\"no\"\"}\"\n"+
+			"x,Earbuds,free,5,,,,\n"+
+			"x,Cable,4.99,,,96385074,,\n"+
+			"x,Speaker,59.99,3,,,,\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 4, report.Failed)
+	require.Len(t, report.Results, 6)
+	assert.Equal(t, service.ProductImportCreated, report.Results[0].Status)
+	assert.Equal(t, "barcode repeated in the file", report.Results[1].Error)
+	assert.Contains(t, report.Results[2].Error, domain.ErrInvalidAttributes.Error())
+	assert.Equal(t, "price must be a positive number below 100000000", report.Results[3].Error)
+	assert.Equal(t, service.ErrBarcodeTaken.Error(), report.Results[4].Error)
+	assert.Equal(t, "96385074", report.Results[4].Barcode)
+	assert.Equal(t, 7, report.Results[5].Line)
+
+	require.Len(t, created, 2)
+	assert.Equal(t, []string{"audio", "wireless"}, created[0].Tags)
+	assert.Equal(t, domain.ProductStatusActive, created[0].Status)
+	assert.Equal(t, map[string]any{"wireless": true}, created[0].Attributes)
+	assert.Equal(t, "Speaker", created[1].Description)
+	assert.Equal(t, &created[0].ID, report.Results[0].ProductID)
+}
+
+func TestImportProducts_Unit_RejectsInvalidFiles(t *testing.T) {
+	svc := service.NewProductService(mocks.NewMockTxBeginner(t), mocks.NewMockProductRepository(t), mocks.NewMockProductRevisionRepository(t), mocks.NewMockAttributeRepository(t))
+
+	for name, file := range map[string]string{
+		"empty":          "",
+		"missing column": "description,quantity\nHeadphones,25\n",
+		"invalid csv":    "description,price\n\"Headphones,99.99\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.ImportProducts(context.Background(), strings.NewReader(file))
+			assert.ErrorIs(t, err, service.ErrInvalidProductImport)
+		})
+	}
+}
+
+func TestImportProducts_Unit_ReportsRowsReadBeforeMalformedLine(t *testing.T) {
+	svc := service.NewProductService(mocks.NewMockTxBeginner(t), mocks.NewMockProductRepository(t), mocks.NewMockProductRevisionRepository(t), mocks.NewMockAttributeRepository(t))
+
+	report, err := svc.ImportProducts(context.Background(), strings.NewReader(
+		"description,price\nHeadphones,99.99\nEarbuds,free\n\"Speaker,59.99\n"))
+
+	require.ErrorIs(t, err, service.ErrInvalidProductImport)
+	require.NotNil(t, report)
+	assert.Equal(t, 4, report.StoppedAt)
+	assert.Zero(t, report.Created)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, []service.ProductImportResult{
+		{Line: 2, Status: service.ProductImportFailed, Error: "not imported, the import stopped"},
+		{Line: 3, Status: service.ProductImportFailed, Error: "price must be a positive number below 100000000"},
+	}, report.Results)
+}
+
+func TestImportProducts_Unit_ReportsFailedBatch(t *testing.T) {
+	repo := mocks.NewMockProductRepository(t)
+	svc := service.NewProductService(mocks.NewMockTxBeginner(t), repo, mocks.NewMockProductRevisionRepository(t), mocks.NewMockAttributeRepository(t))
+
+	repo.EXPECT().CreateMany(mock.Anything, mock.Anything).Return(assert.AnError)
+
+	report, err := svc.ImportProducts(context.Background(), strings.NewReader(
+		"description,price\nEarbuds,free\nHeadphones,99.99\nSpeaker,59.99\n"))
+
+	require.ErrorIs(t, err, assert.AnError)
+	require.NotNil(t, report)
+	assert.Equal(t, 3, report.StoppedAt)
+	assert.Zero(t, report.Created)
+	assert.Equal(t, 3, report.Failed)
+	require.Len(t, report.Results, 3)
+	assert.Equal(t, "price must be a positive number below 100000000", report.Results[0].Error)
+	assert.Equal(t, "not imported, the import stopped", report.Results[1].Error)
+	assert.Equal(t, "not imported, the import stopped", report.Results[2].Error)
+}
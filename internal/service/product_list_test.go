@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"product-api/internal/repository/postgres"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"product-api/internal/testutil/testdb"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProductListTestSuite struct {
+	suite.Suite
+	productRepo repository.ProductRepository
+	service     *service.ProductService
+}
+
+func (s *ProductListTestSuite) SetupTest() {
+	dbpool := testdb.New(s.T())
+	s.productRepo = postgres.NewProductRepository(dbpool)
+	s.service = service.NewProductService(dbpool, s.productRepo, postgres.NewProductRevisionRepository(dbpool), postgres.NewAttributeRepository(dbpool))
+}
+
+// list returns the IDs of the products listed with the criteria and the total.
+func (s *ProductListTestSuite) list(in service.ProductListInput) ([]uuid.UUID, int) {
+	if in.Limit == 0 {
+		in.Limit = 100
+	}
+	page, err := s.service.ListProducts(context.Background(), in)
+	s.Require().NoError(err)
+	ids := make([]uuid.UUID, 0, len(page.Products))
+	for _, p := range page.Products {
+		ids = append(ids, p.ID)
+	}
+	return ids, page.Total
+}
+
+func (s *ProductListTestSuite) TestPages() {
+	var newestFirst []uuid.UUID
+	for range 5 {
+		newestFirst = append([]uuid.UUID{factory.CreateProduct(s.T(), s.productRepo).ID}, newestFirst...)
+	}
+	factory.CreateProduct(s.T(), s.productRepo, factory.WithStatus(domain.ProductStatusDraft))
+	factory.CreateProduct(s.T(), s.productRepo, factory.WithStatus(domain.ProductStatusArchived))
+
+	ids, total := s.list(service.ProductListInput{})
+	s.Equal(newestFirst, ids, "active products, newest first")
+	s.Equal(5, total)
+
+	ids, total = s.list(service.ProductListInput{Offset: 2, Limit: 2})
+	s.Equal(newestFirst[2:4], ids)
+	s.Equal(5, total, "the total counts every page")
+
+	ids, total = s.list(service.ProductListInput{Offset: 10, Limit: 2})
+	s.Empty(ids)
+	s.Equal(5, total)
+}
+
+func (s *ProductListTestSuite) TestFilters() {
+	summerSale := factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("summer", "sale"), factory.WithPrice(20))
+	summer := factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("summer"), factory.WithPrice(10))
+	soldOut := factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("sale"), factory.WithPrice(30), factory.WithQuantity(0))
+
+	ids, _ := s.list(service.ProductListInput{Tags: []string{"summer", "sale"}})
+	s.Equal([]uuid.UUID{summerSale.ID}, ids, "products having all the tags")
+
+	minPrice, maxPrice := 10.0, 20.0
+	ids, total := s.list(service.ProductListInput{MinPrice: &minPrice, MaxPrice: &maxPrice})
+	s.Equal([]uuid.UUID{summer.ID, summerSale.ID}, ids, "the price range is inclusive")
+	s.Equal(2, total)
+
+	ids, _ = s.list(service.ProductListInput{Tags: []string{"sale"}})
+	s.Equal([]uuid.UUID{soldOut.ID, summerSale.ID}, ids)
+	ids, _ = s.list(service.ProductListInput{Tags: []string{"sale"}, InStock: true})
+	s.Equal([]uuid.UUID{summerSale.ID}, ids)
+
+	_, err := s.service.ListProducts(context.Background(), service.ProductListInput{MinPrice: &maxPrice, MaxPrice: &minPrice, Limit: 10})
+	s.ErrorIs(err, service.ErrInvalidPriceRange)
+}
+
+func (s *ProductListTestSuite) TestInStockBundles() {
+	component := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(3))
+	available := factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("bundle"), factory.WithQuantity(0),
+		factory.WithComponents(domain.BundleComponent{ProductID: component.ID, Quantity: 3}))
+	factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("bundle"), factory.WithQuantity(0),
+		factory.WithComponents(domain.BundleComponent{ProductID: component.ID, Quantity: 4}))
+
+	ids, total := s.list(service.ProductListInput{Tags: []string{"bundle"}, InStock: true})
+	s.Equal([]uuid.UUID{available.ID}, ids, "bundles whose components have stock for one bundle")
+	s.Equal(1, total)
+}
+
+func TestProductListTestSuite(t *testing.T) {
+	suite.Run(t, new(ProductListTestSuite))
+}
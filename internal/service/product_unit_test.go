@@ -0,0 +1,264 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// productServiceMocks contains mocked dependencies of ProductService.
+type productServiceMocks struct {
+	tx         *mocks.MockTx
+	repo       *mocks.MockProductRepository
+	revisions  *mocks.MockProductRevisionRepository
+	attributes *mocks.MockAttributeRepository
+}
+
+func newProductServiceWithMocks(t *testing.T) (*service.ProductService, *productServiceMocks) {
+	db := mocks.NewMockTxBeginner(t)
+	m := &productServiceMocks{
+		tx:         mocks.NewMockTx(t),
+		repo:       mocks.NewMockProductRepository(t),
+		revisions:  mocks.NewMockProductRevisionRepository(t),
+		attributes: mocks.NewMockAttributeRepository(t),
+	}
+	db.EXPECT().Begin(mock.Anything).Return(m.tx, nil)
+	return service.NewProductService(db, m.repo, m.revisions, m.attributes), m
+}
+
+const productActor = "client:admin"
+
+func TestBulkUpdate_Unit_PartialFailure(t *testing.T) {
+	svc, m := newProductServiceWithMocks(t)
+	product := factory.NewProduct(factory.WithPrice(10))
+	missing := uuid.New()
+	price, invalidPrice := 15.0, -1.0
+
+	m.repo.EXPECT().FindByIDsTx(mock.Anything, m.tx, []uuid.UUID{product.ID, missing, product.ID}).Return([]domain.Product{*product}, nil)
+	m.repo.EXPECT().UpdateTx(mock.Anything, m.tx, mock.MatchedBy(func(p *domain.Product) bool {
+		return p.ID == product.ID && p.Price == 15
+	})).Return(nil)
+	m.revisions.EXPECT().AppendTx(mock.Anything, m.tx, mock.MatchedBy(func(revs []domain.ProductRevision) bool {
+		return len(revs) == 1 && revs[0].ProductID == product.ID && revs[0].Actor == productActor &&
+			revs[0].Changes[0] == domain.FieldDiff{Field: domain.ProductFieldPrice, Before: 10.0, After: 15.0}
+	})).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil)
+
+	report, err := svc.BulkUpdate(context.Background(), productActor, []service.ProductChangeInput{
+		{ID: product.ID, Change: domain.ProductChange{Price: &price}},
+		{ID: missing, Change: domain.ProductChange{Price: &price}},
+		{ID: product.ID, Change: domain.ProductChange{Price: &invalidPrice}},
+	}, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, service.BulkItemUpdated, report.Results[0].Status)
+	assert.Equal(t, 15.0, report.Results[0].Product.Price)
+	assert.Equal(t, service.BulkItemFailed, report.Results[1].Status)
+	assert.Equal(t, service.BulkItemFailed, report.Results[2].Status)
+}
+
+func TestBulkUpdate_Unit_AtomicRollsBackOnFailure(t *testing.T) {
+	svc, m := newProductServiceWithMocks(t)
+	product := factory.NewProduct()
+	missing := uuid.New()
+
+	m.repo.EXPECT().FindByIDsTx(mock.Anything, m.tx, []uuid.UUID{product.ID, missing}).Return([]domain.Product{*product}, nil)
+	m.tx.EXPECT().Rollback(mock.Anything).Return(nil)
+
+	report, err := svc.BulkUpdate(context.Background(), productActor, []service.ProductChangeInput{
+		{ID: product.ID, Change: domain.ProductChange{AddTags: []string{"sale"}}},
+		{ID: missing, Change: domain.ProductChange{AddTags: []string{"sale"}}},
+	}, true)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Updated)
+	assert.Equal(t, service.BulkItemRejected, report.Results[0].Status)
+	assert.Nil(t, report.Results[0].Product)
+	assert.Equal(t, service.BulkItemFailed, report.Results[1].Status)
+}
+
+func TestBulkUpdate_Unit_ValidatesChangedAttributes(t *testing.T) {
+	svc, m := newProductServiceWithMocks(t)
+	product := factory.NewProduct(factory.WithTags("monitor"))
+	category := "monitors"
+
+	m.repo.EXPECT().FindByIDsTx(mock.Anything, m.tx, []uuid.UUID{product.ID, product.ID}).Return([]domain.Product{*product}, nil)
+	m.attributes.EXPECT().FindByCategory(mock.Anything, category).Return([]domain.AttributeDefinition{
+		{Category: category, Name: "screen_size", Type: domain.AttributeTypeNumber, Unit: "in"},
+	}, nil).Once()
+	m.repo.EXPECT().UpdateTx(mock.Anything, m.tx, mock.MatchedBy(func(p *domain.Product) bool {
+		return p.Category == category && p.Attributes["screen_size"] == 27.0 && len(p.Tags) == 1
+	})).Return(nil)
+	m.revisions.EXPECT().AppendTx(mock.Anything, m.tx, mock.Anything).Return(nil)
+	m.tx.EXPECT().Commit(mock.Anything).Return(nil)
+
+	report, err := svc.BulkUpdate(context.Background(), productActor, []service.ProductChangeInput{
+		{ID: product.ID, Change: domain.ProductChange{Category: &category, Attributes: map[string]any{"screen_size": 27.0}}},
+		{ID: product.ID, Change: domain.ProductChange{RemoveTags: []string{"monitor"}, Attributes: map[string]any{"screen_size": "27in"}}},
+	}, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, service.BulkItemUpdated, report.Results[0].Status)
+	assert.Equal(t, service.BulkItemFailed, report.Results[1].Status)
+	assert.Contains(t, report.Results[1].Error, domain.ErrInvalidAttributes.Error())
+}
+
+func TestDelete_Unit_ProductInUse(t *testing.T) {
+	svc, m := newProductServiceWithMocks(t)
+	product := factory.NewProduct()
+
+	m.repo.EXPECT().FindByIDTx(mock.Anything, m.tx, product.ID).Return(product, nil)
+	m.repo.EXPECT().DeleteTx(mock.Anything, m.tx, product.ID).Return(repository.ErrProductInUse)
+	m.tx.EXPECT().Rollback(mock.Anything).Return(nil)
+
+	err := svc.Delete(context.Background(), product.ID)
+
+	assert.ErrorIs(t, err, service.ErrProductInUse)
+}
+
+func TestDelete_Unit_ProductNotFound(t *testing.T) {
+	svc, m := newProductServiceWithMocks(t)
+	id := uuid.New()
+
+	m.repo.EXPECT().FindByIDTx(mock.Anything, m.tx, id).Return(nil, repository.ErrProductNotFound)
+	m.tx.EXPECT().Rollback(mock.Anything).Return(nil)
+
+	err := svc.Delete(context.Background(), id)
+
+	assert.ErrorIs(t, err, service.ErrProductNotFound)
+}
+
+func TestPriceAt_Unit_ResolvesPriceFromHistory(t *testing.T) {
+	repo := mocks.NewMockProductRepository(t)
+	revisions := mocks.NewMockProductRevisionRepository(t)
+	svc := service.NewProductService(mocks.NewMockTxBeginner(t), repo, revisions, mocks.NewMockAttributeRepository(t))
+	product := factory.NewProduct()
+	changedAt := time.Now().Add(-time.Hour)
+
+	repo.EXPECT().FindByID(mock.Anything, product.ID).Return(product, nil)
+	revisions.EXPECT().FindByField(mock.Anything, product.ID, domain.ProductFieldPrice).Return([]domain.ProductRevision{
+		{ProductID: product.ID, Actor: productActor, ChangedAt: changedAt, Changes: []domain.FieldDiff{
+			{Field: domain.ProductFieldPrice, Before: 120.0, After: product.Price},
+		}},
+	}, nil)
+
+	price, err := svc.PriceAt(context.Background(), product.ID, changedAt.Add(-time.Minute))
+
+	require.NoError(t, err)
+	assert.Equal(t, 120.0, price.Price)
+	assert.Nil(t, price.SetAt)
+}
+
+func TestPriceAt_Unit_ProductNotFound(t *testing.T) {
+	repo := mocks.NewMockProductRepository(t)
+	svc := service.NewProductService(mocks.NewMockTxBeginner(t), repo, mocks.NewMockProductRevisionRepository(t), mocks.NewMockAttributeRepository(t))
+	id := uuid.New()
+
+	repo.EXPECT().FindByID(mock.Anything, id).Return(nil, repository.ErrProductNotFound)
+
+	_, err := svc.PriceAt(context.Background(), id, time.Now())
+
+	assert.ErrorIs(t, err, service.ErrProductNotFound)
+}
+
+func TestListProducts_Unit_ListsActiveProducts(t *testing.T) {
+	repo := mocks.NewMockProductRepository(t)
+	svc := service.NewProductService(mocks.NewMockTxBeginner(t), repo, mocks.NewMockProductRevisionRepository(t), mocks.NewMockAttributeRepository(t))
+	product := factory.NewProduct()
+	minPrice := 10.0
+
+	repo.EXPECT().List(mock.Anything, repository.ProductFilter{
+		Status:   domain.ProductStatusActive,
+		Tags:     []string{"audio"},
+		MinPrice: &minPrice,
+		InStock:  true,
+	}, 40, 20).Return([]domain.Product{*product}, 41, nil)
+
+	page, err := svc.ListProducts(context.Background(), service.ProductListInput{
+		Tags: []string{"audio"}, MinPrice: &minPrice, InStock: true, Offset: 40, Limit: 20,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 41, page.Total)
+	assert.Equal(t, []domain.Product{*product}, page.Products)
+}
+
+func TestListProducts_Unit_InvalidPriceRange(t *testing.T) {
+	svc := service.NewProductService(mocks.NewMockTxBeginner(t), mocks.NewMockProductRepository(t), mocks.NewMockProductRevisionRepository(t), mocks.NewMockAttributeRepository(t))
+	low, high, negative := 10.0, 20.0, -1.0
+
+	tests := []struct {
+		name string
+		in   service.ProductListInput
+	}{
+		{name: "minimum above maximum", in: service.ProductListInput{MinPrice: &high, MaxPrice: &low}},
+		{name: "negative minimum", in: service.ProductListInput{MinPrice: &negative}},
+		{name: "negative maximum", in: service.ProductListInput{MaxPrice: &negative}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.ListProducts(context.Background(), tt.in)
+			assert.ErrorIs(t, err, service.ErrInvalidPriceRange)
+		})
+	}
+}
+
+func TestIssues_Unit_CountsEveryRule(t *testing.T) {
+	repo := mocks.NewMockProductRepository(t)
+	svc := service.NewProductService(mocks.NewMockTxBeginner(t), repo, mocks.NewMockProductRevisionRepository(t), mocks.NewMockAttributeRepository(t))
+	issue := domain.CatalogIssue{ProductID: uuid.New(), Status: domain.ProductStatusActive, Rule: domain.CatalogIssueNoTags}
+
+	repo.EXPECT().FindIssues(mock.Anything, []string(nil), 0, 10).Return([]domain.CatalogIssue{issue}, map[string]int{
+		domain.CatalogIssueNoTags:    3,
+		domain.CatalogIssueZeroPrice: 1,
+	}, nil)
+
+	report, err := svc.Issues(context.Background(), nil, 0, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, []domain.CatalogIssue{issue}, report.Issues)
+	assert.Equal(t, 4, report.Total)
+	// Rules finding no issues are reported with a zero count
+	assert.Equal(t, map[string]int{
+		domain.CatalogIssueInactiveComponent: 0,
+		domain.CatalogIssueNegativeStock:     0,
+		domain.CatalogIssueNoTags:            3,
+		domain.CatalogIssueZeroPrice:         1,
+	}, report.Counts)
+}
+
+func TestIssues_Unit_RequestedRules(t *testing.T) {
+	repo := mocks.NewMockProductRepository(t)
+	svc := service.NewProductService(mocks.NewMockTxBeginner(t), repo, mocks.NewMockProductRevisionRepository(t), mocks.NewMockAttributeRepository(t))
+	rules := []string{domain.CatalogIssueNegativeStock, domain.CatalogIssueNegativeStock}
+
+	repo.EXPECT().FindIssues(mock.Anything, rules, 20, 10).Return(nil, map[string]int{domain.CatalogIssueNegativeStock: 25}, nil)
+
+	report, err := svc.Issues(context.Background(), rules, 20, 10)
+
+	require.NoError(t, err)
+	assert.Empty(t, report.Issues)
+	assert.NotNil(t, report.Issues)
+	assert.Equal(t, 25, report.Total)
+	assert.Equal(t, map[string]int{domain.CatalogIssueNegativeStock: 25}, report.Counts)
+}
+
+func TestIssues_Unit_UnknownRule(t *testing.T) {
+	svc := service.NewProductService(mocks.NewMockTxBeginner(t), mocks.NewMockProductRepository(t), mocks.NewMockProductRevisionRepository(t), mocks.NewMockAttributeRepository(t))
+
+	_, err := svc.Issues(context.Background(), []string{domain.CatalogIssueNoTags, "missing_images"}, 0, 10)
+
+	assert.ErrorIs(t, err, service.ErrInvalidIssueRule)
+}
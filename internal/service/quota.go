@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+)
+
+var (
+	// ErrQuotaExceeded is returned when an API client made more calls than its quota allows.
+	ErrQuotaExceeded = errors.New("api quota exceeded")
+	// ErrInvalidQuota is returned when a quota has an unknown period or a negative limit.
+	ErrInvalidQuota = errors.New("invalid quota")
+)
+
+// QuotaService enforces daily and monthly call quotas of API clients.
+// Quotas stored by admins override the defaults from configuration.
+type QuotaService struct {
+	repo      repository.QuotaRepository
+	defaults  map[string]map[string]int // Period -> client -> limit
+	softLimit int                       // Percentage of a quota after which clients are warned, 0 to not warn
+}
+
+// NewQuotaService creates a new quota service with default daily and monthly limits by client name.
+// Clients are warned once they used more than softLimit percent of a quota; 0 and 100 disable warnings.
+func NewQuotaService(repo repository.QuotaRepository, daily, monthly map[string]int, softLimit int) *QuotaService {
+	return &QuotaService{
+		repo:      repo,
+		softLimit: softLimit,
+		defaults: map[string]map[string]int{
+			domain.QuotaPeriodDay:   daily,
+			domain.QuotaPeriodMonth: monthly,
+		},
+	}
+}
+
+// Consume counts a call of the client against each of its enabled quotas and returns their usage.
+// Returns the usage together with ErrQuotaExceeded if any quota is exceeded.
+func (s *QuotaService) Consume(ctx context.Context, client string) ([]domain.QuotaUsage, error) {
+	const op = "QuotaService.Consume"
+
+	limits, err := s.limits(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		usage    []domain.QuotaUsage
+		exceeded bool
+	)
+	now := time.Now()
+	for _, period := range domain.QuotaPeriods {
+		limit := limits[period]
+		if limit == 0 {
+			continue
+		}
+		start, end := domain.QuotaWindow(period, now)
+		used, err := s.repo.Increment(ctx, client, period, start)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		u := s.usage(period, limit, used, end)
+		exceeded = exceeded || u.Exceeded()
+		usage = append(usage, u)
+	}
+
+	if exceeded {
+		return usage, ErrQuotaExceeded
+	}
+	return usage, nil
+}
+
+// Usage returns the current usage of each enabled quota of the client without counting a call.
+func (s *QuotaService) Usage(ctx context.Context, client string) ([]domain.QuotaUsage, error) {
+	const op = "QuotaService.Usage"
+
+	limits, err := s.limits(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	usage := []domain.QuotaUsage{}
+	now := time.Now()
+	for _, period := range domain.QuotaPeriods {
+		limit := limits[period]
+		if limit == 0 {
+			continue
+		}
+		start, end := domain.QuotaWindow(period, now)
+		used, err := s.repo.FindUsage(ctx, client, period, start)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		usage = append(usage, s.usage(period, limit, used, end))
+	}
+	return usage, nil
+}
+
+// SetQuota sets the limit of the client's quota for the period, overriding the configured default.
+// A limit of 0 disables the quota.
+func (s *QuotaService) SetQuota(ctx context.Context, client, period string, limit int) error {
+	if !domain.IsValidQuotaPeriod(period) || limit < 0 {
+		return ErrInvalidQuota
+	}
+	return s.repo.SetLimit(ctx, domain.APIQuota{Client: client, Period: period, Limit: limit})
+}
+
+// ResetUsage clears the call counters of the client, e.g. after a partner's quota was raised.
+func (s *QuotaService) ResetUsage(ctx context.Context, client string) error {
+	return s.repo.ResetUsage(ctx, client)
+}
+
+// usage returns the usage of a quota with its soft limit.
+func (s *QuotaService) usage(period string, limit, used int, resetAt time.Time) domain.QuotaUsage {
+	u := domain.QuotaUsage{Period: period, Limit: limit, Used: used, ResetAt: resetAt}
+	if s.softLimit > 0 && s.softLimit < 100 {
+		u.SoftLimit = limit * s.softLimit / 100
+	}
+	return u
+}
+
+// limits returns the effective limit of each period for the client.
+func (s *QuotaService) limits(ctx context.Context, client string) (map[string]int, error) {
+	limits := make(map[string]int, len(domain.QuotaPeriods))
+	for period, defaults := range s.defaults {
+		limits[period] = defaults[client]
+	}
+
+	stored, err := s.repo.FindLimits(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range stored {
+		limits[q.Period] = q.Limit
+	}
+	return limits, nil
+}
@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaService_Consume(t *testing.T) {
+	repo := mocks.NewMockQuotaRepository(t)
+	svc := service.NewQuotaService(repo, map[string]int{"gateway": 100}, map[string]int{"gateway": 1000}, 0)
+	ctx := context.Background()
+
+	// Stored monthly limit overrides the default, the daily default still applies
+	repo.EXPECT().FindLimits(ctx, "gateway").Return([]domain.APIQuota{
+		{Client: "gateway", Period: domain.QuotaPeriodMonth, Limit: 5000},
+	}, nil)
+	repo.EXPECT().Increment(ctx, "gateway", domain.QuotaPeriodDay, mock.Anything).Return(42, nil)
+	repo.EXPECT().Increment(ctx, "gateway", domain.QuotaPeriodMonth, mock.Anything).Return(420, nil)
+
+	usage, err := svc.Consume(ctx, "gateway")
+
+	require.NoError(t, err)
+	require.Len(t, usage, 2)
+	assert.Equal(t, domain.QuotaUsage{Period: domain.QuotaPeriodDay, Limit: 100, Used: 42, ResetAt: usage[0].ResetAt}, usage[0])
+	assert.Equal(t, 5000, usage[1].Limit)
+	assert.Equal(t, 4580, usage[1].Remaining())
+}
+
+func TestQuotaService_Consume_Exceeded(t *testing.T) {
+	repo := mocks.NewMockQuotaRepository(t)
+	svc := service.NewQuotaService(repo, map[string]int{"scim": 10}, nil, 0)
+	ctx := context.Background()
+
+	repo.EXPECT().FindLimits(ctx, "scim").Return(nil, nil)
+	repo.EXPECT().Increment(ctx, "scim", domain.QuotaPeriodDay, mock.Anything).Return(11, nil)
+
+	usage, err := svc.Consume(ctx, "scim")
+
+	assert.ErrorIs(t, err, service.ErrQuotaExceeded)
+	require.Len(t, usage, 1)
+	assert.True(t, usage[0].Exceeded())
+}
+
+func TestQuotaService_Consume_Disabled(t *testing.T) {
+	repo := mocks.NewMockQuotaRepository(t)
+	svc := service.NewQuotaService(repo, map[string]int{"gateway": 100}, nil, 0)
+	ctx := context.Background()
+
+	// A stored limit of 0 disables the default quota, no call is counted
+	repo.EXPECT().FindLimits(ctx, "gateway").Return([]domain.APIQuota{
+		{Client: "gateway", Period: domain.QuotaPeriodDay, Limit: 0},
+	}, nil)
+
+	usage, err := svc.Consume(ctx, "gateway")
+
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+}
+
+func TestQuotaService_SetQuota_Invalid(t *testing.T) {
+	svc := service.NewQuotaService(mocks.NewMockQuotaRepository(t), nil, nil, 0)
+
+	assert.ErrorIs(t, svc.SetQuota(context.Background(), "gateway", "week", 10), service.ErrInvalidQuota)
+	assert.ErrorIs(t, svc.SetQuota(context.Background(), "gateway", domain.QuotaPeriodDay, -1), service.ErrInvalidQuota)
+}
+
+func TestQuotaService_Consume_SoftLimit(t *testing.T) {
+	repo := mocks.NewMockQuotaRepository(t)
+	svc := service.NewQuotaService(repo, map[string]int{"gateway": 100}, nil, 80)
+	ctx := context.Background()
+
+	repo.EXPECT().FindLimits(ctx, "gateway").Return(nil, nil)
+	repo.EXPECT().Increment(ctx, "gateway", domain.QuotaPeriodDay, mock.Anything).Return(81, nil)
+
+	usage, err := svc.Consume(ctx, "gateway")
+
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.Equal(t, 80, usage[0].SoftLimit)
+	assert.True(t, usage[0].Warned())
+}
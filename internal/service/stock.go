@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/event"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidStockMovement is returned when a manual stock movement has an unsupported reason or zero delta.
+	ErrInvalidStockMovement = errors.New("invalid stock movement")
+)
+
+// StockService manages the stock ledger. Product quantities are a projection of it.
+type StockService struct {
+	repo   repository.StockRepository
+	events event.Publisher
+	logger logger.Logger
+}
+
+// NewStockService creates a new stock service. Recorded movements are published to events.
+func NewStockService(repo repository.StockRepository, events event.Publisher, logger logger.Logger) *StockService {
+	return &StockService{repo: repo, events: events, logger: logger}
+}
+
+// RecordMovementInput contains data for a manual stock movement.
+type RecordMovementInput struct {
+	ProductID uuid.UUID
+	Delta     int
+	Reason    string // receipt, adjustment or return; allocations and releases are only recorded by orders
+	Note      string
+}
+
+// RecordMovement appends a manual movement to the ledger, updates the product quantity
+// and publishes event.ProductStockChanged.
+// Returns ErrInsufficientStock if the movement would make the quantity negative.
+func (s *StockService) RecordMovement(ctx context.Context, in RecordMovementInput) (*domain.StockMovement, error) {
+	const op = "StockService.RecordMovement"
+
+	switch {
+	case in.Delta == 0, !domain.IsValidStockReason(in.Reason),
+		in.Reason == domain.StockReasonAllocation, in.Reason == domain.StockReasonRelease:
+		return nil, ErrInvalidStockMovement
+	}
+
+	movement := &domain.StockMovement{
+		ID:        uuid.New(),
+		ProductID: in.ProductID,
+		Delta:     in.Delta,
+		Reason:    in.Reason,
+		Note:      in.Note,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Record(ctx, movement); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrProductNotFound):
+			return nil, ErrProductNotFound
+		case errors.Is(err, repository.ErrNegativeStock):
+			return nil, ErrInsufficientStock
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	s.events.Publish(ctx, event.StockChanged(*movement))
+	return movement, nil
+}
+
+// History returns the most recent stock movements of the product, newest first.
+func (s *StockService) History(ctx context.Context, productID uuid.UUID, limit int) ([]domain.StockMovement, error) {
+	return s.repo.FindByProductID(ctx, productID, limit)
+}
+
+// StockDriftReport contains products whose cached quantity differs from the ledger.
+type StockDriftReport struct {
+	Drifts  []domain.StockDrift
+	Rebuilt int // Number of rebuilt products, 0 unless repair was requested
+}
+
+// CheckDrift finds up to limit products whose quantity does not match the sum of their stock movements.
+// If repair is true, their quantities are rebuilt from the ledger and event.ProductStockChanged is published.
+func (s *StockService) CheckDrift(ctx context.Context, limit int, repair bool) (*StockDriftReport, error) {
+	const op = "StockService.CheckDrift"
+
+	drifts, err := s.repo.FindDrift(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	report := &StockDriftReport{Drifts: drifts}
+	if report.Drifts == nil {
+		report.Drifts = []domain.StockDrift{}
+	}
+	for _, d := range drifts {
+		s.logger.WithTrace(ctx).Warn("product quantity drifted from stock ledger", "op", op, "product_id", d.ProductID,
+			"cached_quantity", d.CachedQuantity, "ledger_quantity", d.LedgerQuantity)
+		if !repair {
+			continue
+		}
+		delta, err := s.repo.RebuildQuantity(ctx, d.ProductID)
+		if err != nil {
+			return report, fmt.Errorf("%s: rebuild product %s: %w", op, d.ProductID, err)
+		}
+		report.Rebuilt++
+		if delta != 0 {
+			s.events.Publish(ctx, event.ProductStockChanged{ProductID: d.ProductID, Delta: delta, Reason: event.StockReasonRebuild})
+		}
+	}
+	return report, nil
+}
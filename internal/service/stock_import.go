@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"product-api/internal/domain"
+	"product-api/internal/event"
+	"product-api/internal/repository"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidStockImport is returned when a stock import file cannot be read or has an unknown mode.
+	// Invalid rows do not fail the import, they are reported.
+	ErrInvalidStockImport = errors.New("invalid stock import")
+)
+
+// Stock import modes.
+const (
+	StockImportAbsolute = "absolute" // Quantities replace product quantities, e.g. counted in the warehouse
+	StockImportDelta    = "delta"    // Quantities are added to product quantities, negative ones are subtracted
+)
+
+// Stock import row statuses.
+const (
+	StockImportUpdated   = "updated"
+	StockImportUnchanged = "unchanged" // The quantity already was the imported one, or the delta is 0
+	StockImportFailed    = "failed"
+)
+
+const (
+	// MaxStockImportRows is the maximum number of rows of a stock import file.
+	MaxStockImportRows = 100_000
+	// stockImportBatchSize is the number of rows applied in one transaction.
+	stockImportBatchSize = 1000
+	// stockImportNote is the note of stock movements recorded by imports.
+	stockImportNote = "stock import"
+)
+
+// StockImportRow is a row of a stock import file.
+type StockImportRow struct {
+	Line     int // Line in the file, starting at 1 with the header
+	SKU      string
+	Quantity int
+	Error    string // Reason the row is invalid, empty if valid
+}
+
+// StockImportResult is the outcome of a row of a stock import, in file order.
+type StockImportResult struct {
+	Line      int
+	SKU       string
+	ProductID *uuid.UUID `json:",omitempty"` // Not set for unknown SKUs
+	Previous  int        // Quantity before the import
+	Quantity  int        // Quantity after the import
+	Status    string     // updated, unchanged or failed
+	Error     string     `json:",omitempty"` // Reason of the failure
+}
+
+// StockImportReport reconciles a stock import with product quantities.
+type StockImportReport struct {
+	Mode      string
+	Updated   int
+	Unchanged int
+	Failed    int
+	Results   []StockImportResult
+}
+
+// ParseStockImport reads a CSV stock import file. The header names the "sku" and "quantity" columns
+// in any order; other columns are ignored. Rows with a missing SKU or a quantity that is not an integer
+// are returned with an error, so they are reported instead of failing the import.
+// Returns ErrInvalidStockImport if the file is not valid CSV, lacks a column or has more than MaxStockImportRows rows.
+func ParseStockImport(r io.Reader) ([]StockImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: empty file", ErrInvalidStockImport)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStockImport, err)
+	}
+	skuColumn, quantityColumn := -1, -1
+	for i, name := range header {
+		// Spreadsheet applications may start files with a byte order mark
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) {
+		case "sku":
+			skuColumn = i
+		case "quantity":
+			quantityColumn = i
+		}
+	}
+	if skuColumn < 0 || quantityColumn < 0 {
+		return nil, fmt.Errorf("%w: header must name the sku and quantity columns", ErrInvalidStockImport)
+	}
+
+	var rows []StockImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidStockImport, err)
+		}
+		if len(rows) == MaxStockImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidStockImport, MaxStockImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := StockImportRow{Line: line, SKU: strings.TrimSpace(csvField(record, skuColumn))}
+		quantity, err := strconv.Atoi(strings.TrimSpace(csvField(record, quantityColumn)))
+		switch {
+		case row.SKU == "":
+			row.Error = "missing SKU"
+		case err != nil:
+			row.Error = "quantity must be an integer"
+		default:
+			row.Quantity = quantity
+		}
+		rows = append(rows, row)
+	}
+}
+
+// csvField returns the field of the record, empty if the record is too short.
+func csvField(record []string, i int) string {
+	if i < len(record) {
+		return record[i]
+	}
+	return ""
+}
+
+// Import applies imported quantities to the products whose barcode is the SKU, recording ledger adjustments.
+// SKUs are normalized like barcodes, so a UPC-A SKU finds the product stored with its EAN-13 barcode.
+// Rows are applied in batches of separate transactions; if a batch fails, the error is returned with the report
+// of earlier batches, which stay applied. Absolute imports can be repeated safely.
+// Invalid rows, SKUs that are not valid barcodes, unknown or repeated SKUs and changes that would make a quantity negative fail and are reported.
+// Returns ErrInvalidStockImport for unknown modes.
+func (s *StockService) Import(ctx context.Context, rows []StockImportRow, mode string) (*StockImportReport, error) {
+	const op = "StockService.Import"
+
+	if mode != StockImportAbsolute && mode != StockImportDelta {
+		return nil, fmt.Errorf("%w: unknown mode %q", ErrInvalidStockImport, mode)
+	}
+
+	report := &StockImportReport{Mode: mode, Results: make([]StockImportResult, len(rows))}
+	barcodes := make([]string, len(rows)) // Normalized SKUs of the rows
+	seen := make(map[string]bool, len(rows))
+	var batch []int // Indexes of the rows of the next batch
+	for i, row := range rows {
+		result := &report.Results[i]
+		result.Line, result.SKU = row.Line, row.SKU
+		barcode, barcodeErr := domain.NormalizeBarcode(row.SKU)
+		switch {
+		case row.Error != "":
+			result.Error = row.Error
+		case barcodeErr != nil:
+			result.Error = "SKU must be an EAN-8, UPC-A, EAN-13 or GTIN-14 barcode"
+		case mode == StockImportAbsolute && row.Quantity < 0:
+			result.Error = "quantity cannot be negative"
+		case seen[barcode]:
+			result.Error = "SKU repeated in the file"
+		default:
+			seen[barcode] = true
+			barcodes[i] = barcode
+			batch = append(batch, i)
+		}
+		if result.Error != "" {
+			result.Status = StockImportFailed
+			report.Failed++
+		}
+
+		if len(batch) == stockImportBatchSize || (i == len(rows)-1 && len(batch) > 0) {
+			if err := s.importBatch(ctx, report, rows, barcodes, batch, mode == StockImportAbsolute); err != nil {
+				return report, fmt.Errorf("%s: %w", op, err)
+			}
+			batch = batch[:0]
+		}
+	}
+	return report, nil
+}
+
+// importBatch applies the rows with the indexes in one transaction, matching products by the normalized
+// barcodes of the rows, and records their results in the report.
+func (s *StockService) importBatch(ctx context.Context, report *StockImportReport, rows []StockImportRow, barcodes []string, batch []int, absolute bool) error {
+	items := make([]repository.StockImportItem, len(batch))
+	for i, idx := range batch {
+		items[i] = repository.StockImportItem{Barcode: barcodes[idx], Quantity: rows[idx].Quantity}
+	}
+	changes, err := s.repo.ImportQuantities(ctx, items, absolute, stockImportNote)
+	if err != nil {
+		return err
+	}
+	bySKU := make(map[string]repository.StockImportChange, len(changes))
+	for _, c := range changes {
+		bySKU[c.Barcode] = c
+	}
+
+	for _, idx := range batch {
+		result := &report.Results[idx]
+		change, ok := bySKU[barcodes[idx]]
+		switch {
+		case !ok:
+			result.Status, result.Error = StockImportFailed, "unknown SKU"
+			report.Failed++
+			continue
+		case change.Quantity < 0:
+			result.ProductID, result.Previous, result.Quantity = &change.ProductID, change.Previous, change.Previous
+			result.Status, result.Error = StockImportFailed, "quantity would become negative"
+			report.Failed++
+			continue
+		}
+		result.ProductID, result.Previous, result.Quantity = &change.ProductID, change.Previous, change.Quantity
+		if change.Quantity == change.Previous {
+			result.Status = StockImportUnchanged
+			report.Unchanged++
+		} else {
+			result.Status = StockImportUpdated
+			report.Updated++
+			s.events.Publish(ctx, event.ProductStockChanged{
+				ProductID: change.ProductID,
+				Delta:     change.Quantity - change.Previous,
+				Reason:    domain.StockReasonAdjustment,
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/event"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStockImport_Unit_ReportsInvalidRows(t *testing.T) {
+	rows, err := service.ParseStockImport(strings.NewReader(
+		"\ufeffLocation,Quantity,SKU\nA1,25,4006381333931\nA2,many,96385074\nA3,3,\n\nA4, -2 , 12345670\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, []service.StockImportRow{
+		{Line: 2, SKU: "4006381333931", Quantity: 25},
+		{Line: 3, SKU: "96385074", Error: "quantity must be an integer"},
+		{Line: 4, Error: "missing SKU"},
+		{Line: 6, SKU: "12345670", Quantity: -2},
+	}, rows)
+}
+
+func TestParseStockImport_Unit_RejectsInvalidFiles(t *testing.T) {
+	for name, file := range map[string]string{
+		"empty":          "",
+		"missing column": "sku,count\n4006381333931,25\n",
+		"invalid csv":    "sku,quantity\n\"4006381333931,25\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.ParseStockImport(strings.NewReader(file))
+			assert.ErrorIs(t, err, service.ErrInvalidStockImport)
+		})
+	}
+}
+
+func TestImport_Unit_NormalizesSKUs(t *testing.T) {
+	repo := mocks.NewMockStockRepository(t)
+	log := logger.NewSlogAdapter("local")
+	svc := service.NewStockService(repo, event.NewBus(log), log)
+	productID := uuid.New()
+
+	// The UPC-A SKU is looked up by the EAN-13 barcode products are stored with
+	repo.EXPECT().ImportQuantities(mock.Anything, []repository.StockImportItem{{Barcode: "0036000291452", Quantity: 7}}, true, mock.Anything).
+		Return([]repository.StockImportChange{{Barcode: "0036000291452", ProductID: productID, Previous: 3, Quantity: 7}}, nil)
+
+	report, err := svc.Import(context.Background(), []service.StockImportRow{
+		{Line: 2, SKU: "036000291452", Quantity: 7},
+		{Line: 3, SKU: "0036000291452", Quantity: 8},
+		{Line: 4, SKU: "ABC-1", Quantity: 1},
+	}, service.StockImportAbsolute)
+
+	require.NoError(t, err)
+	assert.Equal(t, []service.StockImportResult{
+		{Line: 2, SKU: "036000291452", ProductID: &productID, Previous: 3, Quantity: 7, Status: service.StockImportUpdated},
+		{Line: 3, SKU: "0036000291452", Status: service.StockImportFailed, Error: "SKU repeated in the file"},
+		{Line: 4, SKU: "ABC-1", Status: service.StockImportFailed, Error: "SKU must be an EAN-8, UPC-A, EAN-13 or GTIN-14 barcode"},
+	}, report.Results)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 2, report.Failed)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidSnapshotWindow is returned when the exported days end before they start or span more than MaxStockSnapshotWindow.
+var ErrInvalidSnapshotWindow = errors.New("invalid stock snapshot window")
+
+// MaxStockSnapshotWindow is the longest span of days snapshots are exported for.
+const MaxStockSnapshotWindow = 366 * 24 * time.Hour
+
+// stockSnapshotDelay is the time after midnight (UTC) before a day is snapshotted, so movements
+// of transactions committing late, e.g. checkouts started just before midnight, are included.
+const stockSnapshotDelay = 5 * time.Minute
+
+// StockSnapshotService records the closing stock of every product per day, so analysts can compute sell-through
+// and stockout durations that the current quantity cannot answer. Snapshots are derived from the stock ledger,
+// so days missed while no instance was running are snapshotted exactly once it runs again.
+type StockSnapshotService struct {
+	repo   repository.StockSnapshotRepository
+	logger logger.Logger
+}
+
+// NewStockSnapshotService creates a new stock snapshot service.
+func NewStockSnapshotService(repo repository.StockSnapshotRepository, logger logger.Logger) *StockSnapshotService {
+	return &StockSnapshotService{repo: repo, logger: logger}
+}
+
+// Run snapshots completed days until ctx is done, checking for a new day every pollInterval. Each check is recorded by hb.
+func (s *StockSnapshotService) Run(ctx context.Context, pollInterval time.Duration, hb *Heartbeat) {
+	const op = "StockSnapshotService.Run"
+
+	for {
+		_, err := s.SnapshotDays(ctx, time.Now())
+		hb.Beat(err)
+		if err != nil {
+			s.logger.Error("failed to snapshot stock", "op", op, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// SnapshotDays snapshots the days (UTC) completed by now after the last snapshotted day, only the previous day
+// if none was snapshotted yet, and returns the number of snapshotted days.
+func (s *StockSnapshotService) SnapshotDays(ctx context.Context, now time.Time) (int, error) {
+	const op = "StockSnapshotService.SnapshotDays"
+
+	last := now.Add(-stockSnapshotDelay).UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	day, err := s.repo.LastDay(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if day.IsZero() {
+		day = last
+	} else {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	days := 0
+	for ; !day.After(last); day = day.AddDate(0, 0, 1) {
+		products, err := s.repo.Snapshot(ctx, day)
+		if err != nil {
+			return days, fmt.Errorf("%s: day %s: %w", op, day.Format(time.DateOnly), err)
+		}
+		s.logger.Info("stock snapshot taken", "op", op, "day", day.Format(time.DateOnly), "products", products)
+		days++
+	}
+	return days, nil
+}
+
+// Export calls fn for every snapshot of the days in [from, to], of the product if productID is not nil,
+// ordered by day and product. Returns ErrInvalidSnapshotWindow.
+func (s *StockSnapshotService) Export(ctx context.Context, from, to time.Time, productID *uuid.UUID, fn func(*domain.StockSnapshot) error) error {
+	if to.Before(from) || to.Sub(from) >= MaxStockSnapshotWindow {
+		return ErrInvalidSnapshotWindow
+	}
+	if err := s.repo.Export(ctx, from, to, productID, fn); err != nil {
+		return fmt.Errorf("StockSnapshotService.Export: %w", err)
+	}
+	return nil
+}
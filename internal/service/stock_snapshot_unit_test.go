@@ -0,0 +1,59 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockSnapshotService_Unit_SnapshotDays(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	now := time.Date(2026, 3, 14, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	// Without snapshots only the previous day is snapshotted
+	repo := mocks.NewMockStockSnapshotRepository(t)
+	repo.EXPECT().LastDay(mock.Anything).Return(time.Time{}, nil)
+	repo.EXPECT().Snapshot(mock.Anything, day(13)).Return(3, nil).Once()
+	days, err := service.NewStockSnapshotService(repo, discardLogger{}).SnapshotDays(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, days)
+
+	// Days missed since the last snapshot are caught up
+	repo = mocks.NewMockStockSnapshotRepository(t)
+	repo.EXPECT().LastDay(mock.Anything).Return(day(11), nil)
+	repo.EXPECT().Snapshot(mock.Anything, day(12)).Return(3, nil).Once()
+	repo.EXPECT().Snapshot(mock.Anything, day(13)).Return(0, errors.New("connection reset")).Once()
+	days, err = service.NewStockSnapshotService(repo, discardLogger{}).SnapshotDays(ctx, now)
+	assert.Error(t, err)
+	assert.Equal(t, 1, days)
+
+	// The previous day is snapshotted once movements committing late had time to complete
+	repo = mocks.NewMockStockSnapshotRepository(t)
+	repo.EXPECT().LastDay(mock.Anything).Return(day(12), nil)
+	days, err = service.NewStockSnapshotService(repo, discardLogger{}).SnapshotDays(ctx, day(14).Add(time.Minute))
+	require.NoError(t, err)
+	assert.Zero(t, days)
+}
+
+func TestStockSnapshotService_Unit_Export(t *testing.T) {
+	repo := mocks.NewMockStockSnapshotRepository(t)
+	svc := service.NewStockSnapshotService(repo, discardLogger{})
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	err := svc.Export(context.Background(), from, from.AddDate(0, 0, -1), nil, nil)
+	assert.ErrorIs(t, err, service.ErrInvalidSnapshotWindow)
+	err = svc.Export(context.Background(), from, from.AddDate(0, 0, 366), nil, nil)
+	assert.ErrorIs(t, err, service.ErrInvalidSnapshotWindow, "at most 366 days")
+
+	repo.EXPECT().Export(mock.Anything, from, from.AddDate(0, 0, 365), (*uuid.UUID)(nil), mock.Anything).Return(nil)
+	assert.NoError(t, svc.Export(context.Background(), from, from.AddDate(0, 0, 365), nil, nil))
+}
@@ -0,0 +1,201 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/event"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"product-api/internal/repository/postgres"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"product-api/internal/testutil/testdb"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/suite"
+)
+
+type StockServiceTestSuite struct {
+	suite.Suite
+	dbpool      *pgxpool.Pool
+	productRepo repository.ProductRepository
+	changes     *[]event.ProductStockChanged
+	service     *service.StockService
+}
+
+func (s *StockServiceTestSuite) SetupTest() {
+	s.dbpool = testdb.New(s.T())
+	s.productRepo = postgres.NewProductRepository(s.dbpool)
+	events := event.NewBus(logger.NewSlogAdapter("local"))
+	s.changes = recordStockChanges(events)
+	s.service = service.NewStockService(postgres.NewStockRepository(s.dbpool), events, logger.NewSlogAdapter("local"))
+}
+
+func (s *StockServiceTestSuite) TestRecordMovement() {
+	ctx := context.Background()
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(10))
+
+	_, err := s.service.RecordMovement(ctx, service.RecordMovementInput{
+		ProductID: product.ID, Delta: 5, Reason: domain.StockReasonReceipt, Note: "delivery",
+	})
+	s.Require().NoError(err)
+	_, err = s.service.RecordMovement(ctx, service.RecordMovementInput{
+		ProductID: product.ID, Delta: -3, Reason: domain.StockReasonAdjustment,
+	})
+	s.Require().NoError(err)
+
+	updated, err := s.productRepo.FindByID(ctx, product.ID)
+	s.Require().NoError(err)
+	s.Equal(12, updated.Quantity)
+
+	// Initial stock is recorded as a receipt when the product is created
+	movements, err := s.service.History(ctx, product.ID, 10)
+	s.Require().NoError(err)
+	s.Require().Len(movements, 3)
+	s.Equal(-3, movements[0].Delta)
+	s.Equal(domain.StockReasonReceipt, movements[2].Reason)
+	s.Equal(10, movements[2].Delta)
+}
+
+func (s *StockServiceTestSuite) TestRecordMovement_Rejected() {
+	ctx := context.Background()
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(2))
+
+	_, err := s.service.RecordMovement(ctx, service.RecordMovementInput{
+		ProductID: product.ID, Delta: -3, Reason: domain.StockReasonAdjustment,
+	})
+	s.ErrorIs(err, service.ErrInsufficientStock)
+
+	_, err = s.service.RecordMovement(ctx, service.RecordMovementInput{
+		ProductID: product.ID, Delta: -1, Reason: domain.StockReasonAllocation,
+	})
+	s.ErrorIs(err, service.ErrInvalidStockMovement)
+
+	_, err = s.service.RecordMovement(ctx, service.RecordMovementInput{
+		ProductID: factory.NewProduct().ID, Delta: 1, Reason: domain.StockReasonReceipt,
+	})
+	s.ErrorIs(err, service.ErrProductNotFound)
+}
+
+func (s *StockServiceTestSuite) TestMovementsAreAppendOnly() {
+	product := factory.CreateProduct(s.T(), s.productRepo)
+
+	_, err := s.dbpool.Exec(context.Background(), `DELETE FROM stock_movements WHERE product_id = $1`, product.ID)
+	s.Error(err)
+}
+
+func (s *StockServiceTestSuite) TestCheckDrift() {
+	ctx := context.Background()
+	product := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(10))
+	factory.CreateProduct(s.T(), s.productRepo)
+
+	// Simulate a quantity change that bypassed the ledger
+	_, err := s.dbpool.Exec(ctx, `UPDATE products SET quantity = 7 WHERE id = $1`, product.ID)
+	s.Require().NoError(err)
+
+	report, err := s.service.CheckDrift(ctx, 100, false)
+	s.Require().NoError(err)
+	s.Require().Len(report.Drifts, 1)
+	s.Equal(domain.StockDrift{ProductID: product.ID, CachedQuantity: 7, LedgerQuantity: 10}, report.Drifts[0])
+	s.Zero(report.Rebuilt)
+
+	report, err = s.service.CheckDrift(ctx, 100, true)
+	s.Require().NoError(err)
+	s.Equal(1, report.Rebuilt)
+
+	rebuilt, err := s.productRepo.FindByID(ctx, product.ID)
+	s.Require().NoError(err)
+	s.Equal(10, rebuilt.Quantity)
+	s.Equal([]event.ProductStockChanged{{ProductID: product.ID, Delta: 3, Reason: event.StockReasonRebuild}}, *s.changes)
+}
+
+func (s *StockServiceTestSuite) TestImport() {
+	ctx := context.Background()
+	counted := factory.CreateProduct(s.T(), s.productRepo, factory.WithBarcode("4006381333931"), factory.WithQuantity(10))
+	unchanged := factory.CreateProduct(s.T(), s.productRepo, factory.WithBarcode("96385074"), factory.WithQuantity(5))
+
+	rows, err := service.ParseStockImport(strings.NewReader("sku,quantity\n4006381333931,25\n96385074,5\n12345670,1\n"))
+	s.Require().NoError(err)
+	report, err := s.service.Import(ctx, rows, service.StockImportAbsolute)
+	s.Require().NoError(err)
+	s.Equal(1, report.Updated)
+	s.Equal(1, report.Unchanged)
+	s.Equal(1, report.Failed)
+	s.Equal(service.StockImportResult{
+		Line: 2, SKU: "4006381333931", ProductID: &counted.ID, Previous: 10, Quantity: 25, Status: service.StockImportUpdated,
+	}, report.Results[0])
+	s.Equal(&unchanged.ID, report.Results[1].ProductID)
+	s.Equal("unknown SKU", report.Results[2].Error)
+	s.Equal([]event.ProductStockChanged{{ProductID: counted.ID, Delta: 15, Reason: domain.StockReasonAdjustment}}, *s.changes,
+		"changes are published once committed")
+
+	// Deltas that would make the quantity negative are not applied
+	report, err = s.service.Import(ctx, []service.StockImportRow{
+		{Line: 2, SKU: "4006381333931", Quantity: -5},
+		{Line: 3, SKU: "96385074", Quantity: -6},
+	}, service.StockImportDelta)
+	s.Require().NoError(err)
+	s.Equal(1, report.Updated)
+	s.Equal(service.StockImportFailed, report.Results[1].Status)
+
+	for id, want := range map[uuid.UUID]int{counted.ID: 20, unchanged.ID: 5} {
+		product, err := s.productRepo.FindByID(ctx, id)
+		s.Require().NoError(err)
+		s.Equal(want, product.Quantity)
+	}
+
+	// Imports are recorded in the ledger
+	drift, err := s.service.CheckDrift(ctx, 100, false)
+	s.Require().NoError(err)
+	s.Empty(drift.Drifts)
+	movements, err := s.service.History(ctx, counted.ID, 10)
+	s.Require().NoError(err)
+	s.Require().Len(movements, 3)
+	s.Equal(-5, movements[0].Delta)
+	s.Equal(domain.StockReasonAdjustment, movements[0].Reason)
+}
+
+func (s *StockServiceTestSuite) TestSnapshot() {
+	ctx := context.Background()
+	snapshots := postgres.NewStockSnapshotRepository(s.dbpool)
+	sold := factory.CreateProduct(s.T(), s.productRepo, factory.WithQuantity(10))
+	_, err := s.service.RecordMovement(ctx, service.RecordMovementInput{ProductID: sold.ID, Delta: 5, Reason: domain.StockReasonReceipt})
+	s.Require().NoError(err)
+	_, err = s.service.RecordMovement(ctx, service.RecordMovementInput{ProductID: sold.ID, Delta: -15, Reason: domain.StockReasonAdjustment})
+	s.Require().NoError(err)
+
+	// The current day is snapshotted so far, the product is out of stock since the last movement
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	taken, err := snapshots.Snapshot(ctx, today)
+	s.Require().NoError(err)
+	s.Equal(1, taken)
+	taken, err = snapshots.Snapshot(ctx, today)
+	s.Require().NoError(err)
+	s.Zero(taken, "products are snapshotted once a day")
+
+	last, err := snapshots.LastDay(ctx)
+	s.Require().NoError(err)
+	s.True(today.Equal(last))
+
+	var exported []domain.StockSnapshot
+	err = snapshots.Export(ctx, today, today, &sold.ID, func(snapshot *domain.StockSnapshot) error {
+		exported = append(exported, *snapshot)
+		return nil
+	})
+	s.Require().NoError(err)
+	s.Require().Len(exported, 1)
+	s.Equal(0, exported[0].Quantity)
+	s.Equal(15, exported[0].Received, "initial quantity and receipt")
+	s.Equal(-15, exported[0].Adjusted)
+	s.Zero(exported[0].Sold)
+	s.Greater(exported[0].StockoutSeconds, 0)
+}
+
+func TestStockServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(StockServiceTestSuite))
+}
@@ -0,0 +1,73 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/event"
+	"product-api/internal/repository"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordStockChanges subscribes to stock changes published to the bus and returns them.
+func recordStockChanges(bus *event.Bus) *[]event.ProductStockChanged {
+	var changes []event.ProductStockChanged
+	event.Subscribe(bus, "test", func(_ context.Context, e event.ProductStockChanged) error {
+		changes = append(changes, e)
+		return nil
+	})
+	return &changes
+}
+
+func TestCheckDrift_Unit_PublishesRebuilds(t *testing.T) {
+	repo := mocks.NewMockStockRepository(t)
+	bus := event.NewBus(discardLogger{})
+	changes := recordStockChanges(bus)
+	svc := service.NewStockService(repo, bus, discardLogger{})
+	drifted, settled, failed := uuid.New(), uuid.New(), uuid.New()
+
+	repo.EXPECT().FindDrift(mock.Anything, 10).Return([]domain.StockDrift{
+		{ProductID: drifted, CachedQuantity: 7, LedgerQuantity: 10},
+		{ProductID: settled, CachedQuantity: 4, LedgerQuantity: 5}, // Caught up by a movement before the rebuild
+		{ProductID: failed, CachedQuantity: 1, LedgerQuantity: 0},
+	}, nil)
+	repo.EXPECT().RebuildQuantity(mock.Anything, drifted).Return(3, nil)
+	repo.EXPECT().RebuildQuantity(mock.Anything, settled).Return(0, nil)
+	repo.EXPECT().RebuildQuantity(mock.Anything, failed).Return(0, repository.ErrProductNotFound)
+
+	report, err := svc.CheckDrift(context.Background(), 10, true)
+	require.ErrorIs(t, err, repository.ErrProductNotFound)
+	assert.Equal(t, 2, report.Rebuilt)
+	assert.Equal(t, []event.ProductStockChanged{
+		{ProductID: drifted, Delta: 3, Reason: event.StockReasonRebuild},
+	}, *changes, "only changed quantities are published")
+}
+
+func TestImport_Unit_PublishesUpdates(t *testing.T) {
+	repo := mocks.NewMockStockRepository(t)
+	bus := event.NewBus(discardLogger{})
+	changes := recordStockChanges(bus)
+	svc := service.NewStockService(repo, bus, discardLogger{})
+	counted, unchanged := uuid.New(), uuid.New()
+
+	repo.EXPECT().ImportQuantities(mock.Anything, mock.Anything, true, mock.Anything).Return([]repository.StockImportChange{
+		{Barcode: "4006381333931", ProductID: counted, Previous: 10, Quantity: 25},
+		{Barcode: "96385074", ProductID: unchanged, Previous: 5, Quantity: 5},
+	}, nil)
+
+	_, err := svc.Import(context.Background(), []service.StockImportRow{
+		{Line: 2, SKU: "4006381333931", Quantity: 25},
+		{Line: 3, SKU: "96385074", Quantity: 5},
+	}, service.StockImportAbsolute)
+
+	require.NoError(t, err)
+	assert.Equal(t, []event.ProductStockChanged{
+		{ProductID: counted, Delta: 15, Reason: domain.StockReasonAdjustment},
+	}, *changes)
+}
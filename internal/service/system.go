@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"product-api/internal/notification"
+	"product-api/internal/repository"
+	"product-api/internal/storage"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// dependencyCheckTimeout bounds each dependency check, so a hanging dependency cannot block the status.
+const dependencyCheckTimeout = 2 * time.Second
+
+// storageProbeKey is looked up to check the object storage is reachable; it does not have to exist.
+const storageProbeKey = "system/status-probe"
+
+// Status values of the system and its dependencies.
+const (
+	SystemStatusOK       = "ok"
+	SystemStatusDegraded = "degraded" // At least one dependency check failed or a background runner stalled
+	SystemStatusFailed   = "failed"   // Status of a failed dependency
+)
+
+// DatabasePool is a database connection pool whose health and usage are reported.
+type DatabasePool interface {
+	Ping(ctx context.Context) error
+	Stat() *pgxpool.Stat
+}
+
+// SystemStatus is a snapshot of the state of the application for on-call diagnosis.
+type SystemStatus struct {
+	Status       string // ok, or degraded if a dependency check failed or a background runner stalled
+	CheckedAt    time.Time
+	StartedAt    time.Time
+	Dependencies []DependencyStatus
+	Pools        []PoolStatus
+	Queues       []QueueStatus
+	Workers      []WorkerStatus // Background runners of the instance, none outside the primary region
+	Templates    notification.TemplatesStatus
+}
+
+// DependencyStatus is the result of checking a dependency.
+type DependencyStatus struct {
+	Name      string `example:"database:primary"`
+	Status    string `example:"ok"`
+	LatencyMs int64  `example:"3"`
+	Error     string // Reason of a failed check
+}
+
+// PoolStatus contains usage statistics of a database connection pool.
+// Acquire counters are cumulative since the application started.
+type PoolStatus struct {
+	Name                 string `example:"primary"`
+	MaxConns             int32
+	TotalConns           int32
+	AcquiredConns        int32 // Connections in use
+	IdleConns            int32
+	ConstructingConns    int32
+	AcquireCount         int64
+	EmptyAcquireCount    int64 // Acquires that waited for a connection, growing quickly when the pool is exhausted
+	CanceledAcquireCount int64 // Acquires cancelled while waiting, e.g. by request timeouts
+	AcquireDurationMs    int64 // Total time spent acquiring connections
+}
+
+// QueueStatus contains the backlog of work waiting in a queue.
+type QueueStatus struct {
+	Name     string `example:"refund_requests"`
+	Depth    int
+	OldestAt *time.Time // Time the oldest waiting item was queued, nil if the queue is empty
+}
+
+// SystemService reports the state of the application and its dependencies.
+type SystemService struct {
+	pools     map[string]DatabasePool
+	refunds   repository.RefundRequestRepository
+	objects   storage.Storage
+	templates *notification.Templates
+	workers   *Workers
+	startedAt time.Time
+}
+
+// NewSystemService creates a new system service reporting the pools by name and the heartbeats of the workers.
+func NewSystemService(pools map[string]DatabasePool, refunds repository.RefundRequestRepository, objects storage.Storage, templates *notification.Templates, workers *Workers) *SystemService {
+	return &SystemService{pools: pools, refunds: refunds, objects: objects, templates: templates, workers: workers, startedAt: time.Now()}
+}
+
+// Status checks the dependencies concurrently and collects pool statistics, queue backlogs and worker heartbeats.
+// Failed dependency checks and stalled workers degrade the status instead of failing it; only failures to read
+// the queues are returned.
+func (s *SystemService) Status(ctx context.Context) (*SystemStatus, error) {
+	status := &SystemStatus{
+		Status:       SystemStatusOK,
+		CheckedAt:    time.Now(),
+		StartedAt:    s.startedAt,
+		Dependencies: s.checkDependencies(ctx),
+		Templates:    s.templates.Status(),
+	}
+	status.Workers = s.workers.Status(status.CheckedAt)
+	for _, dep := range status.Dependencies {
+		if dep.Status != SystemStatusOK {
+			status.Status = SystemStatusDegraded
+		}
+	}
+	for _, worker := range status.Workers {
+		if worker.Stalled {
+			status.Status = SystemStatusDegraded
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(s.pools)) {
+		stat := s.pools[name].Stat()
+		status.Pools = append(status.Pools, PoolStatus{
+			Name:                 name,
+			MaxConns:             stat.MaxConns(),
+			TotalConns:           stat.TotalConns(),
+			AcquiredConns:        stat.AcquiredConns(),
+			IdleConns:            stat.IdleConns(),
+			ConstructingConns:    stat.ConstructingConns(),
+			AcquireCount:         stat.AcquireCount(),
+			EmptyAcquireCount:    stat.EmptyAcquireCount(),
+			CanceledAcquireCount: stat.CanceledAcquireCount(),
+			AcquireDurationMs:    stat.AcquireDuration().Milliseconds(),
+		})
+	}
+
+	pending, oldest, err := s.refunds.CountPending(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("SystemService.Status: pending refund requests: %w", err)
+	}
+	status.Queues = append(status.Queues, QueueStatus{Name: "refund_requests", Depth: pending, OldestAt: oldest})
+
+	return status, nil
+}
+
+// checkDependencies pings the database pools and the object storage concurrently.
+func (s *SystemService) checkDependencies(ctx context.Context) []DependencyStatus {
+	checks := make(map[string]func(context.Context) error, len(s.pools)+1)
+	for name, pool := range s.pools {
+		checks["database:"+name] = pool.Ping
+	}
+	checks["storage:"+s.objects.Name()] = func(ctx context.Context) error {
+		if _, err := s.objects.Stat(ctx, storageProbeKey); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+		return nil
+	}
+
+	names := slices.Sorted(maps.Keys(checks))
+	deps := make([]DependencyStatus, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Go(func() {
+			ctx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := checks[name](ctx)
+			deps[i] = DependencyStatus{Name: name, Status: SystemStatusOK, LatencyMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				deps[i].Status, deps[i].Error = SystemStatusFailed, err.Error()
+			}
+		})
+	}
+	wg.Wait()
+	return deps
+}
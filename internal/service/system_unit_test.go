@@ -0,0 +1,62 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/notification"
+	"product-api/internal/repository/mocks"
+	"product-api/internal/service"
+	"product-api/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePool is an unconnected database pool answering pings with err.
+type fakePool struct {
+	*pgxpool.Pool
+	err error
+}
+
+func (p fakePool) Ping(context.Context) error { return p.err }
+
+func newFakePool(t *testing.T, err error) fakePool {
+	pool, poolErr := pgxpool.New(context.Background(), "postgres://localhost:1/unused")
+	require.NoError(t, poolErr)
+	t.Cleanup(pool.Close)
+	return fakePool{Pool: pool, err: err}
+}
+
+func TestSystemService_Unit_Status(t *testing.T) {
+	refunds := mocks.NewMockRefundRequestRepository(t)
+	objects, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	templates := notification.NewTemplates(mocks.NewMockNotificationTemplateRepository(t), time.Hour, discardLogger{})
+	pools := map[string]service.DatabasePool{
+		"primary":   newFakePool(t, nil),
+		"reporting": newFakePool(t, errors.New("connection refused")),
+	}
+	svc := service.NewSystemService(pools, refunds, objects, templates, service.NewWorkers())
+
+	oldest := time.Now().Add(-time.Hour)
+	refunds.EXPECT().CountPending(context.Background()).Return(2, &oldest, nil)
+
+	status, err := svc.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, service.SystemStatusDegraded, status.Status)
+	require.Len(t, status.Dependencies, 3)
+	assert.Equal(t, "database:primary", status.Dependencies[0].Name)
+	assert.Equal(t, service.SystemStatusOK, status.Dependencies[0].Status)
+	assert.Equal(t, "database:reporting", status.Dependencies[1].Name)
+	assert.Equal(t, service.SystemStatusFailed, status.Dependencies[1].Status)
+	assert.Equal(t, "connection refused", status.Dependencies[1].Error)
+	assert.Equal(t, "storage:local", status.Dependencies[2].Name)
+	assert.Equal(t, service.SystemStatusOK, status.Dependencies[2].Status, "missing probe object means the storage is reachable")
+	require.Len(t, status.Pools, 2)
+	assert.Equal(t, "primary", status.Pools[0].Name)
+	assert.Equal(t, []service.QueueStatus{{Name: "refund_requests", Depth: 2, OldestAt: &oldest}}, status.Queues)
+	assert.True(t, status.Templates.LoadedAt.IsZero())
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrTagNotFound is returned when no product has the tag being renamed or merged.
+	ErrTagNotFound = errors.New("tag not found")
+	// ErrInvalidTag is returned when a tag name is empty or a tag would be merged into itself.
+	ErrInvalidTag = errors.New("invalid tag")
+)
+
+// TagService manages product tags across the catalog.
+// Renames and merges are recorded in the history of every changed product.
+type TagService struct {
+	db        repository.TxBeginner
+	repo      repository.TagRepository
+	revisions repository.ProductRevisionRepository
+	logger    logger.Logger
+}
+
+// NewTagService creates a new tag service.
+func NewTagService(db repository.TxBeginner, repo repository.TagRepository, revisions repository.ProductRevisionRepository, logger logger.Logger) *TagService {
+	return &TagService{db: db, repo: repo, revisions: revisions, logger: logger}
+}
+
+// TagUpdateResult contains the products changed by a tag rename or merge.
+type TagUpdateResult struct {
+	Tag             string
+	UpdatedProducts []uuid.UUID
+}
+
+// List returns all tags in use with the number of products that have them.
+func (s *TagService) List(ctx context.Context) ([]domain.TagCount, error) {
+	tags, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("TagService.List: %w", err)
+	}
+	if tags == nil {
+		tags = []domain.TagCount{}
+	}
+	return tags, nil
+}
+
+// Rename renames the tag in all products on behalf of actor. Products that already have the new name keep it once.
+// Returns ErrTagNotFound if no product has the tag.
+func (s *TagService) Rename(ctx context.Context, actor, from, to string) (*TagUpdateResult, error) {
+	return s.merge(ctx, "TagService.Rename", actor, []string{from}, to)
+}
+
+// Merge replaces the source tags with the target tag in all products on behalf of actor.
+// Returns ErrTagNotFound if no product has any of the source tags.
+func (s *TagService) Merge(ctx context.Context, actor string, sources []string, target string) (*TagUpdateResult, error) {
+	return s.merge(ctx, "TagService.Merge", actor, sources, target)
+}
+
+func (s *TagService) merge(ctx context.Context, op, actor string, sources []string, target string) (_ *TagUpdateResult, err error) {
+	target = strings.TrimSpace(target)
+	if target == "" || len(sources) == 0 || slices.Contains(sources, target) || slices.Contains(sources, "") {
+		return nil, ErrInvalidTag
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: could not begin transaction: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	changes, err := s.repo.MergeTx(ctx, tx, sources, target)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(changes) == 0 {
+		return nil, ErrTagNotFound
+	}
+
+	now := time.Now()
+	ids := make([]uuid.UUID, len(changes))
+	revisions := make([]domain.ProductRevision, len(changes))
+	for i, c := range changes {
+		ids[i] = c.ProductID
+		revisions[i] = domain.ProductRevision{
+			ID:        uuid.New(),
+			ProductID: c.ProductID,
+			Actor:     actor,
+			ChangedAt: now,
+			Changes:   []domain.FieldDiff{{Field: domain.ProductFieldTags, Before: c.Before, After: c.After}},
+		}
+	}
+	if err = s.revisions.AppendTx(ctx, tx, revisions); err != nil {
+		return nil, fmt.Errorf("%s: record product history: %w", op, err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: could not commit transaction: %w", op, err)
+	}
+
+	s.logger.Info("products retagged", "op", op, "sources", sources, "target", target, "products", len(ids))
+	return &TagUpdateResult{Tag: target, UpdatedProducts: ids}, nil
+}
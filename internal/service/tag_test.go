@@ -0,0 +1,91 @@
+package service_test
+
+import (
+	"context"
+	"product-api/internal/domain"
+	"product-api/internal/logger"
+	"product-api/internal/repository"
+	"product-api/internal/repository/postgres"
+	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"product-api/internal/testutil/testdb"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+const tagActor = "client:admin"
+
+type TagServiceTestSuite struct {
+	suite.Suite
+	productRepo repository.ProductRepository
+	revisions   repository.ProductRevisionRepository
+	service     *service.TagService
+}
+
+func (s *TagServiceTestSuite) SetupTest() {
+	dbpool := testdb.New(s.T())
+	s.productRepo = postgres.NewProductRepository(dbpool)
+	s.revisions = postgres.NewProductRevisionRepository(dbpool)
+	s.service = service.NewTagService(dbpool, postgres.NewTagRepository(dbpool), s.revisions, logger.NewSlogAdapter("local"))
+}
+
+func (s *TagServiceTestSuite) TestList() {
+	factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("audio", "sale"))
+	factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("audio"))
+
+	tags, err := s.service.List(context.Background())
+
+	s.Require().NoError(err)
+	s.Equal([]domain.TagCount{{Name: "audio", Products: 2}, {Name: "sale", Products: 1}}, tags)
+}
+
+func (s *TagServiceTestSuite) TestMerge() {
+	ctx := context.Background()
+	both := factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("headphones", "sale", "audio"))
+	one := factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("new", "speakers"))
+	other := factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("sale"))
+
+	result, err := s.service.Merge(ctx, tagActor, []string{"headphones", "speakers"}, "audio")
+
+	s.Require().NoError(err)
+	s.ElementsMatch([]uuid.UUID{both.ID, one.ID}, result.UpdatedProducts)
+	s.assertTags(both, "audio", "sale")
+	s.assertTags(one, "new", "audio")
+	s.assertTags(other, "sale")
+
+	revisions, err := s.revisions.FindByProductID(ctx, both.ID, 10)
+	s.Require().NoError(err)
+	s.Require().Len(revisions, 1)
+	s.Equal(tagActor, revisions[0].Actor)
+	s.Equal([]domain.FieldDiff{{
+		Field:  domain.ProductFieldTags,
+		Before: []any{"headphones", "sale", "audio"},
+		After:  []any{"audio", "sale"},
+	}}, revisions[0].Changes)
+}
+
+func (s *TagServiceTestSuite) TestRename_Errors() {
+	ctx := context.Background()
+	factory.CreateProduct(s.T(), s.productRepo, factory.WithTags("audio"))
+
+	_, err := s.service.Rename(ctx, tagActor, "video", "media")
+	s.ErrorIs(err, service.ErrTagNotFound)
+
+	_, err = s.service.Rename(ctx, tagActor, "audio", "audio")
+	s.ErrorIs(err, service.ErrInvalidTag)
+
+	_, err = s.service.Rename(ctx, tagActor, "audio", " ")
+	s.ErrorIs(err, service.ErrInvalidTag)
+}
+
+func (s *TagServiceTestSuite) assertTags(product *domain.Product, tags ...string) {
+	updated, err := s.productRepo.FindByID(context.Background(), product.ID)
+	s.Require().NoError(err)
+	s.Equal(tags, updated.Tags)
+}
+
+func TestTagServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TagServiceTestSuite))
+}
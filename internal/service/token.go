@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidToken is returned when a token is malformed, has an invalid signature or is expired.
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// TokenClaims contains the claims of a JWT access token issued by UsersService.
+type TokenClaims struct {
+	ID        uuid.UUID // jti
+	UserID    uuid.UUID // sub
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	Roles     []string // Roles of the user at login
+}
+
+// HasRole reports whether the token grants any of the roles.
+func (c *TokenClaims) HasRole(roles ...string) bool {
+	return slices.ContainsFunc(c.Roles, func(role string) bool { return slices.Contains(roles, role) })
+}
+
+// IsCurrentFor reports whether the token carries the current role of the user and was issued after
+// the user last changed their password. Tokens issued before a role change are no longer accepted,
+// so revoked roles cannot be used, nor are tokens issued before a password change, so a stolen token dies with the password.
+func (c *TokenClaims) IsCurrentFor(user *domain.User) bool {
+	if user.PasswordChangedAt != nil && c.IssuedAt.Before(*user.PasswordChangedAt) {
+		return false
+	}
+	return slices.Equal(c.Roles, []string{user.Role})
+}
+
+// TokenService provides token introspection and revocation.
+type TokenService struct {
+	repo     repository.TokenRepository
+	userRepo repository.UserRepository
+	keys     *TokenKeys
+}
+
+// NewTokenService creates a new token service verifying tokens with keys.
+func NewTokenService(repo repository.TokenRepository, userRepo repository.UserRepository, keys *TokenKeys) *TokenService {
+	return &TokenService{repo: repo, userRepo: userRepo, keys: keys}
+}
+
+// Parse validates the token signature and expiration and returns its claims.
+// Returns ErrInvalidToken if the token cannot be trusted.
+func (s *TokenService) Parse(tokenString string) (*TokenClaims, error) {
+	token, err := s.keys.parse(tokenString)
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	sub, err := claims.GetSubject()
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	userID, err := uuid.Parse(sub)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	result := &TokenClaims{UserID: userID}
+	if jti, ok := claims["jti"].(string); ok {
+		// Tokens issued before revocation support have no jti and cannot be revoked individually
+		result.ID, _ = uuid.Parse(jti)
+	}
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		result.IssuedAt = iat.Time
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		result.ExpiresAt = exp.Time
+	}
+	if roles, ok := claims["roles"].([]any); ok {
+		for _, role := range roles {
+			if role, ok := role.(string); ok {
+				result.Roles = append(result.Roles, role)
+			}
+		}
+	} else {
+		// Tokens issued before roles were introduced belong to customers
+		result.Roles = []string{domain.RoleCustomer}
+	}
+
+	return result, nil
+}
+
+// PublicKeys returns the public keys other services validate tokens with, empty if tokens are signed
+// with the shared secret.
+func (s *TokenService) PublicKeys() []JWK {
+	return s.keys.PublicKeys()
+}
+
+// IsRevoked reports whether the token with the given claims was revoked.
+func (s *TokenService) IsRevoked(ctx context.Context, claims *TokenClaims) (bool, error) {
+	if claims.ID == uuid.Nil {
+		return false, nil
+	}
+	return s.repo.IsRevoked(ctx, claims.ID)
+}
+
+// Introspect reports whether the token is currently active: correctly signed, not expired,
+// not revoked, and issued to an existing active user with their current role after their last password change.
+// Returns the token claims and false for inactive tokens; only internal failures are returned as errors.
+func (s *TokenService) Introspect(ctx context.Context, tokenString string) (*TokenClaims, bool, error) {
+	const op = "TokenService.Introspect"
+
+	claims, err := s.Parse(tokenString)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	revoked, err := s.IsRevoked(ctx, claims)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+	if revoked {
+		return claims, false, nil
+	}
+
+	// Tokens of deleted or disabled accounts, or issued before a role or password change, are no longer active
+	user, err := s.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return claims, false, nil
+		}
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return claims, user.IsActive && claims.IsCurrentFor(user), nil
+}
+
+// Revoke revokes the token so it is no longer accepted (RFC 7009).
+// Invalid or already expired tokens are ignored.
+func (s *TokenService) Revoke(ctx context.Context, tokenString string) error {
+	claims, err := s.Parse(tokenString)
+	if err != nil {
+		return nil
+	}
+	return s.RevokeClaims(ctx, claims)
+}
+
+// RevokeClaims revokes the token with the given parsed claims.
+func (s *TokenService) RevokeClaims(ctx context.Context, claims *TokenClaims) error {
+	if claims.ID == uuid.Nil {
+		return nil
+	}
+	if err := s.repo.Revoke(ctx, claims.ID, claims.UserID, claims.ExpiresAt); err != nil {
+		return fmt.Errorf("TokenService.RevokeClaims: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Access token signing algorithms.
+const (
+	TokenAlgHS256 = "HS256" // HMAC-SHA256 with the shared JWT secret
+	TokenAlgRS256 = "RS256" // RSA PKCS #1 v1.5 with SHA-256, at least 2048 bit keys
+	TokenAlgEdDSA = "EdDSA" // Ed25519
+)
+
+// minRSAKeyBits is the minimum size of RSA keys signing tokens.
+const minRSAKeyBits = 2048
+
+// JWK is a public key of access tokens in JSON Web Key format (RFC 7517), for services validating tokens themselves.
+type JWK struct {
+	KeyType   string `json:"kty" example:"RSA"` // RSA or OKP
+	Use       string `json:"use" example:"sig"`
+	Algorithm string `json:"alg" example:"RS256"`
+	KeyID     string `json:"kid" example:"NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"`
+	N         string `json:"n,omitempty"`                     // RSA modulus
+	E         string `json:"e,omitempty" example:"AQAB"`      // RSA public exponent
+	Curve     string `json:"crv,omitempty" example:"Ed25519"` // OKP curve
+	X         string `json:"x,omitempty"`                     // OKP public key
+}
+
+// TokenKeys are the keys access tokens are signed and verified with. Tokens are signed with HS256 and the shared
+// secret, or with an RSA or Ed25519 private key whose public key other services fetch as a JWK to validate tokens
+// without the secret. Tokens carry the ID of their key in the kid header.
+// While the secret is configured, HS256 tokens remain valid after switching to a private key,
+// so users are not logged out by the switch.
+type TokenKeys struct {
+	method  jwt.SigningMethod
+	signing crypto.PrivateKey // Nil for HS256
+	jwk     *JWK              // Public key of signing, nil for HS256
+	secret  []byte            // Shared secret of HS256 tokens, may be empty for other algorithms
+}
+
+// NewHMACTokenKeys returns keys signing and verifying HS256 tokens with the shared secret.
+func NewHMACTokenKeys(secret []byte) *TokenKeys {
+	return &TokenKeys{method: jwt.SigningMethodHS256, secret: secret}
+}
+
+// NewTokenKeys returns keys signing tokens with the algorithm: HS256 with secret, or RS256 and EdDSA with
+// the PEM encoded RSA or Ed25519 private key (PKCS #8, or PKCS #1 for RSA). HS256 tokens are also verified
+// with secret if it is not empty. Returns an error for unknown algorithms and keys not matching the algorithm.
+func NewTokenKeys(alg string, privateKeyPEM, secret []byte) (*TokenKeys, error) {
+	if alg == TokenAlgHS256 {
+		if len(secret) == 0 {
+			return nil, errors.New("HS256 requires a secret")
+		}
+		return NewHMACTokenKeys(secret), nil
+	}
+
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("%s requires a PEM encoded private key", alg)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		var pkcs1Err error
+		if key, pkcs1Err = x509.ParsePKCS1PrivateKey(block.Bytes); pkcs1Err != nil {
+			return nil, fmt.Errorf("parse private key: %w", err)
+		}
+	}
+
+	keys := &TokenKeys{signing: key, secret: secret}
+	switch alg {
+	case TokenAlgRS256:
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("RS256 requires an RSA private key")
+		}
+		if rsaKey.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must have at least %d bits", minRSAKeyBits)
+		}
+		keys.method = jwt.SigningMethodRS256
+		keys.jwk = &JWK{
+			KeyType: "RSA",
+			N:       base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+			E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+		}
+	case TokenAlgEdDSA:
+		edKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New("EdDSA requires an Ed25519 private key")
+		}
+		keys.method = jwt.SigningMethodEdDSA
+		keys.jwk = &JWK{
+			KeyType: "OKP",
+			Curve:   "Ed25519",
+			X:       base64.RawURLEncoding.EncodeToString(edKey.Public().(ed25519.PublicKey)),
+		}
+	default:
+		return nil, fmt.Errorf("unknown token signing algorithm %q, must be HS256, RS256 or EdDSA", alg)
+	}
+	keys.jwk.Use = "sig"
+	keys.jwk.Algorithm = alg
+	keys.jwk.KeyID = thumbprint(keys.jwk)
+	return keys, nil
+}
+
+// thumbprint returns the JWK thumbprint (RFC 7638) of the public key, its required members
+// in lexicographic order hashed with SHA-256.
+func thumbprint(k *JWK) string {
+	var members any
+	if k.KeyType == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.KeyType, k.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Curve, k.KeyType, k.X}
+	}
+	data, _ := json.Marshal(members) // Marshaling strings cannot fail
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Algorithm returns the algorithm tokens are signed with.
+func (k *TokenKeys) Algorithm() string {
+	return k.method.Alg()
+}
+
+// PublicKeys returns the public keys of tokens as JWKs, empty if tokens are signed with the shared secret.
+func (k *TokenKeys) PublicKeys() []JWK {
+	if k.jwk == nil {
+		return []JWK{}
+	}
+	return []JWK{*k.jwk}
+}
+
+// sign returns the token with the claims signed with the signing key.
+func (k *TokenKeys) sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.jwk == nil {
+		return token.SignedString(k.secret)
+	}
+	token.Header["kid"] = k.jwk.KeyID
+	return token.SignedString(k.signing)
+}
+
+// parse validates the signature and expiration of the token. HS256 tokens are verified with the shared secret,
+// others with the public key of the signing key.
+func (k *TokenKeys) parse(tokenString string) (*jwt.Token, error) {
+	methods := []string{k.method.Alg()}
+	if k.jwk != nil && len(k.secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	return jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+		if token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+			return k.secret, nil
+		}
+		if kid, ok := token.Header["kid"].(string); ok && kid != k.jwk.KeyID {
+			return nil, fmt.Errorf("unknown key %q", kid)
+		}
+		return k.signing.(crypto.Signer).Public(), nil
+	}, jwt.WithValidMethods(methods), jwt.WithExpirationRequired())
+}
@@ -0,0 +1,108 @@
+package service_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"product-api/internal/service"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// privateKeyPEM returns the key PEM encoded in PKCS #8.
+func privateKeyPEM(t *testing.T, key any) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+// signedToken returns a token of a new user expiring in an hour, signed with key.
+func signedToken(t *testing.T, method jwt.SigningMethod, kid string, key any) string {
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
+		"sub": uuid.New().String(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestTokenKeys_Unit_EdDSA(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	secret := []byte("previous-secret")
+
+	keys, err := service.NewTokenKeys(service.TokenAlgEdDSA, privateKeyPEM(t, key), secret)
+	require.NoError(t, err)
+	jwks := keys.PublicKeys()
+	require.Len(t, jwks, 1)
+	assert.Equal(t, "OKP", jwks[0].KeyType)
+	assert.Equal(t, "Ed25519", jwks[0].Curve)
+	assert.Equal(t, "EdDSA", jwks[0].Algorithm)
+	assert.NotEmpty(t, jwks[0].KeyID)
+
+	tokens := service.NewTokenService(nil, nil, keys)
+	_, err = tokens.Parse(signedToken(t, jwt.SigningMethodEdDSA, jwks[0].KeyID, key))
+	assert.NoError(t, err)
+	_, err = tokens.Parse(signedToken(t, jwt.SigningMethodHS256, "", secret))
+	assert.NoError(t, err, "tokens signed with the secret before the switch stay valid")
+
+	_, other, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, err = tokens.Parse(signedToken(t, jwt.SigningMethodEdDSA, jwks[0].KeyID, other))
+	assert.ErrorIs(t, err, service.ErrInvalidToken)
+	_, err = tokens.Parse(signedToken(t, jwt.SigningMethodEdDSA, "unknown", key))
+	assert.ErrorIs(t, err, service.ErrInvalidToken)
+
+	// Without the secret, only tokens signed with the private key are accepted
+	keys, err = service.NewTokenKeys(service.TokenAlgEdDSA, privateKeyPEM(t, key), nil)
+	require.NoError(t, err)
+	_, err = service.NewTokenService(nil, nil, keys).Parse(signedToken(t, jwt.SigningMethodHS256, "", secret))
+	assert.ErrorIs(t, err, service.ErrInvalidToken)
+}
+
+func TestTokenKeys_Unit_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys, err := service.NewTokenKeys(service.TokenAlgRS256, privateKeyPEM(t, key), nil)
+	require.NoError(t, err)
+	jwks := keys.PublicKeys()
+	require.Len(t, jwks, 1)
+	assert.Equal(t, "RSA", jwks[0].KeyType)
+	assert.Equal(t, "AQAB", jwks[0].E)
+
+	_, err = service.NewTokenService(nil, nil, keys).Parse(signedToken(t, jwt.SigningMethodRS256, jwks[0].KeyID, key))
+	assert.NoError(t, err)
+
+	_, err = service.NewTokenKeys(service.TokenAlgEdDSA, privateKeyPEM(t, key), nil)
+	assert.Error(t, err, "key does not match the algorithm")
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	_, err = service.NewTokenKeys(service.TokenAlgRS256, privateKeyPEM(t, small), nil)
+	assert.Error(t, err, "key is too small")
+	_, err = service.NewTokenKeys("ES256", privateKeyPEM(t, key), nil)
+	assert.Error(t, err)
+}
+
+func TestTokenKeys_Unit_HS256(t *testing.T) {
+	keys, err := service.NewTokenKeys(service.TokenAlgHS256, nil, []byte("secret"))
+	require.NoError(t, err)
+	assert.Empty(t, keys.PublicKeys())
+
+	_, err = service.NewTokenService(nil, nil, keys).Parse(signedToken(t, jwt.SigningMethodHS256, "", []byte("secret")))
+	assert.NoError(t, err)
+
+	_, err = service.NewTokenKeys(service.TokenAlgHS256, nil, nil)
+	assert.Error(t, err)
+}
@@ -4,14 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/getsentry/sentry-go"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/crypto/bcrypt"
 
 	"product-api/internal/domain"
+	"product-api/internal/event"
+	"product-api/internal/logger"
+	"product-api/internal/money"
 	"product-api/internal/repository"
+	"product-api/internal/telemetry"
 )
 
 var (
@@ -21,26 +29,89 @@ var (
 	ErrUserNotFound = errors.New("user not found")
 	// ErrInvalidCredentials is returned when authentication credentials are invalid.
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrUsernameTaken is returned when attempting to register a username used by another user.
+	ErrUsernameTaken = errors.New("username already taken")
+	// ErrAccountDisabled is returned when an inactive (deprovisioned) user attempts to log in.
+	ErrAccountDisabled = errors.New("account is disabled")
+	// ErrAccountLocked is returned when a user attempts to log in with a password while the account is locked
+	// after too many failed logins, including the failure locking it.
+	ErrAccountLocked = errors.New("account is temporarily locked after too many failed logins")
+	// ErrExternalIDTaken is returned when external identity provider ID is already linked to another user.
+	ErrExternalIDTaken = errors.New("external id already linked to another user")
+	// ErrUnderage is returned when the user is younger than the required age.
+	ErrUnderage = errors.New("user must be at least 18 years old")
+	// ErrInvalidRole is returned when assigning an unknown role.
+	ErrInvalidRole = errors.New("invalid role, must be admin, manager or customer")
 )
 
 // UsersService provides business logic for user operations.
 type UsersService struct {
-	repo      repository.UserRepository
-	jwtSecret []byte
-	jwtTTL    time.Duration
+	repo         repository.UserRepository
+	loginRepo    repository.LoginAttemptRepository
+	identityRepo repository.IdentityRepository
+	tokenKeys    *TokenKeys
+	jwtTTL       time.Duration
+	lockout      LoginLockout
+	events       event.Publisher
+	logger       logger.Logger
 }
 
-// NewUsersService creates a new users service.
-func NewUsersService(repo repository.UserRepository, jwtSecret []byte, jwtTTL time.Duration) *UsersService {
-	return &UsersService{repo: repo, jwtSecret: jwtSecret, jwtTTL: jwtTTL}
+// LoginLockout locks accounts out of password logins after consecutive failures, so passwords of an account
+// cannot be guessed faster than MaxFailures per Duration. Logins through identity providers are not locked out.
+type LoginLockout struct {
+	MaxFailures int           // Consecutive failed logins locking the account, 0 disables lockouts
+	Duration    time.Duration // How long the account stays locked
+}
+
+// NewUsersService creates a new users service issuing tokens signed with tokenKeys and valid for jwtTTL.
+// Registrations are published to events.
+func NewUsersService(repo repository.UserRepository, loginRepo repository.LoginAttemptRepository, identityRepo repository.IdentityRepository, tokenKeys *TokenKeys, jwtTTL time.Duration, lockout LoginLockout, events event.Publisher, logger logger.Logger) *UsersService {
+	return &UsersService{repo: repo, loginRepo: loginRepo, identityRepo: identityRepo, tokenKeys: tokenKeys, jwtTTL: jwtTTL, lockout: lockout, events: events, logger: logger}
+}
+
+// LoginMetadata contains information about the client performing a login.
+type LoginMetadata struct {
+	IPAddress string
+	UserAgent string
+}
+
+// RegisterInput contains data for registering a new user.
+type RegisterInput struct {
+	Email     string
+	Username  string // Optional, normalized before saving
+	Password  string
+	Firstname string
+	Lastname  string
+	Birthdate time.Time
+	IsMarried bool
+	Locale    string // Optional preferred locale, e.g. "de-AT"
 }
 
 // Register registers a new user.
-// Checks that a user with this email does not already exist,
-// hashes the password and saves the user to the database.
-func (s *UsersService) Register(ctx context.Context, email, password, firstname, lastname string, age int, isMarried bool) (*domain.User, error) {
+// Checks that the user is an adult and a user with this email or username does not already exist,
+// hashes the password, saves the user to the database and publishes event.UserRegistered.
+// Returns money.ErrUnknownLocale if documents cannot be formatted in the preferred locale.
+func (s *UsersService) Register(ctx context.Context, in RegisterInput) (*domain.User, error) {
+	// Enforce the 18+ rule against the birthdate
+	if domain.AgeAt(in.Birthdate, time.Now()) < domain.AdultAge {
+		return nil, ErrUnderage
+	}
+	locale, err := supportedLocale(in.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalize and check optional username
+	var username string
+	if in.Username != "" {
+		var err error
+		if username, err = s.checkUsername(ctx, in.Username); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check if user with this email already exists
-	_, err := s.repo.FindByEmail(ctx, email)
+	_, err = s.repo.FindByEmail(ctx, in.Email)
 	if err == nil {
 		return nil, ErrUserAlreadyExists
 	}
@@ -49,7 +120,7 @@ func (s *UsersService) Register(ctx context.Context, email, password, firstname,
 	}
 
 	// Hash password before saving
-	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(in.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
 	}
@@ -57,51 +128,248 @@ func (s *UsersService) Register(ctx context.Context, email, password, firstname,
 	// Create new user
 	user := &domain.User{
 		ID:           uuid.New(),
-		Email:        email,
+		Email:        in.Email,
+		Username:     username,
 		PasswordHash: string(passwordHash),
-		Firstname:    firstname,
-		Lastname:     lastname,
-		Age:          age,
-		IsMarried:    isMarried,
+		Firstname:    in.Firstname,
+		Lastname:     in.Lastname,
+		Birthdate:    in.Birthdate,
+		IsMarried:    in.IsMarried,
+		IsActive:     true,
+		Role:         domain.RoleCustomer,
+		Locale:       locale,
 	}
 
 	// Save user to database
 	if err := s.repo.Create(ctx, user); err != nil {
-		return nil, err
+		return nil, mapUserConflict(err)
 	}
 
+	s.events.Publish(ctx, event.UserRegistered{User: user})
 	return user, nil
 }
 
+// CheckUsername reports whether the username is valid and not taken.
+// Returns the normalized username, or domain.ErrUsernameInvalid / domain.ErrUsernameReserved
+// if it violates normalization rules.
+func (s *UsersService) CheckUsername(ctx context.Context, username string) (string, bool, error) {
+	normalized, err := s.checkUsername(ctx, username)
+	if errors.Is(err, ErrUsernameTaken) {
+		return normalized, false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return normalized, true, nil
+}
+
+// checkUsername normalizes the username and checks that no user has it.
+// Returns the normalized username together with ErrUsernameTaken if it is already used.
+func (s *UsersService) checkUsername(ctx context.Context, username string) (string, error) {
+	normalized, err := domain.NormalizeUsername(username)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = s.repo.FindByUsername(ctx, normalized)
+	if err == nil {
+		return normalized, ErrUsernameTaken
+	}
+	if !errors.Is(err, repository.ErrUserNotFound) {
+		return "", err
+	}
+	return normalized, nil
+}
+
 // Login authenticates a user and returns a JWT token.
-// Validates email and password, generates JWT token on successful validation.
-func (s *UsersService) Login(ctx context.Context, email, password string) (string, error) {
+// The login may be either an email or a username.
+// Validates credentials, generates JWT token on successful validation.
+// Every attempt, successful or not, is recorded in the login history.
+func (s *UsersService) Login(ctx context.Context, login, password string, meta LoginMetadata) (_ string, err error) {
 	const op = "UsersService.Login"
 
-	// Find user by email
-	user, err := s.repo.FindByEmail(ctx, email)
+	loginMethod := "username"
+	if strings.Contains(login, "@") {
+		loginMethod = "email"
+	}
+	ctx, span := telemetry.StartSpan(ctx, op, trace.WithAttributes(attribute.String("login.method", loginMethod)))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	attempt := &domain.LoginAttempt{
+		ID:        uuid.New(),
+		Login:     login,
+		IPAddress: meta.IPAddress,
+		UserAgent: meta.UserAgent,
+		CreatedAt: time.Now(),
+	}
+
+	// Find user by email or username
+	user, err := s.findByLogin(ctx, login)
 	if err != nil {
 		if errors.Is(err, repository.ErrUserNotFound) {
-			return "", ErrInvalidCredentials
+			return "", s.recordFailedLogin(ctx, attempt, domain.LoginFailureUnknownUser, ErrInvalidCredentials)
 		}
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
+	attempt.UserID = &user.ID
+	span.SetAttributes(attribute.String("user_id", user.ID.String()))
+
+	// Locked accounts are refused before the password is checked, so guessing does not go on during the lockout
+	if user.IsLocked(attempt.CreatedAt) {
+		return "", s.recordFailedLogin(ctx, attempt, domain.LoginFailureAccountLocked, ErrAccountLocked)
+	}
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return "", ErrInvalidCredentials
+		return "", s.recordWrongPassword(ctx, user, attempt)
 	}
 
-	// Generate JWT token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub": user.ID.String(),
-		"exp": time.Now().Add(s.jwtTTL).Unix(),
-	})
+	// Deprovisioned users cannot log in even with valid credentials
+	if !user.IsActive {
+		return "", s.recordFailedLogin(ctx, attempt, domain.LoginFailureAccountDisabled, ErrAccountDisabled)
+	}
+
+	tokenString, err := s.completeLogin(ctx, user, attempt)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokenString, nil
+}
 
-	tokenString, err := token.SignedString(s.jwtSecret)
+// completeLogin generates a JWT token carrying the role of the authenticated user
+// and records the successful attempt in the login history.
+func (s *UsersService) completeLogin(ctx context.Context, user *domain.User, attempt *domain.LoginAttempt) (string, error) {
+	tokenString, err := s.issueToken(user, time.Now())
 	if err != nil {
-		return "", fmt.Errorf("%s: failed to sign token: %w", op, err)
+		return "", err
+	}
+
+	// Record successful login
+	attempt.Success = true
+	if err := s.loginRepo.Create(ctx, attempt); err != nil {
+		return "", fmt.Errorf("failed to record login attempt: %w", err)
+	}
+	if err := s.repo.UpdateLastLogin(ctx, user.ID, attempt.CreatedAt); err != nil {
+		return "", fmt.Errorf("failed to update last login: %w", err)
 	}
 
 	return tokenString, nil
 }
+
+// issueToken generates a JWT token carrying the role of the user, issued at now.
+func (s *UsersService) issueToken(user *domain.User, now time.Time) (string, error) {
+	// jti allows revoking individual tokens
+	tokenString, err := s.tokenKeys.sign(jwt.MapClaims{
+		"sub":   user.ID.String(),
+		"jti":   uuid.New().String(),
+		"iat":   now.Unix(),
+		"exp":   now.Add(s.jwtTTL).Unix(),
+		"roles": []string{user.Role},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return tokenString, nil
+}
+
+// recordFailedLogin stores a failed login attempt and returns loginErr,
+// or an internal error if the attempt could not be recorded.
+func (s *UsersService) recordFailedLogin(ctx context.Context, attempt *domain.LoginAttempt, reason string, loginErr error) error {
+	attempt.FailureReason = reason
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("login.failure_reason", reason))
+	telemetry.RecordLoginFailure(ctx, reason)
+	if err := s.loginRepo.Create(ctx, attempt); err != nil {
+		return fmt.Errorf("UsersService.Login: failed to record login attempt: %w", err)
+	}
+	return loginErr
+}
+
+// recordWrongPassword counts a login with a wrong password towards a lockout of the account and records it.
+// Returns ErrAccountLocked if the failure locked the account, ErrInvalidCredentials otherwise.
+func (s *UsersService) recordWrongPassword(ctx context.Context, user *domain.User, attempt *domain.LoginAttempt) error {
+	loginErr := ErrInvalidCredentials
+	if s.lockout.MaxFailures > 0 {
+		lockedUntil := attempt.CreatedAt.Add(s.lockout.Duration)
+		locked, err := s.repo.RecordFailedLogin(ctx, user.ID, s.lockout.MaxFailures, lockedUntil)
+		if err != nil {
+			return fmt.Errorf("UsersService.Login: failed to count failed login: %w", err)
+		}
+		if locked {
+			s.reportLockout(ctx, user, attempt, lockedUntil)
+			loginErr = ErrAccountLocked
+		}
+	}
+	return s.recordFailedLogin(ctx, attempt, domain.LoginFailureWrongPassword, loginErr)
+}
+
+// reportLockout logs a locked account and reports it to Sentry, so security can spot password guessing.
+// Events of all lockouts are grouped into one issue.
+func (s *UsersService) reportLockout(ctx context.Context, user *domain.User, attempt *domain.LoginAttempt, lockedUntil time.Time) {
+	s.logger.WithTrace(ctx).Warn("account locked after failed logins", "op", "UsersService.Login",
+		"user_id", user.ID, "ip", attempt.IPAddress, "user_agent", attempt.UserAgent,
+		"failures", s.lockout.MaxFailures, "locked_until", lockedUntil)
+
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetLevel(sentry.LevelWarning)
+		scope.SetFingerprint([]string{"account-locked"})
+		scope.SetUser(sentry.User{ID: user.ID.String(), IPAddress: attempt.IPAddress})
+		scope.SetContext("lockout", sentry.Context{
+			"failures":     s.lockout.MaxFailures,
+			"locked_until": lockedUntil.Format(time.RFC3339),
+			"user_agent":   attempt.UserAgent,
+		})
+		hub.CaptureMessage("account locked after failed logins")
+	})
+}
+
+// findByLogin finds a user by email (if login contains '@') or by normalized username.
+func (s *UsersService) findByLogin(ctx context.Context, login string) (*domain.User, error) {
+	if strings.Contains(login, "@") {
+		return s.repo.FindByEmail(ctx, login)
+	}
+
+	username, err := domain.NormalizeUsername(login)
+	if err != nil {
+		// Malformed usernames cannot belong to anyone
+		return nil, repository.ErrUserNotFound
+	}
+	return s.repo.FindByUsername(ctx, username)
+}
+
+// LoginHistory returns the most recent login attempts of the user, newest first.
+func (s *UsersService) LoginHistory(ctx context.Context, userID uuid.UUID, limit int) ([]domain.LoginAttempt, error) {
+	return s.loginRepo.FindByUserID(ctx, userID, limit)
+}
+
+// SetLocale changes the locale the user's emails and documents of later orders are written in,
+// an empty locale uses the configured one. Returns money.ErrUnknownLocale and ErrUserNotFound.
+func (s *UsersService) SetLocale(ctx context.Context, id uuid.UUID, locale string) (*domain.User, error) {
+	locale, err := supportedLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateLocale(ctx, id, locale); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("UsersService.SetLocale: %w", err)
+	}
+	return s.GetUser(ctx, id)
+}
+
+// supportedLocale returns the canonical tag of a preferred locale, keeping empty locales empty.
+// Returns money.ErrUnknownLocale if amounts and dates cannot be formatted in its language.
+func supportedLocale(locale string) (string, error) {
+	if locale == "" {
+		return "", nil
+	}
+	if _, err := money.ResolveLocale(locale); err != nil {
+		return "", err
+	}
+	return money.CanonicalTag(locale), nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrFederatedAccountNotFound is returned when a federated identity cannot be linked to any local user.
+	ErrFederatedAccountNotFound = errors.New("no local account for this identity, register first")
+)
+
+// FederatedIdentity contains identity information returned by an external identity provider.
+type FederatedIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Firstname     string
+	Lastname      string
+	Birthdate     *time.Time // Optional, required only to create a new local user
+}
+
+// FederatedLogin authenticates a user through an external identity provider and returns a JWT token.
+// The identity is resolved to a local user in this order:
+//   - an identity already linked to a user;
+//   - a user with the same (provider-verified) email, which is then linked;
+//   - a new user created from the identity claims, if they include a birthdate satisfying the 18+ rule.
+func (s *UsersService) FederatedLogin(ctx context.Context, identity FederatedIdentity, meta LoginMetadata) (string, error) {
+	const op = "UsersService.FederatedLogin"
+
+	attempt := &domain.LoginAttempt{
+		ID:        uuid.New(),
+		Login:     identity.Provider + ":" + identity.Email,
+		IPAddress: meta.IPAddress,
+		UserAgent: meta.UserAgent,
+		CreatedAt: time.Now(),
+	}
+
+	user, err := s.resolveIdentity(ctx, identity)
+	if err != nil {
+		if errors.Is(err, ErrFederatedAccountNotFound) || errors.Is(err, ErrUnderage) {
+			return "", s.recordFailedLogin(ctx, attempt, domain.LoginFailureUnknownUser, err)
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	attempt.UserID = &user.ID
+
+	if !user.IsActive {
+		return "", s.recordFailedLogin(ctx, attempt, domain.LoginFailureAccountDisabled, ErrAccountDisabled)
+	}
+
+	token, err := s.completeLogin(ctx, user, attempt)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return token, nil
+}
+
+// resolveIdentity finds, links or creates the local user for a federated identity.
+func (s *UsersService) resolveIdentity(ctx context.Context, identity FederatedIdentity) (*domain.User, error) {
+	linked, err := s.identityRepo.Find(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return s.GetUser(ctx, linked.UserID)
+	}
+	if !errors.Is(err, repository.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	// Unverified emails could be used to take over local accounts
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, ErrFederatedAccountNotFound
+	}
+
+	user, err := s.repo.FindByEmail(ctx, identity.Email)
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		if identity.Birthdate == nil {
+			return nil, ErrFederatedAccountNotFound
+		}
+		user, err = s.ProvisionUser(ctx, ProvisionInput{
+			Email:     identity.Email,
+			Firstname: identity.Firstname,
+			Lastname:  identity.Lastname,
+			Birthdate: *identity.Birthdate,
+			Active:    true,
+		})
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	link := &domain.Identity{
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		UserID:    user.ID,
+		Email:     identity.Email,
+		CreatedAt: time.Now(),
+	}
+	if err := s.identityRepo.Create(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to link identity: %w", err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrWrongPassword is returned when the current password given to change it is wrong.
+	ErrWrongPassword = errors.New("current password is wrong")
+	// ErrSamePassword is returned when the new password equals the current one.
+	ErrSamePassword = errors.New("new password must differ from the current one")
+)
+
+// ChangePassword replaces the user's password after verifying the current one and returns a new token.
+// Tokens issued before the change are no longer accepted, so other sessions have to log in again.
+// Every change and every attempt with a wrong current password is logged for security audits.
+// Returns ErrWrongPassword, ErrSamePassword and ErrUserNotFound.
+func (s *UsersService) ChangePassword(ctx context.Context, id uuid.UUID, current, next string, meta LoginMetadata) (string, error) {
+	const op = "UsersService.ChangePassword"
+	log := s.logger.WithTrace(ctx)
+
+	user, err := s.GetUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return "", err
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(current)); err != nil {
+		log.Warn("password change rejected, wrong current password", "op", op, "audit", "security",
+			"user_id", user.ID, "ip", meta.IPAddress, "user_agent", meta.UserAgent)
+		return "", ErrWrongPassword
+	}
+	if current == next {
+		return "", ErrSamePassword
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(next), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Tokens carry their issue time in whole seconds: the change takes effect at the next second, so tokens
+	// issued in the current one are rejected too, and the new token is issued then
+	changedAt := time.Now().Truncate(time.Second).Add(time.Second)
+	if err := s.repo.UpdatePassword(ctx, user.ID, string(passwordHash), changedAt); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return "", ErrUserNotFound
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	log.Info("password changed", "op", op, "audit", "security",
+		"user_id", user.ID, "ip", meta.IPAddress, "user_agent", meta.UserAgent)
+
+	token, err := s.issueToken(user, changedAt)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return token, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrUserHasOrders is returned when deleting the account of a user who placed orders, which are kept for accounting.
+var ErrUserHasOrders = errors.New("user has orders, account cannot be deleted")
+
+// ProfileUpdate contains the profile fields a user changes. Nil fields are kept.
+type ProfileUpdate struct {
+	Firstname *string
+	Lastname  *string
+	Birthdate *time.Time
+	IsMarried *bool
+}
+
+// UpdateProfile changes the given fields of the user's profile and returns the updated user.
+// Returns ErrUnderage if the birthdate makes the user younger than the required age, and ErrUserNotFound.
+func (s *UsersService) UpdateProfile(ctx context.Context, id uuid.UUID, in ProfileUpdate) (*domain.User, error) {
+	const op = "UsersService.UpdateProfile"
+
+	user, err := s.GetUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if in.Firstname != nil {
+		user.Firstname = *in.Firstname
+	}
+	if in.Lastname != nil {
+		user.Lastname = *in.Lastname
+	}
+	if in.Birthdate != nil {
+		if domain.AgeAt(*in.Birthdate, time.Now()) < domain.AdultAge {
+			return nil, ErrUnderage
+		}
+		user.Birthdate = *in.Birthdate
+	}
+	if in.IsMarried != nil {
+		user.IsMarried = *in.IsMarried
+	}
+
+	if err := s.repo.Update(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
+
+// DeleteAccount deletes the user's account with their consents, preferences, login history and notifications.
+// Tokens of the user are rejected afterwards. Returns ErrUserHasOrders and ErrUserNotFound.
+func (s *UsersService) DeleteAccount(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrUserNotFound):
+			return ErrUserNotFound
+		case errors.Is(err, repository.ErrUserHasOrders):
+			return ErrUserHasOrders
+		}
+		return fmt.Errorf("UsersService.DeleteAccount: %w", err)
+	}
+	s.logger.WithTrace(ctx).Info("user account deleted", "user_id", id)
+	return nil
+}
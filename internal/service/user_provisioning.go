@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ProvisionInput contains data for creating a user from an external identity provider.
+type ProvisionInput struct {
+	ExternalID string
+	Email      string
+	Username   string // Optional, normalized before saving
+	Password   string // Optional, a random unusable password is generated if empty
+	Firstname  string
+	Lastname   string
+	Birthdate  time.Time
+	Active     bool
+}
+
+// ProvisionUser creates a user on behalf of an external identity provider (SCIM).
+// Applies the same age and uniqueness rules as self-registration.
+func (s *UsersService) ProvisionUser(ctx context.Context, in ProvisionInput) (*domain.User, error) {
+	const op = "UsersService.ProvisionUser"
+
+	if domain.AgeAt(in.Birthdate, time.Now()) < domain.AdultAge {
+		return nil, ErrUnderage
+	}
+
+	username, err := normalizeOptionalUsername(in.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	password := in.Password
+	if password == "" {
+		// Provisioned users authenticate through their identity provider
+		if password, err = randomPassword(); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user := &domain.User{
+		ID:           uuid.New(),
+		Email:        in.Email,
+		Username:     username,
+		PasswordHash: string(passwordHash),
+		Firstname:    in.Firstname,
+		Lastname:     in.Lastname,
+		Birthdate:    in.Birthdate,
+		IsActive:     in.Active,
+		ExternalID:   in.ExternalID,
+		Role:         domain.RoleCustomer,
+	}
+
+	if err := s.repo.Create(ctx, user); err != nil {
+		return nil, mapUserConflict(err)
+	}
+
+	return user, nil
+}
+
+// GetUser returns a user by ID.
+// Returns ErrUserNotFound if the user does not exist.
+func (s *UsersService) GetUser(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// ListUsers returns a page of users matching the filter and the total number of matches.
+func (s *UsersService) ListUsers(ctx context.Context, filter repository.UserFilter, offset, limit int) ([]domain.User, int, error) {
+	return s.repo.List(ctx, filter, offset, limit)
+}
+
+// UpdateUser saves changes made to a user by an external identity provider.
+// Username is normalized; password hash is never changed.
+func (s *UsersService) UpdateUser(ctx context.Context, user *domain.User) error {
+	if domain.AgeAt(user.Birthdate, time.Now()) < domain.AdultAge {
+		return ErrUnderage
+	}
+
+	username, err := normalizeOptionalUsername(user.Username)
+	if err != nil {
+		return err
+	}
+	user.Username = username
+
+	if err := s.repo.Update(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return ErrUserNotFound
+		}
+		return mapUserConflict(err)
+	}
+	return nil
+}
+
+// SetActive activates or deactivates (deprovisions) a user.
+func (s *UsersService) SetActive(ctx context.Context, id uuid.UUID, active bool) error {
+	user, err := s.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	user.IsActive = active
+	return s.UpdateUser(ctx, user)
+}
+
+// SetRole assigns a role to a user. Tokens carrying the previous role are no longer accepted, so the user logs in again.
+// Returns ErrInvalidRole and ErrUserNotFound.
+func (s *UsersService) SetRole(ctx context.Context, id uuid.UUID, role string) (*domain.User, error) {
+	if !domain.IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	if err := s.repo.UpdateRole(ctx, id, role); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("UsersService.SetRole: %w", err)
+	}
+	return s.GetUser(ctx, id)
+}
+
+// SetSandbox marks a user as a sandbox account of an integration partner, or back as a regular account.
+// Orders placed afterwards allocate no stock and are paid with the mock payment provider, placed orders keep their mode.
+// Returns ErrUserNotFound.
+func (s *UsersService) SetSandbox(ctx context.Context, id uuid.UUID, sandbox bool) (*domain.User, error) {
+	if err := s.repo.UpdateSandbox(ctx, id, sandbox); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("UsersService.SetSandbox: %w", err)
+	}
+	return s.GetUser(ctx, id)
+}
+
+// normalizeOptionalUsername normalizes a username, keeping empty usernames empty.
+func normalizeOptionalUsername(username string) (string, error) {
+	if username == "" {
+		return "", nil
+	}
+	return domain.NormalizeUsername(username)
+}
+
+// mapUserConflict converts repository uniqueness errors into service errors.
+func mapUserConflict(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrEmailTaken):
+		return ErrUserAlreadyExists
+	case errors.Is(err, repository.ErrUsernameTaken):
+		return ErrUsernameTaken
+	case errors.Is(err, repository.ErrExternalIDTaken):
+		return ErrExternalIDTaken
+	}
+	return err
+}
+
+// randomPassword generates a random password nobody knows.
+func randomPassword() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -2,85 +2,56 @@ package service_test
 
 import (
 	"context"
-	"log"
-	"os"
 	"product-api/internal/domain"
+	"product-api/internal/event"
 	"product-api/internal/repository"
 	"product-api/internal/repository/postgres"
 	"product-api/internal/service"
+	"product-api/internal/testutil/factory"
+	"product-api/internal/testutil/testdb"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/suite"
-	"golang.org/x/crypto/bcrypt"
 )
 
+var testLoginMetadata = service.LoginMetadata{IPAddress: "127.0.0.1", UserAgent: "test-agent"}
+
+func testRegisterInput(email string) service.RegisterInput {
+	return service.RegisterInput{
+		Email:     email,
+		Password:  "password123",
+		Firstname: "John",
+		Lastname:  "Doe",
+		Birthdate: time.Date(1999, 4, 21, 0, 0, 0, 0, time.UTC),
+	}
+}
+
 type UserServiceTestSuite struct {
 	suite.Suite
 	dbpool    *pgxpool.Pool
 	userRepo  repository.UserRepository
 	service   *service.UsersService
+	tokens    *service.TokenService
 	jwtSecret []byte
 }
 
-func (s *UserServiceTestSuite) SetupSuite() {
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbName := os.Getenv("DB_NAME") + "_test_user"
-	maintenanceDbUrl := "postgres://" + dbUser + ":" + dbPassword + "@localhost:5434/postgres?sslmode=disable"
-	testDbUrl := "postgres://" + dbUser + ":" + dbPassword + "@localhost:5434/" + dbName + "?sslmode=disable"
-
-	var err error
-	var maintenanceDb *pgxpool.Pool
-
-	for i := 0; i < 10; i++ {
-		maintenanceDb, err = pgxpool.New(context.Background(), maintenanceDbUrl)
-		if err == nil {
-			break
-		}
-		log.Printf("Failed to connect to maintenance db, retrying in 2 seconds...: %v", err)
-		time.Sleep(2 * time.Second)
-	}
-	s.Require().NoError(err, "Failed to connect to maintenance database after retries")
-
-	_, err = maintenanceDb.Exec(context.Background(), "DROP DATABASE IF EXISTS "+dbName)
-	s.Require().NoError(err)
-	_, err = maintenanceDb.Exec(context.Background(), "CREATE DATABASE "+dbName)
-	s.Require().NoError(err)
-	maintenanceDb.Close()
-
-	s.dbpool, err = pgxpool.New(context.Background(), testDbUrl)
-	s.Require().NoError(err)
-
-	m, err := migrate.New("file://../../migrations", testDbUrl)
-	s.Require().NoError(err)
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		s.Require().NoError(err)
-	}
+func (s *UserServiceTestSuite) SetupTest() {
+	s.dbpool = testdb.New(s.T())
 
 	s.userRepo = postgres.NewUserRepository(s.dbpool)
 	s.jwtSecret = []byte("test-secret")
-	s.service = service.NewUsersService(s.userRepo, s.jwtSecret, time.Hour)
-}
-
-func (s *UserServiceTestSuite) TearDownSuite() {
-	s.dbpool.Close()
-}
-
-func (s *UserServiceTestSuite) TearDownTest() {
-	_, err := s.dbpool.Exec(context.Background(), "TRUNCATE TABLE users RESTART IDENTITY CASCADE")
-	s.Require().NoError(err)
+	s.service = service.NewUsersService(s.userRepo, postgres.NewLoginAttemptRepository(s.dbpool), postgres.NewIdentityRepository(s.dbpool), service.NewHMACTokenKeys(s.jwtSecret), time.Hour,
+		service.LoginLockout{MaxFailures: 3, Duration: 15 * time.Minute}, event.NewBus(discardLogger{}), discardLogger{})
+	s.tokens = service.NewTokenService(postgres.NewTokenRepository(s.dbpool), s.userRepo, service.NewHMACTokenKeys(s.jwtSecret))
 }
 
 func (s *UserServiceTestSuite) TestRegister_Success() {
 	ctx := context.Background()
-	user, err := s.service.Register(ctx, "test@example.com", "password123", "John", "Doe", 25, false)
+	user, err := s.service.Register(ctx, testRegisterInput("test@example.com"))
 	s.NoError(err)
 	s.NotNil(user)
 	dbUser, err := s.userRepo.FindByEmail(ctx, "test@example.com")
@@ -90,25 +61,55 @@ func (s *UserServiceTestSuite) TestRegister_Success() {
 
 func (s *UserServiceTestSuite) TestRegister_UserAlreadyExists() {
 	ctx := context.Background()
-	existingUser := &domain.User{
-		ID:           uuid.New(),
-		Email:        "exists@example.com",
-		PasswordHash: "somehash",
-	}
-	s.Require().NoError(s.userRepo.Create(ctx, existingUser))
-	_, err := s.service.Register(ctx, "exists@example.com", "password123", "John", "Doe", 25, false)
+	factory.CreateUser(s.T(), s.userRepo, factory.WithEmail("exists@example.com"))
+	_, err := s.service.Register(ctx, testRegisterInput("exists@example.com"))
 	s.ErrorIs(err, service.ErrUserAlreadyExists)
 }
 
-func (s *UserServiceTestSuite) TestLogin_Success() {
+func (s *UserServiceTestSuite) TestRegister_Underage() {
+	ctx := context.Background()
+	in := testRegisterInput("young@example.com")
+	in.Birthdate = time.Now().AddDate(-17, 0, 0)
+	_, err := s.service.Register(ctx, in)
+	s.ErrorIs(err, service.ErrUnderage)
+	_, err = s.userRepo.FindByEmail(ctx, "young@example.com")
+	s.ErrorIs(err, repository.ErrUserNotFound)
+}
+
+func (s *UserServiceTestSuite) TestRegister_UsernameTaken() {
+	ctx := context.Background()
+	in := testRegisterInput("first@example.com")
+	in.Username = "John.Doe"
+	user, err := s.service.Register(ctx, in)
+	s.Require().NoError(err)
+	s.Equal("john.doe", user.Username)
+
+	in = testRegisterInput("second@example.com")
+	in.Username = "JOHN.DOE"
+	_, err = s.service.Register(ctx, in)
+	s.ErrorIs(err, service.ErrUsernameTaken)
+
+	_, available, err := s.service.CheckUsername(ctx, " john.doe ")
+	s.NoError(err)
+	s.False(available)
+}
+
+func (s *UserServiceTestSuite) TestLogin_ByUsername() {
 	ctx := context.Background()
-	email := "login@example.com"
-	password := "password123"
-	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	in := testRegisterInput("byname@example.com")
+	in.Username = "byname"
+	_, err := s.service.Register(ctx, in)
 	s.Require().NoError(err)
-	user := &domain.User{ID: uuid.New(), Email: email, PasswordHash: string(passwordHash)}
-	s.Require().NoError(s.userRepo.Create(ctx, user))
-	token, err := s.service.Login(ctx, email, password)
+
+	token, err := s.service.Login(ctx, "ByName", in.Password, testLoginMetadata)
+	s.NoError(err)
+	s.NotEmpty(token)
+}
+
+func (s *UserServiceTestSuite) TestLogin_Success() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	token, err := s.service.Login(ctx, user.Email, factory.DefaultPassword, testLoginMetadata)
 	s.NoError(err)
 	s.NotEmpty(token)
 	tokenClaims := jwt.MapClaims{}
@@ -119,26 +120,221 @@ func (s *UserServiceTestSuite) TestLogin_Success() {
 	s.Require().True(parsedToken.Valid)
 	s.Equal(user.ID.String(), tokenClaims["sub"])
 	s.InDelta(time.Now().Add(time.Hour).Unix(), tokenClaims["exp"], 10) // Check exp is roughly correct
+
+	dbUser, err := s.userRepo.FindByID(ctx, user.ID)
+	s.Require().NoError(err)
+	s.NotNil(dbUser.LastLoginAt)
+	history, err := s.service.LoginHistory(ctx, user.ID, 10)
+	s.Require().NoError(err)
+	s.Require().Len(history, 1)
+	s.True(history[0].Success)
+	s.Equal("test-agent", history[0].UserAgent)
+}
+
+func (s *UserServiceTestSuite) TestTokenIntrospection() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	token, err := s.service.Login(ctx, user.Email, factory.DefaultPassword, testLoginMetadata)
+	s.Require().NoError(err)
+
+	claims, active, err := s.tokens.Introspect(ctx, token)
+	s.Require().NoError(err)
+	s.True(active)
+	s.Equal(user.ID, claims.UserID)
+	s.NotEqual(uuid.Nil, claims.ID)
+
+	_, active, err = s.tokens.Introspect(ctx, "not-a-token")
+	s.Require().NoError(err)
+	s.False(active)
+
+	// Revoked tokens are no longer active, revoking twice is not an error
+	s.Require().NoError(s.tokens.Revoke(ctx, token))
+	s.Require().NoError(s.tokens.Revoke(ctx, token))
+	_, active, err = s.tokens.Introspect(ctx, token)
+	s.Require().NoError(err)
+	s.False(active)
+
+	// Tokens of disabled users are not active
+	token, err = s.service.Login(ctx, user.Email, factory.DefaultPassword, testLoginMetadata)
+	s.Require().NoError(err)
+	s.Require().NoError(s.service.SetActive(ctx, user.ID, false))
+	_, active, err = s.tokens.Introspect(ctx, token)
+	s.Require().NoError(err)
+	s.False(active)
+}
+
+func (s *UserServiceTestSuite) TestSetRole() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	token, err := s.service.Login(ctx, user.Email, factory.DefaultPassword, testLoginMetadata)
+	s.Require().NoError(err)
+	claims, active, err := s.tokens.Introspect(ctx, token)
+	s.Require().NoError(err)
+	s.True(active)
+	s.Equal([]string{domain.RoleCustomer}, claims.Roles)
+
+	updated, err := s.service.SetRole(ctx, user.ID, domain.RoleManager)
+	s.Require().NoError(err)
+	s.Equal(domain.RoleManager, updated.Role)
+
+	// Tokens of the previous role are no longer active, new ones carry the role
+	_, active, err = s.tokens.Introspect(ctx, token)
+	s.Require().NoError(err)
+	s.False(active)
+	token, err = s.service.Login(ctx, user.Email, factory.DefaultPassword, testLoginMetadata)
+	s.Require().NoError(err)
+	claims, active, err = s.tokens.Introspect(ctx, token)
+	s.Require().NoError(err)
+	s.True(active)
+	s.True(claims.HasRole(domain.RoleAdmin, domain.RoleManager))
+
+	_, err = s.service.SetRole(ctx, user.ID, "owner")
+	s.ErrorIs(err, service.ErrInvalidRole)
+	_, err = s.service.SetRole(ctx, uuid.New(), domain.RoleAdmin)
+	s.ErrorIs(err, service.ErrUserNotFound)
+}
+
+func (s *UserServiceTestSuite) TestSetSandbox() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	s.False(user.Sandbox)
+
+	updated, err := s.service.SetSandbox(ctx, user.ID, true)
+	s.Require().NoError(err)
+	s.True(updated.Sandbox)
+	updated, err = s.service.SetSandbox(ctx, user.ID, false)
+	s.Require().NoError(err)
+	s.False(updated.Sandbox)
+
+	_, err = s.service.SetSandbox(ctx, uuid.New(), true)
+	s.ErrorIs(err, service.ErrUserNotFound)
+}
+
+func (s *UserServiceTestSuite) TestUpdateProfile() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+
+	firstname, married := "Jane", true
+	updated, err := s.service.UpdateProfile(ctx, user.ID, service.ProfileUpdate{Firstname: &firstname, IsMarried: &married})
+	s.Require().NoError(err)
+	s.Equal("Jane", updated.Firstname)
+	s.True(updated.IsMarried)
+
+	// Omitted fields are kept
+	dbUser, err := s.userRepo.FindByID(ctx, user.ID)
+	s.Require().NoError(err)
+	s.Equal("Jane", dbUser.Firstname)
+	s.Equal(user.Lastname, dbUser.Lastname)
+	s.Equal(user.PasswordHash, dbUser.PasswordHash)
+
+	minor := time.Now().AddDate(-17, 0, 0)
+	_, err = s.service.UpdateProfile(ctx, user.ID, service.ProfileUpdate{Birthdate: &minor})
+	s.ErrorIs(err, service.ErrUnderage)
+	_, err = s.service.UpdateProfile(ctx, uuid.New(), service.ProfileUpdate{Firstname: &firstname})
+	s.ErrorIs(err, service.ErrUserNotFound)
+}
+
+func (s *UserServiceTestSuite) TestChangePassword() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	before, err := s.service.Login(ctx, user.Email, factory.DefaultPassword, testLoginMetadata)
+	s.Require().NoError(err)
+
+	_, err = s.service.ChangePassword(ctx, user.ID, "wrongpassword", "new-password", testLoginMetadata)
+	s.ErrorIs(err, service.ErrWrongPassword)
+	_, err = s.service.ChangePassword(ctx, user.ID, factory.DefaultPassword, factory.DefaultPassword, testLoginMetadata)
+	s.ErrorIs(err, service.ErrSamePassword)
+	_, err = s.service.ChangePassword(ctx, uuid.New(), factory.DefaultPassword, "new-password", testLoginMetadata)
+	s.ErrorIs(err, service.ErrUserNotFound)
+
+	token, err := s.service.ChangePassword(ctx, user.ID, factory.DefaultPassword, "new-password", testLoginMetadata)
+	s.Require().NoError(err)
+
+	// Tokens issued before the change are no longer active, the returned one is
+	_, active, err := s.tokens.Introspect(ctx, before)
+	s.Require().NoError(err)
+	s.False(active)
+	_, active, err = s.tokens.Introspect(ctx, token)
+	s.Require().NoError(err)
+	s.True(active)
+
+	_, err = s.service.Login(ctx, user.Email, factory.DefaultPassword, testLoginMetadata)
+	s.ErrorIs(err, service.ErrInvalidCredentials)
+	_, err = s.service.Login(ctx, user.Email, "new-password", testLoginMetadata)
+	s.NoError(err)
+}
+
+func (s *UserServiceTestSuite) TestDeleteAccount() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+	_, err := s.service.Login(ctx, user.Email, factory.DefaultPassword, testLoginMetadata)
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.service.DeleteAccount(ctx, user.ID))
+	_, err = s.service.GetUser(ctx, user.ID)
+	s.ErrorIs(err, service.ErrUserNotFound)
+	s.ErrorIs(s.service.DeleteAccount(ctx, user.ID), service.ErrUserNotFound)
+
+	// Orders are kept, so are accounts of buyers
+	buyer := factory.CreateUser(s.T(), s.userRepo)
+	factory.CreateOrder(s.T(), s.dbpool, postgres.NewOrderRepository(s.dbpool), buyer.ID)
+	s.ErrorIs(s.service.DeleteAccount(ctx, buyer.ID), service.ErrUserHasOrders)
 }
 
 func (s *UserServiceTestSuite) TestLogin_UserNotFound() {
 	ctx := context.Background()
-	_, err := s.service.Login(ctx, "nonexistent@example.com", "password123")
+	_, err := s.service.Login(ctx, "nonexistent@example.com", "password123", testLoginMetadata)
 	s.ErrorIs(err, service.ErrInvalidCredentials)
 }
 
 func (s *UserServiceTestSuite) TestLogin_InvalidPassword() {
 	ctx := context.Background()
-	email := "invalidpass@example.com"
-	password := "password123"
-	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	s.Require().NoError(err)
-	user := &domain.User{ID: uuid.New(), Email: email, PasswordHash: string(passwordHash)}
-	s.Require().NoError(s.userRepo.Create(ctx, user))
-	_, err = s.service.Login(ctx, email, "wrongpassword")
+	user := factory.CreateUser(s.T(), s.userRepo)
+	_, err := s.service.Login(ctx, user.Email, "wrongpassword", testLoginMetadata)
 	s.ErrorIs(err, service.ErrInvalidCredentials)
+	history, err := s.service.LoginHistory(ctx, user.ID, 10)
+	s.Require().NoError(err)
+	s.Require().Len(history, 1)
+	s.False(history[0].Success)
+	s.Equal(domain.LoginFailureWrongPassword, history[0].FailureReason)
+}
+
+func (s *UserServiceTestSuite) TestLogin_Lockout() {
+	ctx := context.Background()
+	user := factory.CreateUser(s.T(), s.userRepo)
+
+	// A successful login resets the count of failed logins
+	for range 2 {
+		_, err := s.service.Login(ctx, user.Email, "wrongpassword", testLoginMetadata)
+		s.Require().ErrorIs(err, service.ErrInvalidCredentials)
+	}
+	_, err := s.service.Login(ctx, user.Email, factory.DefaultPassword, testLoginMetadata)
+	s.Require().NoError(err)
+
+	// The third consecutive failure locks the account
+	for range 2 {
+		_, err = s.service.Login(ctx, user.Email, "wrongpassword", testLoginMetadata)
+		s.Require().ErrorIs(err, service.ErrInvalidCredentials)
+	}
+	_, err = s.service.Login(ctx, user.Email, "wrongpassword", testLoginMetadata)
+	s.Require().ErrorIs(err, service.ErrAccountLocked)
+
+	dbUser, err := s.userRepo.FindByID(ctx, user.ID)
+	s.Require().NoError(err)
+	s.True(dbUser.IsLocked(time.Now()))
+	s.WithinDuration(time.Now().Add(15*time.Minute), *dbUser.LockedUntil, time.Minute)
+	s.Zero(dbUser.FailedLogins)
+
+	// Locked accounts are refused even with the right password
+	_, err = s.service.Login(ctx, user.Email, factory.DefaultPassword, testLoginMetadata)
+	s.ErrorIs(err, service.ErrAccountLocked)
+	history, err := s.service.LoginHistory(ctx, user.ID, 1)
+	s.Require().NoError(err)
+	s.Require().Len(history, 1)
+	s.Equal(domain.LoginFailureAccountLocked, history[0].FailureReason)
 }
 
 func TestUserServiceTestSuite(t *testing.T) {
+	t.Parallel()
 	suite.Run(t, new(UserServiceTestSuite))
 }
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// workerStallIntervals is the number of intervals a worker may go without a run before it is reported stalled.
+const workerStallIntervals = 3
+
+// WorkerStatus contains the heartbeat of a background runner.
+type WorkerStatus struct {
+	Name          string     `example:"accounting"`
+	IntervalMs    int64      `example:"60000"` // Interval between runs while idle
+	LastRunAt     *time.Time // Time the last run finished, nil before the first run
+	LastSuccessAt *time.Time // Time the last successful run finished, nil if no run succeeded
+	LastError     string     // Error of the last run, empty if it succeeded
+	Stalled       bool       // No run finished for three intervals since the last run or the start
+}
+
+// Workers is a registry of the heartbeats of background runners, reported in the system status.
+type Workers struct {
+	mu      sync.Mutex
+	workers map[string]*workerHeartbeat
+}
+
+// workerHeartbeat is the state of a registered runner.
+type workerHeartbeat struct {
+	interval      time.Duration
+	registeredAt  time.Time
+	lastRunAt     time.Time
+	lastSuccessAt time.Time
+	lastErr       error
+}
+
+// NewWorkers creates an empty registry of runners.
+func NewWorkers() *Workers {
+	return &Workers{workers: make(map[string]*workerHeartbeat)}
+}
+
+// Heartbeat records the runs of one runner in its registry. A nil Heartbeat records nothing,
+// so runners can be started without a registry, e.g. in tests.
+type Heartbeat struct {
+	workers *Workers
+	name    string
+}
+
+// Register adds the runner with the interval between its runs and returns the heartbeat it records its runs with.
+func (w *Workers) Register(name string, interval time.Duration) *Heartbeat {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.workers[name] = &workerHeartbeat{interval: interval, registeredAt: time.Now()}
+	return &Heartbeat{workers: w, name: name}
+}
+
+// Beat records a finished run of the runner that failed with err, nil if it succeeded.
+func (h *Heartbeat) Beat(err error) {
+	if h == nil {
+		return
+	}
+	h.workers.mu.Lock()
+	defer h.workers.mu.Unlock()
+	w := h.workers.workers[h.name]
+	w.lastRunAt, w.lastErr = time.Now(), err
+	if err == nil {
+		w.lastSuccessAt = w.lastRunAt
+	}
+}
+
+// Status returns the heartbeats of the registered runners by name at now.
+func (w *Workers) Status(now time.Time) []WorkerStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	statuses := make([]WorkerStatus, 0, len(w.workers))
+	for _, name := range slices.Sorted(maps.Keys(w.workers)) {
+		hb := w.workers[name]
+		status := WorkerStatus{Name: name, IntervalMs: hb.interval.Milliseconds()}
+		last := hb.registeredAt
+		if !hb.lastRunAt.IsZero() {
+			lastRunAt := hb.lastRunAt
+			status.LastRunAt, last = &lastRunAt, lastRunAt
+		}
+		if !hb.lastSuccessAt.IsZero() {
+			lastSuccessAt := hb.lastSuccessAt
+			status.LastSuccessAt = &lastSuccessAt
+		}
+		if hb.lastErr != nil {
+			status.LastError = hb.lastErr.Error()
+		}
+		status.Stalled = now.Sub(last) > workerStallIntervals*hb.interval
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
@@ -0,0 +1,8 @@
+package storage
+
+import "time"
+
+// SetClock sets the clock of request signatures.
+func (s *S3Storage) SetClock(now func() time.Time) {
+	s.now = now
+}
@@ -0,0 +1,25 @@
+package storage
+
+// gcsEndpoint is the Cloud Storage XML API, which is interoperable with the S3 REST API.
+const gcsEndpoint = "https://storage.googleapis.com"
+
+// GCSConfig contains settings of a Google Cloud Storage bucket.
+type GCSConfig struct {
+	Bucket    string
+	AccessKey string // HMAC key access ID of a service account
+	Secret    string // HMAC key secret
+}
+
+// NewGCSStorage creates a new Google Cloud Storage storage. Requests go to the XML API
+// and are signed with the service account's HMAC key, the way the API accepts from S3 clients.
+// The returned storage is an S3Storage named "gcs".
+func NewGCSStorage(cfg GCSConfig) (*S3Storage, error) {
+	return newS3Storage("gcs", S3Config{
+		Bucket:          cfg.Bucket,
+		Region:          "auto",
+		Endpoint:        gcsEndpoint,
+		PathStyle:       true,
+		AccessKeyID:     cfg.AccessKey,
+		SecretAccessKey: cfg.Secret,
+	})
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// LocalStorage is a Storage keeping objects as files under a directory, for development and single-node deployments.
+// Content types are derived from key extensions. Presigned URLs are not supported.
+type LocalStorage struct {
+	dir string
+}
+
+// NewLocalStorage creates a new local storage in dir, creating the directory if it does not exist.
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create storage directory: %w", err)
+	}
+	return &LocalStorage{dir: dir}, nil
+}
+
+// Name returns "local".
+func (s *LocalStorage) Name() string {
+	return "local"
+}
+
+// Put writes the object to a temporary file first, so readers never see a partially written object.
+func (s *LocalStorage) Put(_ context.Context, key string, body io.Reader, size int64, _ string) (err error) {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	name := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	n, err := io.Copy(tmp, body)
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return fmt.Errorf("object %s is %d bytes, expected %d", key, n, size)
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+func (s *LocalStorage) Get(_ context.Context, key string) (*Object, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(s.path(key))
+	if err != nil {
+		return nil, localError(err)
+	}
+	stat, err := f.Stat()
+	if err != nil || stat.IsDir() {
+		f.Close()
+		return nil, cmp.Or(err, ErrNotFound)
+	}
+	return &Object{ObjectInfo: localInfo(key, stat), Body: f}, nil
+}
+
+func (s *LocalStorage) Stat(_ context.Context, key string) (*ObjectInfo, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(s.path(key))
+	if err != nil {
+		return nil, localError(err)
+	}
+	if stat.IsDir() {
+		return nil, ErrNotFound
+	}
+	info := localInfo(key, stat)
+	return &info, nil
+}
+
+func (s *LocalStorage) Delete(_ context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// PresignGet returns ErrPresignNotSupported.
+func (s *LocalStorage) PresignGet(context.Context, string, time.Duration) (string, error) {
+	return "", ErrPresignNotSupported
+}
+
+// PresignPut returns ErrPresignNotSupported.
+func (s *LocalStorage) PresignPut(context.Context, string, time.Duration) (string, error) {
+	return "", ErrPresignNotSupported
+}
+
+// path returns the file of a validated key.
+func (s *LocalStorage) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
+
+func localInfo(key string, stat fs.FileInfo) ObjectInfo {
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return ObjectInfo{Key: key, Size: stat.Size(), ContentType: contentType, LastModified: stat.ModTime()}
+}
+
+func localError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,285 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Config contains settings of an S3 bucket.
+type S3Config struct {
+	Bucket          string
+	Region          string // e.g. eu-central-1
+	Endpoint        string // Base URL of S3-compatible services, e.g. http://minio:9000 (default: AWS S3 of the region)
+	PathStyle       bool   // Address the bucket in the URL path instead of the host name, needed by most S3-compatible services
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// S3Storage is a Storage using the S3 REST API, with requests signed with AWS Signature Version 4.
+// Bodies are sent unsigned (UNSIGNED-PAYLOAD), so the endpoint must use HTTPS outside development.
+type S3Storage struct {
+	name   string
+	cfg    S3Config
+	base   *url.URL // Endpoint of bucket requests
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Storage creates a new S3 storage.
+func NewS3Storage(cfg S3Config) (*S3Storage, error) {
+	return newS3Storage("s3", cfg)
+}
+
+func newS3Storage(name string, cfg S3Config) (*S3Storage, error) {
+	if cfg.Bucket == "" || cfg.Region == "" {
+		return nil, fmt.Errorf("%s storage requires a bucket and region", name)
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	base, err := url.Parse(endpoint)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("invalid %s endpoint %q", name, endpoint)
+	}
+	if !cfg.PathStyle {
+		base.Host = cfg.Bucket + "." + base.Host
+	}
+	return &S3Storage{
+		name:   name,
+		cfg:    cfg,
+		base:   base,
+		client: &http.Client{Timeout: 5 * time.Minute},
+		now:    time.Now,
+	}, nil
+}
+
+// Name returns the backend name, "s3" or "gcs".
+func (s *S3Storage) Name() string {
+	return s.name
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if size == 0 {
+		body = http.NoBody // A zero length with a body would be sent chunked, which S3 rejects
+	}
+	resp, err := s.do(ctx, http.MethodPut, key, body, size, contentType)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (*Object, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Object{ObjectInfo: s3Info(key, resp), Body: resp.Body}, nil
+}
+
+func (s *S3Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	info := s3Info(key, resp)
+	return &info, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, "")
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Storage) PresignGet(_ context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presign(http.MethodGet, key, ttl)
+}
+
+func (s *S3Storage) PresignPut(_ context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presign(http.MethodPut, key, ttl)
+}
+
+// do sends a signed request of the object and returns successful responses.
+// Returns ErrNotFound for 404 responses.
+func (s *S3Storage) do(ctx context.Context, method, key string, body io.Reader, size int64, contentType string) (*http.Response, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s %s: %w", s.name, method, key, err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("%s %s %s: status %d: %s", s.name, method, key, resp.StatusCode, strings.TrimSpace(string(msg)))
+}
+
+// objectURL returns the URL of the object with the key.
+func (s *S3Storage) objectURL(key string) *url.URL {
+	u := *s.base
+	path := strings.TrimSuffix(u.Path, "/") + "/"
+	if s.cfg.PathStyle {
+		path += s.cfg.Bucket + "/"
+	}
+	u.Path = path + key
+	u.RawPath = uriEncode(path, false) + uriEncode(key, false)
+	return &u
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to the request.
+func (s *S3Storage) sign(req *http.Request) {
+	t := s.now().UTC()
+	req.Header.Set("X-Amz-Date", t.Format(sigV4TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method, req.URL.EscapedPath(), "", canonicalHeaders.String(), signedHeaders, unsignedPayload,
+	}, "\n")
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.cfg.AccessKeyID, s.scope(t), signedHeaders, s.signature(t, canonicalRequest)))
+}
+
+// presign returns a URL of the object carrying an AWS Signature Version 4 in its query, signing only the host header.
+func (s *S3Storage) presign(method, key string, ttl time.Duration) (string, error) {
+	if err := validatePresign(key, ttl); err != nil {
+		return "", err
+	}
+	u := s.objectURL(key)
+	t := s.now().UTC()
+	query := url.Values{
+		"X-Amz-Algorithm":     {sigV4Algorithm},
+		"X-Amz-Credential":    {s.cfg.AccessKeyID + "/" + s.scope(t)},
+		"X-Amz-Date":          {t.Format(sigV4TimeFormat)},
+		"X-Amz-Expires":       {strconv.Itoa(int(ttl.Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	params := make([]string, len(keys))
+	for i, k := range keys {
+		params[i] = uriEncode(k, true) + "=" + uriEncode(query.Get(k), true)
+	}
+	canonicalQuery := strings.Join(params, "&")
+
+	canonicalRequest := strings.Join([]string{
+		method, u.EscapedPath(), canonicalQuery, "host:" + u.Host + "\n", "host", unsignedPayload,
+	}, "\n")
+	u.RawQuery = canonicalQuery + "&X-Amz-Signature=" + s.signature(t, canonicalRequest)
+	return u.String(), nil
+}
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// scope returns the credential scope of signatures made at t.
+func (s *S3Storage) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + s.cfg.Region + "/s3/aws4_request"
+}
+
+// signature signs the canonical request made at t.
+func (s *S3Storage) signature(t time.Time, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm, t.Format(sigV4TimeFormat), s.scope(t), hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), t.Format("20060102"))
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncode percent-encodes all bytes except unreserved characters, and slashes unless encodeSlash is set,
+// as required by Signature Version 4.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3Info returns the metadata of an object from response headers.
+func s3Info(key string, resp *http.Response) ObjectInfo {
+	info := ObjectInfo{Key: key, Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.LastModified = t
+	}
+	return info
+}
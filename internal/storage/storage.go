@@ -0,0 +1,88 @@
+// Package storage defines the object storage of files such as product images, avatars, invoices and exports.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when there is no object with the key.
+	ErrNotFound = errors.New("object not found")
+	// ErrInvalidKey is returned for keys that are empty, absolute or contain empty, "." or ".." segments.
+	ErrInvalidKey = errors.New("invalid object key")
+	// ErrPresignNotSupported is returned by backends that cannot issue presigned URLs.
+	// Callers then transfer the object through the API instead.
+	ErrPresignNotSupported = errors.New("presigned URLs not supported by the storage backend")
+)
+
+// Key prefixes of stored objects, e.g. "invoices/INV-2024-000001.json".
+const (
+	PrefixProductImages = "product-images/"
+	PrefixAvatars       = "avatars/"
+	PrefixInvoices      = "invoices/"
+	PrefixExports       = "exports/"
+)
+
+// MaxPresignTTL is the longest validity of presigned URLs.
+const MaxPresignTTL = 7 * 24 * time.Hour
+
+// Storage stores objects by slash-separated keys, e.g. "avatars/<user id>.png".
+// Putting an object replaces the object with the same key.
+type Storage interface {
+	// Name returns the backend name, e.g. "s3".
+	Name() string
+	// Put stores size bytes read from body under the key.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get returns the object with the key, its body must be closed by the caller.
+	Get(ctx context.Context, key string) (*Object, error)
+	// Stat returns the object metadata without its body.
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Delete deletes the object with the key. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// PresignGet returns a URL downloading the object without credentials, valid for ttl.
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+	// PresignPut returns a URL uploading the object without credentials with an HTTP PUT, valid for ttl.
+	PresignPut(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// ObjectInfo contains metadata of a stored object.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// Object is a stored object with its contents.
+type Object struct {
+	ObjectInfo
+	Body io.ReadCloser
+}
+
+// validateKey checks that the key is a relative slash-separated path without empty, "." or ".." segments.
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.ContainsAny(key, "\\\x00") {
+		return ErrInvalidKey
+	}
+	for segment := range strings.SplitSeq(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+// validatePresign checks the key and ttl of a presigned URL.
+func validatePresign(key string, ttl time.Duration) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if ttl <= 0 || ttl > MaxPresignTTL {
+		return errors.New("presigned URL ttl must be positive and at most 7 days")
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// maxDatagramSize is the largest datagram sent to the agent, fitting into the MTU of common networks.
+const maxDatagramSize = 1432
+
+// DogStatsDExporter is a metric exporter sending metrics to a Datadog agent with the DogStatsD protocol over UDP.
+// Counters are sent as counts and up-down counters as gauges. Histograms are sent as the counts
+// ".count" and ".sum" with the gauges ".min" and ".max" of each export interval.
+// Global tags, e.g. "env:prod", are added to every metric.
+type DogStatsDExporter struct {
+	tags []string
+
+	mu   sync.Mutex
+	conn net.Conn // Nil after shutdown
+}
+
+// NewDogStatsDExporter creates a new exporter sending metrics to the agent at address, e.g. "localhost:8125".
+func NewDogStatsDExporter(address string, tags []string) (*DogStatsDExporter, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("dogstatsd: %w", err)
+	}
+	sanitized := make([]string, len(tags))
+	for i, tag := range tags {
+		sanitized[i] = sanitizeDogStatsD(tag)
+	}
+	return &DogStatsDExporter{tags: sanitized, conn: conn}, nil
+}
+
+// Temporality returns delta temporality of counters and histograms, the agent aggregates them itself,
+// and cumulative temporality of up-down counters, sent as gauges of their current value.
+func (e *DogStatsDExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case sdkmetric.InstrumentKindUpDownCounter, sdkmetric.InstrumentKindObservableUpDownCounter:
+		return metricdata.CumulativeTemporality
+	}
+	return metricdata.DeltaTemporality
+}
+
+func (e *DogStatsDExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+// Export sends the metrics in datagrams of at most maxDatagramSize bytes.
+// Metrics of unsupported aggregations, e.g. exponential histograms, are skipped.
+func (e *DogStatsDExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.conn == nil {
+		return errors.New("dogstatsd: exporter is shut down")
+	}
+
+	var (
+		datagram []byte
+		errs     []error
+	)
+	send := func() {
+		if len(datagram) > 0 {
+			if _, err := e.conn.Write(datagram); err != nil {
+				errs = append(errs, err)
+			}
+			datagram = datagram[:0]
+		}
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			for _, line := range e.lines(m) {
+				if len(datagram) > 0 && len(datagram)+1+len(line) > maxDatagramSize {
+					send()
+				}
+				if len(datagram) > 0 {
+					datagram = append(datagram, '\n')
+				}
+				datagram = append(datagram, line...)
+			}
+		}
+	}
+	send()
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("dogstatsd: %w", err)
+	}
+	return nil
+}
+
+// ForceFlush does nothing, metrics are sent when they are exported.
+func (e *DogStatsDExporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+// Shutdown closes the connection to the agent.
+func (e *DogStatsDExporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.conn == nil {
+		return nil
+	}
+	err := e.conn.Close()
+	e.conn = nil
+	return err
+}
+
+// lines returns the DogStatsD lines of the metric data points.
+func (e *DogStatsDExporter) lines(m metricdata.Metrics) []string {
+	name := strings.ReplaceAll(sanitizeDogStatsD(m.Name), ":", "_")
+	var lines []string
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		for _, p := range data.DataPoints {
+			lines = append(lines, e.line(name, float64(p.Value), sumType(data.IsMonotonic), p.Attributes))
+		}
+	case metricdata.Sum[float64]:
+		for _, p := range data.DataPoints {
+			lines = append(lines, e.line(name, p.Value, sumType(data.IsMonotonic), p.Attributes))
+		}
+	case metricdata.Gauge[int64]:
+		for _, p := range data.DataPoints {
+			lines = append(lines, e.line(name, float64(p.Value), "g", p.Attributes))
+		}
+	case metricdata.Gauge[float64]:
+		for _, p := range data.DataPoints {
+			lines = append(lines, e.line(name, p.Value, "g", p.Attributes))
+		}
+	case metricdata.Histogram[int64]:
+		for _, p := range data.DataPoints {
+			lines = append(lines, e.histogramLines(name, p.Count, float64(p.Sum), p.Attributes)...)
+			if v, ok := p.Min.Value(); ok {
+				lines = append(lines, e.line(name+".min", float64(v), "g", p.Attributes))
+			}
+			if v, ok := p.Max.Value(); ok {
+				lines = append(lines, e.line(name+".max", float64(v), "g", p.Attributes))
+			}
+		}
+	case metricdata.Histogram[float64]:
+		for _, p := range data.DataPoints {
+			lines = append(lines, e.histogramLines(name, p.Count, p.Sum, p.Attributes)...)
+			if v, ok := p.Min.Value(); ok {
+				lines = append(lines, e.line(name+".min", v, "g", p.Attributes))
+			}
+			if v, ok := p.Max.Value(); ok {
+				lines = append(lines, e.line(name+".max", v, "g", p.Attributes))
+			}
+		}
+	}
+	return lines
+}
+
+func (e *DogStatsDExporter) histogramLines(name string, count uint64, sum float64, attrs attribute.Set) []string {
+	return []string{
+		e.line(name+".count", float64(count), "c", attrs),
+		e.line(name+".sum", sum, "c", attrs),
+	}
+}
+
+// line formats a metric as "name:value|type|#tag1,tag2" with the global tags followed by the attributes.
+func (e *DogStatsDExporter) line(name string, value float64, metricType string, attrs attribute.Set) string {
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte(':')
+	b.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	b.WriteByte('|')
+	b.WriteString(metricType)
+
+	tags := make([]string, 0, len(e.tags)+attrs.Len())
+	tags = append(tags, e.tags...)
+	for _, kv := range attrs.ToSlice() {
+		tags = append(tags, sanitizeDogStatsD(string(kv.Key)+":"+kv.Value.Emit()))
+	}
+	if len(tags) > 0 {
+		b.WriteString("|#")
+		b.WriteString(strings.Join(tags, ","))
+	}
+	return b.String()
+}
+
+// sumType returns the DogStatsD type of sums: counts for monotonic sums, gauges otherwise.
+func sumType(monotonic bool) string {
+	if monotonic {
+		return "c"
+	}
+	return "g"
+}
+
+// sanitizeDogStatsD replaces characters separating parts of DogStatsD lines.
+func sanitizeDogStatsD(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '|', ',', '#', '\n', '\r':
+			return '_'
+		}
+		return r
+	}, s)
+}
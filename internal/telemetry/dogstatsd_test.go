@@ -0,0 +1,107 @@
+package telemetry_test
+
+import (
+	"context"
+	"net"
+	"product-api/internal/telemetry"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// newAgent listens for DogStatsD datagrams on a local port.
+func newAgent(t *testing.T) *net.UDPConn {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// receive returns the lines of the datagrams received until no datagram arrives for a while.
+func receive(t *testing.T, conn *net.UDPConn) []string {
+	t.Helper()
+	var lines []string
+	buf := make([]byte, 65536)
+	for {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+		n, err := conn.Read(buf)
+		if err != nil {
+			return lines
+		}
+		assert.LessOrEqual(t, n, 1432)
+		lines = append(lines, strings.Split(string(buf[:n]), "\n")...)
+	}
+}
+
+func TestDogStatsDExporter(t *testing.T) {
+	agent := newAgent(t)
+	exporter, err := telemetry.NewDogStatsDExporter(agent.LocalAddr().String(), []string{"env:prod", "service:product-api", "version:1.2.3"})
+	require.NoError(t, err)
+	reader := sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(time.Hour))
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	meter := mp.Meter("test")
+	ctx := context.Background()
+
+	failures, err := meter.Int64Counter("users.login.failures")
+	require.NoError(t, err)
+	duration, err := meter.Float64Histogram("orders.create.duration")
+	require.NoError(t, err)
+	active, err := meter.Int64UpDownCounter("carts.active")
+	require.NoError(t, err)
+
+	failures.Add(ctx, 2, metric.WithAttributes(attribute.String("reason", "invalid|password")))
+	duration.Record(ctx, 0.5)
+	duration.Record(ctx, 1.5)
+	active.Add(ctx, 3)
+	require.NoError(t, reader.ForceFlush(ctx))
+
+	assert.ElementsMatch(t, []string{
+		"users.login.failures:2|c|#env:prod,service:product-api,version:1.2.3,reason:invalid_password",
+		"orders.create.duration.count:2|c|#env:prod,service:product-api,version:1.2.3",
+		"orders.create.duration.sum:2|c|#env:prod,service:product-api,version:1.2.3",
+		"orders.create.duration.min:0.5|g|#env:prod,service:product-api,version:1.2.3",
+		"orders.create.duration.max:1.5|g|#env:prod,service:product-api,version:1.2.3",
+		"carts.active:3|g|#env:prod,service:product-api,version:1.2.3",
+	}, receive(t, agent))
+
+	// Counts are deltas of the interval, up-down counters their current value
+	failures.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "invalid|password")))
+	active.Add(ctx, -1)
+	require.NoError(t, reader.ForceFlush(ctx))
+	assert.ElementsMatch(t, []string{
+		"users.login.failures:1|c|#env:prod,service:product-api,version:1.2.3,reason:invalid_password",
+		"carts.active:2|g|#env:prod,service:product-api,version:1.2.3",
+	}, receive(t, agent))
+
+	require.NoError(t, mp.Shutdown(ctx))
+}
+
+func TestDogStatsDExporter_SplitsDatagrams(t *testing.T) {
+	agent := newAgent(t)
+	exporter, err := telemetry.NewDogStatsDExporter(agent.LocalAddr().String(), nil)
+	require.NoError(t, err)
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	ctx := context.Background()
+
+	counter, err := mp.Meter("test").Int64Counter("db.slow_queries")
+	require.NoError(t, err)
+	for i := range 100 {
+		counter.Add(ctx, 1, metric.WithAttributes(attribute.Int("statement", i)))
+	}
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.NoError(t, exporter.Export(ctx, &rm))
+
+	assert.Len(t, receive(t, agent), 100)
+	require.NoError(t, exporter.Shutdown(ctx))
+	assert.Error(t, exporter.Export(ctx, &rm))
+}
@@ -0,0 +1,188 @@
+package telemetry
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// OutcomeSuccess is the outcome of successful operations.
+const OutcomeSuccess = "success"
+
+// Business metric instruments. They are created from the global meter provider,
+// which forwards measurements to the provider configured at startup.
+var (
+	meter = otel.Meter(InstrumentationName)
+
+	orderDuration, _ = meter.Float64Histogram("orders.create.duration",
+		metric.WithDescription("Duration of order creation by outcome"),
+		metric.WithUnit("s"),
+	)
+	orderValue, _ = meter.Float64Histogram("orders.value",
+		metric.WithDescription("Total amount of created orders"),
+		metric.WithUnit("{currency}"),
+		metric.WithExplicitBucketBoundaries(5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000),
+	)
+	stockDecrementFailures, _ = meter.Int64Counter("stock.decrement.failures",
+		metric.WithDescription("Failed attempts to decrement product stock by reason"),
+	)
+	loginFailures, _ = meter.Int64Counter("users.login.failures",
+		metric.WithDescription("Failed login attempts by reason"),
+	)
+	registrations, _ = meter.Int64Counter("users.registrations",
+		metric.WithDescription("Users registered with a password"),
+	)
+	stockMovements, _ = meter.Int64Counter("stock.movements",
+		metric.WithDescription("Committed stock quantity changes by reason"),
+	)
+	slowQueries, _ = meter.Int64Counter("db.slow_queries",
+		metric.WithDescription("Database queries exceeding the slow query threshold"),
+	)
+	poolExhaustions, _ = meter.Int64Counter("db.pool.exhausted",
+		metric.WithDescription("Database connections not acquired within the acquire timeout"),
+	)
+	shadowCalls, _ = meter.Int64Counter("repository.shadow.calls",
+		metric.WithDescription("Calls mirrored to secondary repositories by outcome"),
+	)
+	replicaReads, _ = meter.Int64Counter("repository.replica.reads",
+		metric.WithDescription("Reads routed to read replicas by the source of the result"),
+	)
+	httpRequests, _ = meter.Int64Counter("http.requests",
+		metric.WithDescription("Served HTTP requests by route and status code"),
+	)
+	httpRequestDuration, _ = meter.Float64Histogram("http.request.duration",
+		metric.WithDescription("Duration of HTTP requests by route and status code"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30),
+	)
+	httpRequestsInFlight, _ = meter.Int64UpDownCounter("http.requests.in_flight",
+		metric.WithDescription("HTTP requests being served by method"),
+	)
+	shedRequests, _ = meter.Int64Counter("http.requests.shed",
+		metric.WithDescription("HTTP requests rejected by bulkheads by traffic class and reason"),
+	)
+	rateLimitedRequests, _ = meter.Int64Counter("http.requests.rate_limited",
+		metric.WithDescription("HTTP requests rejected by rate limits by traffic class"),
+	)
+	poolConnections, _ = meter.Int64ObservableGauge("db.pool.connections",
+		metric.WithDescription("Connections of database pools by state: acquired, idle or max"),
+	)
+)
+
+// RecordOrderCreation records the duration of an order creation attempt with its outcome
+// (e.g. "success", "insufficient_stock") and, for created orders, the order value.
+func RecordOrderCreation(ctx context.Context, duration time.Duration, outcome string, amount float64) {
+	orderDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(attribute.String("outcome", outcome)))
+	if outcome == OutcomeSuccess {
+		orderValue.Record(ctx, amount)
+	}
+}
+
+// RecordStockDecrementFailure counts a failed stock decrement.
+func RecordStockDecrementFailure(ctx context.Context, reason string) {
+	stockDecrementFailures.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reason)))
+}
+
+// RecordLoginFailure counts a failed login attempt.
+func RecordLoginFailure(ctx context.Context, reason string) {
+	loginFailures.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reason)))
+}
+
+// RecordUserRegistration counts a user registered with a password.
+func RecordUserRegistration(ctx context.Context) {
+	registrations.Add(ctx, 1)
+}
+
+// RecordStockMovement counts a committed change of a product's stock quantity by its reason,
+// e.g. "allocation", "release" or "rebuild".
+func RecordStockMovement(ctx context.Context, reason string) {
+	stockMovements.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reason)))
+}
+
+// RecordSlowQuery counts a database query exceeding the slow query threshold.
+func RecordSlowQuery(ctx context.Context, statement string) {
+	slowQueries.Add(ctx, 1, metric.WithAttributes(attribute.String("statement", statement)))
+}
+
+// RecordShadowCall counts a call mirrored to a secondary repository by its outcome,
+// e.g. "match" or "mismatch" for reads.
+func RecordShadowCall(ctx context.Context, repository, op, outcome string) {
+	shadowCalls.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("repository", repository),
+		attribute.String("op", op),
+		attribute.String("outcome", outcome),
+	))
+}
+
+// RecordPoolExhaustion counts a database connection that could not be acquired within the acquire timeout.
+func RecordPoolExhaustion(ctx context.Context, pool string) {
+	poolExhaustions.Add(ctx, 1, metric.WithAttributes(attribute.String("pool", pool)))
+}
+
+// RecordReplicaRead counts a read routed to read replicas by the source of its result,
+// e.g. "replica", "hedge" or "primary" after replicas failed.
+func RecordReplicaRead(ctx context.Context, repository, op, source string) {
+	replicaReads.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("repository", repository),
+		attribute.String("op", op),
+		attribute.String("source", source),
+	))
+}
+
+// StartHTTPRequest counts a request in flight until the returned function is called.
+// Requests are not routed yet, so they are labeled by method only.
+func StartHTTPRequest(ctx context.Context, method string) (done func()) {
+	attrs := metric.WithAttributes(attribute.String("method", method))
+	httpRequestsInFlight.Add(ctx, 1, attrs)
+	return func() { httpRequestsInFlight.Add(ctx, -1, attrs) }
+}
+
+// RecordHTTPRequest counts a served request and records its duration, labeled by route pattern,
+// e.g. "/products/{id}", empty for requests matching no route.
+func RecordHTTPRequest(ctx context.Context, method, route string, status int, duration time.Duration) {
+	attrs := metric.WithAttributes(
+		attribute.String("method", method),
+		attribute.String("route", route),
+		attribute.String("status", strconv.Itoa(status)),
+	)
+	httpRequests.Add(ctx, 1, attrs)
+	httpRequestDuration.Record(ctx, duration.Seconds(), attrs)
+}
+
+// RecordShedRequest counts a request rejected by the bulkhead of its traffic class, e.g. "catalog",
+// because its queue was full ("queue_full") or no slot freed up in time ("timeout").
+func RecordShedRequest(ctx context.Context, class, reason string) {
+	shedRequests.Add(ctx, 1, metric.WithAttributes(attribute.String("class", class), attribute.String("reason", reason)))
+}
+
+// RecordRateLimitedRequest counts a request rejected by the rate limit of its traffic class, e.g. "auth".
+func RecordRateLimitedRequest(ctx context.Context, class string) {
+	rateLimitedRequests.Add(ctx, 1, metric.WithAttributes(attribute.String("class", class)))
+}
+
+// PoolStats are connection counts of a database pool.
+type PoolStats struct {
+	Acquired int64 // Connections in use
+	Idle     int64 // Connections ready to be acquired
+	Max      int64 // Maximum size of the pool
+}
+
+// ObservePool reports the connections of the named pool, e.g. "primary", as gauges on every collection,
+// so pool saturation can be monitored. stats is called during collection.
+func ObservePool(pool string, stats func() PoolStats) error {
+	_, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		s := stats()
+		for state, n := range map[string]int64{"acquired": s.Acquired, "idle": s.Idle, "max": s.Max} {
+			o.ObserveInt64(poolConnections, n, metric.WithAttributes(
+				attribute.String("pool", pool),
+				attribute.String("state", state),
+			))
+		}
+		return nil
+	}, poolConnections)
+	return err
+}
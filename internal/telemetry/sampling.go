@@ -0,0 +1,79 @@
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Sampling contains the trace sampling settings.
+type Sampling struct {
+	Ratio       float64 // Share of traces started by the application
+	ParentBased bool    // Follow the sampling decision of the parent span, e.g. of callers propagated in trace headers
+	Errors      bool    // Export failed spans of traces that are not sampled, see ErrorSpanProcessor
+}
+
+// NewSampler returns the sampler of the settings. With Errors set, spans of traces that are not sampled
+// are still recorded, so ErrorSpanProcessor can export those that fail. Recording costs memory and CPU per span,
+// but they are not exported unless they fail.
+func NewSampler(s Sampling) sdktrace.Sampler {
+	sampler := sdktrace.TraceIDRatioBased(s.Ratio)
+	if s.ParentBased {
+		sampler = sdktrace.ParentBased(sampler)
+	}
+	if s.Errors {
+		sampler = recordingSampler{sampler}
+	}
+	return sampler
+}
+
+// recordingSampler records spans its sampler drops, without sampling them.
+type recordingSampler struct {
+	sdktrace.Sampler
+}
+
+func (s recordingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.Sampler.ShouldSample(p)
+	if result.Decision == sdktrace.Drop {
+		result.Decision = sdktrace.RecordOnly
+	}
+	return result
+}
+
+func (s recordingSampler) Description() string {
+	return "RecordingSampler{" + s.Sampler.Description() + "}"
+}
+
+// ErrorSpanProcessor forwards ended spans to its processor if they are sampled or failed.
+// Failed spans of traces that are not sampled are forwarded as sampled, since processors export only sampled spans.
+type ErrorSpanProcessor struct {
+	sdktrace.SpanProcessor
+}
+
+// NewErrorSpanProcessor creates a processor forwarding sampled and failed spans to next.
+func NewErrorSpanProcessor(next sdktrace.SpanProcessor) *ErrorSpanProcessor {
+	return &ErrorSpanProcessor{SpanProcessor: next}
+}
+
+func (p *ErrorSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	switch {
+	case s.SpanContext().IsSampled():
+		p.SpanProcessor.OnEnd(s)
+	case s.Status().Code == codes.Error:
+		p.SpanProcessor.OnEnd(sampledSpan{s})
+	}
+}
+
+// OnStart does not forward spans, they are forwarded once they end and their status is known.
+func (p *ErrorSpanProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+// sampledSpan is a span marked as sampled.
+type sampledSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	return s.ReadOnlySpan.SpanContext().WithTraceFlags(s.ReadOnlySpan.SpanContext().TraceFlags().WithSampled(true))
+}
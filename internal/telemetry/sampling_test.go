@@ -0,0 +1,94 @@
+package telemetry_test
+
+import (
+	"context"
+	"product-api/internal/telemetry"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newSampledProvider returns a tracer provider sampling with the settings, and the recorder of its exported spans.
+func newSampledProvider(s telemetry.Sampling) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	var processor sdktrace.SpanProcessor = sdktrace.NewSimpleSpanProcessor(exporter)
+	if s.Errors {
+		processor = telemetry.NewErrorSpanProcessor(processor)
+	}
+	return sdktrace.NewTracerProvider(sdktrace.WithSampler(telemetry.NewSampler(s)), sdktrace.WithSpanProcessor(processor)), exporter
+}
+
+func TestSampler_ErrorsOfUnsampledTraces(t *testing.T) {
+	tp, exporter := newSampledProvider(telemetry.Sampling{Ratio: 0, ParentBased: true, Errors: true})
+	tracer := tp.Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "request")
+	_, ok := tracer.Start(ctx, "ok")
+	ok.End()
+	_, failed := tracer.Start(ctx, "failed")
+	failed.SetStatus(codes.Error, "insufficient stock")
+	failed.End()
+	root.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "failed", spans[0].Name)
+	assert.True(t, spans[0].SpanContext.IsSampled())
+	assert.False(t, root.SpanContext().IsSampled(), "trace flags propagated to callees are not changed")
+}
+
+func TestSampler_WithoutErrors(t *testing.T) {
+	tp, exporter := newSampledProvider(telemetry.Sampling{Ratio: 0, ParentBased: true})
+
+	_, span := tp.Tracer("test").Start(context.Background(), "failed")
+	span.SetStatus(codes.Error, "insufficient stock")
+	span.End()
+
+	assert.Empty(t, exporter.GetSpans())
+	assert.False(t, span.IsRecording())
+}
+
+func TestSampler_ParentBased(t *testing.T) {
+	remote := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), remote)
+
+	tests := []struct {
+		name        string
+		parentBased bool
+		sampled     bool
+		wantSpans   int
+	}{
+		{name: "follows sampled caller", parentBased: true, sampled: true, wantSpans: 1},
+		{name: "ignores caller", parentBased: false, sampled: false, wantSpans: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, exporter := newSampledProvider(telemetry.Sampling{Ratio: 0, ParentBased: tt.parentBased, Errors: true})
+
+			_, span := tp.Tracer("test").Start(ctx, "request")
+			span.End()
+
+			assert.Equal(t, tt.sampled, span.SpanContext().IsSampled())
+			assert.Len(t, exporter.GetSpans(), tt.wantSpans)
+		})
+	}
+}
+
+func TestSampler_Ratio(t *testing.T) {
+	tp, exporter := newSampledProvider(telemetry.Sampling{Ratio: 1, ParentBased: true, Errors: true})
+
+	_, span := tp.Tracer("test").Start(context.Background(), "request")
+	span.End()
+
+	assert.Len(t, exporter.GetSpans(), 1)
+}
@@ -0,0 +1,33 @@
+// Package telemetry provides helpers for OpenTelemetry instrumentation of the application.
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifies spans and metrics created by the application.
+const InstrumentationName = "product-api"
+
+// StartSpan starts a span with the given name using the global tracer provider.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(InstrumentationName).Start(ctx, name, opts...)
+}
+
+// EndSpan records err (if any) on the span, sets the span status and ends the span.
+// Intended to be deferred with a named error result:
+//
+//	ctx, span := telemetry.StartSpan(ctx, op)
+//	defer func() { telemetry.EndSpan(span, err) }()
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
@@ -0,0 +1,59 @@
+package telemetry_test
+
+import (
+	"context"
+	"errors"
+	"product-api/internal/telemetry"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEndSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	_, span := telemetry.StartSpan(context.Background(), "ok")
+	telemetry.EndSpan(span, nil)
+	_, span = telemetry.StartSpan(context.Background(), "failed")
+	telemetry.EndSpan(span, errors.New("insufficient stock"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Ok, spans[0].Status().Code)
+	assert.Empty(t, spans[0].Events())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "insufficient stock", spans[1].Status().Description)
+	require.Len(t, spans[1].Events(), 1)
+	assert.Equal(t, "exception", spans[1].Events()[0].Name)
+}
+
+func TestRecordOrderCreation(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	ctx := context.Background()
+	telemetry.RecordOrderCreation(ctx, 200*time.Millisecond, telemetry.OutcomeSuccess, 99.5)
+	telemetry.RecordOrderCreation(ctx, 50*time.Millisecond, "insufficient_stock", 0)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	histograms := map[string]metricdata.Histogram[float64]{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		histograms[m.Name] = m.Data.(metricdata.Histogram[float64])
+	}
+	assert.Len(t, histograms["orders.create.duration"].DataPoints, 2) // One per outcome
+	require.Len(t, histograms["orders.value"].DataPoints, 1)
+	assert.Equal(t, uint64(1), histograms["orders.value"].DataPoints[0].Count) // Failed orders have no value
+	assert.Equal(t, 99.5, histograms["orders.value"].DataPoints[0].Sum)
+}
@@ -0,0 +1,247 @@
+// Package factory provides builders of domain objects with sensible defaults for tests.
+//
+// NewX functions build objects in memory, CreateX functions also persist them
+// and fail the test on error:
+//
+//	user := factory.CreateUser(t, userRepo, factory.WithAge(17))
+//	product := factory.CreateProduct(t, productRepo, factory.WithQuantity(5))
+package factory
+
+import (
+	"context"
+	"fmt"
+	"product-api/internal/domain"
+	"product-api/internal/repository"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefaultPassword is the password of users built without WithPassword.
+const DefaultPassword = "password123"
+
+// sequence makes generated emails unique within a test run.
+var sequence atomic.Int64
+
+// defaultPasswordHash is computed once, as bcrypt is slow even at minimum cost.
+var defaultPasswordHash = sync.OnceValue(func() string {
+	return hashPassword(DefaultPassword)
+})
+
+// hashPassword hashes the password with minimum bcrypt cost to keep tests fast.
+func hashPassword(password string) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		panic(fmt.Sprintf("factory: failed to hash password: %v", err))
+	}
+	return string(hash)
+}
+
+// UserOption customizes a user built by NewUser.
+type UserOption func(*domain.User)
+
+// WithEmail sets the user email.
+func WithEmail(email string) UserOption {
+	return func(u *domain.User) { u.Email = email }
+}
+
+// WithUsername sets the normalized username.
+func WithUsername(username string) UserOption {
+	return func(u *domain.User) { u.Username = username }
+}
+
+// WithPassword sets the password hash for the given password.
+func WithPassword(password string) UserOption {
+	return func(u *domain.User) { u.PasswordHash = hashPassword(password) }
+}
+
+// WithBirthdate sets the user birthdate.
+func WithBirthdate(birthdate time.Time) UserOption {
+	return func(u *domain.User) { u.Birthdate = birthdate }
+}
+
+// WithAge sets the birthdate so the user is the given number of full years old today.
+func WithAge(years int) UserOption {
+	return func(u *domain.User) { u.Birthdate = time.Now().AddDate(-years, 0, -1).Truncate(24 * time.Hour) }
+}
+
+// Inactive builds a disabled (deprovisioned) user.
+func Inactive() UserOption {
+	return func(u *domain.User) { u.IsActive = false }
+}
+
+// WithRole sets the user role.
+func WithRole(role string) UserOption {
+	return func(u *domain.User) { u.Role = role }
+}
+
+// NewUser builds an active adult customer with a unique email and DefaultPassword.
+func NewUser(opts ...UserOption) *domain.User {
+	n := sequence.Add(1)
+	user := &domain.User{
+		ID:           uuid.New(),
+		Firstname:    "John",
+		Lastname:     "Doe",
+		Email:        fmt.Sprintf("user%d-%s@example.com", n, uuid.NewString()[:8]),
+		Birthdate:    time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		PasswordHash: defaultPasswordHash(),
+		IsActive:     true,
+		Role:         domain.RoleCustomer,
+	}
+	for _, opt := range opts {
+		opt(user)
+	}
+	return user
+}
+
+// CreateUser builds a user and saves it to the repository.
+func CreateUser(t testing.TB, repo repository.UserRepository, opts ...UserOption) *domain.User {
+	t.Helper()
+	user := NewUser(opts...)
+	require.NoError(t, repo.Create(context.Background(), user), "factory: failed to create user")
+	return user
+}
+
+// ProductOption customizes a product built by NewProduct.
+type ProductOption func(*domain.Product)
+
+// WithDescription sets the product description.
+func WithDescription(description string) ProductOption {
+	return func(p *domain.Product) { p.Description = description }
+}
+
+// WithTags sets the product tags.
+func WithTags(tags ...string) ProductOption {
+	return func(p *domain.Product) { p.Tags = tags }
+}
+
+// WithQuantity sets the quantity in stock.
+func WithQuantity(quantity int) ProductOption {
+	return func(p *domain.Product) { p.Quantity = quantity }
+}
+
+// WithPrice sets the product price.
+func WithPrice(price float64) ProductOption {
+	return func(p *domain.Product) { p.Price = price }
+}
+
+// WithAgeRestriction sets the minimum buyer age.
+func WithAgeRestriction(age int) ProductOption {
+	return func(p *domain.Product) { p.AgeRestriction = age }
+}
+
+// WithStatus sets the product lifecycle status.
+func WithStatus(status string) ProductOption {
+	return func(p *domain.Product) { p.Status = status }
+}
+
+// WithBarcode sets the product barcode.
+func WithBarcode(barcode string) ProductOption {
+	return func(p *domain.Product) { p.Barcode = barcode }
+}
+
+// WithCategory sets the product category.
+func WithCategory(category string) ProductOption {
+	return func(p *domain.Product) { p.Category = category }
+}
+
+// WithComponents makes the product a bundle of the components.
+func WithComponents(components ...domain.BundleComponent) ProductOption {
+	return func(p *domain.Product) { p.Components = components }
+}
+
+// NewProduct builds an active unrestricted product with 10 items in stock.
+func NewProduct(opts ...ProductOption) *domain.Product {
+	product := &domain.Product{
+		ID:          uuid.New(),
+		Description: "Test Product",
+		Tags:        []string{"test"},
+		Quantity:    10,
+		Price:       99.99,
+		Status:      domain.ProductStatusActive,
+	}
+	for _, opt := range opts {
+		opt(product)
+	}
+	return product
+}
+
+// CreateProduct builds a product and saves it to the repository.
+func CreateProduct(t testing.TB, repo repository.ProductRepository, opts ...ProductOption) *domain.Product {
+	t.Helper()
+	product := NewProduct(opts...)
+	require.NoError(t, repo.Create(context.Background(), product), "factory: failed to create product")
+	return product
+}
+
+// OrderOption customizes an order built by NewOrder.
+type OrderOption func(*domain.Order)
+
+// WithItem adds an item with the product's current price and updates the order total.
+func WithItem(product *domain.Product, quantity int) OrderOption {
+	return func(o *domain.Order) {
+		o.Items = append(o.Items, domain.OrderItem{
+			ID:              uuid.New(),
+			ProductID:       product.ID,
+			Quantity:        quantity,
+			PriceAtPurchase: product.Price,
+		})
+		o.TotalAmount += product.Price * float64(quantity)
+	}
+}
+
+// WithOption adds an order option with the fee and updates the order total.
+func WithOption(option string, fee float64) OrderOption {
+	return func(o *domain.Order) {
+		o.Options = append(o.Options, domain.OrderOption{Option: option, Fee: fee})
+		o.TotalAmount += fee
+	}
+}
+
+// WithCreatedAt sets the order creation time.
+func WithCreatedAt(createdAt time.Time) OrderOption {
+	return func(o *domain.Order) { o.CreatedAt = createdAt }
+}
+
+// WithSettings sets the currency, locale and tax region the order was placed with.
+func WithSettings(settings domain.OrderSettings) OrderOption {
+	return func(o *domain.Order) { o.OrderSettings = settings }
+}
+
+// NewOrder builds an order of the user without items. Its number is unique but not sequential.
+func NewOrder(userID uuid.UUID, opts ...OrderOption) *domain.Order {
+	id := uuid.New()
+	order := &domain.Order{
+		ID:        id,
+		Number:    "TEST-" + id.String(),
+		UserID:    userID,
+		Status:    domain.OrderStatusCreated,
+		CreatedAt: time.Now(),
+	}
+	for _, opt := range opts {
+		opt(order)
+	}
+	return order
+}
+
+// CreateOrder builds an order and saves it to the repository in its own transaction.
+// Product stock is not changed and no order events are recorded.
+func CreateOrder(t testing.TB, db *pgxpool.Pool, repo repository.OrderRepository, userID uuid.UUID, opts ...OrderOption) *domain.Order {
+	t.Helper()
+	ctx := context.Background()
+	order := NewOrder(userID, opts...)
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err, "factory: failed to begin transaction")
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	require.NoError(t, repo.CreateTx(ctx, tx, order), "factory: failed to create order")
+	require.NoError(t, tx.Commit(ctx), "factory: failed to commit order")
+	return order
+}
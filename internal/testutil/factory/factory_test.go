@@ -0,0 +1,33 @@
+package factory_test
+
+import (
+	"product-api/internal/testutil/factory"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestNewUser(t *testing.T) {
+	first := factory.NewUser()
+	second := factory.NewUser(factory.WithAge(17), factory.Inactive())
+
+	assert.NotEqual(t, first.Email, second.Email)
+	assert.True(t, first.IsActive)
+	assert.False(t, second.IsActive)
+	assert.Equal(t, 17, second.Age())
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(first.PasswordHash), []byte(factory.DefaultPassword)))
+}
+
+func TestNewOrder(t *testing.T) {
+	product := factory.NewProduct(factory.WithPrice(2.5))
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	order := factory.NewOrder(factory.NewUser().ID, factory.WithItem(product, 4), factory.WithCreatedAt(createdAt))
+
+	assert.Len(t, order.Items, 1)
+	assert.Equal(t, product.ID, order.Items[0].ProductID)
+	assert.Equal(t, 10.0, order.TotalAmount)
+	assert.Equal(t, createdAt, order.CreatedAt)
+}
@@ -0,0 +1,143 @@
+// Package testdb provides isolated PostgreSQL databases for integration tests.
+//
+// All tests share one test database; every test gets its own schema with all
+// migrations applied, so tests and suites can run in parallel without
+// cleaning up shared tables.
+package testdb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// duplicateDatabaseCode is the PostgreSQL error code for an already existing database.
+const duplicateDatabaseCode = "42P04"
+
+var (
+	setupOnce sync.Once
+	setupErr  error
+)
+
+// url returns the connection URL of the database, taking credentials from DB_USER and DB_PASSWORD.
+func url(dbName string) string {
+	return "postgres://" + os.Getenv("DB_USER") + ":" + os.Getenv("DB_PASSWORD") + "@localhost:5434/" + dbName + "?sslmode=disable"
+}
+
+// databaseName returns the name of the shared test database.
+func databaseName() string {
+	return os.Getenv("DB_NAME") + "_test"
+}
+
+// migrationsDir returns the path of the migrations directory relative to this file.
+func migrationsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "..", "migrations")
+}
+
+// setup creates the shared test database if it does not exist.
+// Other test packages may be creating it concurrently.
+func setup() error {
+	var (
+		maintenanceDb *pgxpool.Pool
+		err           error
+	)
+	for i := 0; i < 10; i++ {
+		maintenanceDb, err = pgxpool.New(context.Background(), url("postgres"))
+		if err == nil {
+			err = maintenanceDb.Ping(context.Background())
+		}
+		if err == nil {
+			break
+		}
+		log.Printf("Failed to connect to maintenance db, retrying in 2 seconds...: %v", err)
+		time.Sleep(2 * time.Second)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to maintenance database after retries: %w", err)
+	}
+	defer maintenanceDb.Close()
+
+	_, err = maintenanceDb.Exec(context.Background(), "CREATE DATABASE "+databaseName())
+	var pgErr *pgconn.PgError
+	if err != nil && !(errors.As(err, &pgErr) && pgErr.Code == duplicateDatabaseCode) {
+		return fmt.Errorf("failed to create test database: %w", err)
+	}
+	return nil
+}
+
+// New creates a schema with all migrations applied and returns a pool whose connections use it.
+// The pool is closed and the schema dropped when the test finishes. Suites calling New in SetupTest
+// give every test its own schema, so tests need no cleanup between them.
+func New(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+
+	setupOnce.Do(func() { setupErr = setup() })
+	if setupErr != nil {
+		t.Fatal(setupErr)
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		t.Fatalf("failed to generate schema name: %v", err)
+	}
+	schema := "test_" + hex.EncodeToString(suffix)
+	dbURL := url(databaseName())
+
+	ctx := context.Background()
+	admin, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		admin.Close()
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		defer admin.Close()
+		if _, err := admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+			t.Errorf("failed to drop schema %s: %v", schema, err)
+		}
+	})
+
+	// Migrations and the migration version table are created in the test schema
+	m, err := migrate.New("file://"+migrationsDir(), dbURL+"&search_path="+schema)
+	if err != nil {
+		t.Fatalf("failed to initialize migrations: %v", err)
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+	if srcErr, dbErr := m.Close(); srcErr != nil || dbErr != nil {
+		t.Fatalf("failed to close migrations: %v, %v", srcErr, dbErr)
+	}
+
+	config, err := pgxpool.ParseConfig(dbURL)
+	if err != nil {
+		t.Fatalf("invalid test database url: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to connect to test schema: %v", err)
+	}
+	t.Cleanup(pool.Close) // Registered last, runs before the schema is dropped
+
+	return pool
+}
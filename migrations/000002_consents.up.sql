@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS legal_documents (
+    id UUID PRIMARY KEY,
+    type VARCHAR(32) NOT NULL,
+    version VARCHAR(64) NOT NULL,
+    published_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (type, version)
+);
+
+CREATE TABLE IF NOT EXISTS user_consents (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    document_type VARCHAR(32) NOT NULL,
+    document_version VARCHAR(64) NOT NULL,
+    accepted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (document_type, document_version) REFERENCES legal_documents(type, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_consents_user_id ON user_consents(user_id);
@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS age INT;
+
+UPDATE users
+SET age = EXTRACT(YEAR FROM AGE(CURRENT_DATE, birthdate))::INT
+WHERE age IS NULL;
+
+ALTER TABLE users ALTER COLUMN age SET NOT NULL;
+ALTER TABLE users DROP COLUMN IF EXISTS birthdate;
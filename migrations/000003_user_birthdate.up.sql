@@ -0,0 +1,12 @@
+-- Replace static age with birthdate.
+-- Existing users are backfilled with an approximate birthdate derived from their age
+-- at the time of migration (January 1st of the corresponding year), which keeps them
+-- on the same side of the 18+ rule.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS birthdate DATE;
+
+UPDATE users
+SET birthdate = make_date(EXTRACT(YEAR FROM CURRENT_DATE)::INT - age, 1, 1)
+WHERE birthdate IS NULL;
+
+ALTER TABLE users ALTER COLUMN birthdate SET NOT NULL;
+ALTER TABLE users DROP COLUMN IF EXISTS age;
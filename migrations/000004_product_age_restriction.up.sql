@@ -0,0 +1 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS age_restriction INT NOT NULL DEFAULT 0 CHECK (age_restriction >= 0);
@@ -0,0 +1,3 @@
+ALTER TABLE login_attempts RENAME COLUMN login TO email;
+DROP INDEX IF EXISTS idx_users_username;
+ALTER TABLE users DROP COLUMN IF EXISTS username;
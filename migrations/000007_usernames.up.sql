@@ -0,0 +1,6 @@
+-- Usernames are optional and stored normalized (lowercase), so a plain unique index enforces case-insensitive uniqueness.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS username VARCHAR(32);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users(username) WHERE username IS NOT NULL;
+
+-- Login history stores the identifier used for the attempt, which is now either an email or a username.
+ALTER TABLE login_attempts RENAME COLUMN email TO login;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS order_items_total_matches_order ON order_items;
+DROP TRIGGER IF EXISTS orders_total_matches_items ON orders;
+DROP FUNCTION IF EXISTS check_order_total();
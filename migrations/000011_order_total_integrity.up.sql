@@ -0,0 +1,41 @@
+-- Orders total must equal the sum of item price × quantity.
+-- Checked at commit time, as order and items are inserted by separate statements.
+CREATE OR REPLACE FUNCTION check_order_total() RETURNS TRIGGER AS $$
+DECLARE
+    target_order_id UUID;
+    stored NUMERIC(10, 2);
+    computed NUMERIC(10, 2);
+BEGIN
+    IF TG_TABLE_NAME = 'orders' THEN
+        target_order_id := NEW.id;
+    ELSIF TG_OP = 'DELETE' THEN
+        target_order_id := OLD.order_id;
+    ELSE
+        target_order_id := NEW.order_id;
+    END IF;
+
+    SELECT total_amount INTO stored FROM orders WHERE id = target_order_id;
+    IF NOT FOUND THEN
+        RETURN NULL; -- Order was deleted together with its items
+    END IF;
+
+    SELECT COALESCE(SUM(price_at_purchase * quantity), 0) INTO computed
+    FROM order_items WHERE order_id = target_order_id;
+
+    IF stored <> computed THEN
+        RAISE EXCEPTION 'order % total % does not match items total %', target_order_id, stored, computed
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'orders_total_matches_items';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER orders_total_matches_items
+    AFTER INSERT OR UPDATE OF total_amount ON orders
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_order_total();
+
+CREATE CONSTRAINT TRIGGER order_items_total_matches_order
+    AFTER INSERT OR UPDATE OR DELETE ON order_items
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_order_total();
@@ -0,0 +1,4 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_quantity_non_negative;
+DROP TRIGGER IF EXISTS stock_movements_append_only ON stock_movements;
+DROP FUNCTION IF EXISTS reject_stock_movement_change();
+DROP TABLE IF EXISTS stock_movements;
@@ -0,0 +1,32 @@
+-- Append-only ledger of stock changes. products.quantity is a cached projection of it.
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id),
+    delta INT NOT NULL CHECK (delta <> 0),
+    reason VARCHAR(32) NOT NULL CHECK (reason IN ('receipt', 'allocation', 'adjustment', 'return')),
+    -- Deferred, as stock is allocated before the order row is inserted
+    order_id UUID REFERENCES orders(id) DEFERRABLE INITIALLY DEFERRED,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_id_created_at ON stock_movements(product_id, created_at DESC);
+
+-- Opening balance for existing stock
+INSERT INTO stock_movements (id, product_id, delta, reason, note)
+SELECT gen_random_uuid(), id, quantity, 'adjustment', 'opening balance'
+FROM products
+WHERE quantity <> 0;
+
+CREATE OR REPLACE FUNCTION reject_stock_movement_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'stock movements are append-only'
+        USING ERRCODE = 'restrict_violation';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stock_movements_append_only
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION reject_stock_movement_change();
+
+ALTER TABLE products ADD CONSTRAINT products_quantity_non_negative CHECK (quantity >= 0);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS order_events_append_only ON order_events;
+DROP FUNCTION IF EXISTS reject_order_event_change();
+DROP TABLE IF EXISTS order_events;
+ALTER TABLE orders DROP COLUMN IF EXISTS status;
@@ -0,0 +1,41 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS status VARCHAR(32) NOT NULL DEFAULT 'created'
+    CHECK (status IN ('created', 'paid', 'shipped', 'cancelled'));
+
+-- Append-only event log of orders. orders.status and order_items are a projection of it.
+CREATE TABLE IF NOT EXISTS order_events (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    sequence INT NOT NULL CHECK (sequence > 0),
+    type VARCHAR(32) NOT NULL CHECK (type IN ('created', 'item_added', 'paid', 'shipped', 'cancelled')),
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- Concurrent appends to the same order conflict on the sequence
+    UNIQUE (order_id, sequence)
+);
+
+-- Events of existing orders
+INSERT INTO order_events (id, order_id, sequence, type, payload, created_at)
+SELECT gen_random_uuid(), id, 1, 'created', jsonb_build_object('UserID', user_id), created_at
+FROM orders;
+
+INSERT INTO order_events (id, order_id, sequence, type, payload, created_at)
+SELECT gen_random_uuid(), oi.order_id, 1 + ROW_NUMBER() OVER (PARTITION BY oi.order_id ORDER BY oi.id), 'item_added',
+       jsonb_build_object('Item', jsonb_build_object(
+           'ID', oi.id, 'ProductID', oi.product_id, 'Quantity', oi.quantity, 'PriceAtPurchase', oi.price_at_purchase)),
+       o.created_at
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id;
+
+CREATE OR REPLACE FUNCTION reject_order_event_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND NOT EXISTS (SELECT 1 FROM orders WHERE id = OLD.order_id) THEN
+        RETURN OLD; -- Cascading delete of the order
+    END IF;
+    RAISE EXCEPTION 'order events are append-only'
+        USING ERRCODE = 'restrict_violation';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER order_events_append_only
+    BEFORE UPDATE OR DELETE ON order_events
+    FOR EACH ROW EXECUTE FUNCTION reject_order_event_change();
@@ -0,0 +1,5 @@
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_reason_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_reason_check
+    CHECK (reason IN ('receipt', 'allocation', 'adjustment', 'return'));
+
+ALTER TABLE orders DROP COLUMN IF EXISTS payment_id;
@@ -0,0 +1,6 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_id VARCHAR(255);
+
+-- Stock allocated to cancelled orders is released back through the ledger
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_reason_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_reason_check
+    CHECK (reason IN ('receipt', 'allocation', 'adjustment', 'return', 'release'));
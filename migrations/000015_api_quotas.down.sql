@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS api_quota_usage;
+DROP TABLE IF EXISTS api_quotas;
@@ -0,0 +1,17 @@
+-- Call quotas per API client, overriding the defaults from configuration
+CREATE TABLE IF NOT EXISTS api_quotas (
+    client VARCHAR(64) NOT NULL,
+    period VARCHAR(16) NOT NULL CHECK (period IN ('day', 'month')),
+    quota_limit INT NOT NULL CHECK (quota_limit >= 0), -- 0 disables the quota
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (client, period)
+);
+
+-- Calls made by each API client per quota window
+CREATE TABLE IF NOT EXISTS api_quota_usage (
+    client VARCHAR(64) NOT NULL,
+    period VARCHAR(16) NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL,
+    calls INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (client, period, window_start)
+);
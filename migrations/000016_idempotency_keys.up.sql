@@ -0,0 +1,14 @@
+-- Responses of write requests by Idempotency-Key, replayed when a client retries the request
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope VARCHAR(512) NOT NULL, -- Caller, method and path the key was used with
+    key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL, -- SHA-256 of the request body
+    status_code INT, -- NULL while the request is in progress
+    content_type VARCHAR(255) NOT NULL DEFAULT '',
+    response_body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
@@ -0,0 +1,10 @@
+-- Field-level history of product edits for catalog governance.
+CREATE TABLE IF NOT EXISTS product_revisions (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id),
+    actor TEXT NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    changes JSONB NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_revisions_product_id_changed_at ON product_revisions(product_id, changed_at DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS bundle_item_id;
+ALTER TABLE order_items DROP COLUMN IF EXISTS bundle;
+DROP TABLE IF EXISTS product_bundle_components;
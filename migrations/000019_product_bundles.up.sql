@@ -0,0 +1,12 @@
+-- Bundles are products composed of other products. Their stock is the stock of their components.
+CREATE TABLE IF NOT EXISTS product_bundle_components (
+    bundle_id UUID NOT NULL REFERENCES products(id),
+    component_id UUID NOT NULL REFERENCES products(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (bundle_id, component_id),
+    CHECK (bundle_id <> component_id)
+);
+
+-- A bundle line is followed by its component lines, which carry the allocated stock
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS bundle BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS bundle_item_id UUID REFERENCES order_items(id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_products_barcode;
+ALTER TABLE products DROP COLUMN IF EXISTS barcode;
@@ -0,0 +1,16 @@
+-- Typed attributes of products, defined per category
+CREATE TABLE IF NOT EXISTS attribute_definitions (
+    category TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('string', 'number', 'integer', 'boolean', 'enum')),
+    allowed_values TEXT[] NOT NULL DEFAULT '{}',
+    unit TEXT NOT NULL DEFAULT '',
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (category, name)
+);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category TEXT;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_products_category ON products(category);
+CREATE INDEX IF NOT EXISTS idx_products_attributes ON products USING GIN (attributes jsonb_path_ops);
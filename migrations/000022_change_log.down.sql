@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS users_change_log ON users;
+DROP TRIGGER IF EXISTS order_items_change_log ON order_items;
+DROP TRIGGER IF EXISTS orders_change_log ON orders;
+DROP TRIGGER IF EXISTS products_change_log ON products;
+DROP FUNCTION IF EXISTS log_change();
+DROP TABLE IF EXISTS change_log;
@@ -0,0 +1,47 @@
+-- Ordered log of product, order and user mutations for incremental sync of downstream systems.
+-- Rows are written by triggers, so every mutation is captured, including set-based updates.
+CREATE TABLE IF NOT EXISTS change_log (
+    sequence BIGSERIAL PRIMARY KEY,
+    -- Sequences are not assigned in commit order. The feed is ordered by transaction and sequence
+    -- and only returns changes of finished transactions, so readers never skip a change committed late.
+    txid XID8 NOT NULL DEFAULT pg_current_xact_id(),
+    entity VARCHAR(16) NOT NULL CHECK (entity IN ('product', 'order', 'order_item', 'user')),
+    entity_id UUID NOT NULL,
+    operation VARCHAR(16) NOT NULL CHECK (operation IN ('insert', 'update', 'delete')),
+    data JSONB, -- Row after the change, NULL for deletes
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_change_log_txid_sequence ON change_log(txid, sequence);
+
+CREATE OR REPLACE FUNCTION log_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO change_log (entity, entity_id, operation) VALUES (TG_ARGV[0], OLD.id, 'delete');
+        RETURN OLD;
+    END IF;
+    IF TG_OP = 'UPDATE' AND to_jsonb(OLD) = to_jsonb(NEW) THEN
+        RETURN NEW;
+    END IF;
+    -- Credentials never leave the database
+    INSERT INTO change_log (entity, entity_id, operation, data)
+    VALUES (TG_ARGV[0], NEW.id, lower(TG_OP), to_jsonb(NEW) - 'password_hash');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER products_change_log
+    AFTER INSERT OR UPDATE OR DELETE ON products
+    FOR EACH ROW EXECUTE FUNCTION log_change('product');
+
+CREATE TRIGGER orders_change_log
+    AFTER INSERT OR UPDATE OR DELETE ON orders
+    FOR EACH ROW EXECUTE FUNCTION log_change('order');
+
+CREATE TRIGGER order_items_change_log
+    AFTER INSERT OR UPDATE OR DELETE ON order_items
+    FOR EACH ROW EXECUTE FUNCTION log_change('order_item');
+
+CREATE TRIGGER users_change_log
+    AFTER INSERT OR UPDATE OR DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION log_change('user');
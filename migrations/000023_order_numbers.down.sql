@@ -0,0 +1,8 @@
+ALTER TABLE order_events DISABLE TRIGGER order_events_append_only;
+UPDATE order_events SET payload = payload - 'Number' WHERE type = 'created';
+ALTER TABLE order_events ENABLE TRIGGER order_events_append_only;
+
+DROP INDEX IF EXISTS idx_orders_number;
+ALTER TABLE orders DROP COLUMN IF EXISTS number;
+DROP TABLE IF EXISTS order_number_counters;
+DROP TABLE IF EXISTS order_number_settings;
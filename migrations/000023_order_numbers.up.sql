@@ -0,0 +1,44 @@
+-- Format of human-friendly order numbers, e.g. ORD-2024-000123. Single row.
+CREATE TABLE IF NOT EXISTS order_number_settings (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    prefix VARCHAR(16) NOT NULL,
+    padding INT NOT NULL CHECK (padding BETWEEN 1 AND 12)
+);
+
+INSERT INTO order_number_settings (prefix, padding) VALUES ('ORD', 6) ON CONFLICT DO NOTHING;
+
+-- Last order number of each year. Counters are incremented in the transaction creating the order,
+-- so numbers of rolled back orders are reused and the sequence has no gaps.
+CREATE TABLE IF NOT EXISTS order_number_counters (
+    year INT PRIMARY KEY,
+    last_value BIGINT NOT NULL CHECK (last_value > 0)
+);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS number VARCHAR(48);
+
+-- Numbers of existing orders in creation order
+WITH numbered AS (
+    SELECT id, EXTRACT(YEAR FROM created_at)::int AS year,
+           ROW_NUMBER() OVER (PARTITION BY EXTRACT(YEAR FROM created_at) ORDER BY created_at, id) AS value
+    FROM orders
+)
+UPDATE orders o
+SET number = 'ORD-' || n.year || '-' || LPAD(n.value::text, 6, '0')
+FROM numbered n
+WHERE o.id = n.id;
+
+INSERT INTO order_number_counters (year, last_value)
+SELECT EXTRACT(YEAR FROM created_at)::int, COUNT(*)
+FROM orders
+GROUP BY 1;
+
+ALTER TABLE orders ALTER COLUMN number SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_number ON orders(number);
+
+-- Replayed orders get their number from the created event
+ALTER TABLE order_events DISABLE TRIGGER order_events_append_only;
+UPDATE order_events e
+SET payload = e.payload || jsonb_build_object('Number', o.number)
+FROM orders o
+WHERE o.id = e.order_id AND e.type = 'created';
+ALTER TABLE order_events ENABLE TRIGGER order_events_append_only;
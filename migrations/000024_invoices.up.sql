@@ -0,0 +1,30 @@
+-- Last invoice number of each fiscal year. Incremented in the transaction issuing the invoice,
+-- so numbers of rolled back invoices are reused and the sequence has no gaps.
+CREATE TABLE IF NOT EXISTS invoice_counters (
+    fiscal_year INT PRIMARY KEY,
+    last_value BIGINT NOT NULL CHECK (last_value > 0)
+);
+
+-- Issued invoices. Invoices are legal documents and cannot be changed or deleted.
+CREATE TABLE IF NOT EXISTS invoices (
+    id UUID PRIMARY KEY,
+    number VARCHAR(32) NOT NULL UNIQUE,
+    fiscal_year INT NOT NULL,
+    sequence BIGINT NOT NULL CHECK (sequence > 0),
+    order_id UUID NOT NULL UNIQUE REFERENCES orders(id),
+    amount NUMERIC(10, 2) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    issued_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (fiscal_year, sequence)
+);
+
+CREATE OR REPLACE FUNCTION reject_invoice_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'invoices cannot be changed'
+        USING ERRCODE = 'restrict_violation';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER invoices_immutable
+    BEFORE UPDATE OR DELETE ON invoices
+    FOR EACH ROW EXECUTE FUNCTION reject_invoice_change();
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS order_payments_append_only ON order_payments;
+DROP FUNCTION IF EXISTS reject_order_payment_change();
+DROP TABLE IF EXISTS order_payments;
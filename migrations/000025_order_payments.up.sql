@@ -0,0 +1,36 @@
+-- Ledger of payments and refunds of orders. An order can be paid with several payments
+-- (e.g. gift card and card, deposits), each can be partially refunded.
+CREATE TABLE IF NOT EXISTS order_payments (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('charge', 'refund')),
+    method VARCHAR(32) NOT NULL CHECK (method IN ('card', 'gift_card', 'bank_transfer', 'cash')),
+    reference VARCHAR(255),
+    amount NUMERIC(10, 2) NOT NULL CHECK (amount > 0),
+    refund_of UUID REFERENCES order_payments(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- Refunds reference the refunded charge
+    CHECK ((kind = 'refund') = (refund_of IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_payments_order_id ON order_payments(order_id, created_at);
+
+-- Checkout charges of existing orders
+INSERT INTO order_payments (id, order_id, kind, method, reference, amount, created_at)
+SELECT gen_random_uuid(), id, 'charge', 'card', payment_id, total_amount, created_at
+FROM orders
+WHERE payment_id IS NOT NULL AND total_amount > 0;
+
+CREATE OR REPLACE FUNCTION reject_order_payment_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND NOT EXISTS (SELECT 1 FROM orders WHERE id = OLD.order_id) THEN
+        RETURN OLD; -- Cascading delete of the order
+    END IF;
+    RAISE EXCEPTION 'order payments are append-only'
+        USING ERRCODE = 'restrict_violation';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER order_payments_append_only
+    BEFORE UPDATE OR DELETE ON order_payments
+    FOR EACH ROW EXECUTE FUNCTION reject_order_payment_change();
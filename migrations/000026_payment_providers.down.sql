@@ -0,0 +1,5 @@
+ALTER TABLE order_payments DROP CONSTRAINT IF EXISTS order_payments_method_check;
+ALTER TABLE order_payments ADD CONSTRAINT order_payments_method_check
+    CHECK (method IN ('card', 'gift_card', 'bank_transfer', 'cash'));
+ALTER TABLE order_payments DROP COLUMN IF EXISTS metadata;
+ALTER TABLE order_payments DROP COLUMN IF EXISTS provider;
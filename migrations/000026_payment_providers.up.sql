@@ -0,0 +1,14 @@
+-- Payments made through a payment provider record it with provider-specific details,
+-- refunds of such payments go through the same provider
+ALTER TABLE order_payments ADD COLUMN IF NOT EXISTS provider VARCHAR(32);
+ALTER TABLE order_payments ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE order_payments DROP CONSTRAINT IF EXISTS order_payments_method_check;
+ALTER TABLE order_payments ADD CONSTRAINT order_payments_method_check
+    CHECK (method IN ('card', 'paypal', 'gift_card', 'bank_transfer', 'cash'));
+
+-- Card payments so far went through the logging gateway, which kept no captures to refund through,
+-- so refunds of them are paid out-of-band
+ALTER TABLE order_payments DISABLE TRIGGER order_payments_append_only;
+UPDATE order_payments SET provider = 'legacy' WHERE method = 'card';
+ALTER TABLE order_payments ENABLE TRIGGER order_payments_append_only;
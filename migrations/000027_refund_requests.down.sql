@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS refund_request_events_append_only ON refund_request_events;
+DROP FUNCTION IF EXISTS reject_refund_request_event_change();
+DROP TABLE IF EXISTS refund_request_events;
+DROP TABLE IF EXISTS refund_requests;
@@ -0,0 +1,47 @@
+-- Refunds requested by support and approved or rejected by finance.
+CREATE TABLE IF NOT EXISTS refund_requests (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    payment_id UUID NOT NULL REFERENCES order_payments(id),
+    amount NUMERIC(10, 2) NOT NULL CHECK (amount > 0),
+    items JSONB NOT NULL DEFAULT '[]', -- Returned order lines with quantities
+    reason TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'requested' CHECK (status IN ('requested', 'approved', 'rejected')),
+    requested_by VARCHAR(255) NOT NULL,
+    decided_by VARCHAR(255),
+    decided_at TIMESTAMPTZ,
+    refund_id UUID REFERENCES order_payments(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((status = 'requested') = (decided_at IS NULL)),
+    CHECK ((status = 'approved') = (refund_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_refund_requests_order_id ON refund_requests(order_id, created_at);
+-- Queue of requests waiting for a decision
+CREATE INDEX IF NOT EXISTS idx_refund_requests_pending ON refund_requests(created_at) WHERE status = 'requested';
+
+-- Audit trail of refund requests: who requested, approved or rejected them and failed refund attempts.
+CREATE TABLE IF NOT EXISTS refund_request_events (
+    id UUID PRIMARY KEY,
+    request_id UUID NOT NULL REFERENCES refund_requests(id) ON DELETE CASCADE,
+    action VARCHAR(32) NOT NULL CHECK (action IN ('requested', 'approved', 'rejected', 'refund_failed')),
+    actor VARCHAR(255) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refund_request_events_request_id ON refund_request_events(request_id, created_at);
+
+CREATE OR REPLACE FUNCTION reject_refund_request_event_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND NOT EXISTS (SELECT 1 FROM refund_requests WHERE id = OLD.request_id) THEN
+        RETURN OLD; -- Cascading delete of the request
+    END IF;
+    RAISE EXCEPTION 'refund request events are append-only'
+        USING ERRCODE = 'restrict_violation';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER refund_request_events_append_only
+    BEFORE UPDATE OR DELETE ON refund_request_events
+    FOR EACH ROW EXECUTE FUNCTION reject_refund_request_event_change();
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_order_payments_provider_reference;
+DROP TABLE IF EXISTS order_disputes;
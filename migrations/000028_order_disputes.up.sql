@@ -0,0 +1,20 @@
+-- Chargebacks and disputes of order payments, kept up to date by payment provider webhooks.
+CREATE TABLE IF NOT EXISTS order_disputes (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    payment_id UUID NOT NULL REFERENCES order_payments(id),
+    provider VARCHAR(32) NOT NULL,
+    provider_dispute_id VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('open', 'won', 'lost')),
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    amount NUMERIC(10, 2) NOT NULL CHECK (amount >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, provider_dispute_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_disputes_order_id ON order_disputes(order_id);
+
+-- Disputes arrive with the provider capture ID of the charge
+CREATE INDEX IF NOT EXISTS idx_order_payments_provider_reference ON order_payments(provider, reference)
+    WHERE kind = 'charge' AND provider IS NOT NULL;
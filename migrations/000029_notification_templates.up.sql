@@ -0,0 +1,11 @@
+-- Versions of notification templates edited by admins. The latest version of each template is sent,
+-- templates without versions use the built-in content.
+CREATE TABLE IF NOT EXISTS notification_templates (
+    name VARCHAR(64) NOT NULL,
+    version INT NOT NULL CHECK (version > 0),
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (name, version)
+);
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS phone_verifications;
+
+ALTER TABLE users DROP COLUMN IF EXISTS phone_verified_at;
+ALTER TABLE users DROP COLUMN IF EXISTS phone;
+
+-- Fails while delivered orders exist
+ALTER TABLE order_events DROP CONSTRAINT IF EXISTS order_events_type_check;
+ALTER TABLE order_events ADD CONSTRAINT order_events_type_check
+    CHECK (type IN ('created', 'item_added', 'paid', 'shipped', 'cancelled'));
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('created', 'paid', 'shipped', 'cancelled'));
@@ -0,0 +1,22 @@
+-- Orders are delivered after they are shipped
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('created', 'paid', 'shipped', 'delivered', 'cancelled'));
+
+ALTER TABLE order_events DROP CONSTRAINT IF EXISTS order_events_type_check;
+ALTER TABLE order_events ADD CONSTRAINT order_events_type_check
+    CHECK (type IN ('created', 'item_added', 'paid', 'shipped', 'delivered', 'cancelled'));
+
+-- Phone numbers receive SMS notifications once verified
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone VARCHAR(16);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMPTZ;
+
+-- Pending verification of a phone number, at most one per user. Codes are stored hashed.
+CREATE TABLE IF NOT EXISTS phone_verifications (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    phone VARCHAR(16) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
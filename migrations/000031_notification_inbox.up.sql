@@ -0,0 +1,14 @@
+-- Notifications shown in the inbox of the web app, delivered through the in_app channel.
+CREATE TABLE IF NOT EXISTS inbox_notifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category VARCHAR(32) NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    read_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbox_notifications_user_id ON inbox_notifications(user_id, created_at DESC);
+-- Unread counts of the bell icon
+CREATE INDEX IF NOT EXISTS idx_inbox_notifications_unread ON inbox_notifications(user_id) WHERE read_at IS NULL;
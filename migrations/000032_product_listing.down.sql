@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_products_status_created_at;
+DROP INDEX IF EXISTS idx_products_tags;
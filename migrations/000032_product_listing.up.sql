@@ -0,0 +1,3 @@
+-- Catalog listing filters products by tags and sorts them newest first.
+CREATE INDEX IF NOT EXISTS idx_products_tags ON products USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_products_status_created_at ON products(status, created_at DESC);
@@ -0,0 +1,2 @@
+-- Users list their orders newest first.
+CREATE INDEX IF NOT EXISTS idx_orders_user_id_created_at ON orders(user_id, created_at DESC);
@@ -0,0 +1,3 @@
+-- Existing users are customers; admins assign other roles.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'customer'
+    CHECK (role IN ('admin', 'manager', 'customer'));
@@ -0,0 +1,22 @@
+CREATE OR REPLACE FUNCTION reject_stock_movement_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'stock movements are append-only'
+        USING ERRCODE = 'restrict_violation';
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE product_revisions DROP CONSTRAINT IF EXISTS product_revisions_product_id_fkey;
+ALTER TABLE product_revisions ADD CONSTRAINT product_revisions_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id);
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_product_id_fkey;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id);
+ALTER TABLE product_bundle_components DROP CONSTRAINT IF EXISTS product_bundle_components_bundle_id_fkey;
+ALTER TABLE product_bundle_components ADD CONSTRAINT product_bundle_components_bundle_id_fkey
+    FOREIGN KEY (bundle_id) REFERENCES products(id);
+ALTER TABLE product_bundle_components DROP CONSTRAINT IF EXISTS product_bundle_components_component_id_fkey;
+ALTER TABLE product_bundle_components ADD CONSTRAINT product_bundle_components_component_id_fkey
+    FOREIGN KEY (component_id) REFERENCES products(id);
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_product_id_fkey;
+ALTER TABLE order_items ADD CONSTRAINT order_items_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id);
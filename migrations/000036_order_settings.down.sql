@@ -0,0 +1,6 @@
+ALTER TABLE invoices DROP COLUMN IF EXISTS tax_region;
+ALTER TABLE invoices DROP COLUMN IF EXISTS locale;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_region;
+ALTER TABLE orders DROP COLUMN IF EXISTS locale;
+ALTER TABLE orders DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,14 @@
+-- Currency, locale and tax region an order was placed with, so invoices, refunds and reports
+-- of the order do not depend on the current configuration. NULL for orders placed before they were recorded.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency CHAR(3);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS locale VARCHAR(35);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_region VARCHAR(16);
+
+-- Invoices record the currency they were issued in
+UPDATE orders o SET currency = i.currency
+FROM invoices i
+WHERE i.order_id = o.id AND o.currency IS NULL;
+
+-- Invoices copy the settings of their order when issued
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS locale VARCHAR(35);
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS tax_region VARCHAR(16);
@@ -0,0 +1,11 @@
+-- Set once the user opens the link of a verification email, reset when the email changes
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+-- Pending verification of the user's email address, at most one per user. Tokens are stored hashed.
+CREATE TABLE IF NOT EXISTS email_verifications (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,3 @@
+-- Region an order was placed in, so orders can be routed to and reported by their region.
+-- NULL for orders placed before regions were recorded or by instances without a configured region.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS region VARCHAR(32);
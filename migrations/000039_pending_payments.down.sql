@@ -0,0 +1,6 @@
+-- Fails while events of pending or failed payments exist
+ALTER TABLE order_events DROP CONSTRAINT IF EXISTS order_events_type_check;
+ALTER TABLE order_events ADD CONSTRAINT order_events_type_check
+    CHECK (type IN ('created', 'item_added', 'paid', 'shipped', 'delivered', 'cancelled'));
+
+ALTER TABLE orders DROP COLUMN IF EXISTS pending_payment;
@@ -0,0 +1,9 @@
+-- Provider capture ID of a payment awaiting confirmation by the payment provider.
+-- The order stays created until the provider reports the capture completed or failed by webhook.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS pending_payment VARCHAR(255);
+
+-- Pending and failed captures are recorded in the event log
+ALTER TABLE order_events DROP CONSTRAINT IF EXISTS order_events_type_check;
+ALTER TABLE order_events ADD CONSTRAINT order_events_type_check
+    CHECK (type IN ('created', 'item_added', 'paid', 'shipped', 'delivered', 'cancelled',
+                    'payment_pending', 'payment_failed'));
@@ -0,0 +1,22 @@
+-- Templated announcements admins send to segments of users. The batch runner sends them in batches of users
+-- ordered by ID: cursor is the last processed user, locked_until leases the announcement to one instance.
+CREATE TABLE IF NOT EXISTS announcements (
+    id UUID PRIMARY KEY,
+    template VARCHAR(64) NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    segment JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(16) NOT NULL,
+    cursor_user_id UUID,
+    recipients INT NOT NULL DEFAULT 0,
+    sent INT NOT NULL DEFAULT 0,
+    opted_out INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_announcements_pending ON announcements (created_at) WHERE status <> 'completed';
+
+-- Segments filter users by registration time
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users (created_at);
@@ -0,0 +1,6 @@
+DELETE FROM notification_templates WHERE locale <> '';
+ALTER TABLE notification_templates DROP CONSTRAINT IF EXISTS notification_templates_pkey;
+ALTER TABLE notification_templates ADD PRIMARY KEY (name, version);
+ALTER TABLE notification_templates DROP COLUMN IF EXISTS locale;
+
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
@@ -0,0 +1,8 @@
+-- Preferred locale of users, empty for the configured locale
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT '';
+
+-- Notification templates are versioned per locale, the empty locale is the default sent to users
+-- without a version in their locale or its language
+ALTER TABLE notification_templates ADD COLUMN IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT '';
+ALTER TABLE notification_templates DROP CONSTRAINT IF EXISTS notification_templates_pkey;
+ALTER TABLE notification_templates ADD PRIMARY KEY (name, locale, version);
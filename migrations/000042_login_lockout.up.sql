@@ -0,0 +1,3 @@
+-- Consecutive failed password logins, the account is locked until locked_until once they reach the configured maximum
+ALTER TABLE users ADD COLUMN IF NOT EXISTS failed_login_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
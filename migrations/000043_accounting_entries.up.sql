@@ -0,0 +1,20 @@
+-- Double-entry journal of the payment ledger, exported to accounting software. The lines of each payment
+-- are written at once and balance. Entries are kept if the order is deleted, as they were exported.
+CREATE TABLE IF NOT EXISTS accounting_entries (
+    id UUID PRIMARY KEY,
+    payment_id UUID NOT NULL,
+    order_id UUID NOT NULL,
+    order_number VARCHAR(48) NOT NULL DEFAULT '', -- Empty for orders placed before orders were numbered
+    line SMALLINT NOT NULL,
+    account VARCHAR(32) NOT NULL,
+    debit NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (debit >= 0),
+    credit NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (credit >= 0),
+    currency CHAR(3) NOT NULL,
+    description TEXT NOT NULL,
+    posted_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- Instances journaling the same payment write it once
+    UNIQUE (payment_id, line)
+);
+
+CREATE INDEX IF NOT EXISTS idx_accounting_entries_posted_at ON accounting_entries (posted_at, payment_id, line);
@@ -0,0 +1,2 @@
+-- Tokens issued before the password was last changed are no longer accepted
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMPTZ;
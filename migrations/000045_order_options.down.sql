@@ -0,0 +1,40 @@
+-- Fails while events of options exist
+ALTER TABLE order_events DROP CONSTRAINT IF EXISTS order_events_type_check;
+ALTER TABLE order_events ADD CONSTRAINT order_events_type_check
+    CHECK (type IN ('created', 'item_added', 'paid', 'shipped', 'delivered', 'cancelled',
+                    'payment_pending', 'payment_failed'));
+
+DROP TRIGGER IF EXISTS order_options_total_matches_order ON order_options;
+
+-- Totals of orders with option fees no longer match their items, the order total check reports them
+CREATE OR REPLACE FUNCTION check_order_total() RETURNS TRIGGER AS $$
+DECLARE
+    target_order_id UUID;
+    stored NUMERIC(10, 2);
+    computed NUMERIC(10, 2);
+BEGIN
+    IF TG_TABLE_NAME = 'orders' THEN
+        target_order_id := NEW.id;
+    ELSIF TG_OP = 'DELETE' THEN
+        target_order_id := OLD.order_id;
+    ELSE
+        target_order_id := NEW.order_id;
+    END IF;
+
+    SELECT total_amount INTO stored FROM orders WHERE id = target_order_id;
+    IF NOT FOUND THEN
+        RETURN NULL; -- Order was deleted together with its items
+    END IF;
+
+    SELECT COALESCE(SUM(price_at_purchase * quantity), 0) INTO computed
+    FROM order_items WHERE order_id = target_order_id;
+
+    IF stored <> computed THEN
+        RAISE EXCEPTION 'order % total % does not match items total %', target_order_id, stored, computed
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'orders_total_matches_items';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS order_options;
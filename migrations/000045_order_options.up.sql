@@ -0,0 +1,53 @@
+-- Order-level options such as gift wrap, with the fee charged for each at purchase
+CREATE TABLE IF NOT EXISTS order_options (
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    option VARCHAR(32) NOT NULL CHECK (option IN ('gift_wrap', 'gift_message', 'priority_handling')),
+    position INT NOT NULL CHECK (position > 0), -- Order the options were chosen in
+    fee NUMERIC(10, 2) NOT NULL CHECK (fee >= 0),
+    message TEXT NOT NULL DEFAULT '', -- Gift message
+    PRIMARY KEY (order_id, option)
+);
+
+-- Orders total must equal the sum of item price × quantity and option fees
+CREATE OR REPLACE FUNCTION check_order_total() RETURNS TRIGGER AS $$
+DECLARE
+    target_order_id UUID;
+    stored NUMERIC(10, 2);
+    computed NUMERIC(10, 2);
+BEGIN
+    IF TG_TABLE_NAME = 'orders' THEN
+        target_order_id := NEW.id;
+    ELSIF TG_OP = 'DELETE' THEN
+        target_order_id := OLD.order_id;
+    ELSE
+        target_order_id := NEW.order_id;
+    END IF;
+
+    SELECT total_amount INTO stored FROM orders WHERE id = target_order_id;
+    IF NOT FOUND THEN
+        RETURN NULL; -- Order was deleted together with its items and options
+    END IF;
+
+    SELECT COALESCE(SUM(price_at_purchase * quantity), 0)
+        + (SELECT COALESCE(SUM(fee), 0) FROM order_options WHERE order_id = target_order_id)
+    INTO computed
+    FROM order_items WHERE order_id = target_order_id;
+
+    IF stored <> computed THEN
+        RAISE EXCEPTION 'order % total % does not match items and options total %', target_order_id, stored, computed
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'orders_total_matches_items';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER order_options_total_matches_order
+    AFTER INSERT OR UPDATE OR DELETE ON order_options
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_order_total();
+
+-- Options are recorded in the event log
+ALTER TABLE order_events DROP CONSTRAINT IF EXISTS order_events_type_check;
+ALTER TABLE order_events ADD CONSTRAINT order_events_type_check
+    CHECK (type IN ('created', 'item_added', 'option_added', 'paid', 'shipped', 'delivered', 'cancelled',
+                    'payment_pending', 'payment_failed'));
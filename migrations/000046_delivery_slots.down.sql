@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_orders_delivery_slot_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS delivery_slot_id;
+DROP TABLE IF EXISTS delivery_slots;
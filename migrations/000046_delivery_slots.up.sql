@@ -0,0 +1,18 @@
+-- Delivery windows buyers choose at checkout, with the number of orders each can take per region
+CREATE TABLE IF NOT EXISTS delivery_slots (
+    id UUID PRIMARY KEY,
+    region VARCHAR(64) NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    capacity INT NOT NULL CHECK (capacity >= 0),
+    reserved INT NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT delivery_slots_window_check CHECK (starts_at < ends_at),
+    -- Reservations cannot exceed capacity, even under concurrent checkouts
+    CONSTRAINT delivery_slots_capacity_check CHECK (reserved <= capacity),
+    CONSTRAINT delivery_slots_region_starts_at_key UNIQUE (region, starts_at)
+);
+
+-- Orders reference the slot they reserved, the slot cannot be deleted while orders reference it
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivery_slot_id UUID REFERENCES delivery_slots(id);
+CREATE INDEX IF NOT EXISTS idx_orders_delivery_slot_id ON orders(delivery_slot_id) WHERE delivery_slot_id IS NOT NULL;
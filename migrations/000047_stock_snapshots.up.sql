@@ -0,0 +1,15 @@
+-- Closing stock of every product per day (UTC) with the day's movements, derived from the stock ledger
+CREATE TABLE IF NOT EXISTS stock_snapshots (
+    day DATE NOT NULL,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INT NOT NULL,                 -- Quantity at the end of the day
+    received INT NOT NULL DEFAULT 0,       -- Sum of receipts
+    sold INT NOT NULL DEFAULT 0,           -- Allocations to orders, net of releases by cancelled orders
+    returned INT NOT NULL DEFAULT 0,       -- Sum of customer returns
+    adjusted INT NOT NULL DEFAULT 0,       -- Sum of manual adjustments, e.g. after stock counts
+    stockout_seconds INT NOT NULL DEFAULT 0 CHECK (stockout_seconds BETWEEN 0 AND 86400), -- Time the product was out of stock during the day
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (day, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_snapshots_product_id_day ON stock_snapshots(product_id, day);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS bulk_operation_results;
+DROP TABLE IF EXISTS bulk_operations;
@@ -0,0 +1,31 @@
+-- Price and stock operations applied in the background to all products matching a filter of tags and category.
+-- The batch runner processes products in ID order: cursor is the last processed product. A batch, its results
+-- and the progress are committed together, so no product is changed twice.
+CREATE TABLE IF NOT EXISTS bulk_operations (
+    id UUID PRIMARY KEY,
+    filter JSONB NOT NULL DEFAULT '{}',
+    kind VARCHAR(32) NOT NULL,
+    value NUMERIC(12, 2) NOT NULL,
+    actor TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    cursor_product_id UUID,
+    matched INT NOT NULL DEFAULT 0,
+    processed INT NOT NULL DEFAULT 0,
+    updated INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_bulk_operations_pending ON bulk_operations (created_at) WHERE status <> 'completed';
+
+-- Outcome per product. Products may be deleted later, their results are kept.
+CREATE TABLE IF NOT EXISTS bulk_operation_results (
+    operation_id UUID NOT NULL REFERENCES bulk_operations(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    before_value NUMERIC(12, 2) NOT NULL,
+    after_value NUMERIC(12, 2) NOT NULL,
+    PRIMARY KEY (operation_id, product_id)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS sandbox;
+ALTER TABLE users DROP COLUMN IF EXISTS sandbox;
@@ -0,0 +1,3 @@
+-- Sandbox accounts of integration partners: their orders allocate no stock and are paid with the mock provider
+ALTER TABLE users ADD COLUMN IF NOT EXISTS sandbox BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS sandbox BOOLEAN NOT NULL DEFAULT FALSE;
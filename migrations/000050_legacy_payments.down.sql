@@ -0,0 +1 @@
+-- Legacy payments stay legacy, as 000026 now records them
@@ -0,0 +1,7 @@
+-- 000026 used to record card payments of the logging gateway as mock payments, which cannot be refunded once the
+-- mock provider restarts. Mock payments record their authorization, so those without metadata, and their refunds, are legacy.
+ALTER TABLE order_payments DISABLE TRIGGER order_payments_append_only;
+UPDATE order_payments p SET provider = 'legacy'
+WHERE p.provider = 'mock' AND p.method = 'card'
+  AND COALESCE((SELECT c.metadata FROM order_payments c WHERE c.id = COALESCE(p.refund_of, p.id)), '{}') = '{}';
+ALTER TABLE order_payments ENABLE TRIGGER order_payments_append_only;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_bulk_operations_pending;
+CREATE INDEX IF NOT EXISTS idx_bulk_operations_pending ON bulk_operations (created_at) WHERE status <> 'completed';
+
+ALTER TABLE bulk_operations DROP COLUMN IF EXISTS error;
+ALTER TABLE bulk_operations DROP COLUMN IF EXISTS attempts;
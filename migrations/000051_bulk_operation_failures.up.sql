@@ -0,0 +1,7 @@
+-- Batches failing repeatedly, e.g. on a database error, no longer block the operations queued behind them:
+-- attempts counts the consecutive failed batches and the operation fails after too many
+ALTER TABLE bulk_operations ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE bulk_operations ADD COLUMN IF NOT EXISTS error TEXT NOT NULL DEFAULT '';
+
+DROP INDEX IF EXISTS idx_bulk_operations_pending;
+CREATE INDEX IF NOT EXISTS idx_bulk_operations_pending ON bulk_operations (created_at) WHERE status NOT IN ('completed', 'failed');
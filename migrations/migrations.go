@@ -0,0 +1,109 @@
+// Package migrations embeds the database migrations, so the binary knows the schema version it was built for.
+// Migrations are applied with golang-migrate, see the migrate-up target of the Makefile.
+package migrations
+
+import (
+	"cmp"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+var (
+	// ErrSchemaDirty is returned when a migration failed half-way and the schema must be fixed manually.
+	ErrSchemaDirty = errors.New("database schema is dirty")
+	// ErrSchemaOutdated is returned when migrations of the binary were not applied to the database.
+	ErrSchemaOutdated = errors.New("database schema is older than the binary")
+	// ErrSchemaNewer is returned when the database has migrations the binary does not know,
+	// e.g. while an older binary is still running or after it was rolled back.
+	ErrSchemaNewer = errors.New("database schema is newer than the binary")
+)
+
+// FS contains the up and down migrations.
+//
+//go:embed *.sql
+var FS embed.FS
+
+// upMigrationPattern matches up migrations, e.g. 000030_sms_notifications.up.sql.
+var upMigrationPattern = regexp.MustCompile(`^(\d+)_\w+\.up\.sql$`)
+
+// Migration is an up migration.
+type Migration struct {
+	Version uint
+	Name    string // File name, e.g. 000030_sms_notifications.up.sql
+}
+
+// SQL returns the statements of the migration.
+func (m Migration) SQL() (string, error) {
+	data, err := fs.ReadFile(FS, m.Name)
+	return string(data), err
+}
+
+// upMigrations returns the up migrations ordered by version.
+func upMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(FS, ".")
+	if err != nil {
+		return nil, err
+	}
+	var ups []Migration
+	for _, entry := range entries {
+		match := upMigrationPattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %s: %w", entry.Name(), err)
+		}
+		ups = append(ups, Migration{Version: uint(version), Name: entry.Name()})
+	}
+	if len(ups) == 0 {
+		return nil, fmt.Errorf("no migrations embedded")
+	}
+	slices.SortFunc(ups, func(a, b Migration) int { return cmp.Compare(a.Version, b.Version) })
+	return ups, nil
+}
+
+// Latest returns the version of the latest migration, the schema version the binary expects.
+func Latest() (uint, error) {
+	ups, err := upMigrations()
+	if err != nil {
+		return 0, err
+	}
+	return ups[len(ups)-1].Version, nil
+}
+
+// Pending returns the up migrations after version, in the order they are applied.
+func Pending(version uint) ([]Migration, error) {
+	ups, err := upMigrations()
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(ups, func(m Migration) bool { return m.Version > version })
+	if i < 0 {
+		return nil, nil
+	}
+	return ups[i:], nil
+}
+
+// Check compares the schema version and dirty state of the database with the latest migration.
+// Returns ErrSchemaDirty, ErrSchemaOutdated or ErrSchemaNewer on a mismatch.
+func Check(version uint, dirty bool) error {
+	latest, err := Latest()
+	if err != nil {
+		return err
+	}
+	switch {
+	case dirty:
+		return fmt.Errorf("%w at version %d, fix the failed migration and force the version", ErrSchemaDirty, version)
+	case version < latest:
+		return fmt.Errorf("%w: version %d, expected %d, apply migrations first", ErrSchemaOutdated, version, latest)
+	case version > latest:
+		return fmt.Errorf("%w: version %d, expected %d", ErrSchemaNewer, version, latest)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package migrations_test
+
+import (
+	"io/fs"
+	"product-api/migrations"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatest(t *testing.T) {
+	ups, err := fs.Glob(migrations.FS, "*.up.sql")
+	require.NoError(t, err)
+	downs, err := fs.Glob(migrations.FS, "*.down.sql")
+	require.NoError(t, err)
+	assert.Len(t, downs, len(ups), "every migration can be rolled back")
+
+	latest, err := migrations.Latest()
+	require.NoError(t, err)
+	assert.Equal(t, uint(len(ups)), latest, "versions are consecutive")
+}
+
+func TestCheck(t *testing.T) {
+	latest, err := migrations.Latest()
+	require.NoError(t, err)
+
+	assert.NoError(t, migrations.Check(latest, false))
+	assert.ErrorIs(t, migrations.Check(latest, true), migrations.ErrSchemaDirty)
+	assert.ErrorIs(t, migrations.Check(latest-1, false), migrations.ErrSchemaOutdated)
+	assert.ErrorIs(t, migrations.Check(0, false), migrations.ErrSchemaOutdated, "not migrated")
+	assert.ErrorIs(t, migrations.Check(latest+1, false), migrations.ErrSchemaNewer)
+}
+
+func TestPending(t *testing.T) {
+	latest, err := migrations.Latest()
+	require.NoError(t, err)
+
+	pending, err := migrations.Pending(latest - 2)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, latest-1, pending[0].Version)
+	assert.Equal(t, latest, pending[1].Version)
+	sql, err := pending[1].SQL()
+	require.NoError(t, err)
+	assert.NotEmpty(t, sql)
+
+	pending, err = migrations.Pending(latest)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
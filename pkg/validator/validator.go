@@ -19,21 +19,16 @@ func DecodeAndValidate(r *http.Request, v interface{}) error {
 	return validate.Struct(v)
 }
 
-// HandleValidationError handles validation errors and sends JSON response to client.
-// If error is ValidationErrors, returns detailed field information.
-// Otherwise returns a generic error message.
-func HandleValidationError(w http.ResponseWriter, err error) {
+// FieldErrors returns the failed validation rule of each field if err is a validation error
+// of DecodeAndValidate, or false if the body could not be decoded.
+func FieldErrors(err error) (map[string]string, bool) {
 	var validationErrors validator.ValidationErrors
-	if errors.As(err, &validationErrors) {
-		errors := make(map[string]string)
-		for _, err := range validationErrors {
-			errors[err.Field()] = "failed on the '" + err.Tag() + "' tag"
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{"errors": errors})
-		return
+	if !errors.As(err, &validationErrors) {
+		return nil, false
 	}
-
-	http.Error(w, "invalid request body", http.StatusBadRequest)
+	fields := make(map[string]string, len(validationErrors))
+	for _, err := range validationErrors {
+		fields[err.Field()] = "failed on the '" + err.Tag() + "' tag"
+	}
+	return fields, true
 }
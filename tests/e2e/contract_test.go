@@ -0,0 +1,246 @@
+//go:build e2e
+
+package e2e_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// apiSpec is the subset of the generated Swagger 2.0 document used by contract tests.
+type apiSpec struct {
+	Paths       map[string]map[string]specOperation `json:"paths"`
+	Definitions map[string]*specSchema              `json:"definitions"`
+}
+
+type specOperation struct {
+	Parameters []specParameter         `json:"parameters"`
+	Responses  map[string]specResponse `json:"responses"`
+}
+
+type specParameter struct {
+	Name   string      `json:"name"`
+	In     string      `json:"in"`
+	Schema *specSchema `json:"schema"`
+}
+
+type specResponse struct {
+	Schema *specSchema `json:"schema"`
+}
+
+type specSchema struct {
+	Ref        string                 `json:"$ref"`
+	Type       string                 `json:"type"`
+	Properties map[string]*specSchema `json:"properties"`
+	Required   []string               `json:"required"`
+	Items      *specSchema            `json:"items"`
+	Example    any                    `json:"example"`
+}
+
+// loadSpec reads the generated Swagger document.
+func loadSpec(path string) (*apiSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var spec apiSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// resolve follows a "#/definitions/..." reference.
+func (sp *apiSpec) resolve(schema *specSchema) *specSchema {
+	if schema == nil || schema.Ref == "" {
+		return schema
+	}
+	return sp.Definitions[strings.TrimPrefix(schema.Ref, "#/definitions/")]
+}
+
+// operation returns the documented operation or nil.
+func (sp *apiSpec) operation(method, path string) *specOperation {
+	op, ok := sp.Paths[path][strings.ToLower(method)]
+	if !ok {
+		return nil
+	}
+	return &op
+}
+
+// example builds a value from the documented property examples of the schema.
+func (sp *apiSpec) example(schema *specSchema) any {
+	schema = sp.resolve(schema)
+	if schema == nil {
+		return nil
+	}
+	if schema.Example != nil {
+		return schema.Example
+	}
+
+	switch schema.Type {
+	case "object":
+		obj := make(map[string]any)
+		for name, prop := range schema.Properties {
+			if value := sp.example(prop); value != nil {
+				obj[name] = value
+			}
+		}
+		return obj
+	case "array":
+		if item := sp.example(schema.Items); item != nil {
+			return []any{item}
+		}
+	}
+	return nil
+}
+
+// requestExample returns the documented example body of the operation.
+func (s *E2ETestSuite) requestExample(method, path string) map[string]any {
+	op := s.spec.operation(method, path)
+	s.Require().NotNil(op, "%s %s is not documented", method, path)
+
+	for _, param := range op.Parameters {
+		if param.In == "body" {
+			example, ok := s.spec.example(param.Schema).(map[string]any)
+			s.Require().True(ok, "%s %s has no body example", method, path)
+			return example
+		}
+	}
+	s.FailNow(fmt.Sprintf("%s %s has no body parameter", method, path))
+	return nil
+}
+
+// call sends the request to the documented path template filled with path values
+// and checks the response against the spec. Returns the status and decoded body.
+func (s *E2ETestSuite) call(method, pathTemplate, token string, body any, pathValues ...string) (int, any) {
+	path := pathTemplate
+	for i := 0; i+1 < len(pathValues); i += 2 {
+		path = strings.Replace(path, "{"+pathValues[i]+"}", pathValues[i+1], 1)
+	}
+
+	var buf bytes.Buffer
+	if body != nil {
+		s.Require().NoError(json.NewEncoder(&buf).Encode(body))
+	}
+	req, err := http.NewRequest(method, s.baseURL+path, &buf)
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.client.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	var decoded any
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&decoded))
+	}
+
+	specPath, _, _ := strings.Cut(pathTemplate, "?")
+	s.checkContract(method, specPath, resp.StatusCode, decoded)
+	return resp.StatusCode, decoded
+}
+
+// checkContract fails if the status is not documented for the operation
+// or the body lacks documented fields of the response schema.
+func (s *E2ETestSuite) checkContract(method, path string, status int, body any) {
+	op := s.spec.operation(method, path)
+	s.Require().NotNil(op, "%s %s is not documented", method, path)
+
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !s.True(ok, "%s %s returned undocumented status %d", method, path, status) {
+		return
+	}
+	s.checkSchema(fmt.Sprintf("%s %s (%d)", method, path, status), s.spec.resolve(resp.Schema), body)
+}
+
+// checkSchema checks that objects contain all documented properties, recursively.
+func (s *E2ETestSuite) checkSchema(where string, schema *specSchema, value any) {
+	if schema == nil {
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !s.True(ok, "%s: expected object, got %T", where, value) {
+			return
+		}
+		for name, prop := range schema.Properties {
+			field, ok := obj[name]
+			if !s.True(ok, "%s: documented field %q is missing", where, name) {
+				continue
+			}
+			if field != nil {
+				s.checkSchema(where+"."+name, s.spec.resolve(prop), field)
+			}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !s.True(ok, "%s: expected array, got %T", where, value) {
+			return
+		}
+		for i, item := range items {
+			s.checkSchema(fmt.Sprintf("%s[%d]", where, i), s.spec.resolve(schema.Items), item)
+		}
+	}
+}
+
+func (s *E2ETestSuite) TestContract_CoreFlow() {
+	// Register with the documented example (unique login, no legal documents are published)
+	register := s.requestExample(http.MethodPost, "/users/register")
+	register["email"] = "contract@example.com"
+	register["username"] = "contract.user"
+	delete(register, "accepted_terms_version")
+	delete(register, "accepted_privacy_version")
+	status, _ := s.call(http.MethodPost, "/users/register", "", register)
+	s.Require().Equal(http.StatusCreated, status)
+
+	// Login with the documented example
+	login := s.requestExample(http.MethodPost, "/users/login")
+	login["email"] = register["email"]
+	login["password"] = register["password"]
+	delete(login, "username")
+	status, body := s.call(http.MethodPost, "/users/login", "", login)
+	s.Require().Equal(http.StatusOK, status)
+	token, _ := body.(map[string]any)["token"].(string)
+	s.Require().NotEmpty(token)
+
+	// Wrong password is a documented failure
+	login["password"] = "not-the-password"
+	status, _ = s.call(http.MethodPost, "/users/login", "", login)
+	s.Equal(http.StatusUnauthorized, status)
+
+	// Customers cannot create products, managers create and fetch one with the documented example
+	product := s.requestExample(http.MethodPost, "/products")
+	delete(product, "age_restriction")
+	status, _ = s.call(http.MethodPost, "/products", token, product)
+	s.Equal(http.StatusForbidden, status)
+	status, body = s.call(http.MethodPost, "/products", s.managerToken, product)
+	s.Require().Equal(http.StatusCreated, status)
+	productID, _ := body.(map[string]any)["ID"].(string)
+	s.Require().NotEmpty(productID)
+
+	status, _ = s.call(http.MethodGet, "/products/{id}", token, nil, "id", productID)
+	s.Equal(http.StatusOK, status)
+	status, _ = s.call(http.MethodGet, "/products/{id}", token, nil, "id", "not-a-uuid")
+	s.Equal(http.StatusBadRequest, status)
+
+	// Order the product
+	order := map[string]any{"items": []map[string]any{{"product_id": productID, "quantity": 1}}}
+	status, _ = s.call(http.MethodPost, "/orders", token, order)
+	s.Equal(http.StatusCreated, status)
+	status, _ = s.call(http.MethodPost, "/orders", "", order)
+	s.Equal(http.StatusUnauthorized, status)
+
+	// Username availability
+	status, _ = s.call(http.MethodGet, "/users/check-username?username=contract.user", "", nil)
+	s.Equal(http.StatusOK, status)
+}
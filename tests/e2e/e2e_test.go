@@ -0,0 +1,375 @@
+//go:build e2e
+
+// Package e2e_test contains end-to-end tests that build and start the API binary
+// against a fresh database and exercise the real HTTP API.
+//
+// Run with: make test-e2e (requires the 'db' service, see docker-compose.yml).
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/suite"
+)
+
+type E2ETestSuite struct {
+	suite.Suite
+	baseURL      string
+	server       *exec.Cmd
+	client       *http.Client
+	spec         *apiSpec
+	managerToken string // Access token of a manager, who can create products
+}
+
+// adminAPIKey is the admin API key of the API under test.
+const adminAPIKey = "e2e-admin-key"
+
+func TestE2ETestSuite(t *testing.T) {
+	suite.Run(t, new(E2ETestSuite))
+}
+
+func (s *E2ETestSuite) SetupSuite() {
+	dbUser := os.Getenv("DB_USER")
+	dbPassword := os.Getenv("DB_PASSWORD")
+	dbName := os.Getenv("DB_NAME") + "_test_e2e"
+	maintenanceDbUrl := "postgres://" + dbUser + ":" + dbPassword + "@localhost:5434/postgres?sslmode=disable"
+	testDbUrl := "postgres://" + dbUser + ":" + dbPassword + "@localhost:5434/" + dbName + "?sslmode=disable"
+
+	var err error
+	var maintenanceDb *pgxpool.Pool
+
+	for i := 0; i < 10; i++ {
+		maintenanceDb, err = pgxpool.New(context.Background(), maintenanceDbUrl)
+		if err == nil {
+			break
+		}
+		log.Printf("Failed to connect to maintenance db, retrying in 2 seconds...: %v", err)
+		time.Sleep(2 * time.Second)
+	}
+	s.Require().NoError(err, "Failed to connect to maintenance database after retries")
+
+	_, err = maintenanceDb.Exec(context.Background(), "DROP DATABASE IF EXISTS "+dbName)
+	s.Require().NoError(err)
+	_, err = maintenanceDb.Exec(context.Background(), "CREATE DATABASE "+dbName)
+	s.Require().NoError(err)
+	maintenanceDb.Close()
+
+	m, err := migrate.New("file://../../migrations", testDbUrl)
+	s.Require().NoError(err)
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		s.Require().NoError(err)
+	}
+
+	// Build the binary exactly as it is deployed
+	binDir := s.T().TempDir()
+	binary := filepath.Join(binDir, "product-api")
+	build := exec.Command("go", "build", "-o", binary, "../../cmd/api")
+	build.Stdout, build.Stderr = os.Stdout, os.Stderr
+	s.Require().NoError(build.Run(), "Failed to build API binary")
+
+	addr := freeAddress(s.T())
+	s.baseURL = "http://" + addr
+	s.client = &http.Client{Timeout: 10 * time.Second}
+
+	s.server = exec.Command(binary)
+	s.server.Dir = binDir // No .env file is picked up
+	s.server.Env = append(os.Environ(),
+		"ENV=local",
+		"DATABASE_URL="+testDbUrl,
+		"HTTP_SERVER_ADDRESS="+addr,
+		"GRPC_SERVER_ADDRESS="+freeAddress(s.T()),
+		"PUBLIC_URL="+s.baseURL,
+		"JWT_SECRET=e2e-secret",
+		"API_KEYS=admin:"+adminAPIKey,
+		"SWAGGER_MODE=public",
+		"RATE_LIMIT_REQUESTS=0", // All test users register and log in from localhost
+		"AUTH_RATE_LIMIT_REQUESTS=0",
+	)
+	s.server.Stdout, s.server.Stderr = os.Stdout, os.Stderr
+	s.Require().NoError(s.server.Start(), "Failed to start API binary")
+
+	s.waitReady(30 * time.Second)
+	s.managerToken = s.registerManager("manager@example.com")
+
+	s.spec, err = loadSpec("../../docs/swagger.json")
+	s.Require().NoError(err)
+}
+
+func (s *E2ETestSuite) TearDownSuite() {
+	if s.server == nil || s.server.Process == nil {
+		return
+	}
+	_ = s.server.Process.Signal(syscall.SIGTERM)
+	_ = s.server.Wait()
+}
+
+// waitReady polls the API until it responds or the timeout expires.
+func (s *E2ETestSuite) waitReady(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		resp, err := s.client.Get(s.baseURL + "/users/check-username?username=readiness")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return
+			}
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	s.FailNow("API did not become ready")
+}
+
+// freeAddress returns a local address with a free port.
+func freeAddress(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// do sends a JSON request and decodes the JSON response into out (if not nil).
+func (s *E2ETestSuite) do(method, path, token string, body, out any) int {
+	var buf bytes.Buffer
+	if body != nil {
+		s.Require().NoError(json.NewEncoder(&buf).Encode(body))
+	}
+	req, err := http.NewRequest(method, s.baseURL+path, &buf)
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.client.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	if out != nil && resp.StatusCode < 300 {
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+// registerAndLogin registers a new user and returns the access token.
+func (s *E2ETestSuite) registerAndLogin(email string) string {
+	s.register(email)
+	return s.login(email)
+}
+
+// register registers a new customer and returns their ID.
+func (s *E2ETestSuite) register(email string) string {
+	var user struct {
+		ID string
+	}
+	status := s.do(http.MethodPost, "/users/register", "", map[string]any{
+		"email":     email,
+		"password":  "password123",
+		"firstname": "John",
+		"lastname":  "Doe",
+		"birthdate": "1990-01-01",
+	}, &user)
+	s.Require().Equal(http.StatusCreated, status)
+	return user.ID
+}
+
+// login logs in a user registered with register and returns the access token.
+func (s *E2ETestSuite) login(email string) string {
+	var login struct {
+		Token string `json:"token"`
+	}
+	status := s.do(http.MethodPost, "/users/login", "", map[string]any{
+		"email":    email,
+		"password": "password123",
+	}, &login)
+	s.Require().Equal(http.StatusOK, status)
+	s.Require().NotEmpty(login.Token)
+	return login.Token
+}
+
+// registerManager registers a new user, assigns the manager role with the admin API and returns the access token.
+func (s *E2ETestSuite) registerManager(email string) string {
+	id := s.register(email)
+
+	req, err := http.NewRequest(http.MethodPut, s.baseURL+"/admin/users/"+id+"/role", strings.NewReader(`{"role":"manager"}`))
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", adminAPIKey)
+	resp, err := s.client.Do(req)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	return s.login(email)
+}
+
+// createProduct creates a product as the manager and returns its ID.
+func (s *E2ETestSuite) createProduct(quantity int, price float64) string {
+	var product struct {
+		ID string
+	}
+	status := s.do(http.MethodPost, "/products", s.managerToken, map[string]any{
+		"description": "E2E product",
+		"tags":        []string{"e2e"},
+		"quantity":    quantity,
+		"price":       price,
+	}, &product)
+	s.Require().Equal(http.StatusCreated, status)
+	return product.ID
+}
+
+func (s *E2ETestSuite) TestRegister_DuplicateEmail() {
+	s.registerAndLogin("duplicate@example.com")
+
+	status := s.do(http.MethodPost, "/users/register", "", map[string]any{
+		"email":     "duplicate@example.com",
+		"password":  "password123",
+		"firstname": "Jane",
+		"lastname":  "Doe",
+		"birthdate": "1990-01-01",
+	}, nil)
+	s.Equal(http.StatusConflict, status)
+}
+
+func (s *E2ETestSuite) TestLogin_WrongPassword() {
+	s.registerAndLogin("wrong-password@example.com")
+
+	status := s.do(http.MethodPost, "/users/login", "", map[string]any{
+		"email":    "wrong-password@example.com",
+		"password": "not-the-password",
+	}, nil)
+	s.Equal(http.StatusUnauthorized, status)
+}
+
+func (s *E2ETestSuite) TestProfile() {
+	token := s.registerAndLogin("profile@example.com")
+
+	var user map[string]any
+	status := s.do(http.MethodPatch, "/users/me", token, map[string]any{"lastname": "Smith", "is_married": true}, &user)
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal("John", user["Firstname"])
+	s.Equal("Smith", user["Lastname"])
+	s.Equal(true, user["IsMarried"])
+	s.NotContains(user, "PasswordHash")
+
+	status = s.do(http.MethodPatch, "/users/me", token, map[string]any{"firstname": ""}, nil)
+	s.Equal(http.StatusBadRequest, status)
+
+	status = s.do(http.MethodGet, "/users/me", token, nil, &user)
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal("Smith", user["Lastname"])
+
+	// Tokens of deleted accounts are rejected
+	s.Require().Equal(http.StatusNoContent, s.do(http.MethodDelete, "/users/me", token, nil, nil))
+	s.Equal(http.StatusUnauthorized, s.do(http.MethodGet, "/users/me", token, nil, nil))
+}
+
+func (s *E2ETestSuite) TestChangePassword() {
+	token := s.registerAndLogin("change-password@example.com")
+
+	status := s.do(http.MethodPost, "/users/me/password", token, map[string]any{
+		"current_password": "not-the-password",
+		"new_password":     "new-password123",
+	}, nil)
+	s.Equal(http.StatusForbidden, status)
+
+	var changed struct {
+		Token string `json:"token"`
+	}
+	status = s.do(http.MethodPost, "/users/me/password", token, map[string]any{
+		"current_password": "password123",
+		"new_password":     "new-password123",
+	}, &changed)
+	s.Require().Equal(http.StatusOK, status)
+
+	// Tokens issued before the change are rejected, the returned one is accepted
+	s.Equal(http.StatusUnauthorized, s.do(http.MethodGet, "/users/me", token, nil, nil))
+	s.Equal(http.StatusOK, s.do(http.MethodGet, "/users/me", changed.Token, nil, nil))
+}
+
+func (s *E2ETestSuite) TestProtectedRoutes_RequireToken() {
+	status := s.do(http.MethodPost, "/orders", "", map[string]any{"items": []any{}}, nil)
+	s.Equal(http.StatusUnauthorized, status)
+}
+
+func (s *E2ETestSuite) TestCreateProduct_RequiresManager() {
+	token := s.registerAndLogin("customer@example.com")
+	status := s.do(http.MethodPost, "/products", token, map[string]any{
+		"description": "E2E product",
+		"quantity":    1,
+		"price":       1,
+	}, nil)
+	s.Equal(http.StatusForbidden, status)
+}
+
+func (s *E2ETestSuite) TestOrderFlow() {
+	token := s.registerAndLogin("buyer@example.com")
+	productID := s.createProduct(5, 10.5)
+
+	var product struct {
+		ID       string
+		Quantity int
+	}
+	status := s.do(http.MethodGet, "/products/"+productID, token, nil, &product)
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal(5, product.Quantity)
+
+	var order struct {
+		ID          string
+		TotalAmount float64
+	}
+	status = s.do(http.MethodPost, "/orders", token, map[string]any{
+		"items": []map[string]any{{"product_id": productID, "quantity": 2}},
+	}, &order)
+	s.Require().Equal(http.StatusCreated, status)
+	s.NotEmpty(order.ID)
+	s.InDelta(21.0, order.TotalAmount, 0.001)
+
+	status = s.do(http.MethodGet, "/products/"+productID, token, nil, &product)
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal(3, product.Quantity)
+}
+
+func (s *E2ETestSuite) TestOrder_InsufficientStock() {
+	token := s.registerAndLogin("insufficient@example.com")
+	productID := s.createProduct(1, 10)
+
+	status := s.do(http.MethodPost, "/orders", token, map[string]any{
+		"items": []map[string]any{{"product_id": productID, "quantity": 2}},
+	}, nil)
+	s.Equal(http.StatusConflict, status)
+
+	// Stock is unchanged after the failed order
+	var product struct {
+		Quantity int
+	}
+	status = s.do(http.MethodGet, "/products/"+productID, token, nil, &product)
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal(1, product.Quantity)
+}
+
+func (s *E2ETestSuite) TestOrder_UnknownProduct() {
+	token := s.registerAndLogin("unknown-product@example.com")
+
+	status := s.do(http.MethodPost, "/orders", token, map[string]any{
+		"items": []map[string]any{{"product_id": "00000000-0000-0000-0000-000000000001", "quantity": 1}},
+	}, nil)
+	s.Equal(http.StatusBadRequest, status)
+}